@@ -0,0 +1,60 @@
+// devnet runs a network of nodes within a single process, producing blocks
+// at an accelerated rate, until interrupted. See pkg/devnet.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/devnet"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	var conf devnet.Config
+	flag.StringVar(&conf.Wallets, "wallets", "harness/data", "directory of the wallet files of the nodes")
+	flag.StringVar(&conf.Password, "password", os.Getenv("DUSK_WALLET_PASS"), "password of the wallet files, defaults to $DUSK_WALLET_PASS")
+	flag.IntVar(&conf.Nodes, "nodes", 0, "nodes to run, 0 runs one per wallet file")
+	flag.DurationVar(&conf.StepTimeout, "steptimeout", time.Second, "timeout of the consensus steps")
+	flag.IntVar(&conf.CommitteeSize, "committee", 0, "size of the committees, 0 means the number of nodes")
+	flag.StringVar(&conf.BlindBid, "blindbid", os.Getenv("DUSK_BLINDBID"), "path of the blindbid executable, defaults to $DUSK_BLINDBID")
+	flag.StringVar(&conf.Dir, "dir", "", "directory of the wallet databases, a temporary one by default")
+	level := flag.String("loglevel", "info", "log level")
+	flag.Parse()
+
+	lvl, err := log.ParseLevel(*level)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	log.SetLevel(lvl)
+
+	n, err := devnet.Start(conf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "could not start the devnet:", err)
+		os.Exit(1)
+	}
+
+	// The blocks are reported as the first node accepts them
+	eb := n.Nodes()[0].EventBus
+	blocks, id := consensus.InitAcceptedBlockUpdate(eb)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	for {
+		select {
+		case blk := <-blocks:
+			fmt.Printf("block %d %x, %d txs\n", blk.Header.Height, blk.Header.Hash, len(blk.Txs))
+		case <-interrupt:
+			eb.Unsubscribe(topics.AcceptedBlock, id)
+			n.Stop()
+			return
+		}
+	}
+}
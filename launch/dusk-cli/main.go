@@ -0,0 +1,336 @@
+// dusk-cli controls a running node through its RPC server. Commands are
+// given on the command line, or typed in an interactive console when none is.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc"
+)
+
+const (
+	// callTimeout bounds the time waited for the node to answer a call
+	callTimeout = 30 * time.Second
+	// followInterval is the time between two polls of the logs, when
+	// following them
+	followInterval = time.Second
+)
+
+// client calls the methods of the node, over JSON-RPC
+type client struct {
+	url   string
+	user  string
+	pass  string
+	token string
+	http  *http.Client
+	id    int
+}
+
+func newClient(address, socket, user, pass, token string) *client {
+	c := &client{
+		url:   strings.TrimSuffix(address, "/"),
+		user:  user,
+		pass:  pass,
+		token: token,
+		http:  &http.Client{Timeout: callTimeout},
+	}
+
+	if socket != "" {
+		// The host of the URL is ignored, as all the calls go to the socket
+		c.url = "http://unix"
+		c.http.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+	}
+	return c
+}
+
+// call method on the public namespace
+func (c *client) call(method string, params ...string) (string, error) {
+	return c.do("/", method, params)
+}
+
+// admin calls method on the admin namespace
+func (c *client) admin(method string, params ...string) (string, error) {
+	return c.do("/admin", method, params)
+}
+
+func (c *client) do(path, method string, params []string) (string, error) {
+	c.id++
+	payload, err := json.Marshal(rpc.JSONRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      json.RawMessage(strconv.Itoa(c.id)),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.url+path, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.user != "":
+		req.SetBasicAuth(c.user, c.pass)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var r rpc.JSONResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return "", fmt.Errorf("invalid response (%s): %v", resp.Status, err)
+	}
+
+	if r.Error != nil {
+		return "", fmt.Errorf("%s (code %d)", r.Error.Message, r.Error.Code)
+	}
+	return r.Result, nil
+}
+
+// command runs a console command, printing its result
+type command struct {
+	usage string
+	help  string
+	run   func(c *client, args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"status":   {"", "show the status of the node", method("getnodestatus", false, 0)},
+		"peers":    {"", "list the peers connected", method("getpeerinfo", false, 0)},
+		"ban":      {"<address>", "disconnect and ban the peers of a host", method("banpeer", true, 1)},
+		"connect":  {"<address>", "connect to a peer", method("addpeer", true, 1)},
+		"block":    {"<hash|height>", "show a block", getBlock},
+		"tx":       {"<txid>", "show a transaction", method("gettransaction", false, 1)},
+		"send":     {"<amount> <address>", "send DUSK from the wallet of the node", method("transfer", false, 2)},
+		"sendraw":  {"<tx> [encoding]", "broadcast a signed transaction", method("sendrawtransaction", false, 1)},
+		"createtx": {"<amount> <address> [encoding]", "sign a transaction from the wallet of the node, without broadcasting it", method("createtransaction", false, 2)},
+		"balance":  {"", "show the balance of the wallet of the node", method("balance", false, 0)},
+		"history":  {"", "show the transactions of the wallet of the node", method("txhistory", false, 0)},
+		"pause":    {"", "stop taking part in the consensus until resumed", method("pauseconsensus", true, 0)},
+		"resume":   {"", "take part in the consensus again", method("resumeconsensus", true, 0)},
+		"loglevel": {"<level> [subsystem]", "change the log level", method("setloglevel", true, 1)},
+		"logs":     {"[-f] [lines]", "show the last lines logged, or follow them with -f", logs},
+		"stop":     {"", "shut the node down", method("stopnode", true, 0)},
+		"call":     {"<method> [params...]", "call a method of the public namespace", raw(false)},
+		"admin":    {"<method> [params...]", "call a method of the admin namespace", raw(true)},
+	}
+}
+
+// method returns the run function of a command calling name, with at least
+// min arguments
+func method(name string, admin bool, min int) func(*client, []string) error {
+	return func(c *client, args []string) error {
+		if len(args) < min {
+			return errUsage
+		}
+
+		call := c.call
+		if admin {
+			call = c.admin
+		}
+
+		result, err := call(name, args...)
+		if err != nil {
+			return err
+		}
+		printResult(result)
+		return nil
+	}
+}
+
+func raw(admin bool) func(*client, []string) error {
+	return func(c *client, args []string) error {
+		if len(args) < 1 {
+			return errUsage
+		}
+		return method(args[0], admin, 0)(c, args[1:])
+	}
+}
+
+// getBlock shows a block by hash, or by height
+func getBlock(c *client, args []string) error {
+	if len(args) < 1 {
+		return errUsage
+	}
+
+	hash := args[0]
+	if _, err := strconv.ParseUint(hash, 10, 64); err == nil {
+		var err error
+		if hash, err = c.call("getblockhash", hash); err != nil {
+			return err
+		}
+	}
+	return method("getblock", false, 1)(c, []string{hash})
+}
+
+// logs prints the last lines logged by the node. With -f, the new lines are
+// printed as they are logged, until the process is interrupted.
+func logs(c *client, args []string) error {
+	var follow bool
+	limit := "20"
+	for _, arg := range args {
+		if arg == "-f" {
+			follow = true
+			continue
+		}
+		limit = arg
+	}
+
+	var p struct {
+		Items []string `json:"items"`
+		Next  string   `json:"next"`
+	}
+
+	cursor := ""
+	for {
+		result, err := c.admin("getlogs", limit, cursor)
+		if err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal([]byte(result), &p); err != nil {
+			return err
+		}
+
+		for _, line := range p.Items {
+			fmt.Println(line)
+		}
+
+		if !follow {
+			return nil
+		}
+
+		// Once following, the lines are fetched in full pages
+		cursor, limit = p.Next, "100"
+		if len(p.Items) < 100 {
+			time.Sleep(followInterval)
+		}
+	}
+}
+
+var errUsage = errors.New("invalid arguments")
+
+// printResult prints JSON results indented, and others as they are
+func printResult(result string) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(result), "", "  "); err != nil {
+		fmt.Println(result)
+		return
+	}
+	fmt.Println(out.String())
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("Commands:")
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Printf("  %-32s %s\n", strings.TrimSpace(name+" "+cmd.usage), cmd.help)
+	}
+}
+
+// run a command line, returning an error for unknown commands
+func run(c *client, args []string) error {
+	if args[0] == "help" {
+		usage()
+		return nil
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %s, try help", args[0])
+	}
+
+	err := cmd.run(c, args[1:])
+	if err == errUsage {
+		return fmt.Errorf("usage: %s %s", args[0], cmd.usage)
+	}
+	return err
+}
+
+// console reads commands from stdin until exit, or the end of the input
+func console(c *client) {
+	fmt.Println("Connected to", c.url+", type help for the commands, exit to quit")
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("dusk> ")
+		if !scanner.Scan() {
+			fmt.Println()
+			return
+		}
+
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 {
+			continue
+		}
+
+		if args[0] == "exit" || args[0] == "quit" {
+			return
+		}
+
+		if err := run(c, args); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+}
+
+func main() {
+	address := flag.String("rpc", "http://127.0.0.1:9000", "address of the RPC server of the node")
+	socket := flag.String("unix", "", "path of the unix socket of the RPC server, instead of -rpc")
+	user := flag.String("user", "", "user of the admin namespace (rpc.user)")
+	pass := flag.String("pass", os.Getenv("DUSK_RPC_PASS"), "password of the admin namespace (rpc.pass), defaults to $DUSK_RPC_PASS")
+	token := flag.String("token", os.Getenv("DUSK_RPC_TOKEN"), "token of the admin namespace (rpc.token), defaults to $DUSK_RPC_TOKEN")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [command [args...]]\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Println()
+		usage()
+	}
+	flag.Parse()
+
+	c := newClient(*address, *socket, *user, *pass, *token)
+	if flag.NArg() == 0 {
+		console(c)
+		return
+	}
+
+	if err := run(c, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
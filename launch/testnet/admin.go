@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+)
+
+// listenPeerCommands answers the AddPeer and BanPeer requests, issued by the
+// admin namespace of the RPC server.
+func (s *Server) listenPeerCommands(connMgr *connmgr) error {
+	addPeer := func(r rpcbus.Request) (bytes.Buffer, error) {
+		addr, err := encoding.ReadString(&r.Params)
+		if err != nil {
+			return bytes.Buffer{}, err
+		}
+
+		return bytes.Buffer{}, connMgr.Connect(addr)
+	}
+
+	banPeer := func(r rpcbus.Request) (bytes.Buffer, error) {
+		addr, err := encoding.ReadString(&r.Params)
+		if err != nil {
+			return bytes.Buffer{}, err
+		}
+
+		s.peers.Ban(addr)
+		return bytes.Buffer{}, nil
+	}
+
+	if err := s.rpcBus.Register(rpcbus.AddPeer, addPeer); err != nil {
+		return err
+	}
+
+	return s.rpcBus.Register(rpcbus.BanPeer, banPeer)
+}
+
+// shutdownRequested is closed when the node is stopped through the RPC
+// server. It blocks forever if the RPC server is disabled.
+func (s *Server) shutdownRequested() <-chan struct{} {
+	if s.rpcServ == nil {
+		return nil
+	}
+
+	return s.rpcServ.ShutdownRequested()
+}
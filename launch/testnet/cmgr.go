@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"net"
 	"time"
 
@@ -12,8 +13,11 @@ type CmgrConfig struct {
 	Port     string
 	OnAccept func(net.Conn)
 	OnConn   func(net.Conn, string) // takes the connection  and the string
+	IsBanned func(string) bool      // refuses to dial the addresses banned
 }
 
+var errBanned = errors.New("peer is banned")
+
 type connmgr struct {
 	CmgrConfig
 }
@@ -55,6 +59,10 @@ func NewConnMgr(cfg CmgrConfig) *connmgr {
 // Connect dials a connection with its string, then on succession
 // we pass the connection and the address to the OnConn method
 func (c *connmgr) Connect(addr string) error {
+	if c.CmgrConfig.IsBanned != nil && c.CmgrConfig.IsBanned(addr) {
+		return errBanned
+	}
+
 	conn, err := c.Dial(addr)
 	if err != nil {
 		return err
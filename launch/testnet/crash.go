@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/logging"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/block"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// crashLines is the default amount of log lines in the crash reports
+	crashLines = 200
+	// crashTimeout bounds the time spent querying each subsystem for a crash
+	// report, as the subsystem may well be the cause of the crash
+	crashTimeout = 2 * time.Second
+	// uploadTimeout bounds the time spent uploading a crash report
+	uploadTimeout = 10 * time.Second
+)
+
+// crashReport is the bundle written when the node crashes. The state of the
+// subsystems which do not answer is replaced with their error.
+type crashReport struct {
+	Time       time.Time         `json:"time"`
+	Reason     string            `json:"reason"`
+	Version    string            `json:"version"`
+	Commit     string            `json:"commit"`
+	Config     cfg.Registry      `json:"config"`
+	Tip        *crashTip         `json:"tip,omitempty"`
+	Consensus  *crashConsensus   `json:"consensus,omitempty"`
+	Errors     map[string]string `json:"errors,omitempty"`
+	Logs       []string          `json:"logs"`
+	Goroutines string            `json:"goroutines"`
+}
+
+type crashTip struct {
+	Height uint64 `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+type crashConsensus struct {
+	Round uint64 `json:"round"`
+	Step  uint8  `json:"step"`
+}
+
+// crashReporter writes a crash report when the node panics or logs a fatal
+// error, as configured in logger.crash. Only the first crash is reported.
+type crashReporter struct {
+	dir    string
+	upload string
+	tail   *logging.Tail
+	once   sync.Once
+
+	lock   sync.Mutex
+	rpcBus *rpcbus.RPCBus
+}
+
+// newCrashReporter returns the crash reporter configured, or nil if crash
+// reports are disabled
+func newCrashReporter() *crashReporter {
+	conf := cfg.Get().Logger.Crash
+	if conf.Dir == "" {
+		return nil
+	}
+
+	lines := int(conf.Lines)
+	if lines == 0 {
+		lines = crashLines
+	}
+
+	return &crashReporter{
+		dir:    conf.Dir,
+		upload: conf.Upload,
+		tail:   logging.NewTail(lines),
+	}
+}
+
+// setRPCBus lets the reporter query the subsystems for their state
+func (c *crashReporter) setRPCBus(rpcBus *rpcbus.RPCBus) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rpcBus = rpcBus
+}
+
+// Levels implements logrus.Hook
+func (c *crashReporter) Levels() []log.Level {
+	return []log.Level{log.PanicLevel, log.FatalLevel}
+}
+
+// Fire implements logrus.Hook. Entries are reported before logrus panics or
+// exits.
+func (c *crashReporter) Fire(e *log.Entry) error {
+	c.report(e.Message)
+	return nil
+}
+
+// recover reports a panic of the calling goroutine, and panics again. It must
+// be deferred.
+func (c *crashReporter) recover() {
+	if r := recover(); r != nil {
+		c.report(fmt.Sprint(r))
+		panic(r)
+	}
+}
+
+func (c *crashReporter) report(reason string) {
+	c.once.Do(func() {
+		report := c.collect(reason)
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Printf("could not encode the crash report: %v\n", err)
+			return
+		}
+
+		path := filepath.Join(c.dir, "crash-"+report.Time.Format("20060102T150405")+".json")
+		if err := ioutil.WriteFile(path, b, 0600); err != nil {
+			fmt.Printf("could not write the crash report: %v\n", err)
+		} else {
+			fmt.Printf("crash report written to %s\n", path)
+		}
+
+		if c.upload != "" {
+			if err := upload(c.upload, b); err != nil {
+				fmt.Printf("could not upload the crash report: %v\n", err)
+			}
+		}
+	})
+}
+
+// collect gathers the crash report. The logger is not used, as it is the one
+// reporting the crash.
+func (c *crashReporter) collect(reason string) crashReport {
+	report := crashReport{
+		Time:    time.Now().UTC(),
+		Reason:  reason,
+		Version: protocol.NodeVer.String(),
+		Commit:  protocol.GitCommit,
+		Config:  cfg.Get().Redacted(),
+		Errors:  make(map[string]string),
+		Logs:    c.tail.Lines(),
+	}
+
+	// Stacks of all the goroutines, growing the buffer until they fit
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			report.Goroutines = string(buf[:n])
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	c.lock.Lock()
+	rpcBus := c.rpcBus
+	c.lock.Unlock()
+	if rpcBus == nil {
+		return report
+	}
+
+	if tip, err := fetchCrashTip(rpcBus); err != nil {
+		report.Errors["tip"] = err.Error()
+	} else {
+		report.Tip = tip
+	}
+
+	if state, err := fetchCrashConsensus(rpcBus); err != nil {
+		report.Errors["consensus"] = err.Error()
+	} else {
+		report.Consensus = state
+	}
+
+	return report
+}
+
+func fetchCrashTip(rpcBus *rpcbus.RPCBus) (*crashTip, error) {
+	buf, err := rpcBus.Call(rpcbus.GetLastBlock, rpcbus.NewRequest(bytes.Buffer{}), crashTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&buf, blk); err != nil {
+		return nil, err
+	}
+
+	return &crashTip{blk.Header.Height, hex.EncodeToString(blk.Header.Hash)}, nil
+}
+
+func fetchCrashConsensus(rpcBus *rpcbus.RPCBus) (*crashConsensus, error) {
+	buf, err := rpcBus.Call(rpcbus.GetConsensusState, rpcbus.NewRequest(bytes.Buffer{}), crashTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &crashConsensus{}
+	if err := encoding.ReadUint64LE(&buf, &state.Round); err != nil {
+		return nil, err
+	}
+
+	if err := encoding.ReadUint8(&buf, &state.Step); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// upload POSTs the crash report to url
+func upload(url string, report []byte) error {
+	client := &http.Client{Timeout: uploadTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(report))
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector replied %s", resp.Status)
+	}
+	return nil
+}
@@ -1,15 +1,16 @@
 package main
 
 import (
-	"bytes"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
-	"os/signal"
+	"syscall"
 	"time"
 
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
-	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/logging"
 	log "github.com/sirupsen/logrus"
 )
@@ -20,9 +21,6 @@ const (
 )
 
 func main() {
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
-
 	fmt.Fprintln(os.Stdout, "initializing node...")
 	// Loading all node configurations. Fail-fast if critical error occurs
 	err := cfg.Load(configFileName, nil, nil)
@@ -31,15 +29,32 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Refuse to start with settings the node can not work with
+	known := cfg.Known{
+		Drivers:  database.Drivers(),
+		Networks: protocol.Networks(),
+	}
+	if err := cfg.Get().Validate(known); err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+
 	port := cfg.Get().Network.Port
 	rand.Seed(time.Now().UnixNano())
 
 	// Set up logging.
 	// Any subsystem should be initialized after config and logger loading
 	output := cfg.Get().Logger.Output
-	var logFile *os.File
+	rotation := cfg.Get().Logger.Rotation
+	var logFile io.WriteCloser
 	if cfg.Get().Logger.Output != "stdout" {
-		logFile, err = os.Create(output + port + ".log")
+		path := output + port + ".log"
+		if rotation.MaxSizeMB > 0 {
+			maxAge := time.Duration(rotation.MaxAgeDays) * 24 * time.Hour
+			logFile, err = logging.NewRotatingFile(path, int64(rotation.MaxSizeMB)*1000*1000, maxAge, int(rotation.MaxBackups))
+		} else {
+			logFile, err = os.Create(path)
+		}
 		if err != nil {
 			log.Panic(err)
 		}
@@ -48,11 +63,23 @@ func main() {
 		logFile = os.Stdout
 	}
 
-	logging.InitLog(logFile)
+	// Report panics and fatal errors, along with the last log lines
+	var logOutput io.Writer = logFile
+	crash := newCrashReporter()
+	if crash != nil {
+		defer crash.recover()
+		log.AddHook(crash)
+		logOutput = io.MultiWriter(logFile, crash.tail)
+	}
+
+	logging.InitLog(logOutput)
 
 	log.Infof("Loaded config file %s", cfg.Get().UsedConfigFile)
 	log.Infof("Selected network  %s", cfg.Get().General.Network)
 
+	// Apply the changes made to the live settings of the config file
+	cfg.Watch()
+
 	// Set up profiling tools.
 	profile, err := newProfile()
 	if err != nil {
@@ -66,14 +93,22 @@ func main() {
 	// Setting up the EventBus and the startup processes (like Chain and CommitteeStore)
 	srv := Setup()
 	defer srv.Close()
+	if crash != nil {
+		crash.setRPCBus(srv.rpcBus)
+	}
 
 	//start the connection manager
 	connMgr := NewConnMgr(CmgrConfig{
 		Port:     port,
 		OnAccept: srv.OnAccept,
 		OnConn:   srv.OnConnection,
+		IsBanned: srv.peers.IsBanned,
 	})
 
+	if err := srv.listenPeerCommands(connMgr); err != nil {
+		log.Panic(err)
+	}
+
 	// fetch neighbours addresses from the Seeder
 	ips := ConnectToSeeder()
 
@@ -86,13 +121,18 @@ func main() {
 
 	fmt.Fprintln(os.Stdout, "initialization complete")
 
-	// Wait until the interrupt signal is received from an OS signal or
-	// shutdown is requested through one of the subsystems such as the RPC
-	// server.
-	<-interrupt
+	// Shutdown can be requested through the RPC server
+	if requested := srv.shutdownRequested(); requested != nil {
+		go func() {
+			<-requested
+			srv.supervisor.Shutdown()
+		}()
+	}
 
-	// Graceful shutdown of listening components
-	srv.eventBus.Publish(topics.Quit, new(bytes.Buffer))
+	// Wait until the interrupt signal is received from an OS signal, a
+	// shutdown is requested, or a subsystem failed for good, and stop the
+	// subsystems gracefully
+	srv.supervisor.Wait(os.Interrupt, syscall.SIGTERM)
 
 	log.WithField("prefix", "main").Info("Terminated")
 }
@@ -2,11 +2,17 @@ package main
 
 import (
 	"fmt"
+	"net"
+	"os"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/alert"
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/eventmon/monitor"
+	"github.com/dusk-network/dusk-blockchain/pkg/eventmon/reporter"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing/otlp"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -41,3 +47,98 @@ func startMonitoring(bus eventbus.Broker, monURL string) {
 	}
 	lg.Errorln("Monitoring could not get started")
 }
+
+// LaunchReporter creates the Reporter shipping health reports to the
+// collector configured in network.monitor, if enabled. It returns nil
+// otherwise. The reports are shipped once the Reporter listens.
+func LaunchReporter(bus eventbus.Multicaster) (*reporter.Reporter, error) {
+	conf := cfg.Get().Network.Monitor
+	if !conf.Enabled {
+		return nil, nil
+	}
+
+	interval := time.Duration(conf.Interval) * time.Second
+	if interval == 0 {
+		interval = time.Minute
+	}
+
+	r, err := reporter.New(bus, conf.Address, nodeName(), interval, cfg.GetConsensusParams().ReductionCommitteeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	lg.Infof("Shipping reports to %s every %v", conf.Address, interval)
+	return r, nil
+}
+
+// LaunchAlerter starts checking the node for the conditions configured in
+// alert, if enabled, and alerts the operators through the webhook and command
+// configured
+func LaunchAlerter() {
+	conf := cfg.Get().Alert
+	if !conf.Enabled {
+		return
+	}
+
+	interval := time.Duration(conf.Interval) * time.Second
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	var sinks []alert.Sink
+	if conf.Webhook != "" {
+		sinks = append(sinks, alert.NewWebhook(conf.Webhook))
+	}
+	if conf.Command != "" {
+		sinks = append(sinks, alert.NewCommand(conf.Command))
+	}
+
+	a := alert.New(nodeName(), interval, alert.Thresholds{
+		StallTimeout:    time.Duration(conf.StallTimeout) * time.Second,
+		MinPeers:        int(conf.MinPeers),
+		MaxBlocksFailed: int(conf.MaxBlocksFailed),
+		MinDiskFree:     conf.MinDiskFree / 100,
+		DiskPath:        cfg.Get().Database.Dir,
+	}, sinks...)
+
+	lg.Infof("Checking for alerts every %v", interval)
+	go a.Listen()
+}
+
+// LaunchTracer starts exporting the spans of the traced messages to the
+// collector configured in tracing, if enabled. It returns nil otherwise.
+func LaunchTracer() (*otlp.Exporter, error) {
+	conf := cfg.Get().Tracing
+	if !conf.Enabled {
+		return nil, nil
+	}
+
+	interval := time.Duration(conf.Interval) * time.Second
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+
+	service := conf.Service
+	if service == "" {
+		service = "dusk"
+	}
+
+	e, err := otlp.New(conf.Endpoint, service, nodeName(), interval)
+	if err != nil {
+		return nil, err
+	}
+
+	lg.Infof("Exporting spans to %s every %v", conf.Endpoint, interval)
+	tracing.SetExporter(e)
+	go e.Listen()
+	return e, nil
+}
+
+// nodeName tells the node apart in reports and alerts, by its host and port
+func nodeName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return net.JoinHostPort(host, cfg.Get().Network.Port)
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"expvar"
 	"net/http"
 	"os"
+	"runtime"
 	"runtime/pprof"
 	"strconv"
 	"time"
@@ -22,14 +24,28 @@ type profileMngr struct {
 func newProfile() (*profileMngr, error) {
 	p := new(profileMngr)
 
-	// Enable http profiling server if requested
+	// Enable http profiling server if requested. Along with pprof, it
+	// serves the expvars on /debug/vars, and the stacks of all the
+	// goroutines on /debug/goroutines
 	if cfg.Get().Prof.Address != "" {
+		start := time.Now()
+		expvar.Publish("goroutines", expvar.Func(func() interface{} {
+			return runtime.NumGoroutine()
+		}))
+		expvar.Publish("uptime", expvar.Func(func() interface{} {
+			return int64(time.Since(start).Seconds())
+		}))
+
 		go func() {
 			listenAddr := cfg.Get().Prof.Address
 			log.Infof("Creating profiling server listening on %s", listenAddr)
 			profileRedirect := http.RedirectHandler("/debug/pprof",
 				http.StatusSeeOther)
 			http.Handle("/", profileRedirect)
+			http.HandleFunc("/debug/goroutines", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				_ = pprof.Lookup("goroutine").WriteTo(w, 2)
+			})
 			err := http.ListenAndServe(listenAddr, nil)
 			log.Error(err)
 		}()
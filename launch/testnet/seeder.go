@@ -15,7 +15,7 @@ import (
 
 // ConnectToSeeder initializes the connection with the Voucher Seeder
 func ConnectToSeeder() []string {
-	if cfg.Get().General.Network == "testnet" {
+	if cfg.GetProfile().Magic != "mainnet" {
 		fixedNetwork := cfg.Get().Network.Seeder.Fixed
 		if len(fixedNetwork) > 0 {
 			log.Infof("Fixed-network config activated")
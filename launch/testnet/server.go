@@ -2,11 +2,20 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"net"
+	"net/http"
+	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/eventbridge"
+	"github.com/dusk-network/dusk-blockchain/pkg/eventmon/reporter"
 	"github.com/dusk-network/dusk-blockchain/pkg/gql"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+	"github.com/dusk-network/dusk-blockchain/pkg/node"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing/otlp"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/candidate"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
@@ -17,13 +26,24 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/dupemap"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing/chainsync"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/rpc"
 	log "github.com/sirupsen/logrus"
 
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 )
 
+const (
+	// stopTimeout bounds the time waited for each subsystem to stop
+	stopTimeout = 30 * time.Second
+	// healthTimeout bounds the time the chain takes to answer a health check
+	healthTimeout = 5 * time.Second
+	// rpcBusTimeout is the default of performance.rpcBusTimeout
+	rpcBusTimeout = 60 * time.Second
+)
+
 // Server is the main process of the node
 type Server struct {
 	eventBus *eventbus.EventBus
@@ -32,59 +52,100 @@ type Server struct {
 	dupeMap  *dupemap.DupeMap
 	counter  *chainsync.Counter
 	gossip   *processing.Gossip
+	peers    *peer.Registry
+	rpcServ  *rpc.Server
+
+	mempool         *mempool.Mempool
+	candidateBroker *candidate.Broker
+	transactor      *transactor.Transactor
+	tracer          *otlp.Exporter
+	reporter        *reporter.Reporter
+	gqlServ         *gql.Server
+	supervisor      *node.Supervisor
 }
 
 // Setup creates a new EventBus, generates the BLS and the ED25519 Keys, launches a new `CommitteeStore`, launches the Blockchain process and inits the Stake and Blind Bid channels
+//
+// The components are started by the supervisor, which restarts them if they
+// fail, and stops them on shutdown.
 func Setup() *Server {
+	// Exporting the spans of the messages traced, before any is received
+	tracer, err := LaunchTracer()
+	if err != nil {
+		log.WithField("process", "server").WithError(err).Errorln("could not start the tracer")
+	}
+
 	// creating the eventbus
 	eventBus := eventbus.New()
+	eventBus.EnableDeadLetters(cfg.Get().Logger.DeadLetterSampling)
+	// Components keep track of the chain state through these topics, so
+	// they must be observed in order
+	eventBus.SetOrdered(topics.RoundUpdate)
+	eventBus.SetOrdered(topics.AcceptedBlock)
+	eventBus.EnableJournal(topics.RoundUpdate, int(cfg.Get().Performance.RoundUpdateJournal))
 
 	counter := chainsync.NewCounter(eventBus)
 
 	// creating the rpcbus
 	rpcBus := rpcbus.New()
+	setRPCBusTimeouts(rpcBus)
 
-	m := mempool.NewMempool(eventBus, rpcBus, nil)
-	m.Run()
+	// Nodes storing only the headers can not verify txs, so they run
+	// neither the mempool, the wallet nor the consensus
+	headersOnly := cfg.Get().Chain.HeadersOnly
+	var m *mempool.Mempool
+	if !headersOnly {
+		m = mempool.NewMempool(eventBus, rpcBus, nil)
+	}
 
-	// creating and firing up the chain process
+	// creating the chain process
 	chain, err := chain.New(eventBus, rpcBus, counter)
 	if err != nil {
 		log.Panic(err)
 	}
-	go chain.Listen()
 
 	// Setting up the candidate broker
 	candidateBroker := candidate.NewBroker(eventBus, rpcBus)
-	go candidateBroker.Listen()
 
 	// Setting up a dupemap
 	dupeBlacklist := launchDupeMap(eventBus)
 
 	// Instantiate RPC server
+	var rpcServ *rpc.Server
 	if cfg.Get().RPC.Enabled {
-		rpcServ, err := rpc.NewRPCServer(eventBus, rpcBus)
+		rpcServ, err = rpc.NewRPCServer(eventBus, rpcBus)
 		if err != nil {
-			log.Errorf("RPC http server error: %s", err.Error())
-		}
-
-		if err := rpcServ.Start(); err != nil {
-			log.Errorf("RPC failed to start: %s", err.Error())
+			log.Panicf("RPC http server error: %s", err.Error())
 		}
 	}
 
 	// Instantiate GraphQL server
+	var gqlServ *gql.Server
 	if cfg.Get().Gql.Enabled {
-		gqlServer, err := gql.NewHTTPServer(eventBus, rpcBus)
+		gqlServ, err = gql.NewHTTPServer(eventBus, rpcBus)
 		if err != nil {
-			log.Errorf("GraphQL http server error: %s", err.Error())
+			log.Panicf("GraphQL http server error: %s", err.Error())
 		}
+	}
 
-		if err := gqlServer.Start(); err != nil {
-			log.Errorf("GraphQL failed to start: %s", err.Error())
+	// Instantiate the bridge to external processes
+	if cfg.Get().Bridge.Enabled {
+		exposed := make([]topics.Topic, 0, len(cfg.Get().Bridge.Topics))
+		for _, t := range cfg.Get().Bridge.Topics {
+			exposed = append(exposed, topics.StringToTopic(t))
+		}
+
+		if _, err := eventbridge.Listen(eventBus, cfg.Get().Bridge.Address, exposed); err != nil {
+			log.Errorf("EventBus bridge failed to start: %s", err.Error())
 		}
 	}
 
+	// Shipping health reports to the remote collector
+	healthReporter, err := LaunchReporter(eventBus)
+	if err != nil {
+		log.WithField("process", "server").WithError(err).Errorln("could not start the reporter")
+	}
+
 	// creating the Server
 	srv := &Server{
 		eventBus: eventBus,
@@ -93,23 +154,82 @@ func Setup() *Server {
 		dupeMap:  dupeBlacklist,
 		counter:  counter,
 		gossip:   processing.NewGossip(protocol.TestNet),
+		peers:    peer.NewRegistry(),
+		rpcServ:  rpcServ,
+
+		mempool:         m,
+		candidateBroker: candidateBroker,
+		tracer:          tracer,
+		reporter:        healthReporter,
+		gqlServ:         gqlServ,
+		supervisor:      node.New(supervisorConfig()),
 	}
 
+	if err := srv.peers.Listen(rpcBus); err != nil {
+		log.Panic(err)
+	}
+	srv.peers.ListenRejections(eventBus)
+
 	// Setting up the transactor component
-	transactor, err := transactor.New(eventBus, rpcBus, nil, srv.counter, nil, nil, cfg.Get().General.WalletOnly)
-	if err != nil {
+	if !headersOnly {
+		transactor, err := transactor.New(eventBus, rpcBus, nil, srv.counter, nil, nil, cfg.Get().General.WalletOnly)
+		if err != nil {
+			log.Panic(err)
+		}
+		srv.transactor = transactor
+	}
+
+	srv.addSubsystems()
+	if err := srv.supervisor.Start(); err != nil {
 		log.Panic(err)
 	}
-	go transactor.Listen()
+
+	// Unattended nodes load their wallet at startup
+	if password := cfg.Get().Wallet.Password; password != "" && !headersOnly {
+		go srv.loadWallet(password)
+	}
 
 	// Connecting to the log based monitoring system
 	if err := ConnectToLogMonitor(eventBus); err != nil {
 		log.Panic(err)
 	}
 
+	// Alerting the operators of critical conditions
+	LaunchAlerter()
+
 	return srv
 }
 
+// loadWallet loads the wallet, as the loadwallet RPC does
+func (s *Server) loadWallet(password string) {
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteString(buf, password); err != nil {
+		log.Panic(err)
+	}
+
+	if _, err := s.rpcBus.Call(rpcbus.LoadWallet, rpcbus.NewRequest(*buf), 0); err != nil {
+		log.WithField("process", "server").WithError(err).Errorln("could not load the wallet")
+		return
+	}
+	log.WithField("process", "server").Infoln("wallet loaded")
+}
+
+// setRPCBusTimeouts bounds the internal calls made without a timeout, except
+// those which can legitimately take longer, such as loading a wallet or
+// writing a snapshot
+func setRPCBusTimeouts(rpcBus *rpcbus.RPCBus) {
+	timeout := time.Duration(cfg.Get().Performance.RPCBusTimeout) * time.Second
+	if timeout == 0 {
+		timeout = rpcBusTimeout
+	}
+	rpcBus.SetDefaultTimeout(timeout)
+
+	rpcBus.SetTimeout(rpcbus.CreateWallet, 0)
+	rpcBus.SetTimeout(rpcbus.CreateFromSeed, 0)
+	rpcBus.SetTimeout(rpcbus.LoadWallet, 0)
+	rpcBus.SetTimeout(rpcbus.CreateSnapshot, 0)
+}
+
 func launchDupeMap(eventBus eventbus.Broker) *dupemap.DupeMap {
 	acceptedBlockChan, _ := consensus.InitAcceptedBlockUpdate(eventBus)
 	dupeBlacklist := dupemap.NewDupeMap(1)
@@ -125,6 +245,20 @@ func launchDupeMap(eventBus eventbus.Broker) *dupemap.DupeMap {
 
 // OnAccept read incoming packet from the peers
 func (s *Server) OnAccept(conn net.Conn) {
+	if s.peers.IsBanned(conn.RemoteAddr().String()) {
+		_ = conn.Close()
+		return
+	}
+
+	if !s.peers.AcceptsInbound() {
+		log.WithFields(log.Fields{
+			"process": "server",
+			"address": conn.RemoteAddr().String(),
+		}).Debugln("too many inbound peers, connection refused")
+		_ = conn.Close()
+		return
+	}
+
 	writeQueueChan := make(chan *bytes.Buffer, 1000)
 	exitChan := make(chan struct{}, 1)
 	peerReader, err := peer.NewReader(conn, s.gossip, s.dupeMap, s.eventBus, s.rpcBus, s.counter, writeQueueChan, exitChan)
@@ -144,10 +278,12 @@ func (s *Server) OnAccept(conn net.Conn) {
 		"address": peerReader.Addr(),
 	}).Debugln("connection established")
 
-	go peerReader.ReadLoop()
-
 	peerWriter := peer.NewWriter(conn, s.gossip, s.eventBus)
-	go peerWriter.Serve(writeQueueChan, exitChan)
+	peerWriter.SetServices(peerReader.Services())
+	peerReader.StreamTo(peerWriter)
+
+	go peerReader.ReadLoop()
+	go s.serve(peerWriter, conn, peerReader.Addr(), true, writeQueueChan, exitChan)
 }
 
 // OnConnection is the callback for writing to the peers
@@ -167,19 +303,185 @@ func (s *Server) OnConnection(conn net.Conn, addr string) {
 		"address": peerWriter.Addr(),
 	}).Debugln("connection established")
 
+	// The address dialed may not be the one advertised by the peer
+	if s.peers.IsBanned(peerWriter.Addr()) {
+		_ = conn.Close()
+		return
+	}
+
 	exitChan := make(chan struct{}, 1)
 	peerReader, err := peer.NewReader(conn, s.gossip, s.dupeMap, s.eventBus, s.rpcBus, s.counter, writeQueueChan, exitChan)
 	if err != nil {
 		log.Panic(err)
 	}
 
+	peerReader.StreamTo(peerWriter)
 	go peerReader.ReadLoop()
-	go peerWriter.Serve(writeQueueChan, exitChan)
+	go s.serve(peerWriter, conn, peerWriter.Addr(), false, writeQueueChan, exitChan)
+}
+
+// serve keeps track of the peer in the registry for as long as the
+// connection is open
+func (s *Server) serve(w *peer.Writer, conn net.Conn, addr string, inbound bool, writeQueueChan chan *bytes.Buffer, exitChan chan struct{}) {
+	s.peers.Add(addr, inbound, conn, writeQueueChan)
+	defer s.peers.Remove(addr)
+	w.Serve(writeQueueChan, exitChan)
+}
+
+// addSubsystems hands the components over to the supervisor, in the order
+// they start. They stop in reverse order: the consensus stops first, on the
+// Quit topic, then the components feeding the chain, so that the blocks being
+// accepted are written before the database closes. The consensus keys are
+// wiped last, as nothing may sign anymore.
+func (s *Server) addSubsystems() {
+	if s.tracer != nil {
+		s.supervisor.Add(node.Subsystem{
+			Name: "tracer",
+			// Sending the spans left to the collector
+			Stop: func() error {
+				tracing.SetExporter(nil)
+				s.tracer.Stop()
+				return nil
+			},
+		})
+	}
+
+	s.supervisor.Add(node.Subsystem{Name: "eventbus", Stop: s.eventBus.Close})
+	if s.reporter != nil {
+		s.supervisor.Add(node.Subsystem{
+			Name: "reporter",
+			Run:  s.reporter.Listen,
+			Stop: func() error {
+				s.reporter.Stop()
+				return nil
+			},
+		})
+	}
+	s.supervisor.Add(node.Subsystem{
+		Name: "rpcbus",
+		Stop: func() error {
+			s.rpcBus.Close()
+			return nil
+		},
+	})
+	// Stopped before the rpcbus, which closes the request channels of the
+	// transactor
+	if s.transactor != nil {
+		s.supervisor.Add(node.Subsystem{
+			Name: "transactor",
+			Run:  s.transactor.Listen,
+			Stop: func() error {
+				s.transactor.Close()
+				return nil
+			},
+		})
+	}
+	s.supervisor.Add(node.Subsystem{
+		Name:   "chain",
+		Run:    s.chain.Listen,
+		Stop:   s.chain.Close,
+		Health: s.chainHealth,
+	})
+	if s.mempool != nil {
+		s.supervisor.Add(node.Subsystem{
+			Name: "mempool",
+			Run:  s.mempool.Listen,
+			Stop: func() error {
+				s.mempool.Stop()
+				return nil
+			},
+		})
+	}
+	s.supervisor.Add(node.Subsystem{
+		Name: "candidate",
+		Run:  s.candidateBroker.Listen,
+		Stop: func() error {
+			s.candidateBroker.Close()
+			return nil
+		},
+	})
+
+	if s.rpcServ != nil {
+		s.supervisor.Add(node.Subsystem{Name: "rpc", Start: s.rpcServ.Start, Stop: s.rpcServ.Stop})
+	}
+
+	if s.gqlServ != nil {
+		s.supervisor.Add(node.Subsystem{Name: "gql", Start: s.gqlServ.Start, Stop: s.gqlServ.Stop})
+	}
+
+	// Serve the metrics of the node
+	if conf := cfg.Get().Metrics; conf.Enabled {
+		var metricsServ *http.Server
+		s.supervisor.Add(node.Subsystem{
+			Name: "metrics",
+			Start: func() (err error) {
+				metricsServ, err = metrics.Listen(conf.Address, conf.Path)
+				return err
+			},
+			Stop: func() error {
+				return metricsServ.Close()
+			},
+		})
+	}
+
+	s.supervisor.Add(node.Subsystem{
+		Name: "peers",
+		Stop: func() error {
+			s.peers.DisconnectAll()
+			return nil
+		},
+		Health: func() error {
+			if len(s.peers.Peers()) == 0 {
+				return errors.New("no peers connected")
+			}
+			return nil
+		},
+	})
+
+	// The consensus is started by the transactor, once the wallet is loaded
+	if s.transactor != nil {
+		s.supervisor.Add(node.Subsystem{
+			Name: "consensus",
+			Stop: func() error {
+				s.eventBus.Publish(topics.Quit, new(bytes.Buffer))
+				return nil
+			},
+		})
+	}
+}
+
+// chainHealth checks that the chain answers for its tip
+func (s *Server) chainHealth() error {
+	_, err := s.rpcBus.Call(rpcbus.GetLastBlock, rpcbus.NewRequest(bytes.Buffer{}), healthTimeout)
+	return err
+}
+
+// supervisorConfig returns the supervisor settings, with their defaults
+func supervisorConfig() node.Config {
+	conf := cfg.Get().Supervisor
+	c := node.Config{
+		Restart:        conf.Restart,
+		MaxRestarts:    int(conf.MaxRestarts),
+		RestartWindow:  time.Duration(conf.RestartWindow) * time.Second,
+		HealthInterval: time.Duration(conf.HealthInterval) * time.Second,
+		StopTimeout:    stopTimeout,
+	}
+
+	if c.MaxRestarts == 0 {
+		c.MaxRestarts = 5
+	}
+
+	if c.RestartWindow == 0 {
+		c.RestartWindow = 5 * time.Minute
+	}
+
+	if c.HealthInterval == 0 {
+		c.HealthInterval = 10 * time.Second
+	}
+	return c
 }
 
-// Close the chain and the connections created through the RPC bus
+// Close stops the subsystems, if the supervisor did not already
 func (s *Server) Close() {
-	// TODO: disconnect peers
-	s.chain.Close()
-	s.rpcBus.Close()
+	s.supervisor.Stop()
 }
@@ -0,0 +1,247 @@
+// Package alert watches the node for critical conditions, such as a stalled
+// chain or a node without peers, and notifies the operators through the
+// configured sinks. The conditions are read off the metrics of the node, so
+// that the package does not depend on the components it watches.
+package alert
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "alert")
+
+// Names of the conditions, as found in the alerts
+const (
+	ChainStall   = "chain_stall"
+	NoPeers      = "no_peers"
+	BlocksFailed = "blocks_failed"
+	DiskFull     = "disk_full"
+)
+
+// Alert is a condition which was entered or resolved
+type Alert struct {
+	Condition string    `json:"condition"`
+	Message   string    `json:"message"`
+	Resolved  bool      `json:"resolved"`
+	Time      time.Time `json:"time"`
+	Node      string    `json:"node"`
+}
+
+func (a Alert) String() string {
+	state := "FIRING"
+	if a.Resolved {
+		state = "RESOLVED"
+	}
+	return fmt.Sprintf("[%s] %s on %s: %s", state, a.Condition, a.Node, a.Message)
+}
+
+// Sink delivers the alerts to the operators
+type Sink interface {
+	Send(Alert) error
+}
+
+// Thresholds above (or below) which the conditions fire. Zero values disable
+// the condition.
+type Thresholds struct {
+	// StallTimeout is the time without the chain growing
+	StallTimeout time.Duration
+	// MinPeers is the amount of peers below which the node is isolated
+	MinPeers int
+	// MaxBlocksFailed is the amount of blocks failing verification between
+	// two checks
+	MaxBlocksFailed int
+	// MinDiskFree is the share of free space left on the disk of DiskPath
+	MinDiskFree float64
+	DiskPath    string
+}
+
+// condition reports whether it holds at now, and why
+type condition func(now time.Time) (bool, string, error)
+
+// Alerter checks the conditions every interval, and sends an alert to its
+// sinks when one starts or stops holding
+type Alerter struct {
+	node     string
+	interval time.Duration
+	sinks    []Sink
+	quit     chan struct{}
+
+	names      []string
+	conditions map[string]condition
+	firing     map[string]bool
+
+	// state of the stall and verification conditions between checks
+	height       float64
+	grewAt       time.Time
+	blocksFailed float64
+	lock         sync.Mutex
+}
+
+// New creates an Alerter checking the conditions with thresholds t. node
+// identifies the node in the alerts.
+func New(node string, interval time.Duration, t Thresholds, sinks ...Sink) *Alerter {
+	a := &Alerter{
+		node:       node,
+		interval:   interval,
+		sinks:      sinks,
+		quit:       make(chan struct{}),
+		conditions: make(map[string]condition),
+		firing:     make(map[string]bool),
+		grewAt:     time.Now(),
+	}
+	a.height, _ = metrics.Value("dusk_chain_height")
+	a.blocksFailed, _ = metrics.Value("dusk_chain_blocks_rejected_total")
+
+	if t.StallTimeout > 0 {
+		a.add(ChainStall, a.stalled(t.StallTimeout))
+	}
+	if t.MinPeers > 0 {
+		a.add(NoPeers, a.isolated(t.MinPeers))
+	}
+	if t.MaxBlocksFailed > 0 {
+		a.add(BlocksFailed, a.failing(t.MaxBlocksFailed))
+	}
+	if t.MinDiskFree > 0 && t.DiskPath != "" {
+		a.add(DiskFull, diskFull(t.DiskPath, t.MinDiskFree))
+	}
+	return a
+}
+
+func (a *Alerter) add(name string, c condition) {
+	a.names = append(a.names, name)
+	a.conditions[name] = c
+}
+
+// Listen checks the conditions every interval, until Stop is called
+func (a *Alerter) Listen() {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, alert := range a.check(now) {
+				a.send(alert)
+			}
+		case <-a.quit:
+			return
+		}
+	}
+}
+
+// Stop stops checking the conditions
+func (a *Alerter) Stop() {
+	close(a.quit)
+}
+
+// check returns the alerts for the conditions which started or stopped
+// holding since the last check
+func (a *Alerter) check(now time.Time) []Alert {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var alerts []Alert
+	for _, name := range a.names {
+		holds, message, err := a.conditions[name](now)
+		if err != nil {
+			lg.WithError(err).WithField("condition", name).Warnln("could not check the condition")
+			continue
+		}
+
+		if holds == a.firing[name] {
+			continue
+		}
+
+		a.firing[name] = holds
+		if !holds {
+			message = "back to normal"
+		}
+		alerts = append(alerts, Alert{Condition: name, Message: message, Resolved: !holds, Time: now.UTC(), Node: a.node})
+	}
+	return alerts
+}
+
+func (a *Alerter) send(alert Alert) {
+	l := lg.WithField("condition", alert.Condition)
+	if alert.Resolved {
+		l.Infoln(alert.Message)
+	} else {
+		l.Warnln(alert.Message)
+	}
+
+	for _, sink := range a.sinks {
+		if err := sink.Send(alert); err != nil {
+			l.WithError(err).Warnln("could not send the alert")
+		}
+	}
+}
+
+// stalled holds when the chain did not grow for timeout
+func (a *Alerter) stalled(timeout time.Duration) condition {
+	return func(now time.Time) (bool, string, error) {
+		height, ok := metrics.Value("dusk_chain_height")
+		if !ok {
+			return false, "", errNoMetric("dusk_chain_height")
+		}
+
+		if height != a.height {
+			a.height, a.grewAt = height, now
+		}
+
+		idle := now.Sub(a.grewAt)
+		return idle >= timeout, fmt.Sprintf("no block accepted for %s, at height %.0f", idle.Round(time.Second), height), nil
+	}
+}
+
+// isolated holds when less than min peers are connected
+func (a *Alerter) isolated(min int) condition {
+	return func(time.Time) (bool, string, error) {
+		peers, ok := metrics.Value("dusk_peer_connected")
+		if !ok {
+			return false, "", errNoMetric("dusk_peer_connected")
+		}
+		return peers < float64(min), fmt.Sprintf("%.0f peer(s) connected", peers), nil
+	}
+}
+
+// failing holds when more than max blocks failed verification since the last
+// check
+func (a *Alerter) failing(max int) condition {
+	return func(time.Time) (bool, string, error) {
+		total, ok := metrics.Value("dusk_chain_blocks_rejected_total")
+		if !ok {
+			return false, "", errNoMetric("dusk_chain_blocks_rejected_total")
+		}
+
+		failed := total - a.blocksFailed
+		a.blocksFailed = total
+		return failed > float64(max), fmt.Sprintf("%.0f block(s) failed verification since the last check", failed), nil
+	}
+}
+
+// diskFull holds when less than min of the disk of path is free
+func diskFull(path string, min float64) condition {
+	return func(time.Time) (bool, string, error) {
+		free, total, err := diskSpace(path)
+		if err != nil {
+			return false, "", err
+		}
+
+		if total == 0 {
+			return false, "", nil
+		}
+
+		share := float64(free) / float64(total)
+		return share < min, fmt.Sprintf("%.1f%% of the disk of %s is free", 100*share, path), nil
+	}
+}
+
+type errNoMetric string
+
+func (e errNoMetric) Error() string {
+	return "metric " + string(e) + " is not registered"
+}
@@ -0,0 +1,82 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// The metrics read by the Alerter, registered here in place of the chain and
+// the peer registry
+var (
+	height   = metrics.NewGauge("dusk_chain_height", "Height of the chain tip")
+	peers    = metrics.NewGauge("dusk_peer_connected", "Peers connected")
+	rejected = metrics.NewCounter("dusk_chain_blocks_rejected_total", "Blocks which failed verification")
+)
+
+// TestCheck checks that alerts are raised when a condition starts holding,
+// and once more when it is resolved
+func TestCheck(t *testing.T) {
+	height.Set(10)
+	peers.Set(3)
+	a := New("node", time.Second, Thresholds{StallTimeout: time.Minute, MinPeers: 1, MaxBlocksFailed: 2})
+	start := a.grewAt
+
+	// All good
+	assert.Empty(t, a.check(start.Add(30*time.Second)))
+
+	// The chain stalls, and the peers go away
+	peers.Set(0)
+	alerts := a.check(start.Add(61 * time.Second))
+	if assert.Len(t, alerts, 2) {
+		assert.Equal(t, ChainStall, alerts[0].Condition)
+		assert.Equal(t, NoPeers, alerts[1].Condition)
+		assert.False(t, alerts[1].Resolved)
+	}
+
+	// Still the case, which is not alerted twice
+	assert.Empty(t, a.check(start.Add(90*time.Second)))
+
+	// The chain grows again, while blocks fail verification
+	height.Set(11)
+	rejected.Add(3)
+	alerts = a.check(start.Add(120 * time.Second))
+	if assert.Len(t, alerts, 2) {
+		assert.Equal(t, ChainStall, alerts[0].Condition)
+		assert.True(t, alerts[0].Resolved)
+		assert.Equal(t, BlocksFailed, alerts[1].Condition)
+		assert.False(t, alerts[1].Resolved)
+	}
+
+	// Failures are counted between checks
+	alerts = a.check(start.Add(150 * time.Second))
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, BlocksFailed, alerts[0].Condition)
+		assert.True(t, alerts[0].Resolved)
+	}
+}
+
+// TestWebhook checks that alerts are POSTed as JSON
+func TestWebhook(t *testing.T) {
+	received := make(chan Alert, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var a Alert
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- a
+	}))
+	defer srv.Close()
+
+	sent := Alert{Condition: DiskFull, Message: "1.0% of the disk of /data is free", Time: time.Now().UTC(), Node: "node"}
+	assert.NoError(t, NewWebhook(srv.URL).Send(sent))
+	a := <-received
+	assert.Equal(t, sent.Condition, a.Condition)
+	assert.Equal(t, sent.Message, a.Message)
+}
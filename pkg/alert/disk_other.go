@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package alert
+
+import "errors"
+
+func diskSpace(string) (uint64, uint64, error) {
+	return 0, 0, errors.New("disk space not supported on this platform")
+}
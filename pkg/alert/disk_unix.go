@@ -0,0 +1,18 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package alert
+
+import "syscall"
+
+// diskSpace returns the bytes available to the node, and the size of the
+// disk holding path
+func diskSpace(path string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	size := uint64(stat.Bsize)
+	return uint64(stat.Bavail) * size, uint64(stat.Blocks) * size, nil
+}
@@ -0,0 +1,88 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// sendTimeout bounds the time spent delivering an alert to a sink
+const sendTimeout = 10 * time.Second
+
+// Webhook POSTs the alerts as JSON to a URL
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook creates a Webhook POSTing to url
+func NewWebhook(url string) *Webhook {
+	return &Webhook{url: url, client: &http.Client{Timeout: sendTimeout}}
+}
+
+// Send implements Sink
+func (w *Webhook) Send(a Alert) error {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return errors.New("webhook replied " + resp.Status)
+	}
+	return nil
+}
+
+// Command runs a shell command for every alert, with the alert on its
+// standard input, and its fields in the DUSK_ALERT_CONDITION,
+// DUSK_ALERT_STATE and DUSK_ALERT_MESSAGE environment variables. It is meant
+// to send the alerts by email, e.g with
+// mail -s "dusk $DUSK_ALERT_CONDITION" ops@example.com
+type Command struct {
+	command string
+}
+
+// NewCommand creates a Command running command with sh
+func NewCommand(command string) *Command {
+	return &Command{command}
+}
+
+// Send implements Sink
+func (c *Command) Send(a Alert) error {
+	state := "firing"
+	if a.Resolved {
+		state = "resolved"
+	}
+
+	cmd := exec.Command("sh", "-c", c.command)
+	cmd.Stdin = bytes.NewBufferString(a.String() + "\n")
+	cmd.Env = append(os.Environ(),
+		"DUSK_ALERT_CONDITION="+a.Condition,
+		"DUSK_ALERT_STATE="+state,
+		"DUSK_ALERT_MESSAGE="+a.Message,
+	)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(sendTimeout):
+		_ = cmd.Process.Kill()
+		return errors.New("alert command timed out")
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ParseCheckpoint parses a checkpoint, as in "height:hash" with the hash of
+// the block in hex
+func ParseCheckpoint(s string) (uint64, []byte, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0, nil, errors.New("must be height:hash")
+	}
+
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, nil, errors.New("invalid height " + parts[0])
+	}
+
+	hash, err := hex.DecodeString(parts[1])
+	if err != nil || len(hash) != 32 {
+		return 0, nil, errors.New("hash must be 32 bytes in hex")
+	}
+
+	return height, hash, nil
+}
@@ -0,0 +1,135 @@
+package config
+
+import "time"
+
+// MaxCommitteeSize is the upper bound of the committee sizes, as the votes of
+// a committee are marked in a 64 bits set
+const MaxCommitteeSize = 64
+
+// MinStakeMaturity is the lowest stake maturity. As the consensus runs two
+// rounds ahead of the chain, a stake can not count in the committees of the
+// rounds already started when its block gets accepted.
+const MinStakeMaturity = 2
+
+// ConsensusParams are the parameters of the consensus components. The
+// timeouts and the workers are set in the [consensus] section of the
+// configuration. The other parameters must be the same on every node of a
+// network: they are those of the network profile, and can not be configured.
+type ConsensusParams struct {
+	// Timeout of the first attempt of a step, and the cap of the timeout
+	// which doubles after every failed attempt. A zero MaxStepTimeout does
+	// not cap it
+	StepTimeout    time.Duration
+	MaxStepTimeout time.Duration
+
+	// Maximum size of the reduction and agreement committees
+	ReductionCommitteeSize int
+	AgreementCommitteeSize int
+	// Share of the votes of a committee needed to reach quorum
+	QuorumRatio float64
+
+	// Workers verifying the agreement messages
+	AccumulatorWorkers int
+
+	// A block must be timestamped after the median timestamp of the last
+	// MedianTimeBlocks blocks, and at most MaxFutureDrift ahead of the clock
+	MedianTimeBlocks int
+	MaxFutureDrift   time.Duration
+
+	// Confirmations a stake needs before counting in the committees. It is
+	// at least MinStakeMaturity
+	StakeMaturity uint64
+	// Height from which the committees are extracted out of the active
+	// stakes only, see user.Provisioners.CreateVotingCommittee
+	ActiveStakesHeight uint64
+	// Height from which bids, stakes and timelocks must lock their outputs,
+	// see verifiers.CheckLockTime
+	LockTimeHeight uint64
+}
+
+var defaultConsensusParams = ConsensusParams{
+	StepTimeout:            ConsensusTimeOut,
+	ReductionCommitteeSize: MaxCommitteeSize,
+	AgreementCommitteeSize: MaxCommitteeSize,
+	QuorumRatio:            0.75,
+	AccumulatorWorkers:     4,
+	MedianTimeBlocks:       11,
+	MaxFutureDrift:         2 * time.Minute,
+	StakeMaturity:          MinStakeMaturity,
+}
+
+// GetConsensusParams returns the parameters of the consensus. Every parameter
+// set in the configuration or the network profile overrides the default one.
+func GetConsensusParams() ConsensusParams {
+	return Get().ConsensusParams()
+}
+
+// ConsensusParams returns the parameters of the consensus set in r and in the
+// profile of its network, falling back to the defaults
+func (r Registry) ConsensusParams() ConsensusParams {
+	p := defaultConsensusParams
+	c := r.Consensus
+
+	if c.StepTimeout != 0 {
+		p.StepTimeout = time.Duration(c.StepTimeout) * time.Millisecond
+	}
+
+	if c.MaxStepTimeout != 0 {
+		p.MaxStepTimeout = time.Duration(c.MaxStepTimeout) * time.Millisecond
+	}
+
+	// performance.accumulatorWorkers predates the [consensus] section
+	if c.AccumulatorWorkers != 0 {
+		p.AccumulatorWorkers = int(c.AccumulatorWorkers)
+	} else if r.Performance.AccumulatorWorkers != 0 {
+		p.AccumulatorWorkers = r.Performance.AccumulatorWorkers
+	}
+
+	if profile, ok := LookupProfile(r.General.Network); ok {
+		p.StakeMaturity = profile.StakeMaturity
+		p.ActiveStakesHeight = profile.ActiveStakesHeight
+		p.LockTimeHeight = profile.LockTimeHeight
+
+		if profile.ReductionCommitteeSize != 0 {
+			p.ReductionCommitteeSize = profile.ReductionCommitteeSize
+		}
+
+		if profile.AgreementCommitteeSize != 0 {
+			p.AgreementCommitteeSize = profile.AgreementCommitteeSize
+		}
+
+		if profile.QuorumRatio != 0 {
+			p.QuorumRatio = profile.QuorumRatio
+		}
+
+		if profile.MedianTimeBlocks != 0 {
+			p.MedianTimeBlocks = profile.MedianTimeBlocks
+		}
+
+		if profile.MaxFutureDrift != 0 {
+			p.MaxFutureDrift = profile.MaxFutureDrift
+		}
+	}
+
+	return p
+}
+
+// StakeStart returns the height from which a stake included in the block at
+// height counts in the committees. The stakes of the genesis block only wait
+// for MinStakeMaturity, so that the network can start.
+func (p ConsensusParams) StakeStart(height uint64) uint64 {
+	if height == 0 {
+		return MinStakeMaturity
+	}
+	return height + p.StakeMaturity
+}
+
+// NextTimeout returns the timeout of the attempt following a failed one which
+// lasted t
+func (p ConsensusParams) NextTimeout(t time.Duration) time.Duration {
+	t *= 2
+	if p.MaxStepTimeout != 0 && t > p.MaxStepTimeout {
+		return p.MaxStepTimeout
+	}
+	return t
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConsensusParams(t *testing.T) {
+	var r Registry
+	r.Consensus.MaxStepTimeout = 12000
+	r.Consensus.QuorumRatio = 0.8
+	r.Performance.AccumulatorWorkers = 2
+
+	p := r.ConsensusParams()
+	if p.StepTimeout != ConsensusTimeOut {
+		t.Errorf("expected the default step timeout, got %v", p.StepTimeout)
+	}
+
+	// The quorum ratio can only be set by the network profile
+	if p.QuorumRatio != defaultConsensusParams.QuorumRatio {
+		t.Errorf("expected the default quorum ratio, got %v", p.QuorumRatio)
+	}
+
+	if p.ReductionCommitteeSize != MaxCommitteeSize {
+		t.Errorf("expected the default committee size, got %d", p.ReductionCommitteeSize)
+	}
+
+	if p.AccumulatorWorkers != 2 {
+		t.Errorf("expected the performance workers, got %d", p.AccumulatorWorkers)
+	}
+
+	// The timeout doubles up to the max
+	if next := p.NextTimeout(5 * time.Second); next != 10*time.Second {
+		t.Errorf("expected the timeout to double, got %v", next)
+	}
+
+	if next := p.NextTimeout(10 * time.Second); next != 12*time.Second {
+		t.Errorf("expected the max timeout, got %v", next)
+	}
+
+	// Stakes wait for the maturity, except in the genesis block
+	if start := p.StakeStart(100); start != 100+MinStakeMaturity {
+		t.Errorf("expected the default maturity, got %d", start)
+	}
+
+	if start := p.StakeStart(0); start != MinStakeMaturity {
+		t.Errorf("expected genesis stakes to start at %d, got %d", MinStakeMaturity, start)
+	}
+
+	// The stake parameters are those of the network
+	r.General.Network = "testnet"
+	p = r.ConsensusParams()
+	testnet, _ := LookupProfile("testnet")
+	if p.StakeMaturity != testnet.StakeMaturity || p.ActiveStakesHeight != testnet.ActiveStakesHeight {
+		t.Errorf("expected the stake parameters of the testnet, got %d and %d", p.StakeMaturity, p.ActiveStakesHeight)
+	}
+
+	if p.LockTimeHeight != testnet.LockTimeHeight {
+		t.Errorf("expected the lock time activation of the testnet, got %d", p.LockTimeHeight)
+	}
+}
+
+func TestProfileStakeMaturity(t *testing.T) {
+	for _, name := range Profiles() {
+		p, _ := LookupProfile(name)
+		if p.StakeMaturity < MinStakeMaturity {
+			t.Errorf("stake maturity of %s below %d", name, MinStakeMaturity)
+		}
+	}
+}
+
+func TestProfileCommittees(t *testing.T) {
+	for _, name := range Profiles() {
+		p := Registry{General: generalConfiguration{Network: name}}.ConsensusParams()
+		if p.ReductionCommitteeSize > MaxCommitteeSize || p.AgreementCommitteeSize > MaxCommitteeSize {
+			t.Errorf("committees of %s above %d", name, MaxCommitteeSize)
+		}
+
+		// Below half of the votes, two hashes could reach quorum in the
+		// same step
+		if p.QuorumRatio <= 0.5 || p.QuorumRatio > 1 {
+			t.Errorf("quorum ratio of %s out of range: %v", name, p.QuorumRatio)
+		}
+	}
+}
+
+func TestRegisterProfile(t *testing.T) {
+	orig, _ := LookupProfile("devnet")
+	defer RegisterProfile("devnet", orig)
+
+	p := orig
+	p.AgreementCommitteeSize = 3
+	RegisterProfile("DevNet", p)
+
+	r := Registry{General: generalConfiguration{Network: "devnet"}}
+	if size := r.ConsensusParams().AgreementCommitteeSize; size != 3 {
+		t.Errorf("expected the committee size of the registered profile, got %d", size)
+	}
+}
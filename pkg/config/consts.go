@@ -29,10 +29,8 @@ const (
 
 func DecodeGenesis() *block.Block {
 	b := block.NewBlock()
-	switch Get().General.Network {
-	case "testnet":
-
-		blob, err := hex.DecodeString(TestNetGenesisBlob)
+	if genesis := GetProfile().Genesis; genesis != "" {
+		blob, err := hex.DecodeString(genesis)
 		if err != nil {
 			log.Panic(err)
 		}
@@ -45,7 +43,7 @@ func DecodeGenesis() *block.Block {
 
 		// For some reason, the testnet genesis block root hash
 		// is not correctly set.
-		_ = b.SetRoot()
+		_ = marshalling.SetTxRoot(b)
 	}
 	return b
 }
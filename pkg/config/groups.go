@@ -9,6 +9,42 @@ type loggerConfiguration struct {
 	Level   string
 	Output  string
 	Monitor logMonitorConfiguration
+
+	// Levels of the subsystems (chain, consensus, peer, mempool, kadcast)
+	// or of the processes, overriding Level for their entries
+	Levels map[string]string
+
+	// Record one every DeadLetterSampling messages published on topics
+	// without listeners. 0 disables the recording
+	DeadLetterSampling uint32
+
+	// Format of the entries, "text" or "json". Defaults to text
+	Format string
+	// Rotation of the log file. Not applied when logging to stdout
+	Rotation logRotationConfiguration
+	// Log one every TraceSampling trace entries of each process. 0 logs them
+	// all
+	TraceSampling uint32
+	// Reports written when the node panics or exits on a fatal error
+	Crash crashConfiguration
+}
+
+type crashConfiguration struct {
+	// Directory the crash reports are written to. Empty disables them
+	Dir string
+	// Amount of the last log lines included. 0 defaults to 200
+	Lines uint32
+	// URL the reports are POSTed to, if set
+	Upload string
+}
+
+type logRotationConfiguration struct {
+	// Size in MB above which the log file is rotated. 0 disables rotation
+	MaxSizeMB uint32
+	// Days after which rotated files are removed. 0 keeps them
+	MaxAgeDays uint32
+	// Amount of rotated files kept. 0 keeps them all
+	MaxBackups uint32
 }
 
 // log based monitoring defined in pkg/eventmon/logger
@@ -19,14 +55,53 @@ type logMonitorConfiguration struct {
 }
 
 type networkConfiguration struct {
-	Seeder  seedersConfiguration
-	Monitor monitorConfiguration
-	Port    string
+	Seeder    seedersConfiguration
+	Monitor   monitorConfiguration
+	Republish republishConfiguration
+	Port      string
+
+	// Minimum protocol version required from peers
+	MinPeerVersion string
+	// Until this deadline (RFC3339), peers running the release right
+	// before MinPeerVersion are accepted as well
+	VersionGraceUntil string
+
+	// Ban score at which a misbehaving peer gets banned. Every message
+	// refused by a validator adds 10. Zero disables banning on misbehavior
+	BanThreshold uint32
+
+	// Peers accepted from incoming connections, at most. Zero does not
+	// limit them
+	MaxInboundPeers uint32
+}
+
+// pkg/util/nativeutils/republisher configs
+type republishConfiguration struct {
+	// Seconds during which a gossiped message is republished at most
+	// once. Defaults to 60
+	Window uint32
+
+	// Messages republished per second on each topic, and the burst
+	// allowed above that rate. A zero Rate disables the limit
+	Rate  float64
+	Burst uint32
+	// Same as above, for the messages received from each peer
+	PeerRate  float64
+	PeerBurst uint32
+
+	// Maximum random delay, in milliseconds, before a message is
+	// republished. Zero republishes immediately
+	Jitter uint32
+	// If the message is received from SkipAfter other peers during the
+	// delay, it is not republished. Zero never skips
+	SkipAfter uint32
 }
 
 type monitorConfiguration struct {
 	Address string
 	Enabled bool
+	// Interval is the number of seconds between reports. 0 defaults to 60
+	Interval uint32
 }
 
 type seedersConfiguration struct {
@@ -40,10 +115,35 @@ type databaseConfiguration struct {
 	Dir    string
 }
 
+// pkg/core/chain package configs
+type chainConfiguration struct {
+	// Snapshot is the path of a chain snapshot to bootstrap an empty
+	// database from. Its tip must be the Checkpoint. It must be kept once
+	// imported, as the provisioners are restored from it
+	Snapshot string
+	// Checkpoint is a trusted block, as in "height:hash". Blocks leading to
+	// it are accepted without verifying the proofs of their txs
+	Checkpoint string
+	// HeadersOnly verifies and stores the headers of the blocks, with their
+	// certificates, rather than the whole blocks. Only the stakes and bids
+	// are kept, as the committees are drawn from them. The mempool, the
+	// wallet and the consensus do not run
+	HeadersOnly bool
+}
+
 // wallet configs
 type walletConfiguration struct {
 	File  string
 	Store string
+
+	// Password of the wallet, loaded at startup if set. Like the other
+	// secrets, it can refer to a file or to an ENV variable, see secrets.go
+	Password string
+
+	// Fee paid by every transaction, and for each byte of its encoding.
+	// The fee never goes below the minimum accepted by the mempool
+	FeeBase    uint64
+	FeePerByte uint64
 }
 
 // pprof configs
@@ -64,6 +164,32 @@ type rpcConfiguration struct {
 	Enabled bool
 	User    string
 	Pass    string
+
+	// Bearer token granting admin access, as an alternative to User/Pass
+	Token string
+	// TLS certificate and key. TLS is enabled when both are set
+	CertFile string
+	KeyFile  string
+	// CA used to verify client certificates. Clients presenting a valid
+	// certificate are granted admin access
+	ClientCAFile string
+
+	// Maximum amount of concurrent websocket clients. Defaults to 100
+	MaxSubscribers int
+	// Origins browsers can open a websocket from. "*" allows any origin
+	AllowedOrigins []string
+
+	// Seconds for which the results of block, transaction and provisioner
+	// queries are cached. 0 disables the cache
+	CacheTTL uint
+
+	// Seconds after which /healthz reports the chain as stalled, if no
+	// block is accepted meanwhile. 0 disables the check
+	StallTimeout uint
+
+	// Milliseconds above which calls are logged as slow. 0 disables the
+	// logging
+	SlowCallThreshold uint
 }
 
 type gqlConfiguration struct {
@@ -71,11 +197,87 @@ type gqlConfiguration struct {
 	Enabled bool
 	User    string
 	Pass    string
+
+	// Maximum amount of concurrent websocket subscriptions. Defaults to 100
+	MaxSubscribers int
+	// Origins browsers can open a websocket from. "*" allows any origin
+	AllowedOrigins []string
+}
+
+// pkg/eventbridge package configs
+type bridgeConfiguration struct {
+	Enabled bool
+	// Path of the unix socket to listen on
+	Address string
+	// Topics clients are allowed to subscribe to
+	Topics []string
+}
+
+// pkg/metrics package configs
+type metricsConfiguration struct {
+	Enabled bool
+	// Address to serve the metrics on, at Path
+	Address string
+	// Path of the metrics endpoint, /metrics if empty
+	Path string
+}
+
+// pkg/alert package configs. The conditions are checked every Interval
+// seconds, 0 meaning 30. A zero threshold disables its condition
+type alertConfiguration struct {
+	Enabled  bool
+	Interval uint32
+
+	// Seconds without a block accepted before the chain is stalled
+	StallTimeout uint32
+	// Peers below which the node is isolated
+	MinPeers uint32
+	// Blocks failing verification between two checks
+	MaxBlocksFailed uint32
+	// Share of the database disk, in percent, which must stay free
+	MinDiskFree float64
+
+	// URL to POST the alerts to as JSON, and shell command run with each
+	// alert on its standard input, e.g to send it by email
+	Webhook string
+	Command string
+}
+
+// pkg/util/nativeutils/tracing/otlp package configs
+type tracingConfiguration struct {
+	Enabled bool
+	// OTLP/HTTP collector the spans are exported to, e.g
+	// http://localhost:4318
+	Endpoint string
+	// Service name of the spans. Defaults to dusk
+	Service string
+	// Seconds between exports. Defaults to 5
+	Interval uint32
+}
+
+// pkg/node package configs
+type supervisorConfiguration struct {
+	// Restart the subsystems failing, rather than shutting the node down
+	Restart bool
+	// Restarts of a subsystem allowed within RestartWindow seconds, after
+	// which the node shuts down. Default to 5 and 300
+	MaxRestarts   uint32
+	RestartWindow uint32
+	// Seconds between two health checks of the subsystems. Defaults to 10
+	HealthInterval uint32
 }
 
 // Performance parameters
 type performanceConfiguration struct {
 	AccumulatorWorkers int
+	// Workers verifying the transactions of a block. Zero means one per CPU
+	VerificationWorkers int
+	// Timeout, in seconds, of the internal calls between the subsystems
+	// made without a timeout of their own. Zero means 60
+	RPCBusTimeout uint32
+	// Round updates retained by the EventBus, and replayed to the consensus
+	// when it starts after them. Zero disables the journal
+	RoundUpdateJournal uint32
 }
 
 type mempoolConfiguration struct {
@@ -83,9 +285,65 @@ type mempoolConfiguration struct {
 	PoolType    string
 	PreallocTxs uint32
 	MaxInvItems uint32
+
+	// Minimum fee per byte of the txs relayed, and included in candidate
+	// blocks. Zero accepts any tx paying the consensus MinFee
+	MinRelayFee uint64
 }
 
 type consensusConfiguration struct {
 	DefaultLockTime uint64
 	DefaultAmount   uint64
+
+	// Base timeout of the consensus steps, in milliseconds. Defaults to the
+	// one of the network profile. The timeout doubles after every failed
+	// attempt, up to MaxStepTimeout. A zero MaxStepTimeout does not cap it
+	StepTimeout    uint32
+	MaxStepTimeout uint32
+
+	// Committee sizes, quorum ratio and block timestamp rules. Every node of
+	// a network must share them: they are set by the network profile, and
+	// Validate refuses any local value
+	ReductionCommitteeSize uint32
+	AgreementCommitteeSize uint32
+	QuorumRatio            float64
+	MedianTimeBlocks       uint32
+	MaxFutureDrift         uint32
+
+	// Workers verifying the agreement messages. Zero falls back to
+	// performance.accumulatorWorkers
+	AccumulatorWorkers uint32
+
+	// Size limits of blocks and txs. Zero values fall back to the limits
+	// of the network, see GetLimits
+	MaxBlockSize uint32
+	MaxTxSize    uint32
+	MaxInputs    uint32
+	MaxOutputs   uint32
+
+	// Heights at which the transaction versions activate, starting from
+	// version 1. Version 0 is always active
+	TxVersionHeights []uint64
+
+	// Consensus rule changes signaled in the version of blocks, and the
+	// amount of blocks in which the signals are tallied. See package
+	// versionbits
+	Deployments     []deploymentConfiguration
+	SignalingWindow uint64
+
+	// Check candidates against the stateless rules of the consensus, on
+	// top of their hash and tx root, before storing and relaying them
+	DeepCandidateValidation bool
+
+	// Share of the recent rounds the node was selected in, in which its
+	// votes must make it into the certificates. The node warns below it.
+	// 0 means 0.9
+	ParticipationThreshold float64
+}
+
+type deploymentConfiguration struct {
+	Name        string
+	Bit         uint8
+	StartHeight uint64
+	Threshold   uint64
 }
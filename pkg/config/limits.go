@@ -0,0 +1,54 @@
+package config
+
+// Limits are the size limits enforced by the consensus on blocks and
+// transactions. Sizes are in bytes, and refer to the marshalled form.
+type Limits struct {
+	MaxBlockSize uint32
+	MaxTxSize    uint32
+	MaxInputs    uint32
+	MaxOutputs   uint32
+}
+
+// testnetLimits are the limits of the testnet, shared by the other networks
+// until they need their own
+var testnetLimits = Limits{
+	// Blocks are relayed in a single frame, which can not exceed
+	// processing.MaxFrameSize (250000 bytes)
+	MaxBlockSize: 200000,
+	MaxTxSize:    64 * 1024,
+	MaxInputs:    128,
+	// A range proof aggregates up to 64 commitments
+	MaxOutputs: 64,
+}
+
+// GetLimits returns the limits of the configured network. Every limit set in
+// the configuration overrides the default one of the network profile.
+func GetLimits() Limits {
+	return Get().Limits()
+}
+
+// Limits returns the limits set in r, falling back to the ones of the
+// profile of its network. An unknown network has no default limits, and is
+// refused by Validate.
+func (r Registry) Limits() Limits {
+	p, _ := LookupProfile(r.General.Network)
+	l := p.Limits
+
+	if r.Consensus.MaxBlockSize != 0 {
+		l.MaxBlockSize = r.Consensus.MaxBlockSize
+	}
+
+	if r.Consensus.MaxTxSize != 0 {
+		l.MaxTxSize = r.Consensus.MaxTxSize
+	}
+
+	if r.Consensus.MaxInputs != 0 {
+		l.MaxInputs = r.Consensus.MaxInputs
+	}
+
+	if r.Consensus.MaxOutputs != 0 {
+		l.MaxOutputs = r.Consensus.MaxOutputs
+	}
+
+	return l
+}
@@ -0,0 +1,34 @@
+package config
+
+import "testing"
+
+func TestGetLimits(t *testing.T) {
+	prev := Get()
+	defer Mock(&prev)
+
+	reg := Registry{}
+	reg.General.Network = "testnet"
+	reg.Consensus.MaxTxSize = 1000
+	Mock(&reg)
+
+	l := GetLimits()
+	if l.MaxTxSize != 1000 {
+		t.Errorf("expected the configured max tx size, got %d", l.MaxTxSize)
+	}
+
+	if l.MaxBlockSize != testnetLimits.MaxBlockSize {
+		t.Errorf("expected the testnet max block size, got %d", l.MaxBlockSize)
+	}
+}
+
+func TestLimitsOfProfiles(t *testing.T) {
+	for _, network := range append(Profiles(), "TestNet") {
+		reg := Registry{}
+		reg.General.Network = network
+
+		l := reg.Limits()
+		if l.MaxBlockSize == 0 || l.MaxTxSize == 0 || l.MaxInputs == 0 || l.MaxOutputs == 0 {
+			t.Errorf("%s: missing limits %+v", network, l)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -19,7 +21,9 @@ const (
 )
 
 var (
-	r *Registry
+	// lock guards r, which is replaced on every reload
+	lock sync.RWMutex
+	r    *Registry
 )
 
 type Base struct {
@@ -44,6 +48,7 @@ type Registry struct {
 	// All configuration groups
 	General     generalConfiguration
 	Database    databaseConfiguration
+	Chain       chainConfiguration
 	Wallet      walletConfiguration
 	Network     networkConfiguration
 	Logger      loggerConfiguration
@@ -53,6 +58,11 @@ type Registry struct {
 	Mempool     mempoolConfiguration
 	Consensus   consensusConfiguration
 	Gql         gqlConfiguration
+	Bridge      bridgeConfiguration
+	Metrics     metricsConfiguration
+	Alert       alertConfiguration
+	Tracing     tracingConfiguration
+	Supervisor  supervisorConfiguration
 }
 
 // Load makes an attempt to read and unmarshal any configs from flag, env and
@@ -69,19 +79,23 @@ type Registry struct {
 // properties config files
 func Load(configFileName string, secondary interface{}, customflags func() (string, error)) error {
 
-	r = new(Registry)
-	r.ConfigFileName = configFileName
+	registry := new(Registry)
+	registry.ConfigFileName = configFileName
 
-	r.loadFlagsFn = loadFlags
+	registry.loadFlagsFn = loadFlags
 	if customflags != nil {
-		r.loadFlagsFn = customflags
+		registry.loadFlagsFn = customflags
 	}
 
 	// Initialization
-	if err := r.init(secondary); err != nil {
+	if err := registry.init(secondary); err != nil {
 		return err
 	}
 
+	lock.Lock()
+	r = registry
+	lock.Unlock()
+
 	// Validation and defaulting should be done by the consumers (packages) as
 	// they will be the best at knowing what they expect
 
@@ -103,6 +117,8 @@ func LoadFromFile(configPath string) (Registry, error) {
 // Get returns registry by value in order to avoid further modifications after
 // initial configuration loading
 func Get() Registry {
+	lock.RLock()
+	defer lock.RUnlock()
 	return *r
 }
 
@@ -137,6 +153,10 @@ func (r *Registry) init(secondary interface{}) error {
 
 	defineENV()
 
+	// The network profile provides the defaults of the settings which
+	// differ from a network to another
+	setProfileDefaults(viper.GetString("general.network"))
+
 	// Uncomment on debugging only. This will list all levels of configurations
 	// viper.Debug()
 
@@ -145,6 +165,10 @@ func (r *Registry) init(secondary interface{}) error {
 		return fmt.Errorf("unable to decode into struct, %v", err)
 	}
 
+	if err := r.resolveSecrets(); err != nil {
+		return fmt.Errorf("unable to resolve secrets, %v", err)
+	}
+
 	// Load secondary registry. This would be useful when new projects derives
 	// from dusk node codebase
 	if secondary != nil {
@@ -170,23 +194,43 @@ func loadFlags() (string, error) {
 	// Define all supported flags.
 	// All flags should be verified `loader_test.go/TestSupportedFlags`
 	defineFlags()
+	defineSettingFlags()
 	configFile := pflag.String("config", "", "Set path to the config file")
+	network := pflag.String("network", "", "select the network profile (one of "+strings.Join(Profiles(), ", ")+"), same as --general.network")
 
 	// Bind all command line parameters to their corresponding file configs
 	//
 	// e.g CLI argument `--logger.level="warn"`` will overwrite the value from
 	// `[logger] level = "info"`` in the loaded config file
-	if err := viper.BindPFlags(pflag.CommandLine); err != nil {
-		return "", fmt.Errorf("unable bind pflags, %v", err)
+	//
+	// --network is not bound, as its name is the one of the [network] section
+	var err error
+	pflag.VisitAll(func(f *pflag.Flag) {
+		if f.Name == "network" || err != nil {
+			return
+		}
+
+		if e := viper.BindPFlag(f.Name, f); e != nil {
+			err = fmt.Errorf("unable bind pflags, %v", e)
+		}
+	})
+	if err != nil {
+		return "", err
 	}
 
 	pflag.Parse()
 
+	if *network != "" {
+		viper.Set("general.network", *network)
+	}
+
 	return *configFile, nil
 }
 
 // define a set of flags as bindings to config file settings
-// The settings that are needed to be passed frequently by CLI should be added here
+// The settings that are needed to be passed frequently by CLI should be added
+// here, with a shorthand. Any other setting can be set with the flag named
+// after its key, see defineSettingFlags
 func defineFlags() {
 	_ = pflag.StringP("logger.level", "l", "", "override logger.level settings in config file")
 	_ = pflag.StringP("general.network", "n", "testnet", "override general.network settings in config file")
@@ -199,31 +243,12 @@ func defineFlags() {
 	_ = pflag.StringP("gql.port", "q", "9500", "sets gql server port")
 }
 
-// define a set of environment variables as bindings to config file settings
-func defineENV() {
-
-	// Bind config key general.network to ENV var DUSK_GENERAL_NETWORK
-	if err := viper.BindEnv("general.network", "DUSK_GENERAL_NETWORK"); err != nil {
-		fmt.Printf("defineENV %v", err)
-	}
-
-	if err := viper.BindEnv("logger.level", "DUSK_LOGGER_LEVEL"); err != nil {
-		fmt.Printf("defineENV %v", err)
-	}
-
-	// DUSK_NETWORK_SEEDER_FIXED could be useful on setting up a local P2P
-	// network by setting a single ENV with array of addresses
-	//
-	// export DUSK_NETWORK_SEEDER_FIXED="localhost:7000, localhost:7001, localhost:7002"
-	if err := viper.BindEnv("network.seeder.fixed", "DUSK_NETWORK_SEEDER_FIXED"); err != nil {
-		fmt.Printf("defineENV %v", err)
-	}
-}
-
 // Mock should be used only in test packages. It could be useful when a unit
 // test needs to be rerun with configs different from the default ones.
 func Mock(m *Registry) {
+	lock.Lock()
 	r = m
+	lock.Unlock()
 }
 
 func init() {
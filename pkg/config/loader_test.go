@@ -104,6 +104,56 @@ func TestSupportedEnv(t *testing.T) {
 	}
 }
 
+// TestSettingOverrides ensures that any setting can be overridden by its ENV
+// variable and by its flag, with the flag taking precedence
+func TestSettingOverrides(t *testing.T) {
+
+	Reset()
+	defer os.Unsetenv("DUSK_MEMPOOL_MAXSIZEMB")
+	defer os.Unsetenv("DUSK_RPC_CACHETTL")
+	defer os.Unsetenv("DUSK_CONSENSUS_TXVERSIONHEIGHTS")
+
+	// Mock command line arguments
+	os.Args = append(os.Args, defaultDuskConfig)
+	os.Args = append(os.Args, "--rpc.cachettl=5")
+	os.Args = append(os.Args, "--rpc.enabled=false")
+	os.Args = append(os.Args, "--bridge.topics=tx,roundupdate")
+
+	os.Setenv("DUSK_MEMPOOL_MAXSIZEMB", "200")
+	os.Setenv("DUSK_RPC_CACHETTL", "20")
+	os.Setenv("DUSK_CONSENSUS_TXVERSIONHEIGHTS", "10,20")
+
+	// This relies on default.dusk.toml
+	if err := Load("default.dusk", nil, nil); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+
+	if Get().Mempool.MaxSizeMB != 200 {
+		t.Errorf("Invalid ENV value: %d", Get().Mempool.MaxSizeMB)
+	}
+
+	if Get().RPC.CacheTTL != 5 {
+		t.Errorf("Invalid flag value: %d", Get().RPC.CacheTTL)
+	}
+
+	if Get().RPC.Enabled {
+		t.Error("Invalid flag value: rpc enabled")
+	}
+
+	if len(Get().Bridge.Topics) != 2 || Get().Bridge.Topics[1] != "roundupdate" {
+		t.Errorf("Invalid flag value: %v", Get().Bridge.Topics)
+	}
+
+	if len(Get().Consensus.TxVersionHeights) != 2 || Get().Consensus.TxVersionHeights[1] != 20 {
+		t.Errorf("Invalid ENV value: %v", Get().Consensus.TxVersionHeights)
+	}
+
+	// Settings not overridden are read from the config file
+	if Get().Mempool.PoolType != "hashmap" {
+		t.Errorf("Invalid config value: %s", Get().Mempool.PoolType)
+	}
+}
+
 func TestReadOnly(t *testing.T) {
 
 	Reset()
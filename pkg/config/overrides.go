@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// envPrefix is the prefix of the environment variables overriding the
+// settings, e.g DUSK_MEMPOOL_MAXSIZEMB overrides mempool.maxSizeMB
+const envPrefix = "DUSK_"
+
+// setting is a setting of the Registry which can be overridden
+type setting struct {
+	key  string
+	kind reflect.Type
+}
+
+// settings lists every setting of the Registry, by its lower case key as in
+// "mempool.maxsizemb". Tables, such as logger.levels, and lists of sections,
+// such as consensus.deployments, can only be set in the config file.
+func settings() []setting {
+	var list []setting
+	t := reflect.TypeOf(Registry{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			continue
+		}
+		list = appendSettings(list, strings.ToLower(field.Name), field.Type)
+	}
+	return list
+}
+
+func appendSettings(list []setting, key string, t reflect.Type) []setting {
+	switch {
+	case t.Kind() == reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			list = appendSettings(list, key+"."+strings.ToLower(field.Name), field.Type)
+		}
+	case t.Kind() == reflect.Map:
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct:
+	default:
+		list = append(list, setting{key, t})
+	}
+	return list
+}
+
+// envName returns the environment variable overriding key
+func envName(key string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(key, ".", "_", -1))
+}
+
+// defineSettingFlags defines a flag for every setting not bound yet by
+// defineFlags, named after its key
+func defineSettingFlags() {
+	for _, s := range settings() {
+		if pflag.Lookup(s.key) != nil {
+			continue
+		}
+
+		usage := fmt.Sprintf("override %s settings in config file", s.key)
+		switch {
+		case s.kind.Kind() == reflect.Bool:
+			_ = pflag.Bool(s.key, false, usage)
+		case s.kind == reflect.TypeOf([]string{}):
+			_ = pflag.StringSlice(s.key, nil, usage)
+		default:
+			// Lists of numbers are comma separated
+			_ = pflag.String(s.key, "", usage)
+		}
+	}
+}
+
+// defineENV binds every setting to the environment variable named after its
+// key, e.g general.network to DUSK_GENERAL_NETWORK.
+//
+// DUSK_NETWORK_SEEDER_FIXED could be useful on setting up a local P2P network
+// by setting a single ENV with array of addresses
+//
+// export DUSK_NETWORK_SEEDER_FIXED="localhost:7000, localhost:7001, localhost:7002"
+func defineENV() {
+	for _, s := range settings() {
+		if err := viper.BindEnv(s.key, envName(s.key)); err != nil {
+			fmt.Printf("defineENV %v", err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Profile bundles what differs from a network to another. It is selected by
+// general.network, set with `--network` on the command line.
+//
+// The magic and the genesis block define the network, and can not be
+// changed. The other settings of the profile are defaults, overridden by the
+// config file, the ENV and the flags.
+type Profile struct {
+	// Name of the magic of the network, see protocol.Magic
+	Magic string
+	// Hex encoded genesis block. Empty if the network is not launched yet
+	Genesis string
+
+	// Defaults of network.seeder.addresses and network.seeder.fixed
+	Seeders []string
+	Fixed   []string
+	// Default of consensus.stepTimeout
+	StepTimeout time.Duration
+	// Defaults of database.dir and wallet.store
+	DatabaseDir string
+	WalletStore string
+	// Size limits of blocks and transactions, see GetLimits
+	Limits Limits
+
+	// Stake parameters of the consensus, see ConsensusParams. They can not
+	// be changed once the network is launched, as the committees of its
+	// chain depend on them
+	StakeMaturity      uint64
+	ActiveStakesHeight uint64
+	// Height from which bids, stakes and timelocks must lock their outputs,
+	// see ConsensusParams
+	LockTimeHeight uint64
+
+	// Committee sizes, quorum ratio and block timestamp rules of the
+	// consensus, see ConsensusParams. Every node of the network must share
+	// them, so they can not be configured locally. Zero values fall back to
+	// the defaults
+	ReductionCommitteeSize int
+	AgreementCommitteeSize int
+	QuorumRatio            float64
+	MedianTimeBlocks       int
+	MaxFutureDrift         time.Duration
+}
+
+var profilesMu sync.RWMutex
+
+var profiles = map[string]Profile{
+	"mainnet": {
+		Magic:       "mainnet",
+		Seeders:     []string{"voucher.dusk.network:8081"},
+		StepTimeout: ConsensusTimeOut,
+		DatabaseDir: "chain",
+		WalletStore: "walletDB",
+		Limits:      testnetLimits,
+
+		StakeMaturity:      MinStakeMaturity,
+		ActiveStakesHeight: 0,
+		LockTimeHeight:     0,
+	},
+	"testnet": {
+		Magic:       "testnet",
+		Genesis:     TestNetGenesisBlob,
+		Seeders:     []string{"voucher.dusk.network:8081"},
+		StepTimeout: ConsensusTimeOut,
+		DatabaseDir: "chain",
+		WalletStore: "walletDB",
+		Limits:      testnetLimits,
+
+		// The chain is running: the active stakes and the lock times are
+		// only enforced once an activation height is scheduled
+		StakeMaturity:      MinStakeMaturity,
+		ActiveStakesHeight: math.MaxUint64,
+		LockTimeHeight:     math.MaxUint64,
+	},
+	"devnet": {
+		Magic:       "devnet",
+		Genesis:     TestNetGenesisBlob,
+		StepTimeout: ConsensusTimeOut,
+		DatabaseDir: "devnet/chain",
+		WalletStore: "devnet/walletDB",
+		Limits:      testnetLimits,
+
+		StakeMaturity:      MinStakeMaturity,
+		ActiveStakesHeight: math.MaxUint64,
+		LockTimeHeight:     math.MaxUint64,
+	},
+	// A P2P network of three nodes on the local host, listening on ports
+	// 7000 to 7002
+	"localnet": {
+		Magic:       "devnet",
+		Genesis:     TestNetGenesisBlob,
+		Fixed:       []string{"localhost:7000", "localhost:7001", "localhost:7002"},
+		StepTimeout: 2 * time.Second,
+		DatabaseDir: "localnet/chain",
+		WalletStore: "localnet/walletDB",
+		Limits:      testnetLimits,
+
+		StakeMaturity:      MinStakeMaturity,
+		ActiveStakesHeight: 0,
+		LockTimeHeight:     0,
+	},
+}
+
+// Profiles returns the names of the bundled network profiles
+func Profiles() []string {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupProfile returns the profile of the network name
+func LookupProfile(name string) (Profile, bool) {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+
+	p, ok := profiles[strings.ToLower(name)]
+	return p, ok
+}
+
+// RegisterProfile sets the profile of the network name, replacing the bundled
+// one if any. It is meant for the tools running their own networks, such as
+// the devnet.
+func RegisterProfile(name string, p Profile) {
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+
+	profiles[strings.ToLower(name)] = p
+}
+
+// GetProfile returns the profile of the configured network. The profile of
+// an unknown network is empty.
+func GetProfile() Profile {
+	p, _ := LookupProfile(Get().General.Network)
+	return p
+}
+
+// setProfileDefaults sets the settings of the profile of the network as the
+// viper defaults
+func setProfileDefaults(network string) {
+	p, ok := LookupProfile(network)
+	if !ok {
+		return
+	}
+
+	viper.SetDefault("network.seeder.addresses", p.Seeders)
+	viper.SetDefault("network.seeder.fixed", p.Fixed)
+	viper.SetDefault("consensus.steptimeout", uint32(p.StepTimeout/time.Millisecond))
+	viper.SetDefault("database.dir", p.DatabaseDir)
+	viper.SetDefault("wallet.store", p.WalletStore)
+}
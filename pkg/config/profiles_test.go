@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// TestNetworkProfile ensures that the --network switch selects the profile
+// providing the defaults, and that they can still be overridden
+func TestNetworkProfile(t *testing.T) {
+
+	Reset()
+
+	// Mock command line arguments
+	os.Args = append(os.Args, defaultDuskConfig)
+	os.Args = append(os.Args, "--network=localnet")
+	os.Args = append(os.Args, "--wallet.store=custom")
+
+	// This relies on default.dusk.toml
+	if err := Load("default.dusk", nil, nil); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+
+	if Get().General.Network != "localnet" {
+		t.Errorf("Invalid network %s", Get().General.Network)
+	}
+
+	if GetProfile().Magic != "devnet" {
+		t.Errorf("Invalid magic %s", GetProfile().Magic)
+	}
+
+	if Get().Database.Dir != "localnet/chain" {
+		t.Errorf("Invalid database dir %s", Get().Database.Dir)
+	}
+
+	if len(Get().Network.Seeder.Fixed) != 3 {
+		t.Errorf("Invalid fixed seeders %v", Get().Network.Seeder.Fixed)
+	}
+
+	if GetConsensusParams().StepTimeout != 2*time.Second {
+		t.Errorf("Invalid step timeout %v", GetConsensusParams().StepTimeout)
+	}
+
+	// Overridden by the flag
+	if Get().Wallet.Store != "custom" {
+		t.Errorf("Invalid wallet store %s", Get().Wallet.Store)
+	}
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Secrets can be kept out of the config file, so that it can be shared
+// without leaking credentials. A secret setting can be set to a reference
+// instead of its value:
+//   - "file:<path>" reads the secret from the file at path, which must not be
+//     accessible to the group nor to the others (e.g mode 0600)
+//   - "env:<name>" reads the secret from the environment variable name
+const (
+	secretFilePrefix = "file:"
+	secretEnvPrefix  = "env:"
+)
+
+type secret struct {
+	key   string
+	value *string
+}
+
+// secrets lists the secret settings of r
+func (r *Registry) secrets() []secret {
+	return []secret{
+		{"rpc.pass", &r.RPC.Pass},
+		{"rpc.token", &r.RPC.Token},
+		{"gql.pass", &r.Gql.Pass},
+		{"wallet.password", &r.Wallet.Password},
+		{"logger.crash.upload", &r.Logger.Crash.Upload},
+		{"alert.webhook", &r.Alert.Webhook},
+	}
+}
+
+// keyFiles lists the settings holding the path of a private key, which is
+// held to the same permissions as the secret files
+func (r *Registry) keyFiles() []secret {
+	return []secret{
+		{"rpc.keyFile", &r.RPC.KeyFile},
+	}
+}
+
+// redacted replaces the secrets set, in the output of Redacted
+const redacted = "[redacted]"
+
+// Redacted returns a copy of r with the secrets replaced, so that it can be
+// shown to the operators
+func (r Registry) Redacted() Registry {
+	for _, s := range r.secrets() {
+		if *s.value != "" {
+			*s.value = redacted
+		}
+	}
+	return r
+}
+
+// resolveSecrets replaces the references to secrets with their value, and
+// checks the permissions of the files holding secrets
+func (r *Registry) resolveSecrets() error {
+	for _, s := range r.secrets() {
+		value, err := resolveSecret(*s.value)
+		if err != nil {
+			return fmt.Errorf("%s: %v", s.key, err)
+		}
+		*s.value = value
+	}
+
+	for _, s := range r.keyFiles() {
+		if *s.value == "" {
+			continue
+		}
+
+		if err := checkSecretFile(*s.value); err != nil {
+			return fmt.Errorf("%s: %v", s.key, err)
+		}
+	}
+
+	return nil
+}
+
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, secretFilePrefix):
+		path := strings.TrimPrefix(value, secretFilePrefix)
+		if err := checkSecretFile(path); err != nil {
+			return "", err
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		// Editors usually end files with a new line
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case strings.HasPrefix(value, secretEnvPrefix):
+		name := strings.TrimPrefix(value, secretEnvPrefix)
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s not set", name)
+		}
+		return secret, nil
+	default:
+		return value, nil
+	}
+}
+
+// checkSecretFile checks that the file at path can only be accessed by its
+// owner. Windows does not support the permission bits, so it is not checked
+// there.
+func checkSecretFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("permissions %#o of %s are too open, it must not be accessible to the group nor to the others", info.Mode().Perm(), path)
+	}
+
+	return nil
+}
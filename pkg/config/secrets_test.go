@@ -0,0 +1,91 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dusk-secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(path, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("DUSK_TEST_PASSWORD", "hunter2")
+	defer os.Unsetenv("DUSK_TEST_PASSWORD")
+
+	var r Registry
+	r.RPC.Pass = "inline"
+	r.RPC.Token = "file:" + path
+	r.Wallet.Password = "env:DUSK_TEST_PASSWORD"
+	if err := r.resolveSecrets(); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.RPC.Pass != "inline" {
+		t.Errorf("Invalid inline secret %s", r.RPC.Pass)
+	}
+
+	if r.RPC.Token != "s3cr3t" {
+		t.Errorf("Invalid file secret %s", r.RPC.Token)
+	}
+
+	if r.Wallet.Password != "hunter2" {
+		t.Errorf("Invalid ENV secret %s", r.Wallet.Password)
+	}
+
+	// Unset variables are refused
+	r.Gql.Pass = "env:DUSK_TEST_UNSET"
+	if err := r.resolveSecrets(); err == nil {
+		t.Error("unset ENV secret accepted")
+	}
+	r.Gql.Pass = ""
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	// Files readable by others are refused
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r.RPC.Token = "file:" + path
+	if err := r.resolveSecrets(); err == nil {
+		t.Error("secret file readable by others accepted")
+	}
+
+	r.RPC.Token = ""
+	r.RPC.KeyFile = path
+	if err := r.resolveSecrets(); err == nil {
+		t.Error("key file readable by others accepted")
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	var r Registry
+	r.RPC.Token = "s3cr3t"
+	r.Alert.Webhook = "https://hooks.example.com/s3cr3t"
+	r.Logger.Crash.Upload = "https://crash.example.com/?token=s3cr3t"
+
+	redactedReg := r.Redacted()
+	for _, s := range redactedReg.secrets() {
+		if *s.value != "" && *s.value != redacted {
+			t.Errorf("secret %s not redacted", s.key)
+		}
+	}
+
+	// The original is left untouched
+	if r.Alert.Webhook != "https://hooks.example.com/s3cr3t" {
+		t.Error("original config redacted")
+	}
+}
@@ -0,0 +1,306 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidPort is returned for ports out of range, or used twice
+	ErrInvalidPort = errors.New("invalid port")
+	// ErrNotWritable is returned for directories the node can not write to
+	ErrNotWritable = errors.New("directory not writable")
+	// ErrUnknownDriver is returned for database drivers not registered
+	ErrUnknownDriver = errors.New("unknown database driver")
+	// ErrUnknownNetwork is returned for networks without a magic
+	ErrUnknownNetwork = errors.New("unknown network")
+	// ErrNetworkMismatch is returned for settings not supported on the
+	// configured network
+	ErrNetworkMismatch = errors.New("not supported on this network")
+	// ErrInvalidTimeout is returned for durations which can not work
+	ErrInvalidTimeout = errors.New("invalid timeout")
+	// ErrOutOfRange is returned for values outside of the range supported
+	ErrOutOfRange = errors.New("out of range")
+	// ErrNotConfigurable is returned for settings fixed by the network
+	ErrNotConfigurable = errors.New("not configurable")
+)
+
+// ValidationError is a problem found with a setting. Err is one of the
+// errors above, so that callers can tell problems apart with errors.Is.
+type ValidationError struct {
+	Setting string
+	Value   interface{}
+	Err     error
+	Reason  string
+}
+
+func (e *ValidationError) Error() string {
+	s := fmt.Sprintf("%s = %v: %v", e.Setting, e.Value, e.Err)
+	if e.Reason != "" {
+		s += " (" + e.Reason + ")"
+	}
+	return s
+}
+
+// Unwrap returns the kind of the problem
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors are all the problems found with a Registry
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	lines := make([]string, 0, len(e)+1)
+	lines = append(lines, fmt.Sprintf("invalid configuration, %d problem(s) found:", len(e)))
+	for _, err := range e {
+		lines = append(lines, "  - "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Known lists what the settings of the Registry can refer to. Those are
+// registered outside of this package, which can not import them.
+type Known struct {
+	// Names of the registered database drivers
+	Drivers []string
+	// Names of the networks with a magic
+	Networks []string
+}
+
+// Validate checks the Registry for settings which would prevent the node
+// from working, so that it can refuse to start rather than failing deep
+// inside a subsystem. All the problems found are returned at once, as
+// ValidationErrors.
+func (r Registry) Validate(known Known) error {
+	var errs ValidationErrors
+	add := func(setting string, value interface{}, err error, reason string) {
+		errs = append(errs, &ValidationError{setting, value, err, reason})
+	}
+
+	// Ports
+	ports := make(map[uint64]string)
+	checkPort := func(setting, value string) {
+		port, err := strconv.ParseUint(value, 10, 16)
+		if err != nil || port == 0 {
+			add(setting, value, ErrInvalidPort, "must be within 1 and 65535")
+			return
+		}
+
+		if other, ok := ports[port]; ok {
+			add(setting, value, ErrInvalidPort, "already used by "+other)
+			return
+		}
+		ports[port] = setting
+	}
+
+	checkPort("network.port", r.Network.Port)
+	if r.RPC.Enabled && strings.HasPrefix(r.RPC.Network, "tcp") {
+		_, port, err := net.SplitHostPort(r.RPC.Address)
+		if err != nil {
+			add("rpc.address", r.RPC.Address, ErrInvalidPort, err.Error())
+		} else {
+			checkPort("rpc.address", port)
+		}
+	}
+
+	if r.Gql.Enabled {
+		checkPort("gql.port", r.Gql.Port)
+	}
+
+	if r.Metrics.Enabled {
+		_, port, err := net.SplitHostPort(r.Metrics.Address)
+		if err != nil {
+			add("metrics.address", r.Metrics.Address, ErrInvalidPort, err.Error())
+		} else {
+			checkPort("metrics.address", port)
+		}
+
+		if path := r.Metrics.Path; path != "" && !strings.HasPrefix(path, "/") {
+			add("metrics.path", path, ErrOutOfRange, "must start with /")
+		}
+	}
+
+	if r.Prof.Address != "" {
+		_, port, err := net.SplitHostPort(r.Prof.Address)
+		if err != nil {
+			add("prof.address", r.Prof.Address, ErrInvalidPort, err.Error())
+		} else {
+			checkPort("prof.address", port)
+		}
+	}
+
+	// Directories
+	if err := checkWritable(r.Database.Dir); err != nil {
+		add("database.dir", r.Database.Dir, ErrNotWritable, err.Error())
+	}
+
+	if err := checkWritable(r.Wallet.Store); err != nil {
+		add("wallet.store", r.Wallet.Store, ErrNotWritable, err.Error())
+	}
+
+	// Driver and network
+	if !contains(known.Drivers, r.Database.Driver) {
+		add("database.driver", r.Database.Driver, ErrUnknownDriver, "registered drivers are "+strings.Join(known.Drivers, ", "))
+	}
+
+	profile, ok := LookupProfile(r.General.Network)
+	if !ok {
+		add("general.network", r.General.Network, ErrUnknownNetwork, "known networks are "+strings.Join(Profiles(), ", "))
+	} else if !contains(known.Networks, profile.Magic) {
+		add("general.network", r.General.Network, ErrUnknownNetwork, "no magic for "+profile.Magic)
+	}
+
+	if len(r.Network.Seeder.Fixed) > 0 && profile.Magic == "mainnet" {
+		add("network.seeder.fixed", r.Network.Seeder.Fixed, ErrNetworkMismatch, "fixed seeders are not supported on mainnet")
+	}
+
+	// Consensus
+	params := r.ConsensusParams()
+	if params.MaxStepTimeout != 0 && params.MaxStepTimeout < params.StepTimeout {
+		add("consensus.maxStepTimeout", r.Consensus.MaxStepTimeout, ErrInvalidTimeout, "must not be shorter than consensus.stepTimeout")
+	}
+
+	// Every node of a network must agree on the committees and the block
+	// timestamps, so those are only set by the network profile
+	fixed := []struct {
+		setting string
+		value   interface{}
+		set     bool
+	}{
+		{"consensus.reductionCommitteeSize", r.Consensus.ReductionCommitteeSize, r.Consensus.ReductionCommitteeSize != 0},
+		{"consensus.agreementCommitteeSize", r.Consensus.AgreementCommitteeSize, r.Consensus.AgreementCommitteeSize != 0},
+		{"consensus.quorumRatio", r.Consensus.QuorumRatio, r.Consensus.QuorumRatio != 0},
+		{"consensus.medianTimeBlocks", r.Consensus.MedianTimeBlocks, r.Consensus.MedianTimeBlocks != 0},
+		{"consensus.maxFutureDrift", r.Consensus.MaxFutureDrift, r.Consensus.MaxFutureDrift != 0},
+	}
+
+	for _, f := range fixed {
+		if f.set {
+			add(f.setting, f.value, ErrNotConfigurable, "set by the network profile")
+		}
+	}
+
+	if threshold := r.Consensus.ParticipationThreshold; threshold < 0 || threshold > 1 {
+		add("consensus.participationThreshold", threshold, ErrOutOfRange, "must be within 0 and 1")
+	}
+
+	// Chain
+	if r.Chain.Checkpoint != "" {
+		if _, _, err := ParseCheckpoint(r.Chain.Checkpoint); err != nil {
+			add("chain.checkpoint", r.Chain.Checkpoint, ErrOutOfRange, err.Error())
+		}
+	} else if r.Chain.Snapshot != "" {
+		add("chain.snapshot", r.Chain.Snapshot, ErrOutOfRange, "needs the checkpoint of its tip")
+	}
+
+	// Logging
+	if format := r.Logger.Format; format != "" && format != "text" && format != "json" {
+		add("logger.format", format, ErrOutOfRange, "must be text or json")
+	}
+
+	if upload := r.Logger.Crash.Upload; upload != "" {
+		if u, err := url.Parse(upload); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			add("logger.crash.upload", upload, ErrOutOfRange, "must be an http(s) URL")
+		}
+	}
+
+	if r.Logger.Crash.Dir != "" {
+		if err := checkWritable(r.Logger.Crash.Dir); err != nil {
+			add("logger.crash.dir", r.Logger.Crash.Dir, ErrNotWritable, err.Error())
+		}
+	}
+
+	// Alerts
+	if webhook := r.Alert.Webhook; r.Alert.Enabled && webhook != "" {
+		if u, err := url.Parse(webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			add("alert.webhook", webhook, ErrOutOfRange, "must be an http(s) URL")
+		}
+	}
+
+	if free := r.Alert.MinDiskFree; free < 0 || free >= 100 {
+		add("alert.minDiskFree", free, ErrOutOfRange, "must be a percentage below 100")
+	}
+
+	// Tracing
+	if endpoint := r.Tracing.Endpoint; r.Tracing.Enabled {
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			add("tracing.endpoint", endpoint, ErrOutOfRange, "must be an http(s) URL")
+		}
+	}
+
+	// Timeouts
+	stall := time.Duration(r.RPC.StallTimeout) * time.Second
+	if stall != 0 && stall <= params.StepTimeout {
+		add("rpc.stallTimeout", r.RPC.StallTimeout, ErrInvalidTimeout, "a block takes longer than "+params.StepTimeout.String())
+	}
+
+	republish := r.Network.Republish
+	window := time.Duration(republish.Window) * time.Second
+	if window == 0 {
+		window = time.Minute
+	}
+
+	if time.Duration(republish.Jitter)*time.Millisecond >= window {
+		add("network.republish.jitter", republish.Jitter, ErrInvalidTimeout, "must be shorter than the republish window")
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// checkWritable checks that dir can be written to. If dir does not exist
+// yet, its closest existing parent must be writable.
+func checkWritable(dir string) error {
+	if dir == "" {
+		return errors.New("not set")
+	}
+
+	for {
+		info, err := os.Stat(dir)
+		if os.IsNotExist(err) {
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				return err
+			}
+			dir = parent
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", dir)
+		}
+		break
+	}
+
+	f, err := ioutil.TempFile(dir, ".dusk-write-check")
+	if err != nil {
+		return err
+	}
+
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var known = Known{
+	Drivers:  []string{"heavy_v0.1.0"},
+	Networks: []string{"mainnet", "testnet", "devnet"},
+}
+
+func validRegistry(dir string) Registry {
+	var r Registry
+	r.General.Network = "testnet"
+	r.Network.Port = "7000"
+	r.RPC.Enabled = true
+	r.RPC.Network = "tcp"
+	r.RPC.Address = "127.0.0.1:9000"
+	r.RPC.StallTimeout = 300
+	r.Gql.Enabled = true
+	r.Gql.Port = "9001"
+	r.Database.Driver = "heavy_v0.1.0"
+	r.Database.Dir = filepath.Join(dir, "chain")
+	r.Wallet.Store = filepath.Join(dir, "walletDB")
+	return r
+}
+
+func TestValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dusk-validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := validRegistry(dir)
+	if err := r.Validate(known); err != nil {
+		t.Fatalf("valid registry refused: %v", err)
+	}
+
+	// A file is not a directory
+	file := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r.Network.Port = "70000"
+	r.Gql.Port = "9000"
+	r.Database.Dir = file
+	r.Database.Driver = "unknown"
+	r.General.Network = "mainnet"
+	r.Network.Seeder.Fixed = []string{"localhost:7000"}
+	r.RPC.StallTimeout = 1
+	r.Network.Republish.Jitter = 60000
+	r.Consensus.StepTimeout = 10000
+	r.Consensus.MaxStepTimeout = 5000
+	r.Consensus.AgreementCommitteeSize = 100
+	r.Consensus.QuorumRatio = 0.5
+	r.Consensus.ParticipationThreshold = 1.5
+	r.Chain.Checkpoint = "12:nothex"
+	r.Alert.Enabled = true
+	r.Alert.Webhook = "ftp://alerts"
+	r.Tracing.Enabled = true
+	r.Tracing.Endpoint = "localhost:4318"
+
+	// No magic for the mainnet
+	known := Known{known.Drivers, []string{"testnet", "devnet"}}
+	err = r.Validate(known)
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// All the problems are reported at once
+	expected := []error{
+		ErrInvalidPort,
+		ErrInvalidPort,
+		ErrNotWritable,
+		ErrUnknownDriver,
+		ErrUnknownNetwork,
+		ErrNetworkMismatch,
+		ErrInvalidTimeout,
+		ErrNotConfigurable,
+		ErrNotConfigurable,
+		ErrOutOfRange,
+		ErrOutOfRange,
+		ErrOutOfRange,
+		ErrOutOfRange,
+		ErrInvalidTimeout,
+		ErrInvalidTimeout,
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d problems, got %v", len(expected), err)
+	}
+
+	for i, e := range errs {
+		if !errors.Is(e, expected[i]) {
+			t.Errorf("expected %v, got %v", expected[i], e)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "config")
+
+// live are the settings which can be changed without restarting the node,
+// marked with #live# in default.dusk.toml. They are either read from the
+// registry on every use, or applied by a subscriber of their section.
+var live = map[string]bool{
+	"logger.level":             true,
+	"logger.levels":            true,
+	"logger.tracesampling":     true,
+	"network.banthreshold":     true,
+	"network.maxinboundpeers":  true,
+	"consensus.steptimeout":    true,
+	"consensus.maxsteptimeout": true,
+	"mempool.maxsizemb":        true,
+	"mempool.maxinvitems":      true,
+	"mempool.minrelayfee":      true,
+	"rpc.stalltimeout":         true,
+	"rpc.slowcallthreshold":    true,
+}
+
+// watchInterval is the interval at which the config file is checked for
+// changes
+const watchInterval = 2 * time.Second
+
+var subscribers = struct {
+	sync.Mutex
+	cbs map[string][]func(Registry)
+}{cbs: make(map[string][]func(Registry))}
+
+// Subscribe registers cb to be called with the new registry whenever a
+// reload changes a live setting of section, e.g "logger" or "mempool".
+func Subscribe(section string, cb func(Registry)) {
+	subscribers.Lock()
+	defer subscribers.Unlock()
+	section = strings.ToLower(section)
+	subscribers.cbs[section] = append(subscribers.cbs[section], cb)
+}
+
+// Reload reads the config file again and applies the changes made to the
+// live settings. Changes to any other setting are refused, as they require a
+// restart, and the current value is kept.
+func Reload() error {
+	cur := Get()
+	next, err := LoadFromFile(cur.UsedConfigFile)
+	if err != nil {
+		return err
+	}
+
+	sections, refused := merge(&cur, &next)
+	for _, key := range refused {
+		lg.WithField("setting", key).Warnln("setting can not be changed without a restart, change refused")
+	}
+
+	if len(sections) == 0 {
+		return nil
+	}
+
+	lock.Lock()
+	r = &cur
+	lock.Unlock()
+
+	subscribers.Lock()
+	var cbs []func(Registry)
+	for _, section := range sections {
+		lg.WithField("section", section).Infoln("configuration reloaded")
+		cbs = append(cbs, subscribers.cbs[section]...)
+	}
+	subscribers.Unlock()
+
+	for _, cb := range cbs {
+		cb(cur)
+	}
+	return nil
+}
+
+// Watch reloads the configuration whenever the config file is modified, or
+// the process receives a SIGHUP.
+func Watch() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		path := Get().UsedConfigFile
+		modTime := fileModTime(path)
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-hup:
+			case <-ticker.C:
+				t := fileModTime(path)
+				if t.Equal(modTime) {
+					continue
+				}
+				modTime = t
+			}
+
+			if err := Reload(); err != nil {
+				lg.WithError(err).Errorln("could not reload configuration")
+			}
+		}
+	}()
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// merge copies the live settings of next which differ into cur. It returns
+// the sections of the settings copied, and the settings which differ but are
+// not live.
+func merge(cur, next *Registry) (sections []string, refused []string) {
+	c, n := reflect.ValueOf(cur).Elem(), reflect.ValueOf(next).Elem()
+	for i := 0; i < c.NumField(); i++ {
+		field := c.Type().Field(i)
+		if field.Anonymous {
+			// Base describes the loading, not the settings
+			continue
+		}
+
+		section := strings.ToLower(field.Name)
+		changed, ref := mergeValue(section, c.Field(i), n.Field(i))
+		if changed {
+			sections = append(sections, section)
+		}
+		refused = append(refused, ref...)
+	}
+
+	return sections, refused
+}
+
+func mergeValue(key string, c, n reflect.Value) (changed bool, refused []string) {
+	if c.Kind() == reflect.Struct {
+		for i := 0; i < c.NumField(); i++ {
+			k := key + "." + strings.ToLower(c.Type().Field(i).Name)
+			ch, ref := mergeValue(k, c.Field(i), n.Field(i))
+			changed = changed || ch
+			refused = append(refused, ref...)
+		}
+		return changed, refused
+	}
+
+	if reflect.DeepEqual(c.Interface(), n.Interface()) {
+		return false, nil
+	}
+
+	if !live[key] {
+		return false, []string{key}
+	}
+
+	c.Set(n)
+	return true, nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Test that only the live settings are changed on reload, and that the
+// subscribers of their section are notified.
+func TestReload(t *testing.T) {
+	Reset()
+
+	content, err := ioutil.ReadFile("./samples/default.dusk.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ioutil.TempFile("", "dusk*.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := ioutil.WriteFile(f.Name(), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := func() (string, error) { return f.Name(), nil }
+	if err := Load("default.dusk", nil, flags); err != nil {
+		t.Fatalf("Failed parse: %v", err)
+	}
+
+	var notified []string
+	Subscribe("logger", func(r Registry) { notified = append(notified, r.Logger.Level) })
+	Subscribe("network", func(r Registry) { t.Error("network section notified") })
+
+	modified := strings.Replace(string(content), `level = "trace"`, `level = "warn"`, 1)
+	modified = strings.Replace(modified, "port=7000", "port=7001", 1)
+	if err := ioutil.WriteFile(f.Name(), []byte(modified), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if Get().Logger.Level != "warn" {
+		t.Errorf("Invalid logger level %s", Get().Logger.Level)
+	}
+
+	// The port can not change without a restart
+	if Get().Network.Port != "7000" {
+		t.Errorf("Invalid network port %s", Get().Network.Port)
+	}
+
+	if len(notified) != 1 || notified[0] != "warn" {
+		t.Errorf("Invalid notifications %v", notified)
+	}
+}
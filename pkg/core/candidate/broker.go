@@ -3,8 +3,10 @@ package candidate
 import (
 	"bytes"
 	"errors"
+	"sync"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -12,8 +14,11 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/republisher"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/dusk-network/dusk-wallet/block"
+	log "github.com/sirupsen/logrus"
 )
 
+var lg = log.WithField("process", "candidate broker")
+
 // Broker is the entry point for the candidate component. It manages
 // an in-memory store of `Candidate` messages, and allows for the
 // fetching of these messages through the `RPCBus`. It listens
@@ -22,36 +27,95 @@ import (
 // message, the Broker can make a `GetCandidate` request to the rest
 // of the network, and will attempt to provide the requesting component
 // with it's needed `Candidate`.
+//
+// Requests coming from peers are served through `GetLocalCandidate`,
+// which only looks up the store. Otherwise, a node missing the candidate
+// would forward the request to its own peers, and answer well after the
+// requesting node gave up.
+//
+// Candidates of a past round are refused, and so are the candidates above the
+// caps of their round or of their generator, identified by the Z of its Score
+// message. The refused candidates are counted, and the counters are served
+// through `GetCandidateStats`.
+//
+// The candidate generated by this node is kept until the end of the round,
+// and gossiped again on every `Restart` of the consensus, so that nodes
+// which joined late can still vote on it.
 type Broker struct {
 	publisher   eventbus.Publisher
+	subscriber  eventbus.Subscriber
 	republisher *republisher.Republisher
 	*store
-	// List of block hashes for which a valid Score message was seen.
-	validHashes map[string]struct{}
+	// List of block hashes for which a valid Score message was seen, with
+	// the details of the Score message.
+	validHashes map[string]validScore
+
+	acceptedBlockChan     <-chan block.Block
+	candidateChan         <-chan Candidate
+	getCandidateChan      <-chan rpcbus.Request
+	getLocalCandidateChan <-chan rpcbus.Request
+	getStatsChan          <-chan rpcbus.Request
+	generatedChan         <-chan Candidate
+	restartChan           <-chan bytes.Buffer
 
-	acceptedBlockChan <-chan block.Block
-	candidateChan     <-chan Candidate
-	getCandidateChan  <-chan rpcbus.Request
+	// candidate generated by this node for the current round
+	own *Candidate
+
+	// ids of the event bus subscriptions, removed on Close, which closes
+	// quit to stop Listen
+	subscriptions map[topics.Topic]uint32
+	quit          chan struct{}
+	closeOnce     sync.Once
 }
 
+// ErrCandidateNotFound is returned to peers requesting a candidate which is
+// not in the store
+var ErrCandidateNotFound = errors.New("candidate not found")
+
 // NewBroker returns an initialized Broker struct. It will still need
 // to be started by calling `Listen`.
 func NewBroker(broker eventbus.Broker, rpcBus *rpcbus.RPCBus) *Broker {
-	acceptedBlockChan, _ := consensus.InitAcceptedBlockUpdate(broker)
+	subscriptions := make(map[topics.Topic]uint32)
+	acceptedBlockChan, acceptedBlockID := consensus.InitAcceptedBlockUpdate(broker)
+	subscriptions[topics.AcceptedBlock] = acceptedBlockID
+	candidateChan, candidateID := initCandidateCollector(broker, topics.Candidate)
+	subscriptions[topics.Candidate] = candidateID
+	generatedChan, generatedID := initCandidateCollector(broker, topics.GeneratedCandidate)
+	subscriptions[topics.GeneratedCandidate] = generatedID
 	getCandidateChan := make(chan rpcbus.Request, 1)
-	rpcBus.Register(rpcbus.GetCandidate, getCandidateChan)
+	rpcBus.RegisterChan(rpcbus.GetCandidate, getCandidateChan)
+	getLocalCandidateChan := make(chan rpcbus.Request, 1)
+	rpcBus.RegisterChan(rpcbus.GetLocalCandidate, getLocalCandidateChan)
+	getStatsChan := make(chan rpcbus.Request, 1)
+	rpcBus.RegisterChan(rpcbus.GetCandidateStats, getStatsChan)
+	restartChan := make(chan bytes.Buffer, 1)
+	subscriptions[topics.Restart] = broker.Subscribe(topics.Restart, eventbus.NewChanListener(restartChan))
 
 	b := &Broker{
-		publisher:         broker,
-		store:             newStore(),
-		validHashes:       make(map[string]struct{}),
-		acceptedBlockChan: acceptedBlockChan,
-		candidateChan:     initCandidateCollector(broker),
-		getCandidateChan:  getCandidateChan,
+		publisher:             broker,
+		subscriber:            broker,
+		store:                 newStore(),
+		validHashes:           make(map[string]validScore),
+		acceptedBlockChan:     acceptedBlockChan,
+		candidateChan:         candidateChan,
+		getCandidateChan:      getCandidateChan,
+		getLocalCandidateChan: getLocalCandidateChan,
+		getStatsChan:          getStatsChan,
+		generatedChan:         generatedChan,
+		restartChan:           restartChan,
+		subscriptions:         subscriptions,
+		quit:                  make(chan struct{}),
 	}
 
-	broker.Subscribe(topics.ValidCandidateHash, eventbus.NewCallbackListener(b.AddValidHash))
-	b.republisher = republisher.New(broker, topics.Candidate, Validate)
+	subscriptions[topics.ValidCandidateHash] = broker.Subscribe(topics.ValidCandidateHash, eventbus.NewCallbackListener(b.AddValidHash))
+	// Candidates are validated once, when published, so that neither the
+	// collector nor the republisher get to see malformed ones
+	validators := []republisher.Validator{Validate}
+	if registry, ok := broker.(eventbus.ValidatorRegistry); ok {
+		registry.RegisterValidator(topics.Candidate, Validate)
+		validators = nil
+	}
+	b.republisher = republisher.New(broker, topics.Candidate, validators...)
 	return b
 }
 
@@ -61,30 +125,123 @@ func (b *Broker) Listen() {
 	for {
 		select {
 		case cm := <-b.candidateChan:
-			if _, ok := b.validHashes[string(cm.Block.Header.Hash)]; ok {
-				b.storeCandidateMessage(cm)
-			}
+			b.collect(cm)
 		case r := <-b.getCandidateChan:
-			b.provideCandidate(r)
+			b.provideCandidate(r, true)
+		case r := <-b.getLocalCandidateChan:
+			b.provideCandidate(r, false)
+		case r := <-b.getStatsChan:
+			b.provideStats(r)
+		case cm := <-b.generatedChan:
+			b.storeCandidateMessage(cm)
+			b.own = &cm
+		case <-b.restartChan:
+			b.republishOwn()
 		case blk := <-b.acceptedBlockChan:
 			b.clearEligibleBlocks()
 			b.Clear(blk.Header.Height)
+			if b.own != nil && b.own.Block.Header.Height <= blk.Header.Height {
+				b.own = nil
+			}
+		case <-b.quit:
+			return
+		}
+	}
+}
+
+// Close unsubscribes the Broker from the event bus, stops republishing the
+// candidates, and stops Listen
+func (b *Broker) Close() {
+	b.closeOnce.Do(func() {
+		for topic, id := range b.subscriptions {
+			b.subscriber.Unsubscribe(topic, id)
 		}
+		b.republisher.Stop()
+		close(b.quit)
+	})
+}
+
+// republishOwn gossips again the candidate generated by this node, if any
+func (b *Broker) republishOwn() {
+	if b.own == nil {
+		return
 	}
+
+	buf := new(bytes.Buffer)
+	if err := Encode(buf, b.own); err != nil {
+		lg.WithError(err).Warnln("could not encode candidate")
+		return
+	}
+
+	if err := topics.Prepend(buf, topics.Candidate); err != nil {
+		lg.WithError(err).Warnln("could not encode candidate")
+		return
+	}
+
+	b.publisher.Publish(topics.Gossip, buf)
 }
 
+// validScore holds the details of a verified Score message needed to check
+// its candidate
+type validScore struct {
+	// the Z of the generator
+	generator string
+	// the seed and the score proven by the generator, if known
+	seed, score []byte
+}
+
+// AddValidHash is the callback for `ValidCandidateHash` messages, which carry
+// the hash of a candidate followed, optionally, by the Z of its generator, and
+// then by the seed and the score of its Score message.
 func (b *Broker) AddValidHash(m bytes.Buffer) error {
 	hash := make([]byte, 32)
 	if err := encoding.Read256(&m, hash); err != nil {
 		return err
 	}
 
-	b.validHashes[string(hash)] = struct{}{}
+	var v validScore
+	if m.Len() > 0 {
+		generator := make([]byte, 32)
+		if err := encoding.Read256(&m, generator); err != nil {
+			return err
+		}
+		v.generator = string(generator)
+	}
+
+	if m.Len() > 0 {
+		v.seed = make([]byte, 33)
+		if err := encoding.ReadBLS(&m, v.seed); err != nil {
+			return err
+		}
+
+		v.score = make([]byte, 32)
+		if err := encoding.Read256(&m, v.score); err != nil {
+			return err
+		}
+	}
+
+	b.validHashes[string(hash)] = v
 	return nil
 }
 
-func (b *Broker) provideCandidate(r rpcbus.Request) {
+// provideStats answers a `GetCandidateStats` request with the counters of
+// refused candidates
+func (b *Broker) provideStats(r rpcbus.Request) {
+	buf := new(bytes.Buffer)
+	err := MarshalStats(buf, b.Stats())
+	r.RespChan <- rpcbus.Response{*buf, err}
+}
+
+// provideCandidate answers a request for the candidate with the hash in the
+// request params. If askPeers is set, missing candidates are requested from
+// the network.
+func (b *Broker) provideCandidate(r rpcbus.Request, askPeers bool) {
 	cm := b.store.fetchCandidateMessage(r.Params.Bytes())
+	if cm == nil && !askPeers {
+		r.RespChan <- rpcbus.Response{bytes.Buffer{}, ErrCandidateNotFound}
+		return
+	}
+
 	if cm == nil {
 		// If we don't have the candidate message, we should ask the network for it.
 		var err error
@@ -119,20 +276,48 @@ func (b *Broker) requestCandidate(hash []byte) (*Candidate, error) {
 		// candidates. There should be no race condition in reading from
 		// the channel, as the only way this function can be called would
 		// be through `Listen`. Any incoming candidates which don't match
-		// our request will be passed down to the store.
+		// our request are handled as `Listen` would.
 		case cm := <-b.candidateChan:
-			// We don't check if this is an eligible candidate block,
-			// as we most likely did not get the Score message for it.
-			// However, as we are only interested in one specific block,
-			// we should not be in danger of memory overflow.
-			b.storeCandidateMessage(cm)
-			if bytes.Equal(cm.Block.Header.Hash, hash) {
-				return &cm, nil
+			if !bytes.Equal(cm.Block.Header.Hash, hash) {
+				b.collect(cm)
+				continue
+			}
+
+			// We most likely did not get the Score message for the
+			// requested candidate, so it is only stored up to the next
+			// round, for peers not to fill the store with candidates of
+			// future rounds. The collector already checked the hash and
+			// the tx root of the block with `Validate`, so a peer can not
+			// answer with a forged candidate.
+			if cm.Block.Header.Height <= b.Round()+1 {
+				b.storeCandidateMessage(cm)
 			}
+			return &cm, nil
 		}
 	}
 }
 
+// collect stores cm if a valid Score message was seen for it. With
+// consensus.deepCandidateValidation, the seed and the score of cm must be the
+// ones of the Score message as well.
+func (b *Broker) collect(cm Candidate) {
+	v, ok := b.validHashes[string(cm.Block.Header.Hash)]
+	if !ok {
+		return
+	}
+
+	if config.Get().Consensus.DeepCandidateValidation && v.seed != nil {
+		if err := checkScore(cm.Block, v.seed, v.score); err != nil {
+			lg.WithError(err).WithField("round", cm.Block.Header.Height).Debugln("candidate refused")
+			return
+		}
+	}
+
+	if err := b.storeFrom(cm, v.generator); err != nil {
+		lg.WithError(err).WithField("round", cm.Block.Header.Height).Debugln("candidate refused")
+	}
+}
+
 func (b *Broker) clearEligibleBlocks() {
 	for h := range b.validHashes {
 		delete(b.validHashes, h)
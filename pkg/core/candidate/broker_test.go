@@ -40,7 +40,7 @@ func TestValidHashes(t *testing.T) {
 	time.Sleep(1000 * time.Millisecond)
 
 	// When requesting it, we should get an error.
-	_, err := rb.Call(rpcbus.GetCandidate, rpcbus.Request{*bytes.NewBuffer(blk.Header.Hash), make(chan rpcbus.Response, 1)}, 5*time.Second)
+	_, err := rb.Call(rpcbus.GetCandidate, rpcbus.NewRequest(*bytes.NewBuffer(blk.Header.Hash)), 5*time.Second)
 	assert.Equal(t, "request timeout", err.Error())
 
 	// Now, add the hash to validHashes
@@ -48,7 +48,7 @@ func TestValidHashes(t *testing.T) {
 	// And try again.
 	eb.Publish(topics.Candidate, buf)
 
-	blkBuf, err := rb.Call(rpcbus.GetCandidate, rpcbus.Request{*bytes.NewBuffer(blk.Header.Hash), make(chan rpcbus.Response, 1)}, 5*time.Second)
+	blkBuf, err := rb.Call(rpcbus.GetCandidate, rpcbus.NewRequest(*bytes.NewBuffer(blk.Header.Hash)), 5*time.Second)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -60,3 +60,101 @@ func TestValidHashes(t *testing.T) {
 
 	assert.True(t, blk.Equals(decoded))
 }
+
+// Ensures that a missing candidate is requested from the peers, while peer
+// requests are only served from the store.
+func TestPeerCandidateRequests(t *testing.T) {
+	eb, rb := eventbus.New(), rpcbus.New()
+	b := candidate.NewBroker(eb, rb)
+	go b.Listen()
+
+	gossipChan := make(chan bytes.Buffer, 1)
+	eb.Subscribe(topics.Gossip, eventbus.NewChanListener(gossipChan))
+
+	blk := helper.RandomBlock(t, 1, 3)
+	blk.SetHash()
+	buf := new(bytes.Buffer)
+	if err := candidate.Encode(buf, &candidate.Candidate{Block: blk, Certificate: block.EmptyCertificate()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Peers asking for a candidate we do not have get an answer right away
+	_, err := rb.Call(rpcbus.GetLocalCandidate, rpcbus.NewRequest(*bytes.NewBuffer(blk.Header.Hash)), time.Second)
+	assert.Equal(t, candidate.ErrCandidateNotFound.Error(), err.Error())
+
+	other := helper.RandomBlock(t, 1, 3)
+	other.SetHash()
+	otherBuf := new(bytes.Buffer)
+	if err := candidate.Encode(otherBuf, &candidate.Candidate{Block: other, Certificate: block.EmptyCertificate()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A peer answers our request for the candidate, after sending one we
+	// did not ask for
+	go func() {
+		req := <-gossipChan
+		topic, err := topics.Extract(&req)
+		assert.NoError(t, err)
+		assert.Equal(t, topics.GetCandidate, topic)
+		assert.Equal(t, blk.Header.Hash, req.Bytes())
+
+		eb.Publish(topics.Candidate, otherBuf)
+		eb.Publish(topics.Candidate, bytes.NewBuffer(buf.Bytes()))
+	}()
+
+	blkBuf, err := rb.Call(rpcbus.GetCandidate, rpcbus.NewRequest(*bytes.NewBuffer(blk.Header.Hash)), 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&blkBuf, decoded); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, blk.Equals(decoded))
+
+	// The candidate is now served to peers as well
+	_, err = rb.Call(rpcbus.GetLocalCandidate, rpcbus.NewRequest(*bytes.NewBuffer(blk.Header.Hash)), time.Second)
+	assert.NoError(t, err)
+
+	// Unlike the one which was not requested, nor seen a Score message for
+	_, err = rb.Call(rpcbus.GetLocalCandidate, rpcbus.NewRequest(*bytes.NewBuffer(other.Header.Hash)), time.Second)
+	assert.Equal(t, candidate.ErrCandidateNotFound.Error(), err.Error())
+}
+
+// Ensures that our own candidate is gossiped again when the consensus
+// restarts.
+func TestRepublishOwnCandidate(t *testing.T) {
+	eb, rb := eventbus.New(), rpcbus.New()
+	b := candidate.NewBroker(eb, rb)
+	go b.Listen()
+
+	gossipChan := make(chan bytes.Buffer, 1)
+	eb.Subscribe(topics.Gossip, eventbus.NewChanListener(gossipChan))
+
+	blk := helper.RandomBlock(t, 1, 3)
+	blk.SetHash()
+	buf := new(bytes.Buffer)
+	if err := candidate.Encode(buf, &candidate.Candidate{Block: blk, Certificate: block.EmptyCertificate()}); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	eb.Publish(topics.GeneratedCandidate, bytes.NewBuffer(encoded))
+	time.Sleep(100 * time.Millisecond)
+
+	// Our candidate is served to peers without a ValidCandidateHash message
+	_, err := rb.Call(rpcbus.GetLocalCandidate, rpcbus.NewRequest(*bytes.NewBuffer(blk.Header.Hash)), time.Second)
+	assert.NoError(t, err)
+
+	eb.Publish(topics.Restart, new(bytes.Buffer))
+	select {
+	case m := <-gossipChan:
+		topic, err := topics.Extract(&m)
+		assert.NoError(t, err)
+		assert.Equal(t, topics.Candidate, topic)
+		assert.Equal(t, encoded, m.Bytes())
+	case <-time.After(time.Second):
+		t.Fatal("candidate was not republished")
+	}
+}
@@ -11,12 +11,12 @@ type candidateCollector struct {
 	candidateChan chan Candidate
 }
 
-func initCandidateCollector(sub eventbus.Subscriber) <-chan Candidate {
+func initCandidateCollector(sub eventbus.Subscriber, topic topics.Topic) (<-chan Candidate, uint32) {
 	candidateChan := make(chan Candidate, 100)
 	collector := &candidateCollector{candidateChan}
 	l := eventbus.NewCallbackListener(collector.Collect)
-	sub.Subscribe(topics.Candidate, l)
-	return candidateChan
+	id := sub.Subscribe(topic, l)
+	return candidateChan, id
 }
 
 func (c *candidateCollector) Collect(b bytes.Buffer) error {
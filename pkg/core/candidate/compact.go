@@ -0,0 +1,136 @@
+package candidate
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/dusk-network/dusk-wallet/transactions"
+)
+
+// CompactCandidate is the compact encoding of a Candidate, gossiped between
+// peers which advertise `protocol.CompactCandidates`. The transactions of the
+// block are replaced by their TxID, as most of them are already in the
+// mempool of the receiving node. Only the coinbase, which can not be found
+// in any mempool, is sent in full.
+type CompactCandidate struct {
+	Header *block.Header
+	*block.Certificate
+	Coinbase transactions.Transaction
+	TxIDs    [][]byte
+}
+
+// EncodeCompact writes the compact encoding of cm to b. The first transaction
+// of the block must be its coinbase.
+func EncodeCompact(b *bytes.Buffer, cm *Candidate) error {
+	if len(cm.Block.Txs) == 0 {
+		return errors.New("candidate has no coinbase")
+	}
+
+	if err := marshalling.MarshalHeader(b, cm.Block.Header); err != nil {
+		return err
+	}
+
+	if err := marshalling.MarshalCertificate(b, cm.Certificate); err != nil {
+		return err
+	}
+
+	if err := marshalling.MarshalTx(b, cm.Block.Txs[0]); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteVarInt(b, uint64(len(cm.Block.Txs)-1)); err != nil {
+		return err
+	}
+
+	for _, tx := range cm.Block.Txs[1:] {
+		txid, err := marshalling.TxID(tx)
+		if err != nil {
+			return err
+		}
+
+		if err := encoding.Write256(b, txid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeCompact reads a CompactCandidate from b
+func DecodeCompact(b *bytes.Buffer) (*CompactCandidate, error) {
+	cc := &CompactCandidate{
+		Header:      block.NewBlock().Header,
+		Certificate: block.EmptyCertificate(),
+	}
+
+	if err := marshalling.UnmarshalHeader(b, cc.Header); err != nil {
+		return nil, err
+	}
+
+	if err := marshalling.UnmarshalCertificate(b, cc.Certificate); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if cc.Coinbase, err = marshalling.UnmarshalTx(b); err != nil {
+		return nil, err
+	}
+
+	n, err := encoding.ReadVarInt(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every TxID takes 32 bytes, which bounds the allocation below
+	if n > uint64(b.Len()/32) {
+		return nil, errors.New("invalid amount of transactions")
+	}
+
+	cc.TxIDs = make([][]byte, n)
+	for i := range cc.TxIDs {
+		cc.TxIDs[i] = make([]byte, 32)
+		if err := encoding.Read256(b, cc.TxIDs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return cc, nil
+}
+
+// Expand rebuilds the full Candidate out of the given transactions, usually
+// the content of the mempool. If some of the transactions are not found, the
+// Candidate is nil and their TxIDs are returned instead.
+func (cc *CompactCandidate) Expand(txs []transactions.Transaction) (*Candidate, [][]byte, error) {
+	known := make(map[string]transactions.Transaction, len(txs))
+	for _, tx := range txs {
+		txid, err := marshalling.TxID(tx)
+		if err != nil {
+			return nil, nil, err
+		}
+		known[string(txid)] = tx
+	}
+
+	blk := block.NewBlock()
+	blk.Header = cc.Header
+	blk.Txs = make([]transactions.Transaction, 0, len(cc.TxIDs)+1)
+	blk.Txs = append(blk.Txs, cc.Coinbase)
+
+	var missing [][]byte
+	for _, txid := range cc.TxIDs {
+		tx, ok := known[string(txid)]
+		if !ok {
+			missing = append(missing, txid)
+			continue
+		}
+		blk.Txs = append(blk.Txs, tx)
+	}
+
+	if len(missing) > 0 {
+		return nil, missing, nil
+	}
+
+	return &Candidate{blk, cc.Certificate}, nil, nil
+}
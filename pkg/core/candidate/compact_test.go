@@ -0,0 +1,36 @@
+package candidate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the round trip of a candidate through its compact encoding.
+func TestCompactCandidate(t *testing.T) {
+	cm := mockCandidateMessage(t)
+	buf := new(bytes.Buffer)
+	assert.NoError(t, EncodeCompact(buf, cm))
+
+	// The compact encoding is smaller than the full one
+	full := new(bytes.Buffer)
+	assert.NoError(t, Encode(full, cm))
+	assert.True(t, buf.Len() < full.Len())
+
+	cc, err := DecodeCompact(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, len(cm.Block.Txs)-1, len(cc.TxIDs))
+
+	// With an empty mempool, all but the coinbase are missing
+	_, missing, err := cc.Expand(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, cc.TxIDs, missing)
+
+	// With the transactions at hand, the candidate is rebuilt
+	expanded, missing, err := cc.Expand(cm.Block.Txs[1:])
+	assert.NoError(t, err)
+	assert.Empty(t, missing)
+	assert.True(t, expanded.Block.Equals(cm.Block))
+	assert.True(t, expanded.Certificate.Equals(cm.Certificate))
+}
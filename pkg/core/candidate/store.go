@@ -2,16 +2,64 @@ package candidate
 
 import (
 	"bytes"
+	"errors"
 	"sync"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-wallet/block"
 )
 
+// maxCandidatesPerRound bounds the amount of candidates stored for a round.
+// Honest nodes only produce a few candidates per round, one for every step
+// of score generation.
+const maxCandidatesPerRound = 16
+
+// maxCandidatesPerGenerator bounds the amount of candidates stored for a
+// round, coming from the same block generator. An honest generator only
+// improves its candidate when a new Score is due.
+const maxCandidatesPerGenerator = 2
+
+var (
+	// ErrStaleCandidate is returned when storing a candidate for a round
+	// which is already over
+	ErrStaleCandidate = errors.New("candidate is older than the current round")
+	// ErrRoundFull is returned when storing a candidate for a round which
+	// already has maxCandidatesPerRound of them
+	ErrRoundFull = errors.New("too many candidates for the round")
+	// ErrGeneratorFull is returned when storing a candidate from a generator
+	// which already has maxCandidatesPerGenerator of them in the round
+	ErrGeneratorFull = errors.New("too many candidates from the generator")
+)
+
+var (
+	candidatesHeld    = metrics.NewGauge("dusk_candidate_blocks", "Candidate blocks stored for the current rounds")
+	candidatesRefused = metrics.NewCounterVec("dusk_candidate_blocks_refused_total", "Candidate blocks refused by the store", "reason")
+)
+
 type (
 	store struct {
 		lock     sync.RWMutex
 		messages map[string]*Candidate
+		// amount of candidates stored for each round
+		rounds map[uint64]int
+		// amount of candidates stored for each round, by generator
+		generators map[uint64]map[string]int
+		// candidates below this round are expired
+		round uint64
+		stats Stats
+	}
+
+	// Stats counts the candidates refused by the store
+	Stats struct {
+		// Stale is the amount of candidates older than the current round
+		Stale uint64
+		// ExcessRound is the amount of candidates above the cap of their round
+		ExcessRound uint64
+		// ExcessGenerator is the amount of candidates above the cap of their
+		// generator
+		ExcessGenerator uint64
 	}
 
 	Candidate struct {
@@ -26,15 +74,75 @@ func NewCandidate() *Candidate {
 
 func newStore() *store {
 	return &store{
-		messages: make(map[string]*Candidate),
+		messages:   make(map[string]*Candidate),
+		rounds:     make(map[uint64]int),
+		generators: make(map[uint64]map[string]int),
 	}
 }
 
-func (c *store) storeCandidateMessage(cm Candidate) {
-	// TODO: ensure we can't become a victim of memory overflow attacks
+// storeCandidateMessage adds cm, whose generator is unknown, to the store. It
+// returns false if the candidate was refused.
+func (c *store) storeCandidateMessage(cm Candidate) bool {
+	return c.storeFrom(cm, "") == nil
+}
+
+// storeFrom adds cm, made by generator, to the store. Candidates of expired
+// rounds, and candidates above the caps of their round or of their generator
+// are refused. An empty generator is not capped.
+func (c *store) storeFrom(cm Candidate, generator string) error {
 	c.lock.Lock()
-	c.messages[string(cm.Block.Header.Hash)] = &cm
-	c.lock.Unlock()
+	defer c.lock.Unlock()
+
+	round := cm.Block.Header.Height
+	if round < c.round {
+		c.stats.Stale++
+		candidatesRefused.With("stale").Inc()
+		return ErrStaleCandidate
+	}
+
+	hash := string(cm.Block.Header.Hash)
+	if _, ok := c.messages[hash]; ok {
+		c.messages[hash] = &cm
+		return nil
+	}
+
+	if c.rounds[round] >= maxCandidatesPerRound {
+		c.stats.ExcessRound++
+		candidatesRefused.With("round_full").Inc()
+		return ErrRoundFull
+	}
+
+	if generator != "" {
+		if c.generators[round] == nil {
+			c.generators[round] = make(map[string]int)
+		}
+
+		if c.generators[round][generator] >= maxCandidatesPerGenerator {
+			c.stats.ExcessGenerator++
+			candidatesRefused.With("generator_full").Inc()
+			return ErrGeneratorFull
+		}
+		c.generators[round][generator]++
+	}
+
+	c.messages[hash] = &cm
+	c.rounds[round]++
+	candidatesHeld.Set(float64(len(c.messages)))
+	return nil
+}
+
+// Round returns the current round, the first one candidates are stored for
+func (c *store) Round() uint64 {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.round
+}
+
+// Stats returns the counters of refused candidates
+func (c *store) Stats() Stats {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.stats
 }
 
 func (c *store) fetchCandidateMessage(hash []byte) *Candidate {
@@ -44,9 +152,12 @@ func (c *store) fetchCandidateMessage(hash []byte) *Candidate {
 	return cm
 }
 
-// Clear removes all candidate messages from or before a given round.
-// Returns the amount of messages deleted.
+// Clear removes all candidate messages from or before a given round, which
+// expires. Returns the amount of messages deleted.
 func (c *store) Clear(round uint64) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	deletedCount := 0
 	for h, m := range c.messages {
 		if m.Block.Header.Height <= round {
@@ -55,9 +166,49 @@ func (c *store) Clear(round uint64) int {
 		}
 	}
 
+	for r := range c.rounds {
+		if r <= round {
+			delete(c.rounds, r)
+		}
+	}
+
+	for r := range c.generators {
+		if r <= round {
+			delete(c.generators, r)
+		}
+	}
+
+	if round >= c.round {
+		c.round = round + 1
+	}
+
+	candidatesHeld.Set(float64(len(c.messages)))
 	return deletedCount
 }
 
+// MarshalStats encodes the counters of refused candidates
+func MarshalStats(b *bytes.Buffer, s Stats) error {
+	for _, n := range []uint64{s.Stale, s.ExcessRound, s.ExcessGenerator} {
+		if err := encoding.WriteUint64LE(b, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalStats decodes the counters of refused candidates
+func UnmarshalStats(b *bytes.Buffer) (Stats, error) {
+	s := Stats{}
+	for _, n := range []*uint64{&s.Stale, &s.ExcessRound, &s.ExcessGenerator} {
+		if err := encoding.ReadUint64LE(b, n); err != nil {
+			return Stats{}, err
+		}
+	}
+
+	return s, nil
+}
+
 func Decode(b *bytes.Buffer, cMsg *Candidate) error {
 	if err := marshalling.UnmarshalBlock(b, cMsg.Block); err != nil {
 		return err
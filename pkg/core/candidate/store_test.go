@@ -38,6 +38,57 @@ func TestStoreFetchClear(t *testing.T) {
 	assert.Empty(t, c.messages)
 }
 
+// Ensure the amount of candidates stored for a round is bounded
+func TestStoreBoundedPerRound(t *testing.T) {
+	c := newStore()
+	for i := 0; i < maxCandidatesPerRound; i++ {
+		cm := NewCandidate()
+		cm.Block.Header.Height = 1
+		cm.Block.Header.Hash = []byte{byte(i)}
+		assert.True(t, c.storeCandidateMessage(*cm))
+	}
+
+	cm := NewCandidate()
+	cm.Block.Header.Height = 1
+	cm.Block.Header.Hash = []byte{0xff}
+	assert.False(t, c.storeCandidateMessage(*cm))
+
+	// Other rounds are not affected
+	cm.Block.Header.Height = 2
+	assert.True(t, c.storeCandidateMessage(*cm))
+
+	// Clearing a round expires it
+	c.Clear(1)
+	assert.Empty(t, c.rounds[1])
+	cm.Block.Header.Height = 1
+	cm.Block.Header.Hash = []byte{0xfe}
+	assert.False(t, c.storeCandidateMessage(*cm))
+	assert.Equal(t, Stats{Stale: 1, ExcessRound: 1}, c.Stats())
+}
+
+// Ensure a single generator can not fill the round
+func TestStoreBoundedPerGenerator(t *testing.T) {
+	c := newStore()
+	for i := 0; i < maxCandidatesPerGenerator; i++ {
+		cm := NewCandidate()
+		cm.Block.Header.Height = 1
+		cm.Block.Header.Hash = []byte{byte(i)}
+		assert.NoError(t, c.storeFrom(*cm, "spammer"))
+	}
+
+	cm := NewCandidate()
+	cm.Block.Header.Height = 1
+	cm.Block.Header.Hash = []byte{0xff}
+	assert.Equal(t, ErrGeneratorFull, c.storeFrom(*cm, "spammer"))
+	assert.Equal(t, uint64(1), c.Stats().ExcessGenerator)
+
+	// Other generators, and the next rounds, are not affected
+	assert.NoError(t, c.storeFrom(*cm, "honest"))
+	cm.Block.Header.Height = 2
+	cm.Block.Header.Hash = []byte{0xfe}
+	assert.NoError(t, c.storeFrom(*cm, "spammer"))
+}
+
 // Test the candidate request functionality.
 func TestRequestCandidate(t *testing.T) {
 	eb := eventbus.New()
@@ -52,7 +103,7 @@ func TestRequestCandidate(t *testing.T) {
 	doneChan := make(chan struct{}, 1)
 	genesis := config.DecodeGenesis()
 	go func() {
-		blkBuf, err := rpc.Call(rpcbus.GetCandidate, rpcbus.Request{*bytes.NewBuffer(genesis.Header.Hash), make(chan rpcbus.Response, 1)}, 0)
+		blkBuf, err := rpc.Call(rpcbus.GetCandidate, rpcbus.NewRequest(*bytes.NewBuffer(genesis.Header.Hash)), 0)
 		assert.NoError(t, err)
 
 		blk := block.NewBlock()
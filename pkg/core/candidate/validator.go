@@ -4,11 +4,18 @@ import (
 	"bytes"
 	"errors"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/versionbits"
 	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/dusk-network/dusk-wallet/transactions"
 )
 
 // Make sure the hash and root are correct, to avoid malicious nodes from
-// overwriting the candidate block for a specific hash
+// overwriting the candidate block for a specific hash.
+// With consensus.deepCandidateValidation, the block is also checked against
+// the stateless rules of the consensus, see checkDeep.
 func Validate(b bytes.Buffer) error {
 	cm := &Candidate{block.NewBlock(), block.EmptyCertificate()}
 	if err := Decode(&b, cm); err != nil {
@@ -19,7 +26,79 @@ func Validate(b bytes.Buffer) error {
 		return err
 	}
 
-	return checkRoot(cm.Block)
+	if err := checkRoot(cm.Block); err != nil {
+		return err
+	}
+
+	if config.Get().Consensus.DeepCandidateValidation {
+		return checkDeep(cm.Block)
+	}
+
+	return nil
+}
+
+// checkDeep enforces the rules of AcceptBlock which do not depend on the
+// state of the chain, so that candidates bound to be refused are not stored
+// nor relayed: the size limits of the block and of its txs, the coinbase
+// being the first and only one, and the seed and the score of the generator
+// being well formed. Their relationship is proven by the Score message, and
+// checked by checkScore once the message is verified.
+func checkDeep(blk *block.Block) error {
+	if err := verifiers.CheckBlockSize(*blk); err != nil {
+		return err
+	}
+
+	if !versionbits.IsValid(blk.Header.Version) {
+		return errors.New("unsupported block version")
+	}
+
+	if len(blk.Header.Seed) != 33 {
+		return errors.New("invalid seed")
+	}
+
+	if err := verifiers.CheckMultiCoinbases(blk.Txs); err != nil {
+		return err
+	}
+
+	coinbase, ok := blk.Txs[0].(*transactions.Coinbase)
+	if !ok {
+		return errors.New("coinbase transaction is not in the first position")
+	}
+
+	if err := verifiers.VerifyCoinbase(0, coinbase); err != nil {
+		return err
+	}
+
+	if len(coinbase.Score) == 0 || len(coinbase.Proof) == 0 {
+		return errors.New("coinbase transaction does not hold the score of the generator")
+	}
+
+	for _, tx := range blk.Txs {
+		if err := verifiers.CheckTxLimits(tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkScore ensures that the seed and the score of blk are the ones of its
+// Score message, whose proof binds the score to the seed
+func checkScore(blk *block.Block, seed, score []byte) error {
+	if !bytes.Equal(blk.Header.Seed, seed) {
+		return errors.New("seed does not match the score message")
+	}
+
+	if len(blk.Txs) == 0 {
+		return errors.New("missing coinbase transaction")
+	}
+
+	coinbase, ok := blk.Txs[0].(*transactions.Coinbase)
+	if !ok || !bytes.Equal(coinbase.Score, score) {
+		return errors.New("score does not match the score message")
+	}
+
+	return nil
 }
 
 func checkHash(blk *block.Block) error {
@@ -37,9 +116,8 @@ func checkHash(blk *block.Block) error {
 }
 
 func checkRoot(blk *block.Block) error {
-	root := make([]byte, 32)
-	copy(root, blk.Header.TxRoot)
-	if err := blk.SetRoot(); err != nil {
+	root, err := marshalling.TxRoot(blk.Txs)
+	if err != nil {
 		return err
 	}
 
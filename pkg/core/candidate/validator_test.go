@@ -3,6 +3,13 @@ package candidate
 import (
 	"bytes"
 	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/dusk-network/dusk-wallet/transactions"
+	"github.com/stretchr/testify/assert"
 )
 
 // Ensure that the behaviour of the validator works as intended.
@@ -41,3 +48,60 @@ func TestValidatorInvalidBlock(t *testing.T) {
 		t.Fatal("processing a block with an invalid hash should return an error")
 	}
 }
+
+// Ensure that the deep validation refuses candidates which would fail
+// AcceptBlock regardless of the state of the chain
+func TestValidatorDeep(t *testing.T) {
+	blk := helper.RandomBlock(t, 1, 1)
+	// Drop the coinbase
+	blk.Txs = blk.Txs[1:]
+	if err := marshalling.SetTxRoot(blk); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := blk.SetHash(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := Encode(buf, &Candidate{blk, block.EmptyCertificate()}); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	// The hash and the root are fine
+	assert.NoError(t, Validate(*bytes.NewBuffer(encoded)))
+
+	prev := config.Get()
+	defer config.Mock(&prev)
+	r := prev
+	r.General.Network = "testnet"
+	r.Consensus.DeepCandidateValidation = true
+	config.Mock(&r)
+	assert.Error(t, Validate(*bytes.NewBuffer(encoded)))
+
+	// A well formed candidate passes
+	blk = helper.RandomBlock(t, 1, 1)
+	if err := blk.SetHash(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf = new(bytes.Buffer)
+	if err := Encode(buf, &Candidate{blk, block.EmptyCertificate()}); err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, Validate(*buf))
+}
+
+// Ensure that candidates are refused if their seed or score differ from the
+// ones of their Score message
+func TestCheckScore(t *testing.T) {
+	blk := helper.RandomBlock(t, 1, 1)
+	coinbase := blk.Txs[0].(*transactions.Coinbase)
+	seed := blk.Header.Seed
+	score := coinbase.Score
+
+	assert.NoError(t, checkScore(blk, seed, score))
+	assert.Error(t, checkScore(blk, make([]byte, 33), score))
+	assert.Error(t, checkScore(blk, seed, make([]byte, 32)))
+}
@@ -7,8 +7,11 @@ import (
 	"time"
 
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
 
 	"github.com/bwesterb/go-ristretto"
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
@@ -16,6 +19,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing/chainsync"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 	"github.com/dusk-network/dusk-wallet/block"
 	"github.com/dusk-network/dusk-wallet/key"
 	zkproof "github.com/dusk-network/dusk-zkproof"
@@ -28,14 +32,28 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/versionbits"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
 	"github.com/dusk-network/dusk-wallet/transactions"
 	"golang.org/x/crypto/ed25519"
 )
 
 var log *logger.Entry = logger.WithFields(logger.Fields{"process": "chain"})
 
+var (
+	blocksAccepted = metrics.NewCounter("dusk_chain_blocks_accepted_total", "Blocks appended to the chain")
+	blocksRejected = metrics.NewCounter("dusk_chain_blocks_rejected_total", "Blocks which failed verification")
+	chainHeight    = metrics.NewGauge("dusk_chain_height", "Height of the chain tip")
+	reorgs         = metrics.NewCounter("dusk_chain_reorgs_total", "Switches of the chain to a heavier fork")
+
+	verifyLatency = metrics.NewHistogram("dusk_chain_block_verification_seconds", "Time taken to verify a block", metrics.LatencyBuckets)
+	blockInterval = metrics.NewHistogram("dusk_chain_block_interval_seconds", "Time between the timestamps of consecutive blocks",
+		[]float64{1, 2, 5, 10, 15, 20, 30, 45, 60, 120, 300, 600})
+)
+
 // Chain represents the nodes blockchain
 // This struct will be aware of the current state of the node.
 type Chain struct {
@@ -52,6 +70,10 @@ type Chain struct {
 	// TODO: Consider if mutex can be removed
 	mu sync.RWMutex
 
+	// protects intermediateBlock and lastCertificate, as they are served
+	// on the RPCBus from the goroutines of the callers
+	roundLock sync.RWMutex
+
 	// Intermediate block, decided on by consensus.
 	// Used to verify a candidate against the correct previous block,
 	// and to be accepted once a certificate is decided on.
@@ -64,21 +86,83 @@ type Chain struct {
 
 	// The highest block we've seen from the network. This is updated
 	// by the synchronizer, and used to calculate our synchronization
-	// progress.
+	// progress. Accessed atomically.
 	highestSeen uint64
 
+	// tallies the deployment signals of the accepted blocks
+	versions *versionbits.Tracker
+
+	// Candidate on which the first step of reduction converged, fetched
+	// ahead of its certificate. Protected by prefetchLock, as it is set
+	// from a background goroutine.
+	prefetchLock sync.Mutex
+	prefetched   *candidate.Candidate
+
+	// Candidates which passed verification, by digest of their hash and
+	// txs, with the hash of the block they were verified on top of, so that
+	// the winning one is not verified in full again on acceptance. Protected
+	// by verifiedLock, as candidates are verified from the goroutines of the
+	// rpcbus callers.
+	verifiedLock sync.Mutex
+	verified     map[string][]byte
+
+	// Blocks which do not extend the tip, by hash. Protected by mu
+	forks map[string]block.Block
+	// Provisioners at the fork point of the last fork checked, see
+	// provisionersAt. Protected by mu
+	forkSet *forkProvisioners
+
+	// Serializes the blocks received from the network
+	syncLock sync.Mutex
+
+	// Blocks whose previous block is unknown, held until it is accepted.
+	// The blocks synced ahead of the tip are held there as well.
+	orphans *orphanPool
+
+	// Snapshot the database was bootstrapped from, if any. The provisioners
+	// staking below its tip are restored from it
+	snapshot *Snapshot
+
+	// Trusted block, up to which the blocks of the chain leading to it are
+	// accepted without verifying the proofs of their txs. checkpointHash is
+	// nil if none is set
+	checkpointHeight uint64
+	checkpointHash   []byte
+	// Hashes of the blocks known to lead to the checkpoint, by height
+	trusted map[uint64][]byte
+
+	// headersOnly is set by chain.headersOnly. Blocks are verified without
+	// their txs, and stored with their stakes and bids only
+	headersOnly bool
+
+	// now is the clock the timestamps of the blocks are checked against
+	now func() time.Time
+
 	// collector channels
 	certificateChan <-chan certMsg
 	highestSeenChan <-chan uint64
-
-	// rpcbus channels
-	getLastBlockChan         <-chan rpcbus.Request
-	verifyCandidateBlockChan <-chan rpcbus.Request
-	getLastCertificateChan   <-chan rpcbus.Request
-	getRoundResultsChan      <-chan rpcbus.Request
-	getSyncProgressChan      <-chan rpcbus.Request
+	winningHashChan <-chan []byte
+
+	// ids of the event bus subscriptions, removed on Close
+	subscriptions map[topics.Topic]uint32
+
+	// closed by Close to stop Listen, which holds listenLock while it
+	// runs. closed is protected by mu, and refuses the blocks arriving once
+	// the database is closed
+	quit       chan struct{}
+	listenLock sync.Mutex
+	closed     bool
+	closeOnce  sync.Once
 }
 
+var (
+	// ErrClosed is returned for the blocks arriving after the Chain was closed
+	ErrClosed = errors.New("chain closed")
+	// ErrHeadersOnly is returned for the requests which need the txs of the
+	// blocks, when only their headers are stored
+	ErrHeadersOnly = errors.New("not supported in headers-only mode")
+)
+
 // New returns a new chain object
 func New(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, counter *chainsync.Counter) (*Chain, error) {
 	_, db := heavy.CreateDBConnection()
@@ -88,37 +172,61 @@ func New(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, counter *chainsync.
 		return nil, fmt.Errorf("%s on loading chain db '%s'", err.Error(), cfg.Get().Database.Dir)
 	}
 
+	var checkpointHeight uint64
+	var checkpointHash []byte
+	if checkpoint := cfg.Get().Chain.Checkpoint; checkpoint != "" {
+		checkpointHeight, checkpointHash, err = cfg.ParseCheckpoint(checkpoint)
+		if err != nil {
+			return nil, fmt.Errorf("%s on parsing checkpoint '%s'", err.Error(), checkpoint)
+		}
+	}
+
+	tip := l.chainTip
+	var snapshot *Snapshot
+	if path := cfg.Get().Chain.Snapshot; path != "" {
+		snapshot, tip, err = loadSnapshot(db, path, tip, checkpointHeight, checkpointHash)
+		if err != nil {
+			return nil, fmt.Errorf("%s on loading chain snapshot '%s'", err.Error(), path)
+		}
+	}
+
 	// set up collectors
-	certificateChan := initCertificateCollector(eventBus)
-	highestSeenChan := initHighestSeenCollector(eventBus)
-
-	// set up rpcbus channels
-	getLastBlockChan := make(chan rpcbus.Request, 1)
-	verifyCandidateBlockChan := make(chan rpcbus.Request, 1)
-	getLastCertificateChan := make(chan rpcbus.Request, 1)
-	getRoundResultsChan := make(chan rpcbus.Request, 1)
-	getSyncProgressChan := make(chan rpcbus.Request, 1)
-	rpcBus.Register(rpcbus.GetLastBlock, getLastBlockChan)
-	rpcBus.Register(rpcbus.VerifyCandidateBlock, verifyCandidateBlockChan)
-	rpcBus.Register(rpcbus.GetLastCertificate, getLastCertificateChan)
-	rpcBus.Register(rpcbus.GetRoundResults, getRoundResultsChan)
-	rpcBus.Register(rpcbus.GetSyncProgress, getSyncProgressChan)
+	subscriptions := make(map[topics.Topic]uint32)
+	certificateChan, certificateID := initCertificateCollector(eventBus)
+	subscriptions[topics.Certificate] = certificateID
+	highestSeenChan, highestSeenID := initHighestSeenCollector(eventBus)
+	subscriptions[topics.HighestSeen] = highestSeenID
+	winningHashChan, winningHashID := initStepVotesCollector(eventBus)
+	subscriptions[topics.StepVotes] = winningHashID
 
 	chain := &Chain{
-		eventBus:                 eventBus,
-		rpcBus:                   rpcBus,
-		db:                       db,
-		prevBlock:                *l.chainTip,
-		p:                        user.NewProvisioners(),
-		bidList:                  &user.BidList{},
-		counter:                  counter,
-		certificateChan:          certificateChan,
-		highestSeenChan:          highestSeenChan,
-		getLastBlockChan:         getLastBlockChan,
-		verifyCandidateBlockChan: verifyCandidateBlockChan,
-		getLastCertificateChan:   getLastCertificateChan,
-		getRoundResultsChan:      getRoundResultsChan,
-		getSyncProgressChan:      getSyncProgressChan,
+		eventBus:         eventBus,
+		rpcBus:           rpcBus,
+		db:               db,
+		prevBlock:        *tip,
+		p:                user.NewProvisioners(),
+		bidList:          &user.BidList{},
+		counter:          counter,
+		verified:         make(map[string][]byte),
+		forks:            make(map[string]block.Block),
+		orphans:          newOrphanPool(),
+		snapshot:         snapshot,
+		checkpointHeight: checkpointHeight,
+		checkpointHash:   checkpointHash,
+		trusted:          make(map[uint64][]byte),
+		headersOnly:      cfg.Get().Chain.HeadersOnly,
+		certificateChan:  certificateChan,
+		highestSeenChan:  highestSeenChan,
+		winningHashChan:  winningHashChan,
+		subscriptions:    subscriptions,
+		quit:             make(chan struct{}),
+		now:              time.Now,
+	}
+	chain.versions = versionbits.NewTracker(chain.fetchVersion)
+
+	// The headers of the snapshot lead to the checkpoint, see loadSnapshot
+	if snapshot != nil {
+		chain.trustHeaders(snapshot)
 	}
 
 	// If the `prevBlock` is genesis, we add an empty intermediate block.
@@ -137,32 +245,46 @@ func New(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, counter *chainsync.
 
 	chain.restoreConsensusData()
 
+	// Serve the rpcbus methods
+	rpcBus.Register(rpcbus.GetLastBlock, chain.provideLastBlock)
+	rpcBus.Register(rpcbus.VerifyCandidateBlock, chain.verifyCandidateBlock)
+	rpcBus.Register(rpcbus.GetLastCertificate, chain.provideLastCertificate)
+	rpcBus.Register(rpcbus.GetRoundResults, chain.provideRoundResults)
+	rpcBus.Register(rpcbus.GetSyncProgress, chain.provideSyncProgress)
+	rpcBus.Register(rpcbus.GetProvisioners, chain.provideProvisioners)
+	rpcBus.Register(rpcbus.GetDeployments, chain.provideDeployments)
+	rpcBus.Register(rpcbus.GetBlockByHash, chain.provideBlockByHash)
+	rpcBus.Register(rpcbus.GetBlockByHeight, chain.provideBlockByHeight)
+	rpcBus.Register(rpcbus.GetHeadersRange, chain.provideHeadersRange)
+	rpcBus.Register(rpcbus.CreateSnapshot, chain.provideSnapshot)
+	// The txs of a candidate are already verified by several workers, so
+	// the candidates received meanwhile wait for their turn
+	rpcBus.SetConcurrency(rpcbus.VerifyCandidateBlock, 1)
+
 	// Hook the chain up to the required topics
-	cbListener := eventbus.NewCallbackListener(chain.onAcceptBlock)
-	eventBus.Subscribe(topics.Block, cbListener)
+	cbListener := eventbus.NewTracedCallbackListener(chain.onAcceptBlock)
+	subscriptions[topics.Block] = eventBus.Subscribe(topics.Block, cbListener)
 	initListener := eventbus.NewCallbackListener(chain.onInitialization)
-	eventBus.Subscribe(topics.Initialization, initListener)
+	subscriptions[topics.Initialization] = eventBus.Subscribe(topics.Initialization, initListener)
 	return chain, nil
 }
 
-// Listen to the collectors
+// Listen to the collectors, until Close is called. Listen can be called
+// again once it returns, so that the node supervisor can restart it, and
+// returns right away once the Chain is closed.
 func (c *Chain) Listen() {
+	c.listenLock.Lock()
+	defer c.listenLock.Unlock()
 	for {
 		select {
 		case certMsg := <-c.certificateChan:
 			c.handleCertificateMessage(certMsg)
 		case height := <-c.highestSeenChan:
-			c.highestSeen = height
-		case r := <-c.getLastBlockChan:
-			c.provideLastBlock(r)
-		case r := <-c.verifyCandidateBlockChan:
-			c.verifyCandidateBlock(r)
-		case r := <-c.getLastCertificateChan:
-			c.provideLastCertificate(r)
-		case r := <-c.getRoundResultsChan:
-			c.provideRoundResults(r)
-		case r := <-c.getSyncProgressChan:
-			c.provideSyncProgress(r)
+			atomic.StoreUint64(&c.highestSeen, height)
+		case hash := <-c.winningHashChan:
+			go c.prefetchCandidate(hash)
+		case <-c.quit:
+			return
 		}
 	}
 }
@@ -178,58 +300,126 @@ func (c *Chain) propagateBlock(blk block.Block) error {
 }
 
 func (c *Chain) addBidder(tx *transactions.Bid, startHeight uint64) {
+	c.addBid(newBid(tx, startHeight))
+}
+
+// newBid returns the bid of a bidding transaction accepted at startHeight
+func newBid(tx *transactions.Bid, startHeight uint64) user.Bid {
 	var bid user.Bid
 	x := calculateXFromBytes(tx.Outputs[0].Commitment.Bytes(), tx.M)
 	copy(bid.X[:], x.Bytes())
 	copy(bid.M[:], tx.M)
 	bid.EndHeight = startHeight + tx.Lock
-	c.addBid(bid)
+	return bid
 }
 
-func (c *Chain) Close() error {
-	log.Info("Close database")
-	drvr, err := database.From(cfg.Get().Database.Driver)
-	if err != nil {
-		return err
+// storeBids persists the bids of txs, accepted at startHeight, and removes
+// the ones expired by then
+func storeBids(t database.Transaction, txs []transactions.Transaction, startHeight uint64) error {
+	for _, tx := range txs {
+		if tx, ok := tx.(*transactions.Bid); ok {
+			bid := newBid(tx, startHeight)
+			if err := t.StoreBid(bid.X[:], bid.M[:], bid.EndHeight); err != nil {
+				return err
+			}
+		}
 	}
 
-	return drvr.Close()
+	return t.DeleteExpiredBids(startHeight)
+}
+
+// Close shuts the Chain down. It unsubscribes from the event bus, stops
+// Listen, waits for the blocks being accepted to be written, and closes the
+// database. Blocks arriving afterwards are refused with ErrClosed.
+func (c *Chain) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		for topic, id := range c.subscriptions {
+			c.eventBus.Unsubscribe(topic, id)
+		}
+
+		// Waiting for the running Listen, if any, to return
+		close(c.quit)
+		c.listenLock.Lock()
+		c.listenLock.Unlock()
+
+		// The blocks synced and accepted hold these locks until written
+		c.syncLock.Lock()
+		defer c.syncLock.Unlock()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.closed = true
+
+		log.Info("Close database")
+		var drvr database.Driver
+		drvr, err = database.From(cfg.Get().Database.Driver)
+		if err != nil {
+			return
+		}
+
+		err = drvr.Close()
+	})
+	return err
 }
 
-func (c *Chain) onAcceptBlock(m bytes.Buffer) error {
+// onAcceptBlock handles the blocks received from the network. The stages of
+// their acceptance are traced under the correlation ID of the message.
+func (c *Chain) onAcceptBlock(m bytes.Buffer, id tracing.ID) error {
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&m, blk); err != nil {
+		return err
+	}
+
 	// Ignore blocks from peers if we are only one behind - we are most
-	// likely just about to finalize consensus.
+	// likely just about to finalize consensus. The previous blocks of the
+	// orphans were requested, and are accepted nonetheless.
 	// TODO: we should probably just accept it if consensus was not
 	// started yet
-	if !c.counter.IsSyncing() {
+	//
+	// Blocks competing with the main chain are kept whether syncing or not,
+	// so that the chain can switch to a heavier fork, see acceptFork.
+	syncing := c.counter.IsSyncing()
+	if !syncing && !c.orphans.awaits(blk.Header.Hash) && !c.buildsOnFork(*blk) {
 		return nil
 	}
 
 	// If we are more than one block behind, stop the consensus
-	c.eventBus.Publish(topics.StopConsensus, new(bytes.Buffer))
+	if syncing {
+		c.eventBus.Publish(topics.StopConsensus, new(bytes.Buffer))
+	}
 
-	// Accept the block
-	blk := block.NewBlock()
-	if err := marshalling.UnmarshalBlock(&m, blk); err != nil {
-		return err
+	span := tracing.StartSpan(id, "block.receive")
+	defer span.Finish()
+	span.SetAttribute("height", strconv.FormatUint(blk.Header.Height, 10))
+	span.SetAttribute("hash", hex.EncodeToString(blk.Header.Hash))
+	if origin := tracing.Origin(id); origin != "" {
+		span.SetAttribute("net.peer.name", origin)
 	}
 
-	// This will decrement the sync counter
-	if err := c.AcceptBlock(*blk); err != nil {
+	c.syncLock.Lock()
+	defer c.syncLock.Unlock()
+
+	// Accept the block, and the orphans building on it. This will
+	// decrement the sync counter. Blocks can arrive out of order, from
+	// several peers, so the ones ahead of the tip are held as orphans
+	// until the blocks before them are accepted.
+	if err := c.acceptTraced(*blk, tracing.Origin(id), span); err != nil {
 		return err
 	}
 
 	// If we are no longer syncing after accepting this block,
 	// request a certificate and intermediate block for the
 	// second to last round.
-	if !c.counter.IsSyncing() {
-		blk, cert, err := c.requestRoundResults(blk.Header.Height + 1)
+	if syncing && !c.counter.IsSyncing() {
+		blk, cert, err := c.requestRoundResults(c.tipHeight() + 1)
 		if err != nil {
 			return err
 		}
 
+		c.roundLock.Lock()
 		c.intermediateBlock = blk
 		c.lastCertificate = cert
+		c.roundLock.Unlock()
 
 		// Once received, we can re-start consensus.
 		// This sets off a chain of processing which goes from sending the
@@ -242,71 +432,144 @@ func (c *Chain) onAcceptBlock(m bytes.Buffer) error {
 	return nil
 }
 
+// buildsOnFork returns whether blk is a fork, see isFork
+func (c *Chain) buildsOnFork(blk block.Block) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isFork(blk)
+}
+
 // AcceptBlock will accept a block if
 // 1. We have not seen it before
 // 2. All stateless and statefull checks are true
 // Returns nil, if checks passed and block was successfully saved
+//
+// Blocks building on a known block other than the tip are kept as competing
+// tips, and the chain switches to them once they are heavier, see acceptFork.
+// Blocks whose previous block is unknown are held, without error, until it
+// is accepted, see addOrphan
 func (c *Chain) AcceptBlock(blk block.Block) error {
+	return c.acceptTraced(blk, "", nil)
+}
+
+// acceptTraced accepts blk, received from the peer at origin, tracing the
+// stages of its acceptance as children of span. origin is empty for the
+// blocks which do not come from a peer, and span can be nil.
+func (c *Chain) acceptTraced(blk block.Block, origin string, span *tracing.Span) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.closed {
+		return ErrClosed
+	}
+
+	if c.isOrphan(blk) {
+		span.SetAttribute("orphan", "true")
+		c.addOrphan(blk, origin)
+		return nil
+	}
+
+	var err error
+	if c.isFork(blk) {
+		span.SetAttribute("fork", "true")
+		err = c.acceptFork(blk, origin)
+	} else {
+		err = c.acceptBlock(blk, origin, span)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.acceptOrphans(blk.Header.Hash)
+	return nil
+}
+
+// acceptBlock verifies blk and appends it to the chain. If it fails
+// verification, origin, the peer which sent it, is told about it. The stages
+// are traced as children of span, which can be nil. The caller must hold c.mu.
+func (c *Chain) acceptBlock(blk block.Block, origin string, span *tracing.Span) error {
 	field := logger.Fields{"process": "accept block"}
 	l := log.WithFields(field)
 
 	l.Trace("verifying block")
 
-	// 1. Check that stateless and stateful checks pass
-	if err := verifiers.CheckBlock(c.db, c.prevBlock, blk); err != nil {
+	// 1. Check that stateless and stateful checks pass, and that the
+	// certificate is valid.
+	// The certificate check should avoid a possible race condition between
+	// accepting two blocks at the same height, as the probability of the
+	// committee creating two valid certificates for the same round is
+	// negligible.
+	verifySpan := span.StartChild("block.verify")
+	start := time.Now()
+	err := c.verifyBlock(blk)
+	verifyLatency.ObserveSince(start)
+	if err != nil {
 		l.WithError(err).Warnln("block verification failed")
+		verifySpan.SetAttribute("error", err.Error())
+		verifySpan.Finish()
+		blocksRejected.Inc()
+		c.rejectBlock(blk, origin, err)
 		return err
 	}
+	verifySpan.Finish()
 
-	// 2. Check the certificate
-	// This check should avoid a possible race condition between accepting two blocks
-	// at the same height, as the probability of the committee creating two valid certificates
-	// for the same round is negligible.
-	l.Trace("verifying block certificate")
-	if err := verifiers.CheckBlockCertificate(*c.p, blk); err != nil {
-		l.WithError(err).Warnln("certificate verification failed")
-		return err
-	}
-
-	// 3. Add provisioners and block generators
+	// 2. Add provisioners and block generators
 	l.Trace("adding consensus nodes")
-	// We set the stake start height as blk.Header.Height+2.
-	// This is because, once this block is accepted, the consensus will
-	// be 2 rounds ahead of this current block height. As a result,
-	// if we pick the start height to just be the block height, we
-	// run into some inconsistencies when accepting the next block,
-	// as the certificate could've been made with a different committee.
-	c.addConsensusNodes(blk.Txs, blk.Header.Height+2)
-
-	// 4. Store block in database
+	// Stakes count in the committees once they matured, see
+	// addConsensusNodes
+	c.addConsensusNodes(blk.Txs, blk.Header.Height)
+
+	// 3. Store block, and its bids, in database
 	l.Trace("storing block in db")
-	err := c.db.Update(func(t database.Transaction) error {
-		return t.StoreBlock(&blk)
+	storeSpan := span.StartChild("block.store")
+	stored := blk
+	if c.headersOnly {
+		stored = consensusTxsOnly(blk)
+	}
+
+	err = c.db.Update(func(t database.Transaction) error {
+		if err := t.StoreBlock(&stored); err != nil {
+			return err
+		}
+
+		return storeBids(t, blk.Txs, blk.Header.Height+2)
 	})
 
+	storeSpan.Finish()
 	if err != nil {
 		l.WithError(err).Errorln("block storing failed")
 		return err
 	}
 
+	if blk.Header.Height == c.prevBlock.Header.Height+1 {
+		if interval := blk.Header.Timestamp - c.prevBlock.Header.Timestamp; interval >= 0 {
+			blockInterval.Observe(float64(interval))
+		}
+	}
+
 	c.prevBlock = blk
+	blocksAccepted.Inc()
+	chainHeight.Set(float64(blk.Header.Height))
+	c.pruneVerified(blk.Header.Hash)
+	c.pruneForks()
 
-	// 5. Gossip advertise block Hash
+	// 4. Gossip advertise block Hash
 	l.Trace("gossiping block")
-	if err := c.advertiseBlock(blk); err != nil {
+	advertiseSpan := span.StartChild("block.advertise")
+	err = c.advertiseBlock(blk)
+	advertiseSpan.Finish()
+	if err != nil {
 		l.WithError(err).Errorln("block advertising failed")
 		return err
 	}
 
-	// 6. Remove expired provisioners and bids
+	// 5. Remove expired provisioners and bids
 	l.Trace("removing expired consensus transactions")
 	c.removeExpiredProvisioners(blk.Header.Height)
 	c.removeExpiredBids(blk.Header.Height + 2)
 
-	// 7. Notify other subsystems for the accepted block
+	// 6. Notify other subsystems for the accepted block
 	// Subsystems listening for this topic:
 	// mempool.Mempool
 	// consensus.generation.broker
@@ -323,14 +586,36 @@ func (c *Chain) AcceptBlock(blk block.Block) error {
 	return nil
 }
 
+// consensusTxsOnly returns blk with only the txs the committees are drawn
+// from, as stored in headers-only mode
+func consensusTxsOnly(blk block.Block) block.Block {
+	stripped := blk
+	stripped.Txs = nil
+	for _, tx := range blk.Txs {
+		switch tx.Type() {
+		case transactions.StakeType, transactions.BidType:
+			stripped.Txs = append(stripped.Txs, tx)
+		}
+	}
+	return stripped
+}
+
 func (c *Chain) onInitialization(bytes.Buffer) error {
 	return c.sendRoundUpdate()
 }
 
 func (c *Chain) sendRoundUpdate() error {
+	c.roundLock.RLock()
+	intermediateBlock := c.intermediateBlock
+	c.roundLock.RUnlock()
+	// Dropped on reorganization, until the round results are received
+	if intermediateBlock == nil {
+		return errors.New("no intermediate block known")
+	}
+
 	buf := new(bytes.Buffer)
 	roundBytes := make([]byte, 8)
-	binary.LittleEndian.PutUint64(roundBytes, c.intermediateBlock.Header.Height+1)
+	binary.LittleEndian.PutUint64(roundBytes, intermediateBlock.Header.Height+1)
 	if _, err := buf.Write(roundBytes); err != nil {
 		return err
 	}
@@ -348,11 +633,11 @@ func (c *Chain) sendRoundUpdate() error {
 		return err
 	}
 
-	if err := encoding.WriteBLS(buf, c.intermediateBlock.Header.Seed); err != nil {
+	if err := encoding.WriteBLS(buf, intermediateBlock.Header.Seed); err != nil {
 		return err
 	}
 
-	if err := encoding.Write256(buf, c.intermediateBlock.Header.Hash); err != nil {
+	if err := encoding.Write256(buf, intermediateBlock.Header.Hash); err != nil {
 		return err
 	}
 
@@ -360,40 +645,180 @@ func (c *Chain) sendRoundUpdate() error {
 	return nil
 }
 
-func (c *Chain) addConsensusNodes(txs []transactions.Transaction, startHeight uint64) {
+// addConsensusNodes adds the provisioners and bidders of the txs of the block
+// at height.
+//
+// Stakes count in the committees once they have the stake maturity of the
+// network as confirmations, and until their lock expires. The maturity is at least 2
+// because, once this block is accepted, the consensus will be 2 rounds ahead
+// of this current block height. As a result, if we pick the start height to
+// just be the block height, we run into some inconsistencies when accepting
+// the next block, as the certificate could've been made with a different
+// committee. Bids are valid two rounds after their block.
+func (c *Chain) addConsensusNodes(txs []transactions.Transaction, height uint64) {
 	field := logger.Fields{"process": "accept block"}
 	l := log.WithFields(field)
 
+	params := cfg.GetConsensusParams()
 	for _, tx := range txs {
 		switch tx.Type() {
 		case transactions.StakeType:
 			stake := tx.(*transactions.Stake)
-			if err := c.addProvisioner(stake.PubKeyEd, stake.PubKeyBLS, stake.Outputs[0].EncryptedAmount.BigInt().Uint64(), startHeight, startHeight+stake.Lock-2); err != nil {
+			if err := c.addProvisioner(stake.PubKeyEd, stake.PubKeyBLS, stake.Outputs[0].EncryptedAmount.BigInt().Uint64(), params.StakeStart(height), height+stake.Lock); err != nil {
 				l.Errorf("adding provisioner failed: %s", err.Error())
 			}
 		case transactions.BidType:
 			bid := tx.(*transactions.Bid)
-			c.addBidder(bid, startHeight)
+			c.addBidder(bid, height+2)
 		}
 	}
 }
 
-func (c *Chain) verifyCandidateBlock(r rpcbus.Request) {
+func (c *Chain) verifyCandidateBlock(r rpcbus.Request) (bytes.Buffer, error) {
 	// We need to verify the candidate block against the newest
 	// intermediate block. The intermediate block would be the most
 	// recent block before the candidate.
-	if c.intermediateBlock == nil {
-		r.RespChan <- rpcbus.Response{bytes.Buffer{}, errors.New("no intermediate block hash known")}
-		return
+	c.roundLock.RLock()
+	intermediateBlock := c.intermediateBlock
+	c.roundLock.RUnlock()
+	if intermediateBlock == nil {
+		return bytes.Buffer{}, errors.New("no intermediate block hash known")
+	}
+
+	// The txs of the candidates can not be verified without the outputs and
+	// key images they spend, so none is voted for
+	if c.headersOnly {
+		return bytes.Buffer{}, ErrHeadersOnly
 	}
 
 	blk := block.NewBlock()
 	if err := marshalling.UnmarshalBlock(&r.Params, blk); err != nil {
-		r.RespChan <- rpcbus.Response{bytes.Buffer{}, err}
+		return bytes.Buffer{}, err
+	}
+
+	if err := verifiers.CheckBlock(c.db, *intermediateBlock, *blk, nil, c.now()); err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	c.markVerified(*blk)
+	return bytes.Buffer{}, nil
+}
+
+// verifyBlock verifies blk in full, unless it was verified as a candidate on
+// top of the current tip. Then, only the header, the certificate, which
+// candidates do not have, and the spends, against the blocks accepted since,
+// are checked.
+//
+// In headers-only mode, the txs are not verified. The blocks marked as
+// verified are the ones rolled back, which are accepted again without their
+// txs, and are not checked again.
+func (c *Chain) verifyBlock(blk block.Block) error {
+	if c.checkpointHash != nil && blk.Header.Height <= c.checkpointHeight {
+		if blk.Header.Height == c.checkpointHeight && !bytes.Equal(blk.Header.Hash, c.checkpointHash) {
+			return errors.New("block does not match the checkpoint")
+		}
+
+		if bytes.Equal(c.trusted[blk.Header.Height], blk.Header.Hash) {
+			return c.verifyTrusted(blk)
+		}
+	}
+
+	verified := c.takeVerified(blk, c.prevBlock.Header.Hash)
+	if c.headersOnly {
+		if verified {
+			return nil
+		}
+		return verifiers.CheckHeadersOnly(c.db, c.prevBlock, blk, c.p, c.now())
+	}
+
+	if !verified {
+		return verifiers.CheckBlock(c.db, c.prevBlock, blk, c.p, c.now())
+	}
+
+	if err := verifiers.CheckBlockHeader(c.prevBlock, blk); err != nil {
+		return err
+	}
+
+	if err := verifiers.CheckBlockCertificate(*c.p, blk); err != nil {
+		return err
+	}
+
+	return verifiers.CheckBlockSpends(c.db, blk)
+}
+
+// verifyTrusted checks a block of the header chain known to lead to the
+// checkpoint. The proofs of its txs are not verified, but its certificate
+// is, so that a block with a forged header is not accepted.
+func (c *Chain) verifyTrusted(blk block.Block) error {
+	if err := verifiers.CheckBlockHeader(c.prevBlock, blk); err != nil {
+		return err
+	}
+
+	if err := verifiers.CheckBlockCertificate(*c.p, blk); err != nil {
+		return err
+	}
+
+	if c.headersOnly {
+		return nil
+	}
+
+	return verifiers.CheckBlockSpends(c.db, blk)
+}
+
+// markVerified remembers that blk passed verification on top of its previous
+// block
+func (c *Chain) markVerified(blk block.Block) {
+	digest, err := verifiedDigest(blk)
+	if err != nil {
+		return
+	}
+
+	c.verifiedLock.Lock()
+	defer c.verifiedLock.Unlock()
+	c.verified[string(digest)] = blk.Header.PrevBlockHash
+}
+
+// takeVerified returns whether blk, with the same txs, passed verification
+// on top of prevHash, and forgets about it
+func (c *Chain) takeVerified(blk block.Block, prevHash []byte) bool {
+	digest, err := verifiedDigest(blk)
+	if err != nil {
+		return false
+	}
+
+	c.verifiedLock.Lock()
+	defer c.verifiedLock.Unlock()
+	verifiedOn, ok := c.verified[string(digest)]
+	delete(c.verified, string(digest))
+	return ok && bytes.Equal(verifiedOn, prevHash)
+}
+
+// verifiedDigest hashes the hash of blk along with the full encoding of its
+// txs, so that a block is only taken for a verified candidate if it carries
+// the same txs, witness data included. The certificate is left out, as the
+// candidates have none.
+func verifiedDigest(blk block.Block) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.Write(blk.Header.Hash)
+	for _, tx := range blk.Txs {
+		if err := marshalling.MarshalTx(buf, tx); err != nil {
+			return nil, err
+		}
 	}
 
-	err := verifiers.CheckBlock(c.db, *c.intermediateBlock, *blk)
-	r.RespChan <- rpcbus.Response{bytes.Buffer{}, err}
+	return crypto.Sha3256(buf.Bytes())
+}
+
+// pruneVerified forgets about the candidates which were not verified on top
+// of the new tip, as they can no longer be accepted
+func (c *Chain) pruneVerified(tip []byte) {
+	c.verifiedLock.Lock()
+	defer c.verifiedLock.Unlock()
+	for hash, verifiedOn := range c.verified {
+		if !bytes.Equal(verifiedOn, tip) {
+			delete(c.verified, hash)
+		}
+	}
 }
 
 // Send Inventory message to all peers
@@ -414,7 +839,47 @@ func (c *Chain) advertiseBlock(b block.Block) error {
 	return nil
 }
 
-// TODO: consensus data should be persisted to disk, to decrease
+// Send a Reject message to origin, the peer which sent the block, about the
+// failed validation. Nothing is sent for the blocks which do not come from a
+// peer.
+func (c *Chain) rejectBlock(b block.Block, origin string, reason error) {
+	if origin == "" {
+		return
+	}
+
+	msg := peermsg.NewReject(topics.Block, rejectCode(reason), reason.Error(), b.Header.Hash)
+
+	buf := new(bytes.Buffer)
+	if err := msg.Encode(buf); err != nil {
+		log.WithError(err).Warnln("could not encode reject message")
+		return
+	}
+
+	if err := topics.Prepend(buf, topics.Reject); err != nil {
+		log.WithError(err).Warnln("could not encode reject message")
+		return
+	}
+
+	if err := c.sendToPeer(origin, buf); err != nil {
+		log.WithError(err).WithField("peer", origin).Warnln("could not send reject message")
+	}
+}
+
+// rejectCode tells the peers why a block failed verification
+func rejectCode(reason error) peermsg.RejectCode {
+	var exists *verifiers.ErrBlockExists
+	var doubleSpend *verifiers.ErrDoubleSpend
+	switch {
+	case errors.As(reason, &exists):
+		return peermsg.RejectDuplicate
+	case errors.As(reason, &doubleSpend):
+		return peermsg.RejectDoubleSpend
+	default:
+		return peermsg.RejectInvalid
+	}
+}
+
+// TODO: provisioners should be persisted to disk as well, to decrease
 // startup times
 func (c *Chain) restoreConsensusData() {
 	var currentHeight uint64
@@ -428,11 +893,35 @@ func (c *Chain) restoreConsensusData() {
 		currentHeight = 0
 	}
 
+	// Bids are persisted as blocks get accepted. Databases written before
+	// they were, and the ones rolled back, have none stored, in which case
+	// they are rebuilt from the blocks.
+	rebuildBids := !c.restoreBids(currentHeight + 1)
+	var rebuilt []user.Bid
+
 	searchingHeight := uint64(0)
 	if currentHeight > transactions.MaxLockTime {
 		searchingHeight = currentHeight - transactions.MaxLockTime
 	}
 
+	// The blocks below the snapshot are stored without their txs, so the
+	// provisioners and bids in them are restored from the snapshot
+	if s := c.snapshot; s != nil && currentHeight >= s.Tip.Header.Height {
+		restoreProvisioners(c.p, s, currentHeight)
+		for _, b := range s.Bids {
+			if rebuildBids && b.EndHeight > currentHeight {
+				bid := userBid(b)
+				c.addBid(bid)
+				rebuilt = append(rebuilt, bid)
+			}
+		}
+
+		if searchingHeight <= s.Tip.Header.Height {
+			searchingHeight = s.Tip.Header.Height + 1
+		}
+	}
+
+	params := cfg.GetConsensusParams()
 	for {
 		var blk *block.Block
 		err := c.db.View(func(t database.Transaction) error {
@@ -455,43 +944,110 @@ func (c *Chain) restoreConsensusData() {
 				// Only add them if their stake is still valid
 				if searchingHeight+t.Lock > currentHeight {
 					amount := t.Outputs[0].EncryptedAmount.BigInt().Uint64()
-					c.addProvisioner(t.PubKeyEd, t.PubKeyBLS, amount, searchingHeight+2, searchingHeight+t.Lock)
+					c.addProvisioner(t.PubKeyEd, t.PubKeyBLS, amount, params.StakeStart(searchingHeight), searchingHeight+t.Lock)
 				}
 			case *transactions.Bid:
 				// TODO: The commitment to D is turned (in quite awful fashion) from a Point into a Scalar here,
 				// to work with the `zkproof` package. Investigate if we should change this (reserve for testnet v2,
 				// as this is most likely a consensus-breaking change)
-				if searchingHeight+t.Lock > currentHeight {
-					c.addBidder(t, searchingHeight)
+				if rebuildBids && searchingHeight+t.Lock > currentHeight {
+					bid := newBid(t, searchingHeight)
+					c.addBid(bid)
+					rebuilt = append(rebuilt, bid)
 				}
 			}
 		}
 
 		searchingHeight++
 	}
-	return
+
+	if len(rebuilt) > 0 {
+		err := c.db.Update(func(t database.Transaction) error {
+			for _, bid := range rebuilt {
+				if err := t.StoreBid(bid.X[:], bid.M[:], bid.EndHeight); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		if err != nil {
+			log.WithError(err).Warnln("could not store the bids rebuilt")
+		}
+	}
+}
+
+// restoreBids fills the BidList with the bids stored which expire at height
+// or later. It returns false if none are stored.
+func (c *Chain) restoreBids(height uint64) bool {
+	var bids []database.Bid
+	err := c.db.View(func(t database.Transaction) error {
+		var err error
+		bids, err = t.FetchBids(height)
+		return err
+	})
+
+	if err != nil {
+		log.WithError(err).Warnln("could not fetch the bids stored")
+		return false
+	}
+
+	for _, b := range bids {
+		c.addBid(userBid(b))
+	}
+
+	return len(bids) > 0
+}
+
+// userBid converts a bid stored in the database
+func userBid(b database.Bid) user.Bid {
+	var bid user.Bid
+	copy(bid.X[:], b.X)
+	copy(bid.M[:], b.M)
+	bid.EndHeight = b.EndHeight
+	return bid
 }
 
-// RemoveExpired removes Provisioners which stake expired
+// removeExpiredProvisioners removes the stakes expired before round, and the
+// provisioners left without stakes. Each stake removed is published on the
+// StakeExpired topic.
 func (c *Chain) removeExpiredProvisioners(round uint64) {
+	var expired []user.StakeExpiry
 	for pk, member := range c.p.Members {
 		for i := 0; i < len(member.Stakes); i++ {
 			if member.Stakes[i].EndHeight < round {
+				expiry := user.StakeExpiry{PubKeyBLS: member.PublicKeyBLS, Stake: member.Stakes[i]}
 				member.RemoveStake(i)
 				// If they have no stakes left, we should remove them entirely.
 				if len(member.Stakes) == 0 {
 					c.removeProvisioner([]byte(pk))
+					expiry.Removed = true
 				}
+				expired = append(expired, expiry)
 
 				// Reset index
 				i = -1
 			}
 		}
 	}
+
+	for _, expiry := range expired {
+		buf := new(bytes.Buffer)
+		if err := user.MarshalStakeExpiry(buf, expiry); err != nil {
+			log.WithError(err).Errorln("could not encode the expired stake")
+			continue
+		}
+		c.eventBus.Publish(topics.StakeExpired, buf)
+	}
 }
 
 // addProvisioner will add a Member to the Provisioners by using the bytes of a BLS public key.
 func (c *Chain) addProvisioner(pubKeyEd, pubKeyBLS []byte, amount, startHeight, endHeight uint64) error {
+	return addProvisioner(c.p, pubKeyEd, pubKeyBLS, amount, startHeight, endHeight)
+}
+
+// addProvisioner adds a stake to p, and the Member staking it if new
+func addProvisioner(p *user.Provisioners, pubKeyEd, pubKeyBLS []byte, amount, startHeight, endHeight uint64) error {
 	if len(pubKeyEd) != 32 {
 		return fmt.Errorf("public key is %v bytes long instead of 32", len(pubKeyEd))
 	}
@@ -504,22 +1060,22 @@ func (c *Chain) addProvisioner(pubKeyEd, pubKeyBLS []byte, amount, startHeight,
 	stake := user.Stake{amount, startHeight, endHeight}
 
 	// Check for duplicates
-	_, inserted := c.p.Set.IndexOf(pubKeyBLS)
+	_, inserted := p.Set.IndexOf(pubKeyBLS)
 	if inserted {
 		// If they already exist, just add their new stake
-		c.p.Members[i].AddStake(stake)
+		p.Members[i].AddStake(stake)
 		return nil
 	}
 
 	// This is a new provisioner, so let's initialize the Member struct and add them to the list
-	c.p.Set.Insert(pubKeyBLS)
+	p.Set.Insert(pubKeyBLS)
 	m := &user.Member{}
 	m.PublicKeyEd = ed25519.PublicKey(pubKeyEd)
 
 	m.PublicKeyBLS = pubKeyBLS
 	m.AddStake(stake)
 
-	c.p.Members[i] = m
+	p.Members[i] = m
 	return nil
 }
 
@@ -582,23 +1138,27 @@ func (c *Chain) removeExpiredBids(round uint64) {
 
 func (c *Chain) handleCertificateMessage(cMsg certMsg) {
 	// Set latest certificate
+	c.roundLock.Lock()
 	c.lastCertificate = cMsg.cert
+	c.roundLock.Unlock()
 
 	// Fetch new intermediate block and corresponding certificate
-	candidateBuf, err := c.rpcBus.Call(rpcbus.GetCandidate, rpcbus.Request{*bytes.NewBuffer(cMsg.hash), make(chan rpcbus.Response, 1)}, 5*time.Second)
-	if err != nil {
-		// If the we can't get the block, we will fall
-		// back and catch up later.
-		return
-	}
-
-	cm := candidate.NewCandidate()
-	if err := candidate.Decode(&candidateBuf, cm); err != nil {
-		log.WithError(err).Warnln("could not decode candidate message")
-		return
+	cm := c.takePrefetched(cMsg.hash)
+	if cm == nil {
+		var err error
+		if cm, err = c.fetchCandidate(cMsg.hash); err != nil {
+			// The candidate was requested from our peers, but none of
+			// them answered in time. We will fall back and catch up
+			// later.
+			log.WithError(err).Warnln("could not fetch the winning candidate")
+			return
+		}
 	}
 
-	if c.intermediateBlock == nil {
+	c.roundLock.RLock()
+	missing := c.intermediateBlock == nil
+	c.roundLock.RUnlock()
+	if missing {
 		// If we're missing the intermediate block, we will also fall
 		// back and catch up later.
 		return
@@ -610,7 +1170,9 @@ func (c *Chain) handleCertificateMessage(cMsg certMsg) {
 	}
 
 	// Set new intermediate block
+	c.roundLock.Lock()
 	c.intermediateBlock = cm.Block
+	c.roundLock.Unlock()
 
 	// Notify mempool
 	buf := new(bytes.Buffer)
@@ -623,9 +1185,58 @@ func (c *Chain) handleCertificateMessage(cMsg certMsg) {
 	go c.sendRoundUpdate()
 }
 
+// fetchCandidate requests the candidate with the given hash from the candidate
+// broker, which asks the network for it if needed.
+func (c *Chain) fetchCandidate(hash []byte) (*candidate.Candidate, error) {
+	candidateBuf, err := c.rpcBus.Call(rpcbus.GetCandidate, rpcbus.NewRequest(*bytes.NewBuffer(hash)), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	cm := candidate.NewCandidate()
+	if err := candidate.Decode(&candidateBuf, cm); err != nil {
+		return nil, err
+	}
+
+	return cm, nil
+}
+
+// prefetchCandidate fetches the candidate on which the first step of
+// reduction converged, so that it is at hand when its certificate comes in.
+// The candidate was already verified by the first step, before halting.
+func (c *Chain) prefetchCandidate(hash []byte) {
+	cm, err := c.fetchCandidate(hash)
+	if err != nil {
+		log.WithError(err).Debugln("could not prefetch the winning candidate")
+		return
+	}
+
+	c.prefetchLock.Lock()
+	c.prefetched = cm
+	c.prefetchLock.Unlock()
+}
+
+// takePrefetched returns the prefetched candidate if it has the given hash,
+// and nil otherwise. The prefetched candidate is cleared in both cases, as a
+// certificate was reached for the round.
+func (c *Chain) takePrefetched(hash []byte) *candidate.Candidate {
+	c.prefetchLock.Lock()
+	defer c.prefetchLock.Unlock()
+	cm := c.prefetched
+	c.prefetched = nil
+	if cm == nil || !bytes.Equal(cm.Block.Header.Hash, hash) {
+		return nil
+	}
+
+	return cm
+}
+
 func (c *Chain) finalizeIntermediateBlock(cert *block.Certificate) error {
+	c.roundLock.Lock()
 	c.intermediateBlock.Header.Certificate = cert
-	return c.AcceptBlock(*c.intermediateBlock)
+	blk := *c.intermediateBlock
+	c.roundLock.Unlock()
+	return c.AcceptBlock(blk)
 }
 
 // Send out a query for agreement messages and an intermediate block.
@@ -665,16 +1276,18 @@ func (c *Chain) requestRoundResults(round uint64) (*block.Block, *block.Certific
 				continue
 			}
 
-			// Check block and certificate for correctness
-			if err := verifiers.CheckBlock(c.db, c.prevBlock, *blk); err != nil {
-				continue
-			}
-
 			// Certificate needs to be on a block to be verified.
 			// Since this certificate is supposed to be for the
 			// intermediate block, we can just put it on there.
 			blk.Header.Certificate = cert
-			if err := verifiers.CheckBlockCertificate(*c.p, *blk); err != nil {
+
+			// Check block and certificate for correctness
+			check := verifiers.CheckBlock
+			if c.headersOnly {
+				check = verifiers.CheckHeadersOnly
+			}
+
+			if err := check(c.db, c.prevBlock, *blk, c.p, c.now()); err != nil {
 				continue
 			}
 
@@ -683,7 +1296,7 @@ func (c *Chain) requestRoundResults(round uint64) (*block.Block, *block.Certific
 	}
 }
 
-func (c *Chain) provideLastBlock(r rpcbus.Request) {
+func (c *Chain) provideLastBlock(rpcbus.Request) (bytes.Buffer, error) {
 	buf := new(bytes.Buffer)
 
 	c.mu.RLock()
@@ -691,62 +1304,60 @@ func (c *Chain) provideLastBlock(r rpcbus.Request) {
 	c.mu.RUnlock()
 
 	err := marshalling.MarshalBlock(buf, &prevBlock)
-	r.RespChan <- rpcbus.Response{*buf, err}
+	return *buf, err
 }
 
-func (c *Chain) provideLastCertificate(r rpcbus.Request) {
+func (c *Chain) provideLastCertificate(rpcbus.Request) (bytes.Buffer, error) {
+	c.roundLock.RLock()
+	defer c.roundLock.RUnlock()
 	if c.lastCertificate == nil {
-		r.RespChan <- rpcbus.Response{bytes.Buffer{}, errors.New("no last certificate present")}
-		return
+		return bytes.Buffer{}, errors.New("no last certificate present")
 	}
 
 	buf := new(bytes.Buffer)
 	err := marshalling.MarshalCertificate(buf, c.lastCertificate)
-	r.RespChan <- rpcbus.Response{*buf, err}
+	return *buf, err
 }
 
-func (c *Chain) provideRoundResults(r rpcbus.Request) {
+func (c *Chain) provideRoundResults(r rpcbus.Request) (bytes.Buffer, error) {
+	c.roundLock.RLock()
+	defer c.roundLock.RUnlock()
 	if c.intermediateBlock == nil || c.lastCertificate == nil {
-		r.RespChan <- rpcbus.Response{bytes.Buffer{}, errors.New("no intermediate block or certificate currently known")}
-		return
+		return bytes.Buffer{}, errors.New("no intermediate block or certificate currently known")
 	}
 
 	if r.Params.Len() < 8 {
-		r.RespChan <- rpcbus.Response{bytes.Buffer{}, errors.New("round cannot be read from request param")}
-		return
+		return bytes.Buffer{}, errors.New("round cannot be read from request param")
 	}
 
 	round := binary.LittleEndian.Uint64(r.Params.Bytes())
 	if round != c.intermediateBlock.Header.Height {
-		r.RespChan <- rpcbus.Response{bytes.Buffer{}, errors.New("no intermediate block and certificate for the given round")}
-		return
+		return bytes.Buffer{}, errors.New("no intermediate block and certificate for the given round")
 	}
 
 	buf := new(bytes.Buffer)
 	if err := marshalling.MarshalBlock(buf, c.intermediateBlock); err != nil {
-		r.RespChan <- rpcbus.Response{bytes.Buffer{}, err}
-		return
+		return bytes.Buffer{}, err
 	}
 
 	if err := marshalling.MarshalCertificate(buf, c.lastCertificate); err != nil {
-		r.RespChan <- rpcbus.Response{bytes.Buffer{}, err}
-		return
+		return bytes.Buffer{}, err
 	}
 
-	r.RespChan <- rpcbus.Response{*buf, nil}
+	return *buf, nil
 }
 
-func (c *Chain) provideSyncProgress(r rpcbus.Request) {
-	if c.highestSeen == 0 {
-		r.RespChan <- rpcbus.Response{*bytes.NewBufferString("0"), nil}
-		return
+func (c *Chain) provideSyncProgress(rpcbus.Request) (bytes.Buffer, error) {
+	highestSeen := atomic.LoadUint64(&c.highestSeen)
+	if highestSeen == 0 {
+		return *bytes.NewBufferString("0"), nil
 	}
 
 	c.mu.RLock()
 	prevBlockHeight := c.prevBlock.Header.Height
 	c.mu.RUnlock()
 
-	progressPercentage := (float64(prevBlockHeight) / float64(c.highestSeen)) * 100
+	progressPercentage := (float64(prevBlockHeight) / float64(highestSeen)) * 100
 
 	// Avoiding strange output when the chain can be ahead of the highest
 	// seen block, as in most cases, consensus terminates before we see
@@ -755,7 +1366,185 @@ func (c *Chain) provideSyncProgress(r rpcbus.Request) {
 		progressPercentage = 100
 	}
 
-	r.RespChan <- rpcbus.Response{*bytes.NewBufferString(fmt.Sprintf("%.2f", progressPercentage)), nil}
+	return *bytes.NewBufferString(fmt.Sprintf("%.2f", progressPercentage)), nil
+}
+
+// provideProvisioners sends the current provisioner set, as encoded by
+// user.MarshalProvisioners
+func (c *Chain) provideProvisioners(rpcbus.Request) (bytes.Buffer, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	buf, err := c.marshalProvisioners()
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	return *buf, nil
+}
+
+// provideDeployments sends the state of the configured deployments for the
+// next block, as encoded by versionbits.MarshalStatus
+func (c *Chain) provideDeployments(rpcbus.Request) (bytes.Buffer, error) {
+	// The tracker is replaced on rollback
+	c.mu.RLock()
+	height := c.prevBlock.Header.Height + 1
+	versions := c.versions
+	c.mu.RUnlock()
+
+	deployments := versionbits.Deployments()
+	status := make([]versionbits.Status, 0, len(deployments))
+	for _, d := range deployments {
+		state, err := versions.State(d, height)
+		if err != nil {
+			return bytes.Buffer{}, err
+		}
+
+		signals, err := versions.Signals(d, height)
+		if err != nil {
+			return bytes.Buffer{}, err
+		}
+
+		status = append(status, versionbits.Status{Deployment: d, State: state, Signals: signals})
+	}
+
+	buf := new(bytes.Buffer)
+	if err := versionbits.MarshalStatus(buf, status); err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	return *buf, nil
+}
+
+// provideBlockByHash sends the accepted block with the hash in the params
+func (c *Chain) provideBlockByHash(r rpcbus.Request) (bytes.Buffer, error) {
+	var blk *block.Block
+	err := c.db.View(func(t database.Transaction) error {
+		var err error
+		blk, err = t.FetchBlock(r.Params.Bytes())
+		return err
+	})
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	buf := new(bytes.Buffer)
+	err = marshalling.MarshalBlock(buf, blk)
+	return *buf, err
+}
+
+// provideBlockByHeight sends the accepted block at the height in the params,
+// encoded as a little endian uint64
+func (c *Chain) provideBlockByHeight(r rpcbus.Request) (bytes.Buffer, error) {
+	var height uint64
+	if err := encoding.ReadUint64LE(&r.Params, &height); err != nil {
+		return bytes.Buffer{}, errors.New("height cannot be read from request param")
+	}
+
+	var blk *block.Block
+	err := c.db.View(func(t database.Transaction) error {
+		hash, err := t.FetchBlockHashByHeight(height)
+		if err != nil {
+			return err
+		}
+
+		blk, err = t.FetchBlock(hash)
+		return err
+	})
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	buf := new(bytes.Buffer)
+	err = marshalling.MarshalBlock(buf, blk)
+	return *buf, err
+}
+
+// maxHeadersRange is the number of headers sent at most by GetHeadersRange
+const maxHeadersRange = 500
+
+// provideHeadersRange sends the headers of the accepted blocks within the
+// heights in the params, as encoded by rpcbus.MarshalHeadersRangeRequest. The
+// range is cut at the chain tip and at maxHeadersRange headers. The headers
+// are sent after their count, as a varint.
+func (c *Chain) provideHeadersRange(r rpcbus.Request) (bytes.Buffer, error) {
+	var from, to uint64
+	if err := encoding.ReadUint64LE(&r.Params, &from); err != nil {
+		return bytes.Buffer{}, errors.New("range cannot be read from request param")
+	}
+
+	if err := encoding.ReadUint64LE(&r.Params, &to); err != nil {
+		return bytes.Buffer{}, errors.New("range cannot be read from request param")
+	}
+
+	if from > to {
+		return bytes.Buffer{}, fmt.Errorf("invalid range %d-%d", from, to)
+	}
+
+	c.mu.RLock()
+	tip := c.prevBlock.Header.Height
+	c.mu.RUnlock()
+
+	if to > tip {
+		to = tip
+	}
+
+	if to >= from+maxHeadersRange {
+		to = from + maxHeadersRange - 1
+	}
+
+	var headers []*block.Header
+	err := c.db.View(func(t database.Transaction) error {
+		for height := from; height <= to; height++ {
+			hash, err := t.FetchBlockHashByHeight(height)
+			if err != nil {
+				return err
+			}
+
+			header, err := t.FetchBlockHeader(hash)
+			if err != nil {
+				return err
+			}
+			headers = append(headers, header)
+		}
+		return nil
+	})
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteVarInt(buf, uint64(len(headers))); err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	for _, header := range headers {
+		if err := marshalling.MarshalHeader(buf, header); err != nil {
+			return bytes.Buffer{}, err
+		}
+	}
+
+	return *buf, nil
+}
+
+// fetchVersion returns the version of the accepted block at the given height
+func (c *Chain) fetchVersion(height uint64) (uint8, error) {
+	var version uint8
+	err := c.db.View(func(t database.Transaction) error {
+		hash, err := t.FetchBlockHashByHeight(height)
+		if err != nil {
+			return err
+		}
+
+		header, err := t.FetchBlockHeader(hash)
+		if err != nil {
+			return err
+		}
+
+		version = header.Version
+		return nil
+	})
+
+	return version, err
 }
 
 // mocks an intermediate block with a coinbase attributed to a standard
@@ -770,7 +1559,7 @@ func mockFirstIntermediateBlock(prevBlockHeader *block.Header) (*block.Block, er
 
 	tx := mockDeterministicCoinbase()
 	blk.AddTx(tx)
-	if err := blk.SetRoot(); err != nil {
+	if err := marshalling.SetTxRoot(blk); err != nil {
 		return nil, err
 	}
 
@@ -2,23 +2,32 @@ package chain
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/candidate"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	_ "github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/peermsg"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing/chainsync"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 	crypto "github.com/dusk-network/dusk-crypto/hash"
 	"github.com/dusk-network/dusk-wallet/block"
 	"github.com/dusk-network/dusk-wallet/key"
@@ -43,7 +52,7 @@ func TestAcceptFromPeer(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	assert.NoError(t, c.onAcceptBlock(*buf))
+	assert.NoError(t, c.onAcceptBlock(*buf, tracing.None))
 
 	// Function should return before sending the `StopConsensus` message
 	select {
@@ -59,7 +68,7 @@ func TestAcceptFromPeer(t *testing.T) {
 	blk.SetPrevBlock(c.prevBlock.Header)
 	// Strip all but coinbase tx, to avoid unwanted errors
 	blk.Txs = blk.Txs[0:1]
-	blk.SetRoot()
+	_ = marshalling.SetTxRoot(blk)
 	blk.SetHash()
 	buf = new(bytes.Buffer)
 	if err := marshalling.MarshalBlock(buf, blk); err != nil {
@@ -67,7 +76,7 @@ func TestAcceptFromPeer(t *testing.T) {
 	}
 
 	go func() {
-		if err := c.onAcceptBlock(*buf); err.Error() != "request timeout" {
+		if err := c.onAcceptBlock(*buf, tracing.None); err.Error() != "request timeout" {
 			t.Fatal(err)
 		}
 	}()
@@ -147,6 +156,45 @@ func TestAcceptIntermediate(t *testing.T) {
 	assert.Equal(t, blk.Header.Seed, ru.Seed)
 }
 
+// Ensure the candidate on which the first step of reduction converged is
+// fetched ahead of its certificate.
+func TestPrefetchWinningCandidate(t *testing.T) {
+	eb, rpc, c := setupChainTest(t, false)
+	go c.Listen()
+
+	blk := helper.RandomBlock(t, 2, 1)
+	// Only answers once: the certificate must be handled with the
+	// prefetched candidate
+	provideCandidate(rpc, &candidate.Candidate{blk, block.EmptyCertificate()})
+
+	buf := new(bytes.Buffer)
+	hdr := header.Header{PubKeyBLS: []byte{1}, Round: 2, Step: 1, BlockHash: blk.Header.Hash}
+	if err := header.Marshal(buf, hdr); err != nil {
+		t.Fatal(err)
+	}
+	// StepVotes payload
+	buf.WriteByte(0)
+	eb.Publish(topics.StepVotes, buf)
+
+	for i := 0; ; i++ {
+		c.prefetchLock.Lock()
+		done := c.prefetched != nil
+		c.prefetchLock.Unlock()
+		if done {
+			break
+		}
+
+		if i == 100 {
+			t.Fatal("candidate was not prefetched")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	c.handleCertificateMessage(certMsg{blk.Header.Hash, block.EmptyCertificate()})
+	assert.True(t, blk.Equals(c.intermediateBlock))
+	assert.Nil(t, c.prefetched)
+}
+
 func TestReturnOnNilIntermediateBlock(t *testing.T) {
 	eb, _, c := setupChainTest(t, false)
 	intermediateChan := make(chan bytes.Buffer, 1)
@@ -182,7 +230,7 @@ func TestReturnOnNilIntermediateBlock(t *testing.T) {
 
 func provideCandidate(rpc *rpcbus.RPCBus, cm *candidate.Candidate) {
 	c := make(chan rpcbus.Request, 1)
-	rpc.Register(rpcbus.GetCandidate, c)
+	rpc.RegisterChan(rpcbus.GetCandidate, c)
 	buf := new(bytes.Buffer)
 	if err := candidate.Encode(buf, cm); err != nil {
 		panic(err)
@@ -245,7 +293,7 @@ func TestCertificateExpiredProvisioner(t *testing.T) {
 	blk := helper.RandomBlock(t, 1, 1)
 	// Remove all txs except coinbase, as the helper transactions do not pass verification
 	blk.Txs = blk.Txs[0:1]
-	blk.SetRoot()
+	_ = marshalling.SetTxRoot(blk)
 	blk.SetHash()
 	// Add cert and prev hash
 	blk.Header.Certificate = createMockedCertificate(blk.Header.Hash, 1, k, p)
@@ -302,6 +350,30 @@ func TestRemoveExpired(t *testing.T) {
 	}
 }
 
+// Bids stored in the database should be restored on startup.
+func TestRestoreBids(t *testing.T) {
+	_, _, c := setupChainTest(t, false)
+
+	bid := createBid(t)
+	bid.EndHeight = c.prevBlock.Header.Height + 1000
+	assert.NoError(t, c.db.Update(func(t database.Transaction) error {
+		return t.StoreBid(bid.X[:], bid.M[:], bid.EndHeight)
+	}))
+
+	c.bidList = &user.BidList{}
+	c.restoreConsensusData()
+	assert.True(t, c.bidList.Contains(bid))
+
+	// Expired bids are pruned from the database
+	assert.NoError(t, c.db.Update(func(t database.Transaction) error {
+		return t.DeleteExpiredBids(bid.EndHeight + 1)
+	}))
+
+	c.bidList = &user.BidList{}
+	assert.False(t, c.restoreBids(0))
+	assert.False(t, c.bidList.Contains(bid))
+}
+
 // Add and then a remove a provisioner, to check if removal works properly.
 func TestRemove(t *testing.T) {
 	_, _, c := setupChainTest(t, false)
@@ -322,7 +394,7 @@ func TestRemove(t *testing.T) {
 }
 
 func TestRemoveExpiredProvisioners(t *testing.T) {
-	_, _, c := setupChainTest(t, false)
+	eb, _, c := setupChainTest(t, false)
 
 	for i := 0; i < 10; i++ {
 		keys, _ := key.NewRandConsensusKeys()
@@ -341,8 +413,221 @@ func TestRemoveExpiredProvisioners(t *testing.T) {
 		i++
 	}
 
+	expiredChan := make(chan bytes.Buffer, 10)
+	eb.Subscribe(topics.StakeExpired, eventbus.NewChanListener(expiredChan))
+
 	c.removeExpiredProvisioners(1001)
 	assert.Equal(t, 5, len(c.p.Members))
+
+	// Each stake removed is published
+	for i := 0; i < 5; i++ {
+		buf := <-expiredChan
+		expiry, err := user.UnmarshalStakeExpiry(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1000), expiry.Stake.EndHeight)
+		assert.True(t, expiry.Removed)
+		assert.Nil(t, c.p.GetMember(expiry.PubKeyBLS))
+	}
+}
+
+// Stakes count in the committees once they have the amount of confirmations
+// of the network
+func TestStakeMaturity(t *testing.T) {
+	_, _, c := setupChainTest(t, false)
+	keys, _ := key.NewRandConsensusKeys()
+	tx, err := helper.RandomStakeTx(t, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.PubKeyEd = keys.EdPubKeyBytes
+	tx.PubKeyBLS = keys.BLSPubKeyBytes
+
+	c.addConsensusNodes([]transactions.Transaction{tx}, 100)
+	stake := c.p.GetMember(keys.BLSPubKeyBytes).Stakes[0]
+	maturity := cfg.GetConsensusParams().StakeMaturity
+	assert.Equal(t, 100+maturity, stake.StartHeight)
+	assert.Equal(t, 100+tx.Lock, stake.EndHeight)
+	assert.Equal(t, 0, c.p.SubsetSizeAt(100+maturity-1))
+	assert.Equal(t, 1, c.p.SubsetSizeAt(100+maturity))
+}
+
+// Test that verified candidates are only trusted on top of the block they
+// were verified on.
+func TestVerifiedCandidates(t *testing.T) {
+	c := &Chain{verified: make(map[string][]byte)}
+	tip := helper.RandomBlock(t, 1, 1)
+	blk := helper.RandomBlock(t, 2, 1)
+	blk.Header.PrevBlockHash = tip.Header.Hash
+
+	// Candidates verified on top of the new tip are kept
+	c.markVerified(*blk)
+	c.pruneVerified(tip.Header.Hash)
+	assert.True(t, c.takeVerified(*blk, tip.Header.Hash))
+	// and taken once only
+	assert.False(t, c.takeVerified(*blk, tip.Header.Hash))
+
+	// The others are dropped
+	c.markVerified(*blk)
+	c.pruneVerified(blk.Header.Hash)
+	assert.False(t, c.takeVerified(*blk, tip.Header.Hash))
+
+	// A block with the hash of a candidate verified, but other txs, is not
+	// taken for it
+	c.markVerified(*blk)
+	other := *blk
+	other.Txs = helper.RandomBlock(t, 2, 1).Txs
+	assert.False(t, c.takeVerified(other, tip.Header.Hash))
+}
+
+// Test that the chain switches to a competing tip once it is heavier.
+func TestReorganization(t *testing.T) {
+	_, _, c := setupChainTest(t, false)
+	defer c.Close()
+
+	tip := c.prevBlock
+	newBlock := func(votes uint64) *block.Block {
+		blk := helper.RandomBlock(t, tip.Header.Height+1, 1)
+		// Remove all txs except coinbase, as the helper transactions do not pass verification
+		blk.Txs = blk.Txs[0:1]
+		_ = marshalling.SetTxRoot(blk)
+		blk.Header.PrevBlockHash = tip.Header.Hash
+		blk.SetHash()
+		blk.Header.Certificate = block.EmptyCertificate()
+		blk.Header.Certificate.StepOneCommittee = votes
+		return blk
+	}
+
+	mainBlock := newBlock(1)
+	assert.NoError(t, c.AcceptBlock(*mainBlock))
+
+	// A lighter fork is kept aside
+	lighter := newBlock(0)
+	assert.NoError(t, c.AcceptBlock(*lighter))
+	assert.Equal(t, mainBlock.Header.Hash, c.prevBlock.Header.Hash)
+
+	// A heavier one replaces the tip
+	heavier := newBlock(3)
+	assert.NoError(t, c.AcceptBlock(*heavier))
+	assert.Equal(t, heavier.Header.Hash, c.prevBlock.Header.Hash)
+	_, ok := c.forks[string(mainBlock.Header.Hash)]
+	assert.True(t, ok)
+
+	assert.Equal(t, database.ErrBlockNotFound, c.db.View(func(t database.Transaction) error {
+		_, err := t.FetchBlockExists(mainBlock.Header.Hash)
+		return err
+	}))
+}
+
+// Forks received from the network should be kept whether syncing or not. A
+// heavier one should replace the tip, and drop the round built on the tip
+// rolled back.
+func TestForkFromNetwork(t *testing.T) {
+	eb, _, c := setupChainTest(t, false)
+	defer c.Close()
+
+	stopConsensusChan := make(chan bytes.Buffer, 1)
+	eb.Subscribe(topics.StopConsensus, eventbus.NewChanListener(stopConsensusChan))
+
+	tip := c.prevBlock
+	newBlock := func(votes uint64) *block.Block {
+		blk := helper.RandomBlock(t, tip.Header.Height+1, 1)
+		// Remove all txs except coinbase, as the helper transactions do not pass verification
+		blk.Txs = blk.Txs[0:1]
+		_ = marshalling.SetTxRoot(blk)
+		blk.Header.PrevBlockHash = tip.Header.Hash
+		blk.SetHash()
+		blk.Header.Certificate = block.EmptyCertificate()
+		blk.Header.Certificate.StepOneCommittee = votes
+		return blk
+	}
+
+	mainBlock := newBlock(1)
+	assert.NoError(t, c.AcceptBlock(*mainBlock))
+	assert.False(t, c.counter.IsSyncing())
+
+	heavier := newBlock(3)
+	buf := new(bytes.Buffer)
+	if err := marshalling.MarshalBlock(buf, heavier); err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, eb.Publish(topics.Block, buf))
+
+	c.mu.RLock()
+	assert.Equal(t, heavier.Header.Hash, c.prevBlock.Header.Hash)
+	c.mu.RUnlock()
+
+	c.roundLock.RLock()
+	assert.Nil(t, c.intermediateBlock)
+	assert.Nil(t, c.lastCertificate)
+	c.roundLock.RUnlock()
+
+	// The consensus waits for the results of the round after the new tip
+	select {
+	case <-stopConsensusChan:
+	case <-time.After(time.Second):
+		t.Fatal("consensus not stopped after reorganization")
+	}
+}
+
+// Forks should be checked for votes before their certificate.
+func TestCheckForkHeader(t *testing.T) {
+	parent := helper.RandomBlock(t, 1, 1)
+	blk := helper.RandomBlock(t, 2, 1)
+	blk.Txs = blk.Txs[0:1]
+	_ = marshalling.SetTxRoot(blk)
+	blk.Header.PrevBlockHash = parent.Header.Hash
+	blk.SetHash()
+
+	blk.Header.Certificate = block.EmptyCertificate()
+	assert.Error(t, checkForkHeader(parent.Header, *blk))
+
+	blk.Header.Certificate.StepOneCommittee = 1
+	blk.Header.Certificate.StepTwoCommittee = 1
+	assert.NoError(t, checkForkHeader(parent.Header, *blk))
+}
+
+// Blocks synced out of order should be accepted in order.
+func TestSyncOutOfOrder(t *testing.T) {
+	_, _, c := setupChainTest(t, false)
+	defer c.Close()
+
+	// Keep syncing after the two blocks
+	c.counter.StartSyncing(3)
+
+	prev := c.prevBlock
+	blks := make([]*block.Block, 2)
+	for i := range blks {
+		blk := helper.RandomBlock(t, prev.Header.Height+1, 1)
+		// Remove all txs except coinbase, as the helper transactions do not pass verification
+		blk.Txs = blk.Txs[0:1]
+		_ = marshalling.SetTxRoot(blk)
+		blk.Header.PrevBlockHash = prev.Header.Hash
+		blk.SetHash()
+		blk.Header.Certificate = block.EmptyCertificate()
+		blks[i] = blk
+		prev = *blk
+	}
+
+	accept := func(blk *block.Block) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, marshalling.MarshalBlock(buf, blk))
+		assert.NoError(t, c.onAcceptBlock(*buf, tracing.None))
+	}
+
+	// The second block waits for the first one
+	tip := c.prevBlock.Header.Hash
+	accept(blks[1])
+	assert.Equal(t, tip, c.prevBlock.Header.Hash)
+
+	// Blocks too far ahead are not held
+	far := helper.RandomBlock(t, c.prevBlock.Header.Height+maxOrphans+1, 1)
+	far.SetHash()
+	accept(far)
+	assert.Len(t, c.orphans.blocks, 1)
+
+	accept(blks[0])
+	assert.Equal(t, blks[1].Header.Hash, c.prevBlock.Header.Hash)
+	assert.Empty(t, c.orphans.blocks)
 }
 
 func createBid(t *testing.T) user.Bid {
@@ -372,3 +657,304 @@ func setupChainTest(t *testing.T, includeGenesis bool) (*eventbus.EventBus, *rpc
 
 	return eb, rpc, c
 }
+
+// Closing the chain should stop Listen, and refuse the blocks arriving
+// afterwards.
+func TestClose(t *testing.T) {
+	_, _, c := setupChainTest(t, false)
+	stopped := make(chan struct{})
+	go func() {
+		c.Listen()
+		close(stopped)
+	}()
+
+	assert.NoError(t, c.Close())
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Listen did not return")
+	}
+
+	genesis := c.prevBlock
+	blk := helper.RandomBlock(t, genesis.Header.Height+1, 1)
+	blk.Txs = blk.Txs[0:1]
+	_ = marshalling.SetTxRoot(blk)
+	blk.Header.PrevBlockHash = genesis.Header.Hash
+	blk.SetHash()
+	blk.Header.Certificate = block.EmptyCertificate()
+	assert.Equal(t, ErrClosed, c.AcceptBlock(*blk))
+
+	// Closing again is harmless
+	assert.NoError(t, c.Close())
+}
+
+// Accepted blocks and headers should be served over the rpcbus.
+func TestProvideHistory(t *testing.T) {
+	_, rb, c := setupChainTest(t, false)
+	defer c.Close()
+
+	genesis := c.prevBlock
+	blk := helper.RandomBlock(t, genesis.Header.Height+1, 1)
+	// Remove all txs except coinbase, as the helper transactions do not pass verification
+	blk.Txs = blk.Txs[0:1]
+	_ = marshalling.SetTxRoot(blk)
+	blk.Header.PrevBlockHash = genesis.Header.Hash
+	blk.SetHash()
+	blk.Header.Certificate = block.EmptyCertificate()
+	assert.NoError(t, c.AcceptBlock(*blk))
+
+	fetched := func(buf bytes.Buffer, err error) *block.Block {
+		assert.NoError(t, err)
+		b := block.NewBlock()
+		assert.NoError(t, marshalling.UnmarshalBlock(&buf, b))
+		return b
+	}
+
+	b := fetched(rb.Call(rpcbus.GetBlockByHash, rpcbus.NewRequest(*bytes.NewBuffer(blk.Header.Hash)), time.Second))
+	assert.Equal(t, blk.Header.Hash, b.Header.Hash)
+
+	height := new(bytes.Buffer)
+	assert.NoError(t, encoding.WriteUint64LE(height, blk.Header.Height))
+	b = fetched(rb.Call(rpcbus.GetBlockByHeight, rpcbus.NewRequest(*height), time.Second))
+	assert.Equal(t, blk.Header.Hash, b.Header.Hash)
+
+	// The range is cut at the tip
+	params := new(bytes.Buffer)
+	assert.NoError(t, rpcbus.MarshalHeadersRangeRequest(params, 0, 10))
+	buf, err := rb.Call(rpcbus.GetHeadersRange, rpcbus.NewRequest(*params), time.Second)
+	assert.NoError(t, err)
+
+	count, err := encoding.ReadVarInt(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	hashes := make([][]byte, 0, count)
+	for i := uint64(0); i < count; i++ {
+		header := block.NewHeader()
+		assert.NoError(t, marshalling.UnmarshalHeader(&buf, header))
+		hashes = append(hashes, header.Hash)
+	}
+	assert.Equal(t, [][]byte{genesis.Header.Hash, blk.Header.Hash}, hashes)
+}
+
+// A node bootstrapped from a snapshot should resume from its tip.
+func TestSnapshot(t *testing.T) {
+	_, _, c := setupChainTest(t, false)
+	defer c.Close()
+
+	genesis := c.prevBlock
+	blk := helper.RandomBlock(t, genesis.Header.Height+1, 1)
+	// Remove all txs except coinbase, as the helper transactions do not pass verification
+	blk.Txs = blk.Txs[0:1]
+	_ = marshalling.SetTxRoot(blk)
+	blk.Header.PrevBlockHash = genesis.Header.Hash
+	blk.SetHash()
+	blk.Header.Certificate = block.EmptyCertificate()
+	assert.NoError(t, c.AcceptBlock(*blk))
+
+	// A provisioner staking until after the genesis stakes expire
+	pubKeyBLS := make([]byte, 129)
+	pubKeyBLS[0] = 1
+	end := transactions.GenesisExpirationHeight + 1000
+	assert.NoError(t, c.addProvisioner(make([]byte, 32), pubKeyBLS, 500, 3, end))
+
+	dir, err := ioutil.TempDir("", "dusk-snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "snapshot")
+	assert.NoError(t, c.WriteSnapshot(path))
+
+	// A snapshot is only trusted along with the checkpoint of its tip
+	_, _, err = loadSnapshot(c.db, path, &genesis, 0, nil)
+	assert.Equal(t, ErrUntrustedSnapshot, err)
+	_, _, err = loadSnapshot(c.db, path, &genesis, blk.Header.Height, genesis.Header.Hash)
+	assert.Error(t, err)
+
+	orig := cfg.Get()
+	r := orig
+	r.Chain.Snapshot = path
+	r.Chain.Checkpoint = fmt.Sprintf("%d:%x", blk.Header.Height, blk.Header.Hash)
+	cfg.Mock(&r)
+	defer cfg.Mock(&orig)
+
+	_, _, bootstrapped := setupChainTest(t, false)
+	defer bootstrapped.Close()
+
+	assert.Equal(t, blk.Header.Hash, bootstrapped.prevBlock.Header.Hash)
+	assert.NotNil(t, bootstrapped.p.Members[string(pubKeyBLS)])
+
+	// The blocks below the tip are stored without their txs
+	assert.NoError(t, bootstrapped.db.View(func(t database.Transaction) error {
+		hash, err := t.FetchBlockHashByHeight(1)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(hash, blk.Header.Hash) {
+			return errors.New("tip not stored")
+		}
+		return nil
+	}))
+
+	// The lengths of the lists are bounded by the size of the snapshot
+	buf := new(bytes.Buffer)
+	_ = encoding.WriteUint8(buf, snapshotVersion)
+	_ = encoding.WriteVarInt(buf, 1<<40)
+	_, err = UnmarshalSnapshot(buf)
+	assert.Error(t, err)
+}
+
+// Blocks up to a checkpoint should be rejected unless they lead to it.
+func TestCheckpoint(t *testing.T) {
+	_, _, c := setupChainTest(t, false)
+	defer c.Close()
+
+	genesis := c.prevBlock
+	blk := helper.RandomBlock(t, genesis.Header.Height+1, 1)
+	// Remove all txs except coinbase, as the helper transactions do not pass verification
+	blk.Txs = blk.Txs[0:1]
+	_ = marshalling.SetTxRoot(blk)
+	blk.Header.PrevBlockHash = genesis.Header.Hash
+	blk.SetHash()
+	blk.Header.Certificate = block.EmptyCertificate()
+
+	c.checkpointHeight = blk.Header.Height
+	c.checkpointHash = make([]byte, 32)
+	assert.Error(t, c.AcceptBlock(*blk))
+
+	c.checkpointHash = blk.Header.Hash
+	assert.NoError(t, c.AcceptBlock(*blk))
+}
+
+// In headers-only mode, blocks should be accepted without verifying their
+// txs, and stored with their stakes and bids only.
+func TestHeadersOnly(t *testing.T) {
+	_, _, c := setupChainTest(t, false)
+	defer c.Close()
+	c.headersOnly = true
+
+	// The helper transactions do not pass verification, but are not verified
+	genesis := c.prevBlock
+	blk := helper.RandomBlock(t, genesis.Header.Height+1, 1)
+	_ = marshalling.SetTxRoot(blk)
+	blk.Header.PrevBlockHash = genesis.Header.Hash
+	blk.SetHash()
+	blk.Header.Certificate = block.EmptyCertificate()
+	assert.NoError(t, c.AcceptBlock(*blk))
+	assert.Equal(t, blk.Header.Hash, c.prevBlock.Header.Hash)
+
+	var stored *block.Block
+	assert.NoError(t, c.db.View(func(t database.Transaction) error {
+		var err error
+		stored, err = t.FetchBlock(blk.Header.Hash)
+		return err
+	}))
+
+	assert.Equal(t, 2, len(stored.Txs))
+	for _, tx := range stored.Txs {
+		assert.Contains(t, []transactions.TxType{transactions.StakeType, transactions.BidType}, tx.Type())
+	}
+
+	// The txs are still bound by the merkle root
+	tampered := helper.RandomBlock(t, blk.Header.Height+1, 1)
+	tampered.Header.PrevBlockHash = blk.Header.Hash
+	tampered.SetHash()
+	tampered.Header.Certificate = block.EmptyCertificate()
+	tampered.Txs = tampered.Txs[0:1]
+	assert.Error(t, c.AcceptBlock(*tampered))
+
+	// Candidates are not voted for
+	buf := new(bytes.Buffer)
+	assert.NoError(t, marshalling.MarshalBlock(buf, blk))
+	_, err := c.verifyCandidateBlock(rpcbus.NewRequest(*buf))
+	assert.Equal(t, ErrHeadersOnly, err)
+}
+
+// Blocks arriving before their previous block should be held, and accepted
+// once it is, while it is requested from the network.
+func TestOrphans(t *testing.T) {
+	_, rb, c := setupChainTest(t, false)
+	defer c.Close()
+	sentChan := make(chan bytes.Buffer, 10)
+	assert.NoError(t, rb.Register(rpcbus.SendToPeer, func(r rpcbus.Request) (bytes.Buffer, error) {
+		sentChan <- r.Params
+		return bytes.Buffer{}, nil
+	}))
+
+	prev := c.prevBlock
+	blks := make([]*block.Block, 3)
+	for i := range blks {
+		blk := helper.RandomBlock(t, prev.Header.Height+1, 1)
+		// Remove all txs except coinbase, as the helper transactions do not pass verification
+		blk.Txs = blk.Txs[0:1]
+		_ = marshalling.SetTxRoot(blk)
+		blk.Header.PrevBlockHash = prev.Header.Hash
+		blk.SetHash()
+		blk.Header.Certificate = block.EmptyCertificate()
+		blks[i] = blk
+		prev = *blk
+	}
+
+	tip := c.prevBlock.Header.Hash
+	assert.NoError(t, c.acceptTraced(*blks[1], "peer1", nil))
+	assert.NoError(t, c.acceptTraced(*blks[2], "peer2", nil))
+	assert.Equal(t, tip, c.prevBlock.Header.Hash)
+
+	// The previous block of the first orphan is requested from the peer
+	// which sent it
+	buf := <-sentChan
+	address, err := encoding.ReadString(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "peer1", address)
+	topic, err := topics.Extract(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, topics.GetData, topic)
+	inv := &peermsg.Inv{}
+	assert.NoError(t, inv.Decode(&buf))
+	assert.Equal(t, blks[0].Header.Hash, inv.InvList[0].Hash)
+
+	// The previous block of the second one is held, and not requested
+	assert.True(t, c.orphans.awaits(blks[0].Header.Hash))
+	assert.Empty(t, sentChan)
+
+	// Orphans whose hash does not match their header are dropped
+	forged := *helper.RandomBlock(t, blks[2].Header.Height+1, 1)
+	forged.Header.Hash = make([]byte, 32)
+	assert.NoError(t, c.acceptTraced(forged, "peer2", nil))
+	assert.False(t, c.orphans.has(forged.Header.Hash))
+
+	assert.NoError(t, c.AcceptBlock(*blks[0]))
+	assert.Equal(t, blks[2].Header.Hash, c.prevBlock.Header.Hash)
+	assert.Empty(t, c.orphans.blocks)
+	assert.Empty(t, c.orphans.byPrev)
+}
+
+// The orphan pool should drop the orphans expired, and the oldest one once
+// full.
+func TestOrphanPoolLimits(t *testing.T) {
+	p := newOrphanPool()
+	now := time.Now()
+	newOrphan := func() block.Block {
+		return *helper.RandomBlock(t, 10, 0)
+	}
+
+	expired := newOrphan()
+	assert.True(t, p.add(expired, "", now.Add(-orphanExpiry)))
+	assert.False(t, p.add(expired, "", now))
+
+	oldest := newOrphan()
+	assert.True(t, p.add(oldest, "", now))
+	assert.False(t, p.has(expired.Header.Hash))
+
+	for i := 1; i < maxOrphans; i++ {
+		assert.True(t, p.add(newOrphan(), "", now.Add(time.Duration(i))))
+	}
+	assert.True(t, p.has(oldest.Header.Hash))
+
+	assert.True(t, p.add(newOrphan(), "", now.Add(time.Minute)))
+	assert.False(t, p.has(oldest.Header.Hash))
+	assert.Equal(t, maxOrphans, len(p.blocks))
+}
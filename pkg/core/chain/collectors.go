@@ -3,6 +3,7 @@ package chain
 import (
 	"bytes"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -23,37 +24,46 @@ type (
 	highestSeenCollector struct {
 		highestSeenChan chan<- uint64
 	}
+
+	stepVotesCollector struct {
+		winningHashChan chan<- []byte
+	}
 )
 
-func initCertificateCollector(subscriber eventbus.Subscriber) <-chan certMsg {
+func initCertificateCollector(subscriber eventbus.Subscriber) (<-chan certMsg, uint32) {
 	certificateChan := make(chan certMsg, 10)
 	collector := &certificateCollector{certificateChan}
-	l := eventbus.NewCallbackListener(collector.Collect)
-	subscriber.Subscribe(topics.Certificate, l)
-	return certificateChan
+	id := eventbus.SubscribeMessage(subscriber, topics.Certificate, newCertMsg, collector.Collect)
+	return certificateChan, id
 }
 
-func (c *certificateCollector) Collect(m bytes.Buffer) error {
-	hash := make([]byte, 32)
-	if err := encoding.Read256(&m, hash); err != nil {
-		return err
+func newCertMsg() eventbus.Unmarshaler {
+	return &certMsg{
+		hash: make([]byte, 32),
+		cert: block.EmptyCertificate(),
 	}
+}
 
-	cert := block.EmptyCertificate()
-	if err := marshalling.UnmarshalCertificate(&m, cert); err != nil {
+// Decode a certMsg from m
+func (c *certMsg) Decode(m *bytes.Buffer) error {
+	if err := encoding.Read256(m, c.hash); err != nil {
 		return err
 	}
 
-	c.certificateChan <- certMsg{hash, cert}
+	return marshalling.UnmarshalCertificate(m, c.cert)
+}
+
+func (c *certificateCollector) Collect(m eventbus.Unmarshaler) error {
+	c.certificateChan <- *m.(*certMsg)
 	return nil
 }
 
-func initHighestSeenCollector(sub eventbus.Subscriber) <-chan uint64 {
+func initHighestSeenCollector(sub eventbus.Subscriber) (<-chan uint64, uint32) {
 	highestSeenChan := make(chan uint64, 1)
 	collector := &highestSeenCollector{highestSeenChan}
 	l := eventbus.NewCallbackListener(collector.Collect)
-	sub.Subscribe(topics.HighestSeen, l)
-	return highestSeenChan
+	id := sub.Subscribe(topics.HighestSeen, l)
+	return highestSeenChan, id
 }
 
 func (h *highestSeenCollector) Collect(m bytes.Buffer) error {
@@ -65,3 +75,34 @@ func (h *highestSeenCollector) Collect(m bytes.Buffer) error {
 	h.highestSeenChan <- height
 	return nil
 }
+
+// initStepVotesCollector collects the hashes on which the first step of
+// reduction converged, which are likely to win the round.
+func initStepVotesCollector(sub eventbus.Subscriber) (<-chan []byte, uint32) {
+	winningHashChan := make(chan []byte, 1)
+	collector := &stepVotesCollector{winningHashChan}
+	l := eventbus.NewCallbackListener(collector.Collect)
+	id := sub.Subscribe(topics.StepVotes, l)
+	return winningHashChan, id
+}
+
+func (s *stepVotesCollector) Collect(m bytes.Buffer) error {
+	hdr := header.Header{}
+	if err := header.Unmarshal(&m, &hdr); err != nil {
+		return err
+	}
+
+	// A first step without quorum, or converging on the empty hash, has
+	// no candidate to prefetch
+	if m.Len() == 0 || bytes.Equal(hdr.BlockHash, make([]byte, 32)) {
+		return nil
+	}
+
+	// Prefetching is best effort: a hash is dropped rather than delaying
+	// the consensus
+	select {
+	case s.winningHashChan <- hdr.BlockHash:
+	default:
+	}
+	return nil
+}
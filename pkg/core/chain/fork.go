@@ -0,0 +1,457 @@
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/bits"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/versionbits"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/dusk-network/dusk-wallet/transactions"
+	logger "github.com/sirupsen/logrus"
+)
+
+// maxReorgDepth is the amount of blocks the chain can be rolled back by, to
+// switch to a heavier fork. Forks branching off deeper are dropped.
+const maxReorgDepth = 50
+
+// ErrReorgTooDeep is returned for blocks of forks branching off more than
+// maxReorgDepth blocks below the tip
+var ErrReorgTooDeep = errors.New("fork branches off too deep in the chain")
+
+// isFork returns whether blk builds on a known block other than the tip.
+// Blocks already stored, or whose previous block is unknown, are left to
+// acceptBlock to reject.
+func (c *Chain) isFork(blk block.Block) bool {
+	if bytes.Equal(blk.Header.PrevBlockHash, c.prevBlock.Header.Hash) {
+		return false
+	}
+
+	if _, ok := c.forks[string(blk.Header.PrevBlockHash)]; ok {
+		return true
+	}
+
+	err := c.db.View(func(t database.Transaction) error {
+		if _, err := t.FetchBlockExists(blk.Header.Hash); err != database.ErrBlockNotFound {
+			return errors.New("block already exists")
+		}
+
+		_, err := t.FetchBlockExists(blk.Header.PrevBlockHash)
+		return err
+	})
+	return err == nil
+}
+
+// acceptFork keeps blk as a competing tip. If the branch it ends is heavier
+// than the main chain since they forked off, the chain is reorganized onto
+// the branch. If blk fails the checks, origin, the peer which sent it, is told
+// about it. The caller must hold c.mu.
+//
+// The transactions of the branch can only be verified against the chain
+// state at the fork point, so they are verified on reorganization.
+func (c *Chain) acceptFork(blk block.Block, origin string) error {
+	l := log.WithFields(logger.Fields{"process": "accept block", "height": blk.Header.Height})
+
+	branch, forkPoint, err := c.branch(blk)
+	if err != nil {
+		return err
+	}
+
+	if c.prevBlock.Header.Height-forkPoint.Height > maxReorgDepth {
+		return ErrReorgTooDeep
+	}
+
+	parent := forkPoint
+	if len(branch) > 0 {
+		parent = branch[len(branch)-1].Header
+	}
+
+	// The cheap checks come first, so that the provisioners at the fork
+	// point are only rebuilt for blocks which could be certified
+	if err := checkForkHeader(parent, blk); err != nil {
+		c.rejectBlock(blk, origin, err)
+		return err
+	}
+
+	// The committees of the branch are drawn from the provisioners at the
+	// fork point, not from the ones of the tip
+	p, err := c.provisionersAt(forkPoint, branch)
+	if err != nil {
+		return err
+	}
+
+	if err := verifiers.CheckBlockCertificate(*p, blk); err != nil {
+		c.rejectBlock(blk, origin, err)
+		return err
+	}
+
+	c.forks[string(blk.Header.Hash)] = blk
+	branch = append(branch, blk)
+
+	mainWeight, err := c.mainWeight(forkPoint.Height)
+	if err != nil {
+		return err
+	}
+
+	if branchWeight(branch) <= mainWeight {
+		l.Debugln("keeping competing tip")
+		return nil
+	}
+
+	l.WithField("fork point", forkPoint.Height).Infoln("reorganizing chain onto heavier fork")
+	return c.reorganize(forkPoint.Height, branch)
+}
+
+// checkForkHeader runs the checks of blk which do not need the state of its
+// branch: its header, and the votes of its certificate. From height 2, both
+// reduction steps need votes to reach quorum, see
+// verifiers.CheckBlockCertificate.
+func checkForkHeader(parent *block.Header, blk block.Block) error {
+	if err := checkHeaderHash(blk.Header); err != nil {
+		return err
+	}
+
+	if err := verifiers.CheckBlockHeader(block.Block{Header: parent}, blk); err != nil {
+		return err
+	}
+
+	if blk.Header.Height < 2 {
+		return nil
+	}
+
+	cert := blk.Header.Certificate
+	if cert == nil || cert.StepOneCommittee == 0 || cert.StepTwoCommittee == 0 {
+		return errors.New("certificate without votes")
+	}
+	return nil
+}
+
+// branch returns the blocks of the forks leading to blk, from the one
+// building on the main chain, and the main chain block they build on
+func (c *Chain) branch(blk block.Block) ([]block.Block, *block.Header, error) {
+	var branch []block.Block
+	prevHash := blk.Header.PrevBlockHash
+	for {
+		prev, ok := c.forks[string(prevHash)]
+		if !ok {
+			break
+		}
+		branch = append([]block.Block{prev}, branch...)
+		prevHash = prev.Header.PrevBlockHash
+	}
+
+	var forkPoint *block.Header
+	err := c.db.View(func(t database.Transaction) error {
+		var err error
+		forkPoint, err = t.FetchBlockHeader(prevHash)
+		return err
+	})
+	return branch, forkPoint, err
+}
+
+// reorganize rolls the chain back to the given height, and accepts the blocks
+// of branch. If one of them fails verification, the blocks rolled back are
+// accepted again. The blocks rolled back are kept as a competing branch, and
+// their txs are returned to the mempool, unless only the headers are stored.
+func (c *Chain) reorganize(height uint64, branch []block.Block) error {
+	rolledBack, err := c.rollback(height)
+	if err != nil {
+		return err
+	}
+
+	for i, blk := range branch {
+		if err := c.acceptBlock(blk, "", nil); err != nil {
+			// Drop the invalid block, and the ones building on it
+			for _, invalid := range branch[i:] {
+				delete(c.forks, string(invalid.Header.Hash))
+			}
+
+			if _, rerr := c.rollback(height); rerr != nil {
+				log.WithError(rerr).Errorln("could not roll back invalid fork")
+				return err
+			}
+
+			for j := len(rolledBack) - 1; j >= 0; j-- {
+				// Without their txs, the blocks rolled back would fail
+				// verification in headers-only mode
+				if c.headersOnly {
+					c.markVerified(rolledBack[j])
+				}
+
+				if rerr := c.acceptBlock(rolledBack[j], "", nil); rerr != nil {
+					log.WithError(rerr).Errorln("could not restore main chain")
+					return err
+				}
+			}
+			return err
+		}
+
+		delete(c.forks, string(blk.Header.Hash))
+	}
+
+	// In headers-only mode, the blocks rolled back are stored without their
+	// txs, and could not be switched back to
+	if !c.headersOnly {
+		for _, blk := range rolledBack {
+			c.forks[string(blk.Header.Hash)] = blk
+		}
+		c.returnTxs(rolledBack)
+	}
+	reorgs.Inc()
+	c.resetRound()
+	return nil
+}
+
+// resetRound drops the intermediate block and the certificate of the round
+// following the tip rolled back. As after a sync, the consensus is stopped,
+// and restarted on the results of the round following the new tip, see
+// restartRound. The caller must hold c.mu.
+func (c *Chain) resetRound() {
+	c.roundLock.Lock()
+	c.intermediateBlock = nil
+	c.lastCertificate = nil
+	c.roundLock.Unlock()
+
+	go c.restartRound(c.prevBlock.Header.Height + 1)
+}
+
+// restartRound stops the consensus, requests the intermediate block and the
+// certificate of round from the network, and sends the RoundUpdate restarting
+// the consensus on them. If none is received, the node catches up later.
+func (c *Chain) restartRound(round uint64) {
+	c.eventBus.Publish(topics.StopConsensus, new(bytes.Buffer))
+
+	blk, cert, err := c.requestRoundResults(round)
+	if err != nil {
+		log.WithError(err).WithField("round", round).Warnln("could not fetch the round results after reorganization")
+		return
+	}
+
+	c.roundLock.Lock()
+	c.intermediateBlock = blk
+	c.lastCertificate = cert
+	c.roundLock.Unlock()
+
+	if err := c.sendRoundUpdate(); err != nil {
+		log.WithError(err).Warnln("could not send the round update after reorganization")
+	}
+}
+
+// returnTxs publishes the txs of the blocks rolled back on the Tx topic, for
+// the mempool to keep the ones still valid on the new main chain
+func (c *Chain) returnTxs(blks []block.Block) {
+	for _, blk := range blks {
+		for _, tx := range blk.Txs {
+			if tx.Type() == transactions.CoinbaseType {
+				continue
+			}
+
+			buf := new(bytes.Buffer)
+			if err := marshalling.MarshalTx(buf, tx); err != nil {
+				log.WithError(err).Warnln("could not encode the tx of a block rolled back")
+				continue
+			}
+			c.eventBus.Publish(topics.Tx, buf)
+		}
+	}
+}
+
+// rollback deletes the blocks above height, from the tip down, and rebuilds
+// the provisioners and the bids from the remaining chain. It returns the
+// blocks deleted.
+func (c *Chain) rollback(height uint64) ([]block.Block, error) {
+	var deleted []block.Block
+	var prev *block.Block
+	err := c.db.Update(func(t database.Transaction) error {
+		for h := c.prevBlock.Header.Height; h > height; h-- {
+			hash, err := t.FetchBlockHashByHeight(h)
+			if err != nil {
+				return err
+			}
+
+			blk, err := t.FetchBlock(hash)
+			if err != nil {
+				return err
+			}
+			deleted = append(deleted, *blk)
+		}
+
+		if err := t.RollbackToHeight(height); err != nil {
+			return err
+		}
+
+		hash, err := t.FetchBlockHashByHeight(height)
+		if err != nil {
+			return err
+		}
+
+		prev, err = t.FetchBlock(hash)
+		if err != nil {
+			return err
+		}
+
+		// The bids of the blocks deleted are dropped along with all the
+		// others, to be rebuilt from the remaining chain
+		return t.DeleteExpiredBids(math.MaxUint64)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.prevBlock = *prev
+
+	c.p = user.NewProvisioners()
+	c.bidList = &user.BidList{}
+	c.versions = versionbits.NewTracker(c.fetchVersion)
+	c.restoreConsensusData()
+	return deleted, nil
+}
+
+// provisionersAt returns the provisioners of the main chain at forkPoint, as
+// rollback would restore them, along with the stakes of the blocks of branch
+// building on it. The provisioners at forkPoint are rebuilt from the chain
+// once per fork point. The caller must hold c.mu.
+func (c *Chain) provisionersAt(forkPoint *block.Header, branch []block.Block) (*user.Provisioners, error) {
+	if c.forkSet == nil || !bytes.Equal(c.forkSet.hash, forkPoint.Hash) {
+		base, err := c.mainProvisionersAt(forkPoint.Height)
+		if err != nil {
+			return nil, err
+		}
+		c.forkSet = &forkProvisioners{hash: forkPoint.Hash, height: forkPoint.Height, p: base}
+	}
+
+	// The cached provisioners are copied, as the stakes of the branch are
+	// added to them
+	buf := new(bytes.Buffer)
+	if err := user.MarshalProvisioners(buf, c.forkSet.p); err != nil {
+		return nil, err
+	}
+
+	p, err := user.UnmarshalProvisioners(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	params := cfg.GetConsensusParams()
+	for _, blk := range branch {
+		for _, tx := range blk.Txs {
+			if stake, ok := tx.(*transactions.Stake); ok {
+				amount := stake.Outputs[0].EncryptedAmount.BigInt().Uint64()
+				_ = addProvisioner(&p, stake.PubKeyEd, stake.PubKeyBLS, amount, params.StakeStart(blk.Header.Height), blk.Header.Height+stake.Lock)
+			}
+		}
+	}
+
+	return &p, nil
+}
+
+// mainProvisionersAt rebuilds the provisioners of the main chain at height,
+// from the stakes of the blocks which can still be unexpired, as
+// restoreConsensusData does
+func (c *Chain) mainProvisionersAt(height uint64) (*user.Provisioners, error) {
+	p := user.NewProvisioners()
+	searchingHeight := uint64(0)
+	if height > transactions.MaxLockTime {
+		searchingHeight = height - transactions.MaxLockTime
+	}
+
+	if s := c.snapshot; s != nil && height >= s.Tip.Header.Height {
+		restoreProvisioners(p, s, height)
+		if searchingHeight <= s.Tip.Header.Height {
+			searchingHeight = s.Tip.Header.Height + 1
+		}
+	}
+
+	params := cfg.GetConsensusParams()
+	err := c.db.View(func(t database.Transaction) error {
+		for ; searchingHeight <= height; searchingHeight++ {
+			hash, err := t.FetchBlockHashByHeight(searchingHeight)
+			if err != nil {
+				return err
+			}
+
+			blk, err := t.FetchBlock(hash)
+			if err != nil {
+				return err
+			}
+
+			for _, tx := range blk.Txs {
+				if stake, ok := tx.(*transactions.Stake); ok && searchingHeight+stake.Lock > height {
+					amount := stake.Outputs[0].EncryptedAmount.BigInt().Uint64()
+					_ = addProvisioner(p, stake.PubKeyEd, stake.PubKeyBLS, amount, params.StakeStart(searchingHeight), searchingHeight+stake.Lock)
+				}
+			}
+		}
+		return nil
+	})
+	return p, err
+}
+
+// mainWeight returns the weight of the main chain above height
+func (c *Chain) mainWeight(height uint64) (int, error) {
+	weight := 0
+	err := c.db.View(func(t database.Transaction) error {
+		for h := height + 1; h <= c.prevBlock.Header.Height; h++ {
+			hash, err := t.FetchBlockHashByHeight(h)
+			if err != nil {
+				return err
+			}
+
+			header, err := t.FetchBlockHeader(hash)
+			if err != nil {
+				return err
+			}
+			weight += blockWeight(header)
+		}
+		return nil
+	})
+	return weight, err
+}
+
+func branchWeight(branch []block.Block) int {
+	weight := 0
+	for _, blk := range branch {
+		weight += blockWeight(blk.Header)
+	}
+	return weight
+}
+
+// blockWeight is the amount of committee members which voted for the block
+// in its certificate. The heavier branch is the one which gathered the most
+// votes.
+func blockWeight(header *block.Header) int {
+	if header.Certificate == nil {
+		return 0
+	}
+
+	return bits.OnesCount64(header.Certificate.StepOneCommittee) + bits.OnesCount64(header.Certificate.StepTwoCommittee)
+}
+
+// pruneForks drops the forks which branch off too deep to be switched to,
+// and the provisioners cached for their fork point
+func (c *Chain) pruneForks() {
+	for hash, blk := range c.forks {
+		if blk.Header.Height+maxReorgDepth <= c.prevBlock.Header.Height {
+			delete(c.forks, hash)
+		}
+	}
+
+	if c.forkSet != nil && c.forkSet.height+maxReorgDepth <= c.prevBlock.Header.Height {
+		c.forkSet = nil
+	}
+}
+
+// forkProvisioners are the provisioners of the main chain at the block with
+// the given hash and height, see provisionersAt
+type forkProvisioners struct {
+	hash   []byte
+	height uint64
+	p      *user.Provisioners
+}
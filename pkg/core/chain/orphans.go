@@ -0,0 +1,261 @@
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/peermsg"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/block"
+	logger "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxOrphans bounds the blocks held until their previous block is
+	// accepted. The oldest one is dropped to make room for a new one. A
+	// sync requests up to 500 blocks at a time, which are held here when
+	// received out of order. Orphans further ahead of the tip are dropped.
+	maxOrphans = 500
+	// orphanExpiry is the time after which an orphan block is dropped, as
+	// its previous block is unlikely to arrive anymore
+	orphanExpiry = 10 * time.Minute
+	// sendTimeout bounds the time taken to queue a message for a peer
+	sendTimeout = 5 * time.Second
+)
+
+var orphansHeld = metrics.NewGauge("dusk_chain_orphans", "Blocks held until their previous block is accepted")
+
+type orphan struct {
+	blk block.Block
+	// the address of the peer which sent blk, if any
+	origin string
+	added  time.Time
+}
+
+// orphanPool holds the blocks whose previous block is unknown, by hash, and
+// indexes them by the hash of their previous block. It is safe for
+// concurrent use, as the blocks received are checked against it before
+// taking the locks of the Chain.
+type orphanPool struct {
+	lock   sync.Mutex
+	blocks map[string]orphan
+	byPrev map[string][]string
+}
+
+func newOrphanPool() *orphanPool {
+	return &orphanPool{
+		blocks: make(map[string]orphan),
+		byPrev: make(map[string][]string),
+	}
+}
+
+// add holds blk, received from the peer at origin, dropping the orphans
+// expired and, if the pool is full, the oldest one. It returns false if blk
+// was held already.
+func (p *orphanPool) add(blk block.Block, origin string, now time.Time) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	hash := string(blk.Header.Hash)
+	if _, ok := p.blocks[hash]; ok {
+		return false
+	}
+
+	var oldest string
+	for h, o := range p.blocks {
+		if now.Sub(o.added) >= orphanExpiry {
+			p.remove(h)
+			continue
+		}
+
+		if oldest == "" || o.added.Before(p.blocks[oldest].added) {
+			oldest = h
+		}
+	}
+
+	if len(p.blocks) >= maxOrphans {
+		p.remove(oldest)
+	}
+
+	p.blocks[hash] = orphan{blk, origin, now}
+	prev := string(blk.Header.PrevBlockHash)
+	p.byPrev[prev] = append(p.byPrev[prev], hash)
+	orphansHeld.Set(float64(len(p.blocks)))
+	return true
+}
+
+// take removes and returns the orphans building on the block with the given
+// hash
+func (p *orphanPool) take(prevHash []byte) []orphan {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	hashes := p.byPrev[string(prevHash)]
+	orphans := make([]orphan, 0, len(hashes))
+	for _, hash := range hashes {
+		orphans = append(orphans, p.blocks[hash])
+		p.remove(hash)
+	}
+
+	orphansHeld.Set(float64(len(p.blocks)))
+	return orphans
+}
+
+// has returns whether the block with the given hash is held
+func (p *orphanPool) has(hash []byte) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	_, ok := p.blocks[string(hash)]
+	return ok
+}
+
+// awaits returns whether orphans build on the block with the given hash
+func (p *orphanPool) awaits(hash []byte) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	_, ok := p.byPrev[string(hash)]
+	return ok
+}
+
+// remove drops the orphan with the given hash. The caller must hold p.lock.
+func (p *orphanPool) remove(hash string) {
+	o, ok := p.blocks[hash]
+	if !ok {
+		return
+	}
+	delete(p.blocks, hash)
+
+	prev := string(o.blk.Header.PrevBlockHash)
+	siblings := p.byPrev[prev]
+	for i, h := range siblings {
+		if h == hash {
+			siblings = append(siblings[:i], siblings[i+1:]...)
+			break
+		}
+	}
+
+	if len(siblings) == 0 {
+		delete(p.byPrev, prev)
+		return
+	}
+	p.byPrev[prev] = siblings
+}
+
+// isOrphan returns whether the previous block of blk is unknown, as neither
+// the tip, a competing tip nor a stored block. The caller must hold c.mu.
+func (c *Chain) isOrphan(blk block.Block) bool {
+	if bytes.Equal(blk.Header.PrevBlockHash, c.prevBlock.Header.Hash) {
+		return false
+	}
+
+	if _, ok := c.forks[string(blk.Header.PrevBlockHash)]; ok {
+		return false
+	}
+
+	err := c.db.View(func(t database.Transaction) error {
+		_, err := t.FetchBlockExists(blk.Header.PrevBlockHash)
+		return err
+	})
+	return err == database.ErrBlockNotFound
+}
+
+// addOrphan holds blk until its previous block is accepted, and requests the
+// previous block from origin, the peer which sent blk, unless it is held as
+// well or a sync is running. Its own previous block was requested then, and
+// the sync requests the blocks it misses.
+//
+// Orphans are dropped if their hash does not match their header, or if they
+// are not within reach of the tip: at most maxOrphans blocks ahead, and
+// above the deepest block a reorganization can switch from. The caller must
+// hold c.mu.
+func (c *Chain) addOrphan(blk block.Block, origin string) {
+	l := log.WithFields(logger.Fields{"height": blk.Header.Height, "peer": origin})
+	if err := c.checkOrphan(blk); err != nil {
+		l.WithError(err).Debugln("orphan block dropped")
+		return
+	}
+
+	if !c.orphans.add(blk, origin, time.Now()) {
+		return
+	}
+	l.Debugln("previous block unknown, orphan block held")
+
+	if c.orphans.has(blk.Header.PrevBlockHash) || c.counter.IsSyncing() || origin == "" {
+		return
+	}
+
+	if err := c.requestBlock(blk.Header.PrevBlockHash, origin); err != nil {
+		l.WithError(err).Warnln("could not request the previous block")
+	}
+}
+
+// checkOrphan runs the checks of blk which need neither its previous block
+// nor the chain state. The caller must hold c.mu.
+func (c *Chain) checkOrphan(blk block.Block) error {
+	tip := c.prevBlock.Header.Height
+	if blk.Header.Height > tip+maxOrphans {
+		return errors.New("too far ahead of the tip")
+	}
+
+	if blk.Header.Height+maxReorgDepth <= tip {
+		return ErrReorgTooDeep
+	}
+
+	return checkHeaderHash(blk.Header)
+}
+
+// acceptOrphans accepts the orphans building on the block with the given
+// hash, and the ones building on them in turn. The caller must hold c.mu.
+func (c *Chain) acceptOrphans(hash []byte) {
+	pending := c.orphans.take(hash)
+	for len(pending) > 0 {
+		blk, origin := pending[0].blk, pending[0].origin
+		pending = pending[1:]
+
+		var err error
+		if c.isFork(blk) {
+			err = c.acceptFork(blk, origin)
+		} else {
+			err = c.acceptBlock(blk, origin, nil)
+		}
+
+		if err != nil {
+			log.WithError(err).WithField("height", blk.Header.Height).Warnln("orphan block rejected")
+			continue
+		}
+
+		pending = append(pending, c.orphans.take(blk.Header.Hash)...)
+	}
+}
+
+// requestBlock asks the peer at address for the block with the given hash
+func (c *Chain) requestBlock(hash []byte, address string) error {
+	msg := &peermsg.Inv{}
+	msg.AddItem(peermsg.InvTypeBlock, hash)
+
+	buf := new(bytes.Buffer)
+	if err := msg.Encode(buf); err != nil {
+		return err
+	}
+
+	if err := topics.Prepend(buf, topics.GetData); err != nil {
+		return err
+	}
+
+	return c.sendToPeer(address, buf)
+}
+
+// sendToPeer sends msg, prepended with its topic, to the peer at address
+// alone
+func (c *Chain) sendToPeer(address string, msg *bytes.Buffer) error {
+	req := new(bytes.Buffer)
+	if err := rpcbus.MarshalSendToPeerRequest(req, address, msg); err != nil {
+		return err
+	}
+
+	_, err := c.rpcBus.Call(rpcbus.SendToPeer, rpcbus.NewRequest(*req), sendTimeout)
+	return err
+}
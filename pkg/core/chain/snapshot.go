@@ -0,0 +1,586 @@
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/utils"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/block"
+)
+
+// snapshotVersion is the version of the encoding of the snapshots
+const snapshotVersion uint8 = 1
+
+// Smallest encodings of the elements of the lists of a snapshot, which bound
+// their length by the size of the input
+const (
+	// version, height, timestamp, previous hash, seed, tx root and hash
+	minHeaderSize = 1 + 8 + 8 + 32 + 33 + 32 + 32
+	// X and M lengths, and end height
+	minBidSize = 1 + 1 + 8
+	// key image and tx ID lengths
+	minKeyImageSize = 1 + 1
+	// dest key length, unlock height and locked flag
+	minOutputSize = 1 + 8 + 1
+)
+
+// ErrTipMoved is returned when a block was accepted while a snapshot was
+// created. The snapshot can be written again.
+var ErrTipMoved = errors.New("chain tip moved while creating the snapshot")
+
+// ErrUntrustedSnapshot is returned when loading a snapshot without a
+// checkpoint. The state in a snapshot is not committed to by its blocks, so
+// it is only as trusted as the checkpoint of its tip.
+var ErrUntrustedSnapshot = errors.New("a snapshot can only be loaded along with the checkpoint of its tip")
+
+// Snapshot is the state of the chain at its tip. A node can be bootstrapped
+// from it without accepting the blocks below the tip, which are stored
+// without their txs.
+type Snapshot struct {
+	// Headers of the blocks from the genesis to the one before the tip
+	Headers      []*block.Header
+	Tip          *block.Block
+	Provisioners user.Provisioners
+	Bids         []database.Bid
+	KeyImages    []KeyImage
+	Outputs      []Output
+}
+
+// KeyImage is a key image spent, along with the ID of the tx spending it
+type KeyImage struct {
+	KeyImage []byte
+	TxID     []byte
+}
+
+// Output is an output of the chain, spendable from UnlockHeight
+type Output struct {
+	DestKey      []byte
+	UnlockHeight uint64
+	Locked       bool
+}
+
+// createSnapshot collects the state of the chain at its tip. Only the tip
+// and the provisioners are read under c.mu: the blocks are then read from a
+// view of the database, so that blocks can be accepted meanwhile. If the tip
+// of the view is not the one the provisioners were taken at, ErrTipMoved is
+// returned.
+func (c *Chain) createSnapshot() (*Snapshot, error) {
+	c.mu.RLock()
+	tip := c.prevBlock.Header
+	buf, err := c.marshalProvisioners()
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{}
+	if s.Provisioners, err = user.UnmarshalProvisioners(buf); err != nil {
+		return nil, err
+	}
+
+	err = c.db.View(func(t database.Transaction) error {
+		state, err := t.FetchState()
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(state.TipHash, tip.Hash) {
+			return ErrTipMoved
+		}
+
+		for height := uint64(0); height <= tip.Height; height++ {
+			hash, err := t.FetchBlockHashByHeight(height)
+			if err != nil {
+				return err
+			}
+
+			blk, err := t.FetchBlock(hash)
+			if err != nil {
+				return err
+			}
+
+			if height == tip.Height {
+				s.Tip = blk
+			} else {
+				s.Headers = append(s.Headers, blk.Header)
+			}
+
+			if err := s.addTxs(blk); err != nil {
+				return err
+			}
+		}
+
+		s.Bids, err = t.FetchBids(tip.Height + 1)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// addTxs adds the key images and outputs of the txs of blk, as stored by
+// StoreBlock
+func (s *Snapshot) addTxs(blk *block.Block) error {
+	for _, tx := range blk.Txs {
+		txID, err := marshalling.TxID(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, input := range tx.StandardTx().Inputs {
+			s.KeyImages = append(s.KeyImages, KeyImage{input.KeyImage.Bytes(), txID})
+		}
+
+		for i, output := range tx.StandardTx().Outputs {
+			o := Output{DestKey: output.PubKey.P.Bytes()}
+			o.UnlockHeight, o.Locked = utils.OutputLock(tx, i, blk.Header.Height)
+			s.Outputs = append(s.Outputs, o)
+		}
+	}
+	return nil
+}
+
+// WriteSnapshot writes a snapshot of the chain at its tip to path. The key
+// images and outputs are not stored in headers-only mode, so no snapshot can
+// be written then.
+func (c *Chain) WriteSnapshot(path string) error {
+	_, err := c.writeSnapshot(path)
+	return err
+}
+
+// writeSnapshot writes a snapshot to path, and returns the tip of the
+// snapshot
+func (c *Chain) writeSnapshot(path string) (*block.Header, error) {
+	if c.headersOnly {
+		return nil, ErrHeadersOnly
+	}
+
+	s, err := c.createSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := MarshalSnapshot(buf, s); err != nil {
+		return nil, err
+	}
+
+	// Written aside first, so that an interrupted write does not leave a
+	// truncated snapshot behind
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return nil, err
+	}
+	return s.Tip.Header, os.Rename(tmp, path)
+}
+
+// provideSnapshot writes a snapshot to the path in the params, and sends
+// back the height of its tip, encoded as a little endian uint64
+func (c *Chain) provideSnapshot(r rpcbus.Request) (bytes.Buffer, error) {
+	path, err := encoding.ReadString(&r.Params)
+	if err != nil {
+		return bytes.Buffer{}, errors.New("path cannot be read from request param")
+	}
+
+	tip, err := c.writeSnapshot(path)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	buf := new(bytes.Buffer)
+	err = encoding.WriteUint64LE(buf, tip.Height)
+	return *buf, err
+}
+
+// ReadSnapshot reads the snapshot written at path
+func ReadSnapshot(path string) (*Snapshot, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalSnapshot(bytes.NewBuffer(content))
+}
+
+// check checks that the headers of s link the genesis block to its tip,
+// which is the block at the checkpoint, and that the state of s is well
+// formed. Nothing is stored before s is checked.
+func (s *Snapshot) check(genesis *block.Header, height uint64, hash []byte) error {
+	headers := make([]*block.Header, 0, len(s.Headers)+1)
+	headers = append(append(headers, s.Headers...), s.Tip.Header)
+	if !bytes.Equal(headers[0].Hash, genesis.Hash) {
+		return errors.New("snapshot of another chain")
+	}
+
+	for i := 1; i < len(headers); i++ {
+		if headers[i].Height != uint64(i) || !bytes.Equal(headers[i].PrevBlockHash, headers[i-1].Hash) {
+			return fmt.Errorf("snapshot broken at height %d", i)
+		}
+
+		if err := checkHeaderHash(headers[i]); err != nil {
+			return fmt.Errorf("snapshot broken at height %d: %v", i, err)
+		}
+	}
+
+	if s.Tip.Header.Height != height || !bytes.Equal(s.Tip.Header.Hash, hash) {
+		return errors.New("snapshot tip does not match the checkpoint")
+	}
+
+	root, err := marshalling.TxRoot(s.Tip.Txs)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(root, s.Tip.Header.TxRoot) {
+		return errors.New("snapshot tip does not match its txs")
+	}
+
+	return s.checkState()
+}
+
+// checkState checks that the state of s can be stored
+func (s *Snapshot) checkState() error {
+	for _, k := range s.KeyImages {
+		if len(k.KeyImage) != 32 || len(k.TxID) != 32 {
+			return errors.New("malformed key image in snapshot")
+		}
+	}
+
+	for _, o := range s.Outputs {
+		if len(o.DestKey) != 32 {
+			return errors.New("malformed output in snapshot")
+		}
+	}
+
+	for _, bid := range s.Bids {
+		if len(bid.X) != 32 || len(bid.M) != 32 || bid.EndHeight <= s.Tip.Header.Height {
+			return errors.New("malformed bid in snapshot")
+		}
+	}
+
+	for _, m := range s.Provisioners.Members {
+		if len(m.PublicKeyEd) != 32 || len(m.Stakes) == 0 {
+			return errors.New("malformed provisioner in snapshot")
+		}
+
+		for _, stake := range m.Stakes {
+			if stake.StartHeight > stake.EndHeight {
+				return errors.New("malformed stake in snapshot")
+			}
+		}
+	}
+	return nil
+}
+
+// checkHeaderHash checks that header is hashed as it should
+func checkHeaderHash(header *block.Header) error {
+	h := *header
+	blk := &block.Block{Header: &h}
+	if err := blk.SetHash(); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(h.Hash, header.Hash) {
+		return errors.New("invalid block hash")
+	}
+	return nil
+}
+
+// loadSnapshot reads the snapshot at path, and imports it into db if the tip
+// of db is the genesis block. Otherwise, the snapshot must be part of the
+// chain stored. Either way, the tip of the snapshot must be the checkpoint at
+// height, with the given hash. It returns the snapshot, and the tip of db.
+func loadSnapshot(db database.DB, path string, tip *block.Block, height uint64, hash []byte) (*Snapshot, *block.Block, error) {
+	if hash == nil {
+		return nil, nil, ErrUntrustedSnapshot
+	}
+
+	s, err := ReadSnapshot(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.check(cfg.DecodeGenesis().Header, height, hash); err != nil {
+		return nil, nil, err
+	}
+
+	if tip.Header.Height == 0 {
+		if err := importSnapshot(db, s); err != nil {
+			return nil, nil, err
+		}
+
+		log.WithField("height", s.Tip.Header.Height).Infoln("chain bootstrapped from snapshot")
+		return s, s.Tip, nil
+	}
+
+	var stored []byte
+	err = db.View(func(t database.Transaction) error {
+		var err error
+		stored, err = t.FetchBlockHashByHeight(s.Tip.Header.Height)
+		return err
+	})
+	if err != nil || !bytes.Equal(stored, s.Tip.Header.Hash) {
+		return nil, nil, errors.New("snapshot is not part of the chain stored")
+	}
+
+	return s, tip, nil
+}
+
+// trustHeaders records the blocks of s as leading to the checkpoint
+func (c *Chain) trustHeaders(s *Snapshot) {
+	for _, header := range s.Headers {
+		c.trusted[header.Height] = header.Hash
+	}
+	c.trusted[s.Tip.Header.Height] = s.Tip.Header.Hash
+}
+
+// importSnapshot stores s in db, on top of the genesis block
+func importSnapshot(db database.DB, s *Snapshot) error {
+	return db.Update(func(t database.Transaction) error {
+		for _, header := range s.Headers {
+			// The genesis block is stored already
+			if header.Height == 0 {
+				continue
+			}
+
+			if err := t.StoreBlock(&block.Block{Header: header}); err != nil {
+				return err
+			}
+		}
+
+		if err := t.StoreBlock(s.Tip); err != nil {
+			return err
+		}
+
+		for _, k := range s.KeyImages {
+			if err := t.StoreKeyImage(k.KeyImage, k.TxID); err != nil {
+				return err
+			}
+		}
+
+		for _, o := range s.Outputs {
+			if err := t.StoreOutput(o.DestKey, o.UnlockHeight, o.Locked); err != nil {
+				return err
+			}
+		}
+
+		for _, bid := range s.Bids {
+			if err := t.StoreBid(bid.X, bid.M, bid.EndHeight); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// restoreProvisioners adds the provisioners of s whose stake is still valid
+// after height to p
+func restoreProvisioners(p *user.Provisioners, s *Snapshot, height uint64) {
+	for _, m := range s.Provisioners.Members {
+		for _, stake := range m.Stakes {
+			if stake.EndHeight > height {
+				_ = addProvisioner(p, m.PublicKeyEd, m.PublicKeyBLS, stake.Amount, stake.StartHeight, stake.EndHeight)
+			}
+		}
+	}
+}
+
+// MarshalSnapshot encodes s into w
+func MarshalSnapshot(w *bytes.Buffer, s *Snapshot) error {
+	if err := encoding.WriteUint8(w, snapshotVersion); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteVarInt(w, uint64(len(s.Headers))); err != nil {
+		return err
+	}
+
+	for _, header := range s.Headers {
+		if err := marshalling.MarshalHeader(w, header); err != nil {
+			return err
+		}
+	}
+
+	if err := marshalling.MarshalBlock(w, s.Tip); err != nil {
+		return err
+	}
+
+	if err := user.MarshalProvisioners(w, &s.Provisioners); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteVarInt(w, uint64(len(s.Bids))); err != nil {
+		return err
+	}
+
+	for _, bid := range s.Bids {
+		if err := encoding.WriteVarBytes(w, bid.X); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteVarBytes(w, bid.M); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteUint64LE(w, bid.EndHeight); err != nil {
+			return err
+		}
+	}
+
+	if err := encoding.WriteVarInt(w, uint64(len(s.KeyImages))); err != nil {
+		return err
+	}
+
+	for _, k := range s.KeyImages {
+		if err := encoding.WriteVarBytes(w, k.KeyImage); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteVarBytes(w, k.TxID); err != nil {
+			return err
+		}
+	}
+
+	if err := encoding.WriteVarInt(w, uint64(len(s.Outputs))); err != nil {
+		return err
+	}
+
+	for _, o := range s.Outputs {
+		if err := encoding.WriteVarBytes(w, o.DestKey); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteUint64LE(w, o.UnlockHeight); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteBool(w, o.Locked); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalSnapshot decodes a Snapshot from r
+func UnmarshalSnapshot(r *bytes.Buffer) (*Snapshot, error) {
+	var version uint8
+	if err := encoding.ReadUint8(r, &version); err != nil {
+		return nil, err
+	}
+
+	if version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", version)
+	}
+
+	s := &Snapshot{}
+	count, err := readCount(r, minHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Headers = make([]*block.Header, 0, count)
+	for i := uint64(0); i < count; i++ {
+		header := block.NewHeader()
+		if err := marshalling.UnmarshalHeader(r, header); err != nil {
+			return nil, err
+		}
+		s.Headers = append(s.Headers, header)
+	}
+
+	s.Tip = block.NewBlock()
+	if err := marshalling.UnmarshalBlock(r, s.Tip); err != nil {
+		return nil, err
+	}
+
+	if s.Provisioners, err = user.UnmarshalProvisioners(r); err != nil {
+		return nil, err
+	}
+
+	if count, err = readCount(r, minBidSize); err != nil {
+		return nil, err
+	}
+
+	s.Bids = make([]database.Bid, 0, count)
+	for i := uint64(0); i < count; i++ {
+		bid := database.Bid{}
+		if err := encoding.ReadVarBytes(r, &bid.X); err != nil {
+			return nil, err
+		}
+
+		if err := encoding.ReadVarBytes(r, &bid.M); err != nil {
+			return nil, err
+		}
+
+		if err := encoding.ReadUint64LE(r, &bid.EndHeight); err != nil {
+			return nil, err
+		}
+		s.Bids = append(s.Bids, bid)
+	}
+
+	if count, err = readCount(r, minKeyImageSize); err != nil {
+		return nil, err
+	}
+
+	s.KeyImages = make([]KeyImage, 0, count)
+	for i := uint64(0); i < count; i++ {
+		k := KeyImage{}
+		if err := encoding.ReadVarBytes(r, &k.KeyImage); err != nil {
+			return nil, err
+		}
+
+		if err := encoding.ReadVarBytes(r, &k.TxID); err != nil {
+			return nil, err
+		}
+		s.KeyImages = append(s.KeyImages, k)
+	}
+
+	if count, err = readCount(r, minOutputSize); err != nil {
+		return nil, err
+	}
+
+	s.Outputs = make([]Output, 0, count)
+	for i := uint64(0); i < count; i++ {
+		o := Output{}
+		if err := encoding.ReadVarBytes(r, &o.DestKey); err != nil {
+			return nil, err
+		}
+
+		if err := encoding.ReadUint64LE(r, &o.UnlockHeight); err != nil {
+			return nil, err
+		}
+
+		if err := encoding.ReadBool(r, &o.Locked); err != nil {
+			return nil, err
+		}
+		s.Outputs = append(s.Outputs, o)
+	}
+
+	return s, nil
+}
+
+// readCount reads the length of a list whose elements are encoded in size
+// bytes at least. The length can not exceed what is left of r, so that a
+// malformed snapshot can not trigger a huge allocation.
+func readCount(r *bytes.Buffer, size int) (uint64, error) {
+	count, err := encoding.ReadVarInt(r)
+	if err != nil {
+		return 0, err
+	}
+
+	if count > uint64(r.Len()/size) {
+		return 0, errors.New("invalid list length in snapshot")
+	}
+	return count, nil
+}
@@ -0,0 +1,8 @@
+package chain
+
+// tipHeight returns the height of the chain tip
+func (c *Chain) tipHeight() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.prevBlock.Header.Height
+}
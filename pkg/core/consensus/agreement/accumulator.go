@@ -3,6 +3,7 @@ package agreement
 import (
 	"sync"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -10,19 +11,26 @@ import (
 // reaches a certain threshold.
 type Accumulator struct {
 	handler            Handler
-	verificationChan   chan Agreement
-	eventChan          chan Agreement
+	verificationChan   chan pendingVote
+	eventChan          chan pendingVote
 	CollectedVotesChan chan []Agreement
 	store              *store
 }
 
+// pendingVote is an Agreement going through the Accumulator, along with the
+// span tracing it, if any
+type pendingVote struct {
+	Agreement
+	span *tracing.Span
+}
+
 // NewAccumulator initializes a worker pool, starts up an Accumulator and returns it.
 func newAccumulator(handler Handler, workerAmount int) *Accumulator {
 	// create accumulator
 	a := &Accumulator{
 		handler:            handler,
-		verificationChan:   make(chan Agreement, 100),
-		eventChan:          make(chan Agreement, 100),
+		verificationChan:   make(chan pendingVote, 100),
+		eventChan:          make(chan pendingVote, 100),
 		CollectedVotesChan: make(chan []Agreement, 1),
 		store:              newStore(),
 	}
@@ -35,6 +43,12 @@ func newAccumulator(handler Handler, workerAmount int) *Accumulator {
 // Process a received Event, by passing it to a worker in the worker pool (if the event
 // sender is part of the voting committee).
 func (a *Accumulator) Process(ev Agreement) {
+	a.process(ev, nil)
+}
+
+// process an Event, tracing its verification and accumulation as children of
+// span. span is finished once the Event is accumulated or discarded.
+func (a *Accumulator) process(ev Agreement, span *tracing.Span) {
 	defer func() {
 		// we recover from panic in case of a late Process call which would attempt to write to the closed verificationChan
 		// the alternative would be to never close the verificationChan and either use a multitude of channels to  stop the workers or a shared boolean set in the Accumulator.Stop
@@ -43,17 +57,21 @@ func (a *Accumulator) Process(ev Agreement) {
 		}
 	}()
 
-	a.verificationChan <- ev
+	a.verificationChan <- pendingVote{ev, span}
 }
 
 // Accumulate agreements per block hash until a quorum is reached or a stop is detected (by closing the internal event channel). Supposed to run in a goroutine
 func (a *Accumulator) Accumulate() {
 	for ev := range a.eventChan {
+		accumulateSpan := ev.span.StartChild("vote.accumulate")
 		collected := a.store.Get(ev.Step)
 		weight := a.handler.VotesFor(ev.PubKeyBLS, ev.Round, ev.Step)
-		count := a.store.Insert(ev, weight)
+		count := a.store.Insert(ev.Agreement, weight)
 		if count == len(collected) {
 			lg.Warnln("Agreement was not accumulated since it is a duplicate")
+			accumulateSpan.SetAttribute("duplicate", "true")
+			accumulateSpan.Finish()
+			ev.span.Finish()
 			continue
 		}
 
@@ -62,10 +80,17 @@ func (a *Accumulator) Accumulate() {
 			"quorum": a.handler.Quorum(ev.Round),
 		}).Debugln("collected agreement")
 		if count >= a.handler.Quorum(ev.Round) {
+			quorumSpan := accumulateSpan.StartChild("vote.quorum")
 			votes := a.store.Get(ev.Step)
 			a.CollectedVotesChan <- votes
+			quorumSpan.Finish()
+			accumulateSpan.Finish()
+			ev.span.Finish()
 			return
 		}
+
+		accumulateSpan.Finish()
+		ev.span.Finish()
 	}
 }
 
@@ -87,11 +112,15 @@ func (a *Accumulator) CreateWorkers(amount int) {
 	}()
 }
 
-func verify(verificationChan <-chan Agreement, eventChan chan<- Agreement, verifyFunc func(Agreement) error, wg *sync.WaitGroup) {
+func verify(verificationChan <-chan pendingVote, eventChan chan<- pendingVote, verifyFunc func(Agreement) error, wg *sync.WaitGroup) {
 	for ev := range verificationChan {
-
-		if err := verifyFunc(ev); err != nil {
+		verifySpan := ev.span.StartChild("vote.verify")
+		err := verifyFunc(ev.Agreement)
+		verifySpan.Finish()
+		if err != nil {
 			lg.WithError(err).Errorln("event verification failed")
+			ev.span.SetAttribute("error", err.Error())
+			ev.span.Finish()
 			continue
 		}
 
@@ -99,6 +128,8 @@ func verify(verificationChan <-chan Agreement, eventChan chan<- Agreement, verif
 		case eventChan <- ev:
 		default:
 			lg.Warnln("accumulator skipped sending event")
+			ev.span.SetAttribute("error", "accumulator busy")
+			ev.span.Finish()
 		}
 	}
 	wg.Done()
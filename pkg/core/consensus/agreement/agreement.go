@@ -7,6 +7,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
@@ -17,6 +18,8 @@ import (
 
 var lg = log.WithField("process", "agreement")
 
+var quorums = metrics.NewCounter("dusk_consensus_quorums_total", "Agreement quorums reached")
+
 var _ consensus.Component = (*agreement)(nil)
 
 type agreement struct {
@@ -75,8 +78,10 @@ func (a *agreement) Filter(hdr header.Header) bool {
 // CollectAgreementEvent is the callback to get Events from the Coordinator. It forwards
 // the events to the accumulator until Quorum is reached
 func (a *agreement) CollectAgreementEvent(event consensus.Event) error {
+	span := consensus.StartVoteSpan(topics.Agreement, event)
 	ev, err := convertToAgreement(event)
 	if err != nil {
+		span.Finish()
 		return err
 	}
 
@@ -86,7 +91,7 @@ func (a *agreement) CollectAgreementEvent(event consensus.Event) error {
 		"sender": hex.EncodeToString(event.Header.Sender()),
 		"id":     a.agreementID,
 	}).Debugln("received event")
-	a.accumulator.Process(*ev)
+	a.accumulator.process(*ev, span)
 	return nil
 }
 
@@ -103,6 +108,7 @@ func (a *agreement) listen() {
 	select {
 	case evs := <-a.accumulator.CollectedVotesChan:
 		lg.WithField("id", a.agreementID).Debugln("quorum reached")
+		quorums.Inc()
 		// Start a goroutine here to release the lock held by
 		// Coordinator.CollectEvent
 		go a.sendCertificate(evs[0])
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/sortedset"
 	"github.com/dusk-network/dusk-crypto/bls"
 	"github.com/dusk-network/dusk-wallet/key"
@@ -78,3 +80,31 @@ func genReduction(hash []byte, set *sortedset.Set) ([]byte, []byte, uint8) {
 
 	return s.Compress(), k.BLSPubKeyBytes, uint8(1)
 }
+
+func BenchmarkAgreementMarshal(b *testing.B) {
+	p, keys := consensus.MockProvisioners(50)
+	ev := MockAgreementEvent(make([]byte, 32), 1, 3, keys, p)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf := new(bytes.Buffer)
+		if err := Marshal(buf, *ev); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAgreementUnmarshal(b *testing.B) {
+	p, keys := consensus.MockProvisioners(50)
+	encoded := MockAgreement(make([]byte, 32), 1, 3, keys, p).Bytes()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ev := New(header.Header{})
+		if err := Unmarshal(bytes.NewBuffer(encoded), ev); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
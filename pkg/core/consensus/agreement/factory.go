@@ -19,7 +19,7 @@ type Factory struct {
 
 // NewFactory instantiates a Factory.
 func NewFactory(broker eventbus.Broker, keys key.ConsensusKeys) *Factory {
-	amount := cfg.Get().Performance.AccumulatorWorkers
+	amount := cfg.GetConsensusParams().AccumulatorWorkers
 	r := republisher.New(broker, topics.Agreement)
 
 	return &Factory{
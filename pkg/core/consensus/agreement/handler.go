@@ -6,17 +6,16 @@ import (
 	"fmt"
 	"math"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/committee"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
-	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/msg"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	blsutil "github.com/dusk-network/dusk-blockchain/pkg/crypto/bls"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/sortedset"
 	"github.com/dusk-network/dusk-crypto/bls"
 	"github.com/dusk-network/dusk-wallet/key"
 )
 
-const MaxCommitteeSize = 64
-
 // Handler interface is handy for tests
 type Handler interface {
 	AmMember(uint64, uint8) bool
@@ -29,34 +28,39 @@ type Handler interface {
 
 type handler struct {
 	*committee.Handler
+	maxCommitteeSize int
+	quorumRatio      float64
 }
 
 // newHandler returns an initialized handler.
 func newHandler(keys key.ConsensusKeys, p user.Provisioners) *handler {
+	params := cfg.GetConsensusParams()
 	return &handler{
-		Handler: committee.NewHandler(keys, p),
+		Handler:          committee.NewHandler(keys, p),
+		maxCommitteeSize: params.AgreementCommitteeSize,
+		quorumRatio:      params.QuorumRatio,
 	}
 }
 
 // AmMember checks if we are part of the committee.
 func (a *handler) AmMember(round uint64, step uint8) bool {
-	return a.Handler.AmMember(round, step, MaxCommitteeSize)
+	return a.Handler.AmMember(round, step, a.maxCommitteeSize)
 }
 
 func (a *handler) IsMember(pubKeyBLS []byte, round uint64, step uint8) bool {
-	return a.Handler.IsMember(pubKeyBLS, round, step, MaxCommitteeSize)
+	return a.Handler.IsMember(pubKeyBLS, round, step, a.maxCommitteeSize)
 }
 
 func (a *handler) Committee(round uint64, step uint8) user.VotingCommittee {
-	return a.Handler.Committee(round, step, MaxCommitteeSize)
+	return a.Handler.Committee(round, step, a.maxCommitteeSize)
 }
 
 func (a *handler) VotesFor(pubKeyBLS []byte, round uint64, step uint8) int {
-	return a.Handler.VotesFor(pubKeyBLS, round, step, MaxCommitteeSize)
+	return a.Handler.VotesFor(pubKeyBLS, round, step, a.maxCommitteeSize)
 }
 
 func (a *handler) Quorum(round uint64) int {
-	return int(math.Ceil(float64(a.CommitteeSize(round, MaxCommitteeSize)) * 0.75))
+	return int(math.Ceil(float64(a.CommitteeSize(round, a.maxCommitteeSize)) * a.quorumRatio))
 }
 
 // Verify checks the signature of the set.
@@ -94,29 +98,19 @@ func verifyWhole(a Agreement) error {
 		return err
 	}
 
-	return msg.VerifyBLSSignature(a.Header.PubKeyBLS, r.Bytes(), a.SignedVotes())
+	return blsutil.VerifySignature(a.Header.PubKeyBLS, r.Bytes(), a.SignedVotes())
 }
 
 // ReconstructApk reconstructs an aggregated BLS public key from a subcommittee.
 func ReconstructApk(subcommittee sortedset.Set) (*bls.Apk, error) {
-	var apk *bls.Apk
 	if len(subcommittee) == 0 {
 		return nil, errors.New("Subcommittee is empty")
 	}
 
+	keys := make([][]byte, len(subcommittee))
 	for i, ipk := range subcommittee {
-		pk, err := bls.UnmarshalPk(ipk.Bytes())
-		if err != nil {
-			return nil, err
-		}
-		if i == 0 {
-			apk = bls.NewApk(pk)
-			continue
-		}
-		if err := apk.Aggregate(pk); err != nil {
-			return nil, err
-		}
+		keys[i] = ipk.Bytes()
 	}
 
-	return apk, nil
+	return blsutil.AggregateKeys(keys)
 }
@@ -212,5 +212,5 @@ func mockReduction(hash []byte, round uint64, step uint8, keys []key.ConsensusKe
 	_ = header.MarshalSignableVote(r, hdr)
 	sigma, _ := bls.Sign(keys[idx].BLSSecretKey, keys[idx].BLSPubKey, r.Bytes())
 	signedHash := sigma.Compress()
-	return consensus.Event{hdr, *bytes.NewBuffer(signedHash)}
+	return consensus.Event{Header: hdr, Payload: *bytes.NewBuffer(signedHash)}
 }
@@ -11,7 +11,10 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/generation/score"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/selection"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/fees"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/versionbits"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/dusk-network/dusk-wallet/block"
@@ -27,9 +30,6 @@ var _ consensus.Component = (*Generator)(nil)
 
 var lg *log.Entry = log.WithField("process", "candidate generator")
 
-// TBD along with block size and processing.MaxFrameSize
-const MaxTxSetSize = 150000
-
 // Generator is responsible for generating candidate blocks, and propagating them
 // alongside received Scores. It is triggered by the ScoreEvent, sent by the score generator.
 type Generator struct {
@@ -119,7 +119,7 @@ func (bg *Generator) Collect(e consensus.Event) error {
 	}
 
 	// Create candidate message
-	certBuf, err := bg.rpcBus.Call(rpcbus.GetLastCertificate, rpcbus.Request{bytes.Buffer{}, make(chan rpcbus.Response, 1)}, 5*time.Second)
+	certBuf, err := bg.rpcBus.Call(rpcbus.GetLastCertificate, rpcbus.NewRequest(bytes.Buffer{}), 5*time.Second)
 	if err != nil {
 		return err
 	}
@@ -133,6 +133,10 @@ func (bg *Generator) Collect(e consensus.Event) error {
 		return err
 	}
 
+	// The candidate broker keeps our candidate, to serve it to peers and
+	// to republish it when the consensus restarts
+	bg.publisher.Publish(topics.GeneratedCandidate, bytes.NewBuffer(buf.Bytes()))
+
 	// Since the Candidate message goes straight to the Chain, there is
 	// no need to use `SendAuthenticated`, as the header is irrelevant.
 	// Thus, we will instead gossip it directly.
@@ -159,7 +163,7 @@ func (bg *Generator) GenerateBlock(round uint64, seed, proof, score, prevBlockHa
 
 	// Construct header
 	h := &block.Header{
-		Version:       0,
+		Version:       versionbits.BlockVersion(round),
 		Timestamp:     time.Now().Unix(),
 		Height:        round,
 		PrevBlockHash: prevBlockHash,
@@ -175,7 +179,7 @@ func (bg *Generator) GenerateBlock(round uint64, seed, proof, score, prevBlockHa
 	}
 
 	// Update TxRoot
-	if err := candidateBlock.SetRoot(); err != nil {
+	if err := marshalling.SetTxRoot(candidateBlock); err != nil {
 		return nil, err
 	}
 
@@ -205,8 +209,13 @@ func (bg *Generator) ConstructBlockTxs(proof, score []byte) ([]transactions.Tran
 	if bg.rpcBus != nil {
 
 		// Max transaction size param
+		budget, err := txsBudget(coinbaseTx)
+		if err != nil {
+			return nil, err
+		}
+
 		param := new(bytes.Buffer)
-		if err := encoding.WriteUint32LE(param, uint32(MaxTxSetSize)); err != nil {
+		if err := encoding.WriteUint32LE(param, budget); err != nil {
 			return nil, err
 		}
 
@@ -228,6 +237,22 @@ func (bg *Generator) ConstructBlockTxs(proof, score []byte) ([]transactions.Tran
 				return nil, err
 			}
 
+			if err := verifiers.CheckTxLimits(tx); err != nil {
+				lg.WithError(err).Warnln("skipping mempool tx")
+				continue
+			}
+
+			// The minimum relay fee may have been raised since the tx
+			// entered the mempool
+			rate, err := fees.TxRate(tx)
+			if err != nil {
+				return nil, err
+			}
+
+			if rate < fees.MinRelayFee() {
+				continue
+			}
+
 			txs = append(txs, tx)
 		}
 	}
@@ -237,6 +262,24 @@ func (bg *Generator) ConstructBlockTxs(proof, score []byte) ([]transactions.Tran
 	return txs, nil
 }
 
+// txsBudget returns the space left for the mempool txs in a block holding the
+// given coinbase, so that the block does not exceed the maximum size
+func txsBudget(coinbase transactions.Transaction) (uint32, error) {
+	buf := new(bytes.Buffer)
+	if err := marshalling.MarshalTx(buf, coinbase); err != nil {
+		return 0, err
+	}
+
+	// The tx count is a VarInt, taking at most 9 bytes
+	overhead := uint32(marshalling.HeaderSize + 9 + buf.Len())
+	maxSize := config.GetLimits().MaxBlockSize
+	if maxSize <= overhead {
+		return 0, nil
+	}
+
+	return maxSize - overhead, nil
+}
+
 // ConstructCoinbaseTx forges the transaction to reward the block generator.
 func constructCoinbaseTx(rewardReceiver *key.PublicKey, proof []byte, score []byte) (*transactions.Coinbase, error) {
 	// The rewards for both the Generator and the Provisioners are disclosed.
@@ -94,7 +94,7 @@ func (h *Helper) Initialize(ru consensus.RoundUpdate) {
 
 func provideCertificate(rpcBus *rpcbus.RPCBus) {
 	c := make(chan rpcbus.Request, 1)
-	rpcBus.Register(rpcbus.GetLastCertificate, c)
+	rpcBus.RegisterChan(rpcbus.GetLastCertificate, c)
 
 	go func(c chan rpcbus.Request) {
 		r := <-c
@@ -113,14 +113,14 @@ func (h *Helper) TriggerBlockGeneration() {
 		panic(err)
 	}
 
-	h.Generator.Collect(consensus.Event{header.Header{}, *buf})
+	h.Generator.Collect(consensus.Event{Header: header.Header{}, Payload: *buf})
 }
 
 // ProvideTransactions sends a set of transactions upon the request of
 // the blockgenerator, standing in place of the mempool.
 func (h *Helper) ProvideTransactions(t *testing.T) {
 	reqChan := make(chan rpcbus.Request, 1)
-	h.RBus.Register(rpcbus.GetMempoolTxsBySize, reqChan)
+	h.RBus.RegisterChan(rpcbus.GetMempoolTxsBySize, reqChan)
 
 	go func(reqChan chan rpcbus.Request) {
 		r := <-reqChan
@@ -2,10 +2,13 @@ package consensus
 
 import (
 	"bytes"
+	"encoding/hex"
+	"strconv"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 )
 
 var _ wire.Event = (*Event)(nil)
@@ -18,7 +21,7 @@ type TopicEvent struct {
 
 func NewTopicEvent(topic topics.Topic, hdr header.Header, payload bytes.Buffer) TopicEvent {
 	return TopicEvent{
-		Event{hdr, payload},
+		Event{Header: hdr, Payload: payload},
 		topic,
 	}
 }
@@ -30,6 +33,9 @@ func NewTopicEvent(topic topics.Topic, hdr header.Header, payload bytes.Buffer)
 type Event struct {
 	Header  header.Header
 	Payload bytes.Buffer
+	// ID is the correlation ID of the message the event was received with,
+	// if any. It is not part of the event itself.
+	ID tracing.ID
 }
 
 // Sender returns the BLS public key of the event sender.
@@ -46,3 +52,22 @@ func (e Event) Equal(ev wire.Event) bool {
 
 	return e.Header.Equal(&ce.Header) && bytes.Equal(e.Payload.Bytes(), ce.Payload.Bytes())
 }
+
+// StartVoteSpan starts the span tracing the handling of a vote received on
+// topic. The stages of the handling are traced as its children. It returns
+// nil if the event is not traced.
+func StartVoteSpan(topic topics.Topic, e Event) *tracing.Span {
+	span := tracing.StartSpan(e.ID, "vote.receive")
+	if span == nil {
+		return nil
+	}
+
+	span.SetAttribute("topic", topic.String())
+	span.SetAttribute("round", strconv.FormatUint(e.Header.Round, 10))
+	span.SetAttribute("step", strconv.Itoa(int(e.Header.Step)))
+	span.SetAttribute("hash", hex.EncodeToString(e.Header.BlockHash))
+	if origin := tracing.Origin(e.ID); origin != "" {
+		span.SetAttribute("net.peer.name", origin)
+	}
+	return span
+}
@@ -3,11 +3,13 @@ package factory
 import (
 	"time"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/candidate"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/generation"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/generation/score"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/participation"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction/firststep"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction/secondstep"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/selection"
@@ -54,6 +56,24 @@ func (c *ConsensusFactory) StartConsensus() {
 	redSecondStep := secondstep.NewFactory(c.eventBus, c.rpcBus, c.ConsensusKeys, c.timerLength)
 	agr := agreement.NewFactory(c.eventBus, c.ConsensusKeys)
 
-	consensus.Start(c.eventBus, c.ConsensusKeys, cgen, sgen, sel, redFirstStep, redSecondStep, agr, gen)
+	// A new step timeout applies to the components instantiated on the next
+	// round update
+	cfg.Subscribe("consensus", func(r cfg.Registry) {
+		timeout := r.ConsensusParams().StepTimeout
+		sel.SetTimeout(timeout)
+		redFirstStep.SetTimeout(timeout)
+		redSecondStep.SetTimeout(timeout)
+	})
+
+	coordinator := consensus.Start(c.eventBus, c.ConsensusKeys, cgen, sgen, sel, redFirstStep, redSecondStep, agr, gen)
+	if err := c.rpcBus.Register(rpcbus.GetConsensusState, coordinator.ProvideState); err != nil {
+		log.WithField("process", "factory").WithError(err).Warnln("could not serve the consensus state")
+	}
+
+	// Keep track of the rounds this node votes in
+	tracker := participation.New(c.eventBus, c.ConsensusKeys.BLSPubKeyBytes)
+	if err := c.rpcBus.Register(rpcbus.GetParticipation, tracker.ProvideStats); err != nil {
+		log.WithField("process", "factory").WithError(err).Warnln("could not serve the participation")
+	}
 	log.WithField("process", "factory").Info("Consensus Started")
 }
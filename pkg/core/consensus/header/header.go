@@ -3,6 +3,7 @@ package header
 import (
 	"bytes"
 
+	blsutil "github.com/dusk-network/dusk-blockchain/pkg/crypto/bls"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-crypto/bls"
@@ -163,5 +164,5 @@ func VerifySignatures(round uint64, step uint8, blockHash []byte, apk *bls.Apk,
 		return err
 	}
 
-	return bls.Verify(apk, signed.Bytes(), sig)
+	return blsutil.Verify(apk, signed.Bytes(), sig)
 }
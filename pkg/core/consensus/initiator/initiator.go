@@ -29,11 +29,11 @@ func LaunchConsensus(eventBroker *eventbus.EventBus, rpcBus *rpcbus.RPCBus, w *w
 func startProvisioner(eventBroker *eventbus.EventBus, rpcBus *rpcbus.RPCBus, w *wallet.Wallet, counter *chainsync.Counter) {
 	// Setting up the consensus factory
 	pubKey := w.PublicKey()
-	f := factory.New(eventBroker, rpcBus, cfg.ConsensusTimeOut, &pubKey, w.ConsensusKeys())
+	f := factory.New(eventBroker, rpcBus, cfg.GetConsensusParams().StepTimeout, &pubKey, w.ConsensusKeys())
 	f.StartConsensus()
 
 	// If we are on genesis, we should kickstart the consensus
-	lastBlkBuf, err := rpcBus.Call(rpcbus.GetLastBlock, rpcbus.Request{bytes.Buffer{}, make(chan rpcbus.Response, 1)}, 0)
+	lastBlkBuf, err := rpcBus.Call(rpcbus.GetLastBlock, rpcbus.NewRequest(bytes.Buffer{}), 0)
 	if err != nil {
 		log.Panic(err)
 	}
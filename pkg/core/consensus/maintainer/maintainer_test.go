@@ -131,7 +131,7 @@ func setupMaintainerTest(t *testing.T) (*eventbus.EventBus, chan rpcbus.Request,
 	// Note: we don't need to mock the bidlist as we should not be included if we want to trigger a bid transaction
 
 	c := make(chan rpcbus.Request, 1)
-	rpcBus.Register(rpcbus.SendMempoolTx, c)
+	rpcBus.RegisterChan(rpcbus.SendMempoolTx, c)
 
 	return bus, c, p, w.ConsensusKeys(), mScalar
 }
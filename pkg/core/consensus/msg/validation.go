@@ -1,34 +1,27 @@
 package msg
 
 import (
+	blsutil "github.com/dusk-network/dusk-blockchain/pkg/crypto/bls"
 	"github.com/dusk-network/dusk-crypto/bls"
 )
 
+// VerifyBLSSignature checks the compressed signature of a single signer.
+// It is kept for compatibility: new code should use pkg/crypto/bls.
 func VerifyBLSSignature(pubKeyBytes, message, signature []byte) error {
-	pubKeyBLS := &bls.PublicKey{}
-	if err := pubKeyBLS.Unmarshal(pubKeyBytes); err != nil {
-		return err
-	}
-
-	sig := &bls.Signature{}
-	if err := sig.Decompress(signature); err != nil {
-		return err
-	}
-
-	apk := bls.NewApk(pubKeyBLS)
-	return bls.Verify(apk, message, sig)
+	return blsutil.VerifySignature(pubKeyBytes, message, signature)
 }
 
+// VerifyBLSMultisig checks a compressed signature against an aggregated key.
 func VerifyBLSMultisig(apk, message, signature []byte) error {
 	batchPK, err := bls.UnmarshalApk(apk)
 	if err != nil {
 		return err
 	}
 
-	sig := &bls.Signature{}
-	if err := sig.Decompress(signature); err != nil {
+	sig, err := blsutil.UnmarshalSignature(signature)
+	if err != nil {
 		return err
 	}
 
-	return bls.Verify(batchPK, message, sig)
+	return blsutil.Verify(batchPK, message, sig)
 }
@@ -0,0 +1,262 @@
+// Package participation keeps track of the rounds in which the node was
+// selected in a reduction committee, and of the ones in which its votes made
+// it into the certificate of the block. The network marks the provisioners
+// missing from the certificates as absent, so the node warns as soon as its
+// participation drops, before its stake suffers from it.
+package participation
+
+import (
+	"bytes"
+	"sync"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/block"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "participation")
+
+var (
+	roundsSelected = metrics.NewCounter("dusk_consensus_rounds_selected_total", "Rounds in which the node was selected in a reduction committee")
+	roundsVoted    = metrics.NewCounter("dusk_consensus_rounds_voted_total", "Rounds in which the votes of the node made it into the certificate")
+	participation  = metrics.NewGauge("dusk_consensus_participation", "Share of the recent rounds selected in which the node voted")
+)
+
+const (
+	// window is the amount of recent rounds selected over which the
+	// participation is measured
+	window = 100
+	// minRounds is the amount of rounds selected before the participation
+	// is judged
+	minRounds = 10
+	// defaultThreshold applies when consensus.participationThreshold is 0
+	defaultThreshold = 0.9
+)
+
+// Stats is the participation of the node. Selected and Voted count the rounds
+// since the node started, Recent and RecentVoted the last rounds selected.
+type Stats struct {
+	Selected    uint64
+	Voted       uint64
+	Recent      uint64
+	RecentVoted uint64
+	// LastAbsent is the last round selected in which the node did not vote,
+	// or 0
+	LastAbsent uint64
+}
+
+// Ratio returns the share of the recent rounds selected in which the node
+// voted, or 1 if it was not selected yet
+func (s Stats) Ratio() float64 {
+	if s.Recent == 0 {
+		return 1
+	}
+	return float64(s.RecentVoted) / float64(s.Recent)
+}
+
+// Tracker follows the round updates and the accepted blocks. A round counts
+// as voted if the votes of the node are in the certificate for every
+// reduction step it was selected in.
+type Tracker struct {
+	pubKeyBLS []byte
+	threshold float64
+
+	lock sync.Mutex
+	// round and provisioners of the last round update
+	round        uint64
+	provisioners *user.Provisioners
+	// whether the node voted in the recent rounds selected, as a ring
+	recent []bool
+	next   int
+	stats  Stats
+	low    bool
+}
+
+// New creates a Tracker for the provisioner with the BLS public key
+// pubKeyBLS, following the rounds on subscriber
+func New(subscriber eventbus.Subscriber, pubKeyBLS []byte) *Tracker {
+	threshold := cfg.Get().Consensus.ParticipationThreshold
+	if threshold == 0 {
+		threshold = defaultThreshold
+	}
+
+	t := &Tracker{
+		pubKeyBLS: pubKeyBLS,
+		threshold: threshold,
+		recent:    make([]bool, 0, window),
+	}
+
+	subscriber.Subscribe(topics.RoundUpdate, eventbus.NewCallbackListener(t.onRoundUpdate))
+	subscriber.Subscribe(topics.AcceptedBlock, eventbus.NewCallbackListener(t.onBlock))
+	return t
+}
+
+// Stats returns the participation of the node
+func (t *Tracker) Stats() Stats {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.stats
+}
+
+// ProvideStats answers GetParticipation requests, with the Stats encoded by
+// MarshalStats
+func (t *Tracker) ProvideStats(rpcbus.Request) (bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+	err := MarshalStats(buf, t.Stats())
+	return *buf, err
+}
+
+func (t *Tracker) onRoundUpdate(b bytes.Buffer) error {
+	update := consensus.RoundUpdate{}
+	if err := consensus.DecodeRound(&b, &update); err != nil {
+		return err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.round = update.Round
+	t.provisioners = &update.P
+	return nil
+}
+
+// onBlock checks the certificate of the blocks of the rounds the node took
+// part in. The blocks synced while the consensus was not running are not
+// accounted for, as the node could not vote on them.
+func (t *Tracker) onBlock(b bytes.Buffer) error {
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&b, blk); err != nil {
+		return err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.provisioners == nil || blk.Header.Height != t.round {
+		return nil
+	}
+
+	selected, voted := t.check(blk.Header)
+	if selected {
+		t.record(blk.Header.Height, voted)
+	}
+	return nil
+}
+
+// check tells if the node was selected in the committees of the reduction
+// steps of the certificate, and if its votes made it in all of them. As for
+// the verification of the certificate, those are the two steps preceding the
+// one of the certificate.
+func (t *Tracker) check(header *block.Header) (selected, voted bool) {
+	cert := header.Certificate
+	if cert == nil || cert.Step < 2 {
+		return false, false
+	}
+
+	size := t.provisioners.SubsetSizeAt(header.Height)
+	if max := cfg.GetConsensusParams().ReductionCommitteeSize; size > max {
+		size = max
+	}
+
+	voted = true
+	steps := []struct {
+		step   uint8
+		bitSet uint64
+	}{
+		{cert.Step - 2, cert.StepOneCommittee},
+		{cert.Step - 1, cert.StepTwoCommittee},
+	}
+	for _, s := range steps {
+		committee := t.provisioners.CreateVotingCommittee(header.Height, s.step, size)
+		if !committee.IsMember(t.pubKeyBLS) {
+			continue
+		}
+
+		selected = true
+		voters := committee.IntersectCluster(s.bitSet)
+		if voters.OccurrencesOf(t.pubKeyBLS) == 0 {
+			voted = false
+		}
+	}
+	return selected, selected && voted
+}
+
+// record accounts for a round selected, and warns when the participation
+// drops below the threshold
+func (t *Tracker) record(round uint64, voted bool) {
+	roundsSelected.Inc()
+	t.stats.Selected++
+	if voted {
+		roundsVoted.Inc()
+		t.stats.Voted++
+	} else {
+		t.stats.LastAbsent = round
+	}
+
+	if len(t.recent) < window {
+		t.recent = append(t.recent, voted)
+	} else {
+		t.recent[t.next] = voted
+		t.next = (t.next + 1) % window
+	}
+
+	t.stats.Recent = uint64(len(t.recent))
+	t.stats.RecentVoted = 0
+	for _, v := range t.recent {
+		if v {
+			t.stats.RecentVoted++
+		}
+	}
+
+	ratio := t.stats.Ratio()
+	participation.Set(ratio)
+	l := lg.WithFields(log.Fields{
+		"round":    round,
+		"voted":    t.stats.RecentVoted,
+		"selected": t.stats.Recent,
+	})
+
+	switch {
+	case len(t.recent) < minRounds:
+		if !voted {
+			l.Warnln("votes missing from the certificate")
+		}
+	case ratio < t.threshold && !t.low:
+		t.low = true
+		l.Errorf("participation dropped to %.0f%%, the network marks this node absent: check its connectivity and clock", 100*ratio)
+	case ratio < t.threshold && !voted:
+		l.Warnf("votes missing from the certificate, participation at %.0f%%", 100*ratio)
+	case ratio >= t.threshold && t.low:
+		t.low = false
+		l.Infof("participation back to %.0f%%", 100*ratio)
+	}
+}
+
+// MarshalStats encodes the participation of the node
+func MarshalStats(b *bytes.Buffer, s Stats) error {
+	for _, n := range []uint64{s.Selected, s.Voted, s.Recent, s.RecentVoted, s.LastAbsent} {
+		if err := encoding.WriteUint64LE(b, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalStats decodes the participation of the node
+func UnmarshalStats(b *bytes.Buffer) (Stats, error) {
+	s := Stats{}
+	for _, n := range []*uint64{&s.Selected, &s.Voted, &s.Recent, &s.RecentVoted, &s.LastAbsent} {
+		if err := encoding.ReadUint64LE(b, n); err != nil {
+			return Stats{}, err
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,53 @@
+package participation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that the rounds are told apart by the votes of the node found in the
+// certificates
+func TestTracker(t *testing.T) {
+	bus := eventbus.New()
+	p, keys := consensus.MockProvisioners(1)
+	tracker := New(bus, keys[0].BLSPubKeyBytes)
+
+	accept := func(height, bitSet uint64) {
+		blk := helper.RandomBlock(t, height, 1)
+		blk.Header.Certificate = block.EmptyCertificate()
+		blk.Header.Certificate.Step = 3
+		blk.Header.Certificate.StepOneCommittee = bitSet
+		blk.Header.Certificate.StepTwoCommittee = bitSet
+		buf := new(bytes.Buffer)
+		if err := marshalling.MarshalBlock(buf, blk); err != nil {
+			t.Fatal(err)
+		}
+		bus.Publish(topics.AcceptedBlock, buf)
+	}
+
+	// The only provisioner sits in every committee
+	bus.Publish(topics.RoundUpdate, consensus.MockRoundUpdateBuffer(5, p, nil))
+	accept(5, 1)
+	bus.Publish(topics.RoundUpdate, consensus.MockRoundUpdateBuffer(6, p, nil))
+	accept(6, 0)
+	// Blocks synced ahead of the rounds are not accounted for
+	accept(7, 0)
+
+	stats := tracker.Stats()
+	assert.Equal(t, Stats{Selected: 2, Voted: 1, Recent: 2, RecentVoted: 1, LastAbsent: 6}, stats)
+	assert.Equal(t, 0.5, stats.Ratio())
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, MarshalStats(buf, stats))
+	decoded, err := UnmarshalStats(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, stats, decoded)
+}
@@ -62,3 +62,33 @@ func newReductionEvent(round uint64, step uint8) reduction.Reduction {
 
 	return reduction.Reduction{sig.Compress()}
 }
+
+func BenchmarkReductionMarshal(b *testing.B) {
+	ev := newReductionEvent(1, 1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf := new(bytes.Buffer)
+		if err := reduction.Marshal(buf, ev); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReductionUnmarshal(b *testing.B) {
+	buf := new(bytes.Buffer)
+	if err := reduction.Marshal(buf, newReductionEvent(1, 1)); err != nil {
+		b.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ev := reduction.New()
+		if err := reduction.Unmarshal(bytes.NewBuffer(encoded), ev); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
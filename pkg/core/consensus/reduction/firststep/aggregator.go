@@ -10,6 +10,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/sortedset"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -51,8 +52,9 @@ func newAggregator(
 // Collect a Reduction message, and add it's sender public key and signature to the
 // StepVotes/Set kept under the corresponding block hash. If the Set reaches or exceeds
 // quorum, the candidate block for the given block hash is first verified before
-// propagating the information to the Reducer.
-func (a *aggregator) collectVote(ev reduction.Reduction, hdr header.Header) error {
+// propagating the information to the Reducer. Reaching quorum is traced as a
+// child of span, which can be nil.
+func (a *aggregator) collectVote(ev reduction.Reduction, hdr header.Header, span *tracing.Span) error {
 	a.lock.Lock()
 	defer a.lock.Unlock()
 	if a.finished {
@@ -76,6 +78,8 @@ func (a *aggregator) collectVote(ev reduction.Reduction, hdr header.Header) erro
 	a.voteSets[hash] = sv
 	if sv.Cluster.TotalOccurrences() >= a.handler.Quorum(hdr.Round) {
 		a.finished = true
+		quorumSpan := span.StartChild("vote.quorum")
+		defer quorumSpan.Finish()
 		a.addBitSet(sv.StepVotes, sv.Cluster, hdr.Round, hdr.Step)
 		blockHash := hdr.BlockHash
 
@@ -28,7 +28,7 @@ func TestSuccessfulAggro(t *testing.T) {
 	for _, ev := range evs {
 		r := reduction.Reduction{}
 		_ = reduction.Unmarshal(&ev.Payload, &r)
-		if !assert.NoError(t, aggregator.collectVote(r, ev.Header)) {
+		if !assert.NoError(t, aggregator.collectVote(r, ev.Header, nil)) {
 			assert.FailNow(t, "error in collecting votes")
 		}
 	}
@@ -56,7 +56,7 @@ func TestInvalidBlock(t *testing.T) {
 	for _, ev := range evs {
 		r := reduction.Reduction{}
 		_ = reduction.Unmarshal(&ev.Payload, &r)
-		if !assert.NoError(t, aggregator.collectVote(r, ev.Header)) {
+		if !assert.NoError(t, aggregator.collectVote(r, ev.Header, nil)) {
 			assert.FailNow(t, "error in collecting votes")
 		}
 	}
@@ -84,7 +84,7 @@ func TestCandidateNotFound(t *testing.T) {
 	for _, ev := range evs {
 		r := reduction.Reduction{}
 		_ = reduction.Unmarshal(&ev.Payload, &r)
-		if !assert.NoError(t, aggregator.collectVote(r, ev.Header)) {
+		if !assert.NoError(t, aggregator.collectVote(r, ev.Header, nil)) {
 			assert.FailNow(t, "error in collecting votes")
 		}
 	}
@@ -1,6 +1,7 @@
 package firststep
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
@@ -14,10 +15,11 @@ import (
 
 // Factory creates a first step reduction Component
 type Factory struct {
+	// timeout is accessed atomically, and kept first for its alignment
+	timeout     int64
 	Bus         eventbus.Broker
 	RBus        *rpcbus.RPCBus
 	Keys        key.ConsensusKeys
-	timeout     time.Duration
 	Republisher *republisher.Republisher
 }
 
@@ -25,10 +27,10 @@ type Factory struct {
 func NewFactory(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeout time.Duration) *Factory {
 	r := republisher.New(broker, topics.Reduction)
 	return &Factory{
+		int64(timeout),
 		broker,
 		rpcBus,
 		keys,
-		timeout,
 		r,
 	}
 }
@@ -36,7 +38,13 @@ func NewFactory(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.Consensu
 // Instantiate a first step reduction Component
 // Implements consensus.ComponentFactory.
 func (f *Factory) Instantiate() consensus.Component {
-	return NewComponent(f.Bus, f.RBus, f.Keys, f.timeout)
+	return NewComponent(f.Bus, f.RBus, f.Keys, time.Duration(atomic.LoadInt64(&f.timeout)))
+}
+
+// SetTimeout changes the step timeout of the components instantiated from
+// then on
+func (f *Factory) SetTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&f.timeout, int64(timeout))
 }
 
 // CreateReducer is a reduction.FactoryFunc
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"time"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
@@ -90,12 +91,19 @@ func (r *Reducer) Finalize() {
 
 // Collect forwards Reduction to the aggregator
 func (r *Reducer) Collect(e consensus.Event) error {
+	span := consensus.StartVoteSpan(topics.Reduction, e)
+	defer span.Finish()
+
 	ev := reduction.New()
 	if err := reduction.Unmarshal(&e.Payload, ev); err != nil {
 		return err
 	}
 
-	if err := r.handler.VerifySignature(e.Header, ev.SignedHash); err != nil {
+	verifySpan := span.StartChild("vote.verify")
+	err := r.handler.VerifySignature(e.Header, ev.SignedHash)
+	verifySpan.Finish()
+	if err != nil {
+		span.SetAttribute("error", err.Error())
 		return err
 	}
 
@@ -106,7 +114,9 @@ func (r *Reducer) Collect(e consensus.Event) error {
 		"id":     r.reductionID,
 		"hash":   hex.EncodeToString(e.Header.BlockHash),
 	}).Debugln("received event")
-	return r.aggregator.collectVote(*ev, e.Header)
+	accumulateSpan := span.StartChild("vote.accumulate")
+	defer accumulateSpan.Finish()
+	return r.aggregator.collectVote(*ev, e.Header, accumulateSpan)
 }
 
 // Filter an incoming Reduction message, by checking whether or not it was sent
@@ -153,7 +163,7 @@ func (r *Reducer) Halt(hash []byte, svs ...*agreement.StepVotes) {
 		}
 	} else {
 		// Increase timeout if we did not have a good result
-		r.timeOut = r.timeOut * 2
+		r.timeOut = cfg.GetConsensusParams().NextTimeout(r.timeOut)
 	}
 
 	r.signer.SendInternally(topics.StepVotes, hash, buf, r.ID())
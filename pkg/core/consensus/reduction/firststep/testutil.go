@@ -68,7 +68,7 @@ func (hlp *Helper) shouldFailVerification() bool {
 
 func (hlp *Helper) provideCandidateBlock() {
 	c := make(chan rpcbus.Request, 1)
-	hlp.RBus.Register(rpcbus.GetCandidate, c)
+	hlp.RBus.RegisterChan(rpcbus.GetCandidate, c)
 	for {
 		r := <-c
 		if hlp.shouldFailFetching() {
@@ -82,7 +82,7 @@ func (hlp *Helper) provideCandidateBlock() {
 
 func (hlp *Helper) verifyCandidateBlock() {
 	v := make(chan rpcbus.Request, 1)
-	hlp.RBus.Register(rpcbus.VerifyCandidateBlock, v)
+	hlp.RBus.RegisterChan(rpcbus.VerifyCandidateBlock, v)
 	for {
 		r := <-v
 		if hlp.shouldFailVerification() {
@@ -104,7 +104,7 @@ func (hlp *Helper) createResultChan() {
 func (hlp *Helper) ActivateReduction(hash []byte) {
 	hlp.CollectionWaitGroup.Wait()
 	hdr := header.Header{BlockHash: hash, Round: hlp.Round, Step: hlp.Step(), PubKeyBLS: hlp.PubKeyBLS}
-	hlp.Reducer.(*Reducer).CollectBestScore(consensus.Event{hdr, bytes.Buffer{}})
+	hlp.Reducer.(*Reducer).CollectBestScore(consensus.Event{Header: hdr, Payload: bytes.Buffer{}})
 }
 
 // NextBatch forwards additional batches of consensus.Event. It takes care of marshalling the right Step when creating the Signature
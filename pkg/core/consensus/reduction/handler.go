@@ -4,42 +4,46 @@ import (
 	"bytes"
 	"math"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/committee"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
-	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/msg"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	blsutil "github.com/dusk-network/dusk-blockchain/pkg/crypto/bls"
 	"github.com/dusk-network/dusk-wallet/key"
 )
 
-const maxCommitteeSize = 64
-
 type (
 	// Handler is responsible for performing operations that need to know
 	// about specific event fields.
 	Handler struct {
 		*committee.Handler
+		maxCommitteeSize int
+		quorumRatio      float64
 	}
 )
 
 // newHandler will return a Handler, injected with the passed committee
 // and an unmarshaller which uses the injected validation function.
 func NewHandler(keys key.ConsensusKeys, p user.Provisioners) *Handler {
+	params := cfg.GetConsensusParams()
 	return &Handler{
-		Handler: committee.NewHandler(keys, p),
+		Handler:          committee.NewHandler(keys, p),
+		maxCommitteeSize: params.ReductionCommitteeSize,
+		quorumRatio:      params.QuorumRatio,
 	}
 }
 
 // AmMember checks if we are part of the committee.
 func (b *Handler) AmMember(round uint64, step uint8) bool {
-	return b.Handler.AmMember(round, step, maxCommitteeSize)
+	return b.Handler.AmMember(round, step, b.maxCommitteeSize)
 }
 
 func (b *Handler) IsMember(pubKeyBLS []byte, round uint64, step uint8) bool {
-	return b.Handler.IsMember(pubKeyBLS, round, step, maxCommitteeSize)
+	return b.Handler.IsMember(pubKeyBLS, round, step, b.maxCommitteeSize)
 }
 
 func (b *Handler) VotesFor(pubKeyBLS []byte, round uint64, step uint8) int {
-	return b.Handler.VotesFor(pubKeyBLS, round, step, maxCommitteeSize)
+	return b.Handler.VotesFor(pubKeyBLS, round, step, b.maxCommitteeSize)
 }
 
 // Verify the BLS signature of the Reduction event. Since the payload is nil, verifying the signature equates to verifying solely the Header
@@ -49,14 +53,14 @@ func (b *Handler) VerifySignature(hdr header.Header, sig []byte) error {
 		return err
 	}
 
-	return msg.VerifyBLSSignature(hdr.PubKeyBLS, packet.Bytes(), sig)
+	return blsutil.VerifySignature(hdr.PubKeyBLS, packet.Bytes(), sig)
 }
 
 func (b *Handler) Quorum(round uint64) int {
-	return int(math.Ceil(float64(b.CommitteeSize(round, maxCommitteeSize)) * 0.75))
+	return int(math.Ceil(float64(b.CommitteeSize(round, b.maxCommitteeSize)) * b.quorumRatio))
 }
 
 // Committee returns a VotingCommittee for a given round and step.
 func (b *Handler) Committee(round uint64, step uint8) user.VotingCommittee {
-	return b.Handler.Committee(round, step, maxCommitteeSize)
+	return b.Handler.Committee(round, step, b.maxCommitteeSize)
 }
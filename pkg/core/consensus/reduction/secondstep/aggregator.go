@@ -7,6 +7,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/sortedset"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 )
 
 // The aggregator acts as a de facto storage unit for Reduction messages. Any message
@@ -46,8 +47,9 @@ func newAggregator(
 }
 
 // Collect a Reduction message, and add it's sender public key and signature to the
-// StepVotes/Set kept under the corresponding block hash.
-func (a *aggregator) collectVote(ev reduction.Reduction, hdr header.Header) error {
+// StepVotes/Set kept under the corresponding block hash. Reaching quorum is
+// traced as a child of span, which can be nil.
+func (a *aggregator) collectVote(ev reduction.Reduction, hdr header.Header, span *tracing.Span) error {
 	a.lock.Lock()
 	defer a.lock.Unlock()
 	if a.finished {
@@ -72,8 +74,10 @@ func (a *aggregator) collectVote(ev reduction.Reduction, hdr header.Header) erro
 	a.voteSets[hash] = sv
 	if sv.Cluster.TotalOccurrences() >= a.handler.Quorum(hdr.Round) {
 		a.finished = true
+		quorumSpan := span.StartChild("vote.quorum")
 		a.addBitSet(sv.StepVotes, sv.Cluster, hdr.Round, hdr.Step)
 		a.requestHalt(hdr.BlockHash, a.firstStepVotes, sv.StepVotes)
+		quorumSpan.Finish()
 	}
 	return nil
 }
@@ -25,7 +25,7 @@ func TestSuccessfulAggro(t *testing.T) {
 		for _, ev := range evs {
 			r := reduction.Reduction{}
 			_ = reduction.Unmarshal(&ev.Payload, &r)
-			if !assert.NoError(t, aggregator.collectVote(r, ev.Header)) {
+			if !assert.NoError(t, aggregator.collectVote(r, ev.Header, nil)) {
 				assert.FailNow(t, "error in collecting votes")
 			}
 		}
@@ -1,6 +1,7 @@
 package secondstep
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
@@ -12,26 +13,33 @@ import (
 
 // Factory creates a second step reduction Component
 type Factory struct {
+	// timeout is accessed atomically, and kept first for its alignment
+	timeout int64
 	Bus     eventbus.Broker
 	RBus    *rpcbus.RPCBus
 	Keys    key.ConsensusKeys
-	timeout time.Duration
 }
 
 // NewFactory creates a Factory
 func NewFactory(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeout time.Duration) *Factory {
 	return &Factory{
+		int64(timeout),
 		broker,
 		rpcBus,
 		keys,
-		timeout,
 	}
 }
 
 // Instantiate a second step reduction Component
 // Implements consensus.ComponentFactory.
 func (f *Factory) Instantiate() consensus.Component {
-	return NewComponent(f.Bus, f.RBus, f.Keys, f.timeout)
+	return NewComponent(f.Bus, f.RBus, f.Keys, time.Duration(atomic.LoadInt64(&f.timeout)))
+}
+
+// SetTimeout changes the step timeout of the components instantiated from
+// then on
+func (f *Factory) SetTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&f.timeout, int64(timeout))
 }
 
 // CreateReducer is callback used by reduction.Helper to wire up the tests
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"time"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
@@ -93,12 +94,19 @@ func (r *Reducer) Finalize() {
 }
 
 func (r *Reducer) Collect(e consensus.Event) error {
+	span := consensus.StartVoteSpan(topics.Reduction, e)
+	defer span.Finish()
+
 	ev := reduction.New()
 	if err := reduction.Unmarshal(&e.Payload, ev); err != nil {
 		return err
 	}
 
-	if err := r.handler.VerifySignature(e.Header, ev.SignedHash); err != nil {
+	verifySpan := span.StartChild("vote.verify")
+	err := r.handler.VerifySignature(e.Header, ev.SignedHash)
+	verifySpan.Finish()
+	if err != nil {
+		span.SetAttribute("error", err.Error())
 		return err
 	}
 
@@ -109,7 +117,9 @@ func (r *Reducer) Collect(e consensus.Event) error {
 		"id":     r.reductionID,
 		"hash":   hex.EncodeToString(e.Header.BlockHash),
 	}).Debugln("received event")
-	return r.aggregator.collectVote(*ev, e.Header)
+	accumulateSpan := span.StartChild("vote.accumulate")
+	defer accumulateSpan.Finish()
+	return r.aggregator.collectVote(*ev, e.Header, accumulateSpan)
 }
 
 // Filter an incoming Reduction message, by checking whether or not it was sent
@@ -152,7 +162,7 @@ func (r *Reducer) Halt(hash []byte, b ...*agreement.StepVotes) {
 		r.sendAgreement(step, hash, b)
 	} else {
 		// Increase timeout if we had no agreement
-		r.timeOut = r.timeOut * 2
+		r.timeOut = cfg.GetConsensusParams().NextTimeout(r.timeOut)
 	}
 
 	r.signer.SendInternally(topics.Restart, emptyHash[:], regenerationPackage, r.ID())
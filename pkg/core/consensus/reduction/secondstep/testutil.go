@@ -48,7 +48,7 @@ func (hlp *Helper) ActivateReduction(hash []byte, sv *agreement.StepVotes) error
 			return err
 		}
 	}
-	e := consensus.Event{header.Header{Round: hlp.Round, Step: hlp.Step(), PubKeyBLS: hlp.PubKeyBLS, BlockHash: hash}, *buf}
+	e := consensus.Event{Header: header.Header{Round: hlp.Round, Step: hlp.Step(), PubKeyBLS: hlp.PubKeyBLS, BlockHash: hash}, Payload: *buf}
 	hlp.Reducer.(*Reducer).CollectStepVotes(e)
 	return nil
 }
@@ -5,11 +5,14 @@ import (
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
 	log "github.com/sirupsen/logrus"
 )
 
 var emptyHash [32]byte
 
+var stepTimeouts = metrics.NewCounter("dusk_consensus_step_timeouts_total", "Reduction steps which timed out")
+
 type Timer struct {
 	requestHalt func([]byte, ...*agreement.StepVotes)
 	lock        sync.RWMutex
@@ -38,5 +41,6 @@ func (t *Timer) Stop() {
 
 func (t *Timer) Trigger() {
 	log.WithField("process", "reduction timer").Debugln("timer triggered")
+	stepTimeouts.Inc()
 	t.requestHalt(emptyHash[:])
 }
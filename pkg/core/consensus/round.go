@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 	"github.com/dusk-network/dusk-crypto/bls"
 	"github.com/dusk-network/dusk-wallet/key"
 	log "github.com/sirupsen/logrus"
@@ -22,6 +27,8 @@ var emptyPayload = new(bytes.Buffer)
 
 var lg = log.WithField("process", "coordinator")
 
+var consensusRound = metrics.NewGauge("dusk_consensus_round", "Round the consensus is running")
+
 // roundStore is the central registry for all consensus components and listeners.
 // It is used for message dispatching and controlling the stream of events.
 type roundStore struct {
@@ -67,7 +74,6 @@ func (s *roundStore) initializeComponents(round RoundUpdate) []TopicListener {
 	s.lock.Lock()
 	sort.Slice(s.components, func(i, j int) bool { return s.components[i].ID() < s.components[j].ID() })
 	s.lock.Unlock()
-
 	return allSubs
 }
 
@@ -83,6 +89,12 @@ func (s *roundStore) subscribe(topic topics.Topic, sub Listener) {
 	s.subscribers[topic] = subscribers
 }
 
+func (s *roundStore) handles(topic topics.Topic) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.subscribers[topic]) > 0
+}
+
 func (s *roundStore) pause(id uint32) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -170,11 +182,21 @@ type Coordinator struct {
 
 	pubkeyBuf bytes.Buffer
 
-	lock     sync.RWMutex
-	store    *roundStore
-	unsynced bool
+	lock  sync.RWMutex
+	store *roundStore
+	// routed holds the current store as well, for the handles predicate,
+	// which can not take the lock as it runs on the publishing goroutines
+	routed atomic.Value
 
 	stopped bool
+	// paused is set by PauseConsensus, and keeps the consensus stopped
+	// across round updates until ResumeConsensus
+	paused bool
+
+	// event bus subscriptions, removed once the node quits
+	defaultID     uint32
+	subscriptions map[topics.Topic]uint32
+	quit          bool
 }
 
 // Start the coordinator by wiring the listener to the RoundUpdate
@@ -193,24 +215,88 @@ func Start(eventBus *eventbus.EventBus, keys key.ConsensusKeys, factories ...Com
 		eventqueue: NewQueue(),
 		roundQueue: NewQueue(),
 		pubkeyBuf:  *pkBuf,
-		unsynced:   true,
 		stopped:    true,
 	}
 
 	// completing the initialization
-	listener := eventbus.NewCallbackListener(c.CollectEvent)
-	c.eventBus.SubscribeDefault(listener)
+	listener := eventbus.NewTracedCallbackListener(c.CollectTracedEvent)
+	c.defaultID = c.eventBus.SubscribeDefault(listener)
+	c.eventBus.AddDefaultPredicate(c.handles)
 
+	c.subscriptions = make(map[topics.Topic]uint32)
 	l := eventbus.NewCallbackListener(c.CollectRoundUpdate)
-	c.eventBus.Subscribe(topics.RoundUpdate, l)
+	c.subscriptions[topics.RoundUpdate] = c.eventBus.Subscribe(topics.RoundUpdate, l)
 
 	stopListener := eventbus.NewCallbackListener(c.StopConsensus)
-	c.eventBus.Subscribe(topics.StopConsensus, stopListener)
+	c.subscriptions[topics.StopConsensus] = c.eventBus.Subscribe(topics.StopConsensus, stopListener)
+
+	pauseListener := eventbus.NewCallbackListener(c.PauseConsensus)
+	c.subscriptions[topics.PauseConsensus] = c.eventBus.Subscribe(topics.PauseConsensus, pauseListener)
+
+	resumeListener := eventbus.NewCallbackListener(c.ResumeConsensus)
+	c.subscriptions[topics.ResumeConsensus] = c.eventBus.Subscribe(topics.ResumeConsensus, resumeListener)
+
+	quitListener := eventbus.NewCallbackListener(c.Quit)
+	c.subscriptions[topics.Quit] = c.eventBus.Subscribe(topics.Quit, quitListener)
 
 	c.reinstantiateStore()
+	c.replayRoundUpdate()
 	return c
 }
 
+// replayRoundUpdate catches up with the latest round update journaled by the
+// EventBus, in case the coordinator was started after it was published.
+func (c *Coordinator) replayRoundUpdate() {
+	updates := c.eventBus.Replay(topics.RoundUpdate, time.Time{})
+	if len(updates) == 0 {
+		return
+	}
+
+	m := updates[len(updates)-1]
+	r := RoundUpdate{}
+	if err := DecodeRound(bytes.NewBuffer(m.Bytes()), &r); err != nil {
+		lg.WithError(err).Warnln("could not replay the round update")
+		return
+	}
+
+	// The update might have been received already, after subscribing
+	if r.Round <= c.Round() {
+		return
+	}
+
+	if err := c.CollectRoundUpdate(m); err != nil {
+		lg.WithError(err).Warnln("could not replay the round update")
+	}
+}
+
+// Quit is triggered when the node shuts down. The consensus is stopped, and
+// the coordinator unsubscribes from the EventBus, for good.
+func (c *Coordinator) Quit(bytes.Buffer) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.quit {
+		return nil
+	}
+
+	if !c.stopped {
+		c.stopConsensus()
+		c.stopped = true
+	}
+
+	c.eventBus.UnsubscribeDefault(c.defaultID)
+	for topic, id := range c.subscriptions {
+		c.eventBus.Unsubscribe(topic, id)
+	}
+	c.quit = true
+	return nil
+}
+
+// ProvideState answers GetConsensusState requests with the round and the
+// step the coordinator is on, encoded as by SyncState.ToBuffer.
+func (c *Coordinator) ProvideState(rpcbus.Request) (bytes.Buffer, error) {
+	return c.ToBuffer(), nil
+}
+
 func (c *Coordinator) StopConsensus(bytes.Buffer) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -222,20 +308,53 @@ func (c *Coordinator) StopConsensus(bytes.Buffer) error {
 	return nil
 }
 
+// PauseConsensus stops the consensus until ResumeConsensus. Unlike
+// StopConsensus, the round updates received meanwhile do not restart it.
+func (c *Coordinator) PauseConsensus(bytes.Buffer) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.paused = true
+	if !c.stopped {
+		c.stopConsensus()
+		c.stopped = true
+	}
+
+	return nil
+}
+
+// ResumeConsensus lets the next round update restart the consensus, after
+// PauseConsensus
+func (c *Coordinator) ResumeConsensus(bytes.Buffer) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.paused = false
+	return nil
+}
+
 func (c *Coordinator) stopConsensus() {
 	c.FinalizeRound()
 	c.reinstantiateStore()
 }
 
-func (c *Coordinator) onNewRound(roundUpdate RoundUpdate, fromScratch bool) {
-	subs := c.store.initializeComponents(roundUpdate)
-	if fromScratch && subs != nil {
-		for _, sub := range subs {
+// routeTopics routes the consensus topics the components subscribe to to the
+// coordinator for good, so that the messages of future rounds keep being
+// queued while the consensus is stopped. The other topics, internal to a
+// round, are only routed through the handles predicate.
+func (c *Coordinator) routeTopics(subs []TopicListener) {
+	for _, sub := range subs {
+		if topics.IsConsensus(sub.Topic) {
 			c.eventBus.AddDefaultTopic(sub.Topic)
 		}
 	}
 }
 
+// handles is the predicate routing to the coordinator the extra topics which
+// the components of the current round are subscribed to
+func (c *Coordinator) handles(topic topics.Topic) bool {
+	store, ok := c.routed.Load().(*roundStore)
+	return ok && store.handles(topic)
+}
+
 // CollectRoundUpdate is triggered when the Chain propagates a new round update.
 // The consensus components are swapped out, initialized, and the
 // state will be updated to the new round.
@@ -243,17 +362,26 @@ func (c *Coordinator) CollectRoundUpdate(m bytes.Buffer) error {
 	lg.Debugln("received round update")
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	if c.quit {
+		return nil
+	}
+
 	r := RoundUpdate{}
 	if err := DecodeRound(&m, &r); err != nil {
 		return err
 	}
 
+	if c.paused {
+		lg.WithField("round", r.Round).Debugln("consensus paused, round update ignored")
+		return nil
+	}
+
 	if !c.stopped {
 		c.stopConsensus()
 	}
-	c.onNewRound(r, c.unsynced)
+	c.routeTopics(c.store.initializeComponents(r))
 	c.Update(r.Round)
-	c.unsynced = false
+	consensusRound.Set(float64(r.Round))
 	c.stopped = false
 	go c.flushRoundQueue()
 
@@ -304,7 +432,14 @@ func (c *Coordinator) reinstantiateStore() {
 	c.swapStore(store)
 }
 
+// CollectEvent dispatches a consensus event, which is not traced
 func (c *Coordinator) CollectEvent(m bytes.Buffer) error {
+	return c.CollectTracedEvent(m, tracing.None)
+}
+
+// CollectTracedEvent dispatches a consensus event, received with the
+// correlation ID given, to the components listening for its topic
+func (c *Coordinator) CollectTracedEvent(m bytes.Buffer, id tracing.ID) error {
 	// NOTE: RUnlock is not deferred here, for performance reasons.
 	// https://medium.com/i0exception/runtime-overhead-of-using-defer-in-go-7140d5c40e32
 	// TODO: once go 1.14 is out, re-examine the overhead of using `defer`.
@@ -328,6 +463,9 @@ func (c *Coordinator) CollectEvent(m bytes.Buffer) error {
 		"step":  hdr.Step,
 	}).Traceln("collected event")
 
+	ev := NewTopicEvent(topic, hdr, m)
+	ev.ID = id
+
 	var comparison header.Phase
 	if topic == topics.Agreement {
 		comparison = hdr.CompareRound(c.Round())
@@ -348,19 +486,19 @@ func (c *Coordinator) CollectEvent(m bytes.Buffer) error {
 		// as soon as the Coordinator reaches the round in the event
 		// header.
 		if topic == topics.Agreement {
-			c.roundQueue.PutEvent(hdr.Round, hdr.Step, NewTopicEvent(topic, hdr, m))
+			c.roundQueue.PutEvent(hdr.Round, hdr.Step, ev)
 			c.lock.RUnlock()
 			return nil
 		}
 
 		// Otherwise, we just queue it according to the header round
 		// and step.
-		c.eventqueue.PutEvent(hdr.Round, hdr.Step, NewTopicEvent(topic, hdr, m))
+		c.eventqueue.PutEvent(hdr.Round, hdr.Step, ev)
 		c.lock.RUnlock()
 		return nil
 	}
 
-	c.store.Dispatch(NewTopicEvent(topic, hdr, m))
+	c.store.Dispatch(ev)
 	c.lock.RUnlock()
 	return nil
 }
@@ -368,6 +506,7 @@ func (c *Coordinator) CollectEvent(m bytes.Buffer) error {
 // swapping stores is the only place that needs a lock as store is shared among the CollectRoundUpdate and CollectEvent
 func (c *Coordinator) swapStore(store *roundStore) {
 	c.store = store
+	c.routed.Store(store)
 }
 
 func (c *Coordinator) FinalizeRound() {
@@ -3,6 +3,7 @@ package consensus
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -147,6 +148,77 @@ func TestQueueDispatchAgreement(t *testing.T) {
 	<-agComp.receivedEvents
 }
 
+// Test that the coordinator stops for good once the node quits
+func TestQuit(t *testing.T) {
+	c, _ := initCoordinatorTest(t, topics.Reduction)
+	c.eventBus.Publish(topics.Quit, new(bytes.Buffer))
+	assert.True(t, c.stopped)
+
+	// Round updates are no longer followed
+	c.eventBus.Publish(topics.RoundUpdate, MockRoundUpdateBuffer(2, nil, nil))
+	c.CollectRoundUpdate(*MockRoundUpdateBuffer(3, nil, nil))
+	assert.Equal(t, uint64(1), c.Round())
+	assert.True(t, c.stopped)
+}
+
+// Test that the consensus stays paused across round updates, until resumed
+func TestPauseConsensus(t *testing.T) {
+	c, _ := initCoordinatorTest(t, topics.Reduction)
+	c.eventBus.Publish(topics.PauseConsensus, new(bytes.Buffer))
+	assert.True(t, c.stopped)
+
+	c.CollectRoundUpdate(*MockRoundUpdateBuffer(2, nil, nil))
+	assert.Equal(t, uint64(1), c.Round())
+	assert.True(t, c.stopped)
+
+	c.eventBus.Publish(topics.ResumeConsensus, new(bytes.Buffer))
+	c.CollectRoundUpdate(*MockRoundUpdateBuffer(3, nil, nil))
+	assert.Equal(t, uint64(3), c.Round())
+	assert.False(t, c.stopped)
+}
+
+// Test that the Agreement messages of future rounds are still queued once the
+// consensus is stopped
+func TestQueueAgreementAfterStop(t *testing.T) {
+	c, _ := initCoordinatorTest(t, topics.Agreement)
+	c.eventBus.Publish(topics.StopConsensus, new(bytes.Buffer))
+	assert.True(t, c.stopped)
+
+	ev := mockEventBuffer(t, topics.Agreement, 2, 1)
+	// The bus prepends the topic itself
+	_, _ = topics.Extract(ev)
+	c.eventBus.Publish(topics.Agreement, ev)
+
+	// The default listener is notified asynchronously
+	queued := func() int {
+		c.roundQueue.lock.RLock()
+		defer c.roundQueue.lock.RUnlock()
+		return len(c.roundQueue.entries[2][1])
+	}
+	for i := 0; i < 100 && queued() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, queued())
+}
+
+// Test that a coordinator started late catches up with the journaled round
+// update
+func TestReplayRoundUpdate(t *testing.T) {
+	bus := eventbus.New()
+	bus.EnableJournal(topics.RoundUpdate, 1)
+	bus.Publish(topics.RoundUpdate, MockRoundUpdateBuffer(2, nil, nil))
+	bus.Publish(topics.RoundUpdate, MockRoundUpdateBuffer(3, nil, nil))
+
+	keys, err := key.NewRandConsensusKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := Start(bus, keys, &mockFactory{topics.Reduction})
+	assert.Equal(t, uint64(3), c.Round())
+	assert.False(t, c.stopped)
+}
+
 // Initialize a coordinator with a single component.
 func initCoordinatorTest(t *testing.T, tpcs ...topics.Topic) (*Coordinator, []Component) {
 	bus := eventbus.New()
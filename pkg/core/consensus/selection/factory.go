@@ -1,6 +1,7 @@
 package selection
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
@@ -9,20 +10,27 @@ import (
 
 // Factory creates the selection component.
 type Factory struct {
+	// timeout is accessed atomically, and kept first for its alignment
+	timeout int64
 	Bus     eventbus.Broker
-	timeout time.Duration
 }
 
 // NewFactory instantiates a Factory.
 func NewFactory(bus eventbus.Broker, timeout time.Duration) *Factory {
 	return &Factory{
+		int64(timeout),
 		bus,
-		timeout,
 	}
 }
 
 // Instantiate a Selector and return it.
 // Implements consensus.ComponentFactory.
 func (f *Factory) Instantiate() consensus.Component {
-	return NewComponent(f.Bus, f.timeout)
+	return NewComponent(f.Bus, time.Duration(atomic.LoadInt64(&f.timeout)))
+}
+
+// SetTimeout changes the step timeout of the components instantiated from
+// then on
+func (f *Factory) SetTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&f.timeout, int64(timeout))
 }
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -103,8 +104,15 @@ func (s *Selector) CollectScoreEvent(e consensus.Event) error {
 	}
 
 	// Tell the candidate broker to allow a candidate block with this
-	// hash through.
-	s.publisher.Publish(topics.ValidCandidateHash, bytes.NewBuffer(ev.VoteHash))
+	// hash through. The Z identifies the generator, whose candidates
+	// are capped. The seed and the score, bound by the proof, must be
+	// the ones of the candidate.
+	validHash := new(bytes.Buffer)
+	validHash.Write(ev.VoteHash)
+	validHash.Write(ev.Z)
+	validHash.Write(ev.Seed)
+	validHash.Write(ev.Score)
+	s.publisher.Publish(topics.ValidCandidateHash, validHash)
 
 	if err := s.repropagate(e.Header, ev); err != nil {
 		return err
@@ -135,7 +143,7 @@ func (s *Selector) startSelection() {
 
 // IncreaseTimeOut increases the timeout after a failed selection
 func (s *Selector) IncreaseTimeOut() {
-	s.timeout = s.timeout * 2
+	s.timeout = cfg.GetConsensusParams().NextTimeout(s.timeout)
 }
 
 func (s *Selector) publishBestEvent() error {
@@ -128,7 +128,7 @@ func (h *Helper) Spawn(hash []byte) []consensus.Event {
 			PubKeyBLS: keys.BLSPubKeyBytes,
 			BlockHash: hash,
 		}
-		evs = append(evs, consensus.Event{hdr, *ev})
+		evs = append(evs, consensus.Event{Header: hdr, Payload: *ev})
 	}
 	return evs
 }
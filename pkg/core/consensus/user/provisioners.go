@@ -2,6 +2,7 @@ package user
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
@@ -23,13 +24,31 @@ type (
 		Members map[string]*Member
 	}
 
+	// Stake is an amount of DUSK staked by a provisioner. It counts in the
+	// committees from StartHeight, once the stake has matured, up to and
+	// including EndHeight.
 	Stake struct {
 		Amount      uint64
 		StartHeight uint64
 		EndHeight   uint64
 	}
+
+	// StakeExpiry is published on the StakeExpired topic when a stake is
+	// removed from the provisioners at its expiry.
+	StakeExpiry struct {
+		PubKeyBLS []byte
+		Stake     Stake
+		// Removed is true if the provisioner had no other stake, and was
+		// removed from the provisioners
+		Removed bool
+	}
 )
 
+// ActiveAt returns true if the stake counts in the committees of round
+func (s Stake) ActiveAt(round uint64) bool {
+	return s.StartHeight <= round && round <= s.EndHeight
+}
+
 func (m *Member) AddStake(stake Stake) {
 	m.Stakes = append(m.Stakes, stake)
 }
@@ -74,7 +93,7 @@ func (p Provisioners) SubsetSizeAt(round uint64) int {
 	var size int
 	for _, member := range p.Members {
 		for _, stake := range member.Stakes {
-			if stake.StartHeight <= round && round <= stake.EndHeight {
+			if stake.ActiveAt(round) {
 				size++
 				break
 			}
@@ -184,6 +203,12 @@ func UnmarshalProvisioners(r *bytes.Buffer) (Provisioners, error) {
 		return Provisioners{}, err
 	}
 
+	// Every member takes at least 34 bytes: its Ed25519 key, and the
+	// lengths of its BLS key and of its stakes
+	if lMembers > uint64(r.Len()/34) {
+		return Provisioners{}, errors.New("invalid amount of provisioners")
+	}
+
 	members := make([]*Member, lMembers)
 	for i := uint64(0); i < lMembers; i++ {
 		members[i], err = unmarshalMember(r)
@@ -222,6 +247,11 @@ func unmarshalMember(r *bytes.Buffer) (*Member, error) {
 		return nil, err
 	}
 
+	// Every stake takes 24 bytes
+	if lStakes > uint64(r.Len()/24) {
+		return nil, errors.New("invalid amount of stakes")
+	}
+
 	member.Stakes = make([]Stake, lStakes)
 	for i := uint64(0); i < lStakes; i++ {
 		member.Stakes[i], err = unmarshalStake(r)
@@ -249,3 +279,37 @@ func unmarshalStake(r *bytes.Buffer) (Stake, error) {
 
 	return stake, nil
 }
+
+// MarshalStakeExpiry encodes a StakeExpiry, as published on the StakeExpired
+// topic
+func MarshalStakeExpiry(r *bytes.Buffer, e StakeExpiry) error {
+	if err := encoding.WriteVarBytes(r, e.PubKeyBLS); err != nil {
+		return err
+	}
+
+	if err := marshalStake(r, e.Stake); err != nil {
+		return err
+	}
+
+	return encoding.WriteBool(r, e.Removed)
+}
+
+// UnmarshalStakeExpiry decodes a StakeExpiry published on the StakeExpired
+// topic
+func UnmarshalStakeExpiry(r *bytes.Buffer) (StakeExpiry, error) {
+	e := StakeExpiry{}
+	if err := encoding.ReadVarBytes(r, &e.PubKeyBLS); err != nil {
+		return StakeExpiry{}, err
+	}
+
+	var err error
+	if e.Stake, err = unmarshalStake(r); err != nil {
+		return StakeExpiry{}, err
+	}
+
+	if err := encoding.ReadBool(r, &e.Removed); err != nil {
+		return StakeExpiry{}, err
+	}
+
+	return e, nil
+}
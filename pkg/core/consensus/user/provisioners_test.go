@@ -6,8 +6,10 @@ import (
 	"sort"
 	"testing"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-wallet/key"
 	"github.com/stretchr/testify/assert"
 )
@@ -72,3 +74,62 @@ func TestGetMember(t *testing.T) {
 		assert.Equal(t, m.PublicKeyBLS, tk)
 	}
 }
+
+// Stakes which have not matured yet, or have expired, do not count in the
+// committees, from the activation height of the network
+func TestStakeMaturity(t *testing.T) {
+	prev := cfg.Get()
+	defer cfg.Mock(&prev)
+	r := prev
+	r.General.Network = "localnet"
+	cfg.Mock(&r)
+
+	p, k := consensus.MockProvisioners(10)
+	mature := make(map[string]bool)
+	for i, keys := range k {
+		if i%2 == 1 {
+			mature[string(keys.BLSPubKeyBytes)] = true
+			continue
+		}
+
+		// Several immature or expired stakes, to check they all get left out
+		m := p.GetMember(keys.BLSPubKeyBytes)
+		m.Stakes[0].StartHeight = 200
+		m.AddStake(user.Stake{Amount: 500, StartHeight: 300, EndHeight: 10000})
+		m.AddStake(user.Stake{Amount: 500, StartHeight: 0, EndHeight: 50})
+	}
+
+	assert.Equal(t, 5, p.SubsetSizeAt(100))
+	committee := p.CreateVotingCommittee(100, 1, 50)
+	assert.Equal(t, 5, committee.Size())
+	for _, pk := range committee.MemberKeys() {
+		assert.True(t, mature[string(pk)])
+	}
+
+	// The stakes of the provisioners are left untouched by the sortition
+	assert.Equal(t, 3, len(p.GetMember(k[0].BLSPubKeyBytes).Stakes))
+	assert.Equal(t, 10, p.SubsetSizeAt(300))
+}
+
+func TestStakeExpiryEncoding(t *testing.T) {
+	keys, _ := key.NewRandConsensusKeys()
+	e := user.StakeExpiry{
+		PubKeyBLS: keys.BLSPubKeyBytes,
+		Stake:     user.Stake{Amount: 500, StartHeight: 2, EndHeight: 1000},
+		Removed:   true,
+	}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, user.MarshalStakeExpiry(buf, e))
+	decoded, err := user.UnmarshalStakeExpiry(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, e, decoded)
+}
+
+// Test that the amount of provisioners decoded is bounded by the input.
+func TestUnmarshalProvisionersLength(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, encoding.WriteVarInt(buf, 1<<40))
+	_, err := user.UnmarshalProvisioners(buf)
+	assert.Error(t, err)
+}
@@ -5,8 +5,9 @@ import (
 	"math"
 	"math/big"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/crypto/hash"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/sortedset"
-	"github.com/dusk-network/dusk-crypto/hash"
 	"github.com/dusk-network/dusk-wallet/wallet"
 	log "github.com/sirupsen/logrus"
 )
@@ -50,19 +51,21 @@ func (v VotingCommittee) IsMember(pubKeyBLS []byte) bool {
 	return found
 }
 
-// createSortitionMessage will return the hash of the passed sortition information.
-func createSortitionHash(round uint64, step uint8, i int) ([]byte, error) {
-	msg := make([]byte, 12)
+// createSortitionHash appends the hash of the passed sortition information
+// to dst.
+func createSortitionHash(dst []byte, round uint64, step uint8, i int) []byte {
+	var msg [13]byte
 	binary.LittleEndian.PutUint64(msg[:8], round)
 	binary.LittleEndian.PutUint32(msg[8:12], uint32(i))
-	msg = append(msg, byte(step))
-	return hash.Sha3256(msg)
+	msg[12] = step
+	return hash.HashInto(dst, msg[:])
 }
 
-// generate a score from the given hash and total stake weight
-func generateSortitionScore(hash []byte, W *big.Int) uint64 {
-	hashNum := new(big.Int).SetBytes(hash)
-	return new(big.Int).Mod(hashNum, W).Uint64()
+// generate a score from the given hash and total stake weight. n is used as
+// scratch space, to avoid allocating a big.Int on every extraction.
+func generateSortitionScore(n *big.Int, hash []byte, W *big.Int) uint64 {
+	n.SetBytes(hash)
+	return n.Mod(n, W).Uint64()
 }
 
 // CreateVotingCommittee will run the deterministic sortition function, which determines
@@ -75,29 +78,28 @@ func (p Provisioners) CreateVotingCommittee(round uint64, step uint8, size int)
 	members := copyMembers(p.Members)
 	p.Members = members
 
-	// Remove stakes which have not yet become active, or have expired
-	for _, m := range p.Members {
-		for i, stake := range m.Stakes {
-			if stake.StartHeight > round || stake.EndHeight < round {
-				subtractFromTotalWeight(W, stake.Amount)
-				m.RemoveStake(i)
-			}
-		}
+	// Remove stakes which have not yet matured, or have expired. Below the
+	// activation height of the network, the committees are extracted as by
+	// the nodes which skipped some of these stakes, so that the chain stays
+	// valid.
+	activeStakes := round >= cfg.GetConsensusParams().ActiveStakesHeight
+	if activeStakes {
+		p.removeInactiveStakes(W, round)
+	} else {
+		p.removeInactiveStakesLegacy(W, round)
 	}
 
+	var digest [hash.Size]byte
+	scratch := new(big.Int)
 	for i := 0; votingCommittee.Size() < size; i++ {
 		if W.Uint64() == 0 {
 			// We ran out of staked DUSK, so we return the result prematurely
 			break
 		}
 
-		hash, err := createSortitionHash(round, step, i)
-		if err != nil {
-			log.Panic(err)
-		}
-
-		score := generateSortitionScore(hash, W)
-		blsPk := p.extractCommitteeMember(score)
+		h := createSortitionHash(digest[:0], round, step, i)
+		score := generateSortitionScore(scratch, h, W)
+		blsPk := p.extractCommitteeMember(score, activeStakes)
 		votingCommittee.Insert(blsPk)
 
 		// Subtract up to one DUSK from the extracted committee member.
@@ -112,10 +114,42 @@ func (p Provisioners) CreateVotingCommittee(round uint64, step uint8, size int)
 	return *votingCommittee
 }
 
+// removeInactiveStakes filters the copied stakes in place, and subtracts the
+// inactive ones from W
+func (p Provisioners) removeInactiveStakes(W *big.Int, round uint64) {
+	for _, m := range p.Members {
+		active := m.Stakes[:0]
+		for _, stake := range m.Stakes {
+			if stake.ActiveAt(round) {
+				active = append(active, stake)
+				continue
+			}
+
+			subtractFromTotalWeight(W, stake.Amount)
+		}
+		m.Stakes = active
+	}
+}
+
+// removeInactiveStakesLegacy removes the inactive stakes as before the
+// activation height. As the stakes are removed while iterated, the stake
+// moved in place of a removed one is not checked.
+func (p Provisioners) removeInactiveStakesLegacy(W *big.Int, round uint64) {
+	for _, m := range p.Members {
+		for i, stake := range m.Stakes {
+			if stake.StartHeight > round || stake.EndHeight < round {
+				subtractFromTotalWeight(W, stake.Amount)
+				m.RemoveStake(i)
+			}
+		}
+	}
+}
+
 // extractCommitteeMember walks through the committee set, while deducting
 // each node's stake from the passed score until we reach zero. The public key
 // of the node that the function ends on will be returned as a hexadecimal string.
-func (p Provisioners) extractCommitteeMember(score uint64) []byte {
+// Members without stake left are skipped if activeStakes is set.
+func (p Provisioners) extractCommitteeMember(score uint64, activeStakes bool) []byte {
 	for i := 0; ; i++ {
 		// make sure we wrap around the provisioners array
 		if i >= len(p.Members) {
@@ -131,7 +165,8 @@ func (p Provisioners) extractCommitteeMember(score uint64) []byte {
 			log.Panic(err)
 		}
 
-		if stake >= score {
+		// Members without active stakes left can not be extracted
+		if (stake > 0 || !activeStakes) && stake >= score {
 			return m.PublicKeyBLS
 		}
 
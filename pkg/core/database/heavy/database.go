@@ -8,8 +8,12 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/opt"
 )
 
+// backupBatchSize is the amount of entries written at once by Backup
+const backupBatchSize = 1000
+
 var (
 	// See openStorage for detailed explanation
 	_storage   *leveldb.DB
@@ -187,3 +191,39 @@ func (db DB) Close() error {
 func (db DB) GetSnapshot() (*leveldb.Snapshot, error) {
 	return db.storage.GetSnapshot()
 }
+
+// Backup writes a consistent copy of the storage into a new database at path.
+// The copy is taken from a snapshot, so writes are not suspended meanwhile.
+func (db DB) Backup(path string) error {
+	snapshot, err := db.storage.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.Release()
+
+	backup, err := leveldb.OpenFile(path, &opt.Options{ErrorIfExist: true})
+	if err != nil {
+		return err
+	}
+	defer backup.Close()
+
+	iter := snapshot.NewIterator(nil, nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		batch.Put(iter.Key(), iter.Value())
+		if batch.Len() >= backupBatchSize {
+			if err := backup.Write(batch, nil); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	return backup.Write(batch, nil)
+}
@@ -40,14 +40,17 @@ var (
 	// Key values prefixes to provide prefix-based sorting mechanism
 	// Refer to README.md for overview idea
 
-	HeaderPrefix    = []byte{0x01}
-	TxPrefix        = []byte{0x02}
-	HeightPrefix    = []byte{0x03}
-	TxIDPrefix      = []byte{0x04}
-	KeyImagePrefix  = []byte{0x05}
-	StatePrefix     = []byte{0x06}
-	OutputKeyPrefix = []byte{0x07}
-	BidValuesPrefix = []byte{0x08}
+	HeaderPrefix       = []byte{0x01}
+	TxPrefix           = []byte{0x02}
+	HeightPrefix       = []byte{0x03}
+	TxIDPrefix         = []byte{0x04}
+	KeyImagePrefix     = []byte{0x05}
+	StatePrefix        = []byte{0x06}
+	OutputKeyPrefix    = []byte{0x07}
+	BidValuesPrefix    = []byte{0x08}
+	LockHeightPrefix   = []byte{0x09}
+	BidPrefix          = []byte{0x0A}
+	RegistrationPrefix = []byte{0x0B}
 )
 
 type transaction struct {
@@ -106,7 +109,7 @@ func (t transaction) StoreBlock(b *block.Block) error {
 	// into the store
 	for i, tx := range b.Txs {
 
-		txID, err := tx.CalculateHash()
+		txID, err := marshalling.TxID(tx)
 
 		if err != nil {
 			return err
@@ -159,15 +162,33 @@ func (t transaction) StoreBlock(b *block.Block) error {
 		//
 		// To make FetchOutputKey functioning
 		for i, output := range tx.StandardTx().Outputs {
+			unlockHeight, locked := utils.OutputLock(tx, i, b.Header.Height)
 			value := make([]byte, 8)
-			// Only lock the first output, so that change outputs are
-			// not affected.
-			if i == 0 {
-				binary.LittleEndian.PutUint64(value, tx.LockTime()+b.Header.Height)
+			binary.LittleEndian.PutUint64(value, unlockHeight)
+
+			// Schema
+			//
+			// Key = LockHeightPrefix + unlockheight + tx.output.PublicKey
+			// Value = nil
+			//
+			// To make FetchOutputsUnlockingAt functioning
+			if locked {
+				t.put(lockHeightKey(unlockHeight, output.PubKey.P.Bytes()), nil)
 			}
 			t.put(append(OutputKeyPrefix, output.PubKey.P.Bytes()...), value)
 		}
 
+		// Schema
+		//
+		// Key = RegistrationPrefix + stake.PubKey or bid.M + txID
+		// Value = block.header.height
+		//
+		// To make FetchRegistrations functioning
+		for _, regKey := range utils.RegistrationKeys(tx) {
+			value := make([]byte, 8)
+			byteOrder.PutUint64(value, b.Header.Height)
+			t.put(registrationKey(regKey, txID), value)
+		}
 	}
 
 	// Key = HeightPrefix + block.header.height
@@ -197,6 +218,127 @@ func (t transaction) StoreBlock(b *block.Block) error {
 	return nil
 }
 
+// DeleteBlock removes all the data stored by StoreBlock for the chain tip,
+// and sets the tip to the previous block.
+func (t transaction) DeleteBlock(hash []byte) error {
+
+	if t.batch == nil {
+		return errors.New("DeleteBlock cannot be called on read-only transaction")
+	}
+
+	state, err := t.FetchState()
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(state.TipHash, hash) {
+		return errors.New("only the chain tip can be deleted")
+	}
+
+	b, err := t.FetchBlock(hash)
+	if err != nil {
+		return err
+	}
+
+	if b.Header.Height == 0 {
+		return errors.New("genesis block cannot be deleted")
+	}
+
+	if err := t.deleteBlock(b); err != nil {
+		return err
+	}
+
+	t.put(StatePrefix, b.Header.PrevBlockHash)
+	return nil
+}
+
+// RollbackToHeight deletes all the blocks above height, from the tip down,
+// and sets the tip to the block at height.
+//
+// As the snapshot is not affected by the batch, the blocks are all read
+// before any deletion is committed.
+func (t transaction) RollbackToHeight(height uint64) error {
+
+	if t.batch == nil {
+		return errors.New("RollbackToHeight cannot be called on read-only transaction")
+	}
+
+	tip, err := t.FetchCurrentHeight()
+	if err != nil {
+		return err
+	}
+
+	if height > tip {
+		return fmt.Errorf("cannot roll back to height %d above the tip %d", height, tip)
+	}
+
+	hash, err := t.FetchBlockHashByHeight(height)
+	if err != nil {
+		return err
+	}
+
+	for h := tip; h > height; h-- {
+		blockHash, err := t.FetchBlockHashByHeight(h)
+		if err != nil {
+			return err
+		}
+
+		b, err := t.FetchBlock(blockHash)
+		if err != nil {
+			return err
+		}
+
+		if err := t.deleteBlock(b); err != nil {
+			return err
+		}
+	}
+
+	t.put(StatePrefix, hash)
+	return nil
+}
+
+// deleteBlock removes the block and the entries indexing it, leaving the
+// chain tip to the caller
+func (t transaction) deleteBlock(b *block.Block) error {
+
+	hash := b.Header.Hash
+	t.delete(append(HeaderPrefix, hash...))
+
+	for _, tx := range b.Txs {
+		txID, err := marshalling.TxID(tx)
+		if err != nil {
+			return err
+		}
+
+		key := append(TxPrefix, hash...)
+		t.delete(append(key, txID...))
+		t.delete(append(TxIDPrefix, txID...))
+
+		for _, input := range tx.StandardTx().Inputs {
+			t.delete(append(KeyImagePrefix, input.KeyImage.Bytes()...))
+		}
+
+		for i, output := range tx.StandardTx().Outputs {
+			if unlockHeight, locked := utils.OutputLock(tx, i, b.Header.Height); locked {
+				t.delete(lockHeightKey(unlockHeight, output.PubKey.P.Bytes()))
+			}
+			t.delete(append(OutputKeyPrefix, output.PubKey.P.Bytes()...))
+		}
+
+		for _, regKey := range utils.RegistrationKeys(tx) {
+			t.delete(registrationKey(regKey, txID))
+		}
+	}
+
+	heightBuf := new(bytes.Buffer)
+	if err := utils.WriteUint64(heightBuf, b.Header.Height); err != nil {
+		return err
+	}
+
+	t.delete(append(HeightPrefix, heightBuf.Bytes()...))
+	return nil
+}
+
 // Commit writes a batch to LevelDB storage. See also fsyncEnabled variable
 func (t *transaction) Commit() error {
 	if !t.writable {
@@ -272,6 +414,60 @@ func (t transaction) FetchOutputUnlockHeight(destkey []byte) (uint64, error) {
 	return unlockHeight, err
 }
 
+// FetchOutputsUnlockingAt returns the public keys of the locked outputs which
+// become spendable at the given height
+func (t transaction) FetchOutputsUnlockingAt(height uint64) ([][]byte, error) {
+	iterator := t.snapshot.NewIterator(util.BytesPrefix(lockHeightKey(height, nil)), nil)
+	defer iterator.Release()
+
+	keys := make([][]byte, 0)
+	for iterator.Next() {
+		// Output public key is the iterator key minus the prefix (1 byte)
+		// and the height (8 bytes)
+		key := make([]byte, len(iterator.Key())-9)
+		copy(key, iterator.Key()[9:])
+		keys = append(keys, key)
+	}
+
+	return keys, iterator.Error()
+}
+
+// StoreKeyImage stores keyImage as spent by txID
+func (t transaction) StoreKeyImage(keyImage, txID []byte) error {
+	if t.batch == nil {
+		return errors.New("StoreKeyImage cannot be called on read-only transaction")
+	}
+
+	t.put(append(KeyImagePrefix, keyImage...), txID)
+	return nil
+}
+
+// StoreOutput stores the unlock height of an output, and indexes it by unlock
+// height if locked
+func (t transaction) StoreOutput(destKey []byte, unlockHeight uint64, locked bool) error {
+	if t.batch == nil {
+		return errors.New("StoreOutput cannot be called on read-only transaction")
+	}
+
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint64(value, unlockHeight)
+	t.put(append(OutputKeyPrefix, destKey...), value)
+
+	if locked {
+		t.put(lockHeightKey(unlockHeight, destKey), nil)
+	}
+	return nil
+}
+
+// lockHeightKey builds a key of the lock height index. The height is encoded
+// in big endian, so that the index is sorted by height.
+func lockHeightKey(height uint64, destkey []byte) []byte {
+	key := make([]byte, 9, 9+len(destkey))
+	key[0] = LockHeightPrefix[0]
+	binary.BigEndian.PutUint64(key[1:], height)
+	return append(key, destkey...)
+}
+
 // FetchDecoys iterates over the outputs and fetches `numDecoys` amount
 // of output public keys
 func (t transaction) FetchDecoys(numDecoys int) []ristretto.Point {
@@ -417,6 +613,20 @@ func (t transaction) put(key []byte, value []byte) {
 	}
 }
 
+func (t transaction) delete(key []byte) {
+
+	if !t.writable {
+		return
+	}
+
+	if t.batch != nil {
+		t.batch.Delete(key)
+	} else {
+		// fail-fast when a writable transaction is not capable of storing data
+		log.Panic("leveldb batch is unreachable")
+	}
+}
+
 func (t transaction) FetchBlockTxByHash(txID []byte) (transactions.Transaction, uint32, []byte, error) {
 
 	txIndex := uint32(math.MaxUint32)
@@ -568,6 +778,100 @@ func (t transaction) FetchBidValues() ([]byte, []byte, error) {
 	return value[0:32], value[32:64], nil
 }
 
+// StoreBid stores the M value of a bid, keyed by its expiry height and X
+func (t transaction) StoreBid(x, m []byte, endHeight uint64) error {
+	if t.batch == nil {
+		return errors.New("StoreBid cannot be called on read-only transaction")
+	}
+
+	t.put(bidKey(endHeight, x), m)
+	return nil
+}
+
+// FetchBids returns the bids expiring at height or later, sorted by expiry
+// height
+func (t transaction) FetchBids(height uint64) ([]database.Bid, error) {
+	r := &util.Range{Start: bidKey(height, nil), Limit: util.BytesPrefix(BidPrefix).Limit}
+	iterator := t.snapshot.NewIterator(r, nil)
+	defer iterator.Release()
+
+	bids := make([]database.Bid, 0)
+	for iterator.Next() {
+		key := iterator.Key()
+		bid := database.Bid{
+			X:         make([]byte, len(key)-9),
+			M:         make([]byte, len(iterator.Value())),
+			EndHeight: binary.BigEndian.Uint64(key[1:9]),
+		}
+		copy(bid.X, key[9:])
+		copy(bid.M, iterator.Value())
+		bids = append(bids, bid)
+	}
+
+	return bids, iterator.Error()
+}
+
+// DeleteExpiredBids removes the bids expiring before height
+func (t transaction) DeleteExpiredBids(height uint64) error {
+	if t.batch == nil {
+		return errors.New("DeleteExpiredBids cannot be called on read-only transaction")
+	}
+
+	r := &util.Range{Start: BidPrefix, Limit: bidKey(height, nil)}
+	iterator := t.snapshot.NewIterator(r, nil)
+	defer iterator.Release()
+
+	for iterator.Next() {
+		key := make([]byte, len(iterator.Key()))
+		copy(key, iterator.Key())
+		t.delete(key)
+	}
+
+	return iterator.Error()
+}
+
+// bidKey builds a key of the bids. As for the lock height index, the height
+// is encoded in big endian so that the bids are sorted by expiry height.
+func bidKey(endHeight uint64, x []byte) []byte {
+	key := make([]byte, 9, 9+len(x))
+	key[0] = BidPrefix[0]
+	binary.BigEndian.PutUint64(key[1:], endHeight)
+	return append(key, x...)
+}
+
+// FetchRegistrations returns the stakes and bids indexed with key
+func (t transaction) FetchRegistrations(key []byte) ([]database.Registration, error) {
+	prefix := registrationKey(key, nil)
+	iterator := t.snapshot.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iterator.Release()
+
+	regs := make([]database.Registration, 0)
+	for iterator.Next() {
+		// Skip the longer keys starting with key, such as a BLS public key
+		// starting with an Ed25519 one
+		if len(iterator.Key()) != len(prefix)+32 || len(iterator.Value()) != 8 {
+			continue
+		}
+
+		reg := database.Registration{
+			TxID:   make([]byte, 32),
+			Height: byteOrder.Uint64(iterator.Value()),
+		}
+		copy(reg.TxID, iterator.Key()[len(prefix):])
+		regs = append(regs, reg)
+	}
+
+	return regs, iterator.Error()
+}
+
+// registrationKey builds a key of the registrations index
+func registrationKey(key, txID []byte) []byte {
+	k := make([]byte, 0, 1+len(key)+len(txID))
+	k = append(k, RegistrationPrefix...)
+	k = append(k, key...)
+	return append(k, txID...)
+}
+
 // FetchBlockHeightSince uses binary search to find a block height
 func (t transaction) FetchBlockHeightSince(sinceUnixTime int64, offset uint64) (uint64, error) {
 
@@ -75,6 +75,16 @@ type Transaction interface {
 	// Not to be called concurrently, as it updates chain tip
 	StoreBlock(block *block.Block) error
 
+	// DeleteBlock removes the chain tip with this hash, undoing StoreBlock,
+	// and makes its previous block the tip. Only the tip as of the start of
+	// the transaction can be deleted, so the chain is rolled back one block
+	// per transaction
+	DeleteBlock(hash []byte) error
+
+	// RollbackToHeight deletes all the blocks above height at once, undoing
+	// StoreBlock for each of them, and makes the block at height the tip
+	RollbackToHeight(height uint64) error
+
 	// FetchBlock will return a block, given a hash.
 	FetchBlock(hash []byte) (*block.Block, error)
 
@@ -94,6 +104,10 @@ type Transaction interface {
 	// given a destination public key.
 	FetchOutputUnlockHeight(destkey []byte) (uint64, error)
 
+	// FetchOutputsUnlockingAt will return the destination public keys of
+	// the locked outputs which become spendable at the given height.
+	FetchOutputsUnlockingAt(height uint64) ([][]byte, error)
+
 	// StoreBidValues stores the D and K values passed by the caller in
 	// the database.
 	StoreBidValues([]byte, []byte) error
@@ -102,6 +116,30 @@ type Transaction interface {
 	// the database.
 	FetchBidValues() ([]byte, []byte, error)
 
+	// StoreBid stores the X and M values of a bid, along with the height
+	// at which it expires
+	StoreBid(x, m []byte, endHeight uint64) error
+
+	// FetchBids returns the bids stored which expire at height or later
+	FetchBids(height uint64) ([]Bid, error)
+
+	// DeleteExpiredBids removes the bids which expire before height
+	DeleteExpiredBids(height uint64) error
+
+	// FetchRegistrations returns the stakes and bids stored with key, either
+	// the Ed25519 or the BLS public key of a stake, or the M value of a bid.
+	// They are indexed by StoreBlock
+	FetchRegistrations(key []byte) ([]Registration, error)
+
+	// StoreKeyImage stores a key image spent by the tx txID, as StoreBlock
+	// does for the inputs of the block txs. For importing a chain snapshot
+	StoreKeyImage(keyImage, txID []byte) error
+
+	// StoreOutput stores an output spendable from unlockHeight, as StoreBlock
+	// does for the outputs of the block txs. Locked outputs are indexed for
+	// FetchOutputsUnlockingAt. For importing a chain snapshot
+	StoreOutput(destKey []byte, unlockHeight uint64, locked bool) error
+
 	// FetchBlockHeightSince try to find height of a block generated around
 	// sinceUnixTime starting the search from height (tip - offset)
 	FetchBlockHeightSince(sinceUnixTime int64, offset uint64) (uint64, error)
@@ -135,3 +173,17 @@ type DB interface {
 type State struct {
 	TipHash []byte
 }
+
+// Bid is a bid stored in the database, as accepted on the chain
+type Bid struct {
+	X         []byte
+	M         []byte
+	EndHeight uint64
+}
+
+// Registration is a stake or a bid stored in the database, along with the
+// height of its block
+type Registration struct {
+	TxID   []byte
+	Height uint64
+}
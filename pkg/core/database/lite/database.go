@@ -22,6 +22,8 @@ const (
 	heightInd
 	stateInd
 	bidValuesInd
+	bidsInd
+	registrationsInd
 	maxInd
 )
 
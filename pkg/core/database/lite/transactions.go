@@ -2,9 +2,11 @@ package lite
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/bwesterb/go-ristretto"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
@@ -47,7 +49,7 @@ func (t *transaction) StoreBlock(b *block.Block) error {
 	// Map txId to transactions.Transaction
 	for i, tx := range b.Txs {
 
-		txID, err := tx.CalculateHash()
+		txID, err := marshalling.TxID(tx)
 		if err != nil {
 			return err
 		}
@@ -68,6 +70,15 @@ func (t *transaction) StoreBlock(b *block.Block) error {
 		for _, input := range tx.StandardTx().Inputs {
 			t.batch[keyImagesInd][toKey(input.KeyImage.Bytes())] = txID
 		}
+
+		// Map the txID of stakes and bids to their height
+		if len(utils.RegistrationKeys(tx)) > 0 {
+			buf := new(bytes.Buffer)
+			if err := utils.WriteUint64(buf, b.Header.Height); err != nil {
+				return err
+			}
+			t.batch[registrationsInd][toKey(txID)] = buf.Bytes()
+		}
 	}
 
 	// Map height to buffer bytes
@@ -85,15 +96,127 @@ func (t *transaction) StoreBlock(b *block.Block) error {
 	return nil
 }
 
+// DeleteBlock removes the chain tip, and sets the tip to the previous block.
+// The entries to remove are marked with a nil value in the batch.
+func (t *transaction) DeleteBlock(hash []byte) error {
+
+	if !t.writable {
+		return errors.New("read-only transaction")
+	}
+
+	state, err := t.FetchState()
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(state.TipHash, hash) {
+		return errors.New("only the chain tip can be deleted")
+	}
+
+	b, err := t.FetchBlock(hash)
+	if err != nil {
+		return err
+	}
+
+	if b.Header.Height == 0 {
+		return errors.New("genesis block cannot be deleted")
+	}
+
+	if err := t.deleteBlock(b); err != nil {
+		return err
+	}
+
+	t.batch[stateInd][toKey(stateKey)] = b.Header.PrevBlockHash
+	return nil
+}
+
+// RollbackToHeight deletes all the blocks above height, and sets the tip to
+// the block at height
+func (t *transaction) RollbackToHeight(height uint64) error {
+
+	if !t.writable {
+		return errors.New("read-only transaction")
+	}
+
+	tip, err := t.FetchCurrentHeight()
+	if err != nil {
+		return err
+	}
+
+	if height > tip {
+		return fmt.Errorf("cannot roll back to height %d above the tip %d", height, tip)
+	}
+
+	hash, err := t.FetchBlockHashByHeight(height)
+	if err != nil {
+		return err
+	}
+
+	for h := tip; h > height; h-- {
+		blockHash, err := t.FetchBlockHashByHeight(h)
+		if err != nil {
+			return err
+		}
+
+		b, err := t.FetchBlock(blockHash)
+		if err != nil {
+			return err
+		}
+
+		if err := t.deleteBlock(b); err != nil {
+			return err
+		}
+	}
+
+	t.batch[stateInd][toKey(stateKey)] = hash
+	return nil
+}
+
+// deleteBlock marks the block and its indexes for removal
+func (t *transaction) deleteBlock(b *block.Block) error {
+
+	t.batch[blocksInd][toKey(b.Header.Hash)] = nil
+
+	for _, tx := range b.Txs {
+		txID, err := marshalling.TxID(tx)
+		if err != nil {
+			return err
+		}
+
+		t.batch[txsInd][toKey(txID)] = nil
+		t.batch[txHashInd][toKey(txID)] = nil
+
+		for _, input := range tx.StandardTx().Inputs {
+			t.batch[keyImagesInd][toKey(input.KeyImage.Bytes())] = nil
+		}
+
+		if len(utils.RegistrationKeys(tx)) > 0 {
+			t.batch[registrationsInd][toKey(txID)] = nil
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := utils.WriteUint64(buf, b.Header.Height); err != nil {
+		return err
+	}
+
+	t.batch[heightInd][toKey(buf.Bytes())] = nil
+	return nil
+}
+
 // Commit writes a batch to LevelDB storage. See also fsyncEnabled variable
 func (t *transaction) Commit() error {
 	if !t.writable {
 		return errors.New("read-only transaction cannot commit changes")
 	}
 
-	/// commit changes
+	/// commit changes, nil values being deletions
 	for i := range t.db.storage {
 		for k, v := range t.batch[i] {
+			if v == nil {
+				delete(t.db.storage[i], k)
+				continue
+			}
 			t.db.storage[i][k] = v
 		}
 	}
@@ -207,6 +330,25 @@ func (t transaction) FetchOutputUnlockHeight(destkey []byte) (uint64, error) {
 	return 0, nil
 }
 
+func (t transaction) FetchOutputsUnlockingAt(height uint64) ([][]byte, error) {
+	return nil, nil
+}
+
+// StoreKeyImage stores keyImage as spent by txID
+func (t *transaction) StoreKeyImage(keyImage, txID []byte) error {
+	if !t.writable {
+		return errors.New("read-only transaction")
+	}
+
+	t.batch[keyImagesInd][toKey(keyImage)] = txID
+	return nil
+}
+
+// StoreOutput is a no-op, as outputs are not tracked by this driver
+func (t *transaction) StoreOutput(destKey []byte, unlockHeight uint64, locked bool) error {
+	return nil
+}
+
 func (t transaction) FetchState() (*database.State, error) {
 
 	var hash []byte
@@ -281,6 +423,83 @@ func (t *transaction) FetchBidValues() ([]byte, []byte, error) {
 	return t.db.storage[bidValuesInd][bidKey][0:32], t.db.storage[bidValuesInd][bidKey][32:64], nil
 }
 
+// StoreBid stores the expiry height and M value of a bid, keyed by X
+func (t *transaction) StoreBid(x, m []byte, endHeight uint64) error {
+	if !t.writable {
+		return errors.New("read-only transaction")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := utils.WriteUint64(buf, endHeight); err != nil {
+		return err
+	}
+
+	t.batch[bidsInd][toKey(x)] = append(buf.Bytes(), m...)
+	return nil
+}
+
+// FetchBids returns the bids expiring at height or later, sorted by expiry
+// height
+func (t transaction) FetchBids(height uint64) ([]database.Bid, error) {
+	bids := make([]database.Bid, 0)
+	for k, v := range t.db.storage[bidsInd] {
+		endHeight := binary.LittleEndian.Uint64(v[:8])
+		if endHeight < height {
+			continue
+		}
+
+		x := make([]byte, 32)
+		copy(x, k[:32])
+		bids = append(bids, database.Bid{X: x, M: v[8:], EndHeight: endHeight})
+	}
+
+	sort.Slice(bids, func(i, j int) bool {
+		if bids[i].EndHeight != bids[j].EndHeight {
+			return bids[i].EndHeight < bids[j].EndHeight
+		}
+		return bytes.Compare(bids[i].X, bids[j].X) < 0
+	})
+	return bids, nil
+}
+
+// DeleteExpiredBids removes the bids expiring before height
+func (t *transaction) DeleteExpiredBids(height uint64) error {
+	if !t.writable {
+		return errors.New("read-only transaction")
+	}
+
+	for k, v := range t.db.storage[bidsInd] {
+		if binary.LittleEndian.Uint64(v[:8]) < height {
+			t.batch[bidsInd][k] = nil
+		}
+	}
+	return nil
+}
+
+// FetchRegistrations returns the stakes and bids stored with key. As the keys
+// do not fit in the table keys, the stakes and bids are all looked up
+func (t transaction) FetchRegistrations(key []byte) ([]database.Registration, error) {
+	regs := make([]database.Registration, 0)
+	for k, v := range t.db.storage[registrationsInd] {
+		txID := make([]byte, 32)
+		copy(txID, k[:32])
+
+		tx, _, _, err := t.FetchBlockTxByHash(txID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, regKey := range utils.RegistrationKeys(tx) {
+			if bytes.Equal(regKey, key) {
+				regs = append(regs, database.Registration{TxID: txID, Height: binary.LittleEndian.Uint64(v)})
+				break
+			}
+		}
+	}
+
+	return regs, nil
+}
+
 // FetchBlockHeightSince uses binary search to find a block height
 // NB: Duplicates FetchBlockHeightSince heavy driver
 func (t transaction) FetchBlockHeightSince(sinceUnixTime int64, offset uint64) (uint64, error) {
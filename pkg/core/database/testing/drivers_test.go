@@ -10,8 +10,10 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/utils"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/dusk-network/dusk-wallet/transactions"
 	"github.com/syndtr/goleveldb/leveldb"
 
 	// Import here any supported drivers to verify if they are fully compliant
@@ -178,6 +180,184 @@ func TestStoreBlock(test *testing.T) {
 		test.Fatal(err.Error())
 	}
 }
+
+// TestDeleteBlock ensures that deleting the chain tip restores the previous
+// one. No parallelism should be applied, as the tip changes.
+func TestDeleteBlock(test *testing.T) {
+
+	var prevTip []byte
+	err := db.View(func(t database.Transaction) error {
+		s, err := t.FetchState()
+		if err != nil {
+			return err
+		}
+		prevTip = s.TipHash
+		return nil
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	genBlocks, err := generateChainBlocks(test, 1)
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	blk := genBlocks[0]
+	blk.Header.PrevBlockHash = prevTip
+	if err := storeBlocks(test, db, genBlocks); err != nil {
+		test.Fatal(err.Error())
+	}
+
+	err = db.Update(func(t database.Transaction) error {
+		return t.DeleteBlock(blk.Header.Hash)
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	err = db.View(func(t database.Transaction) error {
+		s, err := t.FetchState()
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(prevTip, s.TipHash) {
+			return fmt.Errorf("invalid chain tip")
+		}
+
+		if _, err := t.FetchBlockExists(blk.Header.Hash); err != database.ErrBlockNotFound {
+			return fmt.Errorf("deleted block still exists")
+		}
+
+		if _, err := t.FetchBlockHashByHeight(blk.Header.Height); err != database.ErrBlockNotFound {
+			return fmt.Errorf("deleted block still indexed by height")
+		}
+
+		for _, tx := range blk.Txs {
+			for _, input := range tx.StandardTx().Inputs {
+				if exists, _, _ := t.FetchKeyImageExists(input.KeyImage.Bytes()); exists {
+					return fmt.Errorf("key image of a deleted block still exists")
+				}
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	// Only the tip can be deleted
+	err = db.Update(func(t database.Transaction) error {
+		return t.DeleteBlock(blocks[0].Header.Hash)
+	})
+
+	if err == nil {
+		test.Fatal("a block below the tip was deleted")
+	}
+}
+
+// TestRollbackToHeight ensures that the blocks above a height are deleted at
+// once, along with their indexes. No parallelism should be applied, as the
+// tip changes.
+func TestRollbackToHeight(test *testing.T) {
+
+	var prevTip *block.Header
+	err := db.View(func(t database.Transaction) error {
+		s, err := t.FetchState()
+		if err != nil {
+			return err
+		}
+
+		prevTip, err = t.FetchBlockHeader(s.TipHash)
+		return err
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	// Generate the blocks right above the tip
+	atomic.StoreUint64(&heightCounter, prevTip.Height)
+	genBlocks, err := generateChainBlocks(test, 3)
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	prevHash := prevTip.Hash
+	for _, blk := range genBlocks {
+		blk.Header.PrevBlockHash = prevHash
+		prevHash = blk.Header.Hash
+	}
+
+	if err := storeBlocks(test, db, genBlocks); err != nil {
+		test.Fatal(err.Error())
+	}
+
+	err = db.Update(func(t database.Transaction) error {
+		return t.RollbackToHeight(prevTip.Height)
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	err = db.View(func(t database.Transaction) error {
+		s, err := t.FetchState()
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(prevTip.Hash, s.TipHash) {
+			return fmt.Errorf("invalid chain tip")
+		}
+
+		for _, blk := range genBlocks {
+			if _, err := t.FetchBlockExists(blk.Header.Hash); err != database.ErrBlockNotFound {
+				return fmt.Errorf("rolled back block still exists")
+			}
+
+			if _, err := t.FetchBlockHashByHeight(blk.Header.Height); err != database.ErrBlockNotFound {
+				return fmt.Errorf("rolled back block still indexed by height")
+			}
+
+			for _, tx := range blk.Txs {
+				txID, err := marshalling.TxID(tx)
+				if err != nil {
+					return err
+				}
+
+				if _, _, _, err := t.FetchBlockTxByHash(txID); err != database.ErrTxNotFound {
+					return fmt.Errorf("tx of a rolled back block still exists")
+				}
+
+				for _, input := range tx.StandardTx().Inputs {
+					if exists, _, _ := t.FetchKeyImageExists(input.KeyImage.Bytes()); exists {
+						return fmt.Errorf("key image of a rolled back block still exists")
+					}
+				}
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	// The chain can not be rolled back above its tip
+	err = db.Update(func(t database.Transaction) error {
+		return t.RollbackToHeight(prevTip.Height + 1)
+	})
+
+	if err == nil {
+		test.Fatal("rolled back above the tip")
+	}
+}
+
 func TestFetchBlockExists(test *testing.T) {
 
 	test.Parallel()
@@ -409,6 +589,243 @@ func TestFetchKeyImageExists(test *testing.T) {
 	})
 }
 
+func TestFetchOutputsUnlockingAt(test *testing.T) {
+
+	// The lite driver does not keep track of outputs
+	if drvrName == lite.DriverName {
+		test.Skip()
+	}
+
+	test.Parallel()
+
+	// Ensure the first output of every locked tx is indexed by its unlock
+	// height
+	err := db.View(func(t database.Transaction) error {
+		for _, block := range blocks {
+			for _, tx := range block.Txs {
+				if tx.LockTime() == 0 || len(tx.StandardTx().Outputs) == 0 {
+					continue
+				}
+
+				keys, err := t.FetchOutputsUnlockingAt(tx.LockTime() + block.Header.Height)
+				if err != nil {
+					return err
+				}
+
+				pubKey := tx.StandardTx().Outputs[0].PubKey.P.Bytes()
+				var found bool
+				for _, key := range keys {
+					if bytes.Equal(key, pubKey) {
+						found = true
+						break
+					}
+				}
+
+				if !found {
+					test.Fatal("FetchOutputsUnlockingAt cannot find locked output")
+				}
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+}
+
+func TestStoreKeyImageAndOutput(test *testing.T) {
+
+	keyImage := bytes.Repeat([]byte{4}, 32)
+	txID := bytes.Repeat([]byte{5}, 32)
+	destKey := bytes.Repeat([]byte{6}, 32)
+	// Far above the unlock heights of the sample blocks
+	unlockHeight := uint64(1 << 40)
+
+	err := db.Update(func(t database.Transaction) error {
+		if err := t.StoreKeyImage(keyImage, txID); err != nil {
+			return err
+		}
+		return t.StoreOutput(destKey, unlockHeight, true)
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	err = db.View(func(t database.Transaction) error {
+		exists, id, err := t.FetchKeyImageExists(keyImage)
+		if err != nil {
+			return err
+		}
+
+		if !exists || !bytes.Equal(id, txID) {
+			return fmt.Errorf("key image not stored")
+		}
+
+		// The lite driver does not keep track of outputs
+		if drvrName == lite.DriverName {
+			return nil
+		}
+
+		height, err := t.FetchOutputUnlockHeight(destKey)
+		if err != nil {
+			return err
+		}
+
+		if height != unlockHeight {
+			return fmt.Errorf("invalid unlock height %d", height)
+		}
+
+		keys, err := t.FetchOutputsUnlockingAt(unlockHeight)
+		if err != nil {
+			return err
+		}
+
+		if len(keys) != 1 || !bytes.Equal(keys[0], destKey) {
+			return fmt.Errorf("locked output not indexed")
+		}
+		return nil
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+}
+
+func TestStoreBids(test *testing.T) {
+
+	x1 := bytes.Repeat([]byte{1}, 32)
+	x2 := bytes.Repeat([]byte{2}, 32)
+	m := bytes.Repeat([]byte{3}, 32)
+
+	err := db.Update(func(t database.Transaction) error {
+		if err := t.StoreBid(x1, m, 10); err != nil {
+			return err
+		}
+		return t.StoreBid(x2, m, 20)
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	// Bids can not be stored nor deleted from a read-only transaction
+	err = db.View(func(t database.Transaction) error {
+		if t.StoreBid(x1, m, 10) == nil {
+			return fmt.Errorf("bid stored on a read-only transaction")
+		}
+
+		if t.DeleteExpiredBids(20) == nil {
+			return fmt.Errorf("bids deleted on a read-only transaction")
+		}
+		return nil
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	// Only the bids expiring at the height or later are fetched
+	err = db.View(func(t database.Transaction) error {
+		bids, err := t.FetchBids(15)
+		if err != nil {
+			return err
+		}
+
+		if len(bids) != 1 || !bytes.Equal(bids[0].X, x2) || !bytes.Equal(bids[0].M, m) || bids[0].EndHeight != 20 {
+			return fmt.Errorf("invalid bids fetched")
+		}
+		return nil
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	err = db.Update(func(t database.Transaction) error {
+		return t.DeleteExpiredBids(20)
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	err = db.View(func(t database.Transaction) error {
+		bids, err := t.FetchBids(0)
+		if err != nil {
+			return err
+		}
+
+		if len(bids) != 1 || !bytes.Equal(bids[0].X, x2) {
+			return fmt.Errorf("expired bid not deleted")
+		}
+		return nil
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+
+	// Clean up
+	err = db.Update(func(t database.Transaction) error {
+		return t.DeleteExpiredBids(math.MaxUint64)
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+}
+
+func TestFetchStakesAndBids(test *testing.T) {
+
+	test.Parallel()
+
+	// Ensure every stake and bid can be found by its keys
+	err := db.View(func(t database.Transaction) error {
+		for _, block := range blocks {
+			for _, tx := range block.Txs {
+				switch tx := tx.(type) {
+				case *transactions.Stake:
+					for _, key := range [][]byte{tx.PubKeyBLS, tx.PubKeyEd} {
+						stakes, err := utils.FetchStakes(t, key)
+						if err != nil {
+							return err
+						}
+
+						if len(stakes) != 1 || stakes[0].Height != block.Header.Height || stakes[0].EndHeight != block.Header.Height+tx.Lock {
+							test.Fatal("FetchStakes cannot find stake")
+						}
+					}
+				case *transactions.Bid:
+					bids, err := utils.FetchBids(t, tx.M)
+					if err != nil {
+						return err
+					}
+
+					if len(bids) != 1 || bids[0].Height != block.Header.Height || bids[0].EndHeight != block.Header.Height+tx.Lock {
+						test.Fatal("FetchBids cannot find bid")
+					}
+				}
+			}
+		}
+
+		stakes, err := utils.FetchStakes(t, []byte{0})
+		if err != nil {
+			return err
+		}
+
+		if len(stakes) != 0 {
+			test.Fatal("FetchStakes returned a stake for an unknown key")
+		}
+		return nil
+	})
+
+	if err != nil {
+		test.Fatal(err.Error())
+	}
+}
+
 // TestAtomicUpdates ensures no change is applied into storage state when DB
 // writable tx does fail
 func TestAtomicUpdates(test *testing.T) {
@@ -611,7 +1028,7 @@ func TestFetchBlockTxByHash(test *testing.T) {
 			for txIndex, originTx := range block.Txs {
 
 				// FetchBlockTxByHash
-				txID, _ := originTx.CalculateHash()
+				txID, _ := marshalling.TxID(originTx)
 				fetchedTx, fetchedIndex, _, err := t.FetchBlockTxByHash(txID)
 
 				if err != nil {
@@ -0,0 +1,14 @@
+package utils
+
+import "github.com/dusk-network/dusk-wallet/transactions"
+
+// OutputLock returns the height from which the output at index of tx, included
+// in the block at height, can be spent, and whether it is locked until then.
+// Only the first output is locked, so that change outputs are not affected.
+// The other outputs have a zero unlock height.
+func OutputLock(tx transactions.Transaction, index int, height uint64) (uint64, bool) {
+	if index != 0 {
+		return 0, false
+	}
+	return tx.LockTime() + height, tx.LockTime() > 0
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"sort"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-wallet/transactions"
+)
+
+// StakeInfo describes a stake found on the chain. As for the provisioners
+// committee, the stake is effective from StartHeight and expires after
+// EndHeight.
+type StakeInfo struct {
+	TxID        []byte
+	Height      uint64
+	PubKeyEd    []byte
+	PubKeyBLS   []byte
+	Amount      uint64
+	Lock        uint64
+	StartHeight uint64
+	EndHeight   uint64
+}
+
+// BidInfo describes a bid found on the chain. The bid amount is hidden in the
+// commitment of its first output.
+type BidInfo struct {
+	TxID       []byte
+	Height     uint64
+	M          []byte
+	Commitment []byte
+	Lock       uint64
+	EndHeight  uint64
+}
+
+// NewStakeInfo returns the registration details of a stake included at the
+// given height
+func NewStakeInfo(tx *transactions.Stake, height uint64) (StakeInfo, error) {
+	txid, err := marshalling.TxID(tx)
+	if err != nil {
+		return StakeInfo{}, err
+	}
+
+	return StakeInfo{
+		TxID:        txid,
+		Height:      height,
+		PubKeyEd:    tx.PubKeyEd,
+		PubKeyBLS:   tx.PubKeyBLS,
+		Amount:      tx.Outputs[0].EncryptedAmount.BigInt().Uint64(),
+		Lock:        tx.Lock,
+		StartHeight: cfg.GetConsensusParams().StakeStart(height),
+		EndHeight:   height + tx.Lock,
+	}, nil
+}
+
+// NewBidInfo returns the registration details of a bid included at the given
+// height
+func NewBidInfo(tx *transactions.Bid, height uint64) (BidInfo, error) {
+	txid, err := marshalling.TxID(tx)
+	if err != nil {
+		return BidInfo{}, err
+	}
+
+	return BidInfo{
+		TxID:       txid,
+		Height:     height,
+		M:          tx.M,
+		Commitment: tx.Outputs[0].Commitment.Bytes(),
+		Lock:       tx.Lock,
+		EndHeight:  height + tx.Lock,
+	}, nil
+}
+
+// RegistrationKeys returns the keys a stake or a bid is indexed with by the
+// database drivers: the Ed25519 and BLS public keys of a stake, or the M value
+// of a bid. Other txs are not indexed.
+func RegistrationKeys(tx transactions.Transaction) [][]byte {
+	switch tx := tx.(type) {
+	case *transactions.Stake:
+		return [][]byte{tx.PubKeyEd, tx.PubKeyBLS}
+	case *transactions.Bid:
+		return [][]byte{tx.M}
+	default:
+		return nil
+	}
+}
+
+// FetchStakes returns the stakes made with key, which can be either the Ed25519
+// or the BLS public key of the provisioner. Only the stakes which can still be
+// unexpired are returned, that is those of the last transactions.MaxLockTime
+// blocks.
+func FetchStakes(t database.Transaction, key []byte) ([]StakeInfo, error) {
+	stakes := make([]StakeInfo, 0)
+	err := fetchRegistrations(t, key, func(tx transactions.Transaction, height uint64) error {
+		stake, ok := tx.(*transactions.Stake)
+		if !ok {
+			return nil
+		}
+
+		info, err := NewStakeInfo(stake, height)
+		if err != nil {
+			return err
+		}
+		stakes = append(stakes, info)
+		return nil
+	})
+
+	return stakes, err
+}
+
+// FetchBids returns the bids made with the bidding key m, from the same
+// blocks as FetchStakes.
+func FetchBids(t database.Transaction, m []byte) ([]BidInfo, error) {
+	bids := make([]BidInfo, 0)
+	err := fetchRegistrations(t, m, func(tx transactions.Transaction, height uint64) error {
+		bid, ok := tx.(*transactions.Bid)
+		if !ok {
+			return nil
+		}
+
+		info, err := NewBidInfo(bid, height)
+		if err != nil {
+			return err
+		}
+		bids = append(bids, info)
+		return nil
+	})
+
+	return bids, err
+}
+
+// fetchRegistrations calls fn on the stakes and bids indexed with key, of the
+// last transactions.MaxLockTime blocks, in chain order
+func fetchRegistrations(t database.Transaction, key []byte, fn func(transactions.Transaction, uint64) error) error {
+	currentHeight, err := t.FetchCurrentHeight()
+	if err != nil {
+		return err
+	}
+
+	from := uint64(0)
+	if currentHeight > transactions.MaxLockTime {
+		from = currentHeight - transactions.MaxLockTime
+	}
+
+	regs, err := t.FetchRegistrations(key)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(regs, func(i, j int) bool {
+		return regs[i].Height < regs[j].Height
+	})
+
+	for _, reg := range regs {
+		if reg.Height < from {
+			continue
+		}
+
+		tx, _, _, err := t.FetchBlockTxByHash(reg.TxID)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(tx, reg.Height); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
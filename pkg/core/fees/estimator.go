@@ -0,0 +1,89 @@
+package fees
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/dusk-network/dusk-wallet/transactions"
+)
+
+// EstimatorWindow is the amount of recent blocks considered by the Estimator
+const EstimatorWindow = 30
+
+// Estimator keeps the median fee rate of the txs of the last EstimatorWindow
+// blocks. Blocks without any tx besides the coinbase are not accounted for.
+type Estimator struct {
+	lock    sync.RWMutex
+	medians []uint64
+	next    int
+}
+
+// NewEstimator returns an Estimator with no blocks
+func NewEstimator() *Estimator {
+	return &Estimator{medians: make([]uint64, 0, EstimatorWindow)}
+}
+
+// AddBlock accounts for the fee rates of the txs of b
+func (e *Estimator) AddBlock(b block.Block) error {
+	rates := make([]uint64, 0, len(b.Txs))
+	for _, tx := range b.Txs {
+		if tx.Type() == transactions.CoinbaseType {
+			continue
+		}
+
+		buf := new(bytes.Buffer)
+		if err := marshalling.MarshalTx(buf, tx); err != nil {
+			return err
+		}
+		rates = append(rates, Rate(tx.StandardTx().Fee.BigInt().Uint64(), buf.Len()))
+	}
+
+	if len(rates) == 0 {
+		return nil
+	}
+
+	sort.Slice(rates, func(i, j int) bool { return rates[i] < rates[j] })
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if len(e.medians) < EstimatorWindow {
+		e.medians = append(e.medians, rates[len(rates)/2])
+		return nil
+	}
+
+	e.medians[e.next] = rates[len(rates)/2]
+	e.next = (e.next + 1) % EstimatorWindow
+	return nil
+}
+
+// Estimate returns the fee rate a tx should pay to be included within
+// targetBlocks blocks, along with the amount of blocks the estimation is based
+// on. A target of 1 block returns the highest of the recent medians, and the
+// estimation decreases towards the lowest one as the target grows. The
+// estimation is never lower than the minimum relay fee.
+func (e *Estimator) Estimate(targetBlocks uint64) (uint64, int) {
+	e.lock.RLock()
+	medians := make([]uint64, len(e.medians))
+	copy(medians, e.medians)
+	e.lock.RUnlock()
+
+	if targetBlocks == 0 {
+		targetBlocks = 1
+	}
+
+	rate := uint64(0)
+	if len(medians) > 0 {
+		sort.Slice(medians, func(i, j int) bool { return medians[i] > medians[j] })
+		last := uint64(len(medians) - 1)
+		rate = medians[last-last/targetBlocks]
+	}
+
+	if min := MinRelayFee(); rate < min {
+		rate = min
+	}
+
+	return rate, len(medians)
+}
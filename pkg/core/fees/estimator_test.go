@@ -0,0 +1,63 @@
+package fees
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/dusk-network/dusk-wallet/transactions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimate(t *testing.T) {
+	e := NewEstimator()
+	rate, blocks := e.Estimate(1)
+	assert.Equal(t, uint64(0), rate)
+	assert.Equal(t, 0, blocks)
+
+	e.medians = []uint64{10, 50, 30, 20, 40}
+
+	// Shorter targets pay higher rates
+	rate, blocks = e.Estimate(1)
+	assert.Equal(t, uint64(50), rate)
+	assert.Equal(t, 5, blocks)
+	rate, _ = e.Estimate(2)
+	assert.Equal(t, uint64(30), rate)
+	rate, _ = e.Estimate(1000)
+	assert.Equal(t, uint64(10), rate)
+
+	// Estimations never go below the minimum relay fee
+	prev := config.Get()
+	defer config.Mock(&prev)
+	r := prev
+	r.Mempool.MinRelayFee = 35
+	config.Mock(&r)
+	rate, _ = e.Estimate(1000)
+	assert.Equal(t, uint64(35), rate)
+}
+
+func TestAddBlock(t *testing.T) {
+	e := NewEstimator()
+
+	// Blocks holding only a coinbase are not accounted for
+	b := block.NewBlock()
+	b.Txs = []transactions.Transaction{helper.RandomCoinBaseTx(t, false)}
+	assert.NoError(t, e.AddBlock(*b))
+	assert.Empty(t, e.medians)
+
+	tx := helper.RandomStandardTx(t, false)
+	rate, err := TxRate(tx)
+	assert.NoError(t, err)
+
+	b.Txs = append(b.Txs, tx)
+	for i := 0; i < EstimatorWindow+5; i++ {
+		assert.NoError(t, e.AddBlock(*b))
+	}
+
+	// Only the last blocks are kept
+	assert.Len(t, e.medians, EstimatorWindow)
+	estimate, blocks := e.Estimate(1)
+	assert.Equal(t, rate, estimate)
+	assert.Equal(t, EstimatorWindow, blocks)
+}
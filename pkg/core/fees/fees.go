@@ -0,0 +1,56 @@
+// Package fees implements the fee policy of the node: the minimum fee rate at
+// which transactions are relayed and included in candidate blocks, and the
+// estimation of the rate needed for a transaction to be accepted within a
+// given amount of blocks.
+//
+// Fee rates are expressed in atomic units per byte of the marshalled
+// transaction.
+package fees
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-wallet/transactions"
+)
+
+// ErrFeeTooLow is returned for transactions paying less than the minimum
+// relay fee rate
+var ErrFeeTooLow = errors.New("fee rate below minimum relay fee")
+
+// Rate returns the fee paid for each byte of a transaction of the given size
+func Rate(fee uint64, size int) uint64 {
+	if size <= 0 {
+		return 0
+	}
+
+	return fee / uint64(size)
+}
+
+// TxRate returns the fee rate paid by tx
+func TxRate(tx transactions.Transaction) (uint64, error) {
+	buf := new(bytes.Buffer)
+	if err := marshalling.MarshalTx(buf, tx); err != nil {
+		return 0, err
+	}
+
+	return Rate(tx.StandardTx().Fee.BigInt().Uint64(), buf.Len()), nil
+}
+
+// MinRelayFee returns the minimum fee rate, set by mempool.minRelayFee. It is
+// a policy of this node, on top of the consensus config.MinFee.
+func MinRelayFee() uint64 {
+	return config.Get().Mempool.MinRelayFee
+}
+
+// CheckRelayFee returns ErrFeeTooLow if tx, of the given size, pays less than
+// the minimum relay fee rate
+func CheckRelayFee(tx transactions.Transaction, size int) error {
+	if Rate(tx.StandardTx().Fee.BigInt().Uint64(), size) < MinRelayFee() {
+		return ErrFeeTooLow
+	}
+
+	return nil
+}
@@ -10,7 +10,17 @@ import (
 	"github.com/dusk-network/dusk-wallet/transactions"
 )
 
+const (
+	// CertificateSize is the size of a marshalled block.Certificate
+	CertificateSize = 33 + 33 + 1 + 8 + 8
+	// HeaderSize is the size of a marshalled block.Header
+	HeaderSize = 1 + 8 + 8 + 32 + 33 + 32 + CertificateSize + 32
+)
+
 func MarshalBlock(r *bytes.Buffer, b *block.Block) error {
+	// Pre-size the buffer, to avoid growing it for each transaction
+	r.Grow(HeaderSize + 9 + len(b.Txs)*txSizeHint)
+
 	if err := MarshalHeader(r, b.Header); err != nil {
 		return err
 	}
@@ -35,6 +45,10 @@ func UnmarshalBlock(r *bytes.Buffer, b *block.Block) error {
 		return err
 	}
 
+	return unmarshalTxs(r, b)
+}
+
+func unmarshalTxs(r *bytes.Buffer, b *block.Block) error {
 	lTxs, err := encoding.ReadVarInt(r)
 	if err != nil {
 		return err
@@ -67,7 +81,7 @@ func MarshalHashable(r *bytes.Buffer, h *block.Header) error {
 		return err
 	}
 
-	if err := encoding.WriteUint64LE(r, uint64(h.Timestamp)); err != nil {
+	if err := encoding.WriteTimestamp(r, h.Timestamp); err != nil {
 		return err
 	}
 
@@ -111,11 +125,9 @@ func UnmarshalHeader(r *bytes.Buffer, h *block.Header) error {
 		return err
 	}
 
-	var timestamp uint64
-	if err := encoding.ReadUint64LE(r, &timestamp); err != nil {
+	if err := encoding.ReadTimestamp(r, &h.Timestamp); err != nil {
 		return err
 	}
-	h.Timestamp = int64(timestamp)
 
 	h.PrevBlockHash = make([]byte, 32)
 	if err := encoding.Read256(r, h.PrevBlockHash); err != nil {
@@ -74,3 +74,94 @@ func TestEncodeDecodeHeader(t *testing.T) {
 	// Check both structs are equal
 	assert.True(hdr.Equals(decHdr))
 }
+
+func TestStreamBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	blk := helper.RandomBlock(t, 200, 2)
+
+	// The streamed encoding should match the buffered one
+	buf := new(bytes.Buffer)
+	assert.NoError(marshalling.MarshalBlock(buf, blk))
+
+	streamed := new(bytes.Buffer)
+	assert.NoError(marshalling.EncodeBlock(streamed, blk))
+	assert.Equal(buf.Bytes(), streamed.Bytes())
+
+	decBlk := block.NewBlock()
+	assert.NoError(marshalling.DecodeBlock(streamed, decBlk))
+	assert.True(blk.Equals(decBlk))
+}
+
+func TestStreamHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	headers := make([]*block.Header, 5)
+	for i := range headers {
+		headers[i] = helper.RandomHeader(t, uint64(200+i))
+		assert.NoError(headers[i].SetHash())
+	}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(marshalling.EncodeHeaders(buf, headers))
+	// Count prefix plus fixed-size headers
+	assert.Equal(1+5*marshalling.HeaderSize, buf.Len())
+
+	decHeaders, err := marshalling.DecodeHeaders(buf)
+	assert.NoError(err)
+	assert.Equal(len(headers), len(decHeaders))
+	for i := range headers {
+		assert.True(headers[i].Equals(decHeaders[i]))
+	}
+
+	// Batches which are too large are refused
+	assert.Error(marshalling.EncodeHeaders(new(bytes.Buffer), make([]*block.Header, marshalling.MaxHeadersPerBatch+1)))
+}
+
+// Each tx batch holds 4 transactions, on top of the coinbase
+func BenchmarkEncodeBlock100(b *testing.B) {
+	benchmarkEncodeBlock(b, 25)
+}
+
+func BenchmarkEncodeBlock1000(b *testing.B) {
+	benchmarkEncodeBlock(b, 250)
+}
+
+func BenchmarkDecodeBlock100(b *testing.B) {
+	benchmarkDecodeBlock(b, 25)
+}
+
+func BenchmarkDecodeBlock1000(b *testing.B) {
+	benchmarkDecodeBlock(b, 250)
+}
+
+func benchmarkEncodeBlock(b *testing.B, txBatchCount uint16) {
+	blk := helper.RandomBlock(b, 200, txBatchCount)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf := new(bytes.Buffer)
+		if err := marshalling.MarshalBlock(buf, blk); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkDecodeBlock(b *testing.B, txBatchCount uint16) {
+	blk := helper.RandomBlock(b, 200, txBatchCount)
+	buf := new(bytes.Buffer)
+	if err := marshalling.MarshalBlock(buf, blk); err != nil {
+		b.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		decBlk := block.NewBlock()
+		if err := marshalling.UnmarshalBlock(bytes.NewBuffer(encoded), decBlk); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
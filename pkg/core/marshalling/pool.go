@@ -0,0 +1,41 @@
+package marshalling
+
+import (
+	"bytes"
+	"sync"
+)
+
+const (
+	// txSizeHint is a rough estimate of the size of a marshalled
+	// transaction. It is used to pre-size the buffer a block gets
+	// marshalled into, to avoid growing it repeatedly.
+	txSizeHint = 2048
+
+	// maxPooledSize is the capacity above which scratch buffers are not
+	// returned to the pool, to avoid holding on to exceptionally large
+	// allocations.
+	maxPooledSize = 64 * 1024
+)
+
+// scratchPool holds the intermediate buffers used while marshalling
+// signatures and rangeproofs, which are written as VarBytes and thus need
+// to be encoded separately first.
+var scratchPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+func getScratch() *bytes.Buffer {
+	buf := scratchPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putScratch(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledSize {
+		return
+	}
+
+	scratchPool.Put(buf)
+}
@@ -0,0 +1,160 @@
+package marshalling
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-wallet/block"
+)
+
+// MaxHeadersPerBatch is the maximum amount of headers that can be streamed in
+// a single batch
+const MaxHeadersPerBatch = 2000
+
+// The functions in this file complement the bytes.Buffer based marshalling
+// functions with io.Writer and io.Reader based ones. They are used for
+// messages which could grow large (blocks and header batches), to avoid
+// building (and copying) the whole message in memory.
+// The produced encoding is identical to the one of the buffer based
+// functions.
+
+// EncodeBlock streams the block to w. Only a single transaction is held in
+// memory at any given time.
+func EncodeBlock(w io.Writer, b *block.Block) error {
+	scratch := new(bytes.Buffer)
+	if err := MarshalHeader(scratch, b.Header); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteVarInt(scratch, uint64(len(b.Txs))); err != nil {
+		return err
+	}
+
+	if _, err := scratch.WriteTo(w); err != nil {
+		return err
+	}
+
+	for _, tx := range b.Txs {
+		scratch.Reset()
+		if err := MarshalTx(scratch, tx); err != nil {
+			return err
+		}
+
+		if _, err := scratch.WriteTo(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeBlock reads a block from r. As transactions are variable-length
+// structures, r is expected to be exhausted by the block, which is the case
+// for the payload of a wire message.
+func DecodeBlock(r io.Reader, b *block.Block) error {
+	if err := DecodeHeader(r, b.Header); err != nil {
+		return err
+	}
+
+	txs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalTxs(bytes.NewBuffer(txs), b)
+}
+
+// DecodeHeader reads a single block.Header from r.
+func DecodeHeader(r io.Reader, h *block.Header) error {
+	var b [HeaderSize]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return err
+	}
+
+	return UnmarshalHeader(bytes.NewBuffer(b[:]), h)
+}
+
+// EncodeHeaders streams a batch of headers to w, preceded by their count.
+func EncodeHeaders(w io.Writer, headers []*block.Header) error {
+	if len(headers) > MaxHeadersPerBatch {
+		return fmt.Errorf("too many headers in batch (%d)", len(headers))
+	}
+
+	scratch := bytes.NewBuffer(make([]byte, 0, HeaderSize))
+	if err := encoding.WriteVarInt(scratch, uint64(len(headers))); err != nil {
+		return err
+	}
+
+	if _, err := scratch.WriteTo(w); err != nil {
+		return err
+	}
+
+	for _, h := range headers {
+		scratch.Reset()
+		if err := MarshalHeader(scratch, h); err != nil {
+			return err
+		}
+
+		if _, err := scratch.WriteTo(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeHeaders reads a batch of headers from r.
+func DecodeHeaders(r io.Reader) ([]*block.Header, error) {
+	lHeaders, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if lHeaders > MaxHeadersPerBatch {
+		return nil, errors.New("too many headers in batch")
+	}
+
+	headers := make([]*block.Header, lHeaders)
+	for i := range headers {
+		headers[i] = block.NewHeader()
+		if err := DecodeHeader(r, headers[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return headers, nil
+}
+
+// readVarInt is the io.Reader counterpart of encoding.ReadVarInt. It reads
+// only as many bytes as the CompactSize int occupies.
+func readVarInt(r io.Reader) (uint64, error) {
+	var prefix [1]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return 0, err
+	}
+
+	var size int
+	switch prefix[0] {
+	case 0xff:
+		size = 8
+	case 0xfe:
+		size = 4
+	case 0xfd:
+		size = 2
+	default:
+		return uint64(prefix[0]), nil
+	}
+
+	b := make([]byte, 1+size)
+	b[0] = prefix[0]
+	if _, err := io.ReadFull(r, b[1:]); err != nil {
+		return 0, err
+	}
+
+	// Canonical checks are performed by encoding.ReadVarInt
+	return encoding.ReadVarInt(bytes.NewBuffer(b))
+}
@@ -12,6 +12,12 @@ import (
 	"github.com/dusk-network/dusk-wallet/transactions"
 )
 
+// MaxRangeProofSize is the maximum size of an encoded rangeproof. An
+// aggregated proof over 64 outputs, commitments included, takes a little more
+// than 3KB. Larger blobs are refused before being handed to the rangeproof
+// decoder.
+const MaxRangeProofSize = 8 * 1024
+
 func UnmarshalTx(r *bytes.Buffer) (transactions.Transaction, error) {
 	var txType uint8
 	if err := encoding.ReadUint8(r, &txType); err != nil {
@@ -78,7 +84,9 @@ func MarshalStandard(w *bytes.Buffer, s *transactions.Standard) error {
 	return marshalStandard(w, s, true)
 }
 
-func marshalStandard(w *bytes.Buffer, s *transactions.Standard, encodeSignature bool) error {
+// marshalStandard encodes the standard fields of a transaction. If witness is
+// false, the ring signatures and the range proof are left out.
+func marshalStandard(w *bytes.Buffer, s *transactions.Standard, witness bool) error {
 	if err := encoding.WriteUint8(w, uint8(s.TxType)); err != nil {
 		return err
 	}
@@ -96,7 +104,7 @@ func marshalStandard(w *bytes.Buffer, s *transactions.Standard, encodeSignature
 	}
 
 	for _, input := range s.Inputs {
-		if err := MarshalInput(w, input, encodeSignature); err != nil {
+		if err := MarshalInput(w, input, witness); err != nil {
 			return err
 		}
 	}
@@ -114,11 +122,16 @@ func marshalStandard(w *bytes.Buffer, s *transactions.Standard, encodeSignature
 		return err
 	}
 
+	if !witness {
+		return nil
+	}
+
 	// Marshal the rangeproof into it's own buffer and then marshal it as VarBytes.
 	// This is because Rangeproof.Decode uses `buf.ReadFrom`, which can cause issues
 	// when trying to unmarshal a block with multiple txs.
 	// It also ensures backwards-compatibility with the current testnet.
-	buf := new(bytes.Buffer)
+	buf := getScratch()
+	defer putScratch(buf)
 	if err := s.RangeProof.Encode(buf, true); err != nil {
 		return err
 	}
@@ -130,8 +143,8 @@ func MarshalTimelock(r *bytes.Buffer, tx *transactions.Timelock) error {
 	return marshalTimelock(r, tx, true)
 }
 
-func marshalTimelock(r *bytes.Buffer, tx *transactions.Timelock, encodeSignature bool) error {
-	if err := marshalStandard(r, tx.Standard, encodeSignature); err != nil {
+func marshalTimelock(r *bytes.Buffer, tx *transactions.Timelock, witness bool) error {
+	if err := marshalStandard(r, tx.Standard, witness); err != nil {
 		return err
 	}
 
@@ -146,8 +159,8 @@ func MarshalBid(r *bytes.Buffer, tx *transactions.Bid) error {
 	return marshalBid(r, tx, true)
 }
 
-func marshalBid(r *bytes.Buffer, tx *transactions.Bid, encodeSignature bool) error {
-	if err := marshalTimelock(r, tx.Timelock, encodeSignature); err != nil {
+func marshalBid(r *bytes.Buffer, tx *transactions.Bid, witness bool) error {
+	if err := marshalTimelock(r, tx.Timelock, witness); err != nil {
 		return err
 	}
 
@@ -162,8 +175,8 @@ func MarshalStake(r *bytes.Buffer, tx *transactions.Stake) error {
 	return marshalStake(r, tx, true)
 }
 
-func marshalStake(r *bytes.Buffer, tx *transactions.Stake, encodeSignature bool) error {
-	if err := marshalTimelock(r, tx.Timelock, encodeSignature); err != nil {
+func marshalStake(r *bytes.Buffer, tx *transactions.Stake, witness bool) error {
+	if err := marshalTimelock(r, tx.Timelock, witness); err != nil {
 		return err
 	}
 
@@ -263,7 +276,7 @@ func UnmarshalStandard(w *bytes.Buffer, s *transactions.Standard) error {
 	s.Fee.SetBigInt(big.NewInt(0).SetUint64(fee))
 
 	var rangeProofBuf []byte
-	if err := encoding.ReadVarBytes(w, &rangeProofBuf); err != nil {
+	if err := encoding.ReadVarBytesMax(w, &rangeProofBuf, MaxRangeProofSize); err != nil {
 		return err
 	}
 	return s.RangeProof.Decode(bytes.NewBuffer(rangeProofBuf), true)
@@ -418,7 +431,8 @@ func MarshalInput(w *bytes.Buffer, i *transactions.Input, encodeSignature bool)
 	if encodeSignature {
 		// Signature needs to be encoded and decoded as VarBytes, to ensure backwards-compatibility with
 		// the current testnet.
-		buf := new(bytes.Buffer)
+		buf := getScratch()
+		defer putScratch(buf)
 		if err := i.Signature.Encode(buf, true); err != nil {
 			return err
 		}
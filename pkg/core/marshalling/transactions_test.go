@@ -6,6 +6,7 @@ import (
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-wallet/transactions"
 	"github.com/stretchr/testify/assert"
 )
@@ -299,3 +300,56 @@ func TestDecodeTransactions(t *testing.T) {
 		assert.True(t, tx.Equals(decTx))
 	}
 }
+
+// Truncated txs must be refused, rather than decoded from partial data
+func TestDecodeTruncatedStandard(t *testing.T) {
+	tx := helper.RandomStandardTx(t, false)
+	buf := new(bytes.Buffer)
+	assert.NoError(t, marshalling.MarshalTx(buf, tx))
+	encoded := buf.Bytes()
+
+	for i := 0; i < len(encoded); i += 7 {
+		_, err := marshalling.UnmarshalTx(bytes.NewBuffer(encoded[:i]))
+		assert.Error(t, err)
+	}
+}
+
+func TestDecodeOversizedRangeProof(t *testing.T) {
+	tx := helper.RandomStandardTx(t, false)
+	buf := new(bytes.Buffer)
+	assert.NoError(t, marshalling.MarshalTx(buf, tx))
+
+	// Replace the rangeproof, which comes last, with an oversized blob. Its
+	// length is encoded on 3 bytes
+	var rp bytes.Buffer
+	assert.NoError(t, tx.RangeProof.Encode(&rp, true))
+	encoded := buf.Bytes()
+	prefix := encoded[:len(encoded)-rp.Len()-3]
+
+	oversized := bytes.NewBuffer(append([]byte{}, prefix...))
+	assert.NoError(t, encoding.WriteVarBytes(oversized, make([]byte, marshalling.MaxRangeProofSize+1)))
+	_, err := marshalling.UnmarshalTx(oversized)
+	assert.Error(t, err)
+}
+
+// Txs of versions unknown to this node are decoded as they are, so that they
+// can be relayed in blocks once their version activates
+func TestEncodeDecodeUnknownVersion(t *testing.T) {
+
+	assert := assert.New(t)
+
+	tx := helper.RandomStandardTx(t, false)
+	tx.Version = 3
+
+	buf := new(bytes.Buffer)
+	assert.Nil(marshalling.MarshalTx(buf, tx))
+	encoded := append([]byte{}, buf.Bytes()...)
+
+	decTX, err := marshalling.UnmarshalTx(buf)
+	assert.Nil(err)
+	assert.Equal(uint8(3), decTX.StandardTx().Version)
+
+	reencoded := new(bytes.Buffer)
+	assert.Nil(marshalling.MarshalTx(reencoded, decTX))
+	assert.Equal(encoded, reencoded.Bytes())
+}
@@ -0,0 +1,135 @@
+package marshalling
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/crypto/merkle"
+	"github.com/dusk-network/dusk-crypto/hash"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/dusk-network/dusk-wallet/transactions"
+)
+
+// ErrTxNotInBlock is returned when proving the inclusion of a tx which is not
+// part of the block
+var ErrTxNotInBlock = errors.New("transaction not in block")
+
+// TxID returns the canonical identifier of a transaction: the hash of its
+// encoding without the ring signatures and the range proof. As these can be
+// re-serialized by anyone relaying the transaction, they are not allowed to
+// change its identity.
+//
+// TxID is what identifies a transaction in the blockchain database and in
+// the mempool. The merkle tree of a block is built over the WTxIDs, so that
+// the header commits to the witness data as well.
+func TxID(tx transactions.Transaction) ([]byte, error) {
+	buf := getScratch()
+	defer putScratch(buf)
+	if err := marshalTx(buf, tx, false); err != nil {
+		return nil, err
+	}
+
+	return hash.Sha3256(buf.Bytes())
+}
+
+// WTxID returns the hash of the full encoding of a transaction, witness data
+// included. Unlike the TxID, it changes if the signatures or the range proof
+// are re-serialized.
+func WTxID(tx transactions.Transaction) ([]byte, error) {
+	buf := getScratch()
+	defer putScratch(buf)
+	if err := MarshalTx(buf, tx); err != nil {
+		return nil, err
+	}
+
+	return hash.Sha3256(buf.Bytes())
+}
+
+func marshalTx(w *bytes.Buffer, tx transactions.Transaction, witness bool) error {
+	switch tx.Type() {
+	case transactions.StandardType:
+		return marshalStandard(w, tx.(*transactions.Standard), witness)
+	case transactions.TimelockType:
+		return marshalTimelock(w, tx.(*transactions.Timelock), witness)
+	case transactions.BidType:
+		return marshalBid(w, tx.(*transactions.Bid), witness)
+	case transactions.StakeType:
+		return marshalStake(w, tx.(*transactions.Stake), witness)
+	case transactions.CoinbaseType:
+		// The coinbase carries no witness data
+		return MarshalCoinbase(w, tx.(*transactions.Coinbase))
+	default:
+		return fmt.Errorf("unknown transaction type: %d", tx.Type())
+	}
+}
+
+// TxRoot returns the root of the merkle tree built over the WTxIDs of txs.
+// It commits to the full encoding of the txs, so that a block can not be
+// served with the witness data of its txs swapped.
+func TxRoot(txs []transactions.Transaction) ([]byte, error) {
+	_, wtxids, err := txIDs(txs)
+	if err != nil {
+		return nil, err
+	}
+
+	return merkle.Root(wtxids)
+}
+
+// SetTxRoot computes the TxRoot of a block and sets it in its header
+func SetTxRoot(b *block.Block) error {
+	root, err := TxRoot(b.Txs)
+	if err != nil {
+		return err
+	}
+
+	b.Header.TxRoot = root
+	return nil
+}
+
+// MerkleProof returns the proof that the tx identified by txid is part of
+// the block, to be checked against the TxRoot of its header, along with the
+// WTxID of the tx, which is the leaf proven
+func MerkleProof(b *block.Block, txid []byte) (merkle.Proof, []byte, error) {
+	txids, wtxids, err := txIDs(b.Txs)
+	if err != nil {
+		return merkle.Proof{}, nil, err
+	}
+
+	for i, id := range txids {
+		if bytes.Equal(id, txid) {
+			proof, err := merkle.Prove(wtxids, uint32(i))
+			return proof, wtxids[i], err
+		}
+	}
+
+	return merkle.Proof{}, nil, ErrTxNotInBlock
+}
+
+// VerifyMerkleProof returns true if proof proves that the tx with the given
+// WTxID is part of the block with the given TxRoot
+func VerifyMerkleProof(root []byte, proof merkle.Proof, wtxid []byte) bool {
+	return merkle.Verify(root, proof, wtxid)
+}
+
+// txIDs returns the TxIDs and the WTxIDs of txs
+func txIDs(txs []transactions.Transaction) ([][]byte, [][]byte, error) {
+	txids := make([][]byte, len(txs))
+	wtxids := make([][]byte, len(txs))
+	for i, tx := range txs {
+		txid, err := TxID(tx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		wtxid, err := WTxID(tx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		txids[i] = txid
+		wtxids[i] = wtxid
+	}
+
+	return txids, wtxids, nil
+}
@@ -0,0 +1,84 @@
+package marshalling_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+// Swapping the witness data of a tx changes its wtxid, but not its txid
+func TestTxIDMalleability(t *testing.T) {
+
+	assert := assert.New(t)
+
+	tx := helper.RandomStandardTx(t, false)
+	other := helper.RandomStandardTx(t, false)
+
+	txid, err := marshalling.TxID(tx)
+	assert.Nil(err)
+	wtxid, err := marshalling.WTxID(tx)
+	assert.Nil(err)
+	assert.False(bytes.Equal(txid, wtxid))
+
+	tx.Inputs[0].Signature = other.Inputs[0].Signature
+	tx.RangeProof = other.RangeProof
+
+	newTxid, err := marshalling.TxID(tx)
+	assert.Nil(err)
+	assert.True(bytes.Equal(txid, newTxid))
+
+	newWtxid, err := marshalling.WTxID(tx)
+	assert.Nil(err)
+	assert.False(bytes.Equal(wtxid, newWtxid))
+}
+
+func TestTxRoot(t *testing.T) {
+
+	assert := assert.New(t)
+
+	blk := helper.RandomBlock(t, 1, 2)
+	root, err := marshalling.TxRoot(blk.Txs)
+	assert.Nil(err)
+	assert.True(bytes.Equal(root, blk.Header.TxRoot))
+
+	// The root commits to the order of the txs
+	blk.Txs[0], blk.Txs[1] = blk.Txs[1], blk.Txs[0]
+	root, err = marshalling.TxRoot(blk.Txs)
+	assert.Nil(err)
+	assert.False(bytes.Equal(root, blk.Header.TxRoot))
+
+	// And to their witness data
+	blk = helper.RandomBlock(t, 1, 2)
+	tx := helper.RandomStandardTx(t, false)
+	blk.Txs = append(blk.Txs, tx)
+	assert.Nil(marshalling.SetTxRoot(blk))
+	tx.RangeProof = helper.RandomStandardTx(t, false).RangeProof
+	root, err = marshalling.TxRoot(blk.Txs)
+	assert.Nil(err)
+	assert.False(bytes.Equal(root, blk.Header.TxRoot))
+}
+
+func TestMerkleProof(t *testing.T) {
+
+	assert := assert.New(t)
+
+	blk := helper.RandomBlock(t, 1, 2)
+	for _, tx := range blk.Txs {
+		txid, err := marshalling.TxID(tx)
+		assert.Nil(err)
+
+		wtxid, err := marshalling.WTxID(tx)
+		assert.Nil(err)
+
+		proof, leaf, err := marshalling.MerkleProof(blk, txid)
+		assert.Nil(err)
+		assert.Equal(wtxid, leaf)
+		assert.True(marshalling.VerifyMerkleProof(blk.Header.TxRoot, proof, wtxid))
+	}
+
+	_, _, err := marshalling.MerkleProof(blk, make([]byte, 32))
+	assert.Equal(marshalling.ErrTxNotInBlock, err)
+}
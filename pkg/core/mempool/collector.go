@@ -13,12 +13,12 @@ type intermediateBlockCollector struct {
 	blkChan chan<- block.Block
 }
 
-func initIntermediateBlockCollector(sub eventbus.Subscriber) chan block.Block {
+func initIntermediateBlockCollector(sub eventbus.Subscriber) (chan block.Block, uint32) {
 	blkChan := make(chan block.Block, 1)
 	coll := &intermediateBlockCollector{blkChan}
 	l := eventbus.NewCallbackListener(coll.Collect)
-	sub.Subscribe(topics.IntermediateBlock, l)
-	return blkChan
+	id := sub.Subscribe(topics.IntermediateBlock, l)
+	return blkChan, id
 }
 
 func (i *intermediateBlockCollector) Collect(m bytes.Buffer) error {
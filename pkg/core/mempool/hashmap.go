@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-wallet/transactions"
 )
 
@@ -51,7 +52,7 @@ func (m *HashMap) Put(t TxDesc) error {
 	}
 
 	// store tx
-	txID, err := t.tx.CalculateHash()
+	txID, err := marshalling.TxID(t.tx)
 	if err != nil {
 		return err
 	}
@@ -110,6 +111,17 @@ func (m *HashMap) Contains(txID []byte) bool {
 	return ok
 }
 
+// Get returns the tx of the given key, or nil if it is not in the pool.
+func (m *HashMap) Get(txID []byte) transactions.Transaction {
+	var k txHash
+	copy(k[:], txID)
+	t, ok := m.data[k]
+	if !ok {
+		return nil
+	}
+	return t.tx
+}
+
 // Size of the txs
 func (m *HashMap) Size() uint32 {
 	return m.txsSize
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
 	crypto "github.com/dusk-network/dusk-crypto/hash"
 	"github.com/dusk-network/dusk-wallet/transactions"
@@ -184,3 +185,26 @@ func dummyTransactionsSet(size int) []*transactions.Standard {
 
 	return txs
 }
+
+func TestGet(t *testing.T) {
+
+	pool := &HashMap{Capacity: 10}
+
+	tx := helper.RandomStandardTx(t, false)
+	if err := pool.Put(TxDesc{tx: tx}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	txID, err := marshalling.TxID(tx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if found := pool.Get(txID); found == nil || !found.Equals(tx) {
+		t.Fatal("tx not found by its TxID")
+	}
+
+	if pool.Get(make([]byte, 32)) != nil {
+		t.Fatal("unknown TxID found")
+	}
+}
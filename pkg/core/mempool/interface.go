@@ -19,6 +19,8 @@ type TxDesc struct {
 	// the point in time, tx was accepted by this node
 	// accepted time.Time
 	size uint
+	// the address of the peer the tx was received from, if any
+	origin string
 }
 
 // Pool represents a transaction pool of the verified txs only.
@@ -29,6 +31,8 @@ type Pool interface {
 	Put(t TxDesc) error
 	// Contains returns true if the given key is in the pool.
 	Contains(key []byte) bool
+	// Get returns the tx of the given key, or nil if it is not in the pool.
+	Get(key []byte) transactions.Transaction
 	// ContainsKeyImage returns true if txpool includes a input that contains
 	// this keyImage
 	ContainsKeyImage(keyImage []byte) bool
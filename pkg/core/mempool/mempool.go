@@ -6,19 +6,22 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/fees"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/peermsg"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
-	"github.com/dusk-network/dusk-crypto/merkletree"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 	"github.com/dusk-network/dusk-wallet/block"
 	"github.com/dusk-network/dusk-wallet/transactions"
 	logger "github.com/sirupsen/logrus"
@@ -26,9 +29,21 @@ import (
 
 var log = logger.WithFields(logger.Fields{"prefix": "mempool"})
 
+var (
+	txsAccepted = metrics.NewCounter("dusk_mempool_txs_accepted_total", "Txs verified and added to the mempool")
+	txsRejected = metrics.NewCounter("dusk_mempool_txs_rejected_total", "Txs which failed verification, duplicates aside")
+	poolTxs     = metrics.NewGauge("dusk_mempool_txs", "Txs in the mempool")
+	poolBytes   = metrics.NewGauge("dusk_mempool_size_bytes", "Size of the txs in the mempool")
+
+	verifyLatency = metrics.NewHistogram("dusk_mempool_tx_verification_seconds", "Time taken to verify a tx", metrics.LatencyBuckets)
+)
+
 const (
 	consensusSeconds = 20
 	maxPendingLen    = 1000
+	maxLockedLen     = 1000
+	// sendTimeout bounds the time taken to queue a reject for a peer
+	sendTimeout = 5 * time.Second
 )
 
 var (
@@ -38,14 +53,23 @@ var (
 	ErrAlreadyExists = errors.New("already exists")
 	// ErrDoubleSpending transaction uses outputs spent in other mempool txs
 	ErrDoubleSpending = errors.New("double-spending in mempool")
+	// ErrNonStandard transaction version is unknown to this node, or not
+	// active yet
+	ErrNonStandard = errors.New("non-standard transaction version")
+	// ErrTooManyLocked transaction spends locked outputs and no more of
+	// these can be held
+	ErrTooManyLocked = errors.New("too many txs held for locked inputs")
+
+	// errHeld is returned by processTx for txs held until their inputs unlock
+	errHeld = errors.New("held until inputs unlock")
 )
 
 // Mempool is a storage for the chain transactions that are valid according to the
 // current chain state and can be included in the next block.
 type Mempool struct {
-	getMempoolTxsChan       <-chan rpcbus.Request
-	getMempoolTxsBySizeChan <-chan rpcbus.Request
-	sendTxChan              <-chan rpcbus.Request
+	// serializes the main loop and the rpcbus handlers, which run on the
+	// goroutines of the callers
+	lock sync.Mutex
 
 	// transactions emitted by RPC and Peer subsystems
 	// pending to be verified before adding them to verified pool
@@ -54,21 +78,38 @@ type Mempool struct {
 	// verified txs to be included in next block
 	verified Pool
 
+	// txs spending outputs which are still locked. They are verified again
+	// once a new block unlocks outputs, and moved to the verified pool once
+	// spendable
+	locked Pool
+	// tip of the chain when the held txs were last verified, 0 if unknown
+	releasedHeight uint64
+
 	// the collector to listen for new intermediate blocks
 	intermediateBlockChan <-chan block.Block
 
 	// used by tx verification procedure
 	latestBlockTimestamp int64
+	// height of the intermediate block. Txs are relayed only if standard
+	// for the block after it
+	latestBlockHeight uint64
+
+	// median fee rates of the last blocks, served to GetFeeEstimate
+	estimator *fees.Estimator
 
 	eventBus *eventbus.EventBus
+	rpcBus   *rpcbus.RPCBus
 	db       database.DB
 
 	// the magic function that knows best what is valid chain Tx
 	verifyTx func(tx transactions.Transaction) error
 	quitChan chan struct{}
+	stopOnce sync.Once
 
-	// ID of subscription to the TX topic on the EventBus
-	txSubscriberID uint32
+	// IDs of the subscriptions to the Tx and IntermediateBlock topics on
+	// the EventBus
+	txSubscriberID    uint32
+	blockSubscriberID uint32
 }
 
 // checkTx is responsible to determine if a tx is valid or not
@@ -85,7 +126,14 @@ func (m *Mempool) checkTx(tx transactions.Transaction) error {
 
 	// run the default blockchain verifier
 	approxBlockTime := uint64(consensusSeconds) + uint64(m.latestBlockTimestamp)
-	return verifiers.CheckTx(m.db, 0, approxBlockTime, tx)
+	return verifiers.CheckTx(m.db, m.targetHeight(), 0, approxBlockTime, tx)
+}
+
+// targetHeight is the height of the next candidate block, built on top of the
+// intermediate block. The txs are relayed and verified for that height, so
+// that the version and lock time rules activate as they do for the block.
+func (m *Mempool) targetHeight() uint64 {
+	return m.latestBlockHeight + 1
 }
 
 // NewMempool instantiates and initializes node mempool
@@ -93,31 +141,33 @@ func NewMempool(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, verifyTx fun
 
 	log.Infof("Create instance")
 
-	getMempoolTxsChan := make(chan rpcbus.Request, 1)
-	if err := rpcBus.Register(rpcbus.GetMempoolTxs, getMempoolTxsChan); err != nil {
+	intermediateBlockChan, blockSubscriberID := initIntermediateBlockCollector(eventBus)
+
+	m := &Mempool{
+		eventBus:              eventBus,
+		rpcBus:                rpcBus,
+		latestBlockTimestamp:  math.MinInt32,
+		quitChan:              make(chan struct{}),
+		intermediateBlockChan: intermediateBlockChan,
+		blockSubscriberID:     blockSubscriberID,
+		estimator:             fees.NewEstimator(),
+	}
+
+	if err := rpcBus.Register(rpcbus.GetMempoolTxs, m.serialize(m.onGetMempoolTxs, "GetMempoolTxs")); err != nil {
 		log.Errorf("rpcbus.GetMempoolTxs err=%v", err)
 	}
 
-	getMempoolTxsBySizeChan := make(chan rpcbus.Request, 1)
-	if err := rpcBus.Register(rpcbus.GetMempoolTxsBySize, getMempoolTxsBySizeChan); err != nil {
+	if err := rpcBus.Register(rpcbus.GetMempoolTxsBySize, m.serialize(m.onGetMempoolTxsBySize, "GetMempoolTxsBySize")); err != nil {
 		log.Errorf("rpcbus.getMempoolTxsBySize err=%v", err)
 	}
 
-	sendTxChan := make(chan rpcbus.Request, 1)
-	if err := rpcBus.Register(rpcbus.SendMempoolTx, sendTxChan); err != nil {
+	if err := rpcBus.Register(rpcbus.SendMempoolTx, m.serialize(m.onSendMempoolTx, "SendTx")); err != nil {
 		log.Errorf("rpcbus.SendMempoolTx err=%v", err)
 	}
 
-	intermediateBlockChan := initIntermediateBlockCollector(eventBus)
-
-	m := &Mempool{
-		eventBus:                eventBus,
-		latestBlockTimestamp:    math.MinInt32,
-		quitChan:                make(chan struct{}),
-		intermediateBlockChan:   intermediateBlockChan,
-		getMempoolTxsChan:       getMempoolTxsChan,
-		getMempoolTxsBySizeChan: getMempoolTxsBySizeChan,
-		sendTxChan:              sendTxChan,
+	// The estimator has its own lock, and is not serialized with the mempool
+	if err := rpcBus.Register(rpcbus.GetFeeEstimate, m.onGetFeeEstimate); err != nil {
+		log.Errorf("rpcbus.GetFeeEstimate err=%v", err)
 	}
 
 	if verifyTx != nil {
@@ -125,53 +175,78 @@ func NewMempool(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, verifyTx fun
 	}
 
 	m.verified = m.newPool()
+	m.locked = m.newPool()
 
 	log.Infof("Running with pool type %s", config.Get().Mempool.PoolType)
 
 	// topics.Tx will be published by RPC subsystem or Peer subsystem (deserialized from gossip msg)
 	m.pending = make(chan TxDesc, maxPendingLen)
-	l := eventbus.NewCallbackListener(m.CollectPending)
+	l := eventbus.NewTracedCallbackListener(m.CollectPending)
 	m.txSubscriberID = m.eventBus.Subscribe(topics.Tx, l)
 	return m
 }
 
-// Run spawns the mempool lifecycle routine. The whole mempool cycle is around
-// getting input from the outside world (from input channels) and provide the
-// actual list of the verified txs (onto output channel).
-//
-// All operations are always executed in a single go-routine so no
-// protection-by-mutex needed
+// Run spawns the mempool lifecycle routine, Listen.
 func (m *Mempool) Run() {
-	go func() {
-		for {
-			select {
-			//rpcbus methods
-			case r := <-m.sendTxChan:
-				handleRequest(r, m.onSendMempoolTx, "SendTx")
-			case r := <-m.getMempoolTxsChan:
-				handleRequest(r, m.onGetMempoolTxs, "GetMempoolTxs")
-			case r := <-m.getMempoolTxsBySizeChan:
-				handleRequest(r, m.onGetMempoolTxsBySize, "GetMempoolTxsBySize")
-			// Mempool input channels
-			case b := <-m.intermediateBlockChan:
+	go m.Listen()
+}
+
+// Listen runs the mempool lifecycle, until Stop is called. The whole mempool
+// cycle is around getting input from the outside world (from input channels)
+// and provide the actual list of the verified txs (onto output channel).
+//
+// All operations are executed while holding the mempool lock, which is shared
+// with the rpcbus handlers
+func (m *Mempool) Listen() {
+	for {
+		select {
+		// Mempool input channels
+		case b := <-m.intermediateBlockChan:
+			m.locked(func() {
 				m.onIntermediateBlock(b)
-			case tx := <-m.pending:
-				// TODO: the m.pending channel looks a bit wasteful. Consider
-				// removing it and call onPendingTx directly within
-				// CollectPending
-				_, _ = m.onPendingTx(tx)
-			case <-time.After(20 * time.Second):
-				m.onIdle()
-			// Mempool terminating
-			case <-m.quitChan:
-				//m.eventBus.Unsubscribe(topics.Tx, m.txSubscriberID)
-				return
-			}
+			})
+		case tx := <-m.pending:
+			// TODO: the m.pending channel looks a bit wasteful. Consider
+			// removing it and call onPendingTx directly within
+			// CollectPending
+			m.locked(func() {
+				if txid, err := m.onPendingTx(tx); err != nil && err != errHeld {
+					m.rejectTx(txid, tx.origin, err)
+				}
+			})
+		case <-time.After(20 * time.Second):
+			m.locked(m.onIdle)
+		// Mempool terminating
+		case <-m.quitChan:
+			return
 		}
-	}()
+	}
+}
+
+// locked runs fn holding the mempool lock, which is released even if fn
+// panics, so that Listen can be run again
+func (m *Mempool) locked(fn func()) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	fn()
 }
 
-// onPendingTx handles a submitted tx from any source (rpcBus or eventBus)
+// Stop unsubscribes the mempool from the EventBus, and terminates the
+// lifecycle routine. The txs being processed are completed first, as they
+// hold the mempool lock.
+func (m *Mempool) Stop() {
+	m.stopOnce.Do(func() {
+		m.eventBus.Unsubscribe(topics.Tx, m.txSubscriberID)
+		m.eventBus.Unsubscribe(topics.IntermediateBlock, m.blockSubscriberID)
+		close(m.quitChan)
+
+		m.lock.Lock()
+		m.lock.Unlock()
+	})
+}
+
+// onPendingTx handles a submitted tx from any source (rpcBus or eventBus).
+// It returns errHeld for the txs held until their inputs unlock.
 func (m *Mempool) onPendingTx(t TxDesc) ([]byte, error) {
 
 	log.Infof("Pending txs=%d", len(m.pending))
@@ -180,9 +255,12 @@ func (m *Mempool) onPendingTx(t TxDesc) ([]byte, error) {
 	txid, err := m.processTx(t)
 	elapsed := time.Since(start)
 
-	if err != nil {
+	switch {
+	case err == errHeld:
+		log.Infof("Held txid=%s until its inputs unlock", toHex(txid))
+	case err != nil:
 		log.Errorf("Failed txid=%s err='%v' duration=%d μs", toHex(txid), err, elapsed.Microseconds())
-	} else {
+	default:
 		log.Infof("Verified txid=%s duration=%d μs", toHex(txid), elapsed.Microseconds())
 	}
 
@@ -193,7 +271,7 @@ func (m *Mempool) onPendingTx(t TxDesc) ([]byte, error) {
 // into the verified pool
 func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 
-	txid, err := t.tx.CalculateHash()
+	txid, err := marshalling.TxID(t.tx)
 	if err != nil {
 		return txid, fmt.Errorf("hash err: %s", err.Error())
 	}
@@ -205,18 +283,42 @@ func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 		return txid, ErrCoinbaseTxNotAllowed
 	}
 
-	// expect it is not already a verified tx
-	if m.verified.Contains(txid) {
+	// expect it is not already a verified or held tx
+	if m.verified.Contains(txid) || m.locked.Contains(txid) {
 		return txid, ErrAlreadyExists
 	}
 
+	// expect its version to be known and active
+	if !verifiers.IsStandardTxVersion(t.tx.StandardTx().Version, m.targetHeight()) {
+		return txid, ErrNonStandard
+	}
+
+	// expect it to pay at least the minimum relay fee
+	if err := fees.CheckRelayFee(t.tx, int(t.size)); err != nil {
+		return txid, err
+	}
+
 	// expect it is not already spent from mempool verified txs
 	if err := m.checkTXDoubleSpent(t.tx); err != nil {
 		return txid, ErrDoubleSpending
 	}
 
 	// execute tx verification procedure
-	if err := m.checkTx(t.tx); err != nil {
+	start := time.Now()
+	err = m.checkTx(t.tx)
+	verifyLatency.ObserveSince(start)
+	if err != nil {
+		// premature spends are held rather than rejected, as they
+		// become valid once the lock of their inputs expires
+		if err == verifiers.ErrLockedInputs {
+			return txid, m.holdTx(t)
+		}
+
+		var doubleSpend *verifiers.ErrDoubleSpend
+		if errors.As(err, &doubleSpend) {
+			return txid, ErrDoubleSpending
+		}
+
 		return txid, fmt.Errorf("verification: %v", err)
 	}
 
@@ -227,19 +329,164 @@ func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 	if err := m.verified.Put(t); err != nil {
 		return txid, fmt.Errorf("store: %v", err)
 	}
+	txsAccepted.Inc()
+	m.updatePoolMetrics()
 
-	// advertise the hash of the verified tx to the P2P network
+	// advertise the hash of the verified tx to the P2P network. The tx is
+	// valid and stored already, so it is not rejected if this fails
 	if err := m.advertiseTx(txid); err != nil {
 		// TODO: Perform re-advertise procedure
-		return txid, fmt.Errorf("advertise: %v", err)
+		log.Errorf("Advertising txid=%s err='%v'", toHex(txid), err)
 	}
 
 	return txid, nil
 }
 
+// rejectTx informs origin, the peer which sent a transaction, that it failed
+// verification. Duplicates are not reported, as receiving the same tx from
+// multiple peers is part of the normal gossip flow. Nothing is sent for the
+// txs which do not come from a peer.
+func (m *Mempool) rejectTx(txid []byte, origin string, reason error) {
+	if reason == ErrAlreadyExists {
+		return
+	}
+	txsRejected.Inc()
+
+	if origin == "" {
+		return
+	}
+
+	msg := newTxReject(txid, reason)
+	buf := new(bytes.Buffer)
+	if err := msg.Encode(buf); err != nil {
+		log.Errorf("encoding reject err=%v", err)
+		return
+	}
+
+	if err := topics.Prepend(buf, topics.Reject); err != nil {
+		log.Errorf("encoding reject err=%v", err)
+		return
+	}
+
+	req := new(bytes.Buffer)
+	if err := rpcbus.MarshalSendToPeerRequest(req, origin, buf); err != nil {
+		log.Errorf("encoding reject err=%v", err)
+		return
+	}
+
+	if _, err := m.rpcBus.Call(rpcbus.SendToPeer, rpcbus.NewRequest(*req), sendTimeout); err != nil {
+		log.Warnf("sending reject to peer=%s err=%v", origin, err)
+	}
+}
+
+// newTxReject describes why a transaction failed verification
+func newTxReject(txid []byte, reason error) *peermsg.Reject {
+	var code peermsg.RejectCode
+	switch reason {
+	case ErrAlreadyExists:
+		code = peermsg.RejectDuplicate
+	case ErrDoubleSpending:
+		code = peermsg.RejectDoubleSpend
+	case ErrNonStandard:
+		code = peermsg.RejectNonStandard
+	case fees.ErrFeeTooLow:
+		code = peermsg.RejectInsufficientFee
+	default:
+		code = peermsg.RejectInvalid
+	}
+
+	if len(txid) != 32 {
+		txid = nil
+	}
+
+	return peermsg.NewReject(topics.Tx, code, reason.Error(), txid)
+}
+
+// holdTx stores a tx spending locked outputs, until its inputs unlock
+func (m *Mempool) holdTx(t TxDesc) error {
+	if m.locked.Len() >= maxLockedLen {
+		return ErrTooManyLocked
+	}
+
+	if err := m.locked.Put(t); err != nil {
+		return fmt.Errorf("store: %v", err)
+	}
+
+	return errHeld
+}
+
 func (m *Mempool) onIntermediateBlock(b block.Block) {
 	m.latestBlockTimestamp = b.Header.Timestamp
+	m.latestBlockHeight = b.Header.Height
+	if err := m.estimator.AddBlock(b); err != nil {
+		log.Errorf("fee estimation err=%v", err)
+	}
 	m.removeAccepted(b)
+	m.releaseLocked()
+}
+
+// releaseLocked verifies again the held txs, if the last blocks unlocked
+// outputs which may be their inputs. Txs still locked are held for another
+// block, while the ones failing verification for any other reason are
+// dropped.
+func (m *Mempool) releaseLocked() {
+	if m.locked.Len() == 0 {
+		m.releasedHeight = 0
+		return
+	}
+
+	if !m.unlocking() {
+		return
+	}
+
+	held := m.locked
+	m.locked = m.newPool()
+
+	_ = held.Range(func(k txHash, t TxDesc) error {
+		switch _, err := m.processTx(t); err {
+		case nil:
+			log.Infof("Released txid=%s", toHex(k[:]))
+		case errHeld:
+		default:
+			log.Warnf("Dropped held txid=%s err='%v'", toHex(k[:]), err)
+		}
+		return nil
+	})
+}
+
+// unlocking returns whether outputs became spendable since the held txs were
+// last verified. It is assumed so if it is not known when that was, or if the
+// txs are verified by an external verifyTx rather than against the database
+// of the chain.
+func (m *Mempool) unlocking() bool {
+	if m.verifyTx != nil {
+		return true
+	}
+
+	if m.db == nil {
+		_, m.db = heavy.CreateDBConnection()
+	}
+
+	found := m.releasedHeight == 0
+	err := m.db.View(func(t database.Transaction) error {
+		tip, err := t.FetchCurrentHeight()
+		if err != nil {
+			return err
+		}
+
+		for height := m.releasedHeight + 1; height <= tip && !found; height++ {
+			keys, err := t.FetchOutputsUnlockingAt(height)
+			if err != nil {
+				return err
+			}
+			found = len(keys) > 0
+		}
+
+		m.releasedHeight = tip
+		return nil
+	})
+
+	return found || err != nil
 }
 
 // removeAccepted to clean up all txs from the mempool that have been already
@@ -261,42 +508,58 @@ func (m *Mempool) removeAccepted(b block.Block) {
 		return
 	}
 
-	payloads := make([]merkletree.Payload, len(b.Txs))
-	for i, tx := range b.Txs {
-		payloads[i] = tx.(merkletree.Payload)
+	root, err := marshalling.TxRoot(b.Txs)
+	if err != nil {
+		log.Errorf("block %s txroot err=%v", blockHash, err)
+		return
 	}
 
-	tree, err := merkletree.NewTree(payloads)
-
-	if err == nil && tree != nil {
+	if !bytes.Equal(root, b.Header.TxRoot) {
+		log.Errorf("block %s has invalid txroot", blockHash)
+		return
+	}
 
-		if !bytes.Equal(tree.MerkleRoot, b.Header.TxRoot) {
-			log.Errorf("block %s has invalid txroot", blockHash)
+	accepted := make(map[txHash]bool, len(b.Txs))
+	for _, tx := range b.Txs {
+		txid, err := marshalling.TxID(tx)
+		if err != nil {
+			log.Errorf("block %s txid err=%v", blockHash, err)
 			return
 		}
 
-		s := m.newPool()
-		// Check if mempool verified tx is part of merkle tree of this block
-		// if not, then keep it in the mempool for the next block
-		err = m.verified.Range(func(k txHash, t TxDesc) error {
-			if r, _ := tree.VerifyContent(t.tx); !r {
-				if err := s.Put(t); err != nil {
-					return err
-				}
-			}
-			return nil
-		})
+		var k txHash
+		copy(k[:], txid)
+		accepted[k] = true
+	}
 
-		if err != nil {
-			log.Error(err.Error())
+	s := m.newPool()
+	// Check if mempool verified tx is part of this block
+	// if not, then keep it in the mempool for the next block
+	err = m.verified.Range(func(k txHash, t TxDesc) error {
+		if !accepted[k] {
+			if err := s.Put(t); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
 
-		m.verified = s
+	if err != nil {
+		log.Error(err.Error())
 	}
 
+	m.verified = s
+	m.updatePoolMetrics()
+
 	log.Infof("Processing block %s completed", toHex(b.Header.Hash))
 }
 
+// updatePoolMetrics reports the size of the verified pool
+func (m *Mempool) updatePoolMetrics() {
+	poolTxs.Set(float64(m.verified.Len()))
+	poolBytes.Set(float64(m.verified.Size()))
+}
+
 func (m *Mempool) onIdle() {
 
 	// stats to log
@@ -349,47 +612,49 @@ func (m *Mempool) newPool() Pool {
 	return p
 }
 
-// CollectPending process the emitted transactions.
+// CollectPending process the emitted transactions. The correlation ID tells
+// the peer a tx was received from, if any.
 // Fast-processing and simple impl to avoid locking here.
 // NB This is always run in a different than main mempool routine
-func (m *Mempool) CollectPending(message bytes.Buffer) error {
+func (m *Mempool) CollectPending(message bytes.Buffer, id tracing.ID) error {
 	txDesc, err := unmarshalTxDesc(message)
 	if err != nil {
 		return err
 	}
+	txDesc.origin = tracing.Origin(id)
 
 	m.pending <- txDesc
 	return nil
 }
 
 // onGetMempoolTxs retrieves current state of the mempool of the verified but
-// still unaccepted txs.
+// still unaccepted txs. If TxIDs are requested, only their txs are returned,
+// as found in the pool.
 // Called by P2P on InvTypeMempoolTx msg
-func (m Mempool) onGetMempoolTxs(r rpcbus.Request) (bytes.Buffer, error) {
+func (m *Mempool) onGetMempoolTxs(r rpcbus.Request) (bytes.Buffer, error) {
 
 	// Read inputs
-	filterTxID := r.Params.Bytes()
+	filterTxIDs := r.Params.Bytes()
 
 	outputTxs := make([]transactions.Transaction, 0)
 
-	// When filterTxID is empty, mempool returns all verified txs sorted
+	// When filterTxIDs is empty, mempool returns all verified txs sorted
 	// by fee from highest to lowest
-	err := m.verified.RangeSort(func(k txHash, t TxDesc) (bool, error) {
-		if len(filterTxID) > 0 {
-			if bytes.Equal(filterTxID, k[:]) {
-				// tx found
-				outputTxs = append(outputTxs, t.tx)
-				return true, nil
-			}
-		} else {
+	if len(filterTxIDs) == 0 {
+		err := m.verified.RangeSort(func(k txHash, t TxDesc) (bool, error) {
 			outputTxs = append(outputTxs, t.tx)
-		}
-
-		return false, nil
-	})
+			return false, nil
+		})
 
-	if err != nil {
-		return bytes.Buffer{}, err
+		if err != nil {
+			return bytes.Buffer{}, err
+		}
+	} else if len(filterTxIDs)%len(txHash{}) == 0 {
+		for i := 0; i < len(filterTxIDs); i += len(txHash{}) {
+			if tx := m.verified.Get(filterTxIDs[i : i+len(txHash{})]); tx != nil {
+				outputTxs = append(outputTxs, tx)
+			}
+		}
 	}
 
 	// marshal Txs
@@ -412,7 +677,7 @@ func (m Mempool) onGetMempoolTxs(r rpcbus.Request) (bytes.Buffer, error) {
 // 1. contains only highest fee txs
 // 2. has total txs size not bigger than maxTxsSize (request param)
 // Called by BlockGenerator on generating a new candidate block
-func (m Mempool) onGetMempoolTxsBySize(r rpcbus.Request) (bytes.Buffer, error) {
+func (m *Mempool) onGetMempoolTxsBySize(r rpcbus.Request) (bytes.Buffer, error) {
 
 	// Read maxTxsSize param
 	var maxTxsSize uint32
@@ -457,12 +722,38 @@ func (m Mempool) onGetMempoolTxsBySize(r rpcbus.Request) (bytes.Buffer, error) {
 	return *w, nil
 }
 
+// onGetFeeEstimate returns the fee rate estimated for a tx to be accepted
+// within the requested amount of blocks (uint64 LE), followed by the amount
+// of blocks the estimation is based on (uint32 LE)
+func (m *Mempool) onGetFeeEstimate(r rpcbus.Request) (bytes.Buffer, error) {
+	var targetBlocks uint64
+	if err := encoding.ReadUint64LE(&r.Params, &targetBlocks); err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	rate, blocks := m.estimator.Estimate(targetBlocks)
+
+	w := new(bytes.Buffer)
+	if err := encoding.WriteUint64LE(w, rate); err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	if err := encoding.WriteUint32LE(w, uint32(blocks)); err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	return *w, nil
+}
+
 // onSendMempoolTx utilizes rpcbus to allow submitting a tx to mempool with
-func (m Mempool) onSendMempoolTx(r rpcbus.Request) (bytes.Buffer, error) {
+// synchronous verification. The TxID is returned, followed by
+// rpcbus.TxAccepted or rpcbus.TxHeld. Refused txs are reported with a
+// *peermsg.Reject error, carrying the reason of the rejection.
+func (m *Mempool) onSendMempoolTx(r rpcbus.Request) (bytes.Buffer, error) {
 
 	txDesc, err := unmarshalTxDesc(r.Params)
 	if err != nil {
-		return bytes.Buffer{}, err
+		return bytes.Buffer{}, peermsg.NewReject(topics.Tx, peermsg.RejectMalformed, err.Error(), nil)
 	}
 
 	// Process request
@@ -471,7 +762,16 @@ func (m Mempool) onSendMempoolTx(r rpcbus.Request) (bytes.Buffer, error) {
 	result := bytes.Buffer{}
 	result.Write(txid)
 
-	return result, err
+	switch err {
+	case nil:
+		result.WriteByte(rpcbus.TxAccepted)
+	case errHeld:
+		result.WriteByte(rpcbus.TxHeld)
+	default:
+		return result, newTxReject(txid, err)
+	}
+
+	return result, nil
 }
 
 // checkTXDoubleSpent differs from verifiers.checkTXDoubleSpent as it executes on
@@ -532,18 +832,22 @@ func unmarshalTxDesc(message bytes.Buffer) (TxDesc, error) {
 	return TxDesc{tx: tx, received: time.Now(), size: uint(txSize)}, nil
 }
 
-func handleRequest(r rpcbus.Request, handler func(r rpcbus.Request) (bytes.Buffer, error), name string) {
-
-	log.Tracef("Handling %s request", name)
+// serialize wraps a rpcbus handler, so that it never runs concurrently with
+// the main loop
+func (m *Mempool) serialize(handler rpcbus.HandlerFunc, name string) rpcbus.HandlerFunc {
+	return func(r rpcbus.Request) (bytes.Buffer, error) {
+		m.lock.Lock()
+		defer m.lock.Unlock()
 
-	result, err := handler(r)
-	if err != nil {
-		log.Errorf("Failed %s request: %v", name, err)
-		r.RespChan <- rpcbus.Response{Err: err}
-		return
-	}
+		log.Tracef("Handling %s request", name)
 
-	r.RespChan <- rpcbus.Response{Resp: result, Err: nil}
+		result, err := handler(r)
+		if err != nil {
+			log.Errorf("Failed %s request: %v", name, err)
+			return bytes.Buffer{}, err
+		}
 
-	log.Tracef("Handled %s request", name)
+		log.Tracef("Handled %s request", name)
+		return result, nil
+	}
 }
@@ -14,10 +14,13 @@ import (
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/peermsg"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/block"
 	"github.com/dusk-network/dusk-wallet/transactions"
 	"github.com/stretchr/testify/assert"
 )
@@ -54,6 +57,7 @@ func (c *ctx) reset() {
 	// Reset shared context state
 	c.m.Quit()
 	c.m.verified = c.m.newPool()
+	c.m.locked = c.m.newPool()
 	c.verifiedTx = make([]transactions.Transaction, 0)
 	c.propagated = make([][]byte, 0)
 
@@ -301,7 +305,7 @@ func TestRemoveAccepted(t *testing.T) {
 
 	c.wait()
 
-	_ = b.SetRoot()
+	_ = marshalling.SetTxRoot(b)
 	buf := new(bytes.Buffer)
 	_ = marshalling.MarshalBlock(buf, b)
 
@@ -411,10 +415,14 @@ func TestSendMempoolTx(t *testing.T) {
 			t.Fatal(err.Error())
 		}
 
-		txid, _ := tx.CalculateHash()
-		if !bytes.Equal(txidBytes.Bytes(), txid) {
+		txid, _ := marshalling.TxID(tx)
+		if !bytes.Equal(txidBytes.Next(32), txid) {
 			t.Fatal("unexpected txid retrieved")
 		}
+
+		if status, _ := txidBytes.ReadByte(); status != rpcbus.TxAccepted {
+			t.Fatal("unexpected tx status")
+		}
 	}
 
 	if c.m.verified.Size() != totalSize {
@@ -423,6 +431,88 @@ func TestSendMempoolTx(t *testing.T) {
 
 }
 
+// Ensure submitters are told why a tx was refused
+func TestSendMempoolTxRejected(t *testing.T) {
+
+	c.reset()
+
+	tx := helper.RandomStandardTx(t, false)
+	buf := new(bytes.Buffer)
+	if err := marshalling.MarshalTx(buf, tx); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	if _, err := c.rpcBus.Call(rpcbus.SendMempoolTx, rpcbus.NewRequest(*bytes.NewBuffer(encoded)), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Submitting the same tx twice
+	_, err := c.rpcBus.Call(rpcbus.SendMempoolTx, rpcbus.NewRequest(*bytes.NewBuffer(encoded)), 0)
+	reject, ok := err.(*peermsg.Reject)
+	if assert.True(t, ok) {
+		assert.Equal(t, peermsg.RejectDuplicate, reject.Code)
+		assert.Equal(t, ErrAlreadyExists.Error(), reject.Reason)
+	}
+
+	// Submitting garbage
+	_, err = c.rpcBus.Call(rpcbus.SendMempoolTx, rpcbus.NewRequest(*bytes.NewBuffer([]byte{1, 2, 3})), 0)
+	reject, ok = err.(*peermsg.Reject)
+	if assert.True(t, ok) {
+		assert.Equal(t, peermsg.RejectMalformed, reject.Code)
+	}
+}
+
+// Ensure txs spending locked outputs are held until a block unlocks them
+func TestHoldLockedTx(t *testing.T) {
+
+	c.reset()
+
+	locked := true
+	c.m.lock.Lock()
+	c.m.verifyTx = func(tx transactions.Transaction) error {
+		if locked {
+			return verifiers.ErrLockedInputs
+		}
+		return nil
+	}
+	c.m.lock.Unlock()
+
+	defer func() {
+		c.m.lock.Lock()
+		c.m.verifyTx = verifyFunc
+		c.m.lock.Unlock()
+	}()
+
+	tx := helper.RandomStandardTx(t, false)
+	buf := new(bytes.Buffer)
+	if err := marshalling.MarshalTx(buf, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Premature spends are not rejected, but reported as held
+	resp, err := c.rpcBus.Call(rpcbus.SendMempoolTx, rpcbus.NewRequest(*buf), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, rpcbus.TxHeld, resp.Bytes()[resp.Len()-1])
+
+	c.m.lock.Lock()
+	defer c.m.lock.Unlock()
+	assert.Equal(t, 0, c.m.verified.Len())
+	assert.Equal(t, 1, c.m.locked.Len())
+
+	// A block which does not unlock the inputs keeps the tx on hold
+	c.m.onIntermediateBlock(*block.NewBlock())
+	assert.Equal(t, 0, c.m.verified.Len())
+	assert.Equal(t, 1, c.m.locked.Len())
+
+	locked = false
+	c.m.onIntermediateBlock(*block.NewBlock())
+	assert.Equal(t, 1, c.m.verified.Len())
+	assert.Equal(t, 0, c.m.locked.Len())
+}
+
 // Only difference with helper.RandomSliceOfTxs is lack of appending a coinbase tx
 func randomSliceOfTxs(t *testing.T, txsBatchCount uint16) []transactions.Transaction {
 	var txs []transactions.Transaction
@@ -444,3 +534,27 @@ func randomSliceOfTxs(t *testing.T, txsBatchCount uint16) []transactions.Transac
 
 	return txs
 }
+
+// Ensure txs paying less than the minimum relay fee are rejected
+func TestMinRelayFee(t *testing.T) {
+
+	c.reset()
+
+	prev := config.Get()
+	defer config.Mock(&prev)
+	r := prev
+	r.Mempool.MinRelayFee = math.MaxUint64
+	config.Mock(&r)
+
+	tx := helper.RandomStandardTx(t, false)
+	buf := new(bytes.Buffer)
+	if err := marshalling.MarshalTx(buf, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.rpcBus.Call(rpcbus.SendMempoolTx, rpcbus.NewRequest(*buf), 0)
+	reject, ok := err.(*peermsg.Reject)
+	if assert.True(t, ok) {
+		assert.Equal(t, peermsg.RejectInsufficientFee, reject.Code)
+	}
+}
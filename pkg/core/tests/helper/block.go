@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-wallet/block"
 	"github.com/stretchr/testify/assert"
 )
@@ -11,20 +12,20 @@ import (
 // RandomBlock returns a random block for testing.
 // For `height` see also helper.RandomHeader
 // For txBatchCount see also helper.RandomSliceOfTxs
-func RandomBlock(t *testing.T, height uint64, txBatchCount uint16) *block.Block {
+func RandomBlock(t testing.TB, height uint64, txBatchCount uint16) *block.Block {
 	b := &block.Block{
 		Header: RandomHeader(t, height),
 		Txs:    RandomSliceOfTxs(t, txBatchCount),
 	}
 	err := b.SetHash()
 	assert.NoError(t, err)
-	err = b.SetRoot()
+	err = marshalling.SetTxRoot(b)
 	assert.NoError(t, err)
 	return b
 }
 
 // TwoLinkedBlocks returns two blocks that are linked via their headers
-func TwoLinkedBlocks(t *testing.T) (*block.Block, *block.Block) {
+func TwoLinkedBlocks(t testing.TB) (*block.Block, *block.Block) {
 	blk0 := &block.Block{
 		Header: RandomHeader(t, 200),
 		Txs:    RandomSliceOfTxs(t, 20),
@@ -40,7 +41,7 @@ func TwoLinkedBlocks(t *testing.T) (*block.Block, *block.Block) {
 	blk1.Header.PrevBlockHash = blk0.Header.Hash
 	blk1.Header.Height = blk0.Header.Height + 1
 	blk1.Header.Timestamp = blk0.Header.Timestamp + 100
-	err = blk1.SetRoot()
+	err = marshalling.SetTxRoot(blk1)
 	assert.Nil(t, err)
 	err = blk1.SetHash()
 	assert.Nil(t, err)
@@ -49,13 +50,13 @@ func TwoLinkedBlocks(t *testing.T) (*block.Block, *block.Block) {
 }
 
 // RandomCertificate returns a random block certificate for testing
-func RandomCertificate(t *testing.T) *block.Certificate {
+func RandomCertificate(t testing.TB) *block.Certificate {
 	return block.EmptyCertificate()
 }
 
 // RandomHeader returns a random header for testing. `height` randomness is up
 // to the caller. A global atomic counter per pkg can handle it
-func RandomHeader(t *testing.T, height uint64) *block.Header {
+func RandomHeader(t testing.TB, height uint64) *block.Header {
 
 	h := &block.Header{
 		Version:   0,
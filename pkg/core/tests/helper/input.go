@@ -14,7 +14,7 @@ import (
 const respAmount uint32 = 7
 
 // RandomInput returns a random input for testing
-func RandomInput(t *testing.T) *transactions.Input {
+func RandomInput(t testing.TB) *transactions.Input {
 	amount := ristretto.Scalar{}
 	amount.Rand()
 	privKey := ristretto.Scalar{}
@@ -35,7 +35,7 @@ func RandomInput(t *testing.T) *transactions.Input {
 }
 
 // RandomInputs returns a slice of inputs of size `size` for testing
-func RandomInputs(t *testing.T, size int) transactions.Inputs {
+func RandomInputs(t testing.TB, size int) transactions.Inputs {
 	var ins transactions.Inputs
 
 	for i := 0; i < size; i++ {
@@ -47,7 +47,7 @@ func RandomInputs(t *testing.T, size int) transactions.Inputs {
 	return ins
 }
 
-func randomSignatureBuffer(t *testing.T) *bytes.Buffer {
+func randomSignatureBuffer(t testing.TB) *bytes.Buffer {
 	buf := new(bytes.Buffer)
 	// c
 	c := ristretto.Scalar{}
@@ -82,7 +82,7 @@ func randomSignatureBuffer(t *testing.T) *bytes.Buffer {
 	return buf
 }
 
-func fixedSignatureBuffer(t *testing.T) *bytes.Buffer {
+func fixedSignatureBuffer(t testing.TB) *bytes.Buffer {
 	buf := new(bytes.Buffer)
 	c := ristretto.Scalar{}
 	c.SetOne()
@@ -10,7 +10,7 @@ import (
 )
 
 // RandomOutput returns a random output for testing
-func RandomOutput(t *testing.T) *transactions.Output {
+func RandomOutput(t testing.TB) *transactions.Output {
 	seed := RandomSlice(t, 128)
 	keyPair := key.NewKeyPair(seed)
 
@@ -31,7 +31,7 @@ func RandomOutput(t *testing.T) *transactions.Output {
 }
 
 // RandomOutputs returns a slice of random outputs for testing
-func RandomOutputs(t *testing.T, size int) transactions.Outputs {
+func RandomOutputs(t testing.TB, size int) transactions.Outputs {
 
 	var outs transactions.Outputs
 
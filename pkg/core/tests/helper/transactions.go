@@ -25,7 +25,7 @@ var numInputs, numOutputs = 23, 16
 
 // RandomSliceOfTxs returns a random slice of transactions for testing
 // Each tx batch represents all 4 non-coinbase tx types
-func RandomSliceOfTxs(t *testing.T, txsBatchCount uint16) []transactions.Transaction {
+func RandomSliceOfTxs(t testing.TB, txsBatchCount uint16) []transactions.Transaction {
 	var txs []transactions.Transaction
 
 	txs = append(txs, RandomCoinBaseTx(t, false))
@@ -49,7 +49,7 @@ func RandomSliceOfTxs(t *testing.T, txsBatchCount uint16) []transactions.Transac
 }
 
 // RandomBidTx returns a random bid transaction for testing
-func RandomBidTx(t *testing.T, malformed bool) (*transactions.Bid, error) {
+func RandomBidTx(t testing.TB, malformed bool) (*transactions.Bid, error) {
 	var M = RandomSlice(t, 32)
 
 	if malformed {
@@ -73,7 +73,7 @@ func RandomBidTx(t *testing.T, malformed bool) (*transactions.Bid, error) {
 }
 
 // RandomCoinBaseTx returns a random coinbase transaction for testing
-func RandomCoinBaseTx(t *testing.T, malformed bool) *transactions.Coinbase {
+func RandomCoinBaseTx(t testing.TB, malformed bool) *transactions.Coinbase {
 	proof := RandomSlice(t, 2000)
 	score := RandomSlice(t, 32)
 
@@ -84,7 +84,7 @@ func RandomCoinBaseTx(t *testing.T, malformed bool) *transactions.Coinbase {
 }
 
 // RandomTLockTx returns a random timelock transaction for testing
-func RandomTLockTx(t *testing.T, malformed bool) *transactions.Timelock {
+func RandomTLockTx(t testing.TB, malformed bool) *transactions.Timelock {
 	tx, err := transactions.NewTimelock(0, 2, fee, lockTime)
 	if err != nil {
 		t.Fatal(err)
@@ -102,7 +102,7 @@ func RandomTLockTx(t *testing.T, malformed bool) *transactions.Timelock {
 }
 
 // RandomStandardTx returns a random standard tx for testing
-func RandomStandardTx(t *testing.T, malformed bool) *transactions.Standard {
+func RandomStandardTx(t testing.TB, malformed bool) *transactions.Standard {
 	tx, err := transactions.NewStandard(0, 2, fee)
 	if err != nil {
 		t.Fatal(err)
@@ -122,7 +122,7 @@ func RandomStandardTx(t *testing.T, malformed bool) *transactions.Standard {
 }
 
 // RandomStakeTx returns a random stake tx for testing
-func RandomStakeTx(t *testing.T, malformed bool) (*transactions.Stake, error) {
+func RandomStakeTx(t testing.TB, malformed bool) (*transactions.Stake, error) {
 	edKey := RandomSlice(t, 32)
 	blsKey := RandomSlice(t, 33)
 
@@ -165,7 +165,7 @@ func fetchDecoys(numMixins int) []mlsag.PubKeys {
 	return pubKeys
 }
 
-func randomRangeProofBuffer(t *testing.T) *bytes.Buffer {
+func randomRangeProofBuffer(t testing.TB) *bytes.Buffer {
 	lenComm := uint32(1)
 	commBytes := make([]byte, 4)
 	binary.BigEndian.PutUint32(commBytes, lenComm)
@@ -190,7 +190,7 @@ func randomRangeProofBuffer(t *testing.T) *bytes.Buffer {
 	return buf
 }
 
-func writeRandomIPProof(t *testing.T, w io.Writer) {
+func writeRandomIPProof(t testing.TB, w io.Writer) {
 	// Add scalars
 	for i := 0; i < 2; i++ {
 		writeRandomScalar(t, w)
@@ -202,7 +202,7 @@ func writeRandomIPProof(t *testing.T, w io.Writer) {
 	}
 }
 
-func writeRandomScalar(t *testing.T, w io.Writer) {
+func writeRandomScalar(t testing.TB, w io.Writer) {
 	s := ristretto.Scalar{}
 	s.Rand()
 	if _, err := w.Write(s.Bytes()); err != nil {
@@ -210,7 +210,7 @@ func writeRandomScalar(t *testing.T, w io.Writer) {
 	}
 }
 
-func writeRandomPoint(t *testing.T, w io.Writer) {
+func writeRandomPoint(t testing.TB, w io.Writer) {
 	p := ristretto.Point{}
 	p.Rand()
 	if _, err := w.Write(p.Bytes()); err != nil {
@@ -218,7 +218,7 @@ func writeRandomPoint(t *testing.T, w io.Writer) {
 	}
 }
 
-func fixedRangeProof(t *testing.T) rangeproof.Proof {
+func fixedRangeProof(t testing.TB) rangeproof.Proof {
 	lenComm := uint32(1)
 	commBytes := make([]byte, 4)
 	binary.BigEndian.PutUint32(commBytes, lenComm)
@@ -265,7 +265,7 @@ func fixedRangeProof(t *testing.T) rangeproof.Proof {
 // FixedStandardTx generates an encodable standard Tx with 1 input and 1 output
 // It guarantees that for one seed the same standard Tx (incl. TxID) is
 // always generated.
-func FixedStandardTx(t *testing.T, seed uint64) transactions.Transaction {
+func FixedStandardTx(t testing.TB, seed uint64) transactions.Transaction {
 
 	seedScalar := ristretto.Scalar{}
 	seedScalar.SetBigInt(big.NewInt(0).SetUint64(uint64(seed)))
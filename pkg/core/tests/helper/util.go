@@ -19,7 +19,7 @@ import (
 )
 
 // TxsToBuffer converts a slice of transactions to a bytes.Buffer.
-func TxsToBuffer(t *testing.T, txs []transactions.Transaction) *bytes.Buffer {
+func TxsToBuffer(t testing.TB, txs []transactions.Transaction) *bytes.Buffer {
 	buf := new(bytes.Buffer)
 
 	for _, tx := range txs {
@@ -33,7 +33,7 @@ func TxsToBuffer(t *testing.T, txs []transactions.Transaction) *bytes.Buffer {
 }
 
 // RandomSlice returns a random slice of size `size`
-func RandomSlice(t *testing.T, size uint32) []byte {
+func RandomSlice(t testing.TB, size uint32) []byte {
 	randSlice := make([]byte, size)
 	_, err := rand.Read(randSlice)
 	assert.Nil(t, err)
@@ -0,0 +1,180 @@
+package transactor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	ristretto "github.com/bwesterb/go-ristretto"
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/fees"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-wallet/key"
+	"github.com/dusk-network/dusk-wallet/transactions"
+	"github.com/dusk-network/dusk-wallet/wallet"
+)
+
+// maxFeeRounds bounds the attempts made to converge on the fee of a
+// transaction. The fee changes the inputs selected by the wallet, and thus
+// the size of the transaction.
+const maxFeeRounds = 4
+
+// FeeRule computes the fee of a transaction from its size.
+type FeeRule struct {
+	// Base is paid by every transaction
+	Base uint64
+	// PerByte is paid for every byte of the encoded transaction
+	PerByte uint64
+	// MinRate is the minimum fee rate relayed by the mempool
+	MinRate uint64
+}
+
+// Fee returns the fee of a transaction of size bytes. It is never lower than
+// the minimum fee accepted by the mempool, nor than the fee paying its
+// minimum relay fee rate.
+func (r FeeRule) Fee(size int) int64 {
+	fee := r.Base + r.PerByte*uint64(size)
+	if relay := r.MinRate * uint64(size); fee < relay {
+		fee = relay
+	}
+
+	if int64(fee) < cfg.MinFee {
+		return cfg.MinFee
+	}
+
+	return int64(fee)
+}
+
+// feeRuleFromConfig returns the fee rule set in the wallet configuration,
+// bounded by the minimum relay fee of the mempool
+func feeRuleFromConfig() FeeRule {
+	return FeeRule{
+		Base:    cfg.Get().Wallet.FeeBase,
+		PerByte: cfg.Get().Wallet.FeePerByte,
+		MinRate: fees.MinRelayFee(),
+	}
+}
+
+type txKind uint8
+
+const (
+	standardKind txKind = iota
+	stakeKind
+	bidKind
+)
+
+type builderOutput struct {
+	address string
+	amount  uint64
+}
+
+// Builder assembles a Standard, Stake or Bid transaction, and pays the fee
+// required by its FeeRule. Inputs and change outputs are selected by the
+// wallet when signing.
+type Builder struct {
+	w    *wallet.Wallet
+	rule FeeRule
+
+	kind     txKind
+	outputs  []builderOutput
+	amount   uint64
+	lockTime uint64
+}
+
+// NewBuilder returns a Builder of Standard transactions, spending the funds
+// of w.
+func NewBuilder(w *wallet.Wallet, rule FeeRule) *Builder {
+	return &Builder{w: w, rule: rule}
+}
+
+// AddOutput sends amount to address. It is only valid for Standard
+// transactions.
+func (b *Builder) AddOutput(address string, amount uint64) *Builder {
+	b.outputs = append(b.outputs, builderOutput{address, amount})
+	return b
+}
+
+// Stake turns the transaction into a Stake of amount, locked for lockTime
+// blocks.
+func (b *Builder) Stake(amount, lockTime uint64) *Builder {
+	b.kind, b.amount, b.lockTime = stakeKind, amount, lockTime
+	return b
+}
+
+// Bid turns the transaction into a Bid of amount, locked for lockTime
+// blocks.
+func (b *Builder) Bid(amount, lockTime uint64) *Builder {
+	b.kind, b.amount, b.lockTime = bidKind, amount, lockTime
+	return b
+}
+
+// Build returns the signed transaction. It is signed once with the fee of an
+// empty transaction, then signed again with the fee matching its size, until
+// the fee paid covers the size.
+func (b *Builder) Build() (transactions.Transaction, error) {
+	if b.kind == standardKind && len(b.outputs) == 0 {
+		return nil, errors.New("transaction has no output")
+	}
+
+	if b.kind != standardKind && len(b.outputs) > 0 {
+		return nil, errors.New("outputs can only be added to standard transactions")
+	}
+
+	fee := b.rule.Fee(0)
+	for i := 0; i < maxFeeRounds; i++ {
+		tx, err := b.sign(fee)
+		if err != nil {
+			return nil, err
+		}
+
+		buf := new(bytes.Buffer)
+		if err := marshalling.MarshalTx(buf, tx); err != nil {
+			return nil, err
+		}
+
+		required := b.rule.Fee(buf.Len())
+		if fee >= required {
+			return tx, nil
+		}
+		fee = required
+	}
+
+	return nil, fmt.Errorf("fee did not converge after %d rounds", maxFeeRounds)
+}
+
+// sign creates and signs the transaction, paying fee
+func (b *Builder) sign(fee int64) (transactions.Transaction, error) {
+	switch b.kind {
+	case stakeKind:
+		tx, err := b.w.NewStakeTx(fee, b.lockTime, toScalar(b.amount))
+		if err != nil {
+			return nil, err
+		}
+		return tx, b.w.Sign(tx)
+	case bidKind:
+		tx, err := b.w.NewBidTx(fee, b.lockTime, toScalar(b.amount))
+		if err != nil {
+			return nil, err
+		}
+		return tx, b.w.Sign(tx)
+	default:
+		tx, err := b.w.NewStandardTx(fee)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range b.outputs {
+			if err := tx.AddOutput(key.PublicAddress(o.address), toScalar(o.amount)); err != nil {
+				return nil, err
+			}
+		}
+		return tx, b.w.Sign(tx)
+	}
+}
+
+func toScalar(amount uint64) ristretto.Scalar {
+	s := ristretto.Scalar{}
+	s.SetBigInt(big.NewInt(0).SetUint64(amount))
+	return s
+}
@@ -0,0 +1,28 @@
+package transactor
+
+import (
+	"testing"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeRule(t *testing.T) {
+	// The mempool minimum applies by default
+	assert.Equal(t, cfg.MinFee, FeeRule{}.Fee(1000))
+
+	rule := FeeRule{Base: 100, PerByte: 2}
+	assert.Equal(t, int64(2100), rule.Fee(1000))
+
+	// The minimum relay fee rate wins over a lower rule
+	rule.MinRate = 5
+	assert.Equal(t, int64(5000), rule.Fee(1000))
+}
+
+func TestBuilderRejectsInvalidTxs(t *testing.T) {
+	_, err := NewBuilder(nil, FeeRule{}).Build()
+	assert.Error(t, err)
+
+	_, err = NewBuilder(nil, FeeRule{}).Stake(100, 10).AddOutput("address", 10).Build()
+	assert.Error(t, err)
+}
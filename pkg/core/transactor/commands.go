@@ -7,16 +7,12 @@ import (
 	"fmt"
 	"time"
 
-	"math/big"
-
-	ristretto "github.com/bwesterb/go-ristretto"
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	walletdb "github.com/dusk-network/dusk-wallet/database"
-	"github.com/dusk-network/dusk-wallet/key"
 	"github.com/dusk-network/dusk-wallet/transactions"
 	"github.com/dusk-network/dusk-wallet/wallet"
 )
@@ -100,72 +96,15 @@ func (t *Transactor) createFromSeed(seed string, password string) (string, error
 }
 
 func (t *Transactor) CreateStandardTx(amount uint64, address string) (transactions.Transaction, error) {
-
-	// Create a new standard tx
-	// TODO: customizable fee
-	tx, err := t.w.NewStandardTx(cfg.MinFee)
-	if err != nil {
-		return nil, err
-	}
-
-	// Turn amount into a scalar
-	amountScalar := ristretto.Scalar{}
-	amountScalar.SetBigInt(big.NewInt(0).SetUint64(amount))
-
-	// Send amount to address
-	if err := tx.AddOutput(key.PublicAddress(address), amountScalar); err != nil {
-		return nil, err
-	}
-
-	// Sign tx
-	err = t.w.Sign(tx)
-	if err != nil {
-		return nil, err
-	}
-
-	return tx, nil
+	return NewBuilder(t.w, feeRuleFromConfig()).AddOutput(address, amount).Build()
 }
 
 func (t *Transactor) CreateStakeTx(amount, lockTime uint64) (transactions.Transaction, error) {
-
-	// Turn amount into a scalar
-	amountScalar := ristretto.Scalar{}
-	amountScalar.SetBigInt(big.NewInt(0).SetUint64(amount))
-
-	// Create a new stake tx
-	tx, err := t.w.NewStakeTx(cfg.MinFee, lockTime, amountScalar)
-	if err != nil {
-		return nil, err
-	}
-
-	// Sign tx
-	err = t.w.Sign(tx)
-	if err != nil {
-		return nil, err
-	}
-
-	return tx, nil
+	return NewBuilder(t.w, feeRuleFromConfig()).Stake(amount, lockTime).Build()
 }
 
 func (t *Transactor) CreateBidTx(amount, lockTime uint64) (transactions.Transaction, error) {
-
-	// Turn amount into a scalar
-	amountScalar := ristretto.Scalar{}
-	amountScalar.SetBigInt(big.NewInt(0).SetUint64(amount))
-
-	// Create a new bid tx
-	tx, err := t.w.NewBidTx(cfg.MinFee, lockTime, amountScalar)
-	if err != nil {
-		return nil, err
-	}
-
-	// Sign tx
-	err = t.w.Sign(tx)
-	if err != nil {
-		return nil, err
-	}
-
-	return tx, nil
+	return NewBuilder(t.w, feeRuleFromConfig()).Bid(amount, lockTime).Build()
 }
 
 func (t *Transactor) syncWallet() error {
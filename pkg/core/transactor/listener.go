@@ -28,43 +28,91 @@ var (
 	errWalletAlreadyLoaded = errors.New("wallet is already loaded")
 )
 
+// Listen serves the requests of the rpcbus until Close is called, or the
+// rpcbus is closed. Either way, the keys of the wallet are wiped on return.
 func (t *Transactor) Listen() {
+	defer close(t.done)
+	defer t.wipeKeys()
 	for {
 		select {
 
 		// Wallet requests to respond to
-		case r := <-t.createWalletChan:
+		case r, ok := <-t.createWalletChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleCreateWallet, "CreateWallet")
-		case r := <-t.createFromSeedChan:
+		case r, ok := <-t.createFromSeedChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleCreateFromSeed, "CreateWalletFromSeed")
-		case r := <-t.loadWalletChan:
+		case r, ok := <-t.loadWalletChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleLoadWallet, "LoadWallet")
-		case r := <-t.automateConsensusTxsChan:
+		case r, ok := <-t.automateConsensusTxsChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleAutomateConsensusTxs, "AutomateConsensusTxs")
 
 		// Transaction requests to respond to
-		case r := <-t.sendBidTxChan:
+		case r, ok := <-t.sendBidTxChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleSendBidTx, "BidTx")
-		case r := <-t.sendStakeTxChan:
+		case r, ok := <-t.sendStakeTxChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleSendStakeTx, "StakeTx")
-		case r := <-t.sendStandardTxChan:
+		case r, ok := <-t.sendStandardTxChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleSendStandardTx, "StandardTx")
+		case r, ok := <-t.createStandardTxChan:
+			if !ok {
+				return
+			}
+			handleRequest(r, t.handleCreateStandardTx, "CreateStandardTx")
 
 		// Information requests to respond to
-		case r := <-t.getBalanceChan:
+		case r, ok := <-t.getBalanceChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleBalance, "Balance")
-		case r := <-t.getUnconfirmedBalanceChan:
+		case r, ok := <-t.getUnconfirmedBalanceChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleUnconfirmedBalance, "UnconfirmedBalance")
-		case r := <-t.getAddressChan:
+		case r, ok := <-t.getAddressChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleAddress, "Address")
-		case r := <-t.getTxHistoryChan:
+		case r, ok := <-t.getTxHistoryChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleGetTxHistory, "GetTxHistory")
-		case r := <-t.isWalletLoadedChan:
+		case r, ok := <-t.isWalletLoadedChan:
+			if !ok {
+				return
+			}
 			handleRequest(r, t.handleIsWalletLoaded, "IsWalletLoaded")
 
 		// Event list to handle
 		case b := <-t.acceptedBlockChan:
 			t.onAcceptedBlockEvent(b)
+
+		case <-t.quit:
+			return
 		}
 	}
 }
@@ -354,6 +402,38 @@ func (t *Transactor) handleSendStandardTx(r rpcbus.Request) error {
 	return nil
 }
 
+// handleCreateStandardTx signs a standard tx with the wallet, and answers
+// with the tx marshalled, without publishing it. The tx can be broadcast
+// later on, through the mempool of any node.
+func (t *Transactor) handleCreateStandardTx(r rpcbus.Request) error {
+	if t.w == nil {
+		return errWalletNotLoaded
+	}
+
+	var amount uint64
+	if err := encoding.ReadUint64LE(&r.Params, &amount); err != nil {
+		return err
+	}
+
+	address, err := encoding.ReadString(&r.Params)
+	if err != nil {
+		return err
+	}
+
+	tx, err := t.CreateStandardTx(amount, address)
+	if err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := marshalling.MarshalTx(buf, tx); err != nil {
+		return err
+	}
+
+	r.RespChan <- rpcbus.Response{*buf, nil}
+	return nil
+}
+
 func (t *Transactor) handleBalance(r rpcbus.Request) error {
 
 	if t.w == nil {
@@ -386,7 +466,7 @@ func (t *Transactor) handleUnconfirmedBalance(r rpcbus.Request) error {
 	}
 
 	// Retrieve mempool txs
-	txsBuf, err := t.rb.Call(rpcbus.GetMempoolTxs, rpcbus.Request{bytes.Buffer{}, make(chan rpcbus.Response, 1)}, 2*time.Second)
+	txsBuf, err := t.rb.Call(rpcbus.GetMempoolTxs, rpcbus.NewRequest(bytes.Buffer{}), 2*time.Second)
 	if err != nil {
 		return err
 	}
@@ -446,13 +526,13 @@ func (t *Transactor) publishTx(tx transactions.Transaction) ([]byte, error) {
 		return nil, fmt.Errorf("error encoding transaction: %v\n", err)
 	}
 
-	hash, err := tx.CalculateHash()
+	hash, err := marshalling.TxID(tx)
 	if err != nil {
 		// If we found a valid bid tx, we should under no circumstance have issues marshalling it
 		return nil, fmt.Errorf("error encoding transaction: %v\n", err)
 	}
 
-	_, err = t.rb.Call(rpcbus.SendMempoolTx, rpcbus.Request{*buf, make(chan rpcbus.Response, 1)}, 0)
+	_, err = t.rb.Call(rpcbus.SendMempoolTx, rpcbus.NewRequest(*buf), 0)
 	if err != nil {
 		return nil, err
 	}
@@ -2,11 +2,13 @@ package transactor
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/maintainer"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/dusk-network/dusk-blockchain/pkg/crypto/secrets"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing/chainsync"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
@@ -38,12 +40,19 @@ type Transactor struct {
 	sendBidTxChan             chan rpcbus.Request
 	sendStakeTxChan           chan rpcbus.Request
 	sendStandardTxChan        chan rpcbus.Request
+	createStandardTxChan      chan rpcbus.Request
 	getBalanceChan            chan rpcbus.Request
 	getUnconfirmedBalanceChan chan rpcbus.Request
 	getAddressChan            chan rpcbus.Request
 	getTxHistoryChan          chan rpcbus.Request
 	automateConsensusTxsChan  chan rpcbus.Request
 	isWalletLoadedChan        chan rpcbus.Request
+
+	// closed to stop the listener, which closes done once the keys of the
+	// wallet are wiped
+	quit      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 // Instantiate a new Transactor struct.
@@ -70,12 +79,16 @@ func New(eb *eventbus.EventBus, rb *rpcbus.RPCBus, db database.DB,
 		sendBidTxChan:             make(chan rpcbus.Request, 1),
 		sendStakeTxChan:           make(chan rpcbus.Request, 1),
 		sendStandardTxChan:        make(chan rpcbus.Request, 1),
+		createStandardTxChan:      make(chan rpcbus.Request, 1),
 		getBalanceChan:            make(chan rpcbus.Request, 1),
 		getUnconfirmedBalanceChan: make(chan rpcbus.Request, 1),
 		getAddressChan:            make(chan rpcbus.Request, 1),
 		getTxHistoryChan:          make(chan rpcbus.Request, 1),
 		automateConsensusTxsChan:  make(chan rpcbus.Request, 1),
 		isWalletLoadedChan:        make(chan rpcbus.Request, 1),
+
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
 	}
 
 	if t.fetchDecoys == nil {
@@ -98,51 +111,55 @@ func New(eb *eventbus.EventBus, rb *rpcbus.RPCBus, db database.DB,
 
 // registers all rpcBus channels
 func (t *Transactor) registerMethods() error {
-	if err := t.rb.Register(rpcbus.LoadWallet, t.loadWalletChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.LoadWallet, t.loadWalletChan); err != nil {
+		return err
+	}
+
+	if err := t.rb.RegisterChan(rpcbus.CreateWallet, t.createWalletChan); err != nil {
 		return err
 	}
 
-	if err := t.rb.Register(rpcbus.CreateWallet, t.createWalletChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.CreateFromSeed, t.createFromSeedChan); err != nil {
 		return err
 	}
 
-	if err := t.rb.Register(rpcbus.CreateFromSeed, t.createFromSeedChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.SendBidTx, t.sendBidTxChan); err != nil {
 		return err
 	}
 
-	if err := t.rb.Register(rpcbus.SendBidTx, t.sendBidTxChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.SendStakeTx, t.sendStakeTxChan); err != nil {
 		return err
 	}
 
-	if err := t.rb.Register(rpcbus.SendStakeTx, t.sendStakeTxChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.SendStandardTx, t.sendStandardTxChan); err != nil {
 		return err
 	}
 
-	if err := t.rb.Register(rpcbus.SendStandardTx, t.sendStandardTxChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.CreateStandardTx, t.createStandardTxChan); err != nil {
 		return err
 	}
 
-	if err := t.rb.Register(rpcbus.GetBalance, t.getBalanceChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.GetBalance, t.getBalanceChan); err != nil {
 		return err
 	}
 
-	if err := t.rb.Register(rpcbus.GetUnconfirmedBalance, t.getUnconfirmedBalanceChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.GetUnconfirmedBalance, t.getUnconfirmedBalanceChan); err != nil {
 		return err
 	}
 
-	if err := t.rb.Register(rpcbus.GetAddress, t.getAddressChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.GetAddress, t.getAddressChan); err != nil {
 		return err
 	}
 
-	if err := t.rb.Register(rpcbus.GetTxHistory, t.getTxHistoryChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.GetTxHistory, t.getTxHistoryChan); err != nil {
 		return err
 	}
 
-	if err := t.rb.Register(rpcbus.AutomateConsensusTxs, t.automateConsensusTxsChan); err != nil {
+	if err := t.rb.RegisterChan(rpcbus.AutomateConsensusTxs, t.automateConsensusTxsChan); err != nil {
 		return err
 	}
 
-	return t.rb.Register(rpcbus.IsWalletLoaded, t.isWalletLoadedChan)
+	return t.rb.RegisterChan(rpcbus.IsWalletLoaded, t.isWalletLoadedChan)
 }
 
 func (t *Transactor) Wallet() (*wallet.Wallet, error) {
@@ -173,3 +190,21 @@ func (t *Transactor) launchMaintainer() error {
 	t.maintainerStarted = true
 	return nil
 }
+
+// Close stops the listener, and wipes the consensus keys of the wallet from
+// memory. It must only be called once Listen is running.
+func (t *Transactor) Close() {
+	t.closeOnce.Do(func() {
+		close(t.quit)
+	})
+	<-t.done
+}
+
+func (t *Transactor) wipeKeys() {
+	if t.w == nil {
+		return
+	}
+
+	keys := t.w.ConsensusKeys()
+	secrets.WipeConsensusKeys(&keys)
+}
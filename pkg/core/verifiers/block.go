@@ -3,19 +3,38 @@ package verifiers
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/versionbits"
 	"github.com/dusk-network/dusk-crypto/bls"
 	"github.com/dusk-network/dusk-wallet/block"
 	"github.com/dusk-network/dusk-wallet/transactions"
 )
 
+// ErrBlockTooLarge is returned for blocks exceeding the maximum size
+var ErrBlockTooLarge = errors.New("block exceeds the maximum size")
+
 // CheckBlock will verify whether a block is valid according to the rules of the consensus
 // returns nil if a block is valid
-func CheckBlock(db database.DB, prevBlock block.Block, blk block.Block) error {
+// The certificate is verified against the committees drawn from provisioners.
+// Candidate blocks, which are not certified yet, are checked with nil
+// provisioners. The timestamp is checked against now.
+func CheckBlock(db database.DB, prevBlock block.Block, blk block.Block, provisioners *user.Provisioners, now time.Time) error {
+	if err := CheckBlockSize(blk); err != nil {
+		return err
+	}
+
 	// 1. Check that we have not seen this block before
 	err := db.View(func(t database.Transaction) error {
 		_, err := t.FetchBlockExists(blk.Header.Hash)
@@ -24,7 +43,7 @@ func CheckBlock(db database.DB, prevBlock block.Block, blk block.Block) error {
 
 	if err != database.ErrBlockNotFound {
 		if err == nil {
-			err = errors.New("block already exists")
+			err = &ErrBlockExists{Hash: blk.Header.Hash}
 		}
 		return err
 	}
@@ -33,22 +52,108 @@ func CheckBlock(db database.DB, prevBlock block.Block, blk block.Block) error {
 		return err
 	}
 
-	if err := CheckMultiCoinbases(blk.Txs); err != nil {
+	if err := CheckBlockTimestamp(db, prevBlock, blk, now); err != nil {
 		return err
 	}
 
-	for i, merklePayload := range blk.Txs {
-		tx, ok := merklePayload.(transactions.Transaction)
-		if !ok {
-			return errors.New("tx does not implement the transaction interface")
+	// The certificate is checked before the transactions, so that blocks
+	// which were not finalized are not verified any further
+	if provisioners != nil {
+		if err := CheckBlockCertificate(*provisioners, blk); err != nil {
+			return err
 		}
-		if err := CheckTx(db, uint64(i), uint64(blk.Header.Timestamp), tx); err != nil {
+	}
+
+	if err := CheckMultiCoinbases(blk.Txs); err != nil {
+		return err
+	}
+
+	// The key images are only stored once the block is accepted, so the
+	// transactions are checked against each other here
+	if err := CheckBlockDoubleSpend(blk.Txs); err != nil {
+		return err
+	}
+
+	return CheckBlockTxs(db, blk)
+}
+
+// CheckHeadersOnly verifies a block as nodes storing only the headers do,
+// without the outputs and key images its transactions spend: its header,
+// which binds the transactions through the merkle root, its timestamp and its
+// certificate. The transactions themselves are not verified. Candidate
+// blocks are checked with nil provisioners.
+func CheckHeadersOnly(db database.DB, prevBlock block.Block, blk block.Block, provisioners *user.Provisioners, now time.Time) error {
+	if err := CheckBlockSize(blk); err != nil {
+		return err
+	}
+
+	if err := CheckBlockHeader(prevBlock, blk); err != nil {
+		return err
+	}
+
+	if err := CheckBlockTimestamp(db, prevBlock, blk, now); err != nil {
+		return err
+	}
+
+	if provisioners == nil {
+		return nil
+	}
+
+	return CheckBlockCertificate(*provisioners, blk)
+}
+
+// CheckBlockTxs verifies the transactions of a block across the workers set
+// by performance.verificationWorkers, as they do not depend on each other.
+// The error of the first invalid transaction, in block order, is returned,
+// whichever worker finishes first.
+func CheckBlockTxs(db database.DB, blk block.Block) error {
+	workers := config.Get().Performance.VerificationWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	errs := make([]error, len(blk.Txs))
+	indexes := make(chan int, len(blk.Txs))
+	for i := range blk.Txs {
+		indexes <- i
+	}
+	close(indexes)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = CheckTx(db, blk.Header.Height, uint64(i), uint64(blk.Header.Timestamp), blk.Txs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// CheckBlockSize returns ErrBlockTooLarge if the marshalled block exceeds the
+// maximum size of the network
+func CheckBlockSize(blk block.Block) error {
+	buf := new(bytes.Buffer)
+	if err := marshalling.MarshalBlock(buf, &blk); err != nil {
+		return err
+	}
+
+	if uint32(buf.Len()) > config.GetLimits().MaxBlockSize {
+		return ErrBlockTooLarge
+	}
+
+	return nil
+}
+
 // CheckBlockCertificate ensures that the block certificate is valid.
 func CheckBlockCertificate(provisioners user.Provisioners, blk block.Block) error {
 	if blk.Header.Height < 2 {
@@ -63,26 +168,39 @@ func CheckBlockCertificate(provisioners user.Provisioners, blk block.Block) erro
 	// Reconstruct signatures
 	stepOneBatchedSig, err := bls.UnmarshalSignature(blk.Header.Certificate.StepOneBatchedSig)
 	if err != nil {
-		return err
+		return &ErrBadCertificate{Step: stepOne, Err: err}
 	}
 
 	stepTwoBatchedSig, err := bls.UnmarshalSignature(blk.Header.Certificate.StepTwoBatchedSig)
 	if err != nil {
-		return err
+		return &ErrBadCertificate{Step: stepTwo, Err: err}
 	}
 
 	// Now, check the certificate's correctness for both reduction steps
 	if err := checkBlockCertificateForStep(stepOneBatchedSig, blk.Header.Certificate.StepOneCommittee, blk.Header.Height, stepOne, provisioners, blk.Header.Hash); err != nil {
-		return err
+		return &ErrBadCertificate{Step: stepOne, Err: err}
 	}
 
-	return checkBlockCertificateForStep(stepTwoBatchedSig, blk.Header.Certificate.StepTwoCommittee, blk.Header.Height, stepTwo, provisioners, blk.Header.Hash)
+	if err := checkBlockCertificateForStep(stepTwoBatchedSig, blk.Header.Certificate.StepTwoCommittee, blk.Header.Height, stepTwo, provisioners, blk.Header.Hash); err != nil {
+		return &ErrBadCertificate{Step: stepTwo, Err: err}
+	}
+
+	return nil
 }
 
+// checkBlockCertificateForStep reconstructs the committee of a reduction step,
+// and checks that the voters set in bitSet reached quorum and signed the
+// block hash
 func checkBlockCertificateForStep(batchedSig *bls.Signature, bitSet uint64, round uint64, step uint8, provisioners user.Provisioners, blockHash []byte) error {
 	size := committeeSize(provisioners.SubsetSizeAt(round))
 	committee := provisioners.CreateVotingCommittee(round, step, size)
 	subcommittee := committee.IntersectCluster(bitSet)
+
+	quorum := int(math.Ceil(float64(committee.Size()) * config.GetConsensusParams().QuorumRatio))
+	if votes := subcommittee.TotalOccurrences(); votes < quorum {
+		return fmt.Errorf("%d votes do not reach the quorum of %d", votes, quorum)
+	}
+
 	apk, err := agreement.ReconstructApk(subcommittee.Set)
 	if err != nil {
 		return err
@@ -91,9 +209,11 @@ func checkBlockCertificateForStep(batchedSig *bls.Signature, bitSet uint64, roun
 	return header.VerifySignatures(round, step, blockHash, apk, batchedSig)
 }
 
+// committeeSize returns the size of the reduction committees, whose votes
+// make up the certificate
 func committeeSize(memberAmount int) int {
-	if memberAmount > agreement.MaxCommitteeSize {
-		return agreement.MaxCommitteeSize
+	if max := config.GetConsensusParams().ReductionCommitteeSize; memberAmount > max {
+		return max
 	}
 
 	return memberAmount
@@ -104,38 +224,69 @@ func committeeSize(memberAmount int) int {
 // returns nil, if all checks pass
 func CheckBlockHeader(prevBlock block.Block, blk block.Block) error {
 	// Version
-	if blk.Header.Version > 0 {
+	if !versionbits.IsValid(blk.Header.Version) {
 		return errors.New("unsupported block version")
 	}
 
 	// blk.Headerhash = prevHeaderHash
 	if !bytes.Equal(blk.Header.PrevBlockHash, prevBlock.Header.Hash) {
-		return errors.New("Previous block hash does not equal the previous hash in the current block")
+		return &ErrBadPrevHash{PrevHash: blk.Header.PrevBlockHash, Expected: prevBlock.Header.Hash}
 	}
 
 	// blk.Headerheight = prevHeaderHeight +1
 	if blk.Header.Height != prevBlock.Header.Height+1 {
-		return errors.New("current block height is not one plus the previous block height")
-	}
-
-	// blk.Timestamp > prevTimestamp
-	if blk.Header.Timestamp <= prevBlock.Header.Timestamp {
-		return errors.New("current timestamp is less than the previous timestamp")
+		return &ErrBadHeight{Height: blk.Header.Height, Expected: prevBlock.Header.Height + 1}
 	}
 
 	// Merkle tree check -- Check is here as the root is not calculated on decode
-	tR := blk.Header.TxRoot
-	if err := blk.SetRoot(); err != nil {
+	root, err := marshalling.TxRoot(blk.Txs)
+	if err != nil {
 		return errors.New("could not calculate the merkle tree root for this header")
 	}
 
-	if !bytes.Equal(tR, blk.Header.TxRoot) {
+	if !bytes.Equal(root, blk.Header.TxRoot) {
 		return errors.New("merkle root mismatch")
 	}
 
 	return nil
 }
 
+// CheckBlockTimestamp checks that the block is timestamped after the median
+// timestamp of the last blocks, up to prevBlock, and at most MaxFutureDrift
+// ahead of now. prevBlock does not need to be stored yet, as for candidate
+// blocks. The blocks before it are found through their previous block hash,
+// so that the timestamps are those of the branch of prevBlock, even when it
+// is not the main chain.
+func CheckBlockTimestamp(db database.DB, prevBlock block.Block, blk block.Block, now time.Time) error {
+	params := config.GetConsensusParams()
+	timestamps := []int64{prevBlock.Header.Timestamp}
+	err := db.View(func(t database.Transaction) error {
+		hash := prevBlock.Header.PrevBlockHash
+		for height := prevBlock.Header.Height; height > 0 && len(timestamps) < params.MedianTimeBlocks; height-- {
+			header, err := t.FetchBlockHeader(hash)
+			if err != nil {
+				return err
+			}
+
+			timestamps = append(timestamps, header.Timestamp)
+			hash = header.PrevBlockHash
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	min := timestamps[len(timestamps)/2] + 1
+	max := now.Add(params.MaxFutureDrift).Unix()
+	if blk.Header.Timestamp < min || blk.Header.Timestamp > max {
+		return &ErrBadTimestamp{Timestamp: blk.Header.Timestamp, Min: min, Max: max}
+	}
+
+	return nil
+}
+
 // CheckMultiCoinbases returns an error if there is more than one coinbase transaction
 //  in the list or if there are none
 func CheckMultiCoinbases(txs []transactions.Transaction) error {
@@ -155,3 +306,33 @@ func CheckMultiCoinbases(txs []transactions.Transaction) error {
 	}
 	return nil
 }
+
+// CheckBlockSpends checks the inputs of the transactions against the outputs
+// and the key images of the chain. It is the part of the transaction checks
+// which depends on the blocks accepted since a candidate was verified.
+func CheckBlockSpends(db database.DB, blk block.Block) error {
+	for _, tx := range blk.Txs {
+		if err := checkTXDoubleSpent(db, tx.StandardTx().Inputs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckBlockDoubleSpend returns ErrDoubleSpend if a key image is spent by more
+// than one input of the transactions
+func CheckBlockDoubleSpend(txs []transactions.Transaction) error {
+	keyImages := make(map[string]struct{})
+	for _, tx := range txs {
+		for _, input := range tx.StandardTx().Inputs {
+			keyImage := string(input.KeyImage.Bytes())
+			if _, ok := keyImages[keyImage]; ok {
+				return &ErrDoubleSpend{KeyImage: input.KeyImage.Bytes()}
+			}
+			keyImages[keyImage] = struct{}{}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package verifiers_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/dusk-network/dusk-wallet/transactions"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that a key image can only be spent once in a block.
+func TestBlockDoubleSpend(t *testing.T) {
+	first := helper.RandomStandardTx(t, false)
+	second := helper.RandomStandardTx(t, false)
+	txs := []transactions.Transaction{first, second}
+	assert.NoError(t, verifiers.CheckBlockDoubleSpend(txs))
+
+	// Spend the first input of the first tx again
+	second.Inputs[0].KeyImage = first.Inputs[0].KeyImage
+	err := verifiers.CheckBlockDoubleSpend(txs)
+	var doubleSpend *verifiers.ErrDoubleSpend
+	assert.True(t, errors.As(err, &doubleSpend))
+	assert.Equal(t, first.Inputs[0].KeyImage.Bytes(), doubleSpend.KeyImage)
+}
+
+// Test that the committees of both reduction steps must reach quorum.
+func TestCertificateQuorum(t *testing.T) {
+	p, k := consensus.MockProvisioners(3)
+	blk := helper.RandomBlock(t, 2, 1)
+	votes := agreement.GenVotes(blk.Header.Hash, 2, 3, k, p)
+	blk.Header.Certificate = &block.Certificate{
+		StepOneBatchedSig: votes[0].Signature.Compress(),
+		StepTwoBatchedSig: votes[1].Signature.Compress(),
+		Step:              3,
+		StepOneCommittee:  votes[0].BitSet,
+		StepTwoCommittee:  votes[1].BitSet,
+	}
+	assert.NoError(t, verifiers.CheckBlockCertificate(*p, *blk))
+
+	// Drop the votes of the second step
+	blk.Header.Certificate.StepTwoCommittee = 0
+	err := verifiers.CheckBlockCertificate(*p, *blk)
+	var badCert *verifiers.ErrBadCertificate
+	assert.True(t, errors.As(err, &badCert))
+	assert.Equal(t, uint8(2), badCert.Step)
+}
+
+// Test that the error of the first invalid transaction is returned, however
+// many workers verify them.
+func TestBlockTxsErrorOrder(t *testing.T) {
+	r := config.Registry{}
+	r.General.Network = "testnet"
+	r.Consensus.MaxInputs = 10
+	r.Consensus.MaxOutputs = 10
+	r.Performance.VerificationWorkers = 4
+	config.Mock(&r)
+
+	// Too many outputs
+	outputs := helper.RandomStandardTx(t, false)
+	outputs.Inputs = outputs.Inputs[:1]
+	// Too many inputs
+	inputs := helper.RandomStandardTx(t, false)
+	inputs.Outputs = inputs.Outputs[:1]
+
+	blk := block.NewBlock()
+	blk.Txs = []transactions.Transaction{outputs, inputs, inputs, inputs, inputs}
+	assert.Equal(t, verifiers.ErrTooManyOutputs, verifiers.CheckBlockTxs(nil, *blk))
+
+	blk.Txs = []transactions.Transaction{inputs, outputs, outputs, outputs, outputs}
+	assert.Equal(t, verifiers.ErrTooManyInputs, verifiers.CheckBlockTxs(nil, *blk))
+}
+
+// Test that blocks must be timestamped after the median timestamp of the last
+// blocks, and not too far in the future.
+func TestMedianTimePast(t *testing.T) {
+	orig, _ := config.LookupProfile("devnet")
+	defer config.RegisterProfile("devnet", orig)
+
+	p := orig
+	p.MedianTimeBlocks = 5
+	config.RegisterProfile("devnet", p)
+
+	r := config.Registry{}
+	r.General.Network = "devnet"
+	config.Mock(&r)
+
+	_, db := lite.CreateDBConnection()
+	now := time.Unix(1600000000, 0)
+	base := now.Unix() - 1000
+	var prevHash []byte
+	for height, offset := range []int64{500, 100, 300, 200} {
+		blk := helper.RandomBlock(t, uint64(height), 1)
+		blk.Header.Timestamp = base + offset
+		blk.Header.PrevBlockHash = prevHash
+		prevHash = blk.Header.Hash
+		assert.NoError(t, db.Update(func(t database.Transaction) error {
+			return t.StoreBlock(blk)
+		}))
+	}
+
+	// A block of another branch, stored at the same height, is not taken
+	// into account
+	fork := helper.RandomBlock(t, 2, 1)
+	fork.Header.Timestamp = base + 900
+	assert.NoError(t, db.Update(func(t database.Transaction) error {
+		return t.StoreBlock(fork)
+	}))
+
+	// The median of the five timestamps is base+300
+	prevBlock := helper.RandomBlock(t, 4, 1)
+	prevBlock.Header.Timestamp = base + 400
+	prevBlock.Header.PrevBlockHash = prevHash
+	blk := helper.RandomBlock(t, 5, 1)
+
+	blk.Header.Timestamp = base + 300
+	err := verifiers.CheckBlockTimestamp(db, *prevBlock, *blk, now)
+	var badTimestamp *verifiers.ErrBadTimestamp
+	assert.True(t, errors.As(err, &badTimestamp))
+	assert.Equal(t, base+301, badTimestamp.Min)
+
+	// Earlier than the previous block, but after the median
+	blk.Header.Timestamp = base + 301
+	assert.NoError(t, verifiers.CheckBlockTimestamp(db, *prevBlock, *blk, now))
+
+	// The drift is measured against the clock given
+	blk.Header.Timestamp = now.Add(time.Hour).Unix()
+	assert.True(t, errors.As(verifiers.CheckBlockTimestamp(db, *prevBlock, *blk, now), &badTimestamp))
+	assert.Equal(t, now.Add(2*time.Minute).Unix(), badTimestamp.Max)
+}
@@ -0,0 +1,85 @@
+package verifiers
+
+import "fmt"
+
+// The rules of the blocks and the transactions fail with the errors below,
+// carrying the values which broke them, so that callers can tell them apart
+// with errors.As, e.g. to pick the code of the reject sent back to a peer.
+
+// ErrBlockExists is returned for blocks already in the chain
+type ErrBlockExists struct {
+	Hash []byte
+}
+
+func (e *ErrBlockExists) Error() string {
+	return fmt.Sprintf("block %x already exists", e.Hash)
+}
+
+// ErrBadPrevHash is returned for blocks which do not build on the previous
+// block
+type ErrBadPrevHash struct {
+	PrevHash []byte
+	Expected []byte
+}
+
+func (e *ErrBadPrevHash) Error() string {
+	return fmt.Sprintf("previous block hash %x does not match the previous block %x", e.PrevHash, e.Expected)
+}
+
+// ErrBadHeight is returned for blocks not following the previous block
+type ErrBadHeight struct {
+	Height   uint64
+	Expected uint64
+}
+
+func (e *ErrBadHeight) Error() string {
+	return fmt.Sprintf("block height %d is not the previous block height plus one (%d)", e.Height, e.Expected)
+}
+
+// ErrBadTimestamp is returned for blocks which are not timestamped after the
+// median timestamp of the previous blocks, or too far in the future
+type ErrBadTimestamp struct {
+	Timestamp int64
+	Min       int64
+	Max       int64
+}
+
+func (e *ErrBadTimestamp) Error() string {
+	return fmt.Sprintf("block timestamp %d is not within %d and %d", e.Timestamp, e.Min, e.Max)
+}
+
+// ErrBadCertificate is returned for certificates whose votes are invalid, or
+// did not reach quorum, on one of the reduction steps
+type ErrBadCertificate struct {
+	Step uint8
+	Err  error
+}
+
+func (e *ErrBadCertificate) Error() string {
+	return fmt.Sprintf("invalid certificate at step %d: %v", e.Step, e.Err)
+}
+
+// Unwrap returns the reason the votes are invalid
+func (e *ErrBadCertificate) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnknownOutput is returned for transactions spending outputs which are
+// not in the chain
+type ErrUnknownOutput struct {
+	Output []byte
+}
+
+func (e *ErrUnknownOutput) Error() string {
+	return fmt.Sprintf("transaction spends an unknown output %x", e.Output)
+}
+
+// ErrDoubleSpend is returned for transactions spending outputs already spent,
+// in the chain or in the same block
+type ErrDoubleSpend struct {
+	KeyImage []byte
+}
+
+func (e *ErrDoubleSpend) Error() string {
+	return fmt.Sprintf("key image %x is already spent", e.KeyImage)
+}
@@ -1,25 +1,50 @@
 package verifiers
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-crypto/rangeproof"
 	"github.com/dusk-network/dusk-wallet/transactions"
 	"github.com/pkg/errors"
 )
 
+var (
+	// ErrLockedInputs is returned for transactions spending outputs whose
+	// lock time has not expired yet
+	ErrLockedInputs = errors.New("transaction contains one or more locked inputs")
+	// ErrNoLockTime is returned for bids, stakes and timelocks which do not
+	// lock their outputs at all, once the rule is active, see CheckLockTime
+	ErrNoLockTime = errors.New("timelock must be greater than zero")
+	// ErrTxTooLarge is returned for transactions exceeding the maximum size
+	ErrTxTooLarge = errors.New("transaction exceeds the maximum size")
+	// ErrTooManyInputs is returned for transactions exceeding the maximum
+	// amount of inputs
+	ErrTooManyInputs = errors.New("transaction exceeds the maximum amount of inputs")
+	// ErrTooManyOutputs is returned for transactions exceeding the maximum
+	// amount of outputs
+	ErrTooManyOutputs = errors.New("transaction exceeds the maximum amount of outputs")
+)
+
 // CheckTx will verify whether a transaction is valid by checking:
 // - It has not been double spent
 // - It is not malformed
+// Height is the height of the block the transaction is included in. If it is
+// a solo transaction, this is the height of the next block to be generated
 // Index indicates the position that the transaction is in, in a block
 // If it is a solo transaction, this is set to 0
 // blockTime indicates what time the transaction will be included in a block
 // If it is a solo transaction, the blockTime is calculated by using currentBlockTime+consensusSeconds
 // Returns nil if a tx is valid
-func CheckTx(db database.DB, index uint64, blockTime uint64, tx transactions.Transaction) error {
-	if err := CheckStandardTx(db, tx.StandardTx()); err != nil && tx.Type() != transactions.CoinbaseType {
+func CheckTx(db database.DB, height uint64, index uint64, blockTime uint64, tx transactions.Transaction) error {
+	if err := CheckTxLimits(tx); err != nil {
+		return err
+	}
+
+	if err := CheckStandardTx(db, height, tx.StandardTx()); err != nil && tx.Type() != transactions.CoinbaseType {
 		return err
 	}
 
@@ -27,15 +52,69 @@ func CheckTx(db database.DB, index uint64, blockTime uint64, tx transactions.Tra
 		return err
 	}
 
+	return CheckLockTime(height, tx)
+}
+
+// CheckLockTime returns ErrNoLockTime for the bids, stakes and timelocks
+// which do not lock their outputs, if included at a height from which the
+// rule is active on the network
+func CheckLockTime(height uint64, tx transactions.Transaction) error {
+	if height < config.GetConsensusParams().LockTimeHeight {
+		return nil
+	}
+
+	var lockTime uint64
+	switch x := tx.(type) {
+	case *transactions.Timelock:
+		lockTime = x.Lock
+	case *transactions.Bid:
+		lockTime = x.Lock
+	case *transactions.Stake:
+		lockTime = x.Lock
+	default:
+		return nil
+	}
+
+	if lockTime == 0 {
+		return ErrNoLockTime
+	}
+
+	return nil
+}
+
+// CheckTxLimits checks the size and the amount of inputs and outputs of a
+// transaction against the limits of the network
+func CheckTxLimits(tx transactions.Transaction) error {
+	limits := config.GetLimits()
+	stx := tx.StandardTx()
+
+	if uint32(len(stx.Inputs)) > limits.MaxInputs {
+		return ErrTooManyInputs
+	}
+
+	if uint32(len(stx.Outputs)) > limits.MaxOutputs {
+		return ErrTooManyOutputs
+	}
+
+	buf := new(bytes.Buffer)
+	if err := marshalling.MarshalTx(buf, tx); err != nil {
+		return err
+	}
+
+	if uint32(buf.Len()) > limits.MaxTxSize {
+		return ErrTxTooLarge
+	}
+
 	return nil
 }
 
 // CheckStandardTx checks whether the standard fields are correct against the
-// passed blockchain db. These checks are both stateless and stateful.
-func CheckStandardTx(db database.DB, tx *transactions.Standard) error {
-	// Version -- currently we only accept Version 0
-	if tx.Version != 0 {
-		return errors.New("invalid transaction version")
+// passed blockchain db, for a tx included in a block at the given height.
+// These checks are both stateless and stateful.
+func CheckStandardTx(db database.DB, height uint64, tx *transactions.Standard) error {
+	// Version -- must be active at the height the tx is included at
+	if err := checkTxVersion(tx, height); err != nil {
+		return err
 	}
 
 	// Type - currently we only have five types
@@ -58,7 +137,7 @@ func CheckStandardTx(db database.DB, tx *transactions.Standard) error {
 	}
 
 	// Inputs - should be unlocked
-	if err := checkInputsLocked(db, tx.Inputs); err != nil {
+	if err := checkInputsLocked(db, height, tx.Inputs); err != nil {
 		return err
 	}
 
@@ -175,13 +254,13 @@ func checkRangeProof(p rangeproof.Proof) error {
 	return err
 }
 
-// checks that the transaction has not been spent by checking the database for that key image
-// returns nil if item not in database
+// checkTXDoubleSpent checks the inputs against the outputs and the key images
+// stored in the chain. It returns ErrUnknownOutput if a ring member is not a
+// previous output, and ErrDoubleSpend if a key image was already spent.
 func checkTXDoubleSpent(db database.DB, inputs transactions.Inputs) error {
-
-	err := db.View(func(t database.Transaction) error {
+	return db.View(func(t database.Transaction) error {
 		for _, input := range inputs {
-			// Check First key in verification is valid
+			// Every member of the ring must be an output of the chain
 			for _, keyV := range input.Signature.PubKeys {
 				key := keyV.OutputKey()
 				exists, err := t.FetchOutputExists(key.Bytes())
@@ -189,23 +268,13 @@ func checkTXDoubleSpent(db database.DB, inputs transactions.Inputs) error {
 					return err
 				}
 				if !exists {
-					return errors.New("This key is not a previous output ")
+					return &ErrUnknownOutput{Output: key.Bytes()}
 				}
 			}
 
-		}
-
-		return nil
-	})
-	if err != nil {
-		return err
-	}
-
-	return db.View(func(t database.Transaction) error {
-		for _, input := range inputs {
 			exists, txID, _ := t.FetchKeyImageExists(input.KeyImage.Bytes())
 			if exists || txID != nil {
-				return errors.New("already spent")
+				return &ErrDoubleSpend{KeyImage: input.KeyImage.Bytes()}
 			}
 		}
 
@@ -213,13 +282,11 @@ func checkTXDoubleSpent(db database.DB, inputs transactions.Inputs) error {
 	})
 }
 
-func checkInputsLocked(db database.DB, inputs transactions.Inputs) error {
+// checkInputsLocked returns ErrLockedInputs if any of the outputs referenced
+// by the inputs is still locked at the given height. Decoys are checked as
+// well, as the real output of a ring signature can not be told apart.
+func checkInputsLocked(db database.DB, height uint64, inputs transactions.Inputs) error {
 	return db.View(func(t database.Transaction) error {
-		currentHeight, err := t.FetchCurrentHeight()
-		if err != nil {
-			return err
-		}
-
 		for _, input := range inputs {
 			for _, keyV := range input.Signature.PubKeys {
 				key := keyV.OutputKey()
@@ -229,8 +296,8 @@ func checkInputsLocked(db database.DB, inputs transactions.Inputs) error {
 				}
 
 				// Found an input which is still locked
-				if unlockHeight > currentHeight {
-					return errors.New("transaction contains one or more locked inputs")
+				if unlockHeight >= height {
+					return ErrLockedInputs
 				}
 			}
 		}
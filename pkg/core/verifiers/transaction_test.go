@@ -11,6 +11,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
 	"github.com/dusk-network/dusk-crypto/mlsag"
 	"github.com/dusk-network/dusk-wallet/block"
@@ -91,7 +92,92 @@ func TestLockedInputs(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Checking the tx should fail with a specific error
-	assert.Equal(t, "transaction contains one or more locked inputs", verifiers.CheckTx(db, 0, uint64(time.Now().Unix()), tx).Error())
+	assert.Equal(t, "transaction contains one or more locked inputs", verifiers.CheckTx(db, 2, 0, uint64(time.Now().Unix()), tx).Error())
+}
+
+// Test that bids, stakes and timelocks must lock their outputs, once the rule
+// is active on the network.
+func TestZeroLockTime(t *testing.T) {
+	prev := config.Get()
+	defer config.Mock(&prev)
+	r := prev
+	r.General.Network = "localnet"
+	config.Mock(&r)
+
+	stake, err := helper.RandomStakeTx(t, false)
+	assert.NoError(t, err)
+	stake.Lock = 0
+	assert.Equal(t, verifiers.ErrNoLockTime, verifiers.CheckLockTime(1, stake))
+
+	bid, err := helper.RandomBidTx(t, false)
+	assert.NoError(t, err)
+	bid.Lock = 0
+	assert.Equal(t, verifiers.ErrNoLockTime, verifiers.CheckLockTime(1, bid))
+
+	tlock := helper.RandomTLockTx(t, false)
+	tlock.Lock = 0
+	assert.Equal(t, verifiers.ErrNoLockTime, verifiers.CheckLockTime(1, tlock))
+
+	// The rule is not active on testnet yet
+	r.General.Network = "testnet"
+	config.Mock(&r)
+	assert.NoError(t, verifiers.CheckLockTime(1, tlock))
+}
+
+// Test that transactions exceeding the limits of the network are rejected.
+func TestTxLimits(t *testing.T) {
+	r := config.Registry{}
+	r.General.Network = "testnet"
+	config.Mock(&r)
+
+	tx := helper.RandomStandardTx(t, false)
+	assert.NoError(t, verifiers.CheckTxLimits(tx))
+
+	r.Consensus.MaxInputs = uint32(len(tx.Inputs) - 1)
+	assert.Equal(t, verifiers.ErrTooManyInputs, verifiers.CheckTxLimits(tx))
+
+	r.Consensus.MaxInputs = 0
+	r.Consensus.MaxOutputs = uint32(len(tx.Outputs) - 1)
+	assert.Equal(t, verifiers.ErrTooManyOutputs, verifiers.CheckTxLimits(tx))
+
+	r.Consensus.MaxOutputs = 0
+	r.Consensus.MaxTxSize = 1
+	assert.Equal(t, verifiers.ErrTxTooLarge, verifiers.CheckTxLimits(tx))
+}
+
+// Test that transaction versions activate at the configured heights.
+func TestTxVersionActivation(t *testing.T) {
+	r := config.Registry{}
+	r.Consensus.TxVersionHeights = []uint64{100}
+	config.Mock(&r)
+
+	assert.True(t, verifiers.TxVersionActive(0, 0))
+	assert.False(t, verifiers.TxVersionActive(1, 99))
+	assert.True(t, verifiers.TxVersionActive(1, 100))
+	assert.False(t, verifiers.TxVersionActive(2, 1000))
+
+	// Active versions unknown to this node are valid, but not relayed
+	assert.True(t, verifiers.IsStandardTxVersion(0, 0))
+	assert.False(t, verifiers.IsStandardTxVersion(1, 100))
+}
+
+// Test that the rules activating at a height are enforced from the height of
+// the block the transaction is included in, whatever the tip of the database.
+func TestCheckTxHeight(t *testing.T) {
+	r := config.Registry{}
+	r.General.Network = "testnet"
+	r.Consensus.TxVersionHeights = []uint64{100}
+	config.Mock(&r)
+
+	tx := helper.RandomStandardTx(t, false)
+	tx.Version = 1
+	// Without inputs, the tx is refused before the database is looked up
+	tx.Inputs = nil
+	assert.Equal(t, verifiers.ErrInactiveTxVersion, verifiers.CheckTx(nil, 99, 0, 0, tx))
+
+	err := verifiers.CheckTx(nil, 100, 0, 0, tx)
+	assert.Error(t, err)
+	assert.NotEqual(t, verifiers.ErrInactiveTxVersion, err)
 }
 
 // Write a block with one transaction to the db.
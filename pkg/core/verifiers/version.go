@@ -0,0 +1,61 @@
+package verifiers
+
+import (
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-wallet/transactions"
+	"github.com/pkg/errors"
+)
+
+// ErrInactiveTxVersion is returned for transactions of a version which is not
+// active yet at the height they would be included at
+var ErrInactiveTxVersion = errors.New("transaction version is not active")
+
+// versionRules holds the rules specific to each transaction version known to
+// this node, enforced on top of the ones of CheckStandardTx.
+//
+// Versions missing here are unknown. Once active, txs of an unknown version
+// are accepted in blocks, so that nodes which did not upgrade keep following
+// the chain, but they are considered non-standard and are not relayed.
+var versionRules = map[uint8]func(tx *transactions.Standard) error{
+	0: func(*transactions.Standard) error { return nil },
+}
+
+// TxVersionActive returns true if txs of the given version are valid in a
+// block at the given height. Version 0 is always active, while version n
+// activates at the n-th height of consensus.txVersionHeights.
+func TxVersionActive(version uint8, height uint64) bool {
+	if version == 0 {
+		return true
+	}
+
+	heights := config.Get().Consensus.TxVersionHeights
+	if int(version) > len(heights) {
+		return false
+	}
+
+	return height >= heights[version-1]
+}
+
+// IsStandardTxVersion returns true if txs of the given version are relayed,
+// that is if the version is both known to this node and active
+func IsStandardTxVersion(version uint8, height uint64) bool {
+	_, known := versionRules[version]
+	return known && TxVersionActive(version, height)
+}
+
+// checkTxVersion enforces the rules of the version of tx, for a tx included
+// in a block at the given height. The height is the one of the block or the
+// candidate being verified, not the tip of the database, as forks and
+// candidates are verified ahead of it.
+func checkTxVersion(tx *transactions.Standard, height uint64) error {
+	if !TxVersionActive(tx.Version, height) {
+		return ErrInactiveTxVersion
+	}
+
+	rules, known := versionRules[tx.Version]
+	if !known {
+		return nil
+	}
+
+	return rules(tx)
+}
@@ -0,0 +1,214 @@
+package versionbits
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+)
+
+// State is the activation state of a deployment
+type State uint8
+
+const (
+	// Defined deployments are not signaled yet
+	Defined State = iota
+	// Started deployments are being signaled
+	Started
+	// LockedIn deployments become active at the next window
+	LockedIn
+	// Active deployments are enforced
+	Active
+)
+
+var stateNames = [...]string{"defined", "started", "lockedin", "active"}
+
+func (s State) String() string {
+	if int(s) < len(stateNames) {
+		return stateNames[s]
+	}
+
+	return "unknown"
+}
+
+// VersionFetcher returns the version of the block at the given height
+type VersionFetcher func(height uint64) (uint8, error)
+
+// Tracker tallies the signals of the blocks of each window, and returns the
+// state of the deployments. States only change at the start of a window, and
+// are cached as the chain grows.
+type Tracker struct {
+	lock  sync.Mutex
+	fetch VersionFetcher
+	// states of each deployment, indexed by window
+	states map[Deployment][]State
+}
+
+// NewTracker returns a Tracker reading the blocks with fetch
+func NewTracker(fetch VersionFetcher) *Tracker {
+	return &Tracker{
+		fetch:  fetch,
+		states: make(map[Deployment][]State),
+	}
+}
+
+// State returns the state of d for the block at the given height. All the
+// blocks of the previous windows must be known.
+func (t *Tracker) State(d Deployment, height uint64) (State, error) {
+	window := Window()
+	target := height / window
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	states := t.states[d]
+	for n := uint64(len(states)); n <= target; n++ {
+		prev := Defined
+		if n > 0 {
+			prev = states[n-1]
+		}
+
+		state, err := t.next(d, prev, n, window)
+		if err != nil {
+			return Defined, err
+		}
+
+		states = append(states, state)
+		t.states[d] = states
+	}
+
+	return states[target], nil
+}
+
+// next returns the state of d in the n-th window, given its state in the
+// window before
+func (t *Tracker) next(d Deployment, prev State, n, window uint64) (State, error) {
+	switch prev {
+	case Defined:
+		if n*window >= d.StartHeight {
+			return Started, nil
+		}
+		return Defined, nil
+	case Started:
+		signals, err := t.count(d, (n-1)*window, n*window)
+		if err != nil {
+			return Defined, err
+		}
+
+		if signals >= d.Threshold {
+			return LockedIn, nil
+		}
+		return Started, nil
+	default:
+		return Active, nil
+	}
+}
+
+// Signals returns the amount of blocks signaling d in the window of the given
+// height, up to the block before it
+func (t *Tracker) Signals(d Deployment, height uint64) (uint64, error) {
+	window := Window()
+	return t.count(d, height-height%window, height)
+}
+
+// count returns the amount of blocks signaling d between the heights from
+// (included) and to (excluded)
+func (t *Tracker) count(d Deployment, from, to uint64) (uint64, error) {
+	var signals uint64
+	for height := from; height < to; height++ {
+		version, err := t.fetch(height)
+		if err != nil {
+			return 0, err
+		}
+
+		if Signals(version, d.Bit) {
+			signals++
+		}
+	}
+
+	return signals, nil
+}
+
+// Status is the state of a deployment, along with the amount of signals
+// tallied in the current window
+type Status struct {
+	Deployment
+	State   State
+	Signals uint64
+}
+
+// MarshalStatus encodes the status of a list of deployments
+func MarshalStatus(w *bytes.Buffer, status []Status) error {
+	if err := encoding.WriteVarInt(w, uint64(len(status))); err != nil {
+		return err
+	}
+
+	for _, s := range status {
+		if err := encoding.WriteString(w, s.Name); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteUint8(w, s.Bit); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteUint64LE(w, s.StartHeight); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteUint64LE(w, s.Threshold); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteUint8(w, uint8(s.State)); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteUint64LE(w, s.Signals); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalStatus decodes a list of deployments encoded by MarshalStatus
+func UnmarshalStatus(r *bytes.Buffer) ([]Status, error) {
+	n, err := encoding.ReadVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]Status, 0)
+	for i := uint64(0); i < n; i++ {
+		var s Status
+		if s.Name, err = encoding.ReadString(r); err != nil {
+			return nil, err
+		}
+
+		if err := encoding.ReadUint8(r, &s.Bit); err != nil {
+			return nil, err
+		}
+
+		if err := encoding.ReadUint64LE(r, &s.StartHeight); err != nil {
+			return nil, err
+		}
+
+		if err := encoding.ReadUint64LE(r, &s.Threshold); err != nil {
+			return nil, err
+		}
+
+		var state uint8
+		if err := encoding.ReadUint8(r, &state); err != nil {
+			return nil, err
+		}
+		s.State = State(state)
+
+		if err := encoding.ReadUint64LE(r, &s.Signals); err != nil {
+			return nil, err
+		}
+
+		status = append(status, s)
+	}
+
+	return status, nil
+}
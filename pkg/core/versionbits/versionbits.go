@@ -0,0 +1,112 @@
+// Package versionbits coordinates the activation of consensus rule changes on
+// chain. Block generators signal their support of a deployment by setting its
+// bit in the version of their blocks, and the deployment locks in once enough
+// blocks of a window signal it.
+//
+// Versions with the SignalingBit set carry deployment bits in their 7 lower
+// bits. Version 0 signals nothing.
+package versionbits
+
+import (
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+)
+
+const (
+	// SignalingBit marks the versions of blocks signaling deployments
+	SignalingBit uint8 = 0x80
+	// MaxBit is the highest deployment bit
+	MaxBit uint8 = 6
+
+	// DefaultWindow is the amount of blocks in which signals are tallied,
+	// unless set by consensus.signalingWindow
+	DefaultWindow uint64 = 1000
+)
+
+// Deployment is a consensus rule change, signaled by setting Bit in the
+// version of blocks from StartHeight on. It locks in once Threshold blocks of
+// a window signal it, and becomes active one window later.
+type Deployment struct {
+	Name        string
+	Bit         uint8
+	StartHeight uint64
+	Threshold   uint64
+}
+
+// Deployments returns the deployments set in the configuration. A zero
+// Threshold means 95% of the window.
+func Deployments() []Deployment {
+	deployments := make([]Deployment, 0)
+	for _, d := range config.Get().Consensus.Deployments {
+		threshold := d.Threshold
+		if threshold == 0 {
+			threshold = Window() * 95 / 100
+		}
+
+		deployments = append(deployments, Deployment{d.Name, d.Bit, d.StartHeight, threshold})
+	}
+
+	return deployments
+}
+
+// Window returns the amount of blocks in which signals are tallied
+func Window() uint64 {
+	if w := config.Get().Consensus.SignalingWindow; w > 0 {
+		return w
+	}
+
+	return DefaultWindow
+}
+
+// IsValid returns true if version is either 0 or a signaling version. Unknown
+// deployment bits are valid, so that nodes which did not upgrade keep
+// following the chain.
+func IsValid(version uint8) bool {
+	return version == 0 || version&SignalingBit != 0
+}
+
+// Signals returns true if version signals the given deployment bit
+func Signals(version uint8, bit uint8) bool {
+	return bit <= MaxBit && version&SignalingBit != 0 && version&(1<<bit) != 0
+}
+
+// Encode returns the version signaling the given deployment bits, or 0 if
+// there are none. Bits higher than MaxBit are ignored.
+func Encode(bits ...uint8) uint8 {
+	var version uint8
+	for _, bit := range bits {
+		if bit <= MaxBit {
+			version |= 1 << bit
+		}
+	}
+
+	if version == 0 {
+		return 0
+	}
+
+	return version | SignalingBit
+}
+
+// Decode returns the deployment bits signaled by version
+func Decode(version uint8) []uint8 {
+	bits := make([]uint8, 0)
+	for bit := uint8(0); bit <= MaxBit; bit++ {
+		if Signals(version, bit) {
+			bits = append(bits, bit)
+		}
+	}
+
+	return bits
+}
+
+// BlockVersion returns the version of a block generated at the given height,
+// signaling every configured deployment past its start height
+func BlockVersion(height uint64) uint8 {
+	bits := make([]uint8, 0)
+	for _, d := range Deployments() {
+		if height >= d.StartHeight {
+			bits = append(bits, d.Bit)
+		}
+	}
+
+	return Encode(bits...)
+}
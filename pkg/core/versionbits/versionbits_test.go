@@ -0,0 +1,75 @@
+package versionbits
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	assert.Equal(t, uint8(0), Encode())
+	assert.Equal(t, uint8(0), Encode(MaxBit+1))
+
+	version := Encode(0, 3)
+	assert.Equal(t, uint8(0x89), version)
+	assert.True(t, IsValid(version))
+	assert.True(t, Signals(version, 3))
+	assert.False(t, Signals(version, 1))
+	assert.Equal(t, []uint8{0, 3}, Decode(version))
+
+	// Versions without the signaling bit signal nothing
+	assert.False(t, IsValid(0x09))
+	assert.Empty(t, Decode(0x09))
+	assert.True(t, IsValid(0))
+}
+
+func TestTracker(t *testing.T) {
+	prev := config.Get()
+	defer config.Mock(&prev)
+	r := prev
+	r.Consensus.SignalingWindow = 10
+	config.Mock(&r)
+
+	d := Deployment{Name: "test", Bit: 2, StartHeight: 15, Threshold: 8}
+
+	// Blocks of the second window signal, and so do 8 out of 10 blocks of
+	// the third one
+	versions := make([]uint8, 60)
+	for h := 10; h < 20; h++ {
+		versions[h] = Encode(d.Bit)
+	}
+	for h := 20; h < 28; h++ {
+		versions[h] = Encode(d.Bit)
+	}
+
+	tracker := NewTracker(func(height uint64) (uint8, error) {
+		return versions[height], nil
+	})
+
+	expected := []State{Defined, Defined, Started, LockedIn, Active, Active}
+	for window, state := range expected {
+		s, err := tracker.State(d, uint64(window*10+5))
+		assert.NoError(t, err)
+		assert.Equal(t, state, s, "window %d", window)
+	}
+
+	signals, err := tracker.Signals(d, 25)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), signals)
+}
+
+func TestMarshalStatus(t *testing.T) {
+	status := []Status{
+		{Deployment{"a", 1, 100, 950}, Started, 12},
+		{Deployment{"b", 6, 0, 10}, Active, 0},
+	}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, MarshalStatus(buf, status))
+
+	decoded, err := UnmarshalStatus(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, status, decoded)
+}
@@ -0,0 +1,162 @@
+// Package bls wraps the BLS primitives of dusk-crypto with the checks needed
+// before trusting keys and signatures received from the network. Keys and
+// signatures at infinity are refused, as they would let a signer cancel out
+// the contribution of the others to an aggregate, and duplicated keys are
+// refused when aggregating.
+//
+// Signatures live in G1, which has a cofactor of 1 on BN256: every point on
+// the curve is in the right subgroup, and points off the curve are refused by
+// Decompress. Public keys live in G2, and are bound to their owner through a
+// proof of possession (see ProvePossession).
+package bls
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dusk-network/dusk-crypto/bls"
+)
+
+var (
+	// ErrInfinity is returned for keys and signatures at infinity
+	ErrInfinity = errors.New("bls: point at infinity")
+	// ErrNoKeys is returned when aggregating an empty set of keys
+	ErrNoKeys = errors.New("bls: no key to aggregate")
+	// ErrDuplicateKey is returned when a key appears twice in an aggregate
+	ErrDuplicateKey = errors.New("bls: duplicate key in aggregate")
+)
+
+// possessionTag separates the proofs of possession from any other signed
+// message, so that a proof can not be replayed as a vote and vice versa.
+var possessionTag = []byte("dusk-bls-pop")
+
+// UnmarshalPk decodes a public key, refusing the point at infinity.
+func UnmarshalPk(b []byte) (*bls.PublicKey, error) {
+	if isZero(b) {
+		return nil, ErrInfinity
+	}
+
+	return bls.UnmarshalPk(b)
+}
+
+// UnmarshalSignature decodes a compressed signature, refusing the point at
+// infinity.
+func UnmarshalSignature(b []byte) (*bls.Signature, error) {
+	if isZero(b) {
+		return nil, ErrInfinity
+	}
+
+	sig, err := bls.UnmarshalSignature(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if isZero(sig.Marshal()) {
+		return nil, ErrInfinity
+	}
+
+	return sig, nil
+}
+
+// AggregateKeys aggregates a set of public keys into an Apk.
+func AggregateKeys(keys [][]byte) (*bls.Apk, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	seen := make(map[string]struct{}, len(keys))
+	var apk *bls.Apk
+	for _, k := range keys {
+		if _, ok := seen[string(k)]; ok {
+			return nil, ErrDuplicateKey
+		}
+		seen[string(k)] = struct{}{}
+
+		pk, err := UnmarshalPk(k)
+		if err != nil {
+			return nil, err
+		}
+
+		if apk == nil {
+			apk = bls.NewApk(pk)
+			continue
+		}
+
+		if err := apk.Aggregate(pk); err != nil {
+			return nil, err
+		}
+	}
+
+	return apk, nil
+}
+
+// Verify checks an aggregated signature against an aggregated key.
+func Verify(apk *bls.Apk, msg []byte, sig *bls.Signature) error {
+	if apk == nil || sig == nil {
+		return errors.New("bls: missing key or signature")
+	}
+
+	if isZero(sig.Marshal()) {
+		return ErrInfinity
+	}
+
+	return bls.Verify(apk, msg, sig)
+}
+
+// VerifySignature checks the compressed signature of a single signer.
+func VerifySignature(pk, msg, sig []byte) error {
+	pubKey, err := UnmarshalPk(pk)
+	if err != nil {
+		return err
+	}
+
+	signature, err := UnmarshalSignature(sig)
+	if err != nil {
+		return err
+	}
+
+	return bls.Verify(bls.NewApk(pubKey), msg, signature)
+}
+
+// AggregateVerify checks an aggregated compressed signature against the keys
+// of its signers.
+func AggregateVerify(keys [][]byte, msg, sig []byte) error {
+	apk, err := AggregateKeys(keys)
+	if err != nil {
+		return err
+	}
+
+	signature, err := UnmarshalSignature(sig)
+	if err != nil {
+		return err
+	}
+
+	return bls.Verify(apk, msg, signature)
+}
+
+// ProvePossession returns a proof that the owner of pk knows the matching
+// secret key. Provisioners are expected to present it along with their key,
+// so that nobody can register a key crafted from the keys of others.
+func ProvePossession(sk *bls.SecretKey, pk *bls.PublicKey) ([]byte, error) {
+	sig, err := bls.Sign(sk, pk, possessionMsg(pk.Marshal()))
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.Compress(), nil
+}
+
+// VerifyPossession checks a proof returned by ProvePossession.
+func VerifyPossession(pk, proof []byte) error {
+	return VerifySignature(pk, possessionMsg(pk), proof)
+}
+
+func possessionMsg(pk []byte) []byte {
+	msg := make([]byte, 0, len(possessionTag)+len(pk))
+	msg = append(msg, possessionTag...)
+	return append(msg, pk...)
+}
+
+func isZero(b []byte) bool {
+	return len(b) == 0 || bytes.Count(b, []byte{0}) == len(b)
+}
@@ -0,0 +1,62 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-crypto/bls"
+	"github.com/dusk-network/dusk-wallet/key"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateVerify(t *testing.T) {
+	msg := []byte("this is a mock message")
+	k1, _ := key.NewRandConsensusKeys()
+	k2, _ := key.NewRandConsensusKeys()
+
+	sig, err := bls.Sign(k1.BLSSecretKey, k1.BLSPubKey, msg)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifySignature(k1.BLSPubKeyBytes, msg, sig.Compress()))
+
+	sig2, err := bls.Sign(k2.BLSSecretKey, k2.BLSPubKey, msg)
+	assert.NoError(t, err)
+	sig.Aggregate(sig2)
+
+	keys := [][]byte{k1.BLSPubKeyBytes, k2.BLSPubKeyBytes}
+	assert.NoError(t, AggregateVerify(keys, msg, sig.Compress()))
+
+	// A signer can not be counted twice
+	keys = append(keys, k1.BLSPubKeyBytes)
+	assert.Equal(t, ErrDuplicateKey, AggregateVerify(keys, msg, sig.Compress()))
+
+	_, err = AggregateKeys(nil)
+	assert.Equal(t, ErrNoKeys, err)
+}
+
+func TestInfinityRefused(t *testing.T) {
+	k, _ := key.NewRandConsensusKeys()
+	zero := make([]byte, len(k.BLSPubKeyBytes))
+
+	_, err := UnmarshalPk(zero)
+	assert.Equal(t, ErrInfinity, err)
+
+	_, err = AggregateKeys([][]byte{k.BLSPubKeyBytes, zero})
+	assert.Equal(t, ErrInfinity, err)
+
+	_, err = UnmarshalSignature(make([]byte, 33))
+	assert.Equal(t, ErrInfinity, err)
+}
+
+func TestPossession(t *testing.T) {
+	k, _ := key.NewRandConsensusKeys()
+	other, _ := key.NewRandConsensusKeys()
+
+	proof, err := ProvePossession(k.BLSSecretKey, k.BLSPubKey)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyPossession(k.BLSPubKeyBytes, proof))
+
+	// The proof is bound to the key
+	assert.Error(t, VerifyPossession(other.BLSPubKeyBytes, proof))
+
+	// and can not pass for a signature of the key itself
+	assert.Error(t, VerifySignature(k.BLSPubKeyBytes, k.BLSPubKeyBytes, proof))
+}
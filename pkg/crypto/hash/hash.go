@@ -0,0 +1,57 @@
+// Package hash provides SHA3-256 helpers which do not allocate, for the hot
+// loops of the consensus. Hashers are pooled, and digests are appended to a
+// buffer owned by the caller.
+package hash
+
+import (
+	"hash"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Size is the size of a digest
+const Size = 32
+
+var hasherPool = sync.Pool{
+	New: func() interface{} {
+		return sha3.New256()
+	},
+}
+
+// HashInto appends the SHA3-256 digest of the concatenation of data to dst,
+// and returns the extended buffer. No allocation takes place if dst has room
+// for Size more bytes.
+func HashInto(dst []byte, data ...[]byte) []byte {
+	h := hasherPool.Get().(hash.Hash)
+	for _, d := range data {
+		// Writes to a hash never fail
+		_, _ = h.Write(d)
+	}
+
+	// Sum copies the state of the sponge before squeezing it, which
+	// allocates. As the hasher is reset right after, it is squeezed in place
+	// when possible.
+	if r, ok := h.(io.Reader); ok {
+		start := len(dst)
+		dst = grow(dst, Size)
+		_, _ = r.Read(dst[start:])
+	} else {
+		dst = h.Sum(dst)
+	}
+
+	h.Reset()
+	hasherPool.Put(h)
+	return dst
+}
+
+// grow extends dst by n bytes, reallocating it only if its capacity is not
+// large enough.
+func grow(dst []byte, n int) []byte {
+	if cap(dst)-len(dst) >= n {
+		return dst[:len(dst)+n]
+	}
+
+	return append(dst, make([]byte, n)...)
+}
@@ -0,0 +1,42 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestHashInto(t *testing.T) {
+	expected := sha3.Sum256([]byte("dusk network"))
+
+	digest := HashInto(nil, []byte("dusk"), []byte(" "), []byte("network"))
+	assert.Equal(t, expected[:], digest)
+
+	// The digest is appended to the buffer
+	buf := make([]byte, 1, 1+Size)
+	digest = HashInto(buf, []byte("dusk network"))
+	assert.Equal(t, append([]byte{0}, expected[:]...), digest)
+
+	// Pooled hashers are reset
+	digest = HashInto(nil, []byte("dusk network"))
+	assert.Equal(t, expected[:], digest)
+}
+
+func BenchmarkHashInto(b *testing.B) {
+	msg := make([]byte, 13)
+	var digest [Size]byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		HashInto(digest[:0], msg)
+	}
+}
+
+func BenchmarkSum256(b *testing.B) {
+	msg := make([]byte, 13)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		digest := sha3.Sum256(msg)
+		_ = digest[:]
+	}
+}
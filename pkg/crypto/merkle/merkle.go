@@ -0,0 +1,129 @@
+// Package merkle implements the binary merkle tree committing to the txs of a
+// block, along with inclusion proofs for light clients.
+//
+// Leaves and inner nodes are hashed with distinct prefixes, so that an inner
+// node can not be passed off as a leaf. A node without a sibling is carried up
+// to the next level unchanged, rather than paired with itself, so that no two
+// lists of leaves share a root.
+package merkle
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/crypto/hash"
+)
+
+var (
+	// ErrEmpty is returned when building a tree without leaves
+	ErrEmpty = errors.New("merkle tree without leaves")
+	// ErrOutOfRange is returned when proving a leaf which is not in the tree
+	ErrOutOfRange = errors.New("leaf index out of range")
+)
+
+const (
+	leafPrefix byte = 0x00
+	nodePrefix byte = 0x01
+)
+
+// Proof proves the inclusion of the leaf at position Index in a tree of Count
+// leaves. Path holds the siblings met on the way to the root, from the bottom.
+type Proof struct {
+	Index uint32
+	Count uint32
+	Path  [][]byte
+}
+
+// Root returns the root of the tree built over leaves
+func Root(leaves [][]byte) ([]byte, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmpty
+	}
+
+	level := hashLeaves(leaves)
+	for len(level) > 1 {
+		level = nextLevel(level)
+	}
+
+	return level[0], nil
+}
+
+// Prove returns the proof of inclusion of the leaf at the given index
+func Prove(leaves [][]byte, index uint32) (Proof, error) {
+	if int(index) >= len(leaves) {
+		return Proof{}, ErrOutOfRange
+	}
+
+	proof := Proof{Index: index, Count: uint32(len(leaves))}
+	level := hashLeaves(leaves)
+	for i := int(index); len(level) > 1; i /= 2 {
+		// The last node of an odd level has no sibling
+		if sibling := i ^ 1; sibling < len(level) {
+			proof.Path = append(proof.Path, level[sibling])
+		}
+
+		level = nextLevel(level)
+	}
+
+	return proof, nil
+}
+
+// Verify returns true if proof proves the inclusion of leaf in the tree with
+// the given root
+func Verify(root []byte, proof Proof, leaf []byte) bool {
+	if proof.Index >= proof.Count {
+		return false
+	}
+
+	h := hashLeaf(leaf)
+	path := proof.Path
+	for i, n := proof.Index, proof.Count; n > 1; i, n = i/2, (n+1)/2 {
+		if i%2 == 0 && i+1 == n {
+			// Carried up without a sibling
+			continue
+		}
+
+		if len(path) == 0 {
+			return false
+		}
+
+		if i%2 == 0 {
+			h = hashNode(h, path[0])
+		} else {
+			h = hashNode(path[0], h)
+		}
+		path = path[1:]
+	}
+
+	return len(path) == 0 && bytes.Equal(h, root)
+}
+
+func hashLeaves(leaves [][]byte) [][]byte {
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = hashLeaf(leaf)
+	}
+
+	return level
+}
+
+func nextLevel(level [][]byte) [][]byte {
+	next := make([][]byte, 0, (len(level)+1)/2)
+	for i := 0; i+1 < len(level); i += 2 {
+		next = append(next, hashNode(level[i], level[i+1]))
+	}
+
+	if len(level)%2 == 1 {
+		next = append(next, level[len(level)-1])
+	}
+
+	return next
+}
+
+func hashLeaf(leaf []byte) []byte {
+	return hash.HashInto(make([]byte, 0, hash.Size), []byte{leafPrefix}, leaf)
+}
+
+func hashNode(left, right []byte) []byte {
+	return hash.HashInto(make([]byte, 0, hash.Size), []byte{nodePrefix}, left, right)
+}
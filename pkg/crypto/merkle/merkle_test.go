@@ -0,0 +1,63 @@
+package merkle
+
+import (
+	"bytes"
+	"testing"
+)
+
+func leaves(n int) [][]byte {
+	l := make([][]byte, n)
+	for i := range l {
+		l[i] = []byte{byte(i)}
+	}
+	return l
+}
+
+func TestProveVerify(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		l := leaves(n)
+		root, err := Root(l)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := range l {
+			proof, err := Prove(l, uint32(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !Verify(root, proof, l[i]) {
+				t.Fatalf("valid proof of leaf %d/%d rejected", i, n)
+			}
+
+			// The proof is bound to the leaf and its position
+			if Verify(root, proof, []byte{0xff}) {
+				t.Fatalf("proof of leaf %d/%d accepted for another leaf", i, n)
+			}
+
+			proof.Index = (proof.Index + 1) % proof.Count
+			if n > 1 && Verify(root, proof, l[i]) {
+				t.Fatalf("proof of leaf %d/%d accepted at another position", i, n)
+			}
+		}
+	}
+}
+
+func TestRootSecondPreimage(t *testing.T) {
+	// An odd level does not pair its last node with itself, so appending a
+	// copy of the last leaf changes the root
+	a, _ := Root(leaves(3))
+	b, _ := Root(append(leaves(3), []byte{2}))
+	if bytes.Equal(a, b) {
+		t.Fatal("duplicated leaf does not change the root")
+	}
+
+	if _, err := Root(nil); err != ErrEmpty {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+
+	if _, err := Prove(leaves(2), 2); err != ErrOutOfRange {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+}
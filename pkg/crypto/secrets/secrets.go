@@ -0,0 +1,24 @@
+// Package secrets wipes secret key material once it is not needed anymore,
+// so that it does not linger in memory.
+package secrets
+
+import (
+	"github.com/dusk-network/dusk-wallet/key"
+)
+
+// Wipe overwrites b with zeroes.
+func Wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// WipeConsensusKeys wipes the Ed25519 secret key of k. The BLS secret key
+// can not be wiped, as dusk-crypto does not expose its content, and is
+// dropped instead.
+func WipeConsensusKeys(k *key.ConsensusKeys) {
+	if k.EdSecretKey != nil {
+		Wipe(*k.EdSecretKey)
+	}
+	k.BLSSecretKey = nil
+}
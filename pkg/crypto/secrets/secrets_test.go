@@ -0,0 +1,18 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-wallet/key"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWipeConsensusKeys(t *testing.T) {
+	k, err := key.NewRandConsensusKeys()
+	assert.NoError(t, err)
+
+	edKey := *k.EdSecretKey
+	WipeConsensusKeys(&k)
+	assert.Equal(t, make([]byte, len(edKey)), []byte(edKey))
+	assert.Nil(t, k.BLSSecretKey)
+}
@@ -0,0 +1,34 @@
+package devnet
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// The nodes produce blocks, and all follow the same chain. As the harness,
+// the test needs the blindbid executable and the password of the wallets:
+//
+//	DUSK_BLINDBID=/path/to/blindbid DUSK_WALLET_PASS=default go test ./pkg/devnet
+func TestDevnet(t *testing.T) {
+	blindBid := os.Getenv("DUSK_BLINDBID")
+	password := os.Getenv("DUSK_WALLET_PASS")
+	if blindBid == "" || password == "" {
+		t.Skip("DUSK_BLINDBID and DUSK_WALLET_PASS are needed to run the devnet")
+	}
+
+	n, err := Start(Config{
+		Wallets:     "../../harness/data",
+		Password:    password,
+		StepTimeout: 500 * time.Millisecond,
+		BlindBid:    blindBid,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Stop()
+
+	if err := n.WaitHeight(3, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,64 @@
+package devnet
+
+import (
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	log "github.com/sirupsen/logrus"
+)
+
+// DriverName is the database driver of the devnet nodes
+var DriverName = "devnet_v0.1.0"
+
+// driver opens in-memory databases, like the lite driver, but keeps one per
+// path: the components of a node opening the database of the node share it,
+// as they would with the heavy driver, while each node has its own.
+type driver struct {
+	lock sync.Mutex
+	dbs  map[string]database.DB
+}
+
+// Open returns the database of path, creating it on first use. A database
+// opened read-only shares the writable one.
+func (d *driver) Open(path string, network protocol.Magic, readonly bool) (database.DB, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if db, ok := d.dbs[path]; ok {
+		return db, nil
+	}
+
+	db, err := lite.NewDatabase(path, network, false)
+	if err != nil {
+		return nil, err
+	}
+
+	d.dbs[path] = db
+	return db, nil
+}
+
+// Close is a no-op, as the databases of the other nodes must stay open. They
+// are dropped with forget, once their node stopped.
+func (d *driver) Close() error {
+	return nil
+}
+
+func (d *driver) Name() string {
+	return DriverName
+}
+
+// forget drops the database of path
+func (d *driver) forget(path string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.dbs, path)
+}
+
+var dbs = &driver{dbs: make(map[string]database.DB)}
+
+func init() {
+	if err := database.Register(dbs); err != nil {
+		log.Panic(err)
+	}
+}
@@ -0,0 +1,31 @@
+package devnet
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+// The components of a node share its database, while each node has its own
+func TestDriver(t *testing.T) {
+	drvr, err := database.From(DriverName)
+	assert.NoError(t, err)
+
+	a, err := drvr.Open("node-0/chain", protocol.DevNet, false)
+	assert.NoError(t, err)
+	shared, err := drvr.Open("node-0/chain", protocol.DevNet, true)
+	assert.NoError(t, err)
+	b, err := drvr.Open("node-1/chain", protocol.DevNet, false)
+	assert.NoError(t, err)
+
+	assert.True(t, a == shared)
+	assert.False(t, a == b)
+
+	// Once forgotten, the database of a node starts empty again
+	dbs.forget("node-0/chain")
+	fresh, err := drvr.Open("node-0/chain", protocol.DevNet, false)
+	assert.NoError(t, err)
+	assert.False(t, a == fresh)
+}
@@ -0,0 +1,257 @@
+// Package devnet runs a network of nodes within a single process. Every node
+// has its own EventBus, RPCBus and in-memory database, and the nodes are
+// connected to each other over in-memory pipes, through the peer layer of the
+// full node. They run the real consensus, with short step timeouts, so that
+// blocks get produced at an accelerated rate, which makes the devnet suited to
+// integration tests and demos.
+//
+// The nodes are the ones of the test harness: each loads one of the wallet
+// files of harness/data, whose keys are the provisioners and bidders of the
+// genesis block of the devnet network. As in the harness, the proofs of blind
+// bid are computed by the blindbid process.
+package devnet
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "devnet")
+
+const (
+	// stopTimeout bounds the time waited for each component of a node to
+	// stop
+	stopTimeout = 10 * time.Second
+	// callTimeout bounds the time waited for a node to answer a call
+	callTimeout = 5 * time.Second
+	// blindBidDelay is the time given to the blindbid process to open its
+	// pipe
+	blindBidDelay = time.Second
+)
+
+// ErrNoWallets is returned when the wallets directory holds no wallet file
+var ErrNoWallets = errors.New("no wallet file found")
+
+// Config of a devnet
+type Config struct {
+	// Wallets is the directory of the wallet files, named wallet-*.dat. The
+	// nodes load them in the order of their names.
+	Wallets string
+	// Password of the wallet files
+	Password string
+	// Nodes to run, at most one per wallet file. Zero runs one per file.
+	Nodes int
+	// StepTimeout of the consensus. Zero means one second.
+	StepTimeout time.Duration
+	// CommitteeSize caps the reduction and agreement committees. Zero means
+	// the number of nodes.
+	CommitteeSize int
+	// BlindBid is the path of the blindbid executable, started along with
+	// the network. Empty if it runs already, with the same TMPDIR.
+	BlindBid string
+	// Dir holds the wallet databases of the nodes. Empty uses a temporary
+	// directory, removed on Stop.
+	Dir string
+}
+
+// Network is a running devnet
+type Network struct {
+	nodes    []*Node
+	blindBid *exec.Cmd
+	dir      string
+	tempDir  bool
+	// orig and origProfile are the configuration and the devnet profile
+	// restored on Stop
+	orig        cfg.Registry
+	origProfile cfg.Profile
+	stopOnce    sync.Once
+}
+
+// Start sets up the nodes, connects each of them to all the others, and
+// starts the consensus on all of them. The configuration of the process is
+// replaced by the one of the devnet until Stop.
+func Start(conf Config) (*Network, error) {
+	files, err := filepath.Glob(filepath.Join(conf.Wallets, "wallet-*.dat"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		return nil, ErrNoWallets
+	}
+
+	if conf.Nodes == 0 {
+		conf.Nodes = len(files)
+	}
+
+	if conf.Nodes > len(files) {
+		return nil, fmt.Errorf("%d nodes for %d wallet files", conf.Nodes, len(files))
+	}
+
+	n := &Network{dir: conf.Dir, orig: cfg.Get()}
+	if n.dir == "" {
+		if n.dir, err = ioutil.TempDir("", "devnet-"); err != nil {
+			return nil, err
+		}
+		n.tempDir = true
+	}
+
+	n.origProfile, _ = cfg.LookupProfile("devnet")
+	cfg.RegisterProfile("devnet", profile(n.origProfile, conf))
+
+	r := registry(n.orig, conf)
+	cfg.Mock(&r)
+
+	if err := n.start(conf, files[:conf.Nodes]); err != nil {
+		n.Stop()
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *Network) start(conf Config, files []string) error {
+	if conf.BlindBid != "" {
+		n.blindBid = exec.Command(conf.BlindBid)
+		if err := n.blindBid.Start(); err != nil {
+			n.blindBid = nil
+			return err
+		}
+		time.Sleep(blindBidDelay)
+	}
+
+	for i, file := range files {
+		nd, err := newNode(i, filepath.Join(n.dir, fmt.Sprintf("node-%d", i)), file, conf.Password)
+		if err != nil {
+			return fmt.Errorf("node %d: %v", i, err)
+		}
+
+		if err := nd.supervisor.Start(); err != nil {
+			nd.stop()
+			return fmt.Errorf("node %d: %v", i, err)
+		}
+		n.nodes = append(n.nodes, nd)
+	}
+
+	for i, a := range n.nodes {
+		for _, b := range n.nodes[i+1:] {
+			if err := a.connect(b); err != nil {
+				return fmt.Errorf("connecting node %d to node %d: %v", a.Index, b.Index, err)
+			}
+		}
+	}
+
+	for _, nd := range n.nodes {
+		nd.startConsensus()
+	}
+
+	lg.WithField("nodes", len(n.nodes)).Infoln("devnet started")
+	return nil
+}
+
+// profile returns the network profile of the devnet, based on orig. The
+// committees are sized after the nodes, so that they can reach quorum.
+func profile(orig cfg.Profile, conf Config) cfg.Profile {
+	p := orig
+
+	size := conf.CommitteeSize
+	if size == 0 {
+		size = conf.Nodes
+	}
+
+	if size > cfg.MaxCommitteeSize {
+		size = cfg.MaxCommitteeSize
+	}
+	p.ReductionCommitteeSize = size
+	p.AgreementCommitteeSize = size
+	return p
+}
+
+// registry returns the configuration of the devnet, based on orig
+func registry(orig cfg.Registry, conf Config) cfg.Registry {
+	r := orig
+	r.General.Network = "devnet"
+	r.General.WalletOnly = false
+	r.Database.Driver = DriverName
+
+	stepTimeout := conf.StepTimeout
+	if stepTimeout == 0 {
+		stepTimeout = time.Second
+	}
+	r.Consensus.StepTimeout = uint32(stepTimeout / time.Millisecond)
+	r.Consensus.MaxStepTimeout = 0
+
+	// The nodes are only reached through the devnet
+	r.RPC.Enabled = false
+	r.Gql.Enabled = false
+	r.Metrics.Enabled = false
+	r.Bridge.Enabled = false
+
+	if r.Mempool.MaxSizeMB == 0 {
+		r.Mempool.MaxSizeMB = 100
+	}
+
+	if r.Mempool.PoolType == "" {
+		r.Mempool.PoolType = "hashmap"
+	}
+
+	if r.Mempool.PreallocTxs == 0 {
+		r.Mempool.PreallocTxs = 100
+	}
+	return r
+}
+
+// Nodes returns the nodes of the network, in the order of their wallet files
+func (n *Network) Nodes() []*Node {
+	return n.nodes
+}
+
+// WaitHeight blocks until every node reached height, or timeout elapsed
+func (n *Network) WaitHeight(height uint64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for _, nd := range n.nodes {
+		for {
+			h, err := nd.Height()
+			if err == nil && h >= height {
+				break
+			}
+
+			if time.Now().After(deadline) {
+				return fmt.Errorf("node %d at height %d after %s, instead of %d", nd.Index, h, timeout, height)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	return nil
+}
+
+// Stop the nodes and the blindbid process, and restore the configuration
+func (n *Network) Stop() {
+	n.stopOnce.Do(func() {
+		for _, nd := range n.nodes {
+			nd.stop()
+		}
+
+		if n.blindBid != nil {
+			_ = n.blindBid.Process.Kill()
+			_ = n.blindBid.Wait()
+		}
+
+		if n.tempDir {
+			_ = os.RemoveAll(n.dir)
+		}
+
+		cfg.Mock(&n.orig)
+		cfg.RegisterProfile("devnet", n.origProfile)
+	})
+}
@@ -0,0 +1,345 @@
+package devnet
+
+import (
+	"bytes"
+	"net"
+	"path/filepath"
+	"sync"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/candidate"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/initiator"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/dusk-network/dusk-blockchain/pkg/node"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/dupemap"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing/chainsync"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/block"
+	walletdb "github.com/dusk-network/dusk-wallet/database"
+	"github.com/dusk-network/dusk-wallet/transactions"
+	"github.com/dusk-network/dusk-wallet/wallet"
+)
+
+// testnet is the network prefix of the wallet addresses
+const testnet = byte(2)
+
+// cfgLock serializes the setup of the nodes, see withConfig
+var cfgLock sync.Mutex
+
+// Node is one of the nodes of the devnet. It runs the components of a full
+// node, along with the consensus, on its own EventBus, RPCBus and database.
+type Node struct {
+	// Index of the node in the network, and of its wallet file
+	Index    int
+	EventBus *eventbus.EventBus
+	RPCBus   *rpcbus.RPCBus
+
+	dir      string
+	db       database.DB
+	counter  *chainsync.Counter
+	dupeMap  *dupemap.DupeMap
+	gossip   *processing.Gossip
+	peers    *peer.Registry
+	walletDB *walletdb.DB
+	wallet   *wallet.Wallet
+
+	chain           *chain.Chain
+	mempool         *mempool.Mempool
+	candidateBroker *candidate.Broker
+	supervisor      *node.Supervisor
+
+	lock  sync.Mutex
+	conns []net.Conn
+	quit  chan struct{}
+}
+
+// newNode sets up the components of a node, storing its data under dir, and
+// loads its wallet from file
+func newNode(index int, dir, file, password string) (*Node, error) {
+	n := &Node{
+		Index:      index,
+		EventBus:   eventbus.New(),
+		RPCBus:     rpcbus.New(),
+		dir:        dir,
+		dupeMap:    dupemap.NewDupeMap(1),
+		peers:      peer.NewRegistry(),
+		supervisor: node.New(node.Config{StopTimeout: stopTimeout}),
+		quit:       make(chan struct{}),
+	}
+
+	// Components keep track of the chain state through these topics, so
+	// they must be observed in order
+	n.EventBus.SetOrdered(topics.RoundUpdate)
+	n.EventBus.SetOrdered(topics.AcceptedBlock)
+	n.counter = chainsync.NewCounter(n.EventBus)
+
+	var err error
+	n.withConfig(func() {
+		n.EventBus.EnableJournal(topics.RoundUpdate, int(cfg.Get().Performance.RoundUpdateJournal))
+		n.gossip = processing.NewGossip(protocol.MagicFromConfig())
+		if n.chain, err = chain.New(n.EventBus, n.RPCBus, n.counter); err != nil {
+			return
+		}
+
+		if n.db, err = dbs.Open(n.dbDir(), protocol.MagicFromConfig(), false); err != nil {
+			return
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := n.peers.Listen(n.RPCBus); err != nil {
+		return nil, err
+	}
+
+	n.mempool = mempool.NewMempool(n.EventBus, n.RPCBus, n.verifyTx)
+	n.candidateBroker = candidate.NewBroker(n.EventBus, n.RPCBus)
+
+	if n.walletDB, err = walletdb.New(filepath.Join(dir, "walletDB")); err != nil {
+		return nil, err
+	}
+
+	// The wallet only provides the keys of the node, so it never fetches
+	// decoys nor inputs
+	if n.wallet, err = wallet.LoadFromFile(testnet, n.walletDB, nil, nil, password, file); err != nil {
+		n.walletDB.Close()
+		return nil, err
+	}
+
+	n.addSubsystems()
+	return n, nil
+}
+
+// withConfig runs fn with the database of the node configured. The
+// components open their database from the global configuration, so the ones
+// doing it are created one node at a time.
+func (n *Node) withConfig(fn func()) {
+	cfgLock.Lock()
+	defer cfgLock.Unlock()
+	orig := cfg.Get()
+	r := orig
+	r.Database.Dir = n.dbDir()
+	cfg.Mock(&r)
+	defer cfg.Mock(&orig)
+	fn()
+}
+
+// dbDir is the path of the in-memory database of the node
+func (n *Node) dbDir() string {
+	return filepath.Join(n.dir, "chain")
+}
+
+// verifyTx checks the txs entering the mempool against the database of the
+// node, for the block after the tip
+func (n *Node) verifyTx(tx transactions.Transaction) error {
+	var height uint64
+	if err := n.db.View(func(t database.Transaction) error {
+		var err error
+		height, err = t.FetchCurrentHeight()
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return verifiers.CheckTx(n.db, height+1, 0, uint64(time.Now().Unix()), tx)
+}
+
+// addSubsystems hands the components over to the supervisor of the node, in
+// the order of the full node
+func (n *Node) addSubsystems() {
+	n.supervisor.Add(node.Subsystem{Name: "eventbus", Stop: n.EventBus.Close})
+	n.supervisor.Add(node.Subsystem{
+		Name: "rpcbus",
+		Stop: func() error {
+			n.RPCBus.Close()
+			return nil
+		},
+	})
+	n.supervisor.Add(node.Subsystem{Name: "chain", Run: n.chain.Listen, Stop: n.chain.Close})
+	n.supervisor.Add(node.Subsystem{
+		Name: "mempool",
+		Run:  n.mempool.Listen,
+		Stop: func() error {
+			n.mempool.Stop()
+			return nil
+		},
+	})
+	n.supervisor.Add(node.Subsystem{
+		Name: "candidate",
+		Run:  n.candidateBroker.Listen,
+		Stop: func() error {
+			n.candidateBroker.Close()
+			return nil
+		},
+	})
+	n.supervisor.Add(node.Subsystem{
+		Name:  "dupemap",
+		Start: n.trackHeight,
+		Stop: func() error {
+			close(n.quit)
+			return nil
+		},
+	})
+	n.supervisor.Add(node.Subsystem{Name: "peers", Stop: n.disconnect})
+	n.supervisor.Add(node.Subsystem{
+		Name: "consensus",
+		Stop: func() error {
+			n.EventBus.Publish(topics.Quit, new(bytes.Buffer))
+			return nil
+		},
+	})
+}
+
+// trackHeight moves the window of the duplicate messages along with the
+// chain, as the server of a full node does
+func (n *Node) trackHeight() error {
+	blocks, id := consensus.InitAcceptedBlockUpdate(n.EventBus)
+	go func() {
+		defer n.EventBus.Unsubscribe(topics.AcceptedBlock, id)
+		for {
+			select {
+			case blk := <-blocks:
+				n.dupeMap.UpdateHeight(blk.Header.Height)
+			case <-n.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// startConsensus stores the bid values of the wallet and starts the
+// consensus, as the transactor of a full node does once the wallet is loaded
+func (n *Node) startConsensus() {
+	n.withConfig(func() {
+		initiator.LaunchConsensus(n.EventBus, n.RPCBus, n.wallet, n.counter)
+	})
+}
+
+// connect opens a connection between n and the peer, over an in-memory pipe.
+// n dials, and the peer accepts.
+func (n *Node) connect(p *Node) error {
+	local, remote := net.Pipe()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- p.accept(remote)
+	}()
+
+	if err := n.dial(local); err != nil {
+		_ = local.Close()
+		_ = remote.Close()
+		<-errChan
+		return err
+	}
+	return <-errChan
+}
+
+// accept performs the handshake of an inbound connection, and serves it, as
+// the server of a full node does
+func (n *Node) accept(conn net.Conn) error {
+	writeQueueChan := make(chan *bytes.Buffer, 1000)
+	exitChan := make(chan struct{}, 1)
+	r, err := n.newReader(conn, writeQueueChan, exitChan)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Accept(); err != nil {
+		return err
+	}
+
+	w := peer.NewWriter(conn, n.gossip, n.EventBus)
+	w.SetServices(r.Services())
+	r.StreamTo(w)
+	go r.ReadLoop()
+	n.serve(w, conn, true, writeQueueChan, exitChan)
+	return nil
+}
+
+// dial performs the handshake of an outbound connection, and serves it
+func (n *Node) dial(conn net.Conn) error {
+	writeQueueChan := make(chan *bytes.Buffer, 1000)
+	exitChan := make(chan struct{}, 1)
+	w := peer.NewWriter(conn, n.gossip, n.EventBus)
+	r, err := n.newReader(conn, writeQueueChan, exitChan)
+	if err != nil {
+		return err
+	}
+
+	if err := w.Connect(); err != nil {
+		return err
+	}
+
+	r.StreamTo(w)
+	go r.ReadLoop()
+	n.serve(w, conn, false, writeQueueChan, exitChan)
+	return nil
+}
+
+// newReader creates the reader of a connection, which opens the database of
+// the node
+func (n *Node) newReader(conn net.Conn, writeQueueChan chan *bytes.Buffer, exitChan chan struct{}) (*peer.Reader, error) {
+	var r *peer.Reader
+	var err error
+	n.withConfig(func() {
+		r, err = peer.NewReader(conn, n.gossip, n.dupeMap, n.EventBus, n.RPCBus, n.counter, writeQueueChan, exitChan)
+	})
+	return r, err
+}
+
+func (n *Node) serve(w *peer.Writer, conn net.Conn, inbound bool, writeQueueChan chan *bytes.Buffer, exitChan chan struct{}) {
+	n.lock.Lock()
+	n.conns = append(n.conns, conn)
+	n.lock.Unlock()
+
+	addr := w.Addr()
+	n.peers.Add(addr, inbound, conn, writeQueueChan)
+	go func() {
+		defer n.peers.Remove(addr)
+		w.Serve(writeQueueChan, exitChan)
+	}()
+}
+
+// disconnect closes the connections to the peers
+func (n *Node) disconnect() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	for _, conn := range n.conns {
+		_ = conn.Close()
+	}
+	n.conns = nil
+	return nil
+}
+
+// Height returns the height of the tip of the chain of the node
+func (n *Node) Height() (uint64, error) {
+	buf, err := n.RPCBus.Call(rpcbus.GetLastBlock, rpcbus.NewRequest(bytes.Buffer{}), callTimeout)
+	if err != nil {
+		return 0, err
+	}
+
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&buf, blk); err != nil {
+		return 0, err
+	}
+	return blk.Header.Height, nil
+}
+
+// stop the components of the node, and drop its database
+func (n *Node) stop() {
+	n.supervisor.Stop()
+	n.walletDB.Close()
+	dbs.forget(n.dbDir())
+}
@@ -0,0 +1,242 @@
+// Package eventbridge forwards EventBus topics to external processes, such as
+// wallets, indexers or monitoring agents, over a stream socket.
+//
+// Once connected, a client selects the topics it is interested in by sending
+// two-byte commands, made of the command code and the topic. The bridge then
+// streams every message published on those topics as a frame made of a
+// 4-byte little-endian length, followed by the topic and the payload.
+//
+// Each client gets a bounded queue. When a client can not keep up, new
+// messages are dropped rather than stalling the node, and the amount of
+// dropped messages is logged.
+package eventbridge
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "eventbridge")
+
+const (
+	// CmdSubscribe starts forwarding a topic to the client
+	CmdSubscribe byte = 0x01
+	// CmdUnsubscribe stops forwarding a topic to the client
+	CmdUnsubscribe byte = 0x02
+)
+
+// queueSize is the amount of messages buffered for each client
+const queueSize = 1000
+
+// writeTimeout bounds the time spent writing a single frame to a client
+var writeTimeout = 5 * time.Second
+
+// ErrTopicNotAllowed is logged when a client subscribes to a topic which is
+// not exposed by the bridge
+var ErrTopicNotAllowed = errors.New("topic is not exposed by the bridge")
+
+// Bridge accepts external clients and forwards them the topics they
+// subscribe to.
+type Bridge struct {
+	bus      eventbus.Multicaster
+	allowed  map[topics.Topic]bool
+	listener net.Listener
+
+	lock    sync.Mutex
+	clients map[*client]struct{}
+}
+
+// New creates a Bridge exposing the given topics through the listener l.
+// Clients can only subscribe to the exposed topics.
+func New(bus eventbus.Multicaster, l net.Listener, exposed []topics.Topic) *Bridge {
+	allowed := make(map[topics.Topic]bool)
+	for _, topic := range exposed {
+		allowed[topic] = true
+	}
+
+	return &Bridge{
+		bus:      bus,
+		allowed:  allowed,
+		listener: l,
+		clients:  make(map[*client]struct{}),
+	}
+}
+
+// Listen on the given unix socket and serve clients in a goroutine. The socket
+// left behind by a node which did not shut down cleanly is removed first.
+func Listen(bus eventbus.Multicaster, address string, exposed []topics.Topic) (*Bridge, error) {
+	if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	l, err := net.Listen("unix", address)
+	if err != nil {
+		return nil, err
+	}
+
+	b := New(bus, l, exposed)
+	go b.Serve()
+	return b, nil
+}
+
+// Serve accepts clients until the Bridge is closed.
+func (b *Bridge) Serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			lg.WithError(err).Debugln("bridge listener closed")
+			return
+		}
+
+		c := b.newClient(conn)
+		b.lock.Lock()
+		b.clients[c] = struct{}{}
+		b.lock.Unlock()
+
+		go c.writeLoop()
+		go func() {
+			c.readLoop()
+			b.remove(c)
+		}()
+	}
+}
+
+// Close the Bridge, disconnecting all clients.
+func (b *Bridge) Close() error {
+	err := b.listener.Close()
+	b.lock.Lock()
+	clients := b.clients
+	b.clients = make(map[*client]struct{})
+	b.lock.Unlock()
+
+	for c := range clients {
+		c.close()
+	}
+
+	return err
+}
+
+func (b *Bridge) remove(c *client) {
+	b.lock.Lock()
+	delete(b.clients, c)
+	b.lock.Unlock()
+	c.close()
+}
+
+type client struct {
+	bridge *Bridge
+	conn   net.Conn
+	queue  *eventbus.QueueListener
+	id     uint32
+
+	lock   sync.RWMutex
+	topics map[topics.Topic]bool
+	quit   chan struct{}
+	once   sync.Once
+}
+
+func (b *Bridge) newClient(conn net.Conn) *client {
+	c := &client{
+		bridge: b,
+		conn:   conn,
+		queue:  eventbus.NewQueueListener(eventbus.QueueConfig{Size: queueSize, Policy: eventbus.DropNew}),
+		topics: make(map[topics.Topic]bool),
+		quit:   make(chan struct{}),
+	}
+
+	c.id = b.bus.SubscribeMatching(c.wants, c.queue)
+	return c
+}
+
+func (c *client) wants(topic topics.Topic) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.topics[topic]
+}
+
+// readLoop processes the subscription commands of the client.
+func (c *client) readLoop() {
+	r := bufio.NewReader(c.conn)
+	cmd := make([]byte, 2)
+	for {
+		if _, err := io.ReadFull(r, cmd); err != nil {
+			return
+		}
+
+		topic := topics.Topic(cmd[1])
+		if !c.bridge.allowed[topic] {
+			lg.WithError(ErrTopicNotAllowed).WithField("topic", topic.String()).Warnln("refusing subscription")
+			continue
+		}
+
+		c.lock.Lock()
+		switch cmd[0] {
+		case CmdSubscribe:
+			c.topics[topic] = true
+		case CmdUnsubscribe:
+			delete(c.topics, topic)
+		default:
+			lg.WithField("command", cmd[0]).Warnln("unknown bridge command")
+		}
+		c.lock.Unlock()
+	}
+}
+
+// writeLoop forwards the queued messages to the client.
+func (c *client) writeLoop() {
+	w := bufio.NewWriter(c.conn)
+	var reported uint64
+	header := make([]byte, 4)
+	for {
+		var m bytes.Buffer
+		select {
+		case m = <-c.queue.Chan():
+		case <-c.quit:
+			return
+		}
+
+		if dropped := c.queue.Dropped(); dropped > reported {
+			lg.WithField("dropped", dropped-reported).Warnln("bridge client is too slow, messages dropped")
+			reported = dropped
+		}
+
+		binary.LittleEndian.PutUint32(header, uint32(m.Len()))
+		_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := w.Write(header); err != nil {
+			c.bridge.remove(c)
+			return
+		}
+
+		if _, err := w.Write(m.Bytes()); err != nil {
+			c.bridge.remove(c)
+			return
+		}
+
+		// Flush only once the queue is empty, to batch writes under load
+		if len(c.queue.Chan()) == 0 {
+			if err := w.Flush(); err != nil {
+				c.bridge.remove(c)
+				return
+			}
+		}
+	}
+}
+
+func (c *client) close() {
+	c.once.Do(func() {
+		c.bridge.bus.UnsubscribeMatching(c.id)
+		close(c.quit)
+		_ = c.conn.Close()
+	})
+}
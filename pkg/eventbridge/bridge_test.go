@@ -0,0 +1,89 @@
+package eventbridge
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBridge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bridge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bus := eventbus.New()
+	addr := filepath.Join(dir, "bridge.sock")
+	b, err := Listen(bus, addr, []topics.Topic{topics.Test})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	conn, err := net.Dial("unix", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Tx is not exposed, so only Test should come through
+	if _, err := conn.Write([]byte{CmdSubscribe, byte(topics.Tx), CmdSubscribe, byte(topics.Test)}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	bus.Publish(topics.Tx, bytes.NewBufferString("ignored"))
+	bus.Publish(topics.Test, bytes.NewBufferString("pluto"))
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		t.Fatal(err)
+	}
+
+	frame := make([]byte, binary.LittleEndian.Uint32(header))
+	if _, err := io.ReadFull(conn, frame); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, byte(topics.Test), frame[0])
+	assert.Equal(t, "pluto", string(frame[1:]))
+}
+
+// Test that the socket left behind by a previous run does not prevent
+// listening
+func TestListenStaleSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bridge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	addr := filepath.Join(dir, "bridge.sock")
+	if err := ioutil.WriteFile(addr, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Listen(eventbus.New(), addr, []topics.Topic{topics.Test})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	conn, err := net.Dial("unix", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+}
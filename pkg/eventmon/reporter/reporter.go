@@ -0,0 +1,319 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/bits"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-wallet/block"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "reporter")
+
+// sendTimeout bounds the time spent shipping a report, so that an
+// unreachable collector does not delay the next ones
+const sendTimeout = 5 * time.Second
+
+// Report is the health of the node over an interval, as shipped to the
+// collector. Averages are zero when nothing was observed during the interval.
+type Report struct {
+	Node string `json:"node"`
+	// Time is the end of the interval, in unix seconds
+	Time int64 `json:"time"`
+	// Interval is the length of the interval, in seconds
+	Interval float64 `json:"interval"`
+	Height   uint64  `json:"height"`
+	Round    uint64  `json:"round"`
+	Blocks   int     `json:"blocks"`
+	// BlockTime is the average time between the timestamps of consecutive
+	// blocks, in seconds
+	BlockTime float64 `json:"blockTime"`
+	// RoundTime is the average time between consecutive rounds as seen by
+	// the node, in seconds
+	RoundTime float64 `json:"roundTime"`
+	// Participation is the average share of the reduction committees which
+	// voted for the blocks accepted
+	Participation float64 `json:"participation"`
+	// Errors counts the messages rejected by topic, and the errors reported
+	// on the Monitor topic
+	Errors map[string]uint64 `json:"errors"`
+}
+
+// Reporter aggregates the round results, accepted blocks and errors seen on
+// the event bus, and ships a Report to a remote collector every interval
+type Reporter struct {
+	multicaster eventbus.Multicaster
+	node        string
+	interval    time.Duration
+	// committee is the size of each reduction committee
+	committee  int
+	sender     sender
+	listenerID uint32
+	quit       chan struct{}
+
+	lock  sync.Mutex
+	start time.Time
+	// last block and round seen, carried over intervals
+	lastBlock *block.Header
+	lastRound time.Time
+	report    Report
+	// sums of the averages of the report
+	blockTimes    float64
+	blockTimeN    int
+	roundTimes    float64
+	roundTimeN    int
+	participation float64
+}
+
+// New creates a Reporter shipping its reports to address, which is either
+// an http(s) URL to POST the reports to, or a UDP address as in
+// "udp://host:port" or "host:port". node identifies the node in the reports.
+func New(multicaster eventbus.Multicaster, address, node string, interval time.Duration, committee int) (*Reporter, error) {
+	s, err := newSender(address)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reporter{
+		multicaster: multicaster,
+		node:        node,
+		interval:    interval,
+		committee:   committee,
+		sender:      s,
+		quit:        make(chan struct{}),
+		start:       time.Now(),
+		report:      Report{Errors: make(map[string]uint64)},
+	}
+
+	l := eventbus.NewCallbackListener(r.collect)
+	r.listenerID = multicaster.SubscribeMulti(l, topics.RoundUpdate, topics.AcceptedBlock, topics.RejectedMessage, topics.Monitor)
+	return r, nil
+}
+
+// collect dispatches the messages, prepended with their topic, to the
+// handler of the topic
+func (r *Reporter) collect(b bytes.Buffer) error {
+	topic, err := topics.Extract(&b)
+	if err != nil {
+		return err
+	}
+
+	switch topic {
+	case topics.RoundUpdate:
+		return r.onRound(b)
+	case topics.AcceptedBlock:
+		return r.onBlock(b)
+	case topics.RejectedMessage:
+		return r.onRejection(b)
+	case topics.Monitor:
+		return r.onError(b)
+	}
+	return nil
+}
+
+// Listen ships a report every interval, until Stop is called
+func (r *Reporter) Listen() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			report := r.flush(time.Now())
+			if err := r.send(report); err != nil {
+				lg.WithError(err).Warnln("could not ship the report")
+			}
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// Stop unsubscribes the Reporter and stops shipping reports
+func (r *Reporter) Stop() {
+	r.multicaster.UnsubscribeMatching(r.listenerID)
+	close(r.quit)
+	_ = r.sender.Close()
+}
+
+func (r *Reporter) onRound(b bytes.Buffer) error {
+	update := consensus.RoundUpdate{}
+	if err := consensus.DecodeRound(&b, &update); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.lastRound.IsZero() && update.Round == r.report.Round+1 {
+		r.roundTimes += now.Sub(r.lastRound).Seconds()
+		r.roundTimeN++
+	}
+	r.lastRound = now
+	r.report.Round = update.Round
+	return nil
+}
+
+func (r *Reporter) onBlock(b bytes.Buffer) error {
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&b, blk); err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	header := blk.Header
+	if r.lastBlock != nil && header.Height == r.lastBlock.Height+1 {
+		r.blockTimes += float64(header.Timestamp - r.lastBlock.Timestamp)
+		r.blockTimeN++
+	}
+	r.lastBlock = header
+	r.report.Height = header.Height
+	r.report.Blocks++
+	r.participation += r.participationOf(header)
+	return nil
+}
+
+// participationOf returns the share of the reduction committees which voted
+// for the block of header
+func (r *Reporter) participationOf(header *block.Header) float64 {
+	if header.Certificate == nil || r.committee == 0 {
+		return 0
+	}
+
+	votes := bits.OnesCount64(header.Certificate.StepOneCommittee) + bits.OnesCount64(header.Certificate.StepTwoCommittee)
+	return float64(votes) / float64(2*r.committee)
+}
+
+func (r *Reporter) onRejection(b bytes.Buffer) error {
+	rejection := eventbus.Rejection{}
+	if err := rejection.Decode(&b); err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.report.Errors[rejection.Topic.String()]++
+	return nil
+}
+
+func (r *Reporter) onError(bytes.Buffer) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.report.Errors[topics.Monitor.String()]++
+	return nil
+}
+
+// flush returns the report of the interval ending at now, and starts the
+// next one
+func (r *Reporter) flush(now time.Time) Report {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	report := r.report
+	report.Node = r.node
+	report.Time = now.Unix()
+	report.Interval = now.Sub(r.start).Seconds()
+	if r.blockTimeN > 0 {
+		report.BlockTime = r.blockTimes / float64(r.blockTimeN)
+	}
+	if r.roundTimeN > 0 {
+		report.RoundTime = r.roundTimes / float64(r.roundTimeN)
+	}
+	if report.Blocks > 0 {
+		report.Participation = r.participation / float64(report.Blocks)
+	}
+
+	r.start = now
+	r.report = Report{Height: report.Height, Round: report.Round, Errors: make(map[string]uint64)}
+	r.blockTimes, r.blockTimeN = 0, 0
+	r.roundTimes, r.roundTimeN = 0, 0
+	r.participation = 0
+	return report
+}
+
+func (r *Reporter) send(report Report) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return r.sender.Send(payload)
+}
+
+// sender ships the encoded reports to the collector
+type sender interface {
+	Send(payload []byte) error
+	Close() error
+}
+
+func newSender(address string) (sender, error) {
+	uri, err := url.Parse(address)
+	if err != nil || uri.Host == "" {
+		// Plain host:port addresses are UDP collectors
+		return newUDPSender(address)
+	}
+
+	switch uri.Scheme {
+	case "udp":
+		return newUDPSender(uri.Host)
+	case "http", "https":
+		return &httpSender{url: address, client: &http.Client{Timeout: sendTimeout}}, nil
+	default:
+		return nil, errors.New("unsupported collector scheme " + uri.Scheme)
+	}
+}
+
+// udpSender sends each report in a datagram
+type udpSender struct {
+	conn net.Conn
+}
+
+func newUDPSender(address string) (*udpSender, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	return &udpSender{conn}, nil
+}
+
+func (u *udpSender) Send(payload []byte) error {
+	_ = u.conn.SetWriteDeadline(time.Now().Add(sendTimeout))
+	_, err := u.conn.Write(payload)
+	return err
+}
+
+func (u *udpSender) Close() error {
+	return u.conn.Close()
+}
+
+// httpSender POSTs each report as JSON
+type httpSender struct {
+	url    string
+	client *http.Client
+}
+
+func (h *httpSender) Send(payload []byte) error {
+	resp, err := h.client.Post(h.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return errors.New("collector replied " + resp.Status)
+	}
+	return nil
+}
+
+func (h *httpSender) Close() error {
+	return nil
+}
@@ -0,0 +1,63 @@
+package reporter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/eventmon/reporter"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReportOverUDP checks that the blocks and rejections seen during an
+// interval are shipped to a UDP collector
+func TestReportOverUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	bus := eventbus.New()
+	r, err := reporter.New(bus, "udp://"+conn.LocalAddr().String(), "node", 100*time.Millisecond, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	for i, height := range []uint64{10, 11} {
+		blk := helper.RandomBlock(t, height, 1)
+		blk.Header.Timestamp = int64(1000 + 10*i)
+		buf := new(bytes.Buffer)
+		if err := marshalling.MarshalBlock(buf, blk); err != nil {
+			t.Fatal(err)
+		}
+		bus.Publish(topics.AcceptedBlock, buf)
+	}
+	eventbus.PublishRejection(bus, eventbus.Rejection{Topic: topics.Tx, Reason: "invalid"})
+	go r.Listen()
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	payload := make([]byte, 65536)
+	n, _, err := conn.ReadFrom(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var report reporter.Report
+	if err := json.Unmarshal(payload[:n], &report); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "node", report.Node)
+	assert.Equal(t, uint64(11), report.Height)
+	assert.Equal(t, 2, report.Blocks)
+	assert.Equal(t, float64(10), report.BlockTime)
+	assert.Equal(t, uint64(1), report.Errors[topics.Tx.String()])
+}
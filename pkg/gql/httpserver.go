@@ -9,8 +9,10 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
 	"github.com/dusk-network/dusk-blockchain/pkg/gql/query"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/wshub"
 	"github.com/graphql-go/graphql"
 
 	logger "github.com/sirupsen/logrus"
@@ -32,6 +34,9 @@ type Server struct {
 	startTime int64
 
 	schema *graphql.Schema
+
+	subscriptions *wshub.Hub
+	blockListener uint32
 }
 
 // NewHTTPServer instantiates a new NewHTTPServer to handle GraphQL queries.
@@ -77,10 +82,16 @@ func (s *Server) Start() error {
 		}
 	})
 
+	// GraphQL subscriptions over websocket
+	ServeMux.Handle("/ws", s.newWebsocketHandler())
+
 	//  Setup graphQL
 	rootQuery := query.NewRoot(s.rpcBus)
 	sc, err := graphql.NewSchema(
-		graphql.SchemaConfig{Query: rootQuery.Query},
+		graphql.SchemaConfig{
+			Query:        rootQuery.Query,
+			Subscription: rootQuery.Subscription,
+		},
 	)
 
 	if err != nil {
@@ -90,6 +101,9 @@ func (s *Server) Start() error {
 	s.schema = &sc
 	_, s.db = heavy.CreateDBConnection()
 
+	s.subscriptions = wshub.New(cfg.Get().Gql.MaxSubscribers)
+	s.blockListener = s.eventBus.Subscribe(topics.AcceptedBlock, eventbus.NewCallbackListener(s.onAcceptedBlock))
+
 	// Set up listener
 	l, err := net.Listen("tcp", "localhost:"+cfg.Get().Gql.Port)
 	if err != nil {
@@ -122,6 +136,7 @@ func (s *Server) listenOnHTTPServer(httpServer *http.Server) {
 // Stop the server
 func (s *Server) Stop() error {
 	s.started = false
+	s.eventBus.Unsubscribe(topics.AcceptedBlock, s.blockListener)
 	if err := s.listener.Close(); err != nil {
 		log.Errorf("error shutting down, %v\n", err)
 		return err
@@ -3,6 +3,7 @@ package query
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
@@ -11,12 +12,16 @@ import (
 )
 
 const (
+	// blocksFetchLimit caps the amount of blocks returned by a single query
+	blocksFetchLimit = 1000
+
 	blockHashArg   = "hash"
 	blockHashesArg = "hashes"
 	blockHeightArg = "height"
 	blockRangeArg  = "range"
 	blockLastArg   = "last"
 	blockSinceArg  = "since"
+	blockOffsetArg = "offset"
 )
 
 // File purpose is to define all arguments and resolvers relevant to "blocks" query only
@@ -46,6 +51,12 @@ func (b blocks) getQuery() *graphql.Field {
 			blockSinceArg: &graphql.ArgumentConfig{
 				Type: graphql.DateTime,
 			},
+			// offset skips the most recent blocks when used along with
+			// `last`, allowing to page backwards through the chain
+			blockOffsetArg: &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 0,
+			},
 		},
 		Resolve: b.resolve,
 	}
@@ -100,7 +111,13 @@ func (b blocks) resolve(p graphql.ResolveParams) (interface{}, error) {
 		if offset <= 0 {
 			return nil, errors.New("invalid offset")
 		}
-		return b.fetchBlocksByHeights(db, int64(offset)*-1, -1)
+
+		skip, _ := p.Args[blockOffsetArg].(int)
+		if skip < 0 {
+			return nil, errors.New("invalid offset")
+		}
+
+		return b.fetchBlocksByHeights(db, int64(offset+skip)*-1, int64(skip+1)*-1)
 	}
 
 	date, ok := p.Args[blockSinceArg].(time.Time)
@@ -117,6 +134,17 @@ func resolveTxs(p graphql.ResolveParams) (interface{}, error) {
 	b, ok := p.Source.(*block.Block)
 	if ok {
 
+		// Blocks pushed to subscribers come with their txs already
+		if len(b.Txs) > 0 {
+			for _, tx := range b.Txs {
+				d, err := newQueryTx(tx, b.Header.Hash)
+				if err == nil {
+					txs = append(txs, d)
+				}
+			}
+			return txs, nil
+		}
+
 		// Retrieve DB conn from context
 		db, ok := p.Context.Value("database").(database.DB)
 		if !ok {
@@ -178,7 +206,8 @@ func (b blocks) fetchBlocksByHashes(db database.DB, hashes []interface{}) ([]*bl
 	return blocks, err
 }
 
-// Fetch block headers by a range of heights
+// Fetch block headers by a range of heights. Negative heights are relative to
+// the chain tip, -1 being the tip itself.
 func (b blocks) fetchBlocksByHeights(db database.DB, from, to int64) ([]*block.Block, error) {
 
 	blocks := make([]*block.Block, 0)
@@ -193,10 +222,6 @@ func (b blocks) fetchBlocksByHeights(db database.DB, from, to int64) ([]*block.B
 			}
 		}
 
-		if from == -1 {
-			from = int64(tip)
-		}
-
 		// For cases where last N blocks are required
 		if from < 0 {
 			from = int64(tip) + from + 1
@@ -205,8 +230,12 @@ func (b blocks) fetchBlocksByHeights(db database.DB, from, to int64) ([]*block.B
 			}
 		}
 
-		if to == -1 {
-			to = int64(tip)
+		if to < 0 {
+			to = int64(tip) + to + 1
+		}
+
+		if to-from >= blocksFetchLimit {
+			return fmt.Errorf("requested blocks count exceeds the limit of %d", blocksFetchLimit)
 		}
 
 		for height := from; height <= to; height++ {
@@ -194,6 +194,37 @@ func TestLastBlocks(t *testing.T) {
 	assertQuery(t, query, response)
 }
 
+func TestLastBlocksOffset(t *testing.T) {
+	query := `
+		{
+		  blocks(last: 2, offset: 1) {
+			header {
+			   height
+			}
+		  }
+		}
+		`
+	response := `
+		{
+			"data": {
+				"blocks": [
+					{
+						"header": {
+							"height": 0
+						}
+					},
+					{
+						"header": {
+							"height": 1
+						}
+					}
+				]
+			}
+		}
+	`
+	assertQuery(t, query, response)
+}
+
 func TestBlocksTxsQuery(t *testing.T) {
 
 	query := `
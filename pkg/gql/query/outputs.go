@@ -0,0 +1,119 @@
+package query
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	outputPubKeyArg  = "pubkey"
+	outputPubKeysArg = "pubkeys"
+)
+
+// File purpose is to define all arguments and resolvers relevant to "outputs" query only
+
+type outputs struct {
+}
+
+func (o outputs) getQuery() *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewList(Output),
+		Args: graphql.FieldConfigArgument{
+			outputPubKeyArg: &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+			outputPubKeysArg: &graphql.ArgumentConfig{
+				Type: graphql.NewList(graphql.String),
+			},
+		},
+		Resolve: o.resolve,
+	}
+}
+
+func (o outputs) resolve(p graphql.ResolveParams) (interface{}, error) {
+
+	// Retrieve DB conn from context
+	db, ok := p.Context.Value("database").(database.DB)
+	if !ok {
+		return nil, errors.New("context does not store database conn")
+	}
+
+	pubkey, ok := p.Args[outputPubKeyArg].(interface{})
+	if ok {
+		return o.fetchOutputs(db, []interface{}{pubkey})
+	}
+
+	pubkeys, ok := p.Args[outputPubKeysArg].([]interface{})
+	if ok {
+		return o.fetchOutputs(db, pubkeys)
+	}
+
+	return nil, nil
+}
+
+// Fetch the outputs stored under a list of destination keys. Unknown keys are
+// skipped.
+func (o outputs) fetchOutputs(db database.DB, pubkeys []interface{}) ([]queryOutput, error) {
+
+	if len(pubkeys) > txsFetchLimit {
+		return nil, errors.New("requested outputs count exceeds the limit")
+	}
+
+	outputs := make([]queryOutput, 0)
+	err := db.View(func(t database.Transaction) error {
+		for _, v := range pubkeys {
+			encoded, ok := v.(string)
+			if !ok {
+				continue
+			}
+
+			pubkey, err := hex.DecodeString(encoded)
+			if err != nil {
+				continue
+			}
+
+			exists, err := t.FetchOutputExists(pubkey)
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				outputs = append(outputs, queryOutput{PubKey: pubkey})
+			}
+		}
+		return nil
+	})
+
+	return outputs, err
+}
+
+// resolveUnlockHeight looks up the height at which an output can be spent.
+// Outputs which are not stored yet, like the ones of mempool txs, resolve to
+// null.
+func resolveUnlockHeight(p graphql.ResolveParams) (interface{}, error) {
+	output, ok := p.Source.(queryOutput)
+	if !ok {
+		return nil, errors.New("invalid source output")
+	}
+
+	// Retrieve DB conn from context
+	db, ok := p.Context.Value("database").(database.DB)
+	if !ok {
+		return nil, errors.New("context does not store database conn")
+	}
+
+	var height uint64
+	err := db.View(func(t database.Transaction) error {
+		var err error
+		height, err = t.FetchOutputUnlockHeight(output.PubKey)
+		return err
+	})
+	if err != nil {
+		return nil, nil
+	}
+
+	return height, nil
+}
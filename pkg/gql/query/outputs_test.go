@@ -0,0 +1,23 @@
+package query
+
+import (
+	"testing"
+)
+
+func TestOutputsUnknownPubKey(t *testing.T) {
+	query := `
+		{
+		  outputs(pubkey: "ea2c58c43d2ac9783a25dae2399b227fc1fd2a8bca41ca34aef74c9a3f7b435f") {
+			pubkey
+		  }
+		}
+		`
+	response := `
+		{
+			"data": {
+				"outputs": []
+			}
+		}
+	`
+	assertQuery(t, query, response)
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/graphql-go/graphql"
+)
+
+// File purpose is to define all arguments and resolvers relevant to "provisioners" query only
+
+type provisioners struct {
+	rpcBus *rpcbus.RPCBus
+}
+
+func (p provisioners) getQuery() *graphql.Field {
+	return &graphql.Field{
+		Type:    graphql.NewList(Provisioner),
+		Resolve: p.resolve,
+	}
+}
+
+// resolve returns the current provisioner set, sorted by BLS public key
+func (p provisioners) resolve(params graphql.ResolveParams) (interface{}, error) {
+
+	if p.rpcBus == nil {
+		return nil, errors.New("provisioners are not available")
+	}
+
+	r, err := p.rpcBus.Call(rpcbus.GetProvisioners, rpcbus.NewRequest(bytes.Buffer{}), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := user.UnmarshalProvisioners(&r)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*user.Member, 0, len(set.Set))
+	for i := range set.Set {
+		if m := set.MemberAt(i); m != nil {
+			members = append(members, m)
+		}
+	}
+
+	return members, nil
+}
@@ -1,17 +1,22 @@
 package query
 
 import (
+	"errors"
+
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/block"
 	"github.com/graphql-go/graphql"
 )
 
 type Root struct {
-	Query *graphql.Object
+	Query        *graphql.Object
+	Subscription *graphql.Object
 }
 
 func NewRoot(rpcBus *rpcbus.RPCBus) *Root {
 
 	m := mempool{rpcBus: rpcBus}
+	p := provisioners{rpcBus: rpcBus}
 
 	root := Root{
 		Query: graphql.NewObject(
@@ -20,10 +25,39 @@ func NewRoot(rpcBus *rpcbus.RPCBus) *Root {
 				Fields: graphql.Fields{
 					"blocks":       blocks{}.getQuery(),
 					"transactions": transactions{}.getQuery(),
+					"outputs":      outputs{}.getQuery(),
 					"mempool":      m.getQuery(),
+					"provisioners": p.getQuery(),
+				},
+			},
+		),
+		Subscription: graphql.NewObject(
+			graphql.ObjectConfig{
+				Name: "Subscription",
+				Fields: graphql.Fields{
+					"newBlock": &graphql.Field{
+						Type:    Block,
+						Resolve: resolveNewBlock,
+					},
 				},
 			},
 		),
 	}
 	return &root
 }
+
+// resolveNewBlock returns the block a subscription is executed for. The
+// block is passed as root value, under the "block" key.
+func resolveNewBlock(p graphql.ResolveParams) (interface{}, error) {
+	root, ok := p.Info.RootValue.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("subscriptions can only be served over websocket")
+	}
+
+	b, ok := root["block"].(*block.Block)
+	if !ok {
+		return nil, errors.New("invalid root block")
+	}
+
+	return b, nil
+}
@@ -17,9 +17,10 @@ import (
 const (
 	txsFetchLimit = 10000
 
-	txidArg   = "txid"
-	txidsArg  = "txids"
-	txlastArg = "last"
+	txidArg     = "txid"
+	txidsArg    = "txids"
+	txlastArg   = "last"
+	txoffsetArg = "offset"
 )
 
 // queryTx is a data-wrapper for all core.transaction relevant fields that
@@ -54,7 +55,7 @@ type transactions struct {
 // newQueryTx constructs query tx data from core tx and block hash
 func newQueryTx(tx core.Transaction, blockHash []byte) (queryTx, error) {
 
-	txId, err := tx.CalculateHash()
+	txId, err := marshalling.TxID(tx)
 	if err != nil {
 		return queryTx{}, err
 	}
@@ -109,6 +110,11 @@ func (t transactions) getQuery() *graphql.Field {
 			txlastArg: &graphql.ArgumentConfig{
 				Type: graphql.Int,
 			},
+			// offset skips the most recent txs when used along with `last`
+			txoffsetArg: &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 0,
+			},
 		},
 		Resolve: t.resolve,
 	}
@@ -140,7 +146,12 @@ func (t transactions) resolve(p graphql.ResolveParams) (interface{}, error) {
 			return nil, errors.New("invalid count")
 		}
 
-		return t.fetchLastTxs(db, count)
+		skip, _ := p.Args[txoffsetArg].(int)
+		if skip < 0 {
+			return nil, errors.New("invalid offset")
+		}
+
+		return t.fetchLastTxs(db, count, skip)
 	}
 
 	return nil, nil
@@ -179,8 +190,9 @@ func (t transactions) fetchTxsByHash(db database.DB, txids []interface{}) ([]que
 	return txs, err
 }
 
-// Fetch #count# number of txs from lastly accepted blocks
-func (b transactions) fetchLastTxs(db database.DB, count int) ([]queryTx, error) {
+// Fetch #count# number of txs from lastly accepted blocks, skipping the
+// #skip# most recent ones
+func (b transactions) fetchLastTxs(db database.DB, count, skip int) ([]queryTx, error) {
 
 	txs := make([]queryTx, 0)
 
@@ -188,7 +200,7 @@ func (b transactions) fetchLastTxs(db database.DB, count int) ([]queryTx, error)
 		return txs, nil
 	}
 
-	if count >= txsFetchLimit {
+	if count+skip >= txsFetchLimit {
 		msg := fmt.Sprintf("requested txs count exceeds the limit of %d", txsFetchLimit)
 		log.Warn(msg)
 
@@ -219,6 +231,11 @@ func (b transactions) fetchLastTxs(db database.DB, count int) ([]queryTx, error)
 
 			for _, tx := range blockTxs {
 
+				if skip > 0 {
+					skip--
+					continue
+				}
+
 				d, err := newQueryTx(tx, hash)
 				if err == nil {
 					txs = append(txs, d)
@@ -88,6 +88,10 @@ var Output = graphql.NewObject(
 			"pubkey": &graphql.Field{
 				Type: Hex,
 			},
+			"unlockheight": &graphql.Field{
+				Type:    graphql.Int,
+				Resolve: resolveUnlockHeight,
+			},
 		},
 	},
 )
@@ -103,6 +107,40 @@ var Input = graphql.NewObject(
 	},
 )
 
+var Provisioner = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Provisioner",
+		Fields: graphql.Fields{
+			"publickeybls": &graphql.Field{
+				Type: Hex,
+			},
+			"publickeyed": &graphql.Field{
+				Type: Hex,
+			},
+			"stakes": &graphql.Field{
+				Type: graphql.NewList(Stake),
+			},
+		},
+	},
+)
+
+var Stake = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Stake",
+		Fields: graphql.Fields{
+			"amount": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"startheight": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"endheight": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	},
+)
+
 var Hex = graphql.NewScalar(graphql.ScalarConfig{
 	Name:        "Hex",
 	Description: "Hex scalar type represents a byte array",
@@ -0,0 +1,111 @@
+package gql
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/wshub"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"golang.org/x/net/websocket"
+)
+
+// subscriberQueueSize is the amount of blocks buffered for a subscriber.
+// Blocks are dropped for subscribers falling further behind.
+const subscriberQueueSize = 16
+
+// subscriber is a websocket client. The result of its query is pushed to it
+// for every accepted block.
+type subscriber struct {
+	req   data
+	queue chan *block.Block
+}
+
+// Notify queues an accepted block. It never blocks, so that a slow client
+// can not hold back the EventBus.
+func (sub *subscriber) Notify(event interface{}) {
+	blk := event.(*block.Block)
+	select {
+	case sub.queue <- blk:
+	default:
+		log.Warnf("websocket subscriber is lagging behind, dropping block %d", blk.Header.Height)
+	}
+}
+
+// onAcceptedBlock is the callback for the AcceptedBlock topic, feeding the
+// block to the websocket clients
+func (s *Server) onAcceptedBlock(m bytes.Buffer) error {
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&m, blk); err != nil {
+		return err
+	}
+
+	s.subscriptions.Broadcast(blk)
+	return nil
+}
+
+// handleSubscription serves a websocket client. The client is expected to
+// send a single query, with the same structure of HTTP requests, such as
+// `subscription { newBlock { header { height hash } } }`. The result of the
+// query is then sent back for every new block, until the client disconnects.
+func (s *Server) handleSubscription(ws *websocket.Conn) {
+	defer ws.Close()
+
+	var req data
+	if err := websocket.JSON.Receive(ws, &req); err != nil {
+		log.Warnf("websocket subscription: %v", err)
+		return
+	}
+
+	sub := &subscriber{req: req, queue: make(chan *block.Block, subscriberQueueSize)}
+	if err := s.subscriptions.Add(sub); err != nil {
+		_ = websocket.JSON.Send(ws, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+	defer s.subscriptions.Remove(sub)
+
+	// Nothing else is expected from the client. Reading lets us know when
+	// the connection is closed.
+	quit := make(chan struct{})
+	go func() {
+		defer close(quit)
+		var discard []byte
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+	}()
+
+	for {
+		select {
+		case blk := <-sub.queue:
+			result := executeSubscription(s.schema, sub.req, blk, s.db)
+			if err := websocket.JSON.Send(ws, result); err != nil {
+				return
+			}
+		case <-quit:
+			return
+		}
+	}
+}
+
+// newWebsocketHandler returns the handler for the websocket endpoint.
+// Browsers can only connect from the origins in gql.allowedOrigins.
+func (s *Server) newWebsocketHandler() http.Handler {
+	return wshub.Handler(s.handleSubscription, cfg.Get().Gql.AllowedOrigins)
+}
+
+// executeSubscription runs a subscription query for a new block
+func executeSubscription(schema *graphql.Schema, req data, blk *block.Block, db database.DB) *graphql.Result {
+	return graphql.Do(graphql.Params{
+		Schema:         *schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.Operation,
+		RootObject:     map[string]interface{}{"block": blk},
+		Context:        context.WithValue(context.Background(), "database", db),
+	})
+}
@@ -0,0 +1,341 @@
+// Package metrics collects the metrics of the node, and serves them on
+// /metrics in the Prometheus text exposition format. It is free of
+// dependencies: the components declare their counters, gauges and histograms
+// as package variables, which register themselves on creation.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// family is a registered metric, with all the samples of its labels
+type family struct {
+	name  string
+	help  string
+	kind  string
+	write func(w io.Writer, name string) error
+	// value reads the sample of unlabeled metrics, and is nil otherwise
+	value func() float64
+}
+
+var registry = struct {
+	sync.RWMutex
+	families map[string]*family
+}{families: make(map[string]*family)}
+
+// register adds a metric to the ones served. Metric names are unique, so
+// registering one twice panics, as it is a programming error.
+func register(name, help, kind string, write func(io.Writer, string) error, value func() float64) {
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.families[name]; ok {
+		panic(fmt.Sprintf("metric %s registered twice", name))
+	}
+	registry.families[name] = &family{name, help, kind, write, value}
+}
+
+// Value returns the current value of the unlabeled metric registered as
+// name. It returns false if there is no such metric.
+func Value(name string) (float64, bool) {
+	registry.RLock()
+	f, ok := registry.families[name]
+	registry.RUnlock()
+	if !ok || f.value == nil {
+		return 0, false
+	}
+	return f.value(), true
+}
+
+// WriteTo writes all the metrics to w, sorted by name
+func WriteTo(w io.Writer) error {
+	registry.RLock()
+	families := make([]*family, 0, len(registry.families))
+	for _, f := range registry.families {
+		families = append(families, f)
+	}
+	registry.RUnlock()
+
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+	for _, f := range families {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind); err != nil {
+			return err
+		}
+
+		if err := f.write(w, f.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Counter is a value which only goes up, such as the amount of blocks
+// accepted
+type Counter struct {
+	value uint64
+}
+
+// NewCounter registers a counter
+func NewCounter(name, help string) *Counter {
+	c := new(Counter)
+	register(name, help, "counter", func(w io.Writer, name string) error {
+		_, err := fmt.Fprintf(w, "%s %d\n", name, c.Value())
+		return err
+	}, func() float64 { return float64(c.Value()) })
+	return c
+}
+
+// Inc increments the counter by one
+func (c *Counter) Inc() {
+	atomic.AddUint64(&c.value, 1)
+}
+
+// Add increments the counter by n
+func (c *Counter) Add(n uint64) {
+	atomic.AddUint64(&c.value, n)
+}
+
+// Value returns the current value of the counter
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+// CounterVec is a set of counters told apart by the value of a label, such as
+// the messages published per topic
+type CounterVec struct {
+	label    string
+	lock     sync.RWMutex
+	counters map[string]*Counter
+}
+
+// NewCounterVec registers a set of counters, labeled with label
+func NewCounterVec(name, help, label string) *CounterVec {
+	v := &CounterVec{label: label, counters: make(map[string]*Counter)}
+	register(name, help, "counter", v.write, nil)
+	return v
+}
+
+// With returns the counter for the given value of the label
+func (v *CounterVec) With(value string) *Counter {
+	v.lock.RLock()
+	c, ok := v.counters[value]
+	v.lock.RUnlock()
+	if ok {
+		return c
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if c, ok := v.counters[value]; ok {
+		return c
+	}
+	c = new(Counter)
+	v.counters[value] = c
+	return c
+}
+
+func (v *CounterVec) write(w io.Writer, name string) error {
+	v.lock.RLock()
+	values := make([]string, 0, len(v.counters))
+	for value := range v.counters {
+		values = append(values, value)
+	}
+	v.lock.RUnlock()
+
+	sort.Strings(values)
+	for _, value := range values {
+		if _, err := fmt.Fprintf(w, "%s{%s=%q} %d\n", name, v.label, value, v.With(value).Value()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Gauge is a value which goes up and down, such as the amount of peers
+type Gauge struct {
+	bits uint64
+}
+
+// NewGauge registers a gauge
+func NewGauge(name, help string) *Gauge {
+	g := new(Gauge)
+	register(name, help, "gauge", func(w io.Writer, name string) error {
+		_, err := fmt.Fprintf(w, "%s %s\n", name, formatFloat(g.Value()))
+		return err
+	}, g.Value)
+	return g
+}
+
+// Set sets the gauge to v
+func (g *Gauge) Set(v float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(v))
+}
+
+// Add adds delta, which can be negative, to the gauge
+func (g *Gauge) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&g.bits)
+		v := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&g.bits, old, v) {
+			return
+		}
+	}
+}
+
+// Value returns the current value of the gauge
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+// LatencyBuckets are the upper bounds, in seconds, of the buckets of the
+// histograms of durations, from a millisecond to a minute
+var LatencyBuckets = []float64{.001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// Histogram counts observations, such as durations, in buckets of increasing
+// upper bounds. It is served as the cumulative counts of the buckets, along
+// with the sum and the count of the observations.
+type Histogram struct {
+	bounds []float64
+	lock   sync.Mutex
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram registers a histogram, with the upper bounds of its buckets
+// in increasing order. Observations above the last bound only count in the
+// +Inf bucket.
+func NewHistogram(name, help string, bounds []float64) *Histogram {
+	h := newHistogram(bounds)
+	register(name, help, "histogram", h.write, nil)
+	return h
+}
+
+func newHistogram(bounds []float64) *Histogram {
+	return &Histogram{
+		bounds: append([]float64(nil), bounds...),
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+// Observe adds v to the histogram
+func (h *Histogram) Observe(v float64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// ObserveSince adds the seconds elapsed since start to the histogram
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// Count returns the amount of observations, and their sum
+func (h *Histogram) Count() (uint64, float64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.count, h.sum
+}
+
+func (h *Histogram) write(w io.Writer, name string) error {
+	return h.writeLabeled(w, name, "")
+}
+
+// writeLabeled writes the samples of the histogram, labeled with labels, as
+// in `topic="tx"`, on top of the bucket bounds
+func (h *Histogram) writeLabeled(w io.Writer, name, labels string) error {
+	h.lock.Lock()
+	counts := append([]uint64(nil), h.counts...)
+	sum, count := h.sum, h.count
+	h.lock.Unlock()
+
+	prefix, suffix := "", ""
+	if labels != "" {
+		prefix, suffix = labels+",", "{"+labels+"}"
+	}
+
+	var cumulative uint64
+	for i, bound := range h.bounds {
+		cumulative += counts[i]
+		if _, err := fmt.Fprintf(w, "%s_bucket{%sle=%q} %d\n", name, prefix, formatFloat(bound), cumulative); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n%s_sum%s %s\n%s_count%s %d\n", name, prefix, count, name, suffix, formatFloat(sum), name, suffix, count)
+	return err
+}
+
+// HistogramVec is a set of histograms told apart by the value of a label,
+// such as the latency of the calls per RPC method
+type HistogramVec struct {
+	label      string
+	bounds     []float64
+	lock       sync.RWMutex
+	histograms map[string]*Histogram
+}
+
+// NewHistogramVec registers a set of histograms with the same buckets,
+// labeled with label
+func NewHistogramVec(name, help, label string, bounds []float64) *HistogramVec {
+	v := &HistogramVec{
+		label:      label,
+		bounds:     append([]float64(nil), bounds...),
+		histograms: make(map[string]*Histogram),
+	}
+	register(name, help, "histogram", v.write, nil)
+	return v
+}
+
+// With returns the histogram for the given value of the label
+func (v *HistogramVec) With(value string) *Histogram {
+	v.lock.RLock()
+	h, ok := v.histograms[value]
+	v.lock.RUnlock()
+	if ok {
+		return h
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if h, ok := v.histograms[value]; ok {
+		return h
+	}
+	h = newHistogram(v.bounds)
+	v.histograms[value] = h
+	return h
+}
+
+func (v *HistogramVec) write(w io.Writer, name string) error {
+	v.lock.RLock()
+	values := make([]string, 0, len(v.histograms))
+	for value := range v.histograms {
+		values = append(values, value)
+	}
+	v.lock.RUnlock()
+
+	sort.Strings(values)
+	for _, value := range values {
+		if err := v.With(value).writeLabeled(w, name, fmt.Sprintf("%s=%q", v.label, value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
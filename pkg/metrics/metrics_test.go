@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that the metrics are written in the Prometheus text format.
+func TestWriteTo(t *testing.T) {
+	c := NewCounter("test_counter_total", "A counter")
+	c.Add(2)
+	c.Inc()
+
+	g := NewGauge("test_gauge", "A gauge")
+	g.Set(5)
+	g.Add(-1.5)
+
+	v := NewCounterVec("test_vec_total", "Labeled counters", "topic")
+	v.With("tx").Inc()
+	v.With("block").Add(2)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, WriteTo(buf))
+	out := buf.String()
+
+	assert.True(t, strings.Contains(out, "# HELP test_counter_total A counter\n# TYPE test_counter_total counter\ntest_counter_total 3\n"))
+	assert.True(t, strings.Contains(out, "# TYPE test_gauge gauge\ntest_gauge 3.5\n"))
+	assert.True(t, strings.Contains(out, "test_vec_total{topic=\"block\"} 2\ntest_vec_total{topic=\"tx\"} 1\n"))
+
+	value, ok := Value("test_gauge")
+	assert.True(t, ok)
+	assert.Equal(t, 3.5, value)
+	_, ok = Value("test_vec_total")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { NewGauge("test_gauge", "Twice") })
+}
+
+// Test that the buckets of a histogram are cumulative.
+func TestHistogram(t *testing.T) {
+	h := NewHistogram("test_seconds", "A histogram", []float64{0.5, 1, 2})
+	h.Observe(0.25)
+	h.Observe(0.5)
+	h.Observe(1.5)
+	h.Observe(4)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, WriteTo(buf))
+	out := buf.String()
+
+	assert.True(t, strings.Contains(out, "# TYPE test_seconds histogram\n"+
+		"test_seconds_bucket{le=\"0.5\"} 2\n"+
+		"test_seconds_bucket{le=\"1\"} 2\n"+
+		"test_seconds_bucket{le=\"2\"} 3\n"+
+		"test_seconds_bucket{le=\"+Inf\"} 4\n"+
+		"test_seconds_sum 6.25\n"+
+		"test_seconds_count 4\n"))
+
+	count, sum := h.Count()
+	assert.Equal(t, uint64(4), count)
+	assert.Equal(t, 6.25, sum)
+	_, ok := Value("test_seconds")
+	assert.False(t, ok)
+}
+
+// Test that the histograms of a set are written with their label.
+func TestHistogramVec(t *testing.T) {
+	v := NewHistogramVec("test_vec_seconds", "Labeled histograms", "method", []float64{1})
+	v.With("ping").Observe(0.5)
+	v.With("ping").Observe(2)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, WriteTo(buf))
+
+	assert.True(t, strings.Contains(buf.String(), "# TYPE test_vec_seconds histogram\n"+
+		"test_vec_seconds_bucket{method=\"ping\",le=\"1\"} 1\n"+
+		"test_vec_seconds_bucket{method=\"ping\",le=\"+Inf\"} 2\n"+
+		"test_vec_seconds_sum{method=\"ping\"} 2.5\n"+
+		"test_vec_seconds_count{method=\"ping\"} 2\n"))
+}
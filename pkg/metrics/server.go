@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"net"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "metrics")
+
+// Handler serves the metrics in the Prometheus text exposition format
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := WriteTo(w); err != nil {
+			lg.WithError(err).Debugln("could not write metrics")
+		}
+	})
+}
+
+// DefaultPath is the path the metrics are served on, unless configured
+// otherwise
+const DefaultPath = "/metrics"
+
+// Listen on the given address and serve the metrics on path, DefaultPath if
+// empty, in a goroutine
+func Listen(address, path string) (*http.Server, error) {
+	if path == "" {
+		path = DefaultPath
+	}
+
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, Handler())
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+			lg.WithError(err).Errorln("metrics server stopped")
+		}
+	}()
+	return srv, nil
+}
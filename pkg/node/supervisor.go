@@ -0,0 +1,377 @@
+// Package node runs the subsystems of the node, such as the chain, the
+// mempool and the peer layer, under a Supervisor. The subsystems are started
+// in the order they are added, and stopped in reverse order. A subsystem
+// whose loop panics or returns while the node is running is restarted, up to
+// a limit, after which the whole node shuts down rather than running without
+// it.
+package node
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "supervisor")
+
+var (
+	restartsTotal = metrics.NewCounterVec("dusk_subsystem_restarts_total", "Restarts of the subsystems after a failure, by subsystem", "subsystem")
+	unhealthy     = metrics.NewGauge("dusk_subsystems_unhealthy", "Subsystems failing their health check")
+)
+
+var (
+	// errReturned is the failure of a loop returning while the node runs
+	errReturned = errors.New("loop returned")
+	// errStopTimeout is returned for subsystems taking too long to stop
+	errStopTimeout = errors.New("stop timed out")
+)
+
+// restartDelay is the time waited before running a failed loop again, so
+// that a loop failing right away does not spin
+var restartDelay = time.Second
+
+// Subsystem is a component of the node run by the Supervisor. All the
+// functions are optional.
+type Subsystem struct {
+	// Name identifies the subsystem in the logs
+	Name string
+	// Start is called on startup, in order. An error aborts the startup.
+	Start func() error
+	// Run is the loop of the subsystem, blocking until Stop is called. Run
+	// panicking, or returning before Stop, is a failure of the subsystem.
+	Run func()
+	// Stop ends Run, and releases the subsystem
+	Stop func() error
+	// Health reports a problem with the subsystem, while it runs
+	Health func() error
+}
+
+// Config of the Supervisor
+type Config struct {
+	// Restart the loops failing, rather than shutting the node down
+	Restart bool
+	// MaxRestarts allowed for a subsystem within RestartWindow. A subsystem
+	// failing more often shuts the node down.
+	MaxRestarts   int
+	RestartWindow time.Duration
+	// HealthInterval is the time between two health checks. Zero disables
+	// the health checks.
+	HealthInterval time.Duration
+	// StopTimeout bounds the time waited for each subsystem to stop, as a
+	// subsystem which failed may never do. Zero waits for as long as it takes.
+	StopTimeout time.Duration
+}
+
+// Status of a subsystem
+type Status struct {
+	Name     string
+	Running  bool
+	Restarts int
+	// Err is the last failure of the loop, or health check
+	Err error
+}
+
+type supervised struct {
+	Subsystem
+	running  bool
+	restarts []time.Time
+	err      error
+	healthy  bool
+}
+
+// Supervisor starts, watches and stops the subsystems of the node
+type Supervisor struct {
+	conf Config
+
+	lock       sync.Mutex
+	subsystems []*supervised
+	started    int
+	stopping   bool
+
+	// shutdown is closed when the node must shut down, on request or after
+	// a subsystem failed for good
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+	stopOnce     sync.Once
+	quit         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// New creates a Supervisor without subsystems
+func New(conf Config) *Supervisor {
+	return &Supervisor{
+		conf:     conf,
+		shutdown: make(chan struct{}),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Add a subsystem, started after the ones added before, and stopped before
+// them. Subsystems must be added before Start.
+func (s *Supervisor) Add(sub Subsystem) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.subsystems = append(s.subsystems, &supervised{Subsystem: sub, healthy: true})
+}
+
+// Start the subsystems in order, and run their loops. If a subsystem fails to
+// start, the ones started already are stopped, and the error is returned.
+func (s *Supervisor) Start() error {
+	s.lock.Lock()
+	subsystems := s.subsystems
+	s.lock.Unlock()
+
+	for _, sub := range subsystems {
+		if sub.Start != nil {
+			if err := sub.Start(); err != nil {
+				s.Stop()
+				return fmt.Errorf("could not start %s: %v", sub.Name, err)
+			}
+		}
+
+		s.lock.Lock()
+		s.started++
+		s.lock.Unlock()
+
+		if sub.Run != nil {
+			s.wg.Add(1)
+			go s.supervise(sub)
+		}
+		lg.WithField("subsystem", sub.Name).Debugln("started")
+	}
+
+	if s.conf.HealthInterval > 0 {
+		s.wg.Add(1)
+		go s.checkHealth()
+	}
+	return nil
+}
+
+// supervise runs the loop of sub until the node stops, restarting it on
+// failure as configured
+func (s *Supervisor) supervise(sub *supervised) {
+	defer s.wg.Done()
+	for {
+		s.setRunning(sub, true)
+		err := runLoop(sub.Run)
+		s.setRunning(sub, false)
+		if s.isStopping() {
+			return
+		}
+
+		l := lg.WithField("subsystem", sub.Name).WithError(err)
+		if !s.allowRestart(sub, err) {
+			l.Errorln("subsystem failed, shutting down")
+			s.Shutdown()
+			return
+		}
+
+		l.Warnln("subsystem failed, restarting")
+		restartsTotal.With(sub.Name).Inc()
+		select {
+		case <-time.After(restartDelay):
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// runLoop calls run, turning a panic into an error along with its stack
+func runLoop(run func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	run()
+	return errReturned
+}
+
+// allowRestart records the failure of sub, and tells whether its loop can be
+// run again
+func (s *Supervisor) allowRestart(sub *supervised, err error) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sub.err = err
+	if !s.conf.Restart {
+		return false
+	}
+
+	// Only the restarts within the window count
+	now := time.Now()
+	recent := sub.restarts[:0]
+	for _, t := range sub.restarts {
+		if now.Sub(t) < s.conf.RestartWindow {
+			recent = append(recent, t)
+		}
+	}
+	sub.restarts = recent
+
+	if len(sub.restarts) >= s.conf.MaxRestarts {
+		return false
+	}
+	sub.restarts = append(sub.restarts, now)
+	return true
+}
+
+// checkHealth runs the health checks every HealthInterval, logging the
+// subsystems turning unhealthy, and back healthy
+func (s *Supervisor) checkHealth() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.conf.HealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.lock.Lock()
+			subsystems := s.subsystems[:s.started]
+			s.lock.Unlock()
+
+			var count int
+			for _, sub := range subsystems {
+				if sub.Health == nil {
+					continue
+				}
+
+				err := sub.Health()
+				s.setHealth(sub, err)
+				if err != nil {
+					count++
+				}
+			}
+			unhealthy.Set(float64(count))
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Supervisor) setHealth(sub *supervised, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	l := lg.WithField("subsystem", sub.Name)
+	switch {
+	case err != nil && sub.healthy:
+		l.WithError(err).Warnln("subsystem unhealthy")
+	case err == nil && !sub.healthy:
+		l.Infoln("subsystem healthy again")
+	}
+
+	sub.healthy = err == nil
+	if err != nil {
+		sub.err = err
+	}
+}
+
+func (s *Supervisor) setRunning(sub *supervised, running bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sub.running = running
+}
+
+func (s *Supervisor) isStopping() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.stopping
+}
+
+// Status returns the status of the subsystems, in order
+func (s *Supervisor) Status() []Status {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	status := make([]Status, 0, len(s.subsystems))
+	for _, sub := range s.subsystems {
+		status = append(status, Status{
+			Name:     sub.Name,
+			Running:  sub.running,
+			Restarts: len(sub.restarts),
+			Err:      sub.err,
+		})
+	}
+	return status
+}
+
+// Shutdown requests the node to shut down. It does not wait for the
+// subsystems to stop, which Wait does.
+func (s *Supervisor) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.shutdown)
+	})
+}
+
+// Wait blocks until the process receives one of signals, or a shutdown is
+// requested, and stops the subsystems
+func (s *Supervisor) Wait(signals ...os.Signal) {
+	interrupt := make(chan os.Signal, 1)
+	if len(signals) > 0 {
+		signal.Notify(interrupt, signals...)
+		defer signal.Stop(interrupt)
+	}
+
+	select {
+	case sig := <-interrupt:
+		lg.WithField("signal", sig).Infoln("shutting down")
+	case <-s.shutdown:
+		lg.Infoln("shutting down")
+	}
+	s.Stop()
+}
+
+// Stop the subsystems started, in reverse order, and wait for their loops to
+// return
+func (s *Supervisor) Stop() {
+	s.stopOnce.Do(func() {
+		s.lock.Lock()
+		s.stopping = true
+		subsystems := s.subsystems[:s.started]
+		s.lock.Unlock()
+		close(s.quit)
+
+		for i := len(subsystems) - 1; i >= 0; i-- {
+			sub := subsystems[i]
+			if sub.Stop == nil {
+				continue
+			}
+
+			if err := s.within(sub.Stop); err != nil {
+				lg.WithField("subsystem", sub.Name).WithError(err).Errorln("could not stop")
+			}
+		}
+
+		err := s.within(func() error {
+			s.wg.Wait()
+			return nil
+		})
+		if err != nil {
+			lg.WithError(err).Errorln("subsystems still running")
+		}
+		s.Shutdown()
+	})
+}
+
+// within calls fn, giving up after StopTimeout
+func (s *Supervisor) within(fn func() error) error {
+	if s.conf.StopTimeout == 0 {
+		return fn()
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- fn()
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-time.After(s.conf.StopTimeout):
+		return errStopTimeout
+	}
+}
@@ -0,0 +1,173 @@
+package node
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	restartDelay = time.Millisecond
+}
+
+// loop is a subsystem loop, which can be made to panic
+type loop struct {
+	runs  chan struct{}
+	panic chan struct{}
+	quit  chan struct{}
+}
+
+func newLoop() *loop {
+	return &loop{
+		runs:  make(chan struct{}, 10),
+		panic: make(chan struct{}),
+		quit:  make(chan struct{}),
+	}
+}
+
+func (l *loop) run() {
+	l.runs <- struct{}{}
+	select {
+	case <-l.panic:
+		panic("loop failure")
+	case <-l.quit:
+	}
+}
+
+func (l *loop) stop() error {
+	close(l.quit)
+	return nil
+}
+
+// Subsystems start in order, and stop in reverse order
+func TestOrder(t *testing.T) {
+	var order []string
+	s := New(Config{})
+	for _, name := range []string{"chain", "mempool", "rpc"} {
+		name := name
+		s.Add(Subsystem{
+			Name:  name,
+			Start: func() error { order = append(order, "start "+name); return nil },
+			Stop:  func() error { order = append(order, "stop "+name); return nil },
+		})
+	}
+
+	assert.NoError(t, s.Start())
+	s.Shutdown()
+	s.Wait()
+
+	assert.Equal(t, []string{"start chain", "start mempool", "start rpc", "stop rpc", "stop mempool", "stop chain"}, order)
+}
+
+// A subsystem failing to start stops the ones started before it
+func TestStartFailure(t *testing.T) {
+	var stopped []string
+	s := New(Config{})
+	s.Add(Subsystem{Name: "chain", Stop: func() error { stopped = append(stopped, "chain"); return nil }})
+	s.Add(Subsystem{Name: "rpc", Start: func() error { return errors.New("address in use") }})
+	s.Add(Subsystem{Name: "consensus", Stop: func() error { stopped = append(stopped, "consensus"); return nil }})
+
+	assert.Error(t, s.Start())
+	assert.Equal(t, []string{"chain"}, stopped)
+}
+
+// A loop panicking is run again, up to MaxRestarts times, after which the
+// node shuts down
+func TestRestart(t *testing.T) {
+	l := newLoop()
+	s := New(Config{Restart: true, MaxRestarts: 2, RestartWindow: time.Minute})
+	s.Add(Subsystem{Name: "chain", Run: l.run, Stop: l.stop})
+	assert.NoError(t, s.Start())
+
+	for i := 0; i < 2; i++ {
+		<-l.runs
+		l.panic <- struct{}{}
+	}
+
+	<-l.runs
+	status := s.Status()[0]
+	assert.Equal(t, 2, status.Restarts)
+	assert.Contains(t, status.Err.Error(), "loop failure")
+
+	// One failure too many
+	l.panic <- struct{}{}
+	select {
+	case <-s.shutdown:
+	case <-time.After(time.Second):
+		t.Fatal("node did not shut down")
+	}
+}
+
+// Without restarts, a loop returning shuts the node down
+func TestNoRestart(t *testing.T) {
+	s := New(Config{})
+	s.Add(Subsystem{Name: "mempool", Run: func() {}})
+	assert.NoError(t, s.Start())
+
+	done := make(chan struct{})
+	go func() {
+		s.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("node did not shut down")
+	}
+	assert.Equal(t, errReturned, s.Status()[0].Err)
+}
+
+// Loops stopped on shutdown are not restarted
+func TestStop(t *testing.T) {
+	l := newLoop()
+	s := New(Config{Restart: true, MaxRestarts: 1, RestartWindow: time.Minute})
+	s.Add(Subsystem{Name: "chain", Run: l.run, Stop: l.stop})
+	assert.NoError(t, s.Start())
+
+	<-l.runs
+	s.Stop()
+	assert.Equal(t, 0, len(l.runs))
+	assert.False(t, s.Status()[0].Running)
+	assert.NoError(t, s.Status()[0].Err)
+}
+
+func TestHealth(t *testing.T) {
+	failing := make(chan error, 1)
+	failing <- errors.New("no peers")
+	s := New(Config{HealthInterval: time.Millisecond})
+	s.Add(Subsystem{Name: "peers", Health: func() error {
+		select {
+		case err := <-failing:
+			return err
+		default:
+			return nil
+		}
+	}})
+	assert.NoError(t, s.Start())
+	defer s.Stop()
+
+	// The last problem found stays in the status
+	deadline := time.Now().Add(time.Second)
+	for s.Status()[0].Err == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("health check did not run")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, "no peers", s.Status()[0].Err.Error())
+}
+
+// Subsystems which do not stop in time are given up on
+func TestStopTimeout(t *testing.T) {
+	var stopped bool
+	s := New(Config{StopTimeout: time.Millisecond})
+	s.Add(Subsystem{Name: "chain", Stop: func() error { stopped = true; return nil }})
+	s.Add(Subsystem{Name: "transactor", Stop: func() error { select {} }})
+	assert.NoError(t, s.Start())
+
+	s.Stop()
+	assert.True(t, stopped)
+}
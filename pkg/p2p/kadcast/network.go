@@ -1,7 +1,6 @@
 package kadcast
 
 import (
-	log "github.com/sirupsen/logrus"
 	"net"
 	"time"
 
@@ -20,7 +19,7 @@ PacketConnCreation:
 	// listen to incoming udp packets
 	pc, err := net.ListenUDP(netw, &lAddr)
 	if err != nil {
-		log.Panic(err)
+		lg.Panic(err)
 	}
 	// Set initial deadline.
 	pc.SetDeadline(time.Now().Add(time.Minute))
@@ -32,7 +31,7 @@ PacketConnCreation:
 		byteNum, uAddr, err := pc.ReadFromUDP(buffer)
 
 		if err != nil {
-			log.WithError(err).Warn("Error on packet read")
+			lg.WithError(err).Warn("Error on packet read")
 			pc.Close()
 			goto PacketConnCreation
 		} 
@@ -51,7 +50,7 @@ func sendUDPPacket(netw string, addr net.UDPAddr, payload []byte) {
 	localAddr := getLocalUDPAddress()
 	conn, err := net.DialUDP(netw, &localAddr, &addr)
 	if err != nil {
-		log.WithError(err).Warn("Could not stablish a connection with the dest Peer.")
+		lg.WithError(err).Warn("Could not stablish a connection with the dest Peer.")
 		return
 	}
 	defer conn.Close()
@@ -59,7 +58,7 @@ func sendUDPPacket(netw string, addr net.UDPAddr, payload []byte) {
 	// Simple write
 	_, err = conn.Write(payload)
 	if err != nil {
-		log.WithError(err).Warn("Error while writting to the filedescriptor.")
+		lg.WithError(err).Warn("Error while writting to the filedescriptor.")
 		return
 	} 
 }
\ No newline at end of file
@@ -4,8 +4,6 @@ import (
 	"encoding/binary"
 	"net"
 
-	log "github.com/sirupsen/logrus"
-
 	"github.com/dusk-network/dusk-blockchain/pkg/util/container/ring"
 )
 
@@ -155,7 +153,7 @@ func ProcessPacket(queue *ring.Buffer, router *Router) {
 			// Get items from the queue packet taken.
 			byteNum, senderAddr, udpPayload, err = decodeRedPacket(item)
 			if err != nil {
-				log.WithError(err).Warn("Error decoding the packet taken from the ring.")
+				lg.WithError(err).Warn("Error decoding the packet taken from the ring.")
 				continue
 			}
 			// Build packet struct
@@ -168,7 +166,7 @@ func ProcessPacket(queue *ring.Buffer, router *Router) {
 			// If we get an error, we just skip the whole process since the
 			// Peer was not validated.
 			if err := verifyIDNonce(senderID, nonce); err != nil {
-				log.WithError(err).Warn("Incorrect packet sender ID. Skipping its processing.")
+				lg.WithError(err).Warn("Incorrect packet sender ID. Skipping its processing.")
 				continue
 			}
 
@@ -182,24 +180,24 @@ func ProcessPacket(queue *ring.Buffer, router *Router) {
 			// Check packet type and process it.
 			switch tipus {
 			case 0:
-				log.WithField(
+				lg.WithField(
 					"Source-IP", peerInf.ip[:],
 				).Infoln("Recieved PING message")
 				handlePing(peerInf, router)
 			case 1:
-				log.WithField(
+				lg.WithField(
 					"Source-IP", peerInf.ip[:],
 				).Infoln("Recieved PONG message")
 				handlePong(peerInf, router)
 
 			case 2:
-				log.WithField(
+				lg.WithField(
 					"Source-IP", peerInf.ip[:],
 				).Infoln("Recieved FIND_NODES message")
 				handleFindNodes(peerInf, router)
 
 			case 3:
-				log.WithField(
+				lg.WithField(
 					"Source-IP", peerInf.ip[:],
 				).Infoln("Recieved NODES message")
 				handleNodes(peerInf, packet, router, byteNum)
@@ -242,7 +240,7 @@ func handleNodes(peerInf Peer, packet Packet, router *Router, byteNum int) {
 	// peerNum announced <=> bytesPerPeer * peerNum
 	if !packet.checkNodesPayloadConsistency(byteNum) {
 		// Since the packet is not consisten, we just discard it.
-		log.Info("NODES message recieved with corrupted payload. Packet ignored.")
+		lg.Info("NODES message recieved with corrupted payload. Packet ignored.")
 		return
 	}
 
@@ -7,6 +7,8 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+var lg = log.WithField("process", "kadcast")
+
 // InitBootstrap inits the Bootstrapping process by sending
 // a `PING` message to every bootstrapping node repeatedly.
 // If it tried 3 or more times and no new `Peers` were added,
@@ -14,7 +16,7 @@ import (
 // Otherways, it returns `nil` and logs the Number of peers
 // the node is connected to at the end of the process.
 func InitBootstrap(router *Router, bootNodes []Peer) error {
-	log.Info("Bootstrapping process started.")
+	lg.Info("Bootstrapping process started.")
 	// Get PeerList ordered by distance so we can compare it
 	// after the `PONG` arrivals.
 	initPeerNum := router.tree.getTotalPeers()
@@ -25,14 +27,14 @@ func InitBootstrap(router *Router, bootNodes []Peer) error {
 			if i == 3 {
 				return errors.New("Maximum number of attempts achieved. Please review yor connection settings")
 			}
-			log.WithFields(log.Fields{
+			lg.WithFields(log.Fields{
 				"Retries": i,
 			}).Warn("Bootstrapping nodes were not added.\nTrying again..")
 		} else {
 			break
 		}
 	}
-	log.WithFields(log.Fields{
+	lg.WithFields(log.Fields{
 		"connected_nodes": router.tree.getTotalPeers(),
 	}).Info("Bootstrapping process finished")
 	return nil
@@ -63,7 +65,7 @@ func StartNetworkDiscovery(router *Router) {
 		actualClosest = router.pollClosestPeer(5 * time.Second)
 	}
 
-	log.WithFields(log.Fields{
+	lg.WithFields(log.Fields{
 		"peers_connected": router.tree.getTotalPeers(),
 	}).Info("Network Discovery process finished.")
 }
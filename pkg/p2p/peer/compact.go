@@ -0,0 +1,46 @@
+package peer
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/candidate"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// lastCompact holds the last candidate compacted for gossip. The same
+// candidate message is written to the GossipConnector of every peer, so it
+// only needs to be compacted once.
+var lastCompact struct {
+	lock    sync.Mutex
+	full    []byte
+	compact []byte
+}
+
+// compactCandidate turns a `Candidate` message, topic included, into a
+// `CompactCandidate` message.
+func compactCandidate(m []byte) ([]byte, error) {
+	lastCompact.lock.Lock()
+	defer lastCompact.lock.Unlock()
+	if bytes.Equal(lastCompact.full, m) {
+		return lastCompact.compact, nil
+	}
+
+	cm := candidate.NewCandidate()
+	if err := candidate.Decode(bytes.NewBuffer(m[1:]), cm); err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := topics.Write(buf, topics.CompactCandidate); err != nil {
+		return nil, err
+	}
+
+	if err := candidate.EncodeCompact(buf, cm); err != nil {
+		return nil, err
+	}
+
+	lastCompact.full = m
+	lastCompact.compact = buf.Bytes()
+	return lastCompact.compact, nil
+}
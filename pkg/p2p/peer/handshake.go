@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/checksum"
@@ -94,7 +95,12 @@ func (p *Connection) readRemoteMsgVersion() error {
 		return err
 	}
 
-	return verifyVersion(version.Version)
+	if err := verifyVersion(version.Version); err != nil {
+		return err
+	}
+
+	p.services = version.Services
+	return nil
 }
 
 func (p *Connection) readVerAck() error {
@@ -145,7 +151,7 @@ func (p *Connection) writeVerAck(g *processing.Gossip) error {
 
 func (p *Connection) createVersionBuffer() (*bytes.Buffer, error) {
 	version := protocol.NodeVer
-	message, err := newVersionMessageBuffer(version, protocol.FullNode)
+	message, err := newVersionMessageBuffer(version, protocol.FullNode|protocol.CompactCandidates)
 	if err != nil {
 		return nil, err
 	}
@@ -154,8 +160,18 @@ func (p *Connection) createVersionBuffer() (*bytes.Buffer, error) {
 }
 
 func verifyVersion(v *protocol.Version) error {
-	if protocol.NodeVer.Major != v.Major {
-		return errors.New("version mismatch")
+	policy, err := protocol.PolicyFromConfig()
+	if err != nil {
+		return err
+	}
+
+	grace, err := policy.Accept(*v, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if grace {
+		l.WithField("version", v.String()).Warnln("peer accepted during the version grace period, it should upgrade")
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package peer
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"io"
@@ -11,11 +12,13 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/dupemap"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing/chainsync"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/responding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/checksum"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
@@ -25,6 +28,11 @@ var readWriteTimeout = 60 * time.Second // Max idle time for a peer
 
 var l *log.Entry = log.WithField("process", "peer")
 
+var (
+	messagesReceived = metrics.NewCounter("dusk_peer_messages_received_total", "Messages read from the peers")
+	bytesReceived    = metrics.NewCounter("dusk_peer_received_bytes_total", "Bytes of the messages read from the peers")
+)
+
 // Connection holds the TCP connection to another node, and it's known protocol magic.
 // The `net.Conn` is guarded by a mutex, to allow both multicast and one-to-one
 // communication between peers.
@@ -32,23 +40,32 @@ type Connection struct {
 	lock sync.Mutex
 	net.Conn
 	gossip *processing.Gossip
+	// services advertised by the peer during the handshake
+	services protocol.ServiceFlag
 }
 
-// GossipConnector calls Gossip.Process on the message stream incoming from the
-// ringbuffer
-// It absolves the function previously carried over by the Gossip preprocessor.
+// GossipConnector queues the messages incoming from the ringbuffer on the
+// outgoing priority queue of the peer.
 type GossipConnector struct {
-	gossip *processing.Gossip
+	queue *priorityQueue
 	*Connection
 }
 
 func (g *GossipConnector) Write(b []byte) (int, error) {
-	buf := bytes.NewBuffer(b)
-	if err := g.gossip.Process(buf); err != nil {
-		return 0, err
+	n := len(b)
+	if g.services&protocol.CompactCandidates != 0 && n > 0 && topics.Topic(b[0]) == topics.Candidate {
+		compact, err := compactCandidate(b)
+		if err != nil {
+			l.WithError(err).Warnln("could not compact candidate")
+		} else {
+			b = compact
+		}
 	}
 
-	return g.Connection.Write(buf.Bytes())
+	// The slice is shared among all peers, and is never modified
+	// afterwards, so it can be queued without copying
+	g.queue.push(bytes.NewBuffer(b))
+	return n, nil
 }
 
 // Writer abstracts all of the logic and fields needed to write messages to
@@ -57,7 +74,7 @@ type Writer struct {
 	*Connection
 	subscriber eventbus.Subscriber
 	gossipID   uint32
-	// TODO: add service flag
+	queue      *priorityQueue
 }
 
 // Reader abstracts all of the logic and fields needed to receive messages from
@@ -66,7 +83,6 @@ type Reader struct {
 	*Connection
 	router   *messageRouter
 	exitChan chan<- struct{} // Way to kill the WriteLoop
-	// TODO: add service flag
 }
 
 // NewWriter returns a Writer. It will still need to be initialized by
@@ -79,6 +95,7 @@ func NewWriter(conn net.Conn, gossip *processing.Gossip, subscriber eventbus.Sub
 			gossip: gossip,
 		},
 		subscriber: subscriber,
+		queue:      newPriorityQueue(),
 	}
 
 	return pw
@@ -124,6 +141,13 @@ func NewReader(conn net.Conn, gossip *processing.Gossip, dupeMap *dupemap.DupeMa
 	return reader, nil
 }
 
+// StreamTo makes the Reader stream the blocks requested by the peer through
+// the outgoing queue of w, instead of queuing them fully marshalled. It
+// needs to be called before ReadLoop is started.
+func (p *Reader) StreamTo(w *Writer) {
+	p.router.dataBroker.SetStreamer(w)
+}
+
 // ReadMessage reads from the connection
 func (c *Connection) ReadMessage() ([]byte, error) {
 	length, err := c.gossip.UnpackLength(c.Conn)
@@ -167,16 +191,43 @@ func (w *Writer) Serve(writeQueueChan <-chan *bytes.Buffer, exitChan chan struct
 	defer w.onDisconnect()
 
 	// Any gossip topics are written into interrupt-driven ringBuffer
-	// Single-consumer pushes messages to the socket
-	g := &GossipConnector{w.gossip, w.Connection}
-	w.gossipID = w.subscriber.Subscribe(topics.Gossip, eventbus.NewStreamListener(g))
+	// Single-consumer pushes messages to the priority queue. Consensus
+	// messages get their own tier, so that they are not stuck behind a
+	// burst of blocks or transactions
+	g := &GossipConnector{w.queue, w.Connection}
+	w.gossipID = w.subscriber.Subscribe(topics.Gossip, eventbus.NewPriorityStreamListener(g, isHighPriority))
 
 	// Ping loop - ensures connection stays alive during quiet periods
 	go w.pingLoop()
 
-	// writeQueue - FIFO queue
-	// writeLoop pushes first-in message to the socket
-	w.writeLoop(writeQueueChan, exitChan)
+	// writeQueue - FIFO queue of 1-to-1 messages, which gets merged
+	// into the priority queue
+	quit := make(chan struct{})
+	defer close(quit)
+	go w.mergeWriteQueue(writeQueueChan, quit)
+
+	// writeLoop pushes the highest priority message to the socket
+	w.writeLoop(exitChan)
+}
+
+// mergeWriteQueue moves the messages from the writeQueue onto the priority
+// queue, until quit is closed.
+func (w *Writer) mergeWriteQueue(writeQueueChan <-chan *bytes.Buffer, quit <-chan struct{}) {
+	for {
+		select {
+		case buf := <-writeQueueChan:
+			w.queue.push(buf)
+		case <-quit:
+			return
+		}
+	}
+}
+
+// Stream queues a message with the given topic on the outgoing queue. Its
+// payload is encoded by enc straight onto the connection, so that large
+// messages (like blocks) are not held in memory while waiting in the queue.
+func (w *Writer) Stream(topic topics.Topic, enc func(io.Writer) error) {
+	w.queue.pushStream(topic, enc)
 }
 
 func (w *Writer) onDisconnect() {
@@ -206,31 +257,28 @@ func (w *Writer) ping() error {
 		return err
 	}
 
-	if err := w.gossip.Process(buf); err != nil {
-		return err
-	}
-
-	_, err := w.Connection.Write(buf.Bytes())
-	return err
+	return w.Connection.WriteFrame(buf.Bytes())
 }
 
-func (w *Writer) writeLoop(writeQueueChan <-chan *bytes.Buffer, exitChan chan struct{}) {
+func (w *Writer) writeLoop(exitChan chan struct{}) {
 
 	for {
-		select {
-		case buf := <-writeQueueChan:
-			if err := w.gossip.Process(buf); err != nil {
-				l.WithError(err).Warnln("error processing outgoing message")
-				continue
-			}
-
-			if _, err := w.Connection.Write(buf.Bytes()); err != nil {
-				l.WithField("queue", "writequeue").WithError(err).Warnln("error writing message")
-				exitChan <- struct{}{}
-			}
-		case <-exitChan:
+		m, ok := w.queue.pop(exitChan)
+		if !ok {
 			return
 		}
+
+		var err error
+		if m.encode != nil {
+			err = w.Connection.WriteStream(m.topic, m.encode)
+		} else {
+			err = w.Connection.WriteFrame(m.Bytes())
+		}
+
+		if err != nil {
+			l.WithField("queue", "writequeue").WithError(err).Warnln("error writing message")
+			exitChan <- struct{}{}
+		}
 	}
 }
 
@@ -264,6 +312,8 @@ func (p *Reader) ReadLoop() {
 			return
 		}
 
+		messagesReceived.Inc()
+		bytesReceived.Add(uint64(len(b)))
 		p.router.Collect(bytes.NewBuffer(message))
 	}
 }
@@ -279,6 +329,54 @@ func (c *Connection) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// WriteFrame frames the message m and writes it to the connection. The
+// frame is written without copying m, to keep the memory footprint of large
+// messages down.
+func (c *Connection) WriteFrame(m []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Conn.SetWriteDeadline(time.Now().Add(readWriteTimeout))
+	bw := bufio.NewWriter(c.Conn)
+	if err := c.gossip.WriteTo(bw, m); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// WriteStream writes a message with the given topic to the connection,
+// streaming the payload produced by enc (e.g. marshalling.EncodeBlock).
+// Unlike WriteFrame, the message never needs to be fully held in memory.
+func (c *Connection) WriteStream(topic topics.Topic, enc func(io.Writer) error) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Conn.SetWriteDeadline(time.Now().Add(readWriteTimeout))
+	bw := bufio.NewWriter(c.Conn)
+	err := c.gossip.ProcessStream(bw, func(w io.Writer) error {
+		if err := topics.Write(w, topic); err != nil {
+			return err
+		}
+
+		return enc(w)
+	})
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// Services returns the services advertised by the peer during the handshake.
+func (c *Connection) Services() protocol.ServiceFlag {
+	return c.services
+}
+
+// SetServices records the services of the peer, for a Writer on a connection
+// on which the handshake was performed by the Reader.
+func (w *Writer) SetServices(services protocol.ServiceFlag) {
+	w.services = services
+}
+
 // Addr returns the peer's address as a string.
 func (c *Connection) Addr() string {
 	return c.Conn.RemoteAddr().String()
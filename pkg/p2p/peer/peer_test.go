@@ -111,6 +111,37 @@ func TestWriteLoop(t *testing.T) {
 	assert.Equal(t, decoded, buf.Bytes())
 }
 
+// Test that streamed messages are framed like the queued ones.
+func TestWriteStream(t *testing.T) {
+	bus := eventbus.New()
+	client, srv := net.Pipe()
+
+	g := processing.NewGossip(protocol.TestNet)
+	buf := makeAgreementBuffer(10)
+	go func(g *processing.Gossip) {
+		writer := peer.NewWriter(client, g, bus)
+		go writer.Serve(make(chan *bytes.Buffer), make(chan struct{}, 1))
+
+		payload := buf.Bytes()[1:]
+		writer.Stream(topics.Topic(buf.Bytes()[0]), func(w io.Writer) error {
+			_, err := w.Write(payload)
+			return err
+		})
+	}(g)
+
+	length, err := g.UnpackLength(srv)
+	assert.NoError(t, err)
+
+	decoded := make([]byte, length)
+	_, err = io.ReadFull(srv, decoded)
+	assert.NoError(t, err)
+
+	// Remove checksum
+	decoded = decoded[4:]
+
+	assert.Equal(t, decoded, buf.Bytes())
+}
+
 // Test that the 'ping' message is sent correctly, and that a 'pong' message will result.
 func TestPingLoop(t *testing.T) {
 	bus := eventbus.New()
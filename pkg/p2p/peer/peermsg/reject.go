@@ -0,0 +1,173 @@
+package peermsg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// RejectCode identifies the reason for which a message was rejected.
+type RejectCode uint8
+
+const (
+	// RejectMalformed is used for messages which could not be decoded
+	RejectMalformed RejectCode = 0x01
+	// RejectInvalid is used for messages which failed validation
+	RejectInvalid RejectCode = 0x10
+	// RejectObsolete is used for messages which are no longer relevant,
+	// for instance a vote for a past round
+	RejectObsolete RejectCode = 0x11
+	// RejectDuplicate is used for items we already know about
+	RejectDuplicate RejectCode = 0x12
+	// RejectDoubleSpend is used for transactions spending already spent
+	// outputs
+	RejectDoubleSpend RejectCode = 0x13
+	// RejectNonStandard is used for transactions which are valid, but
+	// which the node does not relay
+	RejectNonStandard RejectCode = 0x40
+	// RejectInsufficientFee is used for transactions paying a fee below
+	// the node's minimum
+	RejectInsufficientFee RejectCode = 0x42
+)
+
+// maxReasonLength caps the reason string, so that a peer can not make us
+// allocate arbitrary amounts of memory with a reject message.
+const maxReasonLength = 256
+
+var rejectCodeStrings = map[RejectCode]string{
+	RejectMalformed:       "malformed",
+	RejectInvalid:         "invalid",
+	RejectObsolete:        "obsolete",
+	RejectDuplicate:       "duplicate",
+	RejectDoubleSpend:     "double-spend",
+	RejectNonStandard:     "non-standard",
+	RejectInsufficientFee: "insufficient-fee",
+}
+
+func (c RejectCode) String() string {
+	if s, ok := rejectCodeStrings[c]; ok {
+		return s
+	}
+
+	return fmt.Sprintf("unknown(%d)", uint8(c))
+}
+
+// Reject defines a reject message on the Dusk wire protocol. It is sent
+// back to the network when a block, transaction or vote fails validation,
+// so that the operator of the originating node can see why the message
+// was dropped.
+type Reject struct {
+	// Topic of the rejected message
+	Topic topics.Topic
+	// Code denoting the reason of the rejection
+	Code RejectCode
+	// Reason is a human-readable description of the failure
+	Reason string
+	// Hash of the rejected item, if any
+	Hash []byte
+}
+
+// NewReject returns a Reject message for the item identified by hash. The
+// reason gets truncated if it exceeds the maximum allowed length.
+func NewReject(topic topics.Topic, code RejectCode, reason string, hash []byte) *Reject {
+	if len(reason) > maxReasonLength {
+		// Avoid cutting a multi-byte character in half
+		n := maxReasonLength
+		for n > 0 && !utf8.RuneStart(reason[n]) {
+			n--
+		}
+		reason = reason[:n]
+	}
+
+	return &Reject{
+		Topic:  topic,
+		Code:   code,
+		Reason: reason,
+		Hash:   hash,
+	}
+}
+
+// Encode a Reject struct and write it to w.
+func (r *Reject) Encode(w *bytes.Buffer) error {
+	if len(r.Reason) > maxReasonLength {
+		return errors.New("reject reason is too long")
+	}
+
+	if err := topics.Write(w, r.Topic); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteUint8(w, uint8(r.Code)); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteUTF8String(w, r.Reason); err != nil {
+		return err
+	}
+
+	// An absent hash is signalled with a false flag, to keep the message
+	// short for items which can't be identified by hash
+	hasHash := len(r.Hash) > 0
+	if err := encoding.WriteBool(w, hasHash); err != nil {
+		return err
+	}
+
+	if hasHash {
+		if len(r.Hash) != 32 {
+			return fmt.Errorf("invalid reject hash size %d", len(r.Hash))
+		}
+
+		return encoding.Write256(w, r.Hash)
+	}
+
+	return nil
+}
+
+// Decode a Reject struct from b into r.
+func (r *Reject) Decode(b *bytes.Buffer) error {
+	topic, err := topics.Extract(b)
+	if err != nil {
+		return err
+	}
+	r.Topic = topic
+
+	var code uint8
+	if err := encoding.ReadUint8(b, &code); err != nil {
+		return err
+	}
+	r.Code = RejectCode(code)
+
+	reason, err := encoding.ReadUTF8String(b, maxReasonLength)
+	if err != nil {
+		return err
+	}
+	r.Reason = reason
+
+	var hasHash bool
+	if err := encoding.ReadBool(b, &hasHash); err != nil {
+		return err
+	}
+
+	r.Hash = nil
+	if hasHash {
+		r.Hash = make([]byte, 32)
+		return encoding.Read256(b, r.Hash)
+	}
+
+	return nil
+}
+
+// String returns a printable representation of the Reject message.
+func (r *Reject) String() string {
+	return fmt.Sprintf("%s rejected (%s): %s", r.Topic.String(), r.Code.String(), r.Reason)
+}
+
+// Error implements the error interface, so that the submitter of a rejected
+// item can be told why, along with the code.
+func (r *Reject) Error() string {
+	return r.String()
+}
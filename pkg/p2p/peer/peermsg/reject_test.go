@@ -0,0 +1,60 @@
+package peermsg_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/peermsg"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecodeReject(t *testing.T) {
+	hash, _ := crypto.RandEntropy(32)
+	reject := peermsg.NewReject(topics.Tx, peermsg.RejectDoubleSpend, "double-spending in mempool", hash)
+	buf := new(bytes.Buffer)
+	if err := reject.Encode(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	reject2 := &peermsg.Reject{}
+	if err := reject2.Decode(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, reject, reject2)
+}
+
+func TestEncodeDecodeRejectNoHash(t *testing.T) {
+	reject := peermsg.NewReject(topics.Agreement, peermsg.RejectObsolete, "", nil)
+	buf := new(bytes.Buffer)
+	if err := reject.Encode(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	reject2 := &peermsg.Reject{}
+	if err := reject2.Decode(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, reject, reject2)
+}
+
+func TestRejectReasonLimit(t *testing.T) {
+	// NewReject truncates long reasons
+	reason := strings.Repeat("a", 1000)
+	reject := peermsg.NewReject(topics.Block, peermsg.RejectInvalid, reason, nil)
+	assert.Equal(t, 256, len(reject.Reason))
+
+	// Decoding a reason which is too long should fail
+	buf := new(bytes.Buffer)
+	_ = topics.Write(buf, topics.Block)
+	_ = encoding.WriteUint8(buf, uint8(peermsg.RejectInvalid))
+	_ = encoding.WriteString(buf, reason)
+	_ = encoding.WriteBool(buf, false)
+
+	assert.Error(t, (&peermsg.Reject{}).Decode(buf))
+}
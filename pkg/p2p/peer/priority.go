@@ -0,0 +1,115 @@
+package peer
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// lane is a priority class for outgoing messages. Lower values are sent
+// first.
+type lane uint8
+
+const (
+	// highLane carries consensus messages, which are time-critical
+	highLane lane = iota
+	// normalLane carries everything that does not fit the other lanes
+	normalLane
+	// lowLane carries bulk data, like blocks during synchronization and
+	// inventory messages
+	lowLane
+
+	numLanes
+)
+
+// laneCapacity is the amount of messages each lane can hold before the
+// producers start blocking.
+const laneCapacity = 1000
+
+// laneOf returns the lane a message belongs to, based on its topic.
+func laneOf(m *bytes.Buffer) lane {
+	if m.Len() == 0 {
+		return normalLane
+	}
+
+	return topicLane(topics.Topic(m.Bytes()[0]))
+}
+
+func topicLane(topic topics.Topic) lane {
+	switch topic {
+	case topics.Agreement, topics.Reduction, topics.Score:
+		return highLane
+	case topics.Block, topics.Inv, topics.GetData, topics.MemPool:
+		return lowLane
+	}
+
+	return normalLane
+}
+
+// isHighPriority returns true for messages belonging to the high lane.
+func isHighPriority(m []byte) bool {
+	return laneOf(bytes.NewBuffer(m)) == highLane
+}
+
+// message is an entry of the outgoing queue. It either holds a marshalled
+// message, or the function encoding the payload of a streamed one.
+type message struct {
+	*bytes.Buffer
+	topic  topics.Topic
+	encode func(io.Writer) error
+}
+
+// priorityQueue is the outgoing message queue of a peer. Messages are
+// sorted in lanes according to their topic, and higher priority lanes are
+// always drained first. This way, a peer synchronizing from us can not delay
+// our votes.
+type priorityQueue struct {
+	lanes [numLanes]chan *message
+}
+
+func newPriorityQueue() *priorityQueue {
+	q := &priorityQueue{}
+	for i := range q.lanes {
+		q.lanes[i] = make(chan *message, laneCapacity)
+	}
+
+	return q
+}
+
+// push a message on the queue. It blocks if the lane of the message is full.
+func (q *priorityQueue) push(m *bytes.Buffer) {
+	q.lanes[laneOf(m)] <- &message{Buffer: m}
+}
+
+// pushStream queues a message with the given topic, whose payload is only
+// encoded by enc once the message is written. It blocks if the lane of the
+// message is full.
+func (q *priorityQueue) pushStream(topic topics.Topic, enc func(io.Writer) error) {
+	q.lanes[topicLane(topic)] <- &message{topic: topic, encode: enc}
+}
+
+// pop returns the message with the highest priority, blocking until one is
+// available. It returns false if quit fires first.
+func (q *priorityQueue) pop(quit <-chan struct{}) (*message, bool) {
+	// Check the lanes in order of priority first
+	for _, l := range q.lanes {
+		select {
+		case m := <-l:
+			return m, true
+		default:
+		}
+	}
+
+	// Nothing queued, wait for the first message on any lane
+	select {
+	case m := <-q.lanes[highLane]:
+		return m, true
+	case m := <-q.lanes[normalLane]:
+		return m, true
+	case m := <-q.lanes[lowLane]:
+		return m, true
+	case <-quit:
+		return nil, false
+	}
+}
@@ -0,0 +1,53 @@
+package peer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure consensus messages overtake bulk data in the outgoing queue.
+func TestPriorityQueue(t *testing.T) {
+	q := newPriorityQueue()
+
+	block := topics.Block.ToBuffer()
+	tx := topics.Tx.ToBuffer()
+	agreement := topics.Agreement.ToBuffer()
+	q.push(&block)
+	q.push(&tx)
+	q.push(&agreement)
+
+	quit := make(chan struct{})
+	for _, expected := range []topics.Topic{topics.Agreement, topics.Tx, topics.Block} {
+		m, ok := q.pop(quit)
+		assert.True(t, ok)
+		assert.Equal(t, byte(expected), m.Bytes()[0])
+	}
+
+	// An empty queue should return once quit fires
+	close(quit)
+	_, ok := q.pop(quit)
+	assert.False(t, ok)
+}
+
+func TestLaneOf(t *testing.T) {
+	for topic, expected := range map[topics.Topic]lane{
+		topics.Reduction: highLane,
+		topics.Score:     highLane,
+		topics.Inv:       lowLane,
+		topics.Candidate: normalLane,
+	} {
+		buf := topics.Topics[topic].Buffer
+		assert.Equal(t, expected, laneOf(bytes.NewBuffer(buf.Bytes())))
+	}
+
+	assert.Equal(t, normalLane, laneOf(new(bytes.Buffer)))
+}
+
+func TestIsHighPriority(t *testing.T) {
+	assert.True(t, isHighPriority([]byte{byte(topics.Agreement), 1, 2}))
+	assert.False(t, isHighPriority([]byte{byte(topics.Block), 1, 2}))
+	assert.False(t, isHighPriority(nil))
+}
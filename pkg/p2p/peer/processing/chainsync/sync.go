@@ -85,10 +85,12 @@ func (s *ChainSynchronizer) Synchronize(blkBuf *bytes.Buffer, peerInfo string) e
 
 		s.responseChan <- buf
 		s.StartSyncing(uint64(diff))
-		return nil
 	}
 
-	if diff == 1 {
+	// Blocks ahead of the next one are forwarded during a sync, as they
+	// come in out of order from several peers. The `Chain` queues them
+	// until the blocks before them are accepted.
+	if diff == 1 || (diff > 1 && s.IsSyncing()) {
 		// Write bufio.Reader into a bytes.Buffer so we can send it over the event bus.
 		buf := new(bytes.Buffer)
 		if _, err := buf.ReadFrom(r); err != nil {
@@ -21,6 +21,8 @@ func TestSynchronizeBehind(t *testing.T) {
 	// Create a listener for HighestSeen topic
 	highestSeenChan := make(chan bytes.Buffer, 1)
 	eb.Subscribe(topics.HighestSeen, eventbus.NewChanListener(highestSeenChan))
+	blockChan := make(chan bytes.Buffer, 1)
+	eb.Subscribe(topics.Block, eventbus.NewChanListener(blockChan))
 
 	// Create a block that is a few rounds in the future
 	height := uint64(5)
@@ -44,6 +46,10 @@ func TestSynchronizeBehind(t *testing.T) {
 	var highestSeenHeight uint64
 	assert.NoError(t, encoding.ReadUint64LE(&m, &highestSeenHeight))
 	assert.Equal(t, highestSeenHeight, height)
+
+	// The block is forwarded to the `Chain`, to be queued until the blocks
+	// before it are accepted
+	<-blockChan
 }
 
 // Check the behaviour of the ChainSynchronizer when receiving a block, when we
@@ -92,7 +98,7 @@ func setupSynchronizer(t *testing.T) (*chainsync.ChainSynchronizer, *eventbus.Ev
 // requests the last block.
 func respond(t *testing.T, rpcBus *rpcbus.RPCBus) {
 	g := make(chan rpcbus.Request, 1)
-	rpcBus.Register(rpcbus.GetLastBlock, g)
+	rpcBus.RegisterChan(rpcbus.GetLastBlock, g)
 	r := <-g
 	r.RespChan <- rpcbus.Response{*randomBlockBuffer(t, 0, 1), nil}
 }
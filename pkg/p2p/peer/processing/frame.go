@@ -49,6 +49,33 @@ func WriteFrame(buf *bytes.Buffer, magic protocol.Magic, cs []byte) error {
 	return nil
 }
 
+// WriteFrameHeader writes the length-prefixing wire message frame for a
+// payload of payloadLen bytes to w. The payload is expected to be written
+// to w right after.
+func WriteFrameHeader(w io.Writer, magic protocol.Magic, cs []byte, payloadLen int) error {
+	ln := uint64(magic.Len() + checksum.Length + payloadLen)
+	if ln > MaxFrameSize {
+		return fmt.Errorf("message size exceeds MaxFrameSize (%d)", MaxFrameSize)
+	}
+
+	hdr := new(bytes.Buffer)
+	if err := encoding.WriteUint64LE(hdr, ln); err != nil {
+		return err
+	}
+
+	mBuf := magic.ToBuffer()
+	if _, err := hdr.Write(mBuf.Bytes()); err != nil {
+		return err
+	}
+
+	if _, err := hdr.Write(cs); err != nil {
+		return err
+	}
+
+	_, err := hdr.WriteTo(w)
+	return err
+}
+
 func ReadFrame(r io.Reader) (uint64, error) {
 	var length uint64
 	sizeBytes := make([]byte, 8)
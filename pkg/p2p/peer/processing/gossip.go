@@ -3,6 +3,7 @@ package processing
 import (
 	"bytes"
 	"errors"
+	"hash"
 	"io"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/checksum"
@@ -30,6 +31,48 @@ func (g *Gossip) Process(m *bytes.Buffer) error {
 	return WriteFrame(m, g.Magic, cs)
 }
 
+// ProcessStream writes a complete gossip frame to w, with the payload being
+// produced by enc. The payload is encoded twice: a first pass calculates its
+// length and checksum, which need to precede it on the wire, and a second
+// pass writes it to w. This way the message is never held in memory in its
+// entirety, at the cost of encoding it twice.
+func (g *Gossip) ProcessStream(w io.Writer, enc func(io.Writer) error) error {
+	s := &summer{Hash: checksum.NewHash()}
+	if err := enc(s); err != nil {
+		return err
+	}
+
+	cs := s.Sum(nil)[:checksum.Length]
+	if err := WriteFrameHeader(w, g.Magic, cs, s.n); err != nil {
+		return err
+	}
+
+	return enc(w)
+}
+
+// WriteTo writes the frame for payload m to w, without copying m into a new
+// buffer like Process does.
+func (g *Gossip) WriteTo(w io.Writer, m []byte) error {
+	cs := checksum.Generate(m)
+	if err := WriteFrameHeader(w, g.Magic, cs, len(m)); err != nil {
+		return err
+	}
+
+	_, err := w.Write(m)
+	return err
+}
+
+// summer keeps track of the length and checksum of the data written to it.
+type summer struct {
+	hash.Hash
+	n int
+}
+
+func (s *summer) Write(b []byte) (int, error) {
+	s.n += len(b)
+	return s.Hash.Write(b)
+}
+
 // UnpackLength unwraps the incoming packet (likely from a net.Conn struct) and returns the length of the packet without reading the payload (which is left to the user of this method)
 func (g *Gossip) UnpackLength(r io.Reader) (uint64, error) {
 	packetLength, err := ReadFrame(r)
@@ -3,6 +3,7 @@ package processing_test
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing"
@@ -48,3 +49,35 @@ func TestUnpackLength(t *testing.T) {
 	// Checksum was added, so we remove 4 from int(length)
 	assert.Equal(t, len(test), int(length)-4)
 }
+
+// Ensure that WriteTo produces the same frame as Process.
+func TestWriteTo(t *testing.T) {
+	g := processing.NewGossip(protocol.DevNet)
+
+	expected := bytes.NewBufferString("pippo")
+	assert.NoError(t, g.Process(expected))
+
+	written := new(bytes.Buffer)
+	assert.NoError(t, g.WriteTo(written, []byte("pippo")))
+	assert.Equal(t, expected.Bytes(), written.Bytes())
+}
+
+// Ensure that ProcessStream produces the same frame as Process, regardless of
+// how the payload is chunked.
+func TestProcessStream(t *testing.T) {
+	g := processing.NewGossip(protocol.DevNet)
+
+	expected := bytes.NewBufferString("pippo")
+	assert.NoError(t, g.Process(expected))
+
+	streamed := new(bytes.Buffer)
+	assert.NoError(t, g.ProcessStream(streamed, func(w io.Writer) error {
+		if _, err := w.Write([]byte("pip")); err != nil {
+			return err
+		}
+
+		_, err := w.Write([]byte("po"))
+		return err
+	}))
+	assert.Equal(t, expected.Bytes(), streamed.Bytes())
+}
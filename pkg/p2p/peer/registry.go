@@ -0,0 +1,320 @@
+package peer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrPeerNotConnected is returned when sending a message to a peer
+	// which is not connected
+	ErrPeerNotConnected = errors.New("peer not connected")
+	// ErrQueueFull is returned when the write queue of a peer is full
+	ErrQueueFull = errors.New("write queue of the peer is full")
+)
+
+// rejectionScore is the ban score added for every message of a peer refused
+// by a validator
+const rejectionScore = 10
+
+var connectedPeers = metrics.NewGauge("dusk_peer_connected", "Peers connected after a successful handshake")
+
+// Info describes a connected peer.
+type Info struct {
+	Address string
+	// Inbound is true for connections initiated by the peer
+	Inbound bool
+	// Since is the unix time at which the handshake completed
+	Since int64
+}
+
+// Registry keeps track of the peers we are connected to. Its content is
+// served on the RPCBus through the GetPeerInfo method, and messages are sent
+// to a single peer through the SendToPeer method.
+//
+// Misbehaving hosts accumulate a ban score, and get banned once it reaches
+// network.banThreshold. Peers connecting to us are refused beyond
+// network.maxInboundPeers.
+type Registry struct {
+	lock   sync.RWMutex
+	peers  map[string]Info
+	conns  map[string]io.Closer
+	queues map[string]chan<- *bytes.Buffer
+	banned map[string]struct{}
+	scores map[string]uint32
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		peers:  make(map[string]Info),
+		conns:  make(map[string]io.Closer),
+		queues: make(map[string]chan<- *bytes.Buffer),
+		banned: make(map[string]struct{}),
+		scores: make(map[string]uint32),
+	}
+}
+
+// Add a peer after a successful handshake. The connection is closed if the
+// peer gets banned. The messages sent to the peer alone are pushed on queue.
+func (r *Registry) Add(address string, inbound bool, conn io.Closer, queue chan<- *bytes.Buffer) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.peers[address] = Info{
+		Address: address,
+		Inbound: inbound,
+		Since:   time.Now().Unix(),
+	}
+	r.conns[address] = conn
+	r.queues[address] = queue
+	connectedPeers.Set(float64(len(r.peers)))
+}
+
+// Remove a peer once the connection is terminated.
+func (r *Registry) Remove(address string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.peers, address)
+	delete(r.conns, address)
+	delete(r.queues, address)
+	connectedPeers.Set(float64(len(r.peers)))
+}
+
+// Ban the host of address, and disconnect all the peers connected from it.
+// Bans last until the node is restarted.
+func (r *Registry) Ban(address string) {
+	host := hostOf(address)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.banned[host] = struct{}{}
+	for addr, conn := range r.conns {
+		if hostOf(addr) == host {
+			_ = conn.Close()
+		}
+	}
+}
+
+// DisconnectAll closes the connections to all the peers, which are removed
+// as their connection terminates
+func (r *Registry) DisconnectAll() {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, conn := range r.conns {
+		_ = conn.Close()
+	}
+}
+
+// AcceptsInbound returns false once network.maxInboundPeers peers are
+// connected from incoming connections. A zero maximum does not limit them.
+func (r *Registry) AcceptsInbound() bool {
+	max := cfg.Get().Network.MaxInboundPeers
+	if max == 0 {
+		return true
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var inbound uint32
+	for _, info := range r.peers {
+		if info.Inbound {
+			inbound++
+		}
+	}
+	return inbound < max
+}
+
+// Misbehaving adds score to the ban score of the host of address, and bans it
+// if the score reaches network.banThreshold. It returns true if the host got
+// banned. A zero threshold disables banning on misbehavior.
+func (r *Registry) Misbehaving(address string, score uint32) bool {
+	threshold := cfg.Get().Network.BanThreshold
+	if threshold == 0 {
+		return false
+	}
+
+	host := hostOf(address)
+	r.lock.Lock()
+	r.scores[host] += score
+	banned := r.scores[host] >= threshold
+	if banned {
+		delete(r.scores, host)
+	}
+	r.lock.Unlock()
+
+	if banned {
+		r.Ban(host)
+	}
+	return banned
+}
+
+// ListenRejections increases the ban score of the peers whose messages are
+// refused, as reported on the RejectedMessage topic.
+func (r *Registry) ListenRejections(sub eventbus.Subscriber) uint32 {
+	return eventbus.SubscribeMessage(sub, topics.RejectedMessage, newRejection, r.onRejection)
+}
+
+func newRejection() eventbus.Unmarshaler {
+	return &eventbus.Rejection{}
+}
+
+func (r *Registry) onRejection(m eventbus.Unmarshaler) error {
+	rejection := m.(*eventbus.Rejection)
+	if rejection.Origin == "" {
+		return nil
+	}
+
+	if r.Misbehaving(rejection.Origin, rejectionScore) {
+		l.WithFields(log.Fields{
+			"peer":   rejection.Origin,
+			"topic":  rejection.Topic.String(),
+			"reason": rejection.Reason,
+		}).Warnln("peer banned for sending invalid messages")
+	}
+	return nil
+}
+
+// IsBanned returns true if the host of address has been banned.
+func (r *Registry) IsBanned(address string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	_, banned := r.banned[hostOf(address)]
+	return banned
+}
+
+// hostOf strips the port from an address, if any
+func hostOf(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+
+	return host
+}
+
+// Peers returns the connected peers, sorted by address.
+func (r *Registry) Peers() []Info {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	peers := make([]Info, 0, len(r.peers))
+	for _, info := range r.peers {
+		peers = append(peers, info)
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Address < peers[j].Address
+	})
+	return peers
+}
+
+// Send queues msg, prepended with its topic, on the connection to the peer at
+// address. Messages are dropped if the queue of the peer is full.
+func (r *Registry) Send(address string, msg *bytes.Buffer) error {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	queue, ok := r.queues[address]
+	if !ok || queue == nil {
+		return ErrPeerNotConnected
+	}
+
+	select {
+	case queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Listen answers GetPeerInfo and SendToPeer requests on the RPCBus.
+func (r *Registry) Listen(rpcBus *rpcbus.RPCBus) error {
+	if err := rpcBus.Register(rpcbus.GetPeerInfo, r.providePeerInfo); err != nil {
+		return err
+	}
+
+	return rpcBus.Register(rpcbus.SendToPeer, r.sendToPeer)
+}
+
+// sendToPeer sends the message in the params, as encoded by
+// rpcbus.MarshalSendToPeerRequest
+func (r *Registry) sendToPeer(req rpcbus.Request) (bytes.Buffer, error) {
+	address, err := encoding.ReadString(&req.Params)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	msg := bytes.NewBuffer(req.Params.Bytes())
+	return bytes.Buffer{}, r.Send(address, msg)
+}
+
+func (r *Registry) providePeerInfo(rpcbus.Request) (bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+	err := MarshalInfo(buf, r.Peers())
+	return *buf, err
+}
+
+// MarshalInfo encodes a list of peers into buf.
+func MarshalInfo(buf *bytes.Buffer, peers []Info) error {
+	if err := encoding.WriteVarInt(buf, uint64(len(peers))); err != nil {
+		return err
+	}
+
+	for _, p := range peers {
+		if err := encoding.WriteString(buf, p.Address); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteBool(buf, p.Inbound); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteTimestamp(buf, p.Since); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalInfo decodes a list of peers from buf.
+func UnmarshalInfo(buf *bytes.Buffer) ([]Info, error) {
+	n, err := encoding.ReadVarInt(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every entry takes at least 10 bytes, which bounds the allocation
+	if n > uint64(buf.Len()/10) {
+		return nil, errors.New("peer count exceeds buffer size")
+	}
+
+	peers := make([]Info, n)
+	for i := range peers {
+		if peers[i].Address, err = encoding.ReadString(buf); err != nil {
+			return nil, err
+		}
+
+		if err := encoding.ReadBool(buf, &peers[i].Inbound); err != nil {
+			return nil, err
+		}
+
+		if err := encoding.ReadTimestamp(buf, &peers[i].Since); err != nil {
+			return nil, err
+		}
+	}
+
+	return peers, nil
+}
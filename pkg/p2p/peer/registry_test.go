@@ -0,0 +1,119 @@
+package peer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/stretchr/testify/assert"
+)
+
+type closer struct {
+	closed bool
+}
+
+func (c *closer) Close() error {
+	c.closed = true
+	return nil
+}
+
+// Ensure banning a host disconnects all of its peers, and only them.
+func TestBan(t *testing.T) {
+	r := NewRegistry()
+
+	c1, c2, c3 := &closer{}, &closer{}, &closer{}
+	r.Add("10.0.0.1:7100", true, c1, nil)
+	r.Add("10.0.0.1:7101", false, c2, nil)
+	r.Add("10.0.0.2:7100", true, c3, nil)
+
+	r.Ban("10.0.0.1")
+	assert.True(t, c1.closed)
+	assert.True(t, c2.closed)
+	assert.False(t, c3.closed)
+
+	assert.True(t, r.IsBanned("10.0.0.1:7200"))
+	assert.False(t, r.IsBanned("10.0.0.2:7100"))
+}
+
+func TestDisconnectAll(t *testing.T) {
+	r := NewRegistry()
+	c1, c2 := &closer{}, &closer{}
+	r.Add("10.0.0.1:7100", true, c1, nil)
+	r.Add("10.0.0.2:7100", false, c2, nil)
+
+	r.DisconnectAll()
+	assert.True(t, c1.closed)
+	assert.True(t, c2.closed)
+	assert.False(t, r.IsBanned("10.0.0.1:7100"))
+}
+
+// Ensure peers sending refused messages get banned once over the threshold
+func TestBanOnRejections(t *testing.T) {
+	prev := config.Get()
+	defer config.Mock(&prev)
+	conf := prev
+	conf.Network.BanThreshold = 2 * rejectionScore
+	config.Mock(&conf)
+
+	eb := eventbus.New()
+	r := NewRegistry()
+	r.ListenRejections(eb)
+	c := &closer{}
+	r.Add("10.0.0.1:7100", true, c, nil)
+
+	rejection := eventbus.Rejection{Topic: topics.Candidate, Reason: "invalid", Origin: "10.0.0.1:7100"}
+	eventbus.PublishRejection(eb, rejection)
+	assert.False(t, r.IsBanned("10.0.0.1:7100"))
+
+	eventbus.PublishRejection(eb, rejection)
+	assert.True(t, r.IsBanned("10.0.0.1:7100"))
+	assert.True(t, c.closed)
+}
+
+// Ensure messages sent to a peer are queued on its connection only
+func TestSendToPeer(t *testing.T) {
+	rb := rpcbus.New()
+	r := NewRegistry()
+	assert.NoError(t, r.Listen(rb))
+
+	q1, q2 := make(chan *bytes.Buffer, 1), make(chan *bytes.Buffer, 1)
+	r.Add("10.0.0.1:7100", true, &closer{}, q1)
+	r.Add("10.0.0.2:7100", true, &closer{}, q2)
+
+	req := new(bytes.Buffer)
+	assert.NoError(t, rpcbus.MarshalSendToPeerRequest(req, "10.0.0.1:7100", bytes.NewBuffer([]byte{byte(topics.GetData), 1})))
+	_, err := rb.Call(rpcbus.SendToPeer, rpcbus.NewRequest(*req), time.Second)
+	assert.NoError(t, err)
+
+	msg := <-q1
+	assert.Equal(t, []byte{byte(topics.GetData), 1}, msg.Bytes())
+	assert.Empty(t, q2)
+
+	// Messages are dropped once the queue of the peer is full
+	assert.NoError(t, r.Send("10.0.0.2:7100", new(bytes.Buffer)))
+	assert.Equal(t, ErrQueueFull, r.Send("10.0.0.2:7100", new(bytes.Buffer)))
+	assert.Equal(t, ErrPeerNotConnected, r.Send("10.0.0.3:7100", new(bytes.Buffer)))
+}
+
+// Ensure inbound peers are refused beyond the maximum, outbound ones aside
+func TestAcceptsInbound(t *testing.T) {
+	prev := config.Get()
+	defer config.Mock(&prev)
+	conf := prev
+	conf.Network.MaxInboundPeers = 1
+	config.Mock(&conf)
+
+	r := NewRegistry()
+	r.Add("10.0.0.1:7100", false, &closer{}, nil)
+	assert.True(t, r.AcceptsInbound())
+
+	r.Add("10.0.0.2:7100", true, &closer{}, nil)
+	assert.False(t, r.AcceptsInbound())
+
+	r.Remove("10.0.0.2:7100")
+	assert.True(t, r.AcceptsInbound())
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/candidate"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/transactions"
 )
 
 type CandidateBroker struct {
@@ -17,8 +19,11 @@ func NewCandidateBroker(rpcBus *rpcbus.RPCBus, responseChan chan<- *bytes.Buffer
 	return &CandidateBroker{rpcBus, responseChan}
 }
 
+// ProvideCandidate answers a `GetCandidate` request from a peer, with the
+// candidate of the requested hash. Only the candidates held by this node are
+// sent, the request is not forwarded to other peers.
 func (c *CandidateBroker) ProvideCandidate(m *bytes.Buffer) error {
-	candidateBytes, err := c.rpcBus.Call(rpcbus.GetCandidate, rpcbus.Request{*m, make(chan rpcbus.Response, 1)}, 5*time.Second)
+	candidateBytes, err := c.rpcBus.Call(rpcbus.GetLocalCandidate, rpcbus.NewRequest(*m), 5*time.Second)
 	if err != nil {
 		return err
 	}
@@ -30,3 +35,48 @@ func (c *CandidateBroker) ProvideCandidate(m *bytes.Buffer) error {
 	c.responseChan <- &candidateBytes
 	return nil
 }
+
+// ExpandCandidate rebuilds the candidate of a `CompactCandidate` message out of
+// the transactions in the mempool, and returns it encoded as a `Candidate`
+// message, without the topic. If some of the transactions are missing, the
+// full candidate is requested from the peer with a `GetCandidate` message
+// instead, and nil is returned.
+func (c *CandidateBroker) ExpandCandidate(m *bytes.Buffer) (*bytes.Buffer, error) {
+	cc, err := candidate.DecodeCompact(m)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the txs of the candidate are looked up. As an empty TxID returns
+	// the whole content of the mempool, the mempool is not called for a
+	// candidate holding only its coinbase
+	var txs []transactions.Transaction
+	if len(cc.TxIDs) > 0 {
+		txs, err = GetMempoolTxs(c.rpcBus, bytes.Join(cc.TxIDs, nil))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cm, missing, err := cc.Expand(txs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		req := bytes.NewBuffer(cc.Header.Hash)
+		if err := topics.Prepend(req, topics.GetCandidate); err != nil {
+			return nil, err
+		}
+
+		c.responseChan <- req
+		return nil, nil
+	}
+
+	buf := new(bytes.Buffer)
+	if err := candidate.Encode(buf, cm); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
@@ -41,7 +41,7 @@ func provideCandidate(rb *rpcbus.RPCBus, correctHash []byte) chan struct{} {
 	quitChan := make(chan struct{}, 1)
 	reqChan := make(chan rpcbus.Request, 1)
 
-	rb.Register(rpcbus.GetCandidate, reqChan)
+	rb.RegisterChan(rpcbus.GetLocalCandidate, reqChan)
 
 	go func(reqChan chan rpcbus.Request, quitChan chan struct{}, correctHash []byte) {
 		for {
@@ -2,6 +2,7 @@ package responding
 
 import (
 	"bytes"
+	"io"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
@@ -13,6 +14,12 @@ import (
 	"github.com/dusk-network/dusk-wallet/transactions"
 )
 
+// Streamer queues a message for a peer, with its payload being encoded only
+// once the message is written to the connection.
+type Streamer interface {
+	Stream(topic topics.Topic, enc func(io.Writer) error)
+}
+
 // DataBroker is a processing unit responsible for handling GetData messages. It
 // maintains a connection to the outgoing message queue of the peer it receives this
 // message from.
@@ -20,6 +27,7 @@ type DataBroker struct {
 	db           database.DB
 	responseChan chan<- *bytes.Buffer
 	rpcBus       *rpcbus.RPCBus
+	streamer     Streamer
 }
 
 // NewDataBroker returns an initialized DataBroker.
@@ -31,6 +39,15 @@ func NewDataBroker(db database.DB, rpcBus *rpcbus.RPCBus, responseChan chan<- *b
 	}
 }
 
+// SetStreamer makes the DataBroker stream the requested blocks through s,
+// rather than sending them marshalled on the response channel. A node
+// synchronizing from us can request up to a full batch of blocks at once,
+// which would otherwise all be held in memory, in their encoded form, until
+// they are written.
+func (d *DataBroker) SetStreamer(s Streamer) {
+	d.streamer = s
+}
+
 // SendItems takes a GetData message from the wire, and iterates through the list,
 // sending back each item's complete data to the requesting peer.
 func (d *DataBroker) SendItems(m *bytes.Buffer) error {
@@ -56,6 +73,20 @@ func (d *DataBroker) SendItems(m *bytes.Buffer) error {
 				return err
 			}
 
+			// Blocks below an imported chain snapshot are stored without
+			// their txs, as are all the blocks in headers-only mode, and
+			// would not pass verification
+			if len(b.Txs) == 0 || config.Get().Chain.HeadersOnly {
+				continue
+			}
+
+			if d.streamer != nil {
+				d.streamer.Stream(topics.Block, func(w io.Writer) error {
+					return marshalling.EncodeBlock(w, b)
+				})
+				continue
+			}
+
 			// Send the block data back to the initiator node as topics.Block msg
 			if buf, err = marshalBlock(b); err != nil {
 				return err
@@ -111,7 +142,7 @@ func (d *DataBroker) SendTxsItems() error {
 		msg := &peermsg.Inv{}
 
 		for _, tx := range txs {
-			txID, err := tx.CalculateHash()
+			txID, err := marshalling.TxID(tx)
 			if err != nil {
 				return err
 			}
@@ -135,32 +166,22 @@ func (d *DataBroker) SendTxsItems() error {
 	return nil
 }
 
+// Marshalling directly onto the topic buffer avoids copying the whole
+// message for an explicit Prepend.
 func marshalBlock(b *block.Block) (*bytes.Buffer, error) {
-	//TODO: following is more efficient, saves an allocation and avoids the explicit Prepend
-	// buf := topics.Topics[topics.Block].Buffer
-	buf := new(bytes.Buffer)
-	if err := marshalling.MarshalBlock(buf, b); err != nil {
-		return nil, err
-	}
-
-	if err := topics.Prepend(buf, topics.Block); err != nil {
+	buf := topics.Block.ToBuffer()
+	if err := marshalling.MarshalBlock(&buf, b); err != nil {
 		return nil, err
 	}
 
-	return buf, nil
+	return &buf, nil
 }
 
 func marshalTx(tx transactions.Transaction) (*bytes.Buffer, error) {
-	//TODO: following is more efficient, saves an allocation and avoids the explicit Prepend
-	// buf := topics.Topics[topics.Block].Buffer
-	buf := new(bytes.Buffer)
-	if err := marshalling.MarshalTx(buf, tx); err != nil {
-		return nil, err
-	}
-
-	if err := topics.Prepend(buf, topics.Tx); err != nil {
+	buf := topics.Tx.ToBuffer()
+	if err := marshalling.MarshalTx(&buf, tx); err != nil {
 		return nil, err
 	}
 
-	return buf, nil
+	return &buf, nil
 }
@@ -2,6 +2,7 @@ package responding_test
 
 import (
 	"bytes"
+	"io"
 	"testing"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
@@ -61,6 +62,62 @@ func TestSendData(t *testing.T) {
 	}
 }
 
+type streams []*bytes.Buffer
+
+func (s *streams) Stream(topic topics.Topic, enc func(io.Writer) error) {
+	buf := topic.ToBuffer()
+	if err := enc(&buf); err != nil {
+		panic(err)
+	}
+
+	*s = append(*s, &buf)
+}
+
+// Test that the requested blocks go through the Streamer, when one is set.
+func TestStreamData(t *testing.T) {
+	_, db := lite.CreateDBConnection()
+	defer db.Close()
+
+	hashes, blocks := generateBlocks(t, 5)
+	if err := storeBlocks(db, blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	responseChan := make(chan *bytes.Buffer, 100)
+	dataBroker := responding.NewDataBroker(db, nil, responseChan)
+	s := &streams{}
+	dataBroker.SetStreamer(s)
+
+	if err := dataBroker.SendItems(createGetDataBuffer(hashes...)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing should be sent on the response channel
+	if len(responseChan) != 0 {
+		t.Fatalf("%d messages sent on the response channel", len(responseChan))
+	}
+
+	if len(*s) != 5 {
+		t.Fatalf("expected 5 streamed blocks, got %d", len(*s))
+	}
+
+	for i, buf := range *s {
+		topic, _ := topics.Extract(buf)
+		if topic != topics.Block {
+			t.Fatalf("unexpected topic %s, expected Block", topic)
+		}
+
+		blk := block.NewBlock()
+		if err := marshalling.UnmarshalBlock(buf, blk); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(hashes[i], blk.Header.Hash) {
+			t.Fatal("streamed block has mismatched hash")
+		}
+	}
+}
+
 // TODO: probably specify somewhere a choice between block and tx type
 func createGetDataBuffer(hashes ...[]byte) *bytes.Buffer {
 	inv := &peermsg.Inv{}
@@ -115,7 +115,7 @@ func marshalGetData(getData *peermsg.Inv) (*bytes.Buffer, error) {
 }
 
 // GetMempoolTxs is a wire.GetMempoolTx API wrapper. Later it could be moved into
-// a separate utils pkg
+// a separate utils pkg. txID can also hold several TxIDs, concatenated
 func GetMempoolTxs(bus *rpcbus.RPCBus, txID []byte) ([]transactions.Transaction, error) {
 
 	buf := new(bytes.Buffer)
@@ -18,7 +18,7 @@ func NewRoundResultBroker(rpcBus *rpcbus.RPCBus, responseChan chan<- *bytes.Buff
 }
 
 func (r *RoundResultBroker) ProvideRoundResult(m *bytes.Buffer) error {
-	roundResultBuf, err := r.rpcBus.Call(rpcbus.GetRoundResults, rpcbus.Request{*m, make(chan rpcbus.Response, 1)}, 5*time.Second)
+	roundResultBuf, err := r.rpcBus.Call(rpcbus.GetRoundResults, rpcbus.NewRequest(*m), 5*time.Second)
 	if err != nil {
 		return err
 	}
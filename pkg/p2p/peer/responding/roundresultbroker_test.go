@@ -45,7 +45,7 @@ func provideRoundResult(rb *rpcbus.RPCBus, correctRound uint64) chan struct{} {
 	quitChan := make(chan struct{}, 1)
 	reqChan := make(chan rpcbus.Request, 1)
 
-	if err := rb.Register(rpcbus.GetRoundResults, reqChan); err != nil {
+	if err := rb.RegisterChan(rpcbus.GetRoundResults, reqChan); err != nil {
 		panic(err)
 	}
 
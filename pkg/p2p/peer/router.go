@@ -2,14 +2,18 @@ package peer
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/dupemap"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/peermsg"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing/chainsync"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/responding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/republisher"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -75,16 +79,24 @@ func (m *messageRouter) route(topic topics.Topic, b *bytes.Buffer) {
 		// of this message
 	case topics.GetRoundResults:
 		err = m.roundResultBroker.ProvideRoundResult(b)
+	case topics.Reject:
+		err = m.logReject(b)
 	case topics.GetCandidate:
 		// We only accept a certain request once, to avoid infinitely
 		// requesting the same block
 		if m.dupeMap.CanFwd(b) {
 			err = m.candidateBroker.ProvideCandidate(b)
 		}
+	case topics.CompactCandidate:
+		if m.dupeMap.CanFwd(b) {
+			err = m.routeCompactCandidate(b)
+		}
 	default:
 		if m.CanRoute(topic) {
 			if m.dupeMap.CanFwd(b) {
-				m.publisher.Publish(topic, b)
+				m.publish(topic, b)
+			} else {
+				republisher.Observe(topic, b.Bytes(), m.peerInfo)
 			}
 		} else {
 			err = fmt.Errorf("%s topic not routable", topic.String())
@@ -98,3 +110,65 @@ func (m *messageRouter) route(topic topics.Topic, b *bytes.Buffer) {
 		}).Errorf("problem handling message %s", topic.String())
 	}
 }
+
+// routeCompactCandidate expands a compact candidate, and routes it like any
+// `Candidate` message. Candidates which can not be expanded from the mempool
+// are requested in full from the peer.
+func (m *messageRouter) routeCompactCandidate(b *bytes.Buffer) error {
+	cm, err := m.candidateBroker.ExpandCandidate(b)
+	if err != nil || cm == nil {
+		return err
+	}
+
+	m.route(topics.Candidate, cm)
+	return nil
+}
+
+// publish a message received from the network. The message gets a fresh
+// correlation ID, so that it can be followed across the components of the
+// node.
+func (m *messageRouter) publish(topic topics.Topic, b *bytes.Buffer) {
+	p, ok := m.publisher.(eventbus.TracedPublisher)
+	if !ok {
+		if err := m.publisher.Publish(topic, b); err != nil {
+			m.reportRejection(topic, err)
+		}
+		return
+	}
+
+	id := tracing.NewID()
+	tracing.SetOrigin(id, m.peerInfo)
+	log.WithFields(log.Fields{
+		"process": "peer",
+		"peer":    m.peerInfo,
+		"topic":   topic.String(),
+		"trace":   id.String(),
+	}).Traceln("message entering the node")
+	if err := p.PublishTraced(topic, b, id); err != nil {
+		m.reportRejection(topic, err)
+	}
+}
+
+// reportRejection publishes the reason why a message received from the peer
+// was refused, so that the peer can be held accountable.
+func (m *messageRouter) reportRejection(topic topics.Topic, err error) {
+	eventbus.PublishRejection(m.publisher, eventbus.Rejection{Topic: topic, Reason: err.Error(), Origin: m.peerInfo})
+}
+
+// logReject surfaces a reject message sent by a peer, so that the node
+// operator can find out why a message was dropped by the network.
+func (m *messageRouter) logReject(b *bytes.Buffer) error {
+	reject := &peermsg.Reject{}
+	if err := reject.Decode(b); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"process": "peer",
+		"peer":    m.peerInfo,
+		"topic":   reject.Topic.String(),
+		"code":    reject.Code.String(),
+		"hash":    hex.EncodeToString(reject.Hash),
+	}).Warnf("message rejected by peer: %s", reject.Reason)
+	return nil
+}
@@ -21,7 +21,7 @@ func newVersionMessageBuffer(v *protocol.Version, services protocol.ServiceFlag)
 		return nil, err
 	}
 
-	if err := encoding.WriteUint64LE(buffer, uint64(time.Now().Unix())); err != nil {
+	if err := encoding.WriteTimestamp(buffer, time.Now().Unix()); err != nil {
 		return nil, err
 	}
 
@@ -40,13 +40,10 @@ func decodeVersionMessage(r *bytes.Buffer) (*VersionMessage, error) {
 		return nil, err
 	}
 
-	var time uint64
-	if err := encoding.ReadUint64LE(r, &time); err != nil {
+	if err := encoding.ReadTimestamp(r, &versionMessage.Timestamp); err != nil {
 		return nil, err
 	}
 
-	versionMessage.Timestamp = int64(time)
-
 	var services uint64
 	if err := encoding.ReadUint64LE(r, &services); err != nil {
 		return nil, err
@@ -2,6 +2,7 @@ package checksum
 
 import (
 	"bytes"
+	"hash"
 
 	"golang.org/x/crypto/blake2b"
 )
@@ -13,6 +14,15 @@ func Generate(m []byte) []byte {
 	return digest[:Length]
 }
 
+// NewHash returns the hash.Hash used for checksum generation, for callers
+// which produce the message incrementally. The checksum is made up of the
+// first Length bytes of its digest.
+func NewHash() hash.Hash {
+	// blake2b only errors on keys which are too long
+	h, _ := blake2b.New256(nil)
+	return h
+}
+
 func Extract(m []byte) ([]byte, []byte, error) {
 	// First 4 bytes are the checksum
 	checksum := m[:Length]
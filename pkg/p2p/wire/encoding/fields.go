@@ -0,0 +1,123 @@
+// Serialization functions for typed fields, such as UTF-8 strings, amounts
+// and timestamps. Like ReadVarInt, the reading functions refuse any
+// non-canonical encoding, so that a given value can only ever be represented
+// by a single byte sequence.
+
+package encoding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+const (
+	// MaxAmount is the highest amount that can be encoded. Amounts are kept
+	// within the positive int64 range, so that they can be safely converted
+	// to signed integers when doing arithmetic on them.
+	MaxAmount = uint64(math.MaxInt64)
+
+	// MaxTimestamp is the highest timestamp that can be encoded
+	// (9999-12-31T23:59:59Z, in unix seconds).
+	MaxTimestamp = int64(253402300799)
+)
+
+var (
+	// ErrInvalidUTF8 is returned when a string is not valid UTF-8
+	ErrInvalidUTF8 = errors.New("invalid UTF-8 string")
+	// ErrAmountOutOfRange is returned for amounts exceeding MaxAmount
+	ErrAmountOutOfRange = errors.New("amount out of range")
+	// ErrTimestampOutOfRange is returned for negative timestamps or
+	// timestamps exceeding MaxTimestamp
+	ErrTimestampOutOfRange = errors.New("timestamp out of range")
+)
+
+// ReadUTF8String reads a length-prefixed string from r, and ensures that it
+// is valid UTF-8 and no longer than maxLen bytes. The length is checked
+// before the string is read, so that a malicious length prefix can not make
+// us allocate large amounts of memory.
+func ReadUTF8String(r *bytes.Buffer, maxLen uint64) (string, error) {
+	l, err := ReadVarInt(r)
+	if err != nil {
+		return "", err
+	}
+
+	if l > maxLen {
+		return "", fmt.Errorf("string length %d exceeds maximum of %d", l, maxLen)
+	}
+
+	if l > uint64(r.Len()) {
+		return "", errors.New("string length exceeds buffer size")
+	}
+
+	b := r.Next(int(l))
+	// utf8.Valid rejects overlong encodings and surrogate halves, which
+	// leaves a single valid encoding for each code point
+	if !utf8.Valid(b) {
+		return "", ErrInvalidUTF8
+	}
+
+	return string(b), nil
+}
+
+// WriteUTF8String writes s to w as a length-prefixed string, after making
+// sure that it is valid UTF-8.
+func WriteUTF8String(w *bytes.Buffer, s string) error {
+	if !utf8.ValidString(s) {
+		return ErrInvalidUTF8
+	}
+
+	return WriteString(w, s)
+}
+
+// ReadAmount reads a fixed-point amount, expressed in atomic units, from r.
+func ReadAmount(r *bytes.Buffer, v *uint64) error {
+	var a uint64
+	if err := ReadUint64LE(r, &a); err != nil {
+		return err
+	}
+
+	if a > MaxAmount {
+		return ErrAmountOutOfRange
+	}
+
+	*v = a
+	return nil
+}
+
+// WriteAmount writes a fixed-point amount, expressed in atomic units, to w.
+func WriteAmount(w *bytes.Buffer, v uint64) error {
+	if v > MaxAmount {
+		return ErrAmountOutOfRange
+	}
+
+	return WriteUint64LE(w, v)
+}
+
+// ReadTimestamp reads a unix timestamp (in seconds) from r.
+func ReadTimestamp(r *bytes.Buffer, t *int64) error {
+	var v uint64
+	if err := ReadUint64LE(r, &v); err != nil {
+		return err
+	}
+
+	// Negative timestamps wrap around to values above MaxTimestamp, so a
+	// single check covers both ends of the range
+	if v > uint64(MaxTimestamp) {
+		return ErrTimestampOutOfRange
+	}
+
+	*t = int64(v)
+	return nil
+}
+
+// WriteTimestamp writes a unix timestamp (in seconds) to w.
+func WriteTimestamp(w *bytes.Buffer, t int64) error {
+	if t < 0 || t > MaxTimestamp {
+		return ErrTimestampOutOfRange
+	}
+
+	return WriteUint64LE(w, uint64(t))
+}
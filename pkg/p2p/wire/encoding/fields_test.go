@@ -0,0 +1,99 @@
+package encoding_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUTF8StringEncodeDecode(t *testing.T) {
+	s := "dusk – ドゥスク"
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteUTF8String(buf, s); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := encoding.ReadUTF8String(buf, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, s, res)
+}
+
+func TestUTF8StringInvalid(t *testing.T) {
+	// Overlong encoding of '/'
+	overlong := string([]byte{0xc0, 0xaf})
+	assert.Equal(t, encoding.ErrInvalidUTF8, encoding.WriteUTF8String(new(bytes.Buffer), overlong))
+
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteString(buf, overlong); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := encoding.ReadUTF8String(buf, 100)
+	assert.Equal(t, encoding.ErrInvalidUTF8, err)
+
+	// Strings exceeding the maximum length are refused
+	buf = new(bytes.Buffer)
+	if err := encoding.WriteUTF8String(buf, "pippo"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = encoding.ReadUTF8String(buf, 4)
+	assert.Error(t, err)
+
+	// A length prefix pointing past the end of the buffer is refused
+	buf = new(bytes.Buffer)
+	_ = encoding.WriteVarInt(buf, 50)
+	_, _ = buf.Write([]byte("pippo"))
+	_, err = encoding.ReadUTF8String(buf, 100)
+	assert.Error(t, err)
+}
+
+func TestAmountEncodeDecode(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteAmount(buf, encoding.MaxAmount); err != nil {
+		t.Fatal(err)
+	}
+
+	var a uint64
+	if err := encoding.ReadAmount(buf, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, encoding.MaxAmount, a)
+
+	// Out of range amounts
+	assert.Equal(t, encoding.ErrAmountOutOfRange, encoding.WriteAmount(buf, encoding.MaxAmount+1))
+	buf = new(bytes.Buffer)
+	_ = encoding.WriteUint64LE(buf, encoding.MaxAmount+1)
+	assert.Equal(t, encoding.ErrAmountOutOfRange, encoding.ReadAmount(buf, &a))
+}
+
+func TestTimestampEncodeDecode(t *testing.T) {
+	ts := int64(1576000000)
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteTimestamp(buf, ts); err != nil {
+		t.Fatal(err)
+	}
+
+	var res int64
+	if err := encoding.ReadTimestamp(buf, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ts, res)
+
+	// Negative timestamps
+	assert.Equal(t, encoding.ErrTimestampOutOfRange, encoding.WriteTimestamp(buf, -1))
+	buf = new(bytes.Buffer)
+	neg := int64(-1)
+	_ = encoding.WriteUint64LE(buf, uint64(neg))
+	assert.Equal(t, encoding.ErrTimestampOutOfRange, encoding.ReadTimestamp(buf, &res))
+
+	// Timestamps too far in the future
+	assert.Equal(t, encoding.ErrTimestampOutOfRange, encoding.WriteTimestamp(buf, encoding.MaxTimestamp+1))
+}
@@ -52,8 +52,7 @@ func ReadUint64LE(r *bytes.Buffer, v *uint64) error {
 
 // WriteUint8 will write a single byte.
 func WriteUint8(w *bytes.Buffer, v uint8) error {
-	_, err := w.Write([]byte{v})
-	return err
+	return w.WriteByte(v)
 }
 
 // WriteUint16LE will write two bytes in little-endian byte order.
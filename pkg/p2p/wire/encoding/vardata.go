@@ -5,23 +5,36 @@ package encoding
 import (
 	"bytes"
 	"errors"
+	"io"
 	"math"
 )
 
 // ReadVarBytes will read a CompactSize int denoting the length, then
 // proceeds to read that amount of bytes from r into b.
 func ReadVarBytes(r *bytes.Buffer, b *[]byte) error {
+	// We reject reading any data that has a length greater than
+	// math.MaxInt32, to avoid out of memory errors.
+	return ReadVarBytesMax(r, b, math.MaxInt32)
+}
+
+// ReadVarBytesMax is ReadVarBytes for data which can not be longer than max
+// bytes. The length is checked before allocating b, so that a malformed
+// length can not trigger a huge allocation.
+func ReadVarBytesMax(r *bytes.Buffer, b *[]byte, max uint64) error {
 	c, err := ReadVarInt(r)
 	if err != nil {
 		return err
 	}
 
-	// We reject reading any data that has a length greater than
-	// math.MaxInt32, to avoid out of memory errors.
-	if c > math.MaxInt32 {
+	if c > max {
 		return errors.New("attempting to decode data which is too large")
 	}
 
+	// The data can not be longer than what is left in the buffer
+	if c > uint64(r.Len()) {
+		return io.ErrUnexpectedEOF
+	}
+
 	*b = make([]byte, c)
 	if _, err := r.Read(*b); err != nil {
 		return err
@@ -2,6 +2,7 @@ package encoding
 
 import (
 	"bytes"
+	"io"
 	"math/rand"
 	"testing"
 	"time"
@@ -34,6 +35,21 @@ func TestVarBytesEncodeDecode(t *testing.T) {
 	assert.Equal(t, bs, rbs)
 }
 
+func TestReadVarBytesMax(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, WriteVarBytes(buf, randBytes(64)))
+
+	var rbs []byte
+	assert.Error(t, ReadVarBytesMax(bytes.NewBuffer(buf.Bytes()), &rbs, 63))
+	assert.NoError(t, ReadVarBytesMax(bytes.NewBuffer(buf.Bytes()), &rbs, 64))
+
+	// A length exceeding the data is refused before allocating
+	buf.Reset()
+	assert.NoError(t, WriteVarInt(buf, 1<<30))
+	buf.Write(randBytes(16))
+	assert.Equal(t, io.ErrUnexpectedEOF, ReadVarBytes(buf, &rbs))
+}
+
 // Simple test case for writing and reading strings.
 func TestVarStringEncodeDecode(t *testing.T) {
 	// Get a random string
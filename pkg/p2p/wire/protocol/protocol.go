@@ -21,14 +21,11 @@ const (
 
 	// LightNode indicates that a user is running a Dusk light node
 	// LightNode ServiceFlag = 2 // Not implemented
-)
 
-// NodeVer is the current node version.
-var NodeVer = &Version{
-	Major: 0,
-	Minor: 1,
-	Patch: 1,
-}
+	// CompactCandidates indicates that the node understands candidates gossiped
+	// in their compact form, with the transactions replaced by their TxID
+	CompactCandidates ServiceFlag = 4
+)
 
 // Magic is the network that Dusk is running on
 type Magic uint8
@@ -60,6 +57,15 @@ var magics = [...]magicObj{
 	{DevNet, asBuffer(0x74736e40), "devnet"},
 }
 
+// Networks returns the names of the networks the node can run on
+func Networks() []string {
+	names := make([]string, len(magics))
+	for i, m := range magics {
+		names[i] = m.str
+	}
+	return names
+}
+
 func (m Magic) Len() int {
 	return magics[m].buf.Len()
 }
@@ -96,6 +102,10 @@ func asBuffer(magic uint32) bytes.Buffer {
 func MagicFromConfig() Magic {
 
 	magic := cfg.Get().General.Network
+	if p, ok := cfg.LookupProfile(magic); ok {
+		magic = p.Magic
+	}
+
 	mstr := strings.ToLower(magic)
 	for _, m := range magics {
 		if mstr == m.str {
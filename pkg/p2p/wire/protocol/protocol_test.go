@@ -74,3 +74,40 @@ func delayedWrite(t *testing.T, c net.Conn) {
 		t.Fatal(err)
 	}
 }
+
+func TestParseVersion(t *testing.T) {
+	v, err := protocol.ParseVersion("v0.2.13")
+	assert.NoError(t, err)
+	assert.Equal(t, protocol.Version{Major: 0, Minor: 2, Patch: 13}, v)
+
+	for _, s := range []string{"", "0.1", "0.1.a", "256.0.0"} {
+		_, err := protocol.ParseVersion(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestVersionPolicy(t *testing.T) {
+	now := time.Now()
+	policy := protocol.VersionPolicy{
+		Min:        *protocol.NodeVer,
+		GraceUntil: now.Add(time.Hour),
+	}
+
+	// Current version is always accepted
+	grace, err := policy.Accept(*protocol.NodeVer, now)
+	assert.NoError(t, err)
+	assert.False(t, grace)
+
+	// The previous release is only accepted during the grace period
+	prev := protocol.Releases[len(protocol.Releases)-2]
+	grace, err = policy.Accept(prev, now)
+	assert.NoError(t, err)
+	assert.True(t, grace)
+
+	_, err = policy.Accept(prev, now.Add(2*time.Hour))
+	assert.Error(t, err)
+
+	// A different major version is never accepted
+	_, err = protocol.DefaultPolicy().Accept(protocol.Version{Major: protocol.NodeVer.Major + 1}, now)
+	assert.Error(t, err)
+}
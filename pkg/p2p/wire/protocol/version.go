@@ -2,11 +2,30 @@ package protocol
 
 import (
 	"bytes"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 )
 
+// Releases lists the wire protocol versions of all node releases, from the
+// oldest to the newest. Whenever the wire protocol changes, a new entry
+// should be appended.
+var Releases = []Version{
+	{Major: 0, Minor: 1, Patch: 0},
+	{Major: 0, Minor: 1, Patch: 1},
+}
+
+// NodeVer is the current node version.
+var NodeVer = &Releases[len(Releases)-1]
+
+// GitCommit is the commit the node was built from. It is set at build time
+// through -ldflags "-X github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol.GitCommit=<commit>"
+var GitCommit = "unknown"
+
 // Version is a struct that separates version fields.
 type Version struct {
 	Major uint8
@@ -18,6 +37,55 @@ func (v Version) String() string {
 	return strconv.Itoa(int(v.Major)) + "." + strconv.Itoa(int(v.Minor)) + "." + strconv.Itoa(int(v.Patch))
 }
 
+// Cmp compares two versions. It returns -1 if v is older than other, 1 if it
+// is newer and 0 if they are the same.
+func (v Version) Cmp(other Version) int {
+	switch {
+	case v.Major != other.Major:
+		return cmp(int(v.Major), int(other.Major))
+	case v.Minor != other.Minor:
+		return cmp(int(v.Minor), int(other.Minor))
+	default:
+		return cmp(int(v.Patch), int(other.Patch))
+	}
+}
+
+func cmp(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ParseVersion parses a version in the "major.minor.patch" format.
+func ParseVersion(s string) (Version, error) {
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	major, err := strconv.ParseUint(parts[0], 10, 8)
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid major version in %q", s)
+	}
+
+	minor, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid minor version in %q", s)
+	}
+
+	patch, err := strconv.ParseUint(parts[2], 10, 16)
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid patch version in %q", s)
+	}
+
+	return Version{uint8(major), uint8(minor), uint16(patch)}, nil
+}
+
 // Encode will encode a Version struct to w.
 func (v *Version) Encode(w *bytes.Buffer) error {
 	if err := encoding.WriteUint8(w, v.Major); err != nil {
@@ -51,3 +119,74 @@ func (v *Version) Decode(r *bytes.Buffer) error {
 
 	return nil
 }
+
+// VersionPolicy decides which peer versions we accept to connect to. Peers
+// need to run at least the Min version. Until GraceUntil, the release right
+// before Min is tolerated as well, so that a wire change can be deployed
+// progressively instead of splitting the network.
+type VersionPolicy struct {
+	Min        Version
+	GraceUntil time.Time
+}
+
+// DefaultPolicy accepts any peer running the same major version as we do.
+func DefaultPolicy() VersionPolicy {
+	return VersionPolicy{Min: Version{Major: NodeVer.Major}}
+}
+
+// PolicyFromConfig builds a VersionPolicy out of the `network.minPeerVersion`
+// and `network.versionGraceUntil` (RFC3339) settings. Unset settings fall
+// back to the DefaultPolicy.
+func PolicyFromConfig() (VersionPolicy, error) {
+	p := DefaultPolicy()
+	n := cfg.Get().Network
+
+	if n.MinPeerVersion != "" {
+		min, err := ParseVersion(n.MinPeerVersion)
+		if err != nil {
+			return p, err
+		}
+		p.Min = min
+	}
+
+	if n.VersionGraceUntil != "" {
+		until, err := time.Parse(time.RFC3339, n.VersionGraceUntil)
+		if err != nil {
+			return p, fmt.Errorf("invalid version grace deadline: %v", err)
+		}
+		p.GraceUntil = until
+	}
+
+	return p, nil
+}
+
+// Accept checks whether a peer advertising version v can connect at time now.
+// It returns a nil error and a flag telling if the peer is only accepted
+// thanks to the grace period.
+func (p VersionPolicy) Accept(v Version, now time.Time) (bool, error) {
+	// Different major versions are never compatible
+	if v.Major != NodeVer.Major {
+		return false, fmt.Errorf("version mismatch: peer runs %s, we run %s", v, NodeVer)
+	}
+
+	if v.Cmp(p.Min) >= 0 {
+		return false, nil
+	}
+
+	if prev, ok := previousRelease(p.Min); ok && v.Cmp(prev) >= 0 && now.Before(p.GraceUntil) {
+		return true, nil
+	}
+
+	return false, fmt.Errorf("peer version %s is below the minimum version %s", v, p.Min)
+}
+
+// previousRelease returns the latest release older than v.
+func previousRelease(v Version) (Version, bool) {
+	for i := len(Releases) - 1; i >= 0; i-- {
+		if Releases[i].Cmp(v) < 0 {
+			return Releases[i], true
+		}
+	}
+
+	return Version{}, false
+}
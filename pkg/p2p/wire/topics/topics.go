@@ -64,6 +64,12 @@ const (
 	IntermediateBlock
 	HighestSeen
 	ValidCandidateHash
+	GeneratedCandidate
+	CompactCandidate
+	RejectedMessage
+	StakeExpired
+	PauseConsensus
+	ResumeConsensus
 )
 
 type topicBuf struct {
@@ -116,6 +122,12 @@ var Topics = [...]topicBuf{
 	topicBuf{IntermediateBlock, *(bytes.NewBuffer([]byte{byte(IntermediateBlock)})), "intermediateblock"},
 	topicBuf{HighestSeen, *(bytes.NewBuffer([]byte{byte(HighestSeen)})), "highestseen"},
 	topicBuf{ValidCandidateHash, *(bytes.NewBuffer([]byte{byte(ValidCandidateHash)})), "validcandidatehash"},
+	topicBuf{GeneratedCandidate, *(bytes.NewBuffer([]byte{byte(GeneratedCandidate)})), "generatedcandidate"},
+	topicBuf{CompactCandidate, *(bytes.NewBuffer([]byte{byte(CompactCandidate)})), "compactcandidate"},
+	topicBuf{RejectedMessage, *(bytes.NewBuffer([]byte{byte(RejectedMessage)})), "rejectedmessage"},
+	topicBuf{StakeExpired, *(bytes.NewBuffer([]byte{byte(StakeExpired)})), "stakeexpired"},
+	topicBuf{PauseConsensus, *(bytes.NewBuffer([]byte{byte(PauseConsensus)})), "pauseconsensus"},
+	topicBuf{ResumeConsensus, *(bytes.NewBuffer([]byte{byte(ResumeConsensus)})), "resumeconsensus"},
 }
 
 func (t Topic) ToBuffer() bytes.Buffer {
@@ -162,6 +174,11 @@ func Prepend(b *bytes.Buffer, t Topic) error {
 	return nil
 }
 
+// IsConsensus returns true if the topic carries consensus messages
+func IsConsensus(t Topic) bool {
+	return t >= Candidate && t <= StartConsensus
+}
+
 // Extract the topic from an io.Reader
 func Extract(p io.Reader) (Topic, error) {
 	var cmdBuf [1]byte
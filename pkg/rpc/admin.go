@@ -0,0 +1,223 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/logging"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	logger "github.com/sirupsen/logrus"
+)
+
+// peerCommandTimeout bounds the time taken to ban or connect to a peer
+const peerCommandTimeout = 5 * time.Second
+
+// adminCmd maps the methods of the admin namespace, served on the /admin
+// path, to their functions. None of them can be called without credentials.
+var adminCmd = map[string]handler{
+	"stopnode":        stopNode,
+	"banpeer":         banPeer,
+	"addpeer":         addPeer,
+	"setloglevel":     setLogLevel,
+	"getlogs":         getLogs,
+	"pauseconsensus":  pauseConsensus,
+	"resumeconsensus": resumeConsensus,
+	"backupdb":        backupDB,
+	"getconfig":       getConfig,
+	"dumpstate":       dumpState,
+	"createsnapshot":  createSnapshot,
+}
+
+var stopNode = func(s *Server, params []string) (string, error) {
+	if s.shutdown == nil {
+		return "", errors.New("node can not be stopped through this server")
+	}
+
+	s.shutdownOnce.Do(func() {
+		close(s.shutdown)
+	})
+
+	return "node stopping", nil
+}
+
+var banPeer = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("banpeer expects a peer address")
+	}
+
+	req, err := addressRequest(params[0])
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.rpcBus.Call(rpcbus.BanPeer, req, peerCommandTimeout); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("peer %s banned", params[0]), nil
+}
+
+var addPeer = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("addpeer expects a peer address")
+	}
+
+	req, err := addressRequest(params[0])
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.rpcBus.Call(rpcbus.AddPeer, req, peerCommandTimeout); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("connected to %s", params[0]), nil
+}
+
+// addressRequest builds the RPCBus request of the peer management methods
+func addressRequest(address string) (rpcbus.Request, error) {
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteString(buf, address); err != nil {
+		return rpcbus.Request{}, err
+	}
+
+	return rpcbus.NewRequest(*buf), nil
+}
+
+var setLogLevel = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("setloglevel expects a log level")
+	}
+
+	level, err := logger.ParseLevel(params[0])
+	if err != nil {
+		return "", invalidParams("invalid log level %s", params[0])
+	}
+
+	if len(params) > 1 {
+		logging.SetSubsystemLevel(params[1], level)
+		return fmt.Sprintf("log level of %s set to %s", params[1], level), nil
+	}
+
+	logging.SetLevel(level)
+	return fmt.Sprintf("log level set to %s", level), nil
+}
+
+var getLogs = func(s *Server, params []string) (string, error) {
+	limit, cursor, err := pageParams(params)
+	if err != nil {
+		return "", err
+	}
+
+	var since uint64
+	if cursor != "" {
+		since, err = strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return "", invalidParams("invalid cursor %s", cursor)
+		}
+	}
+
+	lines, written := logging.Recent(since)
+	first := written - uint64(len(lines))
+	if cursor == "" {
+		// Without a cursor, the last lines are returned
+		if len(lines) > limit {
+			lines = lines[len(lines)-limit:]
+		}
+	} else if len(lines) > limit {
+		lines = lines[:limit]
+	}
+
+	// The cursor is returned even on the last page, so that the logs can be
+	// followed
+	next := written
+	if cursor != "" {
+		next = first + uint64(len(lines))
+	}
+
+	out, err := json.Marshal(page{Items: lines, Next: strconv.FormatUint(next, 10)})
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+var getConfig = func(s *Server, params []string) (string, error) {
+	b, err := json.Marshal(cfg.Get().Redacted())
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+var pauseConsensus = func(s *Server, params []string) (string, error) {
+	if err := s.eventBus.Publish(topics.PauseConsensus, new(bytes.Buffer)); err != nil {
+		return "", err
+	}
+
+	return "consensus paused", nil
+}
+
+var resumeConsensus = func(s *Server, params []string) (string, error) {
+	if err := s.eventBus.Publish(topics.ResumeConsensus, new(bytes.Buffer)); err != nil {
+		return "", err
+	}
+
+	// The chain answers with a round update, which restarts the consensus
+	if err := s.eventBus.Publish(topics.Initialization, new(bytes.Buffer)); err != nil {
+		return "", err
+	}
+
+	return "consensus resumed", nil
+}
+
+var backupDB = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("backupdb expects a destination directory")
+	}
+
+	_, db := heavy.CreateDBConnection()
+	h, ok := db.(heavy.DB)
+	if !ok {
+		return "", errors.New("database driver does not support backups")
+	}
+
+	if err := h.Backup(params[0]); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("database copied to %s", params[0]), nil
+}
+
+var createSnapshot = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("createsnapshot expects a destination file")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteString(buf, params[0]); err != nil {
+		return "", err
+	}
+
+	// Writing the snapshot takes as long as reading the whole chain
+	resp, err := s.rpcBus.Call(rpcbus.CreateSnapshot, rpcbus.NewRequest(*buf), 0)
+	if err != nil {
+		return "", err
+	}
+
+	var height uint64
+	if err := encoding.ReadUint64LE(&resp, &height); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("snapshot of height %d written to %s", height, params[0]), nil
+}
@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// auditLog records the calls to admin methods. Its entries are tagged, so
+// that they can be filtered out of the node logs.
+var auditLog = log.WithField("audit", true)
+
+// callerOf returns the identity claimed by the sender of r. It is only meant
+// for the audit log: whether the identity is genuine is up to checkAuth.
+func callerOf(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return "cert:" + r.TLS.VerifiedChains[0][0].Subject.CommonName
+	}
+
+	if user, _, ok := r.BasicAuth(); ok {
+		return "user:" + user
+	}
+
+	if strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		return "token"
+	}
+
+	return "anonymous"
+}
+
+// audit logs who called an admin method, with which outcome. Entries are
+// timestamped by the logger. The parameters are only logged if logParams is
+// set, as those of the wallet methods hold passwords and seeds.
+func audit(r *http.Request, req *JSONRequest, authorized bool, logParams bool, start time.Time, err *JSONError) {
+	fields := logger.Fields{
+		"caller":     callerOf(r),
+		"remote":     r.RemoteAddr,
+		"method":     req.Method,
+		"authorized": authorized,
+		"duration":   time.Since(start),
+	}
+
+	if logParams {
+		fields["params"] = req.Params
+	}
+
+	if err != nil {
+		auditLog.WithFields(fields).WithError(err).Warn("admin call failed")
+		return
+	}
+
+	auditLog.WithFields(fields).Info("admin call")
+}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries caps the amount of results held by the cache
+const maxCacheEntries = 4096
+
+// resultCache is a TTL cache for the results of read-only methods, so that
+// repeated queries do not hit the database every time. It is flushed on every
+// accepted block, as the results may change with the state of the chain.
+type resultCache struct {
+	lock    sync.Mutex
+	entries map[string]cacheEntry
+	ttl     time.Duration
+}
+
+type cacheEntry struct {
+	result  string
+	expires time.Time
+}
+
+func newResultCache(ttl time.Duration) *resultCache {
+	return &resultCache{
+		entries: make(map[string]cacheEntry),
+		ttl:     ttl,
+	}
+}
+
+// cacheKey identifies a method call by its name and parameters
+func cacheKey(method string, params []string) string {
+	return method + "\x00" + strings.Join(params, "\x00")
+}
+
+func (c *resultCache) get(key string) (string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	if time.Now().After(e.expires) {
+		delete(c.entries, key)
+		return "", false
+	}
+
+	return e.result, true
+}
+
+func (c *resultCache) put(key, result string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.entries) >= maxCacheEntries {
+		c.purge()
+		// Still full of fresh results, which will expire soon enough
+		if len(c.entries) >= maxCacheEntries {
+			return
+		}
+	}
+
+	c.entries[key] = cacheEntry{result, time.Now().Add(c.ttl)}
+}
+
+// purge removes the expired entries. The lock must be held.
+func (c *resultCache) purge() {
+	now := time.Now()
+	for key, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *resultCache) flush() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = make(map[string]cacheEntry)
+}
+
+// onAcceptedBlock is the callback for the AcceptedBlock topic
+func (c *resultCache) onAcceptedBlock(bytes.Buffer) error {
+	c.flush()
+	return nil
+}
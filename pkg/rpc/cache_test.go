@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultCache(t *testing.T) {
+	c := newResultCache(time.Minute)
+	c.put(cacheKey("getblockhash", []string{"1"}), "aa")
+
+	result, ok := c.get(cacheKey("getblockhash", []string{"1"}))
+	assert.True(t, ok)
+	assert.Equal(t, "aa", result)
+
+	_, ok = c.get(cacheKey("getblockhash", []string{"2"}))
+	assert.False(t, ok)
+
+	// Accepted blocks flush the cache
+	assert.NoError(t, c.onAcceptedBlock(bytes.Buffer{}))
+	_, ok = c.get(cacheKey("getblockhash", []string{"1"}))
+	assert.False(t, ok)
+
+	// Expired results are not served
+	c = newResultCache(time.Millisecond)
+	c.put("key", "aa")
+	time.Sleep(5 * time.Millisecond)
+	_, ok = c.get("key")
+	assert.False(t, ok)
+}
+
+func TestCachedMethod(t *testing.T) {
+	var calls int
+	rpcCmd["countcalls"] = func(*Server, []string) (string, error) {
+		calls++
+		return strconv.Itoa(calls), nil
+	}
+	rpcCachedCmd["countcalls"] = true
+	defer func() {
+		delete(rpcCmd, "countcalls")
+		delete(rpcCachedCmd, "countcalls")
+	}()
+
+	s := &Server{started: true, cache: newResultCache(time.Minute)}
+	for i := 0; i < 3; i++ {
+		resp := call(t, s, `{"jsonrpc":"2.0","method":"countcalls","id":1}`)
+		assert.Equal(t, "1", resp.Result)
+	}
+
+	s.cache.flush()
+	resp := call(t, s, `{"jsonrpc":"2.0","method":"countcalls","id":1}`)
+	assert.Equal(t, "2", resp.Result)
+}
@@ -0,0 +1,869 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/candidate"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/participation"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/utils"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/versionbits"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/peermsg"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/dusk-network/dusk-wallet/transactions"
+)
+
+// blockJSON is the representation of a block returned by getblock
+type blockJSON struct {
+	Version       uint8    `json:"version"`
+	Height        uint64   `json:"height"`
+	Timestamp     int64    `json:"timestamp"`
+	Hash          string   `json:"hash"`
+	PrevBlockHash string   `json:"prevblockhash"`
+	Seed          string   `json:"seed"`
+	TxRoot        string   `json:"txroot"`
+	Txs           []string `json:"txs"`
+}
+
+var getBlockHash = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("getblockhash expects a block height")
+	}
+
+	height, err := strconv.ParseUint(params[0], 10, 64)
+	if err != nil {
+		return "", invalidParams("invalid height %s", params[0])
+	}
+
+	blk, err := s.fetchBlockByHeight(height)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(blk.Header.Hash), nil
+}
+
+var getBlock = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("getblock expects a block hash")
+	}
+
+	hash, err := hex.DecodeString(params[0])
+	if err != nil || len(hash) != 32 {
+		return "", invalidParams("invalid block hash %s", params[0])
+	}
+
+	blk, err := s.fetchBlockByHash(hash)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := newBlockJSON(blk.Header, blk.Txs)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// fetchBlockByHash asks the chain for the accepted block with the given hash
+func (s *Server) fetchBlockByHash(hash []byte) (*block.Block, error) {
+	r, err := s.rpcBus.Call(rpcbus.GetBlockByHash, rpcbus.NewRequest(*bytes.NewBuffer(hash)), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&r, blk); err != nil {
+		return nil, err
+	}
+
+	return blk, nil
+}
+
+// fetchBlockByHeight asks the chain for the accepted block at the given height
+func (s *Server) fetchBlockByHeight(height uint64) (*block.Block, error) {
+	param := new(bytes.Buffer)
+	if err := encoding.WriteUint64LE(param, height); err != nil {
+		return nil, err
+	}
+
+	r, err := s.rpcBus.Call(rpcbus.GetBlockByHeight, rpcbus.NewRequest(*param), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&r, blk); err != nil {
+		return nil, err
+	}
+
+	return blk, nil
+}
+
+// fetchHeaders asks the chain for the headers of the accepted blocks from
+// height from to height to, both included. Fewer headers are returned past
+// the chain tip.
+func (s *Server) fetchHeaders(from, to uint64) ([]*block.Header, error) {
+	param := new(bytes.Buffer)
+	if err := rpcbus.MarshalHeadersRangeRequest(param, from, to); err != nil {
+		return nil, err
+	}
+
+	r, err := s.rpcBus.Call(rpcbus.GetHeadersRange, rpcbus.NewRequest(*param), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := encoding.ReadVarInt(&r)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]*block.Header, 0, count)
+	for i := uint64(0); i < count; i++ {
+		header := block.NewHeader()
+		if err := marshalling.UnmarshalHeader(&r, header); err != nil {
+			return nil, err
+		}
+
+		headers = append(headers, header)
+	}
+
+	return headers, nil
+}
+
+// newBlockJSON builds the representation of a block from its content
+func newBlockJSON(header *block.Header, txs []transactions.Transaction) (blockJSON, error) {
+	result := blockJSON{
+		Version:       header.Version,
+		Height:        header.Height,
+		Timestamp:     header.Timestamp,
+		Hash:          hex.EncodeToString(header.Hash),
+		PrevBlockHash: hex.EncodeToString(header.PrevBlockHash),
+		Seed:          hex.EncodeToString(header.Seed),
+		TxRoot:        hex.EncodeToString(header.TxRoot),
+		Txs:           make([]string, 0, len(txs)),
+	}
+
+	for _, tx := range txs {
+		txid, err := marshalling.TxID(tx)
+		if err != nil {
+			return blockJSON{}, err
+		}
+
+		result.Txs = append(result.Txs, hex.EncodeToString(txid))
+	}
+
+	return result, nil
+}
+
+// getBlocks lists the blocks in ascending height order. The cursor is the
+// height of the first block to return, and defaults to the genesis block.
+var getBlocks = func(s *Server, params []string) (string, error) {
+	limit, cursor, err := pageParams(params)
+	if err != nil {
+		return "", err
+	}
+
+	var from uint64
+	if cursor != "" {
+		from, err = strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return "", invalidParams("invalid cursor %s", cursor)
+		}
+	}
+
+	// One more header than needed tells whether there is a next page
+	headers, err := s.fetchHeaders(from, from+uint64(limit))
+	if err != nil {
+		return "", err
+	}
+
+	result := page{}
+	if len(headers) > limit {
+		headers = headers[:limit]
+		result.Next = strconv.FormatUint(from+uint64(limit), 10)
+	}
+
+	blocks := make([]blockJSON, 0, len(headers))
+	for _, header := range headers {
+		blk, err := s.fetchBlockByHash(header.Hash)
+		if err != nil {
+			return "", err
+		}
+
+		b, err := newBlockJSON(blk.Header, blk.Txs)
+		if err != nil {
+			return "", err
+		}
+
+		blocks = append(blocks, b)
+	}
+
+	result.Items = blocks
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// txJSON is the representation of a transaction returned by gettransaction.
+// Unlike the txid, the wtxid also commits to the signatures and range proof.
+type txJSON struct {
+	TxID      string `json:"txid"`
+	WTxID     string `json:"wtxid"`
+	BlockHash string `json:"blockhash"`
+	Height    uint64 `json:"height"`
+	Index     uint32 `json:"index"`
+	// Raw is the hex encoded transaction, as accepted by sendrawtransaction
+	Raw string `json:"raw"`
+}
+
+var getTransaction = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("gettransaction expects a transaction id")
+	}
+
+	txid, err := hex.DecodeString(params[0])
+	if err != nil || len(txid) != 32 {
+		return "", invalidParams("invalid transaction id %s", params[0])
+	}
+
+	var result txJSON
+	_, db := heavy.CreateDBConnection()
+	err = db.View(func(t database.Transaction) error {
+		tx, index, blockHash, err := t.FetchBlockTxByHash(txid)
+		if err != nil {
+			return err
+		}
+
+		header, err := t.FetchBlockHeader(blockHash)
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		if err := marshalling.MarshalTx(buf, tx); err != nil {
+			return err
+		}
+
+		wtxid, err := marshalling.WTxID(tx)
+		if err != nil {
+			return err
+		}
+
+		result = txJSON{
+			TxID:      hex.EncodeToString(txid),
+			WTxID:     hex.EncodeToString(wtxid),
+			BlockHash: hex.EncodeToString(blockHash),
+			Height:    header.Height,
+			Index:     index,
+			Raw:       hex.EncodeToString(buf.Bytes()),
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// txProofJSON is the proof returned by gettxproof, that a transaction is part
+// of the block with the given txroot. The path lists the sibling hashes from
+// the bottom of the merkle tree, see package crypto/merkle.
+type txProofJSON struct {
+	TxID      string   `json:"txid"`
+	WTxID     string   `json:"wtxid"`
+	BlockHash string   `json:"blockhash"`
+	Height    uint64   `json:"height"`
+	TxRoot    string   `json:"txroot"`
+	Index     uint32   `json:"index"`
+	Count     uint32   `json:"count"`
+	Path      []string `json:"path"`
+}
+
+var getTxProof = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("gettxproof expects a transaction id")
+	}
+
+	txid, err := hex.DecodeString(params[0])
+	if err != nil || len(txid) != 32 {
+		return "", invalidParams("invalid transaction id %s", params[0])
+	}
+
+	var result txProofJSON
+	_, db := heavy.CreateDBConnection()
+	err = db.View(func(t database.Transaction) error {
+		_, _, blockHash, err := t.FetchBlockTxByHash(txid)
+		if err != nil {
+			return err
+		}
+
+		header, err := t.FetchBlockHeader(blockHash)
+		if err != nil {
+			return err
+		}
+
+		txs, err := t.FetchBlockTxs(blockHash)
+		if err != nil {
+			return err
+		}
+
+		proof, wtxid, err := marshalling.MerkleProof(&block.Block{Header: header, Txs: txs}, txid)
+		if err != nil {
+			return err
+		}
+
+		result = txProofJSON{
+			TxID:      hex.EncodeToString(txid),
+			WTxID:     hex.EncodeToString(wtxid),
+			BlockHash: hex.EncodeToString(blockHash),
+			Height:    header.Height,
+			TxRoot:    hex.EncodeToString(header.TxRoot),
+			Index:     proof.Index,
+			Count:     proof.Count,
+			Path:      make([]string, len(proof.Path)),
+		}
+
+		for i, h := range proof.Path {
+			result.Path[i] = hex.EncodeToString(h)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// provisionerJSON is the representation of a provisioner returned by
+// getprovisioners
+type provisionerJSON struct {
+	PublicKeyBLS string      `json:"publickeybls"`
+	PublicKeyEd  string      `json:"publickeyed"`
+	Stakes       []stakeJSON `json:"stakes"`
+}
+
+type stakeJSON struct {
+	Amount      uint64 `json:"amount"`
+	StartHeight uint64 `json:"startheight"`
+	EndHeight   uint64 `json:"endheight"`
+}
+
+var getProvisioners = func(s *Server, params []string) (string, error) {
+	r, err := s.rpcBus.Call(rpcbus.GetProvisioners, rpcbus.NewRequest(bytes.Buffer{}), 5*time.Second)
+	if err != nil {
+		return "", err
+	}
+
+	set, err := user.UnmarshalProvisioners(&r)
+	if err != nil {
+		return "", err
+	}
+
+	// Provisioners are listed in the order of their BLS public keys
+	result := make([]provisionerJSON, 0, len(set.Set))
+	for i := range set.Set {
+		m := set.MemberAt(i)
+		if m == nil {
+			continue
+		}
+
+		p := provisionerJSON{
+			PublicKeyBLS: hex.EncodeToString(m.PublicKeyBLS),
+			PublicKeyEd:  hex.EncodeToString(m.PublicKeyEd),
+			Stakes:       make([]stakeJSON, 0, len(m.Stakes)),
+		}
+
+		for _, stake := range m.Stakes {
+			p.Stakes = append(p.Stakes, stakeJSON{stake.Amount, stake.StartHeight, stake.EndHeight})
+		}
+
+		result = append(result, p)
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// stakeRegistrationJSON is the representation of a stake returned by
+// getstakes
+type stakeRegistrationJSON struct {
+	TxID         string `json:"txid"`
+	Height       uint64 `json:"height"`
+	PublicKeyBLS string `json:"publickeybls"`
+	PublicKeyEd  string `json:"publickeyed"`
+	Amount       uint64 `json:"amount"`
+	Lock         uint64 `json:"lock"`
+	StartHeight  uint64 `json:"startheight"`
+	EndHeight    uint64 `json:"endheight"`
+	Expired      bool   `json:"expired"`
+}
+
+var getStakes = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("getstakes expects a BLS or Ed25519 public key")
+	}
+
+	key, err := hex.DecodeString(params[0])
+	if err != nil || len(key) == 0 {
+		return "", invalidParams("invalid public key %s", params[0])
+	}
+
+	var result []stakeRegistrationJSON
+	_, db := heavy.CreateDBConnection()
+	err = db.View(func(t database.Transaction) error {
+		currentHeight, err := t.FetchCurrentHeight()
+		if err != nil {
+			return err
+		}
+
+		stakes, err := utils.FetchStakes(t, key)
+		if err != nil {
+			return err
+		}
+
+		result = make([]stakeRegistrationJSON, 0, len(stakes))
+		for _, stake := range stakes {
+			result = append(result, stakeRegistrationJSON{
+				TxID:         hex.EncodeToString(stake.TxID),
+				Height:       stake.Height,
+				PublicKeyBLS: hex.EncodeToString(stake.PubKeyBLS),
+				PublicKeyEd:  hex.EncodeToString(stake.PubKeyEd),
+				Amount:       stake.Amount,
+				Lock:         stake.Lock,
+				StartHeight:  stake.StartHeight,
+				EndHeight:    stake.EndHeight,
+				Expired:      stake.EndHeight <= currentHeight,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// bidRegistrationJSON is the representation of a bid returned by getbids
+type bidRegistrationJSON struct {
+	TxID       string `json:"txid"`
+	Height     uint64 `json:"height"`
+	M          string `json:"m"`
+	Commitment string `json:"commitment"`
+	Lock       uint64 `json:"lock"`
+	EndHeight  uint64 `json:"endheight"`
+	Expired    bool   `json:"expired"`
+}
+
+var getBids = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("getbids expects a bidding key")
+	}
+
+	m, err := hex.DecodeString(params[0])
+	if err != nil || len(m) != 32 {
+		return "", invalidParams("invalid bidding key %s", params[0])
+	}
+
+	var result []bidRegistrationJSON
+	_, db := heavy.CreateDBConnection()
+	err = db.View(func(t database.Transaction) error {
+		currentHeight, err := t.FetchCurrentHeight()
+		if err != nil {
+			return err
+		}
+
+		bids, err := utils.FetchBids(t, m)
+		if err != nil {
+			return err
+		}
+
+		result = make([]bidRegistrationJSON, 0, len(bids))
+		for _, bid := range bids {
+			result = append(result, bidRegistrationJSON{
+				TxID:       hex.EncodeToString(bid.TxID),
+				Height:     bid.Height,
+				M:          hex.EncodeToString(bid.M),
+				Commitment: hex.EncodeToString(bid.Commitment),
+				Lock:       bid.Lock,
+				EndHeight:  bid.EndHeight,
+				Expired:    bid.EndHeight <= currentHeight,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// deploymentJSON is the representation of a deployment returned by
+// getdeployments
+type deploymentJSON struct {
+	Name        string `json:"name"`
+	Bit         uint8  `json:"bit"`
+	StartHeight uint64 `json:"startheight"`
+	Threshold   uint64 `json:"threshold"`
+	State       string `json:"state"`
+	Signals     uint64 `json:"signals"`
+}
+
+var getDeployments = func(s *Server, params []string) (string, error) {
+	r, err := s.rpcBus.Call(rpcbus.GetDeployments, rpcbus.NewRequest(bytes.Buffer{}), 5*time.Second)
+	if err != nil {
+		return "", err
+	}
+
+	status, err := versionbits.UnmarshalStatus(&r)
+	if err != nil {
+		return "", err
+	}
+
+	result := make([]deploymentJSON, 0, len(status))
+	for _, d := range status {
+		result = append(result, deploymentJSON{d.Name, d.Bit, d.StartHeight, d.Threshold, d.State.String(), d.Signals})
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// candidateStatsJSON is the representation of the counters returned by
+// getcandidatestats
+type candidateStatsJSON struct {
+	Stale           uint64 `json:"stale"`
+	ExcessRound     uint64 `json:"excessround"`
+	ExcessGenerator uint64 `json:"excessgenerator"`
+}
+
+var getCandidateStats = func(s *Server, params []string) (string, error) {
+	r, err := s.rpcBus.Call(rpcbus.GetCandidateStats, rpcbus.NewRequest(bytes.Buffer{}), 5*time.Second)
+	if err != nil {
+		return "", err
+	}
+
+	stats, err := candidate.UnmarshalStats(&r)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(candidateStatsJSON{stats.Stale, stats.ExcessRound, stats.ExcessGenerator})
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// participationJSON is the representation of the participation returned by
+// getparticipation
+type participationJSON struct {
+	Selected    uint64  `json:"selected"`
+	Voted       uint64  `json:"voted"`
+	Recent      uint64  `json:"recent"`
+	RecentVoted uint64  `json:"recentvoted"`
+	Ratio       float64 `json:"ratio"`
+	LastAbsent  uint64  `json:"lastabsent"`
+}
+
+var getParticipation = func(s *Server, params []string) (string, error) {
+	r, err := s.rpcBus.Call(rpcbus.GetParticipation, rpcbus.NewRequest(bytes.Buffer{}), 5*time.Second)
+	if err != nil {
+		return "", err
+	}
+
+	stats, err := participation.UnmarshalStats(&r)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(participationJSON{stats.Selected, stats.Voted, stats.Recent, stats.RecentVoted, stats.Ratio(), stats.LastAbsent})
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// sentTxJSON is the result of sendrawtransaction
+type sentTxJSON struct {
+	TxID string `json:"txid"`
+	// Status is "accepted" for the transactions verified and gossiped, or
+	// "held" for the ones spending outputs which are still locked
+	Status string `json:"status"`
+}
+
+// rejectionJSON details why sendrawtransaction refused a transaction
+type rejectionJSON struct {
+	Code   uint8  `json:"code"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+var sendRawTransaction = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("sendrawtransaction expects an encoded transaction")
+	}
+
+	var tx []byte
+	var err error
+	format := "hex"
+	if len(params) > 1 {
+		format = params[1]
+	}
+
+	switch format {
+	case "hex":
+		tx, err = hex.DecodeString(params[0])
+	case "base64":
+		tx, err = base64.StdEncoding.DecodeString(params[0])
+	default:
+		return "", invalidParams("unsupported encoding %s", format)
+	}
+	if err != nil {
+		return "", invalidParams("transaction is not %s encoded", format)
+	}
+
+	// The mempool verifies the transaction before answering, and gossips it
+	// once accepted
+	resp, err := s.rpcBus.Call(rpcbus.SendMempoolTx, rpcbus.NewRequest(*bytes.NewBuffer(tx)), 5*time.Second)
+	if reject, ok := err.(*peermsg.Reject); ok {
+		return "", &JSONError{
+			Code:    ErrCodeTxRejected,
+			Message: "transaction rejected: " + reject.Reason,
+			Data: rejectionJSON{
+				Code:   uint8(reject.Code),
+				Name:   reject.Code.String(),
+				Reason: reject.Reason,
+			},
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	sent := sentTxJSON{TxID: hex.EncodeToString(resp.Next(32)), Status: "accepted"}
+	if status, _ := resp.ReadByte(); status == rpcbus.TxHeld {
+		sent.Status = "held"
+	}
+
+	out, err := json.Marshal(sent)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+var getMempoolInfo = func(s *Server, params []string) (string, error) {
+	count, size, err := s.fetchMempoolSize()
+	if err != nil {
+		return "", err
+	}
+
+	result := struct {
+		Size  uint64 `json:"size"`
+		Bytes int    `json:"bytes"`
+	}{count, size}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// feeEstimateJSON is the fee rate returned by estimatefee, and the amount of
+// recent blocks it is based on
+type feeEstimateJSON struct {
+	FeePerByte uint64 `json:"feeperbyte"`
+	Blocks     uint32 `json:"blocks"`
+}
+
+var estimateFee = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("estimatefee expects an amount of blocks")
+	}
+
+	targetBlocks, err := strconv.ParseUint(params[0], 10, 64)
+	if err != nil || targetBlocks == 0 {
+		return "", invalidParams("invalid amount of blocks %s", params[0])
+	}
+
+	param := new(bytes.Buffer)
+	if err := encoding.WriteUint64LE(param, targetBlocks); err != nil {
+		return "", err
+	}
+
+	r, err := s.rpcBus.Call(rpcbus.GetFeeEstimate, rpcbus.NewRequest(*param), 5*time.Second)
+	if err != nil {
+		return "", err
+	}
+
+	var result feeEstimateJSON
+	if err := encoding.ReadUint64LE(&r, &result.FeePerByte); err != nil {
+		return "", err
+	}
+
+	if err := encoding.ReadUint32LE(&r, &result.Blocks); err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// mempoolTxJSON is the representation of a mempool tx returned by
+// getmempooltxs
+type mempoolTxJSON struct {
+	TxID string `json:"txid"`
+	Size int    `json:"size"`
+}
+
+// getMempoolTxs lists the verified txs of the mempool, sorted by id. The
+// cursor is the id of the last tx of the previous page. Txs entering the
+// mempool meanwhile are only returned if they sort after the cursor.
+var getMempoolTxs = func(s *Server, params []string) (string, error) {
+	limit, cursor, err := pageParams(params)
+	if err != nil {
+		return "", err
+	}
+
+	// An empty tx id returns the whole content of the mempool
+	txsBuf, err := s.rpcBus.Call(rpcbus.GetMempoolTxs, rpcbus.NewRequest(bytes.Buffer{}), 5*time.Second)
+	if err != nil {
+		return "", err
+	}
+
+	count, err := encoding.ReadVarInt(&txsBuf)
+	if err != nil {
+		return "", err
+	}
+
+	txs := make([]mempoolTxJSON, 0, count)
+	for i := uint64(0); i < count; i++ {
+		size := txsBuf.Len()
+		tx, err := marshalling.UnmarshalTx(&txsBuf)
+		if err != nil {
+			return "", err
+		}
+
+		txid, err := marshalling.TxID(tx)
+		if err != nil {
+			return "", err
+		}
+
+		id := hex.EncodeToString(txid)
+		if id > cursor {
+			txs = append(txs, mempoolTxJSON{id, size - txsBuf.Len()})
+		}
+	}
+
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].TxID < txs[j].TxID
+	})
+
+	result := page{}
+	if len(txs) > limit {
+		txs = txs[:limit]
+		result.Next = txs[limit-1].TxID
+	}
+
+	result.Items = txs
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+var getPeerInfo = func(s *Server, params []string) (string, error) {
+	peersBuf, err := s.rpcBus.Call(rpcbus.GetPeerInfo, rpcbus.NewRequest(bytes.Buffer{}), 2*time.Second)
+	if err != nil {
+		return "", err
+	}
+
+	peers, err := peer.UnmarshalInfo(&peersBuf)
+	if err != nil {
+		return "", err
+	}
+
+	type peerJSON struct {
+		Address string    `json:"address"`
+		Inbound bool      `json:"inbound"`
+		Since   time.Time `json:"since"`
+	}
+
+	result := make([]peerJSON, 0, len(peers))
+	for _, p := range peers {
+		result = append(result, peerJSON{p.Address, p.Inbound, time.Unix(p.Since, 0)})
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,105 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/block"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that the blocks are listed through the rpcbus methods of the chain
+func TestGetBlocks(t *testing.T) {
+	blocks := make([]*block.Block, 3)
+	for i := range blocks {
+		blocks[i] = helper.RandomBlock(t, uint64(i), 1)
+	}
+
+	s := &Server{rpcBus: mockChain(t, blocks)}
+
+	out, err := getBlocks(s, []string{"2"})
+	assert.NoError(t, err)
+
+	var result struct {
+		Items []blockJSON `json:"items"`
+		Next  string      `json:"next"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Len(t, result.Items, 2)
+	assert.Equal(t, uint64(1), result.Items[1].Height)
+	assert.Equal(t, "2", result.Next)
+
+	out, err = getBlocks(s, []string{"2", result.Next})
+	assert.NoError(t, err)
+	result.Next = ""
+	assert.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Len(t, result.Items, 1)
+	assert.Empty(t, result.Next)
+
+	_, err = getBlockHash(s, []string{"3"})
+	assert.Equal(t, database.ErrBlockNotFound, err)
+}
+
+// mockChain answers the block queries of the RPC server with blocks
+func mockChain(t *testing.T, blocks []*block.Block) *rpcbus.RPCBus {
+	rb := rpcbus.New()
+	send := func(blk *block.Block) (bytes.Buffer, error) {
+		buf := new(bytes.Buffer)
+		err := marshalling.MarshalBlock(buf, blk)
+		return *buf, err
+	}
+
+	assert.NoError(t, rb.Register(rpcbus.GetBlockByHash, func(r rpcbus.Request) (bytes.Buffer, error) {
+		for _, blk := range blocks {
+			if bytes.Equal(blk.Header.Hash, r.Params.Bytes()) {
+				return send(blk)
+			}
+		}
+		return bytes.Buffer{}, database.ErrBlockNotFound
+	}))
+
+	assert.NoError(t, rb.Register(rpcbus.GetBlockByHeight, func(r rpcbus.Request) (bytes.Buffer, error) {
+		var height uint64
+		if err := encoding.ReadUint64LE(&r.Params, &height); err != nil {
+			return bytes.Buffer{}, err
+		}
+
+		if height >= uint64(len(blocks)) {
+			return bytes.Buffer{}, database.ErrBlockNotFound
+		}
+		return send(blocks[height])
+	}))
+
+	assert.NoError(t, rb.Register(rpcbus.GetHeadersRange, func(r rpcbus.Request) (bytes.Buffer, error) {
+		var from, to uint64
+		_ = encoding.ReadUint64LE(&r.Params, &from)
+		_ = encoding.ReadUint64LE(&r.Params, &to)
+		if to >= uint64(len(blocks)) {
+			to = uint64(len(blocks)) - 1
+		}
+
+		buf := new(bytes.Buffer)
+		var count uint64
+		if from <= to {
+			count = to - from + 1
+		}
+		if err := encoding.WriteVarInt(buf, count); err != nil {
+			return bytes.Buffer{}, err
+		}
+
+		for height := from; height <= to; height++ {
+			if err := marshalling.MarshalHeader(buf, blocks[height].Header); err != nil {
+				return bytes.Buffer{}, err
+			}
+		}
+		return *buf, nil
+	}))
+
+	return rb
+}
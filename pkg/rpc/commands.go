@@ -2,14 +2,18 @@ package rpc
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/republisher"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/dusk-network/dusk-wallet/wallet"
 )
@@ -22,6 +26,7 @@ var (
 	// rpcCmd maps method names to their actual functions.
 	rpcCmd = map[string]handler{
 		"transfer":             transfer,
+		"createtransaction":    createTransaction,
 		"bid":                  sendBidTx,
 		"stake":                sendStakeTx,
 		"createwallet":         createWallet,
@@ -34,6 +39,28 @@ var (
 		"syncprogress":         syncProgress,
 		"automateconsensustxs": automateConsensusTxs,
 		"walletstatus":         walletStatus,
+		"deadletters":          deadLetters,
+		"busmetrics":           busMetrics,
+		"rpcbusmetrics":        rpcBusMetrics,
+		"republishermetrics":   republisherMetrics,
+		"rpcmetrics":           rpcMetrics,
+		"getblock":             getBlock,
+		"getblockhash":         getBlockHash,
+		"getblocks":            getBlocks,
+		"gettransaction":       getTransaction,
+		"gettxproof":           getTxProof,
+		"getprovisioners":      getProvisioners,
+		"getstakes":            getStakes,
+		"getbids":              getBids,
+		"getdeployments":       getDeployments,
+		"getcandidatestats":    getCandidateStats,
+		"getparticipation":     getParticipation,
+		"sendrawtransaction":   sendRawTransaction,
+		"getmempoolinfo":       getMempoolInfo,
+		"getmempooltxs":        getMempoolTxs,
+		"estimatefee":          estimateFee,
+		"getpeerinfo":          getPeerInfo,
+		"getnodestatus":        getNodeStatus,
 
 		// Publish Topic (experimental). Injects an event directly into EventBus system.
 		// Would be useful on E2E testing. Mind the supportedTopics list when sends it
@@ -42,13 +69,28 @@ var (
 
 	// rpcAdminCmd holds all admin methods.
 	rpcAdminCmd = map[string]bool{
-		"transfer":       true,
-		"bid":            true,
-		"stake":          true,
-		"createwallet":   true,
-		"loadwallet":     true,
-		"createFromSeed": true,
-		"publishTopic":   true,
+		"transfer":          true,
+		"createtransaction": true,
+		"bid":               true,
+		"stake":             true,
+		"createwallet":      true,
+		"loadwallet":        true,
+		"createFromSeed":    true,
+		"publishTopic":      true,
+		"deadletters":       true,
+	}
+
+	// rpcCachedCmd holds the methods whose results are cached, when
+	// rpc.cacheTTL is set.
+	rpcCachedCmd = map[string]bool{
+		"getblock":        true,
+		"getblockhash":    true,
+		"getblocks":       true,
+		"gettransaction":  true,
+		"gettxproof":      true,
+		"getprovisioners": true,
+		"getstakes":       true,
+		"getbids":         true,
 	}
 
 	// supported topics for injection into EventBus
@@ -155,6 +197,72 @@ var transfer = func(s *Server, params []string) (string, error) {
 	return result, err
 }
 
+// createdTx is a tx signed by createtransaction, along with its id
+type createdTx struct {
+	TxID string `json:"txid"`
+	Tx   string `json:"tx"`
+}
+
+// createTransaction signs a standard tx of <amount> DUSK to <address> with
+// the loaded wallet, without broadcasting it. The tx is encoded as hex
+// (default) or base64, as sendrawtransaction expects it.
+var createTransaction = func(s *Server, params []string) (string, error) {
+	if len(params) < 2 {
+		return "", errors.New("not enough parameters")
+	}
+
+	amount, err := strconv.ParseUint(params[0], 10, 64)
+	if err != nil {
+		return "", invalidParams("invalid amount %s", params[0])
+	}
+
+	format := "hex"
+	if len(params) > 2 {
+		format = params[2]
+	}
+
+	encode := hex.EncodeToString
+	switch format {
+	case "hex":
+	case "base64":
+		encode = base64.StdEncoding.EncodeToString
+	default:
+		return "", invalidParams("unsupported encoding %s", format)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteUint64LE(buf, amount); err != nil {
+		return "", fmt.Errorf("error writing amount to buffer: %v", err)
+	}
+
+	if err := encoding.WriteString(buf, params[1]); err != nil {
+		return "", fmt.Errorf("error writing address to buffer: %v", err)
+	}
+
+	txBuf, err := s.rpcBus.Call(rpcbus.CreateStandardTx, rpcbus.NewRequest(*buf), 0)
+	if err != nil {
+		return "", err
+	}
+
+	raw := txBuf.Bytes()
+	tx, err := marshalling.UnmarshalTx(bytes.NewBuffer(raw))
+	if err != nil {
+		return "", err
+	}
+
+	txid, err := marshalling.TxID(tx)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(createdTx{TxID: hex.EncodeToString(txid), Tx: encode(raw)})
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
 var sendStakeTx = func(s *Server, params []string) (string, error) {
 	if len(params) < 2 {
 		return "", errors.New("not enough parameters")
@@ -315,7 +423,7 @@ var unconfirmedBalance = func(s *Server, params []string) (string, error) {
 }
 
 var txHistory = func(s *Server, params []string) (string, error) {
-	txRecordsBuf, err := s.rpcBus.Call(rpcbus.GetTxHistory, rpcbus.Request{bytes.Buffer{}, make(chan rpcbus.Response, 1)}, 5*time.Second)
+	txRecordsBuf, err := s.rpcBus.Call(rpcbus.GetTxHistory, rpcbus.NewRequest(bytes.Buffer{}), 5*time.Second)
 	if err != nil {
 		return "", err
 	}
@@ -324,7 +432,7 @@ var txHistory = func(s *Server, params []string) (string, error) {
 }
 
 var automateConsensusTxs = func(s *Server, params []string) (string, error) {
-	if _, err := s.rpcBus.Call(rpcbus.AutomateConsensusTxs, rpcbus.Request{bytes.Buffer{}, make(chan rpcbus.Response, 1)}, 5*time.Second); err != nil {
+	if _, err := s.rpcBus.Call(rpcbus.AutomateConsensusTxs, rpcbus.NewRequest(bytes.Buffer{}), 5*time.Second); err != nil {
 		return "", err
 	}
 
@@ -332,7 +440,7 @@ var automateConsensusTxs = func(s *Server, params []string) (string, error) {
 }
 
 var syncProgress = func(s *Server, params []string) (string, error) {
-	percentageBuf, err := s.rpcBus.Call(rpcbus.GetSyncProgress, rpcbus.Request{bytes.Buffer{}, make(chan rpcbus.Response, 1)}, 2*time.Second)
+	percentageBuf, err := s.rpcBus.Call(rpcbus.GetSyncProgress, rpcbus.NewRequest(bytes.Buffer{}), 2*time.Second)
 	if err != nil {
 		return "", err
 	}
@@ -341,7 +449,7 @@ var syncProgress = func(s *Server, params []string) (string, error) {
 }
 
 var walletStatus = func(s *Server, params []string) (string, error) {
-	walletStatusBuf, err := s.rpcBus.Call(rpcbus.IsWalletLoaded, rpcbus.Request{bytes.Buffer{}, make(chan rpcbus.Response, 1)}, 2*time.Second)
+	walletStatusBuf, err := s.rpcBus.Call(rpcbus.IsWalletLoaded, rpcbus.NewRequest(bytes.Buffer{}), 2*time.Second)
 	if err != nil {
 		return "", err
 	}
@@ -353,3 +461,109 @@ var walletStatus = func(s *Server, params []string) (string, error) {
 
 	return fmt.Sprintf("%v", status), nil
 }
+
+// deadLetters dumps the most recent messages published on topics without
+// any listener. Recording needs to be enabled through the
+// logger.deadLetterSampling setting.
+var deadLetters = func(s *Server, params []string) (string, error) {
+	type deadLetter struct {
+		Topic string    `json:"topic"`
+		Size  int       `json:"size"`
+		Time  time.Time `json:"time"`
+		Stack string    `json:"stack"`
+	}
+
+	letters := s.eventBus.DeadLetters()
+	result := make([]deadLetter, len(letters))
+	for i, l := range letters {
+		result[i] = deadLetter{l.Topic.String(), l.Size, l.Time, l.Stack}
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// busMetrics returns the delivery statistics of the EventBus, per topic
+var busMetrics = func(s *Server, params []string) (string, error) {
+	type topicMetrics struct {
+		Published    uint64 `json:"published"`
+		Delivered    uint64 `json:"delivered"`
+		LatencyUs    int64  `json:"latencyUs"`
+		AvgLatencyUs int64  `json:"avgLatencyUs"`
+	}
+
+	result := make(map[string]topicMetrics)
+	for topic, m := range s.eventBus.Metrics() {
+		result[topic.String()] = topicMetrics{m.Published, m.Delivered, m.Latency.Microseconds(), m.AverageLatency().Microseconds()}
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+var republisherMetrics = func(s *Server, params []string) (string, error) {
+	type topicMetrics struct {
+		Republished  uint64 `json:"republished"`
+		Duplicates   uint64 `json:"duplicates"`
+		DroppedTopic uint64 `json:"droppedTopic"`
+		DroppedPeer  uint64 `json:"droppedPeer"`
+		Skipped      uint64 `json:"skipped"`
+	}
+
+	result := make(map[string]topicMetrics)
+	for topic, m := range republisher.Metrics() {
+		result[topic.String()] = topicMetrics{m.Republished, m.Duplicates, m.DroppedTopic, m.DroppedPeer, m.Skipped}
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+var rpcBusMetrics = func(s *Server, params []string) (string, error) {
+	type methodMetrics struct {
+		Calls        uint64 `json:"calls"`
+		Failures     uint64 `json:"failures"`
+		Timeouts     uint64 `json:"timeouts"`
+		LatencyUs    int64  `json:"latencyUs"`
+		AvgLatencyUs int64  `json:"avgLatencyUs"`
+	}
+
+	result := make(map[string]methodMetrics)
+	for method, m := range s.rpcBus.Metrics() {
+		result[method.String()] = methodMetrics{m.Calls, m.Failures, m.Timeouts, m.Latency.Microseconds(), m.AverageLatency().Microseconds()}
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// rpcMetrics returns the latency histograms of the methods of this server
+var rpcMetrics = func(s *Server, params []string) (string, error) {
+	result := make(map[string]methodMetricsJSON)
+	if s.metrics != nil {
+		result = s.metrics.snapshot()
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+// stateSections are the snapshots of the subsystems gathered by dumpstate,
+// by the name of their section in the dump
+var stateSections = map[string]handler{
+	"status":      getNodeStatus,
+	"peers":       getPeerInfo,
+	"eventbus":    busMetrics,
+	"rpcbus":      rpcBusMetrics,
+	"republisher": republisherMetrics,
+}
+
+// stateDumpJSON is the content of the file written by dumpstate
+type stateDumpJSON struct {
+	Time       time.Time                  `json:"time"`
+	Sections   map[string]json.RawMessage `json:"sections"`
+	Goroutines int                        `json:"goroutines"`
+	// Stacks of all the goroutines, as in a panic
+	Stacks string `json:"stacks"`
+}
+
+// dumpState snapshots the state of the subsystems (rounds, queues, peer
+// table) along with the stacks of all the goroutines to a file, to diagnose
+// a node which stopped making progress. A subsystem which does not answer,
+// as it would when deadlocked, gets its error recorded in place of its state.
+var dumpState = func(s *Server, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("dumpstate expects a destination file")
+	}
+
+	dump := stateDumpJSON{
+		Time:       time.Now(),
+		Sections:   make(map[string]json.RawMessage, len(stateSections)),
+		Goroutines: runtime.NumGoroutine(),
+	}
+
+	for name, fn := range stateSections {
+		out, err := fn(s, nil)
+		if err != nil {
+			out, _ := json.Marshal(map[string]string{"error": err.Error()})
+			dump.Sections[name] = out
+			continue
+		}
+		dump.Sections[name] = json.RawMessage(out)
+	}
+
+	stacks := new(bytes.Buffer)
+	if err := pprof.Lookup("goroutine").WriteTo(stacks, 2); err != nil {
+		return "", err
+	}
+	dump.Stacks = stacks.String()
+
+	out, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := ioutil.WriteFile(params[0], out, 0600); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("state dumped to %s", params[0]), nil
+}
@@ -0,0 +1,76 @@
+package rpc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/logging"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	logger "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpStateStalled(t *testing.T) {
+	// Nothing answers on the bus, as with a deadlocked node
+	s := &Server{rpcBus: rpcbus.New(), eventBus: eventbus.New()}
+
+	dir, err := ioutil.TempDir("", "dusk-dumpstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "state.json")
+	_, err = dumpState(s, []string{path})
+	assert.NoError(t, err)
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dump struct {
+		Sections map[string]map[string]interface{} `json:"sections"`
+		Stacks   string                            `json:"stacks"`
+	}
+	assert.NoError(t, json.Unmarshal(content, &dump))
+
+	// The subsystems which did not answer have their error recorded
+	assert.Contains(t, dump.Sections["status"], "error")
+	assert.Contains(t, dump.Sections["peers"], "error")
+	assert.NotContains(t, dump.Sections["eventbus"], "error")
+	assert.Contains(t, dump.Stacks, "goroutine")
+}
+
+func TestGetLogs(t *testing.T) {
+	logging.InitLog(ioutil.Discard)
+	defer logger.SetOutput(os.Stderr)
+	log.Info("first line")
+	log.Info("second line")
+
+	var result struct {
+		Items []string `json:"items"`
+		Next  string   `json:"next"`
+	}
+
+	// The last lines are returned, along with the cursor to follow them
+	out, err := getLogs(nil, []string{"1"})
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Equal(t, 1, len(result.Items))
+	assert.Contains(t, result.Items[0], "second line")
+
+	log.Info("third line")
+	out, err = getLogs(nil, []string{"", result.Next})
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Equal(t, 1, len(result.Items))
+	assert.Contains(t, result.Items[0], "third line")
+
+	_, err = getLogs(nil, []string{"", "latest"})
+	assert.Error(t, err)
+}
@@ -1,27 +1,115 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+)
+
+// jsonRPCVersion is the version of the JSON-RPC protocol spoken by the server
+const jsonRPCVersion = "2.0"
+
+// maxBatchSize caps the amount of requests in a batch
+const maxBatchSize = 50
+
+// Error codes, as defined by the JSON-RPC 2.0 specification
+const (
+	// ErrCodeParse is returned when the request is not valid JSON
+	ErrCodeParse = -32700
+	// ErrCodeInvalidRequest is returned when the request is not a valid
+	// request object
+	ErrCodeInvalidRequest = -32600
+	// ErrCodeMethodNotFound is returned for unknown methods
+	ErrCodeMethodNotFound = -32601
+	// ErrCodeInvalidParams is returned when the parameters of a method are
+	// invalid
+	ErrCodeInvalidParams = -32602
+	// ErrCodeServer is returned when a method fails
+	ErrCodeServer = -32000
+	// ErrCodeUnauthorized is returned for admin methods called without
+	// valid credentials
+	ErrCodeUnauthorized = -32001
+	// ErrCodeTxRejected is returned when a submitted transaction is refused
+	// by the mempool. The reason is detailed in the data member
+	ErrCodeTxRejected = -32002
+	// ErrCodeRateLimited is returned to websocket clients sending requests
+	// too fast
+	ErrCodeRateLimited = -32003
+	// ErrCodeNotFound is returned when the requested block or transaction
+	// is unknown
+	ErrCodeNotFound = -32004
 )
 
 // JSONRequest defines a JSON-RPC request.
 type JSONRequest struct {
-	Method string   `json:"method"`
-	Params []string `json:"params,omitempty"`
+	JSONRPC string          `json:"jsonrpc,omitempty"`
+	Method  string          `json:"method"`
+	Params  []string        `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// JSONError defines the error object of a JSON-RPC response.
+type JSONError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Error implements the error interface
+func (e *JSONError) Error() string {
+	return e.Message
 }
 
 // JSONResponse defines a JSON-RPC response to a method call.
 type JSONResponse struct {
-	Result string `json:"result"`
-	Error  string `json:"error"`
+	JSONRPC string          `json:"jsonrpc"`
+	Result  string          `json:"result,omitempty"`
+	Error   *JSONError      `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
 }
 
 // handleRequest takes a JSON-RPC request and parses it, then returns the result to the
 // message sender.
 func (s *Server) handleRequest(w http.ResponseWriter, r http.Request, isAdmin bool) {
+	s.respond(w, r, func(req *JSONRequest) JSONResponse {
+		start := time.Now()
+		resp := s.serve(req, isAdmin)
+		// Wallet methods are the admin methods of the public namespace
+		if rpcAdminCmd[req.Method] {
+			audit(&r, req, isAdmin, false, start, resp.Error)
+		}
+
+		return resp
+	})
+}
+
+// handleAdminRequest serves a request for the admin namespace. Unlike the
+// public namespace, no method can be called without credentials.
+func (s *Server) handleAdminRequest(w http.ResponseWriter, r http.Request, isAdmin bool) {
+	s.respond(w, r, func(req *JSONRequest) JSONResponse {
+		start := time.Now()
+		var resp JSONResponse
+		if !isAdmin {
+			resp = newErrorResponse(req.ID, &JSONError{Code: ErrCodeUnauthorized, Message: "admin namespace requires credentials"})
+		} else {
+			resp = s.serveAdmin(req)
+		}
+
+		// Rejected calls are audited too, to spot clients probing the
+		// admin namespace
+		audit(&r, req, isAdmin, true, start, resp.Error)
+		return resp
+	})
+}
+
+// respond reads a request from r, and writes the response returned by
+// serveFn to w.
+func (s *Server) respond(w http.ResponseWriter, r http.Request, serveFn func(*JSONRequest) JSONResponse) {
 	// Only handle requests if server is started
 	if !s.started {
 		log.Warn("json-rpc service is not running")
@@ -42,53 +130,196 @@ func (s *Server) handleRequest(w http.ResponseWriter, r http.Request, isAdmin bo
 
 	log.Tracef("Request: %s", string(body))
 
+	// A batch is an array of requests, answered with an array of responses
+	var resp interface{}
+	if trimmed := bytes.TrimLeft(body, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		resp = serveBatch(trimmed, serveFn)
+	} else {
+		resp = serveSingle(body, ErrCodeParse, serveFn)
+	}
+
+	resultData, err := json.MarshalIndent(resp, "", "\t")
+	if err != nil {
+		log.Errorf("marshal response: %v", err)
+	}
+
+	if _, err := w.Write([]byte(resultData)); err != nil {
+		log.Errorf("write response: %v", err)
+		return
+	}
+}
+
+// serveSingle decodes a request and serves it. errCode is the code returned
+// if the request can not be decoded.
+func serveSingle(body []byte, errCode int, serveFn func(*JSONRequest) JSONResponse) JSONResponse {
 	var req JSONRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		log.Errorf("json.unmarshal request: %v", err)
-		return
+		if errCode == ErrCodeParse {
+			return newErrorResponse(nil, &JSONError{Code: errCode, Message: "parse error"})
+		}
+
+		return newErrorResponse(nil, &JSONError{Code: errCode, Message: "invalid request"})
+	}
+
+	return serveFn(&req)
+}
+
+// serveBatch serves the requests of a batch in order. Malformed, empty and
+// oversized batches get a single error response, as mandated by the JSON-RPC
+// specification.
+func serveBatch(body []byte, serveFn func(*JSONRequest) JSONResponse) interface{} {
+	var reqs []json.RawMessage
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		log.Errorf("json.unmarshal batch: %v", err)
+		return newErrorResponse(nil, &JSONError{Code: ErrCodeParse, Message: "parse error"})
+	}
+
+	if len(reqs) == 0 {
+		return newErrorResponse(nil, &JSONError{Code: ErrCodeInvalidRequest, Message: "empty batch"})
+	}
+
+	if len(reqs) > maxBatchSize {
+		return newErrorResponse(nil, &JSONError{Code: ErrCodeInvalidRequest, Message: fmt.Sprintf("batch exceeds %d requests", maxBatchSize)})
+	}
+
+	resps := make([]JSONResponse, 0, len(reqs))
+	for _, raw := range reqs {
+		// Elements which are not request objects are invalid requests
+		// rather than parse errors, as the batch itself is valid JSON
+		resps = append(resps, serveSingle(raw, ErrCodeInvalidRequest, serveFn))
+	}
+
+	return resps
+}
+
+// serve runs a single request and wraps the outcome in a response.
+func (s *Server) serve(req *JSONRequest, isAdmin bool) JSONResponse {
+	if req.Method == "" {
+		return newErrorResponse(req.ID, &JSONError{Code: ErrCodeInvalidRequest, Message: "missing method"})
 	}
 
-	// Parse and run passed method
-	result, err := s.runCmd(&req, isAdmin)
-	errorDesc := "null"
+	result, err := s.runCmd(req, isAdmin)
+	return newResponse(req, result, err)
+}
+
+// serveAdmin runs a single request of the admin namespace.
+func (s *Server) serveAdmin(req *JSONRequest) JSONResponse {
+	if req.Method == "" {
+		return newErrorResponse(req.ID, &JSONError{Code: ErrCodeInvalidRequest, Message: "missing method"})
+	}
+
+	fn, ok := adminCmd[req.Method]
+	if !ok {
+		return newErrorResponse(req.ID, &JSONError{Code: ErrCodeMethodNotFound, Message: fmt.Sprintf("admin method %s unrecognized", req.Method)})
+	}
+
+	start := time.Now()
+	result, err := run(s, fn, req.Params)
+	s.observe(req.Method, start, err)
+	return newResponse(req, result, err)
+}
+
+func newResponse(req *JSONRequest, result string, err *JSONError) JSONResponse {
 	if err != nil {
 		log.Errorf("%v", err)
-		errorDesc = err.Error()
-		result = "{}"
+		return newErrorResponse(req.ID, err)
 	}
 
-	resp := JSONResponse{Result: result, Error: errorDesc}
-	resultData, err := json.MarshalIndent(resp, "", "\t")
-	if err != nil {
-		log.Errorf("marshal response: %v", err)
+	return JSONResponse{
+		JSONRPC: jsonRPCVersion,
+		Result:  result,
+		ID:      nullID(req.ID),
 	}
+}
 
-	if _, err := w.Write([]byte(resultData)); err != nil {
-		log.Errorf("write response: %v", err)
-		return
+func newErrorResponse(id json.RawMessage, err *JSONError) JSONResponse {
+	return JSONResponse{
+		JSONRPC: jsonRPCVersion,
+		Error:   err,
+		ID:      nullID(id),
 	}
 }
 
+// nullID returns the JSON null literal for requests without an id, as the
+// id member is required in responses.
+func nullID(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+
+	return id
+}
+
 // runCmd parses and runs the specified method. Server is included as the receiver in case
 // the method needs to modify anything on the RPC server.
-func (s *Server) runCmd(r *JSONRequest, isAdmin bool) (string, error) {
+func (s *Server) runCmd(r *JSONRequest, isAdmin bool) (string, *JSONError) {
 
 	// Get method
 	fn, ok := rpcCmd[r.Method]
 	if !ok {
-		return "", fmt.Errorf("method %s unrecognized", r.Method)
+		return "", &JSONError{Code: ErrCodeMethodNotFound, Message: fmt.Sprintf("method %s unrecognized", r.Method)}
 	}
 
 	// Check if it is an admin-only method first if caller is not admin
 	if !isAdmin && rpcAdminCmd[r.Method] {
-		return "", fmt.Errorf("unauthorized call to method %v", r.Method)
+		return "", &JSONError{Code: ErrCodeUnauthorized, Message: fmt.Sprintf("unauthorized call to method %v", r.Method)}
+	}
+
+	return s.runCached(r.Method, fn, r.Params)
+}
+
+// runCached calls a method, serving its result from the cache if it is
+// cacheable.
+func (s *Server) runCached(method string, fn handler, params []string) (result string, jErr *JSONError) {
+	defer func(start time.Time) {
+		s.observe(method, start, jErr)
+	}(time.Now())
+
+	if s.cache == nil || !rpcCachedCmd[method] {
+		return run(s, fn, params)
+	}
+
+	key := cacheKey(method, params)
+	if result, ok := s.cache.get(key); ok {
+		return result, nil
+	}
+
+	result, err := run(s, fn, params)
+	if err == nil {
+		s.cache.put(key, result)
+	}
+
+	return result, err
+}
+
+// observe records the latency of a call started at start
+func (s *Server) observe(method string, start time.Time, err *JSONError) {
+	if s.metrics != nil {
+		s.metrics.observe(method, time.Since(start), err != nil)
 	}
+}
 
-	// Run method and return result
-	result, err := fn(s, r.Params)
+// run calls a method, and converts its error to a JSONError
+func run(s *Server, fn handler, params []string) (string, *JSONError) {
+	result, err := fn(s, params)
 	if err != nil {
-		return "", err
+		if jErr, ok := err.(*JSONError); ok {
+			return "", jErr
+		}
+
+		if err == database.ErrBlockNotFound || err == database.ErrTxNotFound {
+			return "", &JSONError{Code: ErrCodeNotFound, Message: err.Error()}
+		}
+
+		return "", &JSONError{Code: ErrCodeServer, Message: err.Error()}
 	}
 
 	return result, nil
 }
+
+// invalidParams returns a JSONError for methods called with the wrong
+// parameters
+func invalidParams(format string, a ...interface{}) error {
+	return &JSONError{Code: ErrCodeInvalidParams, Message: fmt.Sprintf(format, a...)}
+}
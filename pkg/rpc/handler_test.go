@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	logger "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerErrorCodes(t *testing.T) {
+	s := &Server{started: true}
+
+	for body, code := range map[string]int{
+		`{"jsonrpc":"2.0","method":`:                            ErrCodeParse,
+		`{"jsonrpc":"2.0","id":1}`:                              ErrCodeInvalidRequest,
+		`{"jsonrpc":"2.0","method":"pippo","id":1}`:             ErrCodeMethodNotFound,
+		`{"jsonrpc":"2.0","method":"deadletters","id":1}`:       ErrCodeUnauthorized,
+		`{"jsonrpc":"2.0","method":"createtransaction","id":1}`: ErrCodeUnauthorized,
+		`{"jsonrpc":"2.0","method":"getblockhash","id":1}`:      ErrCodeInvalidParams,
+	} {
+		resp := call(t, s, body)
+		if assert.NotNil(t, resp.Error, body) {
+			assert.Equal(t, code, resp.Error.Code, body)
+		}
+		assert.Equal(t, jsonRPCVersion, resp.JSONRPC)
+	}
+}
+
+func TestHandlerEchoesID(t *testing.T) {
+	s := &Server{started: true}
+
+	resp := call(t, s, `{"jsonrpc":"2.0","method":"pippo","id":"abc"}`)
+	assert.Equal(t, `"abc"`, string(resp.ID))
+
+	// Requests without id get a null one
+	resp = call(t, s, `{"jsonrpc":"2.0","method":"pippo"}`)
+	assert.Equal(t, "null", string(resp.ID))
+}
+
+func TestBatch(t *testing.T) {
+	s := &Server{started: true}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewBufferString(`[
+		{"jsonrpc":"2.0","method":"pippo","id":1},
+		{"jsonrpc":"2.0","method":"getblockhash","id":2},
+		42
+	]`))
+	w := httptest.NewRecorder()
+	s.handleRequest(w, *req, false)
+
+	var resps []JSONResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resps); err != nil {
+		t.Fatal(err)
+	}
+
+	// Responses come in the order of the requests
+	if assert.Len(t, resps, 3) {
+		assert.Equal(t, "1", string(resps[0].ID))
+		assert.Equal(t, ErrCodeMethodNotFound, resps[0].Error.Code)
+		assert.Equal(t, "2", string(resps[1].ID))
+		assert.Equal(t, ErrCodeInvalidParams, resps[1].Error.Code)
+		assert.Equal(t, ErrCodeInvalidRequest, resps[2].Error.Code)
+	}
+
+	// Empty and oversized batches get a single error
+	resp := call(t, s, `[]`)
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, ErrCodeInvalidRequest, resp.Error.Code)
+	}
+
+	batch := "[" + strings.Repeat(`{"jsonrpc":"2.0","method":"pippo","id":1},`, maxBatchSize) + `{"jsonrpc":"2.0","method":"pippo","id":1}]`
+	resp = call(t, s, batch)
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, ErrCodeInvalidRequest, resp.Error.Code)
+	}
+}
+
+func TestPageParams(t *testing.T) {
+	limit, cursor, err := pageParams(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultPageSize, limit)
+	assert.Equal(t, "", cursor)
+
+	// Limits are capped
+	limit, cursor, err = pageParams([]string{"100000", "42"})
+	assert.NoError(t, err)
+	assert.Equal(t, maxPageSize, limit)
+	assert.Equal(t, "42", cursor)
+
+	_, _, err = pageParams([]string{"-1"})
+	assert.Error(t, err)
+}
+
+func TestAdminNamespace(t *testing.T) {
+	s := &Server{started: true}
+
+	// Admin methods can not be called without credentials
+	resp := callAdmin(t, s, `{"jsonrpc":"2.0","method":"setloglevel","params":["info"],"id":1}`, false)
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, ErrCodeUnauthorized, resp.Error.Code)
+	}
+
+	// Public methods are not part of the admin namespace
+	resp = callAdmin(t, s, `{"jsonrpc":"2.0","method":"getblockhash","params":["1"],"id":1}`, true)
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, ErrCodeMethodNotFound, resp.Error.Code)
+	}
+
+	// Nor are admin methods part of the public namespace
+	resp = call(t, s, `{"jsonrpc":"2.0","method":"setloglevel","params":["info"],"id":1}`)
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, ErrCodeMethodNotFound, resp.Error.Code)
+	}
+
+	resp = callAdmin(t, s, `{"jsonrpc":"2.0","method":"setloglevel","params":["pippo"],"id":1}`, true)
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, ErrCodeInvalidParams, resp.Error.Code)
+	}
+
+	level := logger.GetLevel()
+	defer logger.SetLevel(level)
+
+	// Secrets are redacted from the configuration
+	prev := cfg.Get()
+	defer cfg.Mock(&prev)
+	r := prev
+	r.RPC.Pass = "s3cr3t"
+	cfg.Mock(&r)
+
+	resp = callAdmin(t, s, `{"jsonrpc":"2.0","method":"getconfig","id":1}`, true)
+	assert.Nil(t, resp.Error)
+	assert.NotContains(t, resp.Result, "s3cr3t")
+	assert.Contains(t, resp.Result, "[redacted]")
+
+	resp = callAdmin(t, s, `{"jsonrpc":"2.0","method":"setloglevel","params":["warning"],"id":1}`, true)
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, logger.WarnLevel, logger.GetLevel())
+}
+
+func call(t *testing.T, s *Server, body string) JSONResponse {
+	req := httptest.NewRequest("POST", "/", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	s.handleRequest(w, *req, false)
+	return decodeResponse(t, w)
+}
+
+func callAdmin(t *testing.T, s *Server, body string, isAdmin bool) JSONResponse {
+	req := httptest.NewRequest("POST", "/admin", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	s.handleAdminRequest(w, *req, isAdmin)
+	return decodeResponse(t, w)
+}
+
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) JSONResponse {
+	var resp JSONResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	return resp
+}
@@ -0,0 +1,135 @@
+package rpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+)
+
+// The latency of the calls and their outcome are served on /metrics as well,
+// labeled with the method
+var (
+	callLatency = metrics.NewHistogramVec("dusk_rpc_call_duration_seconds", "Time taken to serve the RPC calls, by method", "method", metrics.LatencyBuckets)
+	failedCalls = metrics.NewCounterVec("dusk_rpc_calls_failed_total", "RPC calls which returned an error, by method", "method")
+	slowCalls   = metrics.NewCounterVec("dusk_rpc_calls_slow_total", "RPC calls slower than rpc.slowCallThreshold, by method", "method")
+)
+
+// latencyBuckets are the upper bounds of the latency histograms. Slower
+// calls fall in an implicit last bucket.
+var latencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// callMetrics collects the latency of the calls to each method. Calls slower
+// than slowThreshold are logged, unless it is 0.
+type callMetrics struct {
+	lock          sync.Mutex
+	methods       map[string]*methodStats
+	slowThreshold time.Duration
+}
+
+type methodStats struct {
+	calls    uint64
+	failures uint64
+	slow     uint64
+	latency  time.Duration
+	// buckets[i] counts the calls which took up to latencyBuckets[i]. The
+	// last one counts the slower calls.
+	buckets []uint64
+}
+
+func newCallMetrics(slowThreshold time.Duration) *callMetrics {
+	return &callMetrics{
+		methods:       make(map[string]*methodStats),
+		slowThreshold: slowThreshold,
+	}
+}
+
+// setSlowThreshold changes the latency above which calls are logged
+func (m *callMetrics) setSlowThreshold(slowThreshold time.Duration) {
+	m.lock.Lock()
+	m.slowThreshold = slowThreshold
+	m.lock.Unlock()
+}
+
+// observe records a call to method which took d. Calls to unknown methods
+// should not be recorded, as the amount of methods tracked is not bounded.
+func (m *callMetrics) observe(method string, d time.Duration, failed bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	slow := m.slowThreshold > 0 && d > m.slowThreshold
+	if slow {
+		log.WithField("method", method).WithField("duration", d).Warn("slow rpc call")
+	}
+
+	stats, ok := m.methods[method]
+	if !ok {
+		stats = &methodStats{buckets: make([]uint64, len(latencyBuckets)+1)}
+		m.methods[method] = stats
+	}
+
+	stats.calls++
+	stats.latency += d
+	if failed {
+		stats.failures++
+	}
+
+	if slow {
+		stats.slow++
+	}
+
+	i := 0
+	for i < len(latencyBuckets) && d > latencyBuckets[i] {
+		i++
+	}
+	stats.buckets[i]++
+
+	callLatency.With(method).Observe(d.Seconds())
+	if failed {
+		failedCalls.With(method).Inc()
+	}
+
+	if slow {
+		slowCalls.With(method).Inc()
+	}
+}
+
+// methodMetricsJSON is the representation of the statistics of a method
+// returned by rpcmetrics. Histogram maps the upper bound of each bucket to
+// the amount of calls which fell in it.
+type methodMetricsJSON struct {
+	Calls     uint64            `json:"calls"`
+	Failures  uint64            `json:"failures"`
+	Slow      uint64            `json:"slow"`
+	LatencyUs int64             `json:"latencyUs"`
+	Histogram map[string]uint64 `json:"histogram"`
+}
+
+func (m *callMetrics) snapshot() map[string]methodMetricsJSON {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	res := make(map[string]methodMetricsJSON, len(m.methods))
+	for method, stats := range m.methods {
+		histogram := make(map[string]uint64, len(stats.buckets))
+		for i, count := range stats.buckets {
+			bound := "+Inf"
+			if i < len(latencyBuckets) {
+				bound = latencyBuckets[i].String()
+			}
+			histogram[bound] = count
+		}
+
+		res[method] = methodMetricsJSON{stats.calls, stats.failures, stats.slow, stats.latency.Microseconds(), histogram}
+	}
+
+	return res
+}
@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallMetrics(t *testing.T) {
+	m := newCallMetrics(time.Second)
+	m.observe("getblock", 500*time.Microsecond, false)
+	m.observe("getblock", 2*time.Millisecond, true)
+	m.observe("getblock", time.Minute, false)
+
+	stats := m.snapshot()["getblock"]
+	assert.Equal(t, uint64(3), stats.Calls)
+	assert.Equal(t, uint64(1), stats.Failures)
+	assert.Equal(t, uint64(1), stats.Slow)
+	assert.Equal(t, uint64(1), stats.Histogram["1ms"])
+	assert.Equal(t, uint64(1), stats.Histogram["5ms"])
+	assert.Equal(t, uint64(0), stats.Histogram["5s"])
+	assert.Equal(t, uint64(1), stats.Histogram["+Inf"])
+
+	// and served on /metrics
+	count, _ := callLatency.With("getblock").Count()
+	assert.Equal(t, uint64(3), count)
+	assert.Equal(t, uint64(1), failedCalls.With("getblock").Value())
+	assert.Equal(t, uint64(1), slowCalls.With("getblock").Value())
+}
+
+func TestMethodLatencyRecorded(t *testing.T) {
+	s := &Server{started: true, metrics: newCallMetrics(0)}
+	call(t, s, `{"jsonrpc":"2.0","method":"rpcmetrics","id":1}`)
+
+	resp := call(t, s, `{"jsonrpc":"2.0","method":"rpcmetrics","id":1}`)
+	assert.Contains(t, resp.Result, `"rpcmetrics":{"calls":1`)
+}
@@ -0,0 +1,45 @@
+package rpc
+
+import (
+	"strconv"
+)
+
+const (
+	// defaultPageSize is the amount of items returned by list methods called
+	// without a limit
+	defaultPageSize = 20
+	// maxPageSize caps the amount of items returned by list methods
+	maxPageSize = 100
+)
+
+// page is the result of list methods. Next is the cursor to pass in order to
+// fetch the following page, and is omitted on the last one.
+type page struct {
+	Items interface{} `json:"items"`
+	Next  string      `json:"next,omitempty"`
+}
+
+// pageParams parses the parameters shared by list methods: the maximum amount
+// of items to return and the cursor returned by the previous call, in this
+// order. Both are optional, and limits exceeding maxPageSize are capped.
+func pageParams(params []string) (int, string, error) {
+	limit := defaultPageSize
+	if len(params) > 0 && params[0] != "" {
+		l, err := strconv.Atoi(params[0])
+		if err != nil || l <= 0 {
+			return 0, "", invalidParams("invalid limit %s", params[0])
+		}
+
+		limit = l
+		if limit > maxPageSize {
+			limit = maxPageSize
+		}
+	}
+
+	var cursor string
+	if len(params) > 1 {
+		cursor = params[1]
+	}
+
+	return limit, cursor, nil
+}
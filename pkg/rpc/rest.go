@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// restError is the body of failed REST requests
+type restError struct {
+	Error string `json:"error"`
+}
+
+// registerREST sets up the read-only REST gateway. Every route is served by
+// the JSON-RPC method of the same name, so that both share their results and
+// their cache.
+func (s *Server) registerREST(mux *http.ServeMux) {
+	mux.HandleFunc("/block/", s.restHandler("getblock", getBlock, "/block/"))
+	mux.HandleFunc("/tx/", s.restHandler("gettransaction", getTransaction, "/tx/"))
+	mux.HandleFunc("/txproof/", s.restHandler("gettxproof", getTxProof, "/txproof/"))
+	mux.HandleFunc("/status", s.restHandler("getnodestatus", getNodeStatus, ""))
+	mux.HandleFunc("/peers", s.restHandler("getpeerinfo", getPeerInfo, ""))
+	mux.HandleFunc("/healthz", s.handleHealth)
+}
+
+// restHandler returns the handler of a route. If prefix is set, the rest of
+// the path is passed to the method as its only parameter.
+func (s *Server) restHandler(method string, fn handler, prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			writeRESTError(w, http.StatusMethodNotAllowed, "only GET is supported")
+			return
+		}
+
+		var params []string
+		if prefix != "" {
+			param := strings.TrimPrefix(r.URL.Path, prefix)
+			if param == "" || strings.Contains(param, "/") {
+				writeRESTError(w, http.StatusNotFound, "not found")
+				return
+			}
+			params = []string{param}
+		}
+
+		result, err := s.runCached(method, fn, params)
+		if err != nil {
+			writeRESTError(w, restStatus(err), err.Message)
+			return
+		}
+
+		if _, err := w.Write([]byte(result)); err != nil {
+			log.Errorf("write response: %v", err)
+		}
+	}
+}
+
+// restStatus maps a JSON-RPC error code to an HTTP status
+func restStatus(err *JSONError) int {
+	switch err.Code {
+	case ErrCodeInvalidParams:
+		return http.StatusBadRequest
+	case ErrCodeNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeRESTError(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(restError{msg}); err != nil {
+		log.Errorf("write response: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRESTHandler(t *testing.T) {
+	s := &Server{started: true}
+	fn := func(s *Server, params []string) (string, error) {
+		if params[0] == "unknown" {
+			return "", database.ErrBlockNotFound
+		}
+
+		return `{"hash":"` + params[0] + `"}`, nil
+	}
+	h := s.restHandler("getblock", fn, "/block/")
+
+	for path, status := range map[string]int{
+		"/block/aa":      http.StatusOK,
+		"/block/":        http.StatusNotFound,
+		"/block/aa/bb":   http.StatusNotFound,
+		"/block/unknown": http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, status, w.Code, path)
+		assert.True(t, json.Valid(w.Body.Bytes()), path)
+	}
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest("POST", "/block/aa", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
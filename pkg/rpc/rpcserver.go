@@ -2,15 +2,21 @@ package rpc
 
 import (
 	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
+	"errors"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	logger "github.com/sirupsen/logrus"
 
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"golang.org/x/crypto/sha3"
@@ -26,9 +32,26 @@ type Server struct {
 	rpcBus   *rpcbus.RPCBus
 
 	authSHA  []byte       // Hash of the auth credentials
+	tokenSHA []byte       // Hash of the bearer token
 	listener net.Listener // RPC Server listener
 
 	startTime int64
+
+	// websocket clients, fed by the EventBus listeners below
+	wsHub                *wsHub
+	intermediateListener uint32
+	acceptedListener     uint32
+
+	// results of the read-only methods. Nil if caching is disabled
+	cache         *resultCache
+	cacheListener uint32
+
+	// latency of the calls, per method
+	metrics *callMetrics
+
+	// shutdown is closed when the stopnode admin method is called
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
 }
 
 // NewRPCServer instantiates a new RPCServer.
@@ -37,6 +60,8 @@ func NewRPCServer(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus) (*Server,
 	srv := Server{
 		eventBus: eventBus,
 		rpcBus:   rpcBus,
+		shutdown: make(chan struct{}),
+		metrics:  newCallMetrics(time.Duration(cfg.Get().RPC.SlowCallThreshold) * time.Millisecond),
 	}
 
 	user := cfg.Get().RPC.User
@@ -49,6 +74,19 @@ func NewRPCServer(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus) (*Server,
 		srv.authSHA = authSHA[:]
 	}
 
+	if token := cfg.Get().RPC.Token; token != "" {
+		tokenSHA := sha3.Sum256([]byte("Bearer " + token))
+		srv.tokenSHA = tokenSHA[:]
+	}
+
+	if ttl := cfg.Get().RPC.CacheTTL; ttl > 0 {
+		srv.cache = newResultCache(time.Duration(ttl) * time.Second)
+	}
+
+	cfg.Subscribe("rpc", func(r cfg.Registry) {
+		srv.metrics.setSlowThreshold(time.Duration(r.RPC.SlowCallThreshold) * time.Millisecond)
+	})
+
 	return &srv, nil
 }
 
@@ -76,6 +114,40 @@ func (s *Server) Start() error {
 		s.handleRequest(w, *r, isAdmin)
 	})
 
+	// Admin namespace handler
+	ServeMux.HandleFunc("/admin", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Connection", "close")
+		w.Header().Set("Content-Type", "application/json")
+
+		r.Close = true
+
+		isAdmin := s.checkAuth(r)
+		s.handleAdminRequest(w, *r, isAdmin)
+	})
+
+	// Read-only REST gateway
+	s.registerREST(ServeMux)
+
+	// Websocket handler, pushing the events of the chain
+	s.wsHub = newWSHub(cfg.Get().RPC.MaxSubscribers)
+	ServeMux.Handle("/ws", s.newWebsocketHandler())
+	s.intermediateListener = s.eventBus.Subscribe(topics.IntermediateBlock, eventbus.NewCallbackListener(s.wsHub.onIntermediateBlock))
+	s.acceptedListener = s.eventBus.Subscribe(topics.AcceptedBlock, eventbus.NewCallbackListener(s.wsHub.onAcceptedBlock))
+
+	// Cached results are dropped as soon as the chain moves on
+	if s.cache != nil {
+		s.cacheListener = s.eventBus.Subscribe(topics.AcceptedBlock, eventbus.NewCallbackListener(s.cache.onAcceptedBlock))
+	}
+
+	if caFile := cfg.Get().RPC.ClientCAFile; caFile != "" {
+		tlsConfig, err := clientAuthConfig(caFile)
+		if err != nil {
+			return err
+		}
+
+		httpServer.TLSConfig = tlsConfig
+	}
+
 	if network == "unix" {
 		if err := os.RemoveAll(address); err != nil {
 			return err
@@ -103,17 +175,54 @@ func (s *Server) Start() error {
 func (s *Server) listenOnHTTPServer(httpServer *http.Server) {
 	log.Infof("RPC server listening on (%s) %s", cfg.Get().RPC.Network, cfg.Get().RPC.Address)
 
-	if err := httpServer.Serve(s.listener); err != http.ErrServerClosed {
+	var err error
+	certFile, keyFile := cfg.Get().RPC.CertFile, cfg.Get().RPC.KeyFile
+	if certFile != "" && keyFile != "" {
+		err = httpServer.ServeTLS(s.listener, certFile, keyFile)
+	} else {
+		err = httpServer.Serve(s.listener)
+	}
+
+	if err != http.ErrServerClosed {
 		log.Errorf("RPC server stopped with error %v", err)
 	} else {
 		log.Info("RPC server stopped listening")
 	}
 }
 
+// clientAuthConfig returns a TLS configuration verifying the client
+// certificates, if any, against the CA in caFile.
+func clientAuthConfig(caFile string) (*tls.Config, error) {
+	if cfg.Get().RPC.CertFile == "" || cfg.Get().RPC.KeyFile == "" {
+		return nil, errors.New("client certificates require rpc.certFile and rpc.keyFile to be set")
+	}
+
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no certificate found in " + caFile)
+	}
+
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}, nil
+}
+
 // checkAuth checks whether supplied credentials match the server credentials.
+// Basic authentication, Bearer tokens and client certificates are accepted.
 func (s *Server) checkAuth(r *http.Request) bool {
+	// Client certificates are only verified when rpc.clientCAFile is set
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return true
+	}
+
 	// If no authentication is set, disallow admin outright.
-	if s.authSHA == nil {
+	if s.authSHA == nil && s.tokenSHA == nil {
 		return false
 	}
 
@@ -123,16 +232,31 @@ func (s *Server) checkAuth(r *http.Request) bool {
 	}
 
 	authSHA := sha3.Sum256([]byte(authHeader[0]))
-	if cmp := subtle.ConstantTimeCompare(authSHA[:], s.authSHA[:]); cmp == 1 {
+	if s.authSHA != nil && subtle.ConstantTimeCompare(authSHA[:], s.authSHA) == 1 {
+		return true
+	}
+
+	if s.tokenSHA != nil && subtle.ConstantTimeCompare(authSHA[:], s.tokenSHA) == 1 {
 		return true
 	}
 
 	return false
 }
 
+// ShutdownRequested returns a channel which is closed when the node is asked
+// to stop through the stopnode admin method.
+func (s *Server) ShutdownRequested() <-chan struct{} {
+	return s.shutdown
+}
+
 // Stop the RPC server
 func (s *Server) Stop() error {
 	s.started = false
+	s.eventBus.Unsubscribe(topics.IntermediateBlock, s.intermediateListener)
+	s.eventBus.Unsubscribe(topics.AcceptedBlock, s.acceptedListener)
+	if s.cache != nil {
+		s.eventBus.Unsubscribe(topics.AcceptedBlock, s.cacheListener)
+	}
 	if err := s.listener.Close(); err != nil {
 		log.Errorf("error shutting down RPC, %v\n", err)
 		return err
@@ -0,0 +1,222 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/block"
+)
+
+// statusTimeout bounds each of the queries made to the subsystems when
+// collecting the node status. A subsystem which does not answer in time is
+// considered stalled.
+const statusTimeout = 2 * time.Second
+
+// nodeStatusJSON is the representation of the node status returned by
+// getnodestatus
+type nodeStatusJSON struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"goversion"`
+	Uptime    int64  `json:"uptime"`
+
+	Height       uint64  `json:"height"`
+	Hash         string  `json:"hash"`
+	Timestamp    int64   `json:"timestamp"`
+	SyncProgress float64 `json:"syncprogress"`
+
+	// Consensus is null if the node does not take part in the consensus
+	Consensus *consensusStateJSON `json:"consensus"`
+
+	Peers        int    `json:"peers"`
+	MempoolSize  uint64 `json:"mempoolsize"`
+	MempoolBytes int    `json:"mempoolbytes"`
+	DBSize       int64  `json:"dbsize"`
+}
+
+type consensusStateJSON struct {
+	Round uint64 `json:"round"`
+	Step  uint8  `json:"step"`
+}
+
+var getNodeStatus = func(s *Server, params []string) (string, error) {
+	result := nodeStatusJSON{
+		Version:   protocol.NodeVer.String(),
+		Commit:    protocol.GitCommit,
+		GoVersion: runtime.Version(),
+		Uptime:    time.Now().Unix() - s.startTime,
+	}
+
+	tip, err := s.fetchTip()
+	if err != nil {
+		return "", err
+	}
+	result.Height = tip.Height
+	result.Hash = hex.EncodeToString(tip.Hash)
+	result.Timestamp = tip.Timestamp
+
+	progressBuf, err := s.rpcBus.Call(rpcbus.GetSyncProgress, rpcbus.NewRequest(bytes.Buffer{}), statusTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	if result.SyncProgress, err = strconv.ParseFloat(progressBuf.String(), 64); err != nil {
+		return "", err
+	}
+
+	// The consensus state is only served while the consensus is running
+	if stateBuf, err := s.rpcBus.Call(rpcbus.GetConsensusState, rpcbus.NewRequest(bytes.Buffer{}), statusTimeout); err == nil {
+		state := &consensusStateJSON{}
+		if err := encoding.ReadUint64LE(&stateBuf, &state.Round); err != nil {
+			return "", err
+		}
+
+		if err := encoding.ReadUint8(&stateBuf, &state.Step); err != nil {
+			return "", err
+		}
+		result.Consensus = state
+	}
+
+	peersBuf, err := s.rpcBus.Call(rpcbus.GetPeerInfo, rpcbus.NewRequest(bytes.Buffer{}), statusTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	peers, err := peer.UnmarshalInfo(&peersBuf)
+	if err != nil {
+		return "", err
+	}
+	result.Peers = len(peers)
+
+	if result.MempoolSize, result.MempoolBytes, err = s.fetchMempoolSize(); err != nil {
+		return "", err
+	}
+
+	if result.DBSize, err = dirSize(cfg.Get().Database.Dir); err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// fetchTip returns the header of the last block of the chain
+func (s *Server) fetchTip() (*block.Header, error) {
+	blkBuf, err := s.rpcBus.Call(rpcbus.GetLastBlock, rpcbus.NewRequest(bytes.Buffer{}), statusTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&blkBuf, blk); err != nil {
+		return nil, err
+	}
+
+	return blk.Header, nil
+}
+
+// fetchMempoolSize returns the amount of verified txs in the mempool, and
+// their total size in bytes
+func (s *Server) fetchMempoolSize() (uint64, int, error) {
+	// An empty tx id returns the whole content of the mempool
+	txsBuf, err := s.rpcBus.Call(rpcbus.GetMempoolTxs, rpcbus.NewRequest(bytes.Buffer{}), statusTimeout)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	count, err := encoding.ReadVarInt(&txsBuf)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return count, txsBuf.Len(), nil
+}
+
+// dirSize returns the total size of the files under path
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+
+	return size, err
+}
+
+// healthJSON is the body of /healthz responses. Checks maps every critical
+// subsystem to "ok", or to the reason it is considered stalled.
+type healthJSON struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// handleHealth serves the /healthz endpoint, meant for load balancers and
+// orchestration tools. It answers 503 if any critical subsystem is stalled:
+// the chain or the mempool not answering, or the tip of the chain being older
+// than rpc.stallTimeout seconds.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	health := healthJSON{Status: "ok", Checks: make(map[string]string)}
+
+	tip, err := s.fetchTip()
+	switch {
+	case err != nil:
+		health.Checks["chain"] = err.Error()
+	case s.isStalled(tip.Timestamp, time.Now()):
+		health.Checks["chain"] = "no block accepted since " + time.Unix(tip.Timestamp, 0).UTC().Format(time.RFC3339)
+	default:
+		health.Checks["chain"] = "ok"
+	}
+
+	health.Checks["mempool"] = "ok"
+	if _, _, err := s.fetchMempoolSize(); err != nil {
+		health.Checks["mempool"] = err.Error()
+	}
+
+	status := http.StatusOK
+	for _, check := range health.Checks {
+		if check != "ok" {
+			health.Status = "stalled"
+			status = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		log.Errorf("write response: %v", err)
+	}
+}
+
+// isStalled returns true if a tip with the given timestamp is too old.
+func (s *Server) isStalled(timestamp int64, now time.Time) bool {
+	timeout := cfg.Get().RPC.StallTimeout
+	if timeout == 0 {
+		return false
+	}
+
+	return now.Sub(time.Unix(timestamp, 0)) > time.Duration(timeout)*time.Second
+}
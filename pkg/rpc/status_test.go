@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthStalled(t *testing.T) {
+	// Nothing answers on the bus
+	s := &Server{started: true, rpcBus: rpcbus.New()}
+
+	w := httptest.NewRecorder()
+	s.handleHealth(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var health healthJSON
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &health))
+	assert.Equal(t, "stalled", health.Status)
+	assert.NotEqual(t, "ok", health.Checks["chain"])
+	assert.NotEqual(t, "ok", health.Checks["mempool"])
+}
+
+func TestIsStalled(t *testing.T) {
+	s := &Server{}
+	now := time.Now()
+	// The check is disabled by default
+	assert.False(t, s.isStalled(now.Add(-time.Hour).Unix(), now))
+
+	r := cfg.Get()
+	defer cfg.Mock(&r)
+	stalled := r
+	stalled.RPC.StallTimeout = 60
+	cfg.Mock(&stalled)
+	assert.False(t, s.isStalled(now.Add(-time.Second).Unix(), now))
+	assert.True(t, s.isStalled(now.Add(-time.Hour).Unix(), now))
+}
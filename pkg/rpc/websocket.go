@@ -0,0 +1,425 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/marshalling"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/wshub"
+	"github.com/dusk-network/dusk-wallet/block"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// maxSubscriptionsPerClient caps the subscriptions of a single
+	// websocket client
+	maxSubscriptionsPerClient = 16
+
+	// clientQueueSize is the amount of messages buffered for a client.
+	// Events are dropped for clients falling further behind.
+	clientQueueSize = 64
+
+	// clientRequestRate and clientRequestBurst define the token bucket
+	// limiting the requests of a single websocket client
+	clientRequestRate  = 10
+	clientRequestBurst = 20
+)
+
+// Topics a websocket client can subscribe to
+const (
+	// topicNewBlock notifies every block reaching the tip of the chain
+	topicNewBlock = "newBlock"
+	// topicFinality notifies every block becoming final
+	topicFinality = "finality"
+	// topicTxStatus notifies the progress of a single transaction
+	topicTxStatus = "txStatus"
+)
+
+// Statuses of the txStatus topic
+const (
+	txPending  = "pending"
+	txIncluded = "included"
+	txFinal    = "final"
+)
+
+// ErrTooManySubscriptions is returned when a client exceeds
+// maxSubscriptionsPerClient
+var ErrTooManySubscriptions = errors.New("too many subscriptions")
+
+// wsEvent is the payload of the notifications pushed to websocket clients
+type wsEvent struct {
+	Subscription uint32      `json:"subscription"`
+	Topic        string      `json:"topic"`
+	Data         interface{} `json:"data"`
+}
+
+// wsNotification is a JSON-RPC notification carrying an event
+type wsNotification struct {
+	JSONRPC string  `json:"jsonrpc"`
+	Method  string  `json:"method"`
+	Params  wsEvent `json:"params"`
+}
+
+// finalityJSON is the data of the finality topic
+type finalityJSON struct {
+	Height uint64 `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// txStatusJSON is the data of the txStatus topic
+type txStatusJSON struct {
+	TxID   string `json:"txid"`
+	Status string `json:"status"`
+	Height uint64 `json:"height,omitempty"`
+}
+
+type wsSubscription struct {
+	topic string
+	// txid is only set for the txStatus topic
+	txid string
+}
+
+// wsBroadcast is an event broadcast to the clients subscribed to topic. If
+// match is set, only the subscriptions it accepts are notified.
+type wsBroadcast struct {
+	topic string
+	match func(wsSubscription) bool
+	data  interface{}
+}
+
+// wsClient is a websocket connection, along with its subscriptions.
+type wsClient struct {
+	lock   sync.Mutex
+	subs   map[uint32]wsSubscription
+	nextID uint32
+
+	queue   chan interface{}
+	limiter *rateLimiter
+}
+
+func newWSClient() *wsClient {
+	return &wsClient{
+		subs:    make(map[uint32]wsSubscription),
+		queue:   make(chan interface{}, clientQueueSize),
+		limiter: newRateLimiter(clientRequestRate, clientRequestBurst),
+	}
+}
+
+func (c *wsClient) subscribe(sub wsSubscription) (uint32, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.subs) >= maxSubscriptionsPerClient {
+		return 0, ErrTooManySubscriptions
+	}
+
+	c.nextID++
+	c.subs[c.nextID] = sub
+	return c.nextID, nil
+}
+
+func (c *wsClient) unsubscribe(id uint32) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, ok := c.subs[id]
+	delete(c.subs, id)
+	return ok
+}
+
+// Notify queues a wsBroadcast for every subscription it matches. It never
+// blocks, so that a slow client can not hold back the EventBus.
+func (c *wsClient) Notify(event interface{}) {
+	b := event.(wsBroadcast)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for id, sub := range c.subs {
+		if sub.topic != b.topic || (b.match != nil && !b.match(sub)) {
+			continue
+		}
+
+		c.push(id, b.topic, b.data)
+	}
+}
+
+func (c *wsClient) push(id uint32, topic string, data interface{}) {
+	n := wsNotification{
+		JSONRPC: jsonRPCVersion,
+		Method:  "event",
+		Params:  wsEvent{id, topic, data},
+	}
+
+	select {
+	case c.queue <- n:
+	default:
+		log.Warnf("websocket client is lagging behind, dropping %s event", topic)
+	}
+}
+
+// wsHub keeps track of the websocket clients, and feeds them the events
+// bridged from the EventBus.
+type wsHub struct {
+	*wshub.Hub
+}
+
+func newWSHub(max int) *wsHub {
+	return &wsHub{wshub.New(max)}
+}
+
+func (h *wsHub) broadcast(topic string, match func(wsSubscription) bool, data interface{}) {
+	h.Broadcast(wsBroadcast{topic, match, data})
+}
+
+// onIntermediateBlock is the callback for the IntermediateBlock topic. The
+// block is the new tip of the chain, and its txs are included.
+func (h *wsHub) onIntermediateBlock(m bytes.Buffer) error {
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&m, blk); err != nil {
+		return err
+	}
+
+	b, err := newBlockJSON(blk.Header, blk.Txs)
+	if err != nil {
+		return err
+	}
+
+	h.broadcast(topicNewBlock, nil, b)
+	h.notifyTxs(b, txIncluded)
+	return nil
+}
+
+// onAcceptedBlock is the callback for the AcceptedBlock topic. The block and
+// its txs are final.
+func (h *wsHub) onAcceptedBlock(m bytes.Buffer) error {
+	blk := block.NewBlock()
+	if err := marshalling.UnmarshalBlock(&m, blk); err != nil {
+		return err
+	}
+
+	b, err := newBlockJSON(blk.Header, blk.Txs)
+	if err != nil {
+		return err
+	}
+
+	h.broadcast(topicFinality, nil, finalityJSON{b.Height, b.Hash})
+	h.notifyTxs(b, txFinal)
+	return nil
+}
+
+func (h *wsHub) notifyTxs(b blockJSON, status string) {
+	for _, txid := range b.Txs {
+		txid := txid
+		match := func(sub wsSubscription) bool {
+			return sub.txid == txid
+		}
+
+		h.broadcast(topicTxStatus, match, txStatusJSON{txid, status, b.Height})
+	}
+}
+
+// handleWebsocket serves a websocket client. Clients send JSON-RPC requests
+// to subscribe, such as `{"jsonrpc":"2.0","method":"subscribe","params":["txStatus","<txid>"],"id":1}`,
+// which return the id of the subscription. Events are then pushed as
+// notifications until the client unsubscribes or disconnects.
+func (s *Server) handleWebsocket(ws *websocket.Conn) {
+	defer ws.Close()
+
+	c := newWSClient()
+	if err := s.wsHub.Add(c); err != nil {
+		_ = websocket.JSON.Send(ws, newErrorResponse(nil, &JSONError{Code: ErrCodeServer, Message: err.Error()}))
+		return
+	}
+	defer s.wsHub.Remove(c)
+
+	// quit is closed once the client disconnects, done once we stop
+	// writing to it
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(quit)
+		for {
+			var req JSONRequest
+			if err := websocket.JSON.Receive(ws, &req); err != nil {
+				switch err.(type) {
+				case *json.SyntaxError:
+					c.respond(newErrorResponse(nil, &JSONError{Code: ErrCodeParse, Message: "parse error"}), done)
+					continue
+				case *json.UnmarshalTypeError:
+					c.respond(newErrorResponse(nil, &JSONError{Code: ErrCodeInvalidRequest, Message: "invalid request"}), done)
+					continue
+				}
+				return
+			}
+
+			if !c.limiter.allow() {
+				c.respond(newErrorResponse(req.ID, &JSONError{Code: ErrCodeRateLimited, Message: "rate limit exceeded"}), done)
+				continue
+			}
+
+			c.respond(s.serveWebsocket(c, &req), done)
+		}
+	}()
+
+	for {
+		select {
+		case msg := <-c.queue:
+			if err := websocket.JSON.Send(ws, msg); err != nil {
+				return
+			}
+		case <-quit:
+			return
+		}
+	}
+}
+
+// respond queues the response to a request. Unlike events, responses are
+// never dropped.
+func (c *wsClient) respond(resp JSONResponse, done <-chan struct{}) {
+	select {
+	case c.queue <- resp:
+	case <-done:
+	}
+}
+
+// serveWebsocket runs a subscribe or unsubscribe request.
+func (s *Server) serveWebsocket(c *wsClient, req *JSONRequest) JSONResponse {
+	var result string
+	var err error
+	switch req.Method {
+	case "subscribe":
+		result, err = s.wsSubscribe(c, req.Params)
+	case "unsubscribe":
+		result, err = wsUnsubscribe(c, req.Params)
+	case "":
+		return newErrorResponse(req.ID, &JSONError{Code: ErrCodeInvalidRequest, Message: "missing method"})
+	default:
+		return newErrorResponse(req.ID, &JSONError{Code: ErrCodeMethodNotFound, Message: fmt.Sprintf("method %s unrecognized", req.Method)})
+	}
+
+	if err != nil {
+		if jErr, ok := err.(*JSONError); ok {
+			return newErrorResponse(req.ID, jErr)
+		}
+
+		return newErrorResponse(req.ID, &JSONError{Code: ErrCodeServer, Message: err.Error()})
+	}
+
+	return newResponse(req, result, nil)
+}
+
+func (s *Server) wsSubscribe(c *wsClient, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("subscribe expects a topic")
+	}
+
+	sub := wsSubscription{topic: params[0]}
+	switch sub.topic {
+	case topicNewBlock, topicFinality:
+	case topicTxStatus:
+		if len(params) < 2 {
+			return "", invalidParams("txStatus expects a transaction id")
+		}
+
+		txid, err := hex.DecodeString(params[1])
+		if err != nil || len(txid) != 32 {
+			return "", invalidParams("invalid transaction id %s", params[1])
+		}
+		sub.txid = hex.EncodeToString(txid)
+	default:
+		return "", invalidParams("unknown topic %s", sub.topic)
+	}
+
+	id, err := c.subscribe(sub)
+	if err != nil {
+		return "", err
+	}
+
+	// Transactions waiting in the mempool are reported right away, as
+	// there would be no event for them otherwise
+	if sub.topic == topicTxStatus && s.inMempool(sub.txid) {
+		c.lock.Lock()
+		c.push(id, topicTxStatus, txStatusJSON{TxID: sub.txid, Status: txPending})
+		c.lock.Unlock()
+	}
+
+	return strconv.FormatUint(uint64(id), 10), nil
+}
+
+func wsUnsubscribe(c *wsClient, params []string) (string, error) {
+	if len(params) < 1 {
+		return "", invalidParams("unsubscribe expects a subscription id")
+	}
+
+	id, err := strconv.ParseUint(params[0], 10, 32)
+	if err != nil || !c.unsubscribe(uint32(id)) {
+		return "", invalidParams("unknown subscription %s", params[0])
+	}
+
+	return "unsubscribed", nil
+}
+
+// inMempool returns true if the mempool holds the tx identified by txid.
+func (s *Server) inMempool(txid string) bool {
+	if s.rpcBus == nil {
+		return false
+	}
+
+	id, _ := hex.DecodeString(txid)
+	txsBuf, err := s.rpcBus.Call(rpcbus.GetMempoolTxs, rpcbus.NewRequest(*bytes.NewBuffer(id)), 5*time.Second)
+	if err != nil {
+		return false
+	}
+
+	count, err := encoding.ReadVarInt(&txsBuf)
+	return err == nil && count > 0
+}
+
+// newWebsocketHandler returns the handler for the websocket endpoint.
+// Browsers can only connect from the origins in rpc.allowedOrigins.
+func (s *Server) newWebsocketHandler() http.Handler {
+	return wshub.Handler(s.handleWebsocket, cfg.Get().RPC.AllowedOrigins)
+}
+
+// rateLimiter is a token bucket, refilled at rate tokens per second up to
+// burst tokens. It is not safe for concurrent use.
+type rateLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate, burst float64) *rateLimiter {
+	return &rateLimiter{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// allow consumes a token, if any is available.
+func (r *rateLimiter) allow() bool {
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.rate
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.last = now
+
+	if r.tokens < 1 {
+		return false
+	}
+
+	r.tokens--
+	return true
+}
@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/wshub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebsocketTxStatus(t *testing.T) {
+	s := &Server{}
+	hub := newWSHub(0)
+	c := newWSClient()
+	assert.NoError(t, hub.Add(c))
+
+	txid := strings.Repeat("ab", 32)
+	resp := s.serveWebsocket(c, &JSONRequest{Method: "subscribe", Params: []string{topicTxStatus, txid}})
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, "1", resp.Result)
+
+	// Only the subscribed tx is notified
+	hub.notifyTxs(blockJSON{Height: 5, Txs: []string{strings.Repeat("cd", 32), txid}}, txFinal)
+	if assert.Len(t, c.queue, 1) {
+		n := (<-c.queue).(wsNotification)
+		assert.Equal(t, uint32(1), n.Params.Subscription)
+		assert.Equal(t, txStatusJSON{txid, txFinal, 5}, n.Params.Data)
+	}
+
+	// No more events after unsubscribing
+	resp = s.serveWebsocket(c, &JSONRequest{Method: "unsubscribe", Params: []string{"1"}})
+	assert.Nil(t, resp.Error)
+	hub.notifyTxs(blockJSON{Height: 6, Txs: []string{txid}}, txFinal)
+	assert.Empty(t, c.queue)
+}
+
+func TestWebsocketCaps(t *testing.T) {
+	s := &Server{}
+	hub := newWSHub(1)
+	c := newWSClient()
+	assert.NoError(t, hub.Add(c))
+	assert.Equal(t, wshub.ErrTooManySubscribers, hub.Add(newWSClient()))
+
+	for i := 0; i < maxSubscriptionsPerClient; i++ {
+		resp := s.serveWebsocket(c, &JSONRequest{Method: "subscribe", Params: []string{topicNewBlock}})
+		assert.Nil(t, resp.Error)
+	}
+
+	resp := s.serveWebsocket(c, &JSONRequest{Method: "subscribe", Params: []string{topicNewBlock}})
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, ErrTooManySubscriptions.Error(), resp.Error.Message)
+	}
+
+	// Events are dropped rather than blocking the hub
+	for i := 0; i < clientQueueSize; i++ {
+		hub.broadcast(topicNewBlock, nil, blockJSON{})
+	}
+	assert.Len(t, c.queue, clientQueueSize)
+}
+
+func TestRateLimiter(t *testing.T) {
+	r := newRateLimiter(1, 2)
+	assert.True(t, r.allow())
+	assert.True(t, r.allow())
+	assert.False(t, r.allow())
+}
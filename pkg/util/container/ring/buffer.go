@@ -76,16 +76,7 @@ func (r *Buffer) GetAll() ([][]byte, bool) {
 		r.notEmpty.Wait()
 	}
 
-	items := make([][]byte, 0)
-	for i, itemPtr := range r.items {
-		if itemPtr == nil {
-			break
-		}
-		items = append(items, itemPtr)
-		r.items[i] = nil
-	}
-
-	r.writeIndex = -1
+	items := r.drain()
 	r.mu.Unlock()
 
 	return items, r.close
@@ -115,3 +106,20 @@ func (r *Buffer) Closed() bool {
 	r.mu.Unlock()
 	return closed
 }
+
+// Len returns the amount of items waiting to be consumed
+func (r *Buffer) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for n < len(r.items) && r.items[n] != nil {
+		n++
+	}
+	return n
+}
+
+// Cap returns the amount of items the buffer holds before overwriting the
+// oldest ones
+func (r *Buffer) Cap() int {
+	return len(r.items)
+}
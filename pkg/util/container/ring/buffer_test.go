@@ -92,3 +92,54 @@ func mainTest(t *testing.T, bufferSize int) {
 	// give consumers time to terminate
 	time.Sleep(10 * time.Millisecond)
 }
+
+// Ensure high priority items preempt normal items already queued
+func TestPriorityBuffer(t *testing.T) {
+	p := NewPriorityBuffer(100)
+	consumed := make(chan []byte, 10)
+	release := make(chan struct{})
+	consumeFunc := func(items [][]byte, w io.WriteCloser) bool {
+		<-release
+		for _, item := range items {
+			consumed <- item
+		}
+		return true
+	}
+
+	p.Put([]byte{1}, false)
+	p.Put([]byte{2}, false)
+	NewPriorityConsumer(p, consumeFunc, nil)
+	// The consumer is now stuck on the first normal item
+	time.Sleep(10 * time.Millisecond)
+	p.Put([]byte{3}, true)
+
+	release <- struct{}{}
+	release <- struct{}{}
+	release <- struct{}{}
+	for _, expected := range []byte{1, 3, 2} {
+		if item := <-consumed; item[0] != expected {
+			t.Fatalf("expecting item %d, got %d", expected, item[0])
+		}
+	}
+
+	p.Close()
+}
+
+// Ensure the items waiting to be consumed are counted, up to the capacity
+func TestBufferLen(t *testing.T) {
+	r := NewBuffer(2)
+	if r.Len() != 0 || r.Cap() != 2 {
+		t.Fatalf("expecting an empty buffer of 2 items, got %d/%d", r.Len(), r.Cap())
+	}
+
+	r.Put([]byte{1})
+	r.Put([]byte{2})
+	r.Put([]byte{3})
+	if r.Len() != 2 {
+		t.Fatalf("expecting 2 items, got %d", r.Len())
+	}
+
+	if items, _ := r.GetAll(); len(items) != 2 || r.Len() != 0 {
+		t.Fatalf("expecting the buffer to be drained, %d items left", r.Len())
+	}
+}
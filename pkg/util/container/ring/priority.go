@@ -0,0 +1,135 @@
+package ring
+
+import (
+	"io"
+	"sync"
+)
+
+// PriorityBuffer is a two-tier ring buffer. Items on the high tier always
+// get consumed before items on the normal tier, so that urgent data is not
+// delayed behind a burst of bulk data.
+type PriorityBuffer struct {
+	mu       *sync.Mutex
+	notEmpty *sync.Cond
+	high     *Buffer
+	normal   *Buffer
+}
+
+// NewPriorityBuffer returns an initialized PriorityBuffer, where each tier
+// can hold up to length items.
+func NewPriorityBuffer(length int) *PriorityBuffer {
+	// Both tiers share the same lock and condition, so that the consumer
+	// can wait on either of them
+	m := &sync.Mutex{}
+	cv := sync.NewCond(m)
+	newTier := func() *Buffer {
+		return &Buffer{
+			items:      make([][]byte, length, length),
+			notEmpty:   cv,
+			notFull:    sync.NewCond(m),
+			mu:         m,
+			writeIndex: -1,
+		}
+	}
+
+	return &PriorityBuffer{
+		mu:       m,
+		notEmpty: cv,
+		high:     newTier(),
+		normal:   newTier(),
+	}
+}
+
+// Put an item on the high tier if high is true, on the normal tier
+// otherwise.
+func (p *PriorityBuffer) Put(item []byte, high bool) {
+	if high {
+		p.high.Put(item)
+		return
+	}
+
+	p.normal.Put(item)
+}
+
+// Close both tiers.
+func (p *PriorityBuffer) Close() {
+	p.high.Close()
+	p.normal.Close()
+}
+
+// Closed returns true if the buffer was closed.
+func (p *PriorityBuffer) Closed() bool {
+	return p.high.Closed()
+}
+
+// Len returns the amount of items waiting to be consumed, on both tiers.
+func (p *PriorityBuffer) Len() int {
+	return p.high.Len() + p.normal.Len()
+}
+
+// Cap returns the capacity of both tiers.
+func (p *PriorityBuffer) Cap() int {
+	return p.high.Cap() + p.normal.Cap()
+}
+
+// take empties both tiers. If wait is true, it blocks until either tier has
+// items, or the buffer gets closed.
+func (p *PriorityBuffer) take(wait bool) ([][]byte, [][]byte, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for wait && p.high.writeIndex < 0 && p.normal.writeIndex < 0 && !p.high.close {
+		p.notEmpty.Wait()
+	}
+
+	return p.high.drain(), p.normal.drain(), p.high.close
+}
+
+// drain removes all items from the buffer. The caller must hold the lock.
+func (r *Buffer) drain() [][]byte {
+	items := make([][]byte, 0)
+	for i, itemPtr := range r.items {
+		if itemPtr == nil {
+			break
+		}
+		items = append(items, itemPtr)
+		r.items[i] = nil
+	}
+	r.writeIndex = -1
+	return items
+}
+
+// NewPriorityConsumer starts consuming a PriorityBuffer. Normal items are
+// consumed one at a time, and the high tier is checked in between, so that
+// high priority items preempt the normal ones already retrieved.
+func NewPriorityConsumer(p *PriorityBuffer, callback func(items [][]byte, w io.WriteCloser) bool, w io.WriteCloser) {
+	go func() {
+		defer p.Close()
+
+		limit := len(p.normal.items)
+		pending := make([][]byte, 0)
+		for {
+			high, normal, closed := p.take(len(pending) == 0)
+			if len(high) > 0 && !callback(high, w) {
+				return
+			}
+
+			pending = append(pending, normal...)
+			// Honour the capacity of the normal tier, discarding the oldest
+			// items if the consumer can not keep up
+			if len(pending) > limit {
+				pending = pending[len(pending)-limit:]
+			}
+
+			if len(pending) > 0 {
+				if !callback(pending[:1], w) {
+					return
+				}
+				pending = pending[1:]
+			}
+
+			if closed {
+				return
+			}
+		}
+	}()
+}
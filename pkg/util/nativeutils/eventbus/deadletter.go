@@ -0,0 +1,98 @@
+package eventbus
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// deadLetterCapacity is the amount of dead letters kept in memory. Older
+// entries are overwritten.
+const deadLetterCapacity = 100
+
+// maxStackSize bounds the stack trace captured for each dead letter
+const maxStackSize = 4096
+
+// DeadLetter describes a message which was published on a topic without any
+// listener, and got therefore lost
+type DeadLetter struct {
+	Topic topics.Topic
+	Size  int
+	Time  time.Time
+	// Stack of the goroutine which published the message
+	Stack string
+}
+
+// deadLetterBox records a sample of the unroutable messages
+type deadLetterBox struct {
+	lock sync.Mutex
+	// sampleRate is the ratio of unroutable messages which get recorded.
+	// 0 disables the recording
+	sampleRate uint32
+	count      uint32
+	letters    []DeadLetter
+	next       int
+}
+
+func newDeadLetterBox() *deadLetterBox {
+	return &deadLetterBox{
+		letters: make([]DeadLetter, 0, deadLetterCapacity),
+	}
+}
+
+// EnableDeadLetters makes the EventBus record one every sampleRate messages
+// published on topics without listeners, along with the stack of the
+// publisher. Capturing the stack is expensive, so high rates should only be
+// used when debugging. A sampleRate of 0 disables the recording.
+func (bus *EventBus) EnableDeadLetters(sampleRate uint32) {
+	bus.deadLetters.lock.Lock()
+	defer bus.deadLetters.lock.Unlock()
+	bus.deadLetters.sampleRate = sampleRate
+	bus.deadLetters.count = 0
+}
+
+// DeadLetters returns the recorded dead letters, oldest first
+func (bus *EventBus) DeadLetters() []DeadLetter {
+	return bus.deadLetters.dump()
+}
+
+func (d *deadLetterBox) record(topic topics.Topic, size int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.sampleRate == 0 {
+		return
+	}
+
+	d.count++
+	if d.count < d.sampleRate {
+		return
+	}
+	d.count = 0
+
+	stack := make([]byte, maxStackSize)
+	stack = stack[:runtime.Stack(stack, false)]
+	letter := DeadLetter{
+		Topic: topic,
+		Size:  size,
+		Time:  time.Now(),
+		Stack: string(stack),
+	}
+
+	if len(d.letters) < deadLetterCapacity {
+		d.letters = append(d.letters, letter)
+		return
+	}
+
+	d.letters[d.next] = letter
+	d.next = (d.next + 1) % deadLetterCapacity
+}
+
+func (d *deadLetterBox) dump() []DeadLetter {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	letters := make([]DeadLetter, 0, len(d.letters))
+	letters = append(letters, d.letters[d.next:]...)
+	return append(letters, d.letters[:d.next]...)
+}
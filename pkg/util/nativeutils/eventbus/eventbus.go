@@ -26,16 +26,36 @@ type (
 		busLock         sync.RWMutex
 		listeners       *listenerMap
 		defaultListener *multiListener
+		matchers        *matcherList
+		deadLetters     *deadLetterBox
+		metrics         *busMetrics
+		ordered         *orderedTopics
+		journals        *journals
+		validators      *validators
+
+		// closeLock guards closed, and makes sure no delivery starts
+		// after Close begins waiting on inflight
+		closeLock sync.Mutex
+		closed    bool
+		inflight  sync.WaitGroup
 	}
 )
 
 // New returns new EventBus with empty listeners.
 func New() *EventBus {
-	return &EventBus{
+	bus := &EventBus{
 		//ProcessorRegistry: NewSafeProcessorRegistry(),
 
 		busLock:         sync.RWMutex{},
 		listeners:       newListenerMap(),
 		defaultListener: newMultiListener(),
+		matchers:        newMatcherList(),
+		deadLetters:     newDeadLetterBox(),
+		metrics:         newBusMetrics(),
+		journals:        newJournals(),
+		validators:      newValidators(),
 	}
+
+	bus.ordered = newOrderedTopics(&bus.inflight)
+	return bus
 }
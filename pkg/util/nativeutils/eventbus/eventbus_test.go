@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 	crypto "github.com/dusk-network/dusk-crypto/hash"
 	"github.com/stretchr/testify/assert"
 )
@@ -177,3 +178,374 @@ func (m *mockWriteCloser) Write(data []byte) (int, error) {
 func (m *mockWriteCloser) Close() error {
 	return nil
 }
+
+//*********************
+// QUEUE LISTENER TESTS
+//*********************
+func TestQueueListenerDropNew(t *testing.T) {
+	l := NewQueueListener(QueueConfig{Size: 2, Policy: DropNew})
+	for _, s := range []string{"a", "b", "c"} {
+		_ = l.Notify(*bytes.NewBufferString(s))
+	}
+
+	assert.Equal(t, uint64(1), l.Dropped())
+	assert.Equal(t, "a", (<-l.Chan()).String())
+	assert.Equal(t, "b", (<-l.Chan()).String())
+}
+
+func TestQueueListenerDropOldest(t *testing.T) {
+	l := NewQueueListener(QueueConfig{Size: 2, Policy: DropOldest})
+	assert.NoError(t, l.Notify(*bytes.NewBufferString("a")))
+	assert.NoError(t, l.Notify(*bytes.NewBufferString("b")))
+	assert.Equal(t, ErrQueueFull, l.Notify(*bytes.NewBufferString("c")))
+
+	assert.Equal(t, uint64(1), l.Dropped())
+	assert.Equal(t, "b", (<-l.Chan()).String())
+	assert.Equal(t, "c", (<-l.Chan()).String())
+}
+
+func TestQueueListenerBlock(t *testing.T) {
+	eb := New()
+	l := NewQueueListener(QueueConfig{Size: 1, Policy: Block})
+	id := eb.Subscribe(topics.Test, l)
+
+	eb.Publish(topics.Test, bytes.NewBufferString("a"))
+	published := make(chan struct{})
+	go func() {
+		eb.Publish(topics.Test, bytes.NewBufferString("b"))
+		close(published)
+	}()
+
+	select {
+	case <-published:
+		assert.FailNow(t, "publisher should block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Equal(t, "a", (<-l.Chan()).String())
+	<-published
+	assert.Equal(t, "b", (<-l.Chan()).String())
+	assert.Equal(t, uint64(0), l.Dropped())
+
+	// Unsubscribing releases blocked publishers
+	eb.Publish(topics.Test, bytes.NewBufferString("c"))
+	eb.Unsubscribe(topics.Test, id)
+	assert.Error(t, l.Notify(*bytes.NewBufferString("d")))
+}
+
+//*************************
+// MATCHING SUBSCRIBER TESTS
+//*************************
+func TestSubscribeMulti(t *testing.T) {
+	eb := New()
+	msgChan := make(chan bytes.Buffer, 10)
+	id := eb.SubscribeMulti(NewChanListener(msgChan), topics.Test, topics.Reduction)
+
+	eb.Publish(topics.Test, bytes.NewBufferString("pluto"))
+	eb.Publish(topics.Tx, bytes.NewBufferString("ignored"))
+	eb.Publish(topics.Reduction, bytes.NewBufferString("paperino"))
+
+	for _, expected := range []struct {
+		topic topics.Topic
+		msg   string
+	}{{topics.Test, "pluto"}, {topics.Reduction, "paperino"}} {
+		m := <-msgChan
+		topic, err := topics.Extract(&m)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.topic, topic)
+		assert.Equal(t, expected.msg, m.String())
+	}
+
+	eb.UnsubscribeMatching(id)
+	eb.Publish(topics.Test, bytes.NewBufferString("pluto"))
+	select {
+	case <-msgChan:
+		assert.FailNow(t, "We should have not received message")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSubscribeMatching(t *testing.T) {
+	eb := New()
+	msgChan := make(chan bytes.Buffer, 10)
+	eb.SubscribeMatching(topics.IsConsensus, NewChanListener(msgChan))
+
+	eb.Publish(topics.Tx, bytes.NewBufferString("ignored"))
+	eb.Publish(topics.Agreement, bytes.NewBufferString("pluto"))
+
+	m := <-msgChan
+	assert.Equal(t, byte(topics.Agreement), m.Bytes()[0])
+	assert.Equal(t, 0, len(msgChan))
+}
+
+//*******************
+// DEAD LETTER TESTS
+//*******************
+func TestDeadLetters(t *testing.T) {
+	eb := New()
+	// Nothing gets recorded by default
+	eb.Publish(topics.Test, bytes.NewBufferString("lost"))
+	assert.Empty(t, eb.DeadLetters())
+
+	eb.EnableDeadLetters(2)
+	for i := 0; i < 4; i++ {
+		eb.Publish(topics.Test, bytes.NewBufferString("lost"))
+	}
+
+	letters := eb.DeadLetters()
+	assert.Equal(t, 2, len(letters))
+	assert.Equal(t, topics.Test, letters[0].Topic)
+	assert.Equal(t, 4, letters[0].Size)
+	assert.Contains(t, letters[0].Stack, "TestDeadLetters")
+
+	// Routed messages are not dead letters
+	myChan := make(chan bytes.Buffer, 10)
+	eb.Subscribe(topics.Test, NewChanListener(myChan))
+	eb.Publish(topics.Test, bytes.NewBufferString("found"))
+	eb.Publish(topics.Test, bytes.NewBufferString("found"))
+	assert.Equal(t, 2, len(eb.DeadLetters()))
+}
+
+//*******************
+// TYPED API TESTS
+//*******************
+type testMessage struct {
+	value string
+}
+
+func (m *testMessage) Encode(b *bytes.Buffer) error {
+	if m.value == "" {
+		return errors.New("empty message")
+	}
+
+	_, err := b.WriteString(m.value)
+	return err
+}
+
+func (m *testMessage) Decode(b *bytes.Buffer) error {
+	m.value = b.String()
+	return nil
+}
+
+func TestTypedAPI(t *testing.T) {
+	eb := New()
+	msgChan := make(chan *testMessage, 1)
+	SubscribeMessage(eb, topics.Test, func() Unmarshaler {
+		return new(testMessage)
+	}, func(m Unmarshaler) error {
+		msgChan <- m.(*testMessage)
+		return nil
+	})
+
+	assert.NoError(t, PublishMessage(eb, topics.Test, &testMessage{"pluto"}))
+	assert.Equal(t, "pluto", (<-msgChan).value)
+
+	// Encoding errors are returned to the publisher
+	assert.Error(t, PublishMessage(eb, topics.Test, &testMessage{}))
+}
+
+//*******************
+// METRICS TESTS
+//*******************
+func TestMetrics(t *testing.T) {
+	eb := New()
+	eb.Subscribe(topics.Test, NewChanListener(make(chan bytes.Buffer, 4)))
+	eb.SubscribeMulti(NewChanListener(make(chan bytes.Buffer, 4)), topics.Test)
+
+	for i := 0; i < 3; i++ {
+		eb.Publish(topics.Test, bytes.NewBufferString("pluto"))
+	}
+
+	m := eb.Metrics()
+	assert.Equal(t, 1, len(m))
+	assert.Equal(t, uint64(3), m[topics.Test].Published)
+	assert.Equal(t, uint64(6), m[topics.Test].Delivered)
+
+	// Both listeners are at 75% of their capacity now
+	assert.Equal(t, 2, len(eb.metrics.slow))
+}
+
+//*******************
+// ORDERING TESTS
+//*******************
+func TestOrderedDelivery(t *testing.T) {
+	eb := New()
+	eb.SetOrdered(topics.Test)
+	eb.AddDefaultTopic(topics.Test)
+
+	msgChan := make(chan bytes.Buffer, 100)
+	eb.SubscribeDefault(NewChanListener(msgChan))
+
+	for i := byte(0); i < 100; i++ {
+		eb.Publish(topics.Test, bytes.NewBuffer([]byte{i}))
+	}
+
+	for i := byte(0); i < 100; i++ {
+		m := <-msgChan
+		// First byte is the topic
+		assert.Equal(t, i, m.Bytes()[1])
+	}
+}
+
+//*******************
+// JOURNAL TESTS
+//*******************
+func TestJournalReplay(t *testing.T) {
+	eb := New()
+	assert.Nil(t, eb.Replay(topics.Test, time.Time{}))
+
+	eb.EnableJournal(topics.Test, 2)
+	eb.Publish(topics.Test, bytes.NewBufferString("pippo"))
+	checkpoint := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	eb.Publish(topics.Test, bytes.NewBufferString("pluto"))
+	eb.Publish(topics.Test, bytes.NewBufferString("paperino"))
+
+	// Retention is 2, so the first message is gone
+	msgs := eb.Replay(topics.Test, time.Time{})
+	assert.Equal(t, 2, len(msgs))
+	assert.Equal(t, "pluto", msgs[0].String())
+	assert.Equal(t, "paperino", msgs[1].String())
+
+	// Replayed messages are copies
+	msgs[0].Reset()
+	msgs = eb.Replay(topics.Test, checkpoint)
+	assert.Equal(t, "pluto", msgs[0].String())
+}
+
+//*******************
+// LIFECYCLE TESTS
+//*******************
+func TestClose(t *testing.T) {
+	eb, myChan, _ := newEB(t)
+	eb.SetOrdered(topics.Test)
+	assert.NoError(t, eb.Close())
+
+	eb.Publish(topics.Test, bytes.NewBufferString("whatever"))
+	select {
+	case <-myChan:
+		assert.FailNow(t, "We should have not received message")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Empty(t, eb.listeners.Load(topics.Test))
+	// Closing twice is harmless
+	assert.NoError(t, eb.Close())
+}
+
+func TestCloseWaitsForDeliveries(t *testing.T) {
+	eb := New()
+	release := make(chan struct{})
+	delivered := make(chan struct{}, 1)
+	eb.Subscribe(topics.Test, NewCallbackListener(func(bytes.Buffer) error {
+		<-release
+		delivered <- struct{}{}
+		return nil
+	}))
+
+	go eb.Publish(topics.Test, bytes.NewBufferString("pluto"))
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan error)
+	go func() {
+		closed <- eb.Close()
+	}()
+
+	select {
+	case <-closed:
+		assert.FailNow(t, "Close should wait for the callback")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-closed)
+	assert.Equal(t, 1, len(delivered))
+}
+
+//*******************
+// VALIDATOR TESTS
+//*******************
+func TestValidator(t *testing.T) {
+	eb, myChan, _ := newEB(t)
+	errEmpty := errors.New("empty message")
+	eb.RegisterValidator(topics.Test, func(m bytes.Buffer) error {
+		if m.Len() == 0 {
+			return errEmpty
+		}
+
+		// Reading from the message should not affect the listeners
+		m.Reset()
+		return nil
+	})
+
+	assert.Equal(t, errEmpty, eb.Publish(topics.Test, new(bytes.Buffer)))
+	assert.NoError(t, eb.Publish(topics.Test, bytes.NewBufferString("pluto")))
+
+	m := <-myChan
+	assert.Equal(t, "pluto", m.String())
+	assert.Equal(t, 0, len(myChan))
+}
+
+//*******************
+// TRACING TESTS
+//*******************
+func TestPublishTraced(t *testing.T) {
+	eb := New()
+	idChan := make(chan tracing.ID, 2)
+	eb.Subscribe(topics.Test, NewTracedCallbackListener(func(m bytes.Buffer, id tracing.ID) error {
+		idChan <- id
+		return nil
+	}))
+
+	// Plain listeners keep working
+	plainChan := make(chan bytes.Buffer, 1)
+	eb.Subscribe(topics.Test, NewChanListener(plainChan))
+
+	id := tracing.NewID()
+	assert.NoError(t, eb.PublishTraced(topics.Test, bytes.NewBufferString("pluto"), id))
+	assert.Equal(t, id, <-idChan)
+	assert.Equal(t, "pluto", (<-plainChan).String())
+
+	// Untraced messages carry no ID
+	eb.Publish(topics.Test, bytes.NewBufferString("pluto"))
+	assert.Equal(t, tracing.None, <-idChan)
+}
+
+func TestDefaultListenerTraced(t *testing.T) {
+	eb := New()
+	idChan := make(chan tracing.ID, 2)
+	eb.AddDefaultTopic(topics.Test)
+	eb.AddDefaultTopic(topics.Tx)
+	eb.SetOrdered(topics.Tx)
+	eb.SubscribeDefault(NewTracedCallbackListener(func(m bytes.Buffer, id tracing.ID) error {
+		idChan <- id
+		return nil
+	}))
+
+	// The ID reaches the default listener, whether the topic is ordered or not
+	for _, topic := range []topics.Topic{topics.Test, topics.Tx} {
+		id := tracing.NewID()
+		assert.NoError(t, eb.PublishTraced(topic, bytes.NewBufferString("pluto"), id))
+		assert.Equal(t, id, <-idChan)
+	}
+}
+
+func TestDefaultPredicate(t *testing.T) {
+	eb := New()
+	msgChan := make(chan bytes.Buffer, 10)
+	eb.SubscribeDefault(NewChanListener(msgChan))
+	eb.AddDefaultPredicate(eb.Unhandled())
+
+	// Test has a listener, so only Tx falls through to the default listener
+	eb.Subscribe(topics.Test, NewChanListener(make(chan bytes.Buffer, 10)))
+	eb.Publish(topics.Test, bytes.NewBufferString("handled"))
+	eb.Publish(topics.Tx, bytes.NewBufferString("unhandled"))
+
+	m := <-msgChan
+	assert.Equal(t, byte(topics.Tx), m.Bytes()[0])
+	select {
+	case <-msgChan:
+		assert.FailNow(t, "handled topics should not reach the default listener")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
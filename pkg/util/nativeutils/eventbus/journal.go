@@ -0,0 +1,96 @@
+package eventbus
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+type journalEntry struct {
+	time    time.Time
+	payload []byte
+}
+
+// journal retains the most recent messages published on a topic
+type journal struct {
+	lock    sync.RWMutex
+	entries []journalEntry
+	next    int
+}
+
+func newJournal(retention int) *journal {
+	return &journal{entries: make([]journalEntry, 0, retention)}
+}
+
+func (j *journal) append(m bytes.Buffer) {
+	// The buffer is shared with the listeners, so the journal keeps a copy
+	entry := journalEntry{
+		time:    time.Now(),
+		payload: append([]byte(nil), m.Bytes()...),
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if len(j.entries) < cap(j.entries) {
+		j.entries = append(j.entries, entry)
+		return
+	}
+
+	j.entries[j.next] = entry
+	j.next = (j.next + 1) % len(j.entries)
+}
+
+func (j *journal) since(t time.Time) []bytes.Buffer {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	res := make([]bytes.Buffer, 0, len(j.entries))
+	for i := 0; i < len(j.entries); i++ {
+		entry := j.entries[(j.next+i)%len(j.entries)]
+		if entry.time.After(t) {
+			res = append(res, *bytes.NewBuffer(append([]byte(nil), entry.payload...)))
+		}
+	}
+
+	return res
+}
+
+type journals struct {
+	lock     sync.RWMutex
+	journals map[topics.Topic]*journal
+}
+
+func newJournals() *journals {
+	return &journals{journals: make(map[topics.Topic]*journal)}
+}
+
+func (j *journals) get(topic topics.Topic) *journal {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	return j.journals[topic]
+}
+
+// EnableJournal makes the EventBus retain the last retention messages
+// published on topic, so that they can be replayed to components which
+// subscribe late.
+func (bus *EventBus) EnableJournal(topic topics.Topic, retention int) {
+	if retention <= 0 {
+		return
+	}
+
+	bus.journals.lock.Lock()
+	defer bus.journals.lock.Unlock()
+	bus.journals.journals[topic] = newJournal(retention)
+}
+
+// Replay returns the journaled messages published on topic after the given
+// time, oldest first. It returns nil if the topic is not journaled.
+func (bus *EventBus) Replay(topic topics.Topic, since time.Time) []bytes.Buffer {
+	j := bus.journals.get(topic)
+	if j == nil {
+		return nil
+	}
+
+	return j.since(since)
+}
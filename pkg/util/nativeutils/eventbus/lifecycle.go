@@ -0,0 +1,62 @@
+package eventbus
+
+import (
+	"errors"
+	"time"
+)
+
+// closeTimeout is the time Close waits for in-flight deliveries
+var closeTimeout = 5 * time.Second
+
+// ErrCloseTimeout is returned by Close when some deliveries did not
+// complete in time
+var ErrCloseTimeout = errors.New("timeout waiting for in-flight deliveries")
+
+// enter registers an in-flight delivery. It returns false if the EventBus is
+// closed, in which case nothing should be delivered.
+func (bus *EventBus) enter() bool {
+	bus.closeLock.Lock()
+	defer bus.closeLock.Unlock()
+	if bus.closed {
+		return false
+	}
+
+	bus.inflight.Add(1)
+	return true
+}
+
+// Close shuts the EventBus down. Further messages are discarded, in-flight
+// deliveries are given some time to complete, and all listeners get
+// unsubscribed and closed, which stops the consumers of the StreamListeners.
+// It returns ErrCloseTimeout if some delivery is still running after the
+// deadline, in which case the listeners are closed anyway.
+func (bus *EventBus) Close() error {
+	bus.closeLock.Lock()
+	if bus.closed {
+		bus.closeLock.Unlock()
+		return nil
+	}
+	bus.closed = true
+	bus.closeLock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		bus.inflight.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+		bus.ordered.close()
+	case <-time.After(closeTimeout):
+		// The ordered queues can not be closed safely, as publishers might
+		// still be writing to them
+		err = ErrCloseTimeout
+	}
+
+	bus.listeners.clear()
+	bus.matchers.clear()
+	bus.defaultListener.clear()
+	return err
+}
@@ -6,10 +6,12 @@ import (
 	"io"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/container/ring"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/hashset"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -21,6 +23,52 @@ type Listener interface {
 	Close()
 }
 
+// TracedListener is implemented by Listeners interested in the correlation
+// ID of the messages they receive
+type TracedListener interface {
+	Listener
+	NotifyTraced(bytes.Buffer, tracing.ID) error
+}
+
+func notify(l Listener, m bytes.Buffer, id tracing.ID) error {
+	// Listeners are stored wrapped with their ID, which hides NotifyTraced
+	if h, ok := l.(idListener); ok {
+		l = h.Listener
+	}
+
+	if t, ok := l.(TracedListener); ok {
+		return t.NotifyTraced(m, id)
+	}
+
+	return l.Notify(m)
+}
+
+// TracedCallbackListener subscribes using callbacks which receive the
+// correlation ID of the message, if any
+type TracedCallbackListener struct {
+	callback func(bytes.Buffer, tracing.ID) error
+}
+
+// NewTracedCallbackListener creates a callback based dispatcher, which
+// passes the correlation ID along with the message
+func NewTracedCallbackListener(callback func(bytes.Buffer, tracing.ID) error) Listener {
+	return &TracedCallbackListener{callback}
+}
+
+// Notify a message without correlation ID
+func (c *TracedCallbackListener) Notify(m bytes.Buffer) error {
+	return c.callback(m, tracing.None)
+}
+
+// NotifyTraced passes the message and its correlation ID to the callback
+func (c *TracedCallbackListener) NotifyTraced(m bytes.Buffer, id tracing.ID) error {
+	return c.callback(m, id)
+}
+
+// Close as part of the Listener method
+func (c *TracedCallbackListener) Close() {
+}
+
 // CallbackListener subscribes using callbacks
 type CallbackListener struct {
 	callback func(bytes.Buffer) error
@@ -75,6 +123,33 @@ func (s *StreamListener) Close() {
 	}
 }
 
+// PriorityStreamListener is a StreamListener which splits messages over two
+// tiers, so that high priority messages overtake the rest
+type PriorityStreamListener struct {
+	ringbuffer *ring.PriorityBuffer
+	isHigh     func([]byte) bool
+}
+
+// NewPriorityStreamListener creates a new PriorityStreamListener. Messages
+// for which isHigh returns true are written to w ahead of the others.
+func NewPriorityStreamListener(w io.WriteCloser, isHigh func([]byte) bool) Listener {
+	ringBuf := ring.NewPriorityBuffer(ringBufferLength)
+	ring.NewPriorityConsumer(ringBuf, Consume, w)
+	return &PriorityStreamListener{ringBuf, isHigh}
+}
+
+// Notify puts a message on the tier of the ringbuffer it belongs to
+func (s *PriorityStreamListener) Notify(m bytes.Buffer) error {
+	b := m.Bytes()
+	s.ringbuffer.Put(b, s.isHigh(b))
+	return nil
+}
+
+// Close the internal ringbuffer
+func (s *PriorityStreamListener) Close() {
+	s.ringbuffer.Close()
+}
+
 // Consume an item by writing it to the specified WriteCloser. This is used in the StreamListener creation
 func Consume(items [][]byte, w io.WriteCloser) bool {
 	for _, data := range items {
@@ -112,10 +187,120 @@ func (c *ChanListener) Notify(m bytes.Buffer) error {
 func (c *ChanListener) Close() {
 }
 
+// OverflowPolicy determines what a QueueListener does with a message
+// published while its queue is full
+type OverflowPolicy uint8
+
+const (
+	// DropNew discards the incoming message, keeping the queue untouched
+	DropNew OverflowPolicy = iota
+	// DropOldest evicts the oldest queued message to make room for the
+	// incoming one
+	DropOldest
+	// Block stalls the publisher until the subscriber makes room. It should
+	// only be used by subscribers which can not afford to lose events, and
+	// that drain their queue promptly
+	Block
+)
+
+// ErrQueueFull is returned by a QueueListener whenever a message is dropped
+var ErrQueueFull = errors.New("listener queue is full")
+
+// QueueConfig specifies the size and the overflow policy of the queue of a
+// QueueListener
+type QueueConfig struct {
+	Size   int
+	Policy OverflowPolicy
+}
+
+// QueueListener dispatches messages through a bounded queue, handling
+// overflows according to the configured policy. Unlike the ChanListener, it
+// keeps track of the messages it dropped, so that losses can be detected.
+type QueueListener struct {
+	lock    sync.Mutex
+	queue   chan bytes.Buffer
+	policy  OverflowPolicy
+	dropped uint64
+	quit    chan struct{}
+	once    sync.Once
+}
+
+// NewQueueListener creates a QueueListener with the given configuration.
+// Messages can be consumed through the channel returned by Chan.
+func NewQueueListener(cfg QueueConfig) *QueueListener {
+	return &QueueListener{
+		queue:  make(chan bytes.Buffer, cfg.Size),
+		policy: cfg.Policy,
+		quit:   make(chan struct{}),
+	}
+}
+
+// Chan returns the channel messages are delivered on
+func (q *QueueListener) Chan() <-chan bytes.Buffer {
+	return q.queue
+}
+
+// Dropped returns the amount of messages discarded so far
+func (q *QueueListener) Dropped() uint64 {
+	return atomic.LoadUint64(&q.dropped)
+}
+
+// Notify queues a message, applying the overflow policy if the queue is full
+func (q *QueueListener) Notify(m bytes.Buffer) error {
+	switch q.policy {
+	case Block:
+		select {
+		case q.queue <- m:
+			return nil
+		case <-q.quit:
+			return errors.New("listener is closed")
+		}
+	case DropOldest:
+		// The lock prevents concurrent publishers from evicting each other's
+		// messages
+		q.lock.Lock()
+		defer q.lock.Unlock()
+		var err error
+		for {
+			select {
+			case q.queue <- m:
+				return err
+			default:
+			}
+
+			select {
+			case <-q.queue:
+				atomic.AddUint64(&q.dropped, 1)
+				err = ErrQueueFull
+			default:
+				// The consumer made room in the meantime, try again
+			}
+		}
+	default:
+		select {
+		case q.queue <- m:
+			return nil
+		default:
+			atomic.AddUint64(&q.dropped, 1)
+			return ErrQueueFull
+		}
+	}
+}
+
+// Close releases publishers blocked on a full queue. The queue itself is
+// left open, as publishers might still be holding a reference to the
+// listener.
+func (q *QueueListener) Close() {
+	q.once.Do(func() {
+		close(q.quit)
+	})
+}
+
 type multiListener struct {
 	sync.RWMutex
 	*hashset.Set
 	dispatchers []idListener
+	predicates  []TopicMatcher
 }
 
 func newMultiListener() *multiListener {
@@ -125,8 +310,34 @@ func newMultiListener() *multiListener {
 	}
 }
 
-func (m *multiListener) Notify(topic topics.Topic, r bytes.Buffer) {
+// accepts returns true if the topic was added explicitly, or matches any of
+// the predicates
+func (m *multiListener) accepts(topic topics.Topic) bool {
 	if m.Has([]byte{byte(topic)}) {
+		return true
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+	for _, match := range m.predicates {
+		if match(topic) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *multiListener) addPredicate(match TopicMatcher) {
+	m.Lock()
+	defer m.Unlock()
+	m.predicates = append(m.predicates, match)
+}
+
+// Notify dispatches the message, prepended with its topic, to all the
+// Listeners. The correlation ID is handed over to the TracedListeners.
+func (m *multiListener) Notify(topic topics.Topic, r bytes.Buffer, id tracing.ID) {
+	if m.accepts(topic) {
 		// creating a new Buffer carrying also the topic
 		tpcMsg := topic.ToBuffer()
 		if _, err := tpcMsg.ReadFrom(&r); err != nil {
@@ -136,7 +347,7 @@ func (m *multiListener) Notify(topic topics.Topic, r bytes.Buffer) {
 
 		m.RLock()
 		for _, dispatcher := range m.dispatchers {
-			dispatcher.Notify(tpcMsg)
+			_ = notify(dispatcher, tpcMsg, id)
 		}
 		m.RUnlock()
 	}
@@ -169,3 +380,14 @@ func (m *multiListener) Delete(id uint32) bool {
 	}
 	return false
 }
+
+// clear closes and removes all the dispatchers
+func (m *multiListener) clear() {
+	m.Lock()
+	defer m.Unlock()
+	for _, h := range m.dispatchers {
+		h.Close()
+	}
+
+	m.dispatchers = make([]idListener, 0)
+}
@@ -59,3 +59,82 @@ func (h *listenerMap) Delete(key topics.Topic, id uint32) bool {
 	h.lock.Unlock()
 	return found
 }
+
+// clear closes and removes all the listeners
+func (h *listenerMap) clear() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for _, listeners := range h.listeners {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+	}
+
+	h.listeners = make(map[topics.Topic][]idListener)
+}
+
+type matchListener struct {
+	idListener
+	match TopicMatcher
+}
+
+// matcherList holds the Listeners subscribed to multiple topics at once
+type matcherList struct {
+	lock      sync.RWMutex
+	listeners []matchListener
+}
+
+func newMatcherList() *matcherList {
+	return &matcherList{
+		listeners: make([]matchListener, 0),
+	}
+}
+
+// Store a Listener along with its TopicMatcher
+func (m *matcherList) Store(match TopicMatcher, value Listener) uint32 {
+	id := rand.Uint32()
+	m.lock.Lock()
+	m.listeners = append(m.listeners, matchListener{idListener{id, value}, match})
+	m.lock.Unlock()
+	return id
+}
+
+// Load the Listeners interested in a given topic
+func (m *matcherList) Load(topic topics.Topic) []idListener {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var listeners []idListener
+	for _, l := range m.listeners {
+		if l.match(topic) {
+			listeners = append(listeners, l.idListener)
+		}
+	}
+
+	return listeners
+}
+
+// Delete a Listener using the id returned by Store
+func (m *matcherList) Delete(id uint32) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for i, l := range m.listeners {
+		if l.id == id {
+			l.Close()
+			m.listeners = append(m.listeners[:i], m.listeners[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// clear closes and removes all the listeners
+func (m *matcherList) clear() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, l := range m.listeners {
+		l.Close()
+	}
+
+	m.listeners = make([]matchListener, 0)
+}
@@ -0,0 +1,155 @@
+package eventbus
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	lg "github.com/sirupsen/logrus"
+)
+
+// backlogThreshold is the fill ratio (in percent) of a listener queue above
+// which the listener is considered slow
+const backlogThreshold = 75
+
+// The statistics of all the buses are served on /metrics as well
+var (
+	publishedTotal = metrics.NewCounterVec("dusk_eventbus_published_total", "Messages published, by topic", "topic")
+	deliveredTotal = metrics.NewCounterVec("dusk_eventbus_delivered_total", "Notifications sent out to the listeners, by topic", "topic")
+)
+
+// backlogger is implemented by the Listeners which queue messages, and can
+// therefore fall behind
+type backlogger interface {
+	// backlog returns the amount of queued messages and the capacity of the
+	// queue
+	backlog() (int, int)
+}
+
+func (c *ChanListener) backlog() (int, int) {
+	return len(c.messageChannel), cap(c.messageChannel)
+}
+
+func (q *QueueListener) backlog() (int, int) {
+	return len(q.queue), cap(q.queue)
+}
+
+func (s *StreamListener) backlog() (int, int) {
+	if s.ringbuffer == nil {
+		return 0, 0
+	}
+	return s.ringbuffer.Len(), s.ringbuffer.Cap()
+}
+
+func (s *PriorityStreamListener) backlog() (int, int) {
+	return s.ringbuffer.Len(), s.ringbuffer.Cap()
+}
+
+// TopicMetrics holds the delivery statistics of a topic
+type TopicMetrics struct {
+	// Published is the amount of messages published on the topic
+	Published uint64
+	// Delivered is the total amount of notifications sent out, i.e. the
+	// sum of the fan-out of each message
+	Delivered uint64
+	// Latency is the cumulative time spent notifying the listeners
+	Latency time.Duration
+}
+
+// AverageLatency returns the mean time spent notifying the listeners of a
+// message
+func (m TopicMetrics) AverageLatency() time.Duration {
+	if m.Published == 0 {
+		return 0
+	}
+
+	return m.Latency / time.Duration(m.Published)
+}
+
+type topicCounters struct {
+	published uint64
+	delivered uint64
+	latency   int64
+}
+
+// busMetrics collects per-topic delivery statistics, and keeps track of the
+// listeners which fall behind
+type busMetrics struct {
+	counters [256]topicCounters
+
+	lock sync.Mutex
+	slow map[uint32]bool
+}
+
+func newBusMetrics() *busMetrics {
+	return &busMetrics{slow: make(map[uint32]bool)}
+}
+
+func (m *busMetrics) record(topic topics.Topic, fanout int, elapsed time.Duration) {
+	c := &m.counters[topic]
+	atomic.AddUint64(&c.published, 1)
+	atomic.AddUint64(&c.delivered, uint64(fanout))
+	atomic.AddInt64(&c.latency, int64(elapsed))
+
+	publishedTotal.With(topic.String()).Inc()
+	deliveredTotal.With(topic.String()).Add(uint64(fanout))
+}
+
+// checkBacklog warns once whenever a listener's queue fills up beyond the
+// threshold, and once more when it recovers.
+func (m *busMetrics) checkBacklog(topic topics.Topic, l idListener) {
+	b, ok := l.Listener.(backlogger)
+	if !ok {
+		return
+	}
+
+	size, capacity := b.backlog()
+	if capacity == 0 {
+		return
+	}
+
+	slow := size*100 >= capacity*backlogThreshold
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.slow[l.id] == slow {
+		return
+	}
+
+	entry := logEB.WithFields(lg.Fields{
+		"topic":    topic.String(),
+		"listener": l.id,
+		"backlog":  size,
+		"capacity": capacity,
+	})
+	if slow {
+		m.slow[l.id] = true
+		entry.Warnln("slow subscriber detected")
+		return
+	}
+
+	delete(m.slow, l.id)
+	entry.Infoln("subscriber caught up")
+}
+
+// Metrics returns the delivery statistics of all the topics which have seen
+// any traffic
+func (bus *EventBus) Metrics() map[topics.Topic]TopicMetrics {
+	res := make(map[topics.Topic]TopicMetrics)
+	for i := range bus.metrics.counters {
+		c := &bus.metrics.counters[i]
+		published := atomic.LoadUint64(&c.published)
+		if published == 0 {
+			continue
+		}
+
+		res[topics.Topic(i)] = TopicMetrics{
+			Published: published,
+			Delivered: atomic.LoadUint64(&c.delivered),
+			Latency:   time.Duration(atomic.LoadInt64(&c.latency)),
+		}
+	}
+
+	return res
+}
@@ -6,9 +6,29 @@ import (
 
 // Multicaster allows for a single Listener to listen to multiple topics
 type Multicaster interface {
-
 	AddDefaultTopic(topics.Topic)
+	AddDefaultPredicate(TopicMatcher)
 	SubscribeDefault(Listener) uint32
+
+	SubscribeMulti(Listener, ...topics.Topic) uint32
+	SubscribeMatching(TopicMatcher, Listener) uint32
+	UnsubscribeMatching(uint32)
+}
+
+// TopicMatcher selects the topics a Listener subscribed through
+// SubscribeMatching is interested in
+type TopicMatcher func(topics.Topic) bool
+
+// AnyOf returns a TopicMatcher accepting only the specified topics
+func AnyOf(tpcs ...topics.Topic) TopicMatcher {
+	var set [256]bool
+	for _, topic := range tpcs {
+		set[topic] = true
+	}
+
+	return func(topic topics.Topic) bool {
+		return set[topic]
+	}
 }
 
 // AddDefaultTopic adds a topic to the default multiListener
@@ -16,9 +36,53 @@ func (bus *EventBus) AddDefaultTopic(topic topics.Topic) {
 	bus.defaultListener.Add([]byte{byte(topic)})
 }
 
+// AddDefaultPredicate routes to the default multiListener all the topics
+// matching the predicate, in addition to the ones added explicitly. This
+// avoids maintaining a list of topics which drifts out of date as topics get
+// added.
+func (bus *EventBus) AddDefaultPredicate(match TopicMatcher) {
+	bus.defaultListener.addPredicate(match)
+}
+
+// Unhandled returns a TopicMatcher accepting the topics which, at the time
+// of publishing, have no listener subscribed through Subscribe or
+// SubscribeMatching.
+func (bus *EventBus) Unhandled() TopicMatcher {
+	return func(topic topics.Topic) bool {
+		return len(bus.listeners.Load(topic)) == 0 && len(bus.matchers.Load(topic)) == 0
+	}
+}
+
 // SubscribeDefault subscribes a Listener to the default multiListener.
 // This is normally useful for implementing a sub-dispatching mechanism
 // (i.e. bus of busses architecture)
 func (bus *EventBus) SubscribeDefault(listener Listener) uint32 {
 	return bus.defaultListener.Store(listener)
 }
+
+// UnsubscribeDefault removes a Listener subscribed through SubscribeDefault
+func (bus *EventBus) UnsubscribeDefault(id uint32) {
+	found := bus.defaultListener.Delete(id)
+	logEB.WithField("found", found).Traceln("unsubscribing default listener")
+}
+
+// SubscribeMulti subscribes a Listener to a set of topics. Like with the
+// default multiListener, each message is delivered with its topic prepended,
+// so that the Listener can tell them apart.
+func (bus *EventBus) SubscribeMulti(listener Listener, tpcs ...topics.Topic) uint32 {
+	return bus.SubscribeMatching(AnyOf(tpcs...), listener)
+}
+
+// SubscribeMatching subscribes a Listener to all the topics accepted by the
+// matcher (e.g. topics.IsConsensus). Messages are delivered with their topic
+// prepended.
+func (bus *EventBus) SubscribeMatching(match TopicMatcher, listener Listener) uint32 {
+	return bus.matchers.Store(match, listener)
+}
+
+// UnsubscribeMatching removes a Listener subscribed through SubscribeMulti or
+// SubscribeMatching.
+func (bus *EventBus) UnsubscribeMatching(id uint32) {
+	found := bus.matchers.Delete(id)
+	logEB.WithField("found", found).Traceln("unsubscribing matcher")
+}
@@ -0,0 +1,86 @@
+package eventbus
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
+)
+
+// orderedQueueLength is the amount of messages an ordered topic can buffer
+// for the default listener before publishers start blocking
+const orderedQueueLength = 1000
+
+// orderedDispatcher serializes the delivery of the messages of a topic
+type orderedDispatcher struct {
+	// lock makes concurrent publishers take turns, so that all listeners see
+	// the messages in the same order
+	lock sync.Mutex
+	// queue feeds the default listener from a single goroutine, rather than
+	// spawning one goroutine per message
+	queue chan tracedMessage
+}
+
+// tracedMessage is a message queued along with its correlation ID
+type tracedMessage struct {
+	m  bytes.Buffer
+	id tracing.ID
+}
+
+func newOrderedDispatcher(topic topics.Topic, l *multiListener, inflight *sync.WaitGroup) *orderedDispatcher {
+	d := &orderedDispatcher{queue: make(chan tracedMessage, orderedQueueLength)}
+	go func() {
+		for t := range d.queue {
+			l.Notify(topic, t.m, t.id)
+			inflight.Done()
+		}
+	}()
+
+	return d
+}
+
+type orderedTopics struct {
+	lock        sync.RWMutex
+	dispatchers map[topics.Topic]*orderedDispatcher
+	inflight    *sync.WaitGroup
+}
+
+func newOrderedTopics(inflight *sync.WaitGroup) *orderedTopics {
+	return &orderedTopics{
+		dispatchers: make(map[topics.Topic]*orderedDispatcher),
+		inflight:    inflight,
+	}
+}
+
+// close stops the dispatching goroutines. It must only be called once no
+// more messages can be published.
+func (o *orderedTopics) close() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	for topic, d := range o.dispatchers {
+		close(d.queue)
+		delete(o.dispatchers, topic)
+	}
+}
+
+func (o *orderedTopics) get(topic topics.Topic) *orderedDispatcher {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	return o.dispatchers[topic]
+}
+
+// SetOrdered guarantees that the messages published on topic are delivered
+// to every listener in the order they were published. Dispatch for the topic
+// gets serialized, while other topics are unaffected. It should be used for
+// topics like RoundUpdate, where observing an older message after a newer
+// one leads to inconsistent state.
+// Listeners of an ordered topic must not publish on the same topic from
+// within Notify, as that would deadlock.
+func (bus *EventBus) SetOrdered(topic topics.Topic) {
+	bus.ordered.lock.Lock()
+	defer bus.ordered.lock.Unlock()
+	if _, ok := bus.ordered.dispatchers[topic]; !ok {
+		bus.ordered.dispatchers[topic] = newOrderedDispatcher(topic, bus.defaultListener, bus.ordered.inflight)
+	}
+}
@@ -3,36 +3,108 @@ package eventbus
 import (
 	"bytes"
 	"fmt"
+	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
+	lg "github.com/sirupsen/logrus"
 )
 
 // Publisher publishes serialized messages on a specific topic
 type Publisher interface {
-	Publish(topics.Topic, *bytes.Buffer)
+	Publish(topics.Topic, *bytes.Buffer) error
 }
 
-// Publish executes callback defined for a topic.
-func (bus *EventBus) Publish(topic topics.Topic, messageBuffer *bytes.Buffer) {
+// TracedPublisher publishes messages along with their correlation ID
+type TracedPublisher interface {
+	PublishTraced(topics.Topic, *bytes.Buffer, tracing.ID) error
+}
+
+// Publish executes callback defined for a topic. An error is returned if the
+// message fails the validation of the topic, in which case it is not
+// delivered.
+func (bus *EventBus) Publish(topic topics.Topic, messageBuffer *bytes.Buffer) error {
 	if messageBuffer == nil {
 		err := fmt.Errorf("got a nil message on topic %s", topic)
 		logEB.WithField("topic", topic.String()).WithError(err).Errorln("preprocessor error")
-		return
+		return err
 	}
 
-	bus.publish(topic, *messageBuffer)
+	return bus.PublishTraced(topic, messageBuffer, tracing.None)
 }
 
-func (bus *EventBus) publish(topic topics.Topic, event bytes.Buffer) {
+// PublishTraced publishes a message carrying a correlation ID. The ID is
+// handed over to the listeners implementing TracedListener.
+func (bus *EventBus) PublishTraced(topic topics.Topic, messageBuffer *bytes.Buffer, id tracing.ID) error {
+	if messageBuffer == nil {
+		return fmt.Errorf("got a nil message on topic %s", topic)
+	}
+
+	if err := bus.validators.validate(topic, *messageBuffer); err != nil {
+		logEB.WithFields(lg.Fields{
+			"topic": topic.String(),
+			"trace": id.String(),
+		}).WithError(err).Debugln("message failed validation")
+		return err
+	}
+
+	span := tracing.StartSpan(id, "eventbus.publish")
+	span.SetAttribute("topic", topic.String())
+	bus.publish(topic, *messageBuffer, id)
+	span.Finish()
+	return nil
+}
+
+func (bus *EventBus) publish(topic topics.Topic, event bytes.Buffer, id tracing.ID) {
+	if !bus.enter() {
+		logEB.WithField("topic", topic.String()).Traceln("eventbus is closed, discarding message")
+		return
+	}
+	defer bus.inflight.Done()
+
+	if j := bus.journals.get(topic); j != nil {
+		j.append(event)
+	}
 
-	// first serve the default topic listeners as they are most likely to need more time to (pre-)process topics
-	go bus.defaultListener.Notify(topic, event)
+	// The default listener is served asynchronously, so the delivery gets
+	// accounted for separately
+	bus.inflight.Add(1)
+	if d := bus.ordered.get(topic); d != nil {
+		d.lock.Lock()
+		defer d.lock.Unlock()
+		d.queue <- tracedMessage{event, id}
+	} else {
+		// first serve the default topic listeners as they are most likely to need more time to (pre-)process topics
+		go func() {
+			bus.defaultListener.Notify(topic, event, id)
+			bus.inflight.Done()
+		}()
+	}
 
-	if listeners := bus.listeners.Load(topic); listeners != nil {
-		for _, listener := range listeners {
-			if err := listener.Notify(event); err != nil {
-				logEB.WithError(err).WithField("topic", topic).Warnln("listener failed to notify buffer")
-			}
+	listeners := bus.listeners.Load(topic)
+	matchers := bus.matchers.Load(topic)
+	if len(listeners) == 0 && len(matchers) == 0 && !bus.defaultListener.accepts(topic) {
+		bus.deadLetters.record(topic, event.Len())
+		return
+	}
+
+	start := time.Now()
+	for _, listener := range listeners {
+		if err := notify(listener, event, id); err != nil {
+			logEB.WithError(err).WithField("topic", topic).Warnln("listener failed to notify buffer")
 		}
+		bus.metrics.checkBacklog(topic, listener)
 	}
+
+	for _, listener := range matchers {
+		// Each listener gets its own copy, carrying the topic
+		tpcMsg := topic.ToBuffer()
+		_, _ = tpcMsg.Write(event.Bytes())
+		if err := notify(listener, tpcMsg, id); err != nil {
+			logEB.WithError(err).WithField("topic", topic).Warnln("listener failed to notify buffer")
+		}
+		bus.metrics.checkBacklog(topic, listener)
+	}
+
+	bus.metrics.record(topic, len(listeners)+len(matchers), time.Since(start))
 }
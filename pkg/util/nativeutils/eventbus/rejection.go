@@ -0,0 +1,56 @@
+package eventbus
+
+import (
+	"bytes"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// Rejection describes a message refused by a Validator. Rejections are
+// published on the RejectedMessage topic, so that the peer layer can hold
+// the sending peer accountable.
+type Rejection struct {
+	Topic  topics.Topic
+	Reason string
+	// Origin is the address of the peer the message was received from,
+	// if any
+	Origin string
+}
+
+// PublishRejection publishes r on the RejectedMessage topic
+func PublishRejection(p Publisher, r Rejection) {
+	if err := PublishMessage(p, topics.RejectedMessage, &r); err != nil {
+		logEB.WithError(err).Warnln("could not publish rejection")
+	}
+}
+
+// Encode the Rejection into b
+func (r *Rejection) Encode(b *bytes.Buffer) error {
+	if err := encoding.WriteUint8(b, uint8(r.Topic)); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteString(b, r.Reason); err != nil {
+		return err
+	}
+
+	return encoding.WriteString(b, r.Origin)
+}
+
+// Decode a Rejection from b
+func (r *Rejection) Decode(b *bytes.Buffer) error {
+	var topic uint8
+	if err := encoding.ReadUint8(b, &topic); err != nil {
+		return err
+	}
+	r.Topic = topics.Topic(topic)
+
+	var err error
+	if r.Reason, err = encoding.ReadString(b); err != nil {
+		return err
+	}
+
+	r.Origin, err = encoding.ReadString(b)
+	return err
+}
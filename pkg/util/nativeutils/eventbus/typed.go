@@ -0,0 +1,66 @@
+package eventbus
+
+import (
+	"bytes"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// The node is built with Go 1.13, which lacks type parameters. The helpers
+// below provide typed publishing and subscribing through the Marshaler and
+// Unmarshaler interfaces instead, so that collectors do not need to deal with
+// the raw buffers.
+
+// Marshaler is implemented by messages which can encode themselves
+type Marshaler interface {
+	Encode(*bytes.Buffer) error
+}
+
+// Unmarshaler is implemented by messages which can decode themselves
+type Unmarshaler interface {
+	Decode(*bytes.Buffer) error
+}
+
+// PublishMessage encodes a message and publishes it on the given topic. It
+// returns encoding and validation errors.
+func PublishMessage(p Publisher, topic topics.Topic, m Marshaler) error {
+	buf := new(bytes.Buffer)
+	if err := m.Encode(buf); err != nil {
+		return err
+	}
+
+	return p.Publish(topic, buf)
+}
+
+// TypedListener decodes incoming messages before handing them over to a
+// callback
+type TypedListener struct {
+	newMessage func() Unmarshaler
+	callback   func(Unmarshaler) error
+}
+
+// NewTypedListener creates a Listener which decodes each message into a
+// fresh instance obtained from newMessage, and calls back with it. Decoding
+// errors are returned to the EventBus, and the callback is skipped.
+func NewTypedListener(newMessage func() Unmarshaler, callback func(Unmarshaler) error) Listener {
+	return &TypedListener{newMessage, callback}
+}
+
+// Notify decodes the message and passes it to the callback
+func (t *TypedListener) Notify(m bytes.Buffer) error {
+	msg := t.newMessage()
+	if err := msg.Decode(&m); err != nil {
+		return err
+	}
+
+	return t.callback(msg)
+}
+
+// Close has no effect
+func (t *TypedListener) Close() {
+}
+
+// SubscribeMessage subscribes a TypedListener to a topic
+func SubscribeMessage(s Subscriber, topic topics.Topic, newMessage func() Unmarshaler, callback func(Unmarshaler) error) uint32 {
+	return s.Subscribe(topic, NewTypedListener(newMessage, callback))
+}
@@ -0,0 +1,49 @@
+package eventbus
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// Validator checks the payload of a message before it gets published
+type Validator func(bytes.Buffer) error
+
+// ValidatorRegistry allows components to attach Validators to a topic, so
+// that malformed messages are refused at publish time, rather than by each
+// subscriber
+type ValidatorRegistry interface {
+	RegisterValidator(topics.Topic, Validator)
+}
+
+type validators struct {
+	lock       sync.RWMutex
+	validators map[topics.Topic][]Validator
+}
+
+func newValidators() *validators {
+	return &validators{validators: make(map[topics.Topic][]Validator)}
+}
+
+func (v *validators) validate(topic topics.Topic, m bytes.Buffer) error {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	for _, validate := range v.validators[topic] {
+		// Validators get their own copy, so that reading from it does
+		// not affect the message handed to the listeners
+		if err := validate(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterValidator adds a Validator to a topic. Messages failing validation
+// are not delivered, and the error is returned to the publisher.
+func (bus *EventBus) RegisterValidator(topic topics.Topic, v Validator) {
+	bus.validators.lock.Lock()
+	defer bus.validators.lock.Unlock()
+	bus.validators.validators[topic] = append(bus.validators.validators[topic], v)
+}
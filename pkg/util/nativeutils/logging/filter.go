@@ -0,0 +1,202 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// subsystems maps the processes, as named by the "process" field of their
+// log entries, to the subsystem they belong to. Some loggers name their
+// process in the "prefix" field instead.
+var subsystems = map[string]string{
+	"chain":        "chain",
+	"accept block": "chain",
+	"synchronizer": "chain",
+
+	"coordinator":             "consensus",
+	"consensus initiator":     "consensus",
+	"factory":                 "consensus",
+	"agreement":               "consensus",
+	"reduction":               "consensus",
+	"reduction timer":         "consensus",
+	"first-step reduction":    "consensus",
+	"second-step reduction":   "consensus",
+	"selector":                "consensus",
+	"score generator":         "consensus",
+	"score generator factory": "consensus",
+	"proofgenerator":          "consensus",
+	"proof generator factory": "consensus",
+	"candidate generator":     "consensus",
+	"candidate broker":        "consensus",
+
+	"peer":               "peer",
+	"server":             "peer",
+	"connection manager": "peer",
+	"republisher":        "peer",
+
+	"mempool": "mempool",
+	"kadcast": "kadcast",
+}
+
+// levelFilter is a logrus.Formatter which drops the entries above the level
+// of their subsystem. The level of the standard logger is kept to the most
+// verbose of all the levels, so that the entries reach the filter.
+type levelFilter struct {
+	lock      sync.RWMutex
+	formatter log.Formatter
+	// level of the entries out of the subsystems configured
+	base   log.Level
+	levels map[string]log.Level
+
+	// one every sampling trace entries of each process is kept. Accessed
+	// atomically
+	sampling   uint32
+	sampleLock sync.Mutex
+	samples    map[string]uint64
+}
+
+var filter = &levelFilter{
+	formatter: new(log.TextFormatter),
+	base:      log.InfoLevel,
+	levels:    make(map[string]log.Level),
+	samples:   make(map[string]uint64),
+}
+
+// Format implements logrus.Formatter. Entries dropped are formatted as
+// nothing.
+func (f *levelFilter) Format(e *log.Entry) ([]byte, error) {
+	if e.Level > f.levelOf(e) || !f.sample(e) {
+		return nil, nil
+	}
+
+	f.lock.RLock()
+	formatter := f.formatter
+	f.lock.RUnlock()
+	return formatter.Format(e)
+}
+
+// sample returns false for the trace entries dropped by the sampling. Entries
+// are counted by process, so that a chatty process does not crowd out the
+// traces of the others.
+func (f *levelFilter) sample(e *log.Entry) bool {
+	n := atomic.LoadUint32(&f.sampling)
+	if e.Level != log.TraceLevel || n <= 1 {
+		return true
+	}
+
+	name, _ := e.Data["process"].(string)
+	if name == "" {
+		name, _ = e.Data["prefix"].(string)
+	}
+
+	f.sampleLock.Lock()
+	defer f.sampleLock.Unlock()
+	count := f.samples[name]
+	f.samples[name] = count + 1
+	return count%uint64(n) == 0
+}
+
+// levelOf returns the level of the subsystem of e. Levels can be set by
+// process name as well.
+func (f *levelFilter) levelOf(e *log.Entry) log.Level {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	for _, field := range []string{"process", "prefix"} {
+		name, ok := e.Data[field].(string)
+		if !ok {
+			continue
+		}
+
+		if level, ok := f.levels[name]; ok {
+			return level
+		}
+
+		if level, ok := f.levels[subsystems[name]]; ok {
+			return level
+		}
+	}
+
+	return f.base
+}
+
+// apply sets the level of the standard logger to the most verbose level.
+// Callers must hold the lock.
+func (f *levelFilter) apply() {
+	max := f.base
+	for _, level := range f.levels {
+		if level > max {
+			max = level
+		}
+	}
+	log.SetLevel(max)
+}
+
+// SetLevel sets the level of the entries out of the subsystems with their
+// own level
+func SetLevel(level log.Level) {
+	filter.lock.Lock()
+	defer filter.lock.Unlock()
+	filter.base = level
+	filter.apply()
+}
+
+// SetTraceSampling keeps one every n trace entries of each process. 0 and 1
+// keep them all.
+func SetTraceSampling(n uint32) {
+	atomic.StoreUint32(&filter.sampling, n)
+}
+
+// SetFormat sets the format of the entries, "text" or "json". An empty
+// format stands for text.
+func SetFormat(format string) error {
+	var formatter log.Formatter
+	switch format {
+	case "", "text":
+		formatter = new(log.TextFormatter)
+	case "json":
+		formatter = new(log.JSONFormatter)
+	default:
+		return fmt.Errorf("unknown log format %s", format)
+	}
+
+	filter.lock.Lock()
+	defer filter.lock.Unlock()
+	filter.formatter = formatter
+	return nil
+}
+
+// SetSubsystemLevel sets the level of the entries of a subsystem (chain,
+// consensus, peer, mempool or kadcast) or of a process
+func SetSubsystemLevel(subsystem string, level log.Level) {
+	filter.lock.Lock()
+	defer filter.lock.Unlock()
+	filter.levels[subsystem] = level
+	filter.apply()
+}
+
+// SetSubsystemLevels replaces the levels of all the subsystems with the ones
+// in levels, mapping subsystems to level names. Levels which can not be
+// parsed are skipped, and the first error is returned.
+func SetSubsystemLevels(levels map[string]string) error {
+	parsed := make(map[string]log.Level, len(levels))
+	var err error
+	for subsystem, l := range levels {
+		level, e := log.ParseLevel(l)
+		if e != nil {
+			if err == nil {
+				err = e
+			}
+			continue
+		}
+		parsed[subsystem] = level
+	}
+
+	filter.lock.Lock()
+	defer filter.lock.Unlock()
+	filter.levels = parsed
+	filter.apply()
+	return err
+}
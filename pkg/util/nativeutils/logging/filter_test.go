@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Test that the entries are filtered by the level of their subsystem.
+func TestSubsystemLevels(t *testing.T) {
+	logger := log.StandardLogger()
+	prevOut, prevFormatter, prevLevel := logger.Out, logger.Formatter, logger.Level
+	defer func() {
+		logger.SetOutput(prevOut)
+		logger.SetFormatter(prevFormatter)
+		logger.SetLevel(prevLevel)
+	}()
+
+	buf := new(bytes.Buffer)
+	logger.SetOutput(buf)
+	logger.SetFormatter(filter)
+	defer SetSubsystemLevels(nil)
+
+	SetLevel(log.WarnLevel)
+	if err := SetSubsystemLevels(map[string]string{"consensus": "trace", "chain": "pippo"}); err == nil {
+		t.Error("invalid level accepted")
+	}
+
+	// The standard logger lets the most verbose level through
+	if logger.Level != log.TraceLevel {
+		t.Errorf("expected the trace level, got %s", logger.Level)
+	}
+
+	log.WithField("process", "selector").Traceln("consensus trace")
+	log.WithField("process", "chain").Infoln("chain info")
+	log.WithField("prefix", "mempool").Warnln("mempool warning")
+	log.Debugln("debug")
+
+	out := buf.String()
+	if !strings.Contains(out, "consensus trace") || !strings.Contains(out, "mempool warning") {
+		t.Errorf("entries missing: %s", out)
+	}
+
+	if strings.Contains(out, "chain info") || strings.Contains(out, "debug") {
+		t.Errorf("entries not filtered: %s", out)
+	}
+
+	// Levels can be set by process as well
+	buf.Reset()
+	SetSubsystemLevel("selector", log.ErrorLevel)
+	log.WithField("process", "selector").Warnln("selector warning")
+	log.WithField("process", "agreement").Warnln("agreement warning")
+
+	out = buf.String()
+	if strings.Contains(out, "selector warning") || !strings.Contains(out, "agreement warning") {
+		t.Errorf("unexpected entries: %s", out)
+	}
+}
+
+// Test that the trace entries of each process are sampled, and that entries
+// can be formatted as JSON.
+func TestTraceSamplingAndFormat(t *testing.T) {
+	logger := log.StandardLogger()
+	prevOut, prevFormatter, prevLevel := logger.Out, logger.Formatter, logger.Level
+	defer func() {
+		logger.SetOutput(prevOut)
+		logger.SetFormatter(prevFormatter)
+		logger.SetLevel(prevLevel)
+	}()
+
+	buf := new(bytes.Buffer)
+	logger.SetOutput(buf)
+	logger.SetFormatter(filter)
+	defer SetLevel(log.InfoLevel)
+	defer SetTraceSampling(0)
+	defer SetFormat("text")
+
+	if err := SetFormat("yaml"); err == nil {
+		t.Error("unknown format accepted")
+	}
+
+	if err := SetFormat("json"); err != nil {
+		t.Fatal(err)
+	}
+
+	SetLevel(log.TraceLevel)
+	SetTraceSampling(3)
+	for i := 0; i < 6; i++ {
+		log.WithField("process", "sampled").Traceln("sampled trace")
+	}
+	log.WithField("process", "sampled").Infoln("sampled info")
+
+	out := buf.String()
+	if n := strings.Count(out, "sampled trace"); n != 2 {
+		t.Errorf("expected 2 sampled traces, got %d", n)
+	}
+
+	if !strings.Contains(out, "\"msg\":\"sampled info\"") {
+		t.Errorf("entries not formatted as JSON: %s", out)
+	}
+}
@@ -1,24 +1,57 @@
 package logging
 
 import (
-	"os"
+	"io"
 
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	log "github.com/sirupsen/logrus"
 )
 
-func InitLog(logFile *os.File) {
+// recentLines is the amount of lines logged kept for Recent
+const recentLines = 1000
+
+// recent keeps the last lines logged, whatever the output
+var recent = NewTail(recentLines)
+
+// Recent returns the last lines logged, as Tail.Since does, so that they can
+// be read without access to the log output
+func Recent(seq uint64) ([]string, uint64) {
+	return recent.Since(seq)
+}
+
+func InitLog(logFile io.Writer) {
+	// filter the entries by the level of their subsystem
+	if err := SetFormat(cfg.Get().Logger.Format); err != nil {
+		log.Warnf("Parse logger format from config err: %v", err)
+	}
+	log.SetFormatter(filter)
+
 	// apply logger level from configurations
 	SetToLevel(cfg.Get().Logger.Level)
-	log.SetOutput(logFile)
+	setSubsystemLevels(cfg.Get().Logger.Levels)
+	SetTraceSampling(cfg.Get().Logger.TraceSampling)
+	log.SetOutput(io.MultiWriter(logFile, recent))
+
+	// follow the levels and sampling set on reload
+	cfg.Subscribe("logger", func(r cfg.Registry) {
+		SetToLevel(r.Logger.Level)
+		setSubsystemLevels(r.Logger.Levels)
+		SetTraceSampling(r.Logger.TraceSampling)
+	})
 }
 
 func SetToLevel(l string) {
 	level, err := log.ParseLevel(l)
 	if err == nil {
-		log.SetLevel(level)
+		SetLevel(level)
 	} else {
-		log.SetLevel(log.TraceLevel)
+		SetLevel(log.TraceLevel)
 		log.Warnf("Parse logger level from config err: %v", err)
 	}
 }
+
+func setSubsystemLevels(levels map[string]string) {
+	if err := SetSubsystemLevels(levels); err != nil {
+		log.Warnf("Parse logger levels from config err: %v", err)
+	}
+}
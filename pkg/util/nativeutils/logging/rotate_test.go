@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Test that the log file is rotated above its maximum size, and that only
+// maxBackups rotated files are kept.
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dusk-log-rotation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "node.log")
+	f, err := NewRotatingFile(path, 10, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	line := []byte("12345678\n")
+	for i := 0; i < 4; i++ {
+		if _, err := f.Write(line); err != nil {
+			t.Fatal(err)
+		}
+		// Rotated files are named after the time of rotation
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(content, line) {
+		t.Errorf("expected a single line in the current file, got %q", content)
+	}
+
+	backups, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backups) != 2 {
+		t.Errorf("expected 2 rotated files, got %d", len(backups))
+	}
+}
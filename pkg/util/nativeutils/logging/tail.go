@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+)
+
+// Tail keeps the last lines written to it, so that they can be attached to
+// crash reports, or followed through the RPC. It is meant to be written to
+// along with the log output.
+type Tail struct {
+	lock  sync.Mutex
+	lines []string
+	// next is the index of the oldest line, once lines is full
+	next int
+	// partial line, waiting for its end
+	partial string
+	// written is the amount of lines written since the Tail was created
+	written uint64
+}
+
+// NewTail returns a Tail keeping the last n lines
+func NewTail(n int) *Tail {
+	return &Tail{lines: make([]string, 0, n)}
+}
+
+// Write implements io.Writer
+func (t *Tail) Write(p []byte) (int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	lines := strings.Split(t.partial+string(p), "\n")
+	// The last element is what follows the last line end
+	t.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		t.add(line)
+	}
+	return len(p), nil
+}
+
+func (t *Tail) add(line string) {
+	t.written++
+	if cap(t.lines) == 0 {
+		return
+	}
+
+	if len(t.lines) < cap(t.lines) {
+		t.lines = append(t.lines, line)
+		return
+	}
+
+	t.lines[t.next] = line
+	t.next = (t.next + 1) % len(t.lines)
+}
+
+// Lines returns the lines kept, oldest first
+func (t *Tail) Lines() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.ordered()
+}
+
+func (t *Tail) ordered() []string {
+	lines := make([]string, 0, len(t.lines))
+	lines = append(lines, t.lines[t.next:]...)
+	return append(lines, t.lines[:t.next]...)
+}
+
+// Since returns the lines kept which were written after the first seq ones,
+// oldest first, along with the amount of lines written so far. The latter is
+// the seq to pass on the next call, to follow the output.
+func (t *Tail) Since(seq uint64) ([]string, uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	lines := t.ordered()
+
+	// The lines kept are the last ones written
+	first := t.written - uint64(len(lines))
+	if seq > first {
+		if seq > t.written {
+			seq = t.written
+		}
+		lines = lines[seq-first:]
+	}
+	return lines, t.written
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTail(t *testing.T) {
+	tail := NewTail(3)
+	_, _ = io.WriteString(tail, "one\ntwo\n")
+	assert.Equal(t, []string{"one", "two"}, tail.Lines())
+
+	// Lines split over writes are kept whole, and the oldest are dropped
+	_, _ = io.WriteString(tail, "thr")
+	_, _ = io.WriteString(tail, "ee\nfour\n")
+	assert.Equal(t, []string{"two", "three", "four"}, tail.Lines())
+}
+
+func TestTailSince(t *testing.T) {
+	tail := NewTail(3)
+	_, _ = io.WriteString(tail, "one\ntwo\n")
+	lines, seq := tail.Since(0)
+	assert.Equal(t, []string{"one", "two"}, lines)
+	assert.Equal(t, uint64(2), seq)
+
+	// Only the lines written after seq are returned
+	_, _ = io.WriteString(tail, "three\nfour\nfive\n")
+	lines, seq = tail.Since(seq)
+	assert.Equal(t, []string{"three", "four", "five"}, lines)
+	assert.Equal(t, uint64(5), seq)
+
+	lines, _ = tail.Since(seq)
+	assert.Empty(t, lines)
+
+	// Lines dropped already are skipped
+	lines, _ = tail.Since(1)
+	assert.Equal(t, []string{"three", "four", "five"}, lines)
+}
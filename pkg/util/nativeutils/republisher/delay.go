@@ -0,0 +1,109 @@
+package republisher
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// pendingMsg is a message waiting to be republished, along with the peers
+// it was received from since
+type pendingMsg struct {
+	origin string
+	others map[string]struct{}
+}
+
+// delayer holds the messages waiting to be republished
+type delayer struct {
+	lock    sync.Mutex
+	pending map[string]*pendingMsg
+}
+
+func newDelayer() *delayer {
+	return &delayer{pending: make(map[string]*pendingMsg)}
+}
+
+func (d *delayer) add(key, origin string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.pending[key] = &pendingMsg{origin, make(map[string]struct{})}
+}
+
+// observe records that the message identified by key was received from
+// origin. Messages which are not pending are ignored.
+func (d *delayer) observe(key, origin string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	p, ok := d.pending[key]
+	if !ok || origin == "" || origin == p.origin {
+		return
+	}
+
+	p.others[origin] = struct{}{}
+}
+
+// take removes the pending message identified by key, and returns the amount
+// of other peers it was received from
+func (d *delayer) take(key string) int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	p, ok := d.pending[key]
+	if !ok {
+		return 0
+	}
+
+	delete(d.pending, key)
+	return len(p.others)
+}
+
+// jitter returns a random delay up to max
+func jitter(max time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// active holds the Republishers delaying their messages, by topic
+var active = struct {
+	sync.RWMutex
+	republishers map[topics.Topic][]*Republisher
+}{republishers: make(map[topics.Topic][]*Republisher)}
+
+func register(r *Republisher) {
+	active.Lock()
+	defer active.Unlock()
+	active.republishers[r.tpc] = append(active.republishers[r.tpc], r)
+}
+
+func unregister(r *Republisher) {
+	active.Lock()
+	defer active.Unlock()
+	list := active.republishers[r.tpc]
+	for i, other := range list {
+		if other == r {
+			active.republishers[r.tpc] = append(list[:i:i], list[i+1:]...)
+			return
+		}
+	}
+}
+
+// Observe is called by the peer layer with the messages it does not publish,
+// as they were already received from another peer. Messages waiting to be
+// republished are not, if enough other peers sent them.
+func Observe(topic topics.Topic, m []byte, origin string) {
+	active.RLock()
+	list := active.republishers[topic]
+	active.RUnlock()
+	if len(list) == 0 {
+		return
+	}
+
+	key, err := digest(m)
+	if err != nil {
+		return
+	}
+
+	for _, r := range list {
+		r.delayer.observe(key, origin)
+	}
+}
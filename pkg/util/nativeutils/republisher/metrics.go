@@ -0,0 +1,56 @@
+package republisher
+
+import (
+	"sync/atomic"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// TopicMetrics holds the statistics of the Republisher of a topic
+type TopicMetrics struct {
+	// Republished is the amount of messages gossiped again
+	Republished uint64
+	// Duplicates is the amount of messages already republished within
+	// the window
+	Duplicates uint64
+	// DroppedTopic is the amount of messages above the rate of the topic
+	DroppedTopic uint64
+	// DroppedPeer is the amount of messages above the rate of the peer
+	// they were received from
+	DroppedPeer uint64
+	// Skipped is the amount of delayed messages which were not
+	// republished, as enough other peers sent them in the meantime
+	Skipped uint64
+}
+
+type topicCounters struct {
+	republished  uint64
+	duplicates   uint64
+	droppedTopic uint64
+	droppedPeer  uint64
+	skipped      uint64
+}
+
+// counters are shared by the Republishers of a same topic
+var counters [256]topicCounters
+
+// Metrics returns the statistics of the topics which were republished
+func Metrics() map[topics.Topic]TopicMetrics {
+	res := make(map[topics.Topic]TopicMetrics)
+	for i := range counters {
+		c := &counters[i]
+		m := TopicMetrics{
+			Republished:  atomic.LoadUint64(&c.republished),
+			Duplicates:   atomic.LoadUint64(&c.duplicates),
+			DroppedTopic: atomic.LoadUint64(&c.droppedTopic),
+			DroppedPeer:  atomic.LoadUint64(&c.droppedPeer),
+			Skipped:      atomic.LoadUint64(&c.skipped),
+		}
+
+		if m != (TopicMetrics{}) {
+			res[topics.Topic(i)] = m
+		}
+	}
+
+	return res
+}
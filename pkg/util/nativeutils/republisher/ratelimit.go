@@ -0,0 +1,111 @@
+package republisher
+
+import (
+	"sync"
+	"time"
+)
+
+// maxPeerBuckets bounds the amount of peers tracked by a limiter
+const maxPeerBuckets = 1024
+
+// bucket is a token bucket, refilled at rate tokens per second up to burst
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *bucket) take(rate, burst float64, now time.Time) bool {
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// limiter bounds the rate of the messages republished on a topic, and of
+// those received from each peer. A zero rate disables the corresponding
+// limit.
+type limiter struct {
+	lock sync.Mutex
+
+	rate, burst         float64
+	peerRate, peerBurst float64
+
+	topic *bucket
+	peers map[string]*bucket
+}
+
+func newLimiter(rate float64, burst uint32, peerRate float64, peerBurst uint32) *limiter {
+	l := &limiter{
+		rate:      rate,
+		burst:     float64(burst),
+		peerRate:  peerRate,
+		peerBurst: float64(peerBurst),
+		peers:     make(map[string]*bucket),
+	}
+
+	// At least one message has to get through
+	if l.burst < 1 {
+		l.burst = 1
+	}
+
+	if l.peerBurst < 1 {
+		l.peerBurst = 1
+	}
+
+	l.topic = &bucket{tokens: l.burst, last: time.Now()}
+	return l
+}
+
+// allowPeer takes a token from the bucket of the peer the message was
+// received from. Messages of unknown origin are not limited per peer.
+func (l *limiter) allowPeer(origin string, now time.Time) bool {
+	if l.peerRate == 0 || origin == "" {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	b, ok := l.peers[origin]
+	if !ok {
+		if len(l.peers) >= maxPeerBuckets {
+			l.prune(now)
+		}
+		b = &bucket{tokens: l.peerBurst, last: now}
+		l.peers[origin] = b
+	}
+
+	return b.take(l.peerRate, l.peerBurst, now)
+}
+
+// allowTopic takes a token from the bucket of the topic
+func (l *limiter) allowTopic(now time.Time) bool {
+	if l.rate == 0 {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.topic.take(l.rate, l.burst, now)
+}
+
+// prune forgets the peers whose bucket is full again, as they are in the
+// same state as new ones. If none is, all peers are forgotten. The lock must
+// be held.
+func (l *limiter) prune(now time.Time) {
+	for origin, b := range l.peers {
+		if b.tokens+now.Sub(b.last).Seconds()*l.peerRate >= l.peerBurst {
+			delete(l.peers, origin)
+		}
+	}
+
+	if len(l.peers) >= maxPeerBuckets {
+		l.peers = make(map[string]*bucket)
+	}
+}
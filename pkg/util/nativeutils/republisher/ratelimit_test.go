@@ -0,0 +1,42 @@
+package republisher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(1, 2, 0, 0)
+	l.topic.last = now
+
+	// The burst goes through, then the rate applies
+	assert.True(t, l.allowTopic(now))
+	assert.True(t, l.allowTopic(now))
+	assert.False(t, l.allowTopic(now))
+	assert.True(t, l.allowTopic(now.Add(time.Second)))
+	assert.False(t, l.allowTopic(now.Add(time.Second)))
+
+	// Peers are not limited
+	assert.True(t, l.allowPeer("a", now))
+}
+
+func TestPeerLimiter(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(0, 0, 1, 1)
+	assert.True(t, l.allowPeer("a", now))
+	assert.False(t, l.allowPeer("a", now))
+
+	// Other peers, and messages of unknown origin, are not affected
+	assert.True(t, l.allowPeer("b", now))
+	assert.True(t, l.allowPeer("", now))
+	assert.True(t, l.allowPeer("a", now.Add(time.Second)))
+
+	// The amount of peers tracked is bounded
+	for i := 0; i < maxPeerBuckets+1; i++ {
+		l.allowPeer(string(rune(i)), now)
+	}
+	assert.True(t, len(l.peers) <= maxPeerBuckets)
+}
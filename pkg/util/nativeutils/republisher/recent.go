@@ -0,0 +1,96 @@
+package republisher
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-crypto/hash"
+)
+
+// maxRecent bounds the amount of messages remembered by a Republisher
+const maxRecent = 4096
+
+// defaultWindow is used when network.republishWindow is not set
+const defaultWindow = 60 * time.Second
+
+// recent is an LRU of the hashes of the messages republished within the
+// window. Older messages are forgotten, and may be republished again.
+type recent struct {
+	lock   sync.Mutex
+	window time.Duration
+	// oldest first
+	order *list.List
+	seen  map[string]*list.Element
+}
+
+type recentEntry struct {
+	key  string
+	seen time.Time
+}
+
+func newRecent(window time.Duration) *recent {
+	return &recent{
+		window: window,
+		order:  list.New(),
+		seen:   make(map[string]*list.Element),
+	}
+}
+
+// digest returns the key identifying the message m
+func digest(m []byte) (string, error) {
+	d, err := hash.Sha3256(m)
+	return string(d), err
+}
+
+// add records the message identified by key, seen at now. It returns false
+// if the message was already seen within the window.
+func (r *recent) add(key string, now time.Time) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.expire(now)
+	if _, ok := r.seen[key]; ok {
+		return false
+	}
+
+	if r.order.Len() >= maxRecent {
+		r.remove(r.order.Front())
+	}
+
+	r.seen[key] = r.order.PushBack(recentEntry{key, now})
+	return true
+}
+
+// has returns whether the message identified by key was seen within the
+// window, without recording it
+func (r *recent) has(key string, now time.Time) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.expire(now)
+	_, ok := r.seen[key]
+	return ok
+}
+
+// forget drops the message identified by key, for it to be republished again
+func (r *recent) forget(key string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if e, ok := r.seen[key]; ok {
+		r.remove(e)
+	}
+}
+
+// expire forgets the messages seen before the window. The lock must be held.
+func (r *recent) expire(now time.Time) {
+	for e := r.order.Front(); e != nil; e = r.order.Front() {
+		if now.Sub(e.Value.(recentEntry).seen) < r.window {
+			return
+		}
+		r.remove(e)
+	}
+}
+
+func (r *recent) remove(e *list.Element) {
+	delete(r.seen, e.Value.(recentEntry).key)
+	r.order.Remove(e)
+}
@@ -0,0 +1,36 @@
+package republisher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecent(t *testing.T) {
+	now := time.Now()
+	r := newRecent(time.Minute)
+	assert.True(t, r.add(key(t, 1, 0), now))
+	assert.False(t, r.add(key(t, 1, 0), now.Add(30*time.Second)))
+
+	// Forgotten once out of the window
+	assert.True(t, r.add(key(t, 1, 0), now.Add(time.Minute)))
+
+	// The oldest message is evicted when full
+	r = newRecent(time.Minute)
+	for i := 0; i < maxRecent; i++ {
+		assert.True(t, r.add(key(t, byte(i), byte(i>>8)), now))
+	}
+	assert.True(t, r.add(key(t, 0xff, 0xff), now))
+	assert.Equal(t, maxRecent, r.order.Len())
+	assert.True(t, r.add(key(t, 0, 0), now))
+}
+
+func key(t *testing.T, m ...byte) string {
+	k, err := digest(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return k
+}
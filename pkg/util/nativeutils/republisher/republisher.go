@@ -2,28 +2,64 @@ package republisher
 
 import (
 	"bytes"
+	"sync/atomic"
+	"time"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
+	log "github.com/sirupsen/logrus"
 )
 
+var lg = log.WithField("process", "republisher")
+
 type Validator func(bytes.Buffer) error
 
 // Republisher handles the repropagation of messages propagated with a
-// specified topic
+// specified topic. Each message is republished at most once within
+// network.republish.window seconds, so that gossip loops are not amplified.
+//
+// The rate of the republished messages is bounded per topic and, for the
+// messages received from the network, per peer. Messages above the rate
+// are dropped, and only count as republished once relayed.
+//
+// Messages refused by the validators are reported on the RejectedMessage
+// topic.
+//
+// If network.republish.jitter is set, messages are republished after a
+// random delay, and skipped if network.republish.skipAfter other peers sent
+// them in the meantime, as these peers are relaying them already.
 type Republisher struct {
 	tpc        topics.Topic
 	broker     eventbus.Broker
 	id         uint32
 	validators []Validator
+	recent     *recent
+	limiter    *limiter
+
+	jitter    time.Duration
+	skipAfter int
+	delayer   *delayer
 }
 
 // New creates a Republisher
 func New(eb eventbus.Broker, tpc topics.Topic, v ...Validator) *Republisher {
+	conf := cfg.Get().Network.Republish
+	window := time.Duration(conf.Window) * time.Second
+	if window == 0 {
+		window = defaultWindow
+	}
+
 	r := &Republisher{
 		broker:     eb,
 		tpc:        tpc,
 		validators: v,
+		recent:     newRecent(window),
+		limiter:    newLimiter(conf.Rate, conf.Burst, conf.PeerRate, conf.PeerBurst),
+		jitter:     time.Duration(conf.Jitter) * time.Millisecond,
+		skipAfter:  int(conf.SkipAfter),
+		delayer:    newDelayer(),
 	}
 	r.id = r.Activate()
 	return r
@@ -32,6 +68,9 @@ func New(eb eventbus.Broker, tpc topics.Topic, v ...Validator) *Republisher {
 // Stop a Republisher
 func (r *Republisher) Stop() {
 	r.broker.Unsubscribe(r.tpc, r.id)
+	if r.jitter > 0 {
+		unregister(r)
+	}
 }
 
 // Activate the Republisher by listening to topic through a
@@ -41,24 +80,95 @@ func (r *Republisher) Activate() uint32 {
 		return r.id
 	}
 
-	l := eventbus.NewCallbackListener(r.Republish)
+	l := eventbus.NewTracedCallbackListener(r.RepublishTraced)
 	r.id = r.broker.Subscribe(r.tpc, l)
+	if r.jitter > 0 {
+		register(r)
+	}
 	return r.id
 }
 
 // Republish intercepts a topic and repropagates it immediately
-// after applying any eventual validation logic
+// after applying any eventual validation logic. Messages already
+// republished within the window are skipped.
 func (r *Republisher) Republish(b bytes.Buffer) error {
+	return r.RepublishTraced(b, tracing.None)
+}
+
+// RepublishTraced is Republish for a message with a correlation ID, through
+// which the peer it was received from is found.
+func (r *Republisher) RepublishTraced(b bytes.Buffer, id tracing.ID) error {
+	c := &counters[r.tpc]
+	now := time.Now()
+	origin := tracing.Origin(id)
+	key, err := digest(b.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if r.recent.has(key, now) {
+		atomic.AddUint64(&c.duplicates, 1)
+		r.delayer.observe(key, origin)
+		return nil
+	}
+
+	// Messages dropped are not recorded, so that the same message coming
+	// from another peer is still republished
+	if !r.limiter.allowPeer(origin, now) {
+		atomic.AddUint64(&c.droppedPeer, 1)
+		lg.WithFields(log.Fields{"topic": r.tpc.String(), "peer": origin}).Traceln("peer rate exceeded, message dropped")
+		return nil
+	}
+
 	for _, v := range r.validators {
 		if err := v(b); err != nil {
+			eventbus.PublishRejection(r.broker, eventbus.Rejection{Topic: r.tpc, Reason: err.Error(), Origin: origin})
 			return err
 		}
 	}
 
+	if !r.recent.add(key, now) {
+		atomic.AddUint64(&c.duplicates, 1)
+		r.delayer.observe(key, origin)
+		return nil
+	}
+
+	if r.jitter == 0 {
+		return r.gossip(b, key, now)
+	}
+
+	r.delayer.add(key, origin)
+	time.AfterFunc(jitter(r.jitter), func() {
+		others := r.delayer.take(key)
+		if r.skipAfter > 0 && others >= r.skipAfter {
+			atomic.AddUint64(&c.skipped, 1)
+			return
+		}
+
+		if err := r.gossip(b, key, time.Now()); err != nil {
+			lg.WithError(err).WithField("topic", r.tpc.String()).Warnln("could not republish message")
+		}
+	})
+	return nil
+}
+
+// gossip sends the message identified by key to the peers, within the rate
+// of the topic. Messages above the rate are forgotten, to be republished if
+// received again.
+func (r *Republisher) gossip(b bytes.Buffer, key string, now time.Time) error {
+	c := &counters[r.tpc]
+	if !r.limiter.allowTopic(now) {
+		r.recent.forget(key)
+		atomic.AddUint64(&c.droppedTopic, 1)
+		lg.WithField("topic", r.tpc.String()).Traceln("topic rate exceeded, message dropped")
+		return nil
+	}
+
 	if err := topics.Prepend(&b, r.tpc); err != nil {
 		return err
 	}
 
+	atomic.AddUint64(&c.republished, 1)
 	r.broker.Publish(topics.Gossip, &b)
 	return nil
 }
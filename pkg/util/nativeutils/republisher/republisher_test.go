@@ -3,10 +3,13 @@ package republisher_test
 import (
 	"bytes"
 	"testing"
+	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/republisher"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,3 +30,101 @@ func TestRepublisher(t *testing.T) {
 	assert.Equal(t, topics.Agreement, tpc)
 	assert.Equal(t, []byte{1}, packet.Bytes())
 }
+
+// Ensure a message is only republished once
+func TestRepublishOnce(t *testing.T) {
+	eb := eventbus.New()
+	gossipChan := make(chan bytes.Buffer, 3)
+	eb.Subscribe(topics.Gossip, eventbus.NewChanListener(gossipChan))
+
+	republisher.New(eb, topics.Agreement)
+	eb.Publish(topics.Agreement, bytes.NewBuffer([]byte{1}))
+	eb.Publish(topics.Agreement, bytes.NewBuffer([]byte{1}))
+	eb.Publish(topics.Agreement, bytes.NewBuffer([]byte{2}))
+
+	first := <-gossipChan
+	assert.Equal(t, []byte{byte(topics.Agreement), 1}, first.Bytes())
+	second := <-gossipChan
+	assert.Equal(t, []byte{byte(topics.Agreement), 2}, second.Bytes())
+
+	select {
+	case <-gossipChan:
+		t.Fatal("message republished twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// Ensure delayed messages are skipped once relayed by enough other peers
+func TestRepublishDelayed(t *testing.T) {
+	prev := config.Get()
+	defer config.Mock(&prev)
+	r := prev
+	r.Network.Republish.Jitter = 50
+	r.Network.Republish.SkipAfter = 1
+	config.Mock(&r)
+
+	eb := eventbus.New()
+	gossipChan := make(chan bytes.Buffer, 1)
+	eb.Subscribe(topics.Gossip, eventbus.NewChanListener(gossipChan))
+	rp := republisher.New(eb, topics.Reduction)
+	defer rp.Stop()
+
+	id := tracing.NewID()
+	tracing.SetOrigin(id, "peer1")
+	assert.NoError(t, eb.PublishTraced(topics.Reduction, bytes.NewBuffer([]byte{1}), id))
+
+	// Another peer sends the same message during the delay
+	republisher.Observe(topics.Reduction, []byte{1}, "peer2")
+
+	select {
+	case <-gossipChan:
+		t.Fatal("message relayed by another peer was republished")
+	case <-time.After(200 * time.Millisecond):
+	}
+	assert.Equal(t, uint64(1), republisher.Metrics()[topics.Reduction].Skipped)
+
+	// Without other peers, the message is republished after the delay
+	assert.NoError(t, eb.PublishTraced(topics.Reduction, bytes.NewBuffer([]byte{2}), id))
+	republisher.Observe(topics.Reduction, []byte{2}, "peer1")
+	select {
+	case packet := <-gossipChan:
+		assert.Equal(t, []byte{byte(topics.Reduction), 2}, packet.Bytes())
+	case <-time.After(time.Second):
+		t.Fatal("message was not republished")
+	}
+}
+
+// Ensure a message dropped over the rate of a peer is still republished when
+// received from another one
+func TestRepublishAfterPeerLimit(t *testing.T) {
+	prev := config.Get()
+	defer config.Mock(&prev)
+	r := prev
+	r.Network.Republish.PeerRate = 0.001
+	r.Network.Republish.PeerBurst = 1
+	config.Mock(&r)
+
+	eb := eventbus.New()
+	gossipChan := make(chan bytes.Buffer, 2)
+	eb.Subscribe(topics.Gossip, eventbus.NewChanListener(gossipChan))
+	rp := republisher.New(eb, topics.Reduction)
+	defer rp.Stop()
+
+	peer1, peer2 := tracing.NewID(), tracing.NewID()
+	tracing.SetOrigin(peer1, "peer1")
+	tracing.SetOrigin(peer2, "peer2")
+
+	// The second message of peer1 is above its rate
+	assert.NoError(t, eb.PublishTraced(topics.Reduction, bytes.NewBuffer([]byte{1}), peer1))
+	assert.NoError(t, eb.PublishTraced(topics.Reduction, bytes.NewBuffer([]byte{2}), peer1))
+	assert.NoError(t, eb.PublishTraced(topics.Reduction, bytes.NewBuffer([]byte{2}), peer2))
+
+	first := <-gossipChan
+	assert.Equal(t, []byte{byte(topics.Reduction), 1}, first.Bytes())
+	select {
+	case second := <-gossipChan:
+		assert.Equal(t, []byte{byte(topics.Reduction), 2}, second.Bytes())
+	case <-time.After(time.Second):
+		t.Fatal("message dropped for a peer was not republished")
+	}
+}
@@ -2,6 +2,7 @@ package rpcbus
 
 import (
 	"bytes"
+	"strconv"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 )
@@ -30,8 +31,72 @@ const (
 	AutomateConsensusTxs
 	GetSyncProgress
 	IsWalletLoaded
+	GetPeerInfo
+	GetProvisioners
+	AddPeer
+	BanPeer
+	GetConsensusState
+	GetFeeEstimate
+	GetDeployments
+	GetLocalCandidate
+	GetCandidateStats
+	GetBlockByHash
+	GetBlockByHeight
+	GetHeadersRange
+	CreateSnapshot
+	GetParticipation
+	CreateStandardTx
+	SendToPeer
 )
 
+var methodNames = [...]string{
+	"GetLastBlock",
+	"GetMempoolTxs",
+	"GetMempoolTxsBySize",
+	"SendMempoolTx",
+	"VerifyCandidateBlock",
+	"CreateWallet",
+	"CreateFromSeed",
+	"LoadWallet",
+	"SendBidTx",
+	"SendStakeTx",
+	"SendStandardTx",
+	"GetBalance",
+	"GetUnconfirmedBalance",
+	"GetAddress",
+	"GetTxHistory",
+	"GetLastCertificate",
+	"GetCandidate",
+	"GetRoundResults",
+	"AutomateConsensusTxs",
+	"GetSyncProgress",
+	"IsWalletLoaded",
+	"GetPeerInfo",
+	"GetProvisioners",
+	"AddPeer",
+	"BanPeer",
+	"GetConsensusState",
+	"GetFeeEstimate",
+	"GetDeployments",
+	"GetLocalCandidate",
+	"GetCandidateStats",
+	"GetBlockByHash",
+	"GetBlockByHeight",
+	"GetHeadersRange",
+	"CreateSnapshot",
+	"GetParticipation",
+	"CreateStandardTx",
+	"SendToPeer",
+}
+
+func (m method) String() string {
+	if int(m) < len(methodNames) {
+		return methodNames[m]
+	}
+
+	return "method(" + strconv.Itoa(int(m)) + ")"
+}
+
 func MarshalConsensusTxRequest(buf *bytes.Buffer, amount, lockTime uint64) error {
 	if err := encoding.WriteUint64LE(buf, amount); err != nil {
 		return err
@@ -39,3 +104,33 @@ func MarshalConsensusTxRequest(buf *bytes.Buffer, amount, lockTime uint64) error
 
 	return encoding.WriteUint64LE(buf, lockTime)
 }
+
+// MarshalHeadersRangeRequest encodes the params of GetHeadersRange, for the
+// headers from height from to height to, both included
+func MarshalHeadersRangeRequest(buf *bytes.Buffer, from, to uint64) error {
+	if err := encoding.WriteUint64LE(buf, from); err != nil {
+		return err
+	}
+
+	return encoding.WriteUint64LE(buf, to)
+}
+
+// Outcomes of SendMempoolTx, following the TxID in its response
+const (
+	// TxAccepted is reported for the txs verified and advertised
+	TxAccepted byte = iota
+	// TxHeld is reported for the txs held until their inputs unlock. They
+	// are advertised once released.
+	TxHeld
+)
+
+// MarshalSendToPeerRequest encodes the params of SendToPeer, for the message
+// msg, prepended with its topic, to be sent to the peer at address only
+func MarshalSendToPeerRequest(buf *bytes.Buffer, address string, msg *bytes.Buffer) error {
+	if err := encoding.WriteString(buf, address); err != nil {
+		return err
+	}
+
+	_, err := buf.Write(msg.Bytes())
+	return err
+}
@@ -0,0 +1,67 @@
+package rpcbus
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// MethodMetrics are the statistics collected for a method
+type MethodMetrics struct {
+	// Calls is the amount of requests made
+	Calls uint64
+	// Failures is the amount of requests which returned an error,
+	// including timeouts
+	Failures uint64
+	// Timeouts is the amount of requests which timed out
+	Timeouts uint64
+	// Latency is the cumulative time spent waiting for responses
+	Latency time.Duration
+}
+
+// AverageLatency returns the mean time spent waiting for a response
+func (m MethodMetrics) AverageLatency() time.Duration {
+	if m.Calls == 0 {
+		return 0
+	}
+
+	return m.Latency / time.Duration(m.Calls)
+}
+
+type methodCounters struct {
+	calls    uint64
+	failures uint64
+	timeouts uint64
+	latency  int64
+}
+
+func (c *methodCounters) record(d time.Duration, err error) {
+	atomic.AddUint64(&c.calls, 1)
+	atomic.AddInt64(&c.latency, int64(d))
+	if err != nil {
+		atomic.AddUint64(&c.failures, 1)
+	}
+
+	if errors.Is(err, ErrRequestTimeout) {
+		atomic.AddUint64(&c.timeouts, 1)
+	}
+}
+
+// Metrics returns the statistics of all the registered methods
+func (bus *RPCBus) Metrics() map[method]MethodMetrics {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+
+	res := make(map[method]MethodMetrics, len(bus.registry))
+	for m, e := range bus.registry {
+		c := &e.metrics
+		res[m] = MethodMetrics{
+			Calls:    atomic.LoadUint64(&c.calls),
+			Failures: atomic.LoadUint64(&c.failures),
+			Timeouts: atomic.LoadUint64(&c.timeouts),
+			Latency:  time.Duration(atomic.LoadInt64(&c.latency)),
+		}
+	}
+
+	return res
+}
@@ -2,9 +2,13 @@ package rpcbus
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
 )
 
 var (
@@ -19,19 +23,72 @@ var (
 
 	// ErrInvalidRequestChan is returned method is bound to nil chan
 	ErrInvalidRequestChan = errors.New("invalid request channel")
+
+	// ErrInvalidHandler is returned when a method is bound to a nil handler
+	ErrInvalidHandler = errors.New("invalid handler")
+
+	// ErrRequestCanceled is returned when the context of a request is
+	// canceled before a response arrives
+	ErrRequestCanceled = errors.New("canceled request")
 )
 
+// CallError is returned by CallContext when a request could not be served
+// by the bus, as opposed to errors returned by the handler itself. The
+// underlying error is one of ErrRequestTimeout, ErrRequestCanceled or
+// ErrMethodNotExists, and can be inspected with errors.Is.
+type CallError struct {
+	Method method
+	Err    error
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("rpcbus method %s: %s", e.Method.String(), e.Err.Error())
+}
+
+// Unwrap returns the underlying error
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
 // RPCBus is a request–response mechanism for internal communication between node
-// components/subsystems. Under the hood this is long-polling method based on
-// "chan chan" technique.
+// components/subsystems. Methods are served by handler functions, which run
+// on the goroutine of the caller. Methods bound to a channel, through
+// RegisterChan, are served by a consumer goroutine instead, according to
+// the "chan chan" technique.
 type RPCBus struct {
 	mu       sync.RWMutex
-	registry map[method]chan<- Request
+	registry map[method]*entry
+	timeouts map[method]time.Duration
+	// defaultTimeout bounds the calls to the methods without a timeout of
+	// their own. Zero lets them wait for the response
+	defaultTimeout time.Duration
+}
+
+// HandlerFunc serves the requests of a method. The context of the request
+// should be watched by handlers performing lengthy operations.
+type HandlerFunc func(Request) (bytes.Buffer, error)
+
+// entry is a registered method
+type entry struct {
+	// only one between handler and reqChan is set
+	handler HandlerFunc
+	reqChan chan<- Request
+
+	// sem caps the amount of concurrent requests served by handler. It
+	// is nil when no limit applies.
+	sem chan struct{}
+
+	metrics methodCounters
 }
 
 type Request struct {
 	Params   bytes.Buffer
 	RespChan chan Response
+	// Ctx is canceled once the caller stops waiting for a response.
+	// Handlers performing lengthy operations should watch it through
+	// Context. It also carries the correlation ID of the message the
+	// request is made for, if any (see tracing.FromContext).
+	Ctx context.Context
 }
 
 type Response struct {
@@ -41,89 +98,237 @@ type Response struct {
 
 // NewRequest builds a new request with params
 func NewRequest(p bytes.Buffer) Request {
+	return NewRequestWithContext(context.Background(), p)
+}
+
+// NewRequestWithContext builds a new request with params, bound to a context
+func NewRequestWithContext(ctx context.Context, p bytes.Buffer) Request {
 	return Request{
 		Params:   p,
 		RespChan: make(chan Response, 1),
+		Ctx:      ctx,
+	}
+}
+
+// Context returns the context of the request. It never returns nil.
+func (r Request) Context() context.Context {
+	if r.Ctx == nil {
+		return context.Background()
 	}
+
+	return r.Ctx
 }
 
 func New() *RPCBus {
 	return &RPCBus{
-		registry: make(map[method]chan<- Request),
+		registry: make(map[method]*entry),
+		timeouts: make(map[method]time.Duration),
 	}
 }
 
-// Register registers a method and binds it to a handler channel. methodName
-// must be unique per node instance. if not, returns err
-func (bus *RPCBus) Register(m method, req chan<- Request) error {
+// SetDefaultTimeout sets the timeout of the calls which do not specify a
+// timeout or a context deadline of their own, for the methods without a
+// timeout set with SetTimeout.
+func (bus *RPCBus) SetDefaultTimeout(timeOut time.Duration) {
 	bus.mu.Lock()
 	defer bus.mu.Unlock()
+	bus.defaultTimeout = timeOut
+}
+
+// SetTimeout sets the timeout of a method, overriding the default timeout.
+// It applies to calls which do not specify a timeout or a context deadline
+// of their own. A zero timeOut lets them wait for the response, for methods
+// which can outlast the default timeout.
+func (bus *RPCBus) SetTimeout(m method, timeOut time.Duration) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.timeouts[m] = timeOut
+}
+
+// Register registers a method and binds it to a handler. methodName must be
+// unique per node instance. if not, returns err
+func (bus *RPCBus) Register(m method, handler HandlerFunc) error {
+	if handler == nil {
+		return ErrInvalidHandler
+	}
+
+	return bus.register(m, &entry{handler: handler})
+}
+
+// RegisterChan registers a method and binds it to a handler channel.
+// methodName must be unique per node instance. if not, returns err
+func (bus *RPCBus) RegisterChan(m method, req chan<- Request) error {
 	if req == nil {
 		return ErrInvalidRequestChan
 	}
 
+	return bus.register(m, &entry{reqChan: req})
+}
+
+func (bus *RPCBus) register(m method, e *entry) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
 	if _, ok := bus.registry[m]; ok {
 		return ErrMethodExists
 	}
 
-	bus.registry[m] = req
+	bus.registry[m] = e
+	return nil
+}
+
+// SetConcurrency caps the amount of requests a method handler serves at the
+// same time. Further calls wait for a slot to free up, within their timeout.
+// It has no effect on methods bound to a channel, as their consumer serves
+// one request at a time already.
+func (bus *RPCBus) SetConcurrency(m method, limit int) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	e, ok := bus.registry[m]
+	if !ok {
+		return ErrMethodNotExists
+	}
+
+	e.sem = nil
+	if limit > 0 {
+		e.sem = make(chan struct{}, limit)
+	}
+
 	return nil
 }
 
 // Call runs a long-polling technique to request from the method Consumer to
 // run the corresponding procedure and return a result or timeout
 func (bus *RPCBus) Call(m method, req Request, timeOut time.Duration) (bytes.Buffer, error) {
-	reqChan, err := bus.getReqChan(m)
-	if err != nil {
-		return bytes.Buffer{}, err
+	ctx := req.Context()
+	if timeOut > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeOut)
+		defer cancel()
 	}
 
-	if timeOut > 0 {
-		return bus.callTimeout(reqChan, req, timeOut)
+	resp, err := bus.CallContext(ctx, m, req)
+	// Preserve the plain errors callers of Call have always received
+	if cErr, ok := err.(*CallError); ok {
+		return resp, cErr.Err
 	}
 
-	return bus.callNoTimeout(reqChan, req)
+	return resp, err
 }
 
-func (bus *RPCBus) callTimeout(reqChan chan<- Request, req Request, timeOut time.Duration) (bytes.Buffer, error) {
-	select {
-	case reqChan <- req:
-	case <-time.After(timeOut):
-		return bytes.Buffer{}, ErrRequestTimeout
+// CallContext sends a request to the method Consumer and waits for the
+// response, until ctx is done. The context is attached to the request, so
+// that the handler can give up as well. If ctx carries no deadline, the
+// timeout of the method applies, if any. Failures of the bus are
+// reported as a *CallError, while errors returned by the handler are passed
+// through untouched.
+func (bus *RPCBus) CallContext(ctx context.Context, m method, req Request) (resp bytes.Buffer, err error) {
+	// Calls made on behalf of a traced message are part of its trace
+	span := tracing.StartSpan(tracing.FromContext(ctx), "rpcbus.call")
+	span.SetAttribute("method", m.String())
+	defer span.Finish()
+
+	e, sem, timeOut, err := bus.getEntry(m)
+	if err != nil {
+		return bytes.Buffer{}, &CallError{m, err}
+	}
+
+	start := time.Now()
+	defer func() {
+		e.metrics.record(time.Since(start), err)
+	}()
+
+	if _, ok := ctx.Deadline(); !ok && timeOut > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeOut)
+		defer cancel()
+	}
+
+	req.Ctx = ctx
+	if req.RespChan == nil {
+		req.RespChan = make(chan Response, 1)
+	}
+
+	if e.handler != nil {
+		if err := e.serve(req, sem); err != nil {
+			return bytes.Buffer{}, &CallError{m, err}
+		}
+	} else {
+		select {
+		case e.reqChan <- req:
+		case <-ctx.Done():
+			return bytes.Buffer{}, &CallError{m, ctxErr(ctx)}
+		}
 	}
 
-	var resp Response
 	select {
-	case resp = <-req.RespChan:
-	case <-time.After(timeOut):
-		return bytes.Buffer{}, ErrRequestTimeout
+	case resp := <-req.RespChan:
+		return resp.Resp, resp.Err
+	case <-ctx.Done():
+		return bytes.Buffer{}, &CallError{m, ctxErr(ctx)}
 	}
+}
+
+// serve runs the handler in its own goroutine, so that the caller can give up
+// waiting for it. The response is sent on the RespChan of the request. A slot
+// of sem, if any, is held until the handler returns.
+func (e *entry) serve(req Request, sem chan struct{}) error {
+	if sem != nil {
+		select {
+		case sem <- struct{}{}:
+		case <-req.Ctx.Done():
+			return ctxErr(req.Ctx)
+		}
+	}
+
+	go func() {
+		resp, err := e.handler(req)
+		if sem != nil {
+			<-sem
+		}
 
-	return resp.Resp, resp.Err
+		req.RespChan <- Response{resp, err}
+	}()
+
+	return nil
 }
 
-func (bus *RPCBus) callNoTimeout(reqChan chan<- Request, req Request) (bytes.Buffer, error) {
-	reqChan <- req
-	resp := <-req.RespChan
-	return resp.Resp, resp.Err
+func ctxErr(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return ErrRequestTimeout
+	}
+
+	return ErrRequestCanceled
 }
 
-func (bus *RPCBus) getReqChan(m method) (chan<- Request, error) {
+func (bus *RPCBus) getEntry(m method) (*entry, chan struct{}, time.Duration, error) {
 	bus.mu.RLock()
 	defer bus.mu.RUnlock()
-	if reqChan, ok := bus.registry[m]; ok {
-		return reqChan, nil
+	if e, ok := bus.registry[m]; ok {
+		if timeOut, ok := bus.timeouts[m]; ok {
+			return e, e.sem, timeOut, nil
+		}
+		return e, e.sem, bus.defaultTimeout, nil
 	}
 
-	return nil, ErrMethodNotExists
+	return nil, nil, 0, ErrMethodNotExists
+}
+
+// Deregister removes a method from the registry, so that calls to it fail
+// immediately instead of waiting on a handler which is gone.
+func (bus *RPCBus) Deregister(m method) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	delete(bus.registry, m)
 }
 
 // Close all open channels
 func (bus *RPCBus) Close() {
 	bus.mu.Lock()
 	defer bus.mu.Unlock()
-	for _, m := range bus.registry {
-		close(m)
+	for _, e := range bus.registry {
+		if e.reqChan != nil {
+			close(e.reqChan)
+		}
 	}
 
 	bus.registry = nil
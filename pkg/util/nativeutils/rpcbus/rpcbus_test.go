@@ -2,6 +2,7 @@ package rpcbus
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -77,7 +78,7 @@ func TestMethodExists(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	reqChan2 := make(chan Request)
-	err := bus.Register(m, reqChan2)
+	err := bus.RegisterChan(m, reqChan2)
 
 	if err != ErrMethodExists {
 		t.Fatalf("expecting methodExists error but get %v", err)
@@ -108,7 +109,7 @@ func TestNonExistingMethod(t *testing.T) {
 func TestInvalidReqChan(t *testing.T) {
 	bus := New()
 
-	err := bus.Register(m, nil)
+	err := bus.RegisterChan(m, nil)
 	if err != ErrInvalidRequestChan {
 		t.Error("expecting ErrInvalidReqChan error")
 	}
@@ -116,7 +117,7 @@ func TestInvalidReqChan(t *testing.T) {
 
 func setupConsumer(rpcBus *RPCBus, respond bool) {
 	reqChan := make(chan Request, 1)
-	rpcBus.Register(m, reqChan)
+	rpcBus.RegisterChan(m, reqChan)
 
 	if respond {
 		r := <-reqChan
@@ -128,3 +129,164 @@ func setupConsumer(rpcBus *RPCBus, respond bool) {
 		r.RespChan <- Response{*bytes.NewBufferString("output params"), nil}
 	}
 }
+
+func TestCallContextCanceled(t *testing.T) {
+	bus := New()
+	reqChan := make(chan Request, 1)
+	if err := bus.RegisterChan(m, reqChan); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// The handler never responds, but sees the cancellation
+		r := <-reqChan
+		cancel()
+		<-r.Context().Done()
+	}()
+
+	_, err := bus.CallContext(ctx, m, NewRequest(bytes.Buffer{}))
+	if !errors.Is(err, ErrRequestCanceled) {
+		t.Fatalf("expecting canceled error but get %v", err)
+	}
+
+	if _, ok := err.(*CallError); !ok {
+		t.Fatalf("expecting a CallError but get %T", err)
+	}
+}
+
+func TestMethodTimeout(t *testing.T) {
+	bus := New()
+	go setupConsumer(bus, false)
+	time.Sleep(100 * time.Millisecond)
+
+	bus.SetTimeout(m, 100*time.Millisecond)
+	_, err := bus.CallContext(context.Background(), m, NewRequest(*bytes.NewBufferString("input params")))
+	if !errors.Is(err, ErrRequestTimeout) {
+		t.Fatalf("expecting timeout error but get %v", err)
+	}
+
+	// Calls without a timeout are bound by the method timeout as well
+	if _, err := bus.Call(m, NewRequest(*bytes.NewBufferString("input params")), 0); err != ErrRequestTimeout {
+		t.Fatalf("expecting timeout error but get %v", err)
+	}
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	bus := New()
+	go setupConsumer(bus, false)
+	time.Sleep(100 * time.Millisecond)
+
+	// The default timeout applies to the methods without a timeout
+	bus.SetDefaultTimeout(100 * time.Millisecond)
+	if _, err := bus.Call(m, NewRequest(*bytes.NewBufferString("input params")), 0); err != ErrRequestTimeout {
+		t.Fatalf("expecting timeout error but get %v", err)
+	}
+
+	// A zero method timeout overrides it
+	bus.SetTimeout(m, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(300*time.Millisecond, cancel)
+	if _, err := bus.CallContext(ctx, m, NewRequest(*bytes.NewBufferString("input params"))); !errors.Is(err, ErrRequestCanceled) {
+		t.Fatalf("expecting canceled error but get %v", err)
+	}
+}
+
+func TestDeregister(t *testing.T) {
+	bus := New()
+	go setupConsumer(bus, false)
+	time.Sleep(100 * time.Millisecond)
+
+	bus.Deregister(m)
+	if _, err := bus.Call(m, NewRequest(bytes.Buffer{}), 0); err != ErrMethodNotExists {
+		t.Fatalf("expecting methodNotExists error but get %v", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	bus := New()
+	if err := bus.Register(m, func(r Request) (bytes.Buffer, error) {
+		if r.Params.Len() == 0 {
+			return bytes.Buffer{}, errInvalidParams
+		}
+
+		return *bytes.NewBufferString("output params"), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := bus.Call(m, NewRequest(*bytes.NewBufferString("input params")), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.String() != "output params" {
+		t.Errorf("expecting to retrieve response data")
+	}
+
+	if _, err := bus.Call(m, NewRequest(bytes.Buffer{}), time.Second); err != errInvalidParams {
+		t.Errorf("expecting a specific error here but get %v", err)
+	}
+
+	noop := func(Request) (bytes.Buffer, error) { return bytes.Buffer{}, nil }
+	if err := bus.Register(m, noop); err != ErrMethodExists {
+		t.Errorf("expecting methodExists error but get %v", err)
+	}
+
+	if err := bus.Register(GetMempoolTxs, nil); err != ErrInvalidHandler {
+		t.Errorf("expecting invalidHandler error but get %v", err)
+	}
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	bus := New()
+	release := make(chan struct{})
+	if err := bus.Register(m, func(r Request) (bytes.Buffer, error) {
+		<-release
+		return bytes.Buffer{}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.SetConcurrency(m, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first call holds the only slot
+	done := make(chan error, 1)
+	go func() {
+		_, err := bus.Call(m, NewRequest(bytes.Buffer{}), 5*time.Second)
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// The second can not be served in time
+	if _, err := bus.Call(m, NewRequest(bytes.Buffer{}), 100*time.Millisecond); err != ErrRequestTimeout {
+		t.Fatalf("expecting timeout error but get %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	bus := New()
+	go setupConsumer(bus, false)
+	time.Sleep(100 * time.Millisecond)
+
+	_, _ = bus.Call(m, NewRequest(bytes.Buffer{}), 10*time.Millisecond)
+	metrics := bus.Metrics()[m]
+	if metrics.Calls != 1 || metrics.Failures != 1 || metrics.Timeouts != 1 {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+
+	if metrics.Latency < 10*time.Millisecond {
+		t.Fatalf("unexpected latency %v", metrics.Latency)
+	}
+
+	if metrics.AverageLatency() != metrics.Latency {
+		t.Fatalf("unexpected average latency %v", metrics.AverageLatency())
+	}
+}
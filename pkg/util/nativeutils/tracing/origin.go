@@ -0,0 +1,38 @@
+package tracing
+
+import "sync"
+
+// maxOrigins bounds the amount of message origins remembered
+const maxOrigins = 4096
+
+// origins maps the IDs of the last maxOrigins messages received from the
+// network to the peer which sent them
+var origins = struct {
+	sync.RWMutex
+	ring  [maxOrigins]ID
+	next  int
+	peers map[ID]string
+}{peers: make(map[ID]string)}
+
+// SetOrigin records the address of the peer a traced message was received
+// from. Only the origins of the most recent messages are kept.
+func SetOrigin(id ID, origin string) {
+	if id == None {
+		return
+	}
+
+	origins.Lock()
+	defer origins.Unlock()
+	delete(origins.peers, origins.ring[origins.next])
+	origins.ring[origins.next] = id
+	origins.next = (origins.next + 1) % maxOrigins
+	origins.peers[id] = origin
+}
+
+// Origin returns the address of the peer the message identified by id was
+// received from, or an empty string if it is unknown
+func Origin(id ID) string {
+	origins.RLock()
+	defer origins.RUnlock()
+	return origins.peers[id]
+}
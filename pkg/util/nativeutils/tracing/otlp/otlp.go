@@ -0,0 +1,236 @@
+// Package otlp exports the spans of the tracing package to an OpenTelemetry
+// collector, over OTLP/HTTP with the JSON encoding. The spans of a message
+// share a trace, identified by the correlation ID of the message.
+package otlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/metrics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "otlp")
+
+var spansDropped = metrics.NewCounter("dusk_tracing_spans_dropped_total", "Spans dropped as the exporter could not keep up")
+
+const (
+	// tracesPath is where OTLP/HTTP collectors receive the spans
+	tracesPath = "/v1/traces"
+	// sendTimeout bounds the time spent exporting a batch of spans
+	sendTimeout = 5 * time.Second
+	// maxBatch is the amount of spans buffered between two exports. Spans
+	// exported beyond it are dropped, rather than slowing the node down.
+	maxBatch = 2048
+	// spanKindInternal is the kind of all the spans, as they are operations
+	// of the node rather than remote calls
+	spanKindInternal = 1
+)
+
+// Exporter implements tracing.SpanExporter. Spans are buffered, and sent to
+// the collector in batches every interval.
+type Exporter struct {
+	url      string
+	resource resource
+	interval time.Duration
+	client   *http.Client
+	quit     chan struct{}
+	done     chan struct{}
+
+	lock  sync.Mutex
+	spans []tracing.Span
+}
+
+// New creates an Exporter sending the spans to the collector at endpoint,
+// under the service name given. The spans are sent to /v1/traces, unless
+// endpoint has a path already. node identifies the node among the instances
+// of the service.
+func New(endpoint, service, node string, interval time.Duration) (*Exporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.New("unsupported collector scheme " + u.Scheme)
+	}
+
+	if u.Path == "" || u.Path == "/" {
+		u.Path = tracesPath
+	}
+
+	return &Exporter{
+		url: u.String(),
+		resource: resource{Attributes: []attribute{
+			stringAttribute("service.name", service),
+			stringAttribute("service.instance.id", node),
+		}},
+		interval: interval,
+		client:   &http.Client{Timeout: sendTimeout},
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Export buffers a span until the next export.
+// Implements tracing.SpanExporter.
+func (e *Exporter) Export(s tracing.Span) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if len(e.spans) >= maxBatch {
+		spansDropped.Inc()
+		return
+	}
+
+	e.spans = append(e.spans, s)
+}
+
+// Listen sends the spans buffered every interval, until Stop is called
+func (e *Exporter) Listen() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.flush()
+		case <-e.quit:
+			e.flush()
+			return
+		}
+	}
+}
+
+// Stop sends the spans left, and stops the exports. Listen must be running.
+func (e *Exporter) Stop() {
+	close(e.quit)
+	<-e.done
+}
+
+func (e *Exporter) flush() {
+	e.lock.Lock()
+	spans := e.spans
+	e.spans = nil
+	e.lock.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	if err := e.send(spans); err != nil {
+		lg.WithError(err).WithField("spans", len(spans)).Warnln("could not export the spans")
+	}
+}
+
+func (e *Exporter) send(spans []tracing.Span) error {
+	payload, err := json.Marshal(e.encode(spans))
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return errors.New("collector replied " + resp.Status)
+	}
+	return nil
+}
+
+// encode the spans as an ExportTraceServiceRequest
+func (e *Exporter) encode(spans []tracing.Span) request {
+	encoded := make([]span, 0, len(spans))
+	for _, s := range spans {
+		encoded = append(encoded, encodeSpan(s))
+	}
+
+	return request{ResourceSpans: []resourceSpans{{
+		Resource: e.resource,
+		ScopeSpans: []scopeSpans{{
+			Scope: scope{Name: "github.com/dusk-network/dusk-blockchain"},
+			Spans: encoded,
+		}},
+	}}}
+}
+
+func encodeSpan(s tracing.Span) span {
+	encoded := span{
+		// Trace IDs are 16 bytes long, while correlation IDs are 8
+		TraceID:           fmt.Sprintf("%032x", uint64(s.ID)),
+		SpanID:            fmt.Sprintf("%016x", s.SpanID),
+		Name:              s.Name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+		Attributes:        []attribute{stringAttribute("dusk.trace", s.ID.String())},
+	}
+
+	if s.ParentID != 0 {
+		encoded.ParentSpanID = fmt.Sprintf("%016x", s.ParentID)
+	}
+
+	for key, value := range s.Attributes {
+		encoded.Attributes = append(encoded.Attributes, stringAttribute(key, value))
+	}
+	return encoded
+}
+
+// The types below mirror the JSON encoding of the OTLP trace protocol
+
+type request struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []attribute `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []attribute `json:"attributes"`
+}
+
+type attribute struct {
+	Key   string `json:"key"`
+	Value value  `json:"value"`
+}
+
+type value struct {
+	StringValue string `json:"stringValue"`
+}
+
+func stringAttribute(key, v string) attribute {
+	return attribute{key, value{v}}
+}
@@ -0,0 +1,68 @@
+package otlp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExport checks that the spans of a message are sent to the collector in
+// the same trace, on Stop at the latest
+func TestExport(t *testing.T) {
+	requests := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, tracesPath, r.URL.Path)
+		var req request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests <- req
+	}))
+	defer srv.Close()
+
+	e, err := New(srv.URL, "dusk", "node", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracing.SetExporter(e)
+	defer tracing.SetExporter(nil)
+	go e.Listen()
+
+	id := tracing.ID(0xabc)
+	parent := tracing.StartSpan(id, "block.receive")
+	child := parent.StartChild("block.verify")
+	child.SetAttribute("height", "12")
+	child.Finish()
+	parent.Finish()
+	e.Stop()
+
+	req := <-requests
+	assert.Equal(t, 1, len(req.ResourceSpans))
+	assert.Equal(t, stringAttribute("service.name", "dusk"), req.ResourceSpans[0].Resource.Attributes[0])
+
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	assert.Equal(t, 2, len(spans))
+	assert.Equal(t, "block.verify", spans[0].Name)
+	assert.Equal(t, "00000000000000000000000000000abc", spans[0].TraceID)
+	assert.Equal(t, spans[0].TraceID, spans[1].TraceID)
+	assert.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+	assert.Equal(t, "", spans[1].ParentSpanID)
+	assert.Contains(t, spans[0].Attributes, stringAttribute("height", "12"))
+}
+
+func TestEndpoint(t *testing.T) {
+	e, err := New("http://localhost:4318", "dusk", "node", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:4318/v1/traces", e.url)
+
+	// Explicit paths are kept
+	e, err = New("https://collector/otlp/v1/traces", "dusk", "node", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://collector/otlp/v1/traces", e.url)
+
+	_, err = New("udp://localhost:4318", "dusk", "node", time.Second)
+	assert.Error(t, err)
+}
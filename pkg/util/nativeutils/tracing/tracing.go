@@ -0,0 +1,150 @@
+// Package tracing provides correlation IDs, which allow to follow a message
+// across the components of the node. An ID is assigned when a message enters
+// the node, and is carried along through the EventBus and the RPCBus.
+//
+// Spans are handed over to a SpanExporter, if one is set. This keeps the
+// package free of dependencies, while allowing to plug in an OpenTelemetry
+// exporter by implementing the interface, as the otlp package does.
+package tracing
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ID is a correlation ID. The zero value means that a message is not traced.
+type ID uint64
+
+// None is the ID of untraced messages
+const None ID = 0
+
+// NewID returns a random, non-zero ID
+func NewID() ID {
+	for {
+		if id := ID(rand.Uint64()); id != None {
+			return id
+		}
+	}
+}
+
+// String returns the hex representation of the ID
+func (id ID) String() string {
+	return strconv.FormatUint(uint64(id), 16)
+}
+
+type ctxKey struct{}
+
+// WithID returns a copy of ctx carrying the ID
+func WithID(ctx context.Context, id ID) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the ID carried by ctx, or None
+func FromContext(ctx context.Context) ID {
+	if id, ok := ctx.Value(ctxKey{}).(ID); ok {
+		return id
+	}
+
+	return None
+}
+
+// Span represents an operation performed on behalf of a traced message. The
+// spans of a message share its ID, and are told apart by their SpanID.
+type Span struct {
+	ID ID
+	// SpanID identifies the span among the ones of the message
+	SpanID uint64
+	// ParentID is the SpanID of the enclosing span, or zero
+	ParentID   uint64
+	Name       string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]string
+}
+
+// SpanExporter receives the completed spans
+type SpanExporter interface {
+	Export(Span)
+}
+
+var (
+	lock     sync.RWMutex
+	exporter SpanExporter
+)
+
+// SetExporter sets the SpanExporter spans are sent to. Passing nil disables
+// the export of spans.
+func SetExporter(e SpanExporter) {
+	lock.Lock()
+	defer lock.Unlock()
+	exporter = e
+}
+
+// StartSpan starts a span for the message identified by id. It returns nil
+// if id is None or no exporter is set, and calling Finish on a nil span is
+// harmless, so that callers do not need to check.
+func StartSpan(id ID, name string) *Span {
+	if id == None {
+		return nil
+	}
+
+	lock.RLock()
+	defer lock.RUnlock()
+	if exporter == nil {
+		return nil
+	}
+
+	return &Span{
+		ID:         id,
+		SpanID:     newSpanID(),
+		Name:       name,
+		Start:      time.Now(),
+		Attributes: make(map[string]string),
+	}
+}
+
+// StartChild starts a span enclosed in s, such as a stage of the operation s
+// represents. It returns nil if s is nil.
+func (s *Span) StartChild(name string) *Span {
+	if s == nil {
+		return nil
+	}
+
+	child := StartSpan(s.ID, name)
+	if child != nil {
+		child.ParentID = s.SpanID
+	}
+	return child
+}
+
+// SetAttribute annotates the span
+func (s *Span) SetAttribute(key, value string) {
+	if s != nil {
+		s.Attributes[key] = value
+	}
+}
+
+// Finish the span, and export it
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+
+	s.End = time.Now()
+	lock.RLock()
+	defer lock.RUnlock()
+	if exporter != nil {
+		exporter.Export(*s)
+	}
+}
+
+func newSpanID() uint64 {
+	for {
+		if id := rand.Uint64(); id != 0 {
+			return id
+		}
+	}
+}
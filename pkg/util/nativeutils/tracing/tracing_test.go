@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockExporter struct {
+	spans []Span
+}
+
+func (m *mockExporter) Export(s Span) {
+	m.spans = append(m.spans, s)
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, None, FromContext(context.Background()))
+
+	id := NewID()
+	assert.Equal(t, id, FromContext(WithID(context.Background(), id)))
+}
+
+func TestSpans(t *testing.T) {
+	e := &mockExporter{}
+	SetExporter(e)
+	defer SetExporter(nil)
+
+	// Untraced messages produce no span
+	StartSpan(None, "ignored").Finish()
+
+	id := NewID()
+	s := StartSpan(id, "test")
+	s.SetAttribute("topic", "tx")
+	s.Finish()
+
+	assert.Equal(t, 1, len(e.spans))
+	assert.Equal(t, id, e.spans[0].ID)
+	assert.Equal(t, "tx", e.spans[0].Attributes["topic"])
+	assert.False(t, e.spans[0].End.Before(e.spans[0].Start))
+}
+
+func TestChildSpans(t *testing.T) {
+	e := &mockExporter{}
+	SetExporter(e)
+	defer SetExporter(nil)
+
+	// Children of untraced messages are not traced either
+	var untraced *Span
+	untraced.StartChild("ignored").Finish()
+
+	parent := StartSpan(NewID(), "parent")
+	parent.StartChild("child").Finish()
+	parent.Finish()
+
+	assert.Equal(t, 2, len(e.spans))
+	child := e.spans[0]
+	assert.Equal(t, "child", child.Name)
+	assert.Equal(t, parent.ID, child.ID)
+	assert.Equal(t, parent.SpanID, child.ParentID)
+	assert.NotEqual(t, parent.SpanID, child.SpanID)
+}
+
+func TestOrigin(t *testing.T) {
+	id := NewID()
+	assert.Equal(t, "", Origin(id))
+
+	SetOrigin(id, "127.0.0.1:7000")
+	assert.Equal(t, "127.0.0.1:7000", Origin(id))
+
+	// Only the most recent origins are kept
+	for i := 0; i < maxOrigins; i++ {
+		SetOrigin(NewID(), "127.0.0.1:7001")
+	}
+	assert.Equal(t, "", Origin(id))
+}
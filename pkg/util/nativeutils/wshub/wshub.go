@@ -0,0 +1,104 @@
+// Package wshub keeps track of the websocket clients of the services of the
+// node, and restricts the origins browsers can connect from.
+package wshub
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+// DefaultMaxClients is used when the maximum amount of clients is not set
+const DefaultMaxClients = 100
+
+// ErrTooManySubscribers is returned when the client cap is reached
+var ErrTooManySubscribers = errors.New("too many subscribers")
+
+// Client is a websocket connection fed by a Hub
+type Client interface {
+	// Notify queues an event for the client. It must never block, so that a
+	// slow client can not hold back the others.
+	Notify(event interface{})
+}
+
+// Hub keeps track of the websocket clients of a service, up to a maximum, and
+// broadcasts the events of the service to them.
+type Hub struct {
+	lock    sync.Mutex
+	clients map[Client]struct{}
+	max     int
+}
+
+// New creates a Hub accepting up to max clients. DefaultMaxClients applies if
+// max is not positive.
+func New(max int) *Hub {
+	if max <= 0 {
+		max = DefaultMaxClients
+	}
+
+	return &Hub{
+		clients: make(map[Client]struct{}),
+		max:     max,
+	}
+}
+
+// Add registers c, unless the hub is full
+func (h *Hub) Add(c Client) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if len(h.clients) >= h.max {
+		return ErrTooManySubscribers
+	}
+
+	h.clients[c] = struct{}{}
+	return nil
+}
+
+// Remove unregisters c
+func (h *Hub) Remove(c Client) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.clients, c)
+}
+
+// Broadcast notifies event to every client
+func (h *Hub) Broadcast(event interface{}) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for c := range h.clients {
+		c.Notify(event)
+	}
+}
+
+// Handler returns the handler for a websocket endpoint served by handler.
+// Browsers, which send the origin of the page opening the connection, are
+// only accepted from the allowedOrigins, or from any origin if "*" is
+// allowed. Clients sending no origin, such as non-browser ones, are always
+// accepted.
+func Handler(handler websocket.Handler, allowedOrigins []string) http.Handler {
+	return websocket.Server{
+		Handler: handler,
+		Handshake: func(_ *websocket.Config, r *http.Request) error {
+			return CheckOrigin(r.Header.Get("Origin"), allowedOrigins)
+		},
+	}
+}
+
+// CheckOrigin returns an error if origin is set and is not allowed
+func CheckOrigin(origin string, allowedOrigins []string) error {
+	if origin == "" {
+		return nil
+	}
+
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("origin %s not allowed", origin)
+}
@@ -0,0 +1,42 @@
+package wshub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockClient struct {
+	events []interface{}
+}
+
+func (m *mockClient) Notify(event interface{}) {
+	m.events = append(m.events, event)
+}
+
+func TestHubCap(t *testing.T) {
+	h := New(1)
+	c := &mockClient{}
+	assert.NoError(t, h.Add(c))
+	assert.Equal(t, ErrTooManySubscribers, h.Add(&mockClient{}))
+
+	h.Broadcast("event")
+	assert.Equal(t, []interface{}{"event"}, c.events)
+
+	// The slot is freed once the client is removed
+	h.Remove(c)
+	assert.NoError(t, h.Add(&mockClient{}))
+	h.Broadcast("event")
+	assert.Len(t, c.events, 1)
+}
+
+func TestCheckOrigin(t *testing.T) {
+	allowed := []string{"https://explorer.dusk.network/"}
+
+	// Non-browser clients send no origin
+	assert.NoError(t, CheckOrigin("", nil))
+	assert.NoError(t, CheckOrigin("https://explorer.dusk.network", allowed))
+	assert.Error(t, CheckOrigin("https://evil.example", allowed))
+	assert.Error(t, CheckOrigin("https://explorer.dusk.network", nil))
+	assert.NoError(t, CheckOrigin("https://evil.example", []string{"*"}))
+}